@@ -1,10 +1,15 @@
 package sqlparser
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/ident"
+	"github.com/oarkflow/sqlparser/lexer"
 )
 
 type FindingSeverity string
@@ -15,23 +20,47 @@ const (
 	SeverityCritical FindingSeverity = "critical"
 )
 
+// Rank orders severities from least to most severe: 0 for info, 1 for
+// warning and 2 for critical. Unknown severities rank as info.
+func (s FindingSeverity) Rank() int {
+	switch s {
+	case SeverityCritical:
+		return 2
+	case SeverityWarning:
+		return 1
+	}
+	return 0
+}
+
 type AnalysisFinding struct {
-	Severity       FindingSeverity
-	Code           string
-	Message        string
-	Problem        string
-	Recommendation string
-	StatementIndex int
+	Severity       FindingSeverity `json:"severity"`
+	Code           string          `json:"code"`
+	Message        string          `json:"message"`
+	Problem        string          `json:"problem"`
+	Recommendation string          `json:"recommendation,omitempty"`
+	StatementIndex int             `json:"statementIndex"`
+	// Pos is the byte offset in the analyzed SQL of the node the finding
+	// is about, or of the parse error, and Line and Col are its 1-based
+	// line and column.
+	Pos  int32  `json:"pos"`
+	Line uint32 `json:"line"`
+	Col  uint32 `json:"col"`
 }
 
 type AnalysisReport struct {
-	Valid          bool
-	StatementCount int
-	Findings       []AnalysisFinding
+	Valid          bool              `json:"valid"`
+	StatementCount int               `json:"statementCount"`
+	Findings       []AnalysisFinding `json:"findings"`
 }
 
 type AnalysisOptions struct {
 	Dialect Dialect
+	// Schema, when set, enables the rules that need column types, such
+	// as IMPLICIT_CONVERSION, and index-aware cost estimates.
+	Schema *Schema
+	// Statistics, when set, enables LARGE_SCAN and LARGE_RESULT, which
+	// estimate statement costs from table sizes; see EstimateCost.
+	Statistics *Statistics
 }
 
 type OptimizationReport struct {
@@ -41,6 +70,22 @@ type OptimizationReport struct {
 	Converted    bool
 	Analysis     AnalysisReport
 	Actions      []string
+	// Rewrites are the query rewrites OptimizedSQL includes, each with
+	// the statement before and after it.
+	Rewrites []OptimizationRewrite
+	Warnings []ConversionWarning
+}
+
+// OptimizationRewrite is one rewrite of a statement by
+// OptimizeSQLForDialect. Rule names the pass that made it, such as
+// "push-down-predicates", and Before and After are the statement rendered
+// for the target dialect without and with it.
+type OptimizationRewrite struct {
+	Rule           string
+	StatementIndex int
+	Action         string
+	Before         string
+	After          string
 }
 
 func AnalyzeSQL(sql string) AnalysisReport {
@@ -52,7 +97,13 @@ func AnalyzeSQLWithOptions(sql string, opts AnalysisOptions) AnalysisReport {
 	stmts, err := ParseStatements(sql)
 	if err != nil {
 		report.Valid = false
-		addFinding(&report, SeverityCritical, "PARSE_ERROR", err.Error(), "Fix SQL syntax at the reported line/column and re-run parsing.", -1)
+		var pe *ParseError
+		if errors.As(err, &pe) {
+			addFinding(&report, SeverityCritical, "PARSE_ERROR", err.Error(), "Fix SQL syntax at the reported line/column and re-run parsing.", -1, pe.Pos)
+		} else {
+			addFinding(&report, SeverityCritical, "PARSE_ERROR", err.Error(), "Fix SQL syntax at the reported line/column and re-run parsing.", -1, 0)
+		}
+		setFindingLines(report.Findings, sql)
 		return report
 	}
 	report.Valid = true
@@ -61,27 +112,92 @@ func AnalyzeSQLWithOptions(sql string, opts AnalysisOptions) AnalysisReport {
 	for i, stmt := range stmts {
 		analyzeStatement(stmt, i, &report, opts)
 	}
+	setFindingLines(report.Findings, sql)
 	return report
 }
 
+// OptimizationOptions configures OptimizeSQLWithOptions.
+type OptimizationOptions struct {
+	Dialect Dialect
+	// Schema, when set, is used by the analysis and by rewrites that need
+	// the tables' indexes.
+	Schema *Schema
+	// OrToUnion splits ORs on different indexed columns into UNION ALL
+	// branches with OrToUnion. It needs Schema.
+	OrToUnion bool
+}
+
+// OptimizeSQLForDialect optimizes sql for dialect with the default
+// rewrites; see OptimizeSQLWithOptions.
 func OptimizeSQLForDialect(sql string, dialect Dialect) (OptimizationReport, error) {
+	return OptimizeSQLWithOptions(sql, OptimizationOptions{Dialect: dialect})
+}
+
+// OptimizeSQLWithOptions analyzes sql for opts.Dialect, removes constant
+// conditions with EliminateDeadCode, pushes WHERE conditions down with
+// PushDownPredicates and renders the result for the dialect. For PostgreSQL and SQLite it also turns IN lists of more than
+// 1000 integers into VALUES tables with JoinInLists; for MySQL, whose
+// VALUES tables are recent, it recommends a temporary table instead.
+// Actions lists what changed and what the analysis recommends, and
+// Rewrites shows each rewritten statement before and after.
+func OptimizeSQLWithOptions(sql string, opts OptimizationOptions) (OptimizationReport, error) {
+	dialect := opts.Dialect
 	report := OptimizationReport{
 		Dialect:     dialect,
 		OriginalSQL: sql,
 	}
-	report.Analysis = AnalyzeSQLWithOptions(sql, AnalysisOptions{Dialect: dialect})
+	report.Analysis = AnalyzeSQLWithOptions(sql, AnalysisOptions{Dialect: dialect, Schema: opts.Schema})
 	if !report.Analysis.Valid {
 		return report, fmt.Errorf("cannot optimize invalid SQL: %s", report.Analysis.Findings[0].Problem)
 	}
-	converted, err := ConvertDialect(sql, dialect)
+	stmts, err := ParseStatements(sql)
+	if err != nil {
+		return report, err
+	}
+	stmts = detach(stmts)
+	convert := ConvertOptions{Target: dialect}
+	var guidance []string
+	for i, stmt := range stmts {
+		before, _, err := ConvertStatements([]Statement{stmt}, convert)
+		if err != nil {
+			return report, err
+		}
+		for _, rw := range optimizerRewrites(opts) {
+			n := rw.apply(stmt)
+			if n == 0 {
+				continue
+			}
+			after, _, err := ConvertStatements([]Statement{stmt}, convert)
+			if err != nil {
+				return report, err
+			}
+			report.Rewrites = append(report.Rewrites, OptimizationRewrite{
+				Rule:           rw.rule,
+				StatementIndex: i,
+				Action:         fmt.Sprintf(rw.action, n),
+				Before:         before,
+				After:          after,
+			})
+			before = after
+		}
+		if n := countIntLists(stmt, largeInList); n > 0 && dialect == DialectMySQL {
+			guidance = append(guidance, fmt.Sprintf("Statement %d has %d IN list(s) of more than %d integers: load the values into a temporary table with a primary key (CREATE TEMPORARY TABLE ids (v BIGINT PRIMARY KEY)) and join it, since MySQL falls back to a full scan when a list exceeds range_optimizer_max_mem_size and reads VALUES tables only from 8.0.19", i+1, n, largeInList))
+		}
+	}
+	converted, warnings, err := ConvertStatements(stmts, convert)
 	if err != nil {
 		return report, err
 	}
 	report.OptimizedSQL = converted
+	report.Warnings = warnings
 	report.Converted = strings.TrimSpace(sql) != strings.TrimSpace(converted)
 	if report.Converted {
 		report.Actions = append(report.Actions, fmt.Sprintf("Converted SQL to %s-compatible syntax", dialect))
 	}
+	for _, rw := range report.Rewrites {
+		report.Actions = append(report.Actions, rw.Action)
+	}
+	report.Actions = append(report.Actions, guidance...)
 	seen := map[string]bool{}
 	for _, f := range report.Analysis.Findings {
 		if f.Recommendation == "" || seen[f.Recommendation] {
@@ -93,41 +209,76 @@ func OptimizeSQLForDialect(sql string, dialect Dialect) (OptimizationReport, err
 	return report, nil
 }
 
+// largeInList is the IN list length past which analysis reports the list
+// and optimization rewrites it.
+const largeInList = 1000
+
+// optimizerRewrite is a rewrite OptimizeSQLForDialect applies. apply
+// rewrites a statement in place and returns how many changes it made, and
+// action describes them, with %d for their number.
+type optimizerRewrite struct {
+	rule   string
+	action string
+	apply  func(Statement) int
+}
+
+// optimizerRewrites returns the rewrites opts asks for or that pay off in
+// its dialect, in the order they apply.
+func optimizerRewrites(opts OptimizationOptions) []optimizerRewrite {
+	rewrites := []optimizerRewrite{{
+		rule:   "eliminate-dead-code",
+		action: "Simplified %d expression(s) with constant parts, such as WHERE 1 = 1 scaffolding and CASE branches that are never taken",
+		apply:  eliminateDeadCode,
+	}, {
+		rule:   "push-down-predicates",
+		action: "Pushed %d WHERE condition(s) into the derived tables or CTEs they filter, so rows are dropped before materialization",
+		apply:  pushDownPredicates,
+	}}
+	if opts.Dialect == DialectPostgres || opts.Dialect == DialectSQLite {
+		rewrites = append(rewrites, optimizerRewrite{
+			rule:   "join-in-lists",
+			action: fmt.Sprintf("Rewrote %%d IN list(s) of more than %d integers as VALUES tables the planner can hash", largeInList),
+			apply:  func(stmt Statement) int { return joinInLists(stmt, largeInList) },
+		})
+	}
+	if opts.OrToUnion {
+		rewrites = append(rewrites, optimizerRewrite{
+			rule:   "or-to-union",
+			action: "Split %d OR condition(s) on different indexed columns into UNION ALL branches that can each use an index",
+			apply:  func(stmt Statement) int { return orToUnion(stmt, opts.Schema) },
+		})
+	}
+	return rewrites
+}
+
 func analyzeStatement(stmt Statement, idx int, report *AnalysisReport, opts AnalysisOptions) {
+	if opts.Schema != nil {
+		analyzeConversions(stmt, idx, report, opts)
+		analyzeBooleanComparisons(stmt, idx, report, opts)
+	}
+	analyzeSargability(stmt, idx, report, opts)
+	analyzeDDLSafety(stmt, idx, report, opts)
+	analyzeRecursiveCTEs(stmt, idx, report, opts)
+	analyzeReservedWords(stmt, idx, report, opts)
+	analyzeTypeLoss(stmt, idx, report, opts)
+	if opts.Statistics != nil {
+		analyzeCost(stmt, idx, report, opts)
+	}
 	switch s := stmt.(type) {
 	case *ast.SelectStmt:
-		if hasSelectStar(s.Columns) {
-			addFinding(report, SeverityWarning, "SELECT_STAR", "Query uses SELECT *; this can read unnecessary columns and break clients if schema changes.", "Select explicit columns needed by the caller (e.g. SELECT id, name) to reduce IO and improve compatibility.", idx)
-		}
-		if s.SetOp != nil {
-			for cur := s.SetOp; cur != nil; cur = cur.Right.SetOp {
-				if cur.Op == ast.Union && !cur.All {
-					addFinding(report, SeverityInfo, "UNION_DISTINCT_COST", "UNION performs duplicate elimination, which can add sort/hash overhead on large datasets.", "Use UNION ALL when duplicate removal is not required.", idx)
-				}
-			}
-		}
-		for _, tr := range s.From {
-			if jt, ok := tr.(*ast.JoinTable); ok && jt.Kind == ast.CrossJoin {
-				addFinding(report, SeverityWarning, "CROSS_JOIN", "CROSS JOIN can create a cartesian product and explode row counts.", "Ensure join cardinality is intended, or use an INNER/LEFT JOIN with explicit join predicates.", idx)
-			}
-		}
-		analyzeExpr(s.Where, idx, report, opts)
-		analyzeExpr(s.Having, idx, report, opts)
-		for _, c := range s.Columns {
-			analyzeExpr(c.Expr, idx, report, opts)
-		}
+		analyzeSelect(s, idx, report, opts)
 	case *ast.InsertStmt:
 		if len(s.Values) > 1000 {
-			addFinding(report, SeverityInfo, "BULK_INSERT_SIZE", "Very large VALUES clause detected; this can increase lock time and memory pressure.", "Split into smaller batches (for example 200-1000 rows) and use transactions if needed.", idx)
+			addFinding(report, SeverityInfo, "BULK_INSERT_SIZE", "Very large VALUES clause detected; this can increase lock time and memory pressure.", "Split into smaller batches (for example 200-1000 rows) and use transactions if needed.", idx, stmt.Pos())
 		}
 		if len(s.OnDupKey) > 0 || len(s.OnConflictUpdate) > 0 || s.OnConflictDoNothing {
-			addFinding(report, SeverityInfo, "UPSERT_PRESENT", "Upsert logic detected (ON DUPLICATE KEY / ON CONFLICT).", "Verify matching unique/primary indexes exist on conflict columns to avoid full-table checks.", idx)
+			addFinding(report, SeverityInfo, "UPSERT_PRESENT", "Upsert logic detected (ON DUPLICATE KEY / ON CONFLICT).", "Verify matching unique/primary indexes exist on conflict columns to avoid full-table checks.", idx, stmt.Pos())
 		}
 		if opts.Dialect == DialectMySQL && (len(s.OnConflictUpdate) > 0 || s.OnConflictDoNothing) {
-			addFinding(report, SeverityWarning, "DIALECT_UPSERT_MISMATCH", "ON CONFLICT is not native MySQL syntax.", "Use ON DUPLICATE KEY UPDATE (or run dialect conversion targeting mysql).", idx)
+			addFinding(report, SeverityWarning, "DIALECT_UPSERT_MISMATCH", "ON CONFLICT is not native MySQL syntax.", "Use ON DUPLICATE KEY UPDATE (or run dialect conversion targeting mysql).", idx, stmt.Pos())
 		}
 		if opts.Dialect == DialectPostgres && len(s.OnDupKey) > 0 {
-			addFinding(report, SeverityWarning, "DIALECT_UPSERT_MISMATCH", "ON DUPLICATE KEY is not native PostgreSQL syntax.", "Use ON CONFLICT (...) DO UPDATE/DO NOTHING (or run dialect conversion targeting postgres).", idx)
+			addFinding(report, SeverityWarning, "DIALECT_UPSERT_MISMATCH", "ON DUPLICATE KEY is not native PostgreSQL syntax.", "Use ON CONFLICT (...) DO UPDATE/DO NOTHING (or run dialect conversion targeting postgres).", idx, stmt.Pos())
 		}
 		if s.Select != nil {
 			for _, c := range s.Select.Columns {
@@ -135,14 +286,14 @@ func analyzeStatement(stmt Statement, idx int, report *AnalysisReport, opts Anal
 			}
 		}
 		if s.Replace && opts.Dialect == DialectPostgres {
-			addFinding(report, SeverityWarning, "REPLACE_NOT_PORTABLE", "REPLACE is not supported by PostgreSQL.", "Rewrite as INSERT ... ON CONFLICT ... DO UPDATE.", idx)
+			addFinding(report, SeverityWarning, "REPLACE_NOT_PORTABLE", "REPLACE is not supported by PostgreSQL.", "Rewrite as INSERT ... ON CONFLICT ... DO UPDATE.", idx, stmt.Pos())
 		}
 	case *ast.UpdateStmt:
 		if s.Where == nil {
-			addFinding(report, SeverityCritical, "UPDATE_WITHOUT_WHERE", "UPDATE statement has no WHERE clause and will affect all rows.", "Add a WHERE predicate or confirm intentionally full-table update using explicit safeguards.", idx)
+			addFinding(report, SeverityCritical, "UPDATE_WITHOUT_WHERE", "UPDATE statement has no WHERE clause and will affect all rows.", "Add a WHERE predicate or confirm intentionally full-table update using explicit safeguards.", idx, stmt.Pos())
 		}
 		if s.Limit != nil && len(s.Order) == 0 {
-			addFinding(report, SeverityWarning, "UPDATE_LIMIT_NO_ORDER", "UPDATE uses LIMIT without ORDER BY, so chosen rows may be nondeterministic.", "Add ORDER BY on a stable key (for example primary key) before LIMIT.", idx)
+			addFinding(report, SeverityWarning, "UPDATE_LIMIT_NO_ORDER", "UPDATE uses LIMIT without ORDER BY, so chosen rows may be nondeterministic.", "Add ORDER BY on a stable key (for example primary key) before LIMIT.", idx, stmt.Pos())
 		}
 		analyzeExpr(s.Where, idx, report, opts)
 		for _, a := range s.Set {
@@ -150,39 +301,339 @@ func analyzeStatement(stmt Statement, idx int, report *AnalysisReport, opts Anal
 		}
 	case *ast.DeleteStmt:
 		if s.Where == nil {
-			addFinding(report, SeverityCritical, "DELETE_WITHOUT_WHERE", "DELETE statement has no WHERE clause and will remove all rows.", "Add a WHERE predicate or use TRUNCATE explicitly when full deletion is intended.", idx)
+			addFinding(report, SeverityCritical, "DELETE_WITHOUT_WHERE", "DELETE statement has no WHERE clause and will remove all rows.", "Add a WHERE predicate or use TRUNCATE explicitly when full deletion is intended.", idx, stmt.Pos())
 		}
 		if s.Limit != nil && len(s.Order) == 0 {
-			addFinding(report, SeverityWarning, "DELETE_LIMIT_NO_ORDER", "DELETE uses LIMIT without ORDER BY, so deleted rows may be nondeterministic.", "Add ORDER BY on a stable key before LIMIT.", idx)
+			addFinding(report, SeverityWarning, "DELETE_LIMIT_NO_ORDER", "DELETE uses LIMIT without ORDER BY, so deleted rows may be nondeterministic.", "Add ORDER BY on a stable key before LIMIT.", idx, stmt.Pos())
 		}
 		analyzeExpr(s.Where, idx, report, opts)
+	case *ast.TruncateStmt:
+		if opts.Dialect == DialectMySQL && len(s.Tables) > 1 {
+			addFinding(report, SeverityWarning, "TRUNCATE_MULTI_TABLE", "MySQL's TRUNCATE takes a single table.", "Issue one TRUNCATE TABLE per table (or run dialect conversion targeting mysql).", idx, stmt.Pos())
+		}
 	case *ast.CreateTableStmt:
+		if s.Temporary && hasForeignKey(s) {
+			addFinding(report, SeverityWarning, "TEMP_TABLE_FOREIGN_KEY", "Temporary table declares foreign keys; every write to it pays for referential checks it does not need (MySQL rejects them, and PostgreSQL only allows them to reference other temporary tables).", "Drop the FOREIGN KEY / REFERENCES clauses from the temporary table and validate the data when it is copied into permanent tables.", idx, stmt.Pos())
+		}
 		for _, c := range s.Columns {
 			if c.Type != nil && strings.EqualFold(string(c.Type.Name), "jsonb") {
 				switch opts.Dialect {
 				case DialectMySQL:
-					addFinding(report, SeverityInfo, "JSONB_DIALECT_NOTE", "Column uses JSONB but target is MySQL.", "Use JSON type and generated columns + functional indexes for JSON paths.", idx)
+					addFinding(report, SeverityInfo, "JSONB_DIALECT_NOTE", "Column uses JSONB but target is MySQL.", "Use JSON type and generated columns + functional indexes for JSON paths.", idx, stmt.Pos())
 				case DialectSQLite:
-					addFinding(report, SeverityInfo, "JSONB_DIALECT_NOTE", "Column uses JSONB but target is SQLite.", "Use TEXT storage with JSON1 functions and check constraints for shape validation.", idx)
+					addFinding(report, SeverityInfo, "JSONB_DIALECT_NOTE", "Column uses JSONB but target is SQLite.", "Use TEXT storage with JSON1 functions and check constraints for shape validation.", idx, stmt.Pos())
 				default:
-					addFinding(report, SeverityInfo, "JSONB_DIALECT_NOTE", "Column uses JSONB. Dialect conversion keeps JSONB for Postgres, rewrites to JSON in MySQL, and TEXT in SQLite.", "If converting across dialects, verify JSON operator compatibility and add dialect-specific indexes (for example GIN in Postgres, generated-column indexes in MySQL).", idx)
+					addFinding(report, SeverityInfo, "JSONB_DIALECT_NOTE", "Column uses JSONB. Dialect conversion keeps JSONB for Postgres, rewrites to JSON in MySQL, and TEXT in SQLite.", "If converting across dialects, verify JSON operator compatibility and add dialect-specific indexes (for example GIN in Postgres, generated-column indexes in MySQL).", idx, stmt.Pos())
 				}
 			}
 			if c.AutoIncrement && opts.Dialect == DialectPostgres {
-				addFinding(report, SeverityInfo, "AUTO_INCREMENT_REWRITE", "AUTO_INCREMENT detected with PostgreSQL target.", "Use GENERATED AS IDENTITY (dialect converter can rewrite this).", idx)
+				addFinding(report, SeverityInfo, "AUTO_INCREMENT_REWRITE", "AUTO_INCREMENT detected with PostgreSQL target.", "Use GENERATED AS IDENTITY (dialect converter can rewrite this).", idx, c.Name.Pos())
 			}
 		}
 	case *ast.GenericDDLStmt:
-		addFinding(report, SeverityWarning, "GENERIC_DDL", "Statement was parsed with generic DDL fallback, so internals may not be fully analyzed.", "For best validation, rewrite this statement to a currently modeled form or extend parser support for this DDL type.", idx)
+		addFinding(report, SeverityWarning, "GENERIC_DDL", "Statement was parsed with generic DDL fallback, so internals may not be fully analyzed.", "For best validation, rewrite this statement to a currently modeled form or extend parser support for this DDL type.", idx, stmt.Pos())
 	case *ast.UseStmt:
 		if opts.Dialect == DialectPostgres || opts.Dialect == DialectSQLite {
-			addFinding(report, SeverityWarning, "USE_NOT_SUPPORTED", "USE statement is not portable to this dialect.", "For PostgreSQL use explicit database connection; for SQLite use file/database handle selection in the client.", idx)
+			addFinding(report, SeverityWarning, "USE_NOT_SUPPORTED", "USE statement is not portable to this dialect.", "For PostgreSQL use explicit database connection; for SQLite use file/database handle selection in the client.", idx, stmt.Pos())
 		}
 	case *ast.AlterDatabaseStmt:
 		if opts.Dialect == DialectSQLite {
-			addFinding(report, SeverityWarning, "ALTER_DATABASE_NOT_SUPPORTED", "ALTER DATABASE is not supported in SQLite.", "Move database-level options to application/connection settings.", idx)
+			addFinding(report, SeverityWarning, "ALTER_DATABASE_NOT_SUPPORTED", "ALTER DATABASE is not supported in SQLite.", "Move database-level options to application/connection settings.", idx, stmt.Pos())
+		}
+	}
+}
+
+// analyzeSelect checks each SELECT block of s and its set operations.
+func analyzeSelect(s *ast.SelectStmt, idx int, report *AnalysisReport, opts AnalysisOptions) {
+	if op := s.SetOp; op != nil {
+		analyzeSelect(op.Left, idx, report, opts)
+		if op.Op == ast.Union && !op.All {
+			addFinding(report, SeverityInfo, "UNION_DISTINCT_COST", "UNION performs duplicate elimination, which can add sort/hash overhead on large datasets.", "Use UNION ALL when duplicate removal is not required.", idx, op.Right.TokPos)
+		}
+		analyzeSelect(op.Right, idx, report, opts)
+		return
+	}
+	if pos, ok := selectStar(s); ok {
+		addFinding(report, SeverityWarning, "SELECT_STAR", "Query uses SELECT *; this can read unnecessary columns and break clients if schema changes.", "Select explicit columns needed by the caller (e.g. SELECT id, name) to reduce IO and improve compatibility.", idx, pos)
+	}
+	for _, tr := range s.From {
+		if jt, ok := tr.(*ast.JoinTable); ok && jt.Kind == ast.CrossJoin {
+			addFinding(report, SeverityWarning, "CROSS_JOIN", "CROSS JOIN can create a cartesian product and explode row counts.", "Ensure join cardinality is intended, or use an INNER/LEFT JOIN with explicit join predicates.", idx, jt.TokPos)
+		}
+	}
+	if pos, ok := commaCrossJoin(s, opts.Schema); ok {
+		addFinding(report, SeverityWarning, "IMPLICIT_CROSS_JOIN", "Comma-separated FROM items have no equality predicate between them in WHERE, which produces a cartesian product.", "Add the missing join predicate, or write an explicit JOIN ... ON so the join condition cannot be forgotten.", idx, pos)
+	}
+	analyzeExpr(s.Where, idx, report, opts)
+	analyzeExpr(s.Having, idx, report, opts)
+	for _, c := range s.Columns {
+		analyzeExpr(c.Expr, idx, report, opts)
+	}
+}
+
+// analyzeConversions flags comparisons of a string column with a number
+// and of a numeric column with a string. MySQL compares the first as
+// numbers, converting the column on every row so its index cannot be
+// used, and PostgreSQL rejects it; SQLite applies the column's affinity to
+// the literal, so neither needs a finding there.
+func analyzeConversions(stmt Statement, idx int, report *AnalysisReport, opts AnalysisOptions) {
+	if opts.Dialect == DialectSQLite {
+		return
+	}
+	a := &auditor{schema: opts.Schema, visit: func(string, string, accessKind) {}}
+	a.compare = func(scope *auditScope, left ast.Expr, right []ast.Expr) {
+		col, others := left, right
+		table, name, ok := a.resolve(col, scope)
+		if !ok && len(right) == 1 {
+			col, others = right[0], []ast.Expr{left}
+			table, name, ok = a.resolve(col, scope)
+		}
+		if !ok {
+			return
+		}
+		t := opts.Schema.Table(table)
+		if t == nil {
+			return
+		}
+		c := t.Column(name)
+		if c == nil {
+			return
+		}
+		for _, e := range others {
+			lit, number := comparedLiteral(e)
+			if lit == "" {
+				continue
+			}
+			switch {
+			case number && isStringColumn(c) && opts.Dialect == DialectPostgres:
+				addFinding(report, SeverityCritical, "IMPLICIT_CONVERSION", fmt.Sprintf("Column %s.%s is %s but is compared with the number %s; PostgreSQL has no operator for this comparison and rejects the query.", t.Name, c.Name, c.Type, lit), fmt.Sprintf("Compare with a string literal ('%s') or cast explicitly.", lit), idx, left.Pos())
+			case number && isStringColumn(c):
+				addFinding(report, SeverityWarning, "IMPLICIT_CONVERSION", fmt.Sprintf("Column %s.%s is %s but is compared with the number %s; MySQL converts the column to a number on every row, so its index cannot be used and values such as '01' also match.", t.Name, c.Name, c.Type, lit), fmt.Sprintf("Compare with a string literal ('%s'), or change the column type if it holds numbers.", lit), idx, left.Pos())
+			case !number && opts.Dialect != DialectPostgres:
+				if kind, _, _ := fixtureKindOf(c); kind == fixtureInt || kind == fixtureDecimal || kind == fixtureFloat {
+					addFinding(report, SeverityInfo, "IMPLICIT_CONVERSION", fmt.Sprintf("Column %s.%s is %s but is compared with the string %s, which is converted to a number; text that is not a number silently becomes 0.", t.Name, c.Name, c.Type, lit), "Compare with a numeric literal.", idx, left.Pos())
+				}
+			}
+			break
+		}
+	}
+	a.statement(stmt)
+}
+
+// analyzeBooleanComparisons flags comparisons of a boolean column with an
+// integer, as in active = 1. MySQL and SQLite store booleans as integers,
+// so it works there, but PostgreSQL has no operator for it and rejects the
+// query, and in MySQL a TINYINT(1) holding 2 is true yet not = 1.
+func analyzeBooleanComparisons(stmt Statement, idx int, report *AnalysisReport, opts AnalysisOptions) {
+	a := &auditor{schema: opts.Schema, visit: func(string, string, accessKind) {}}
+	a.compare = func(scope *auditScope, left ast.Expr, right []ast.Expr) {
+		col, others := left, right
+		table, name, ok := a.resolve(col, scope)
+		if !ok && len(right) == 1 {
+			col, others = right[0], []ast.Expr{left}
+			table, name, ok = a.resolve(col, scope)
+		}
+		if !ok {
+			return
+		}
+		t := opts.Schema.Table(table)
+		if t == nil {
+			return
+		}
+		c := t.Column(name)
+		if c == nil || !isBoolColumn(c) {
+			return
+		}
+		for _, e := range others {
+			lit, number := comparedLiteral(e)
+			if !number {
+				continue
+			}
+			severity, problem := SeverityInfo, fmt.Sprintf("Column %s.%s is a boolean but is compared with the integer %s, which only works where booleans are integers; PostgreSQL rejects the comparison.", t.Name, c.Name, lit)
+			if opts.Dialect == DialectPostgres {
+				severity, problem = SeverityCritical, fmt.Sprintf("Column %s.%s is a boolean but is compared with the integer %s; PostgreSQL has no operator for this comparison and rejects the query.", t.Name, c.Name, lit)
+			}
+			addFinding(report, severity, "BOOLEAN_COMPARED_WITH_INTEGER", problem, fmt.Sprintf("Test the column itself (WHERE %s or WHERE NOT %s) or compare with TRUE or FALSE, which every dialect reads.", c.Name, c.Name), idx, left.Pos())
+			break
 		}
 	}
+	a.statement(stmt)
+}
+
+// analyzeSargability flags comparisons of a column wrapped in a function
+// or arithmetic with a constant, as in DATE(created_at) = '2024-01-01' or
+// id + 1 = 10: the column is computed for every row, so its index cannot
+// be used. With a schema, only columns that lead an index are flagged.
+func analyzeSargability(stmt Statement, idx int, report *AnalysisReport, opts AnalysisOptions) {
+	a := &auditor{schema: opts.Schema, visit: func(string, string, accessKind) {}}
+	a.compare = func(scope *auditScope, left ast.Expr, right []ast.Expr) {
+		sides := [][]ast.Expr{{left}, right}
+		for i, side := range sides {
+			if len(side) != 1 {
+				continue
+			}
+			col, how := wrappedColumn(side[0])
+			if col == nil || slices.ContainsFunc(sides[1-i], func(e ast.Expr) bool { return a.references(e, scope) }) {
+				continue
+			}
+			if opts.Schema != nil {
+				table, name, ok := a.resolve(col, scope)
+				if !ok || !leadsIndex(opts.Schema.Table(table), name) {
+					continue
+				}
+			}
+			name := exprColumnName(col)
+			var advice string
+			switch how {
+			case "DATE", "YEAR", "MONTH", "DAY", "TO_CHAR", "DATE_FORMAT", "STRFTIME", "DATE_TRUNC", "EXTRACT":
+				advice = fmt.Sprintf("Compare %s with a half-open range instead, e.g. %s >= '2024-01-01' AND %s < '2024-01-02'.", name, name, name)
+			case "+", "-", "*", "/", "%":
+				advice = fmt.Sprintf("Move the arithmetic to the other side so %s is compared directly (e.g. %s = 10 - 1 instead of %s + 1 = 10).", name, name, name)
+			case "LOWER", "UPPER":
+				advice = fmt.Sprintf("Store %s normalized, use a case-insensitive collation (or citext in PostgreSQL), or index the expression.", name)
+			default:
+				advice = fmt.Sprintf("Rewrite the predicate on the bare column %s, or index the expression (an expression index, or a generated column with an index).", name)
+			}
+			addFinding(report, SeverityWarning, "NON_SARGABLE_PREDICATE", fmt.Sprintf("Column %s is wrapped in %s in a comparison, so it is computed for every row and its index cannot be used.", name, wrapperName(how)), advice, idx, exprStart(side[0]))
+		}
+	}
+	a.statement(stmt)
+}
+
+// wrappedColumn returns the column e computes a value from, and the
+// function name or arithmetic operator that wraps it, when e is a call of
+// a scalar function or arithmetic on a column. Aggregates are left alone.
+func wrappedColumn(e ast.Expr) (ast.Expr, string) {
+	switch x := e.(type) {
+	case *ast.FuncCall:
+		if x.Name == nil || x.Star {
+			return nil, ""
+		}
+		name := strings.ToUpper(x.Name.Parts[len(x.Name.Parts)-1].Unquoted)
+		if aggregateFuncs[name] {
+			return nil, ""
+		}
+		for _, arg := range x.Args {
+			if col := bareColumn(arg); col != nil {
+				return col, name
+			}
+			if col, _ := wrappedColumn(arg); col != nil {
+				return col, name
+			}
+		}
+	case *ast.BinaryExpr:
+		switch x.Op {
+		case lexer.PLUS, lexer.MINUS, lexer.STAR, lexer.SLASH, lexer.PERCENT:
+		default:
+			return nil, ""
+		}
+		for _, side := range []ast.Expr{x.Left, x.Right} {
+			if col := bareColumn(side); col != nil {
+				return col, x.Op.String()
+			}
+			if col, _ := wrappedColumn(side); col != nil {
+				return col, x.Op.String()
+			}
+		}
+	case *ast.CastExpr:
+		if col := bareColumn(x.Expr); col != nil {
+			return col, "CAST"
+		}
+		if col, _ := wrappedColumn(x.Expr); col != nil {
+			return col, "CAST"
+		}
+	}
+	return nil, ""
+}
+
+// bareColumn returns e when it is a plain column reference.
+func bareColumn(e ast.Expr) ast.Expr {
+	switch x := e.(type) {
+	case *ast.Ident, *ast.QualifiedIdent:
+		return x
+	}
+	return nil
+}
+
+func wrapperName(how string) string {
+	switch how {
+	case "+", "-", "*", "/", "%":
+		return "arithmetic (" + how + ")"
+	case "CAST":
+		return "a CAST"
+	}
+	return how + "()"
+}
+
+// exprColumnName returns a column reference as written, unquoted.
+func exprColumnName(e ast.Expr) string {
+	if q, ok := e.(*ast.QualifiedIdent); ok {
+		return qualifiedName(q)
+	}
+	return identName(e.(*ast.Ident))
+}
+
+// exprStart returns the offset where e begins; the position of a binary
+// expression is that of its operator, and that of a call its parenthesis.
+func exprStart(e ast.Expr) int32 {
+	for {
+		switch x := e.(type) {
+		case *ast.BinaryExpr:
+			e = x.Left
+		case *ast.FuncCall:
+			if x.Name != nil {
+				return x.Name.Parts[0].Pos()
+			}
+			return x.Pos()
+		default:
+			return e.Pos()
+		}
+	}
+}
+
+// leadsIndex reports whether column is the first column of the primary
+// key or of an index of t.
+func leadsIndex(t *Table, column string) bool {
+	if t == nil {
+		return false
+	}
+	if len(t.PrimaryKey) > 0 && ident.Equal(t.PrimaryKey[0], column, t.dialect) {
+		return true
+	}
+	return slices.ContainsFunc(t.Indexes, func(idx *Index) bool {
+		return len(idx.Columns) > 0 && ident.Equal(idx.Columns[0], column, t.dialect)
+	})
+}
+
+// comparedLiteral returns a numeric or string literal as written, and
+// whether it is a number; lit is empty for any other expression.
+func comparedLiteral(e ast.Expr) (lit string, number bool) {
+	neg := ""
+	if u, ok := e.(*ast.UnaryExpr); ok && u.Op == lexer.MINUS {
+		neg, e = "-", u.Expr
+	}
+	l, ok := e.(*ast.Literal)
+	if !ok {
+		return "", false
+	}
+	switch l.Kind {
+	case lexer.INT, lexer.FLOAT:
+		return neg + string(l.Raw), true
+	case lexer.STRING:
+		if neg == "" {
+			return string(l.Raw), false
+		}
+	}
+	return "", false
+}
+
+// isStringColumn reports whether c holds character strings.
+func isStringColumn(c *Column) bool {
+	switch strings.ToLower(c.Type) {
+	case "text", "tinytext", "mediumtext", "longtext", "citext", "clob", "string":
+		return true
+	}
+	return isCharType(c.Type)
 }
 
 func analyzeExpr(e Expr, idx int, report *AnalysisReport, opts AnalysisOptions) {
@@ -194,7 +645,7 @@ func analyzeExpr(e Expr, idx int, report *AnalysisReport, opts AnalysisOptions)
 		if lit, ok := ex.Pattern.(*ast.Literal); ok {
 			raw := string(lit.Raw)
 			if strings.HasPrefix(raw, "'%") || strings.HasPrefix(raw, "\"%") {
-				addFinding(report, SeverityInfo, "LIKE_LEADING_WILDCARD", "LIKE pattern starts with wildcard; index seeks are usually not possible.", "Use anchored pattern (for example 'abc%') or consider full-text/trigram indexing.", idx)
+				addFinding(report, SeverityInfo, "LIKE_LEADING_WILDCARD", "LIKE pattern starts with wildcard; index seeks are usually not possible.", "Use anchored pattern (for example 'abc%') or consider full-text/trigram indexing.", idx, lit.TokPos)
 			}
 		}
 		analyzeExpr(ex.Expr, idx, report, opts)
@@ -202,7 +653,7 @@ func analyzeExpr(e Expr, idx int, report *AnalysisReport, opts AnalysisOptions)
 		analyzeExpr(ex.Escape, idx, report, opts)
 	case *ast.BinaryExpr:
 		if strings.EqualFold(ex.Op.String(), "OR") {
-			addFinding(report, SeverityInfo, "OR_PREDICATE", "OR predicate can reduce index selectivity and lead to less efficient plans.", "Consider splitting into UNION ALL branches or adding composite indexes aligned with predicates.", idx)
+			addFinding(report, SeverityInfo, "OR_PREDICATE", "OR predicate can reduce index selectivity and lead to less efficient plans.", "Consider splitting into UNION ALL branches or adding composite indexes aligned with predicates.", idx, ex.Pos())
 		}
 		analyzeExpr(ex.Left, idx, report, opts)
 		analyzeExpr(ex.Right, idx, report, opts)
@@ -212,10 +663,10 @@ func analyzeExpr(e Expr, idx int, report *AnalysisReport, opts AnalysisOptions)
 		if ex.Name != nil && len(ex.Name.Parts) == 1 {
 			fn := strings.ToUpper(ex.Name.Parts[0].Unquoted)
 			if opts.Dialect == DialectPostgres && fn == "IFNULL" {
-				addFinding(report, SeverityWarning, "FUNCTION_DIALECT_REWRITE", "IFNULL is not idiomatic in PostgreSQL.", "Use COALESCE(...) for PostgreSQL compatibility.", idx)
+				addFinding(report, SeverityWarning, "FUNCTION_DIALECT_REWRITE", "IFNULL is not idiomatic in PostgreSQL.", "Use COALESCE(...) for PostgreSQL compatibility.", idx, ex.Pos())
 			}
 			if opts.Dialect == DialectMySQL && fn == "COALESCE" {
-				addFinding(report, SeverityInfo, "FUNCTION_DIALECT_REWRITE", "COALESCE will work in MySQL, but IFNULL is often preferred for 2-arg null handling.", "Use IFNULL(a,b) when you specifically need MySQL-style two-argument null coalescing.", idx)
+				addFinding(report, SeverityInfo, "FUNCTION_DIALECT_REWRITE", "COALESCE will work in MySQL, but IFNULL is often preferred for 2-arg null handling.", "Use IFNULL(a,b) when you specifically need MySQL-style two-argument null coalescing.", idx, ex.Pos())
 			}
 		}
 		for _, a := range ex.Args {
@@ -233,6 +684,9 @@ func analyzeExpr(e Expr, idx int, report *AnalysisReport, opts AnalysisOptions)
 		analyzeExpr(ex.Lo, idx, report, opts)
 		analyzeExpr(ex.Hi, idx, report, opts)
 	case *ast.InExpr:
+		if len(ex.List) > largeInList {
+			addFinding(report, SeverityInfo, "LARGE_IN_LIST", "Very large IN list detected; it inflates statement size and planning time and can exceed driver placeholder limits.", "Split the list with PlanInList or rewrite it as a VALUES join via ConvertOptions.InList.", idx, ex.Pos())
+		}
 		analyzeExpr(ex.Expr, idx, report, opts)
 		for _, v := range ex.List {
 			analyzeExpr(v, idx, report, opts)
@@ -259,21 +713,145 @@ func analyzeExpr(e Expr, idx int, report *AnalysisReport, opts AnalysisOptions)
 			}
 			analyzeExpr(ex.Subq.Where, idx, report, opts)
 		}
+	case *ast.QuantifiedComparisonExpr:
+		analyzeExpr(ex.Left, idx, report, opts)
+		analyzeExpr(ex.Array, idx, report, opts)
+		if ex.Subq != nil {
+			for _, c := range ex.Subq.Columns {
+				analyzeExpr(c.Expr, idx, report, opts)
+			}
+			analyzeExpr(ex.Subq.Where, idx, report, opts)
+		}
 	case *ast.CastExpr:
 		analyzeExpr(ex.Expr, idx, report, opts)
 	}
 }
 
-func hasSelectStar(cols []ast.SelectColumn) bool {
-	for _, c := range cols {
-		if c.Star {
+// commaCrossJoin returns the position of the first item of a
+// comma-separated FROM list that no top-level col = col term of WHERE
+// connects, directly or through other items, to the first one. Unqualified
+// columns are attributed with the schema; when one cannot be, nothing is
+// reported.
+func commaCrossJoin(s *ast.SelectStmt, schema *Schema) (int32, bool) {
+	if len(s.From) < 2 {
+		return 0, false
+	}
+	dialect := schema.identDialect()
+	items := map[string]int{}
+	for i, tr := range s.From {
+		fromQualifiers(tr, func(q string) { items[ident.Key(q, dialect)] = i })
+	}
+	item := func(e Expr) (int, bool) {
+		switch x := e.(type) {
+		case *ast.QualifiedIdent:
+			i, ok := items[ident.Key(qualifiedName(&ast.QualifiedIdent{Parts: x.Parts[:len(x.Parts)-1]}), dialect)]
+			return i, ok
+		case *ast.Ident:
+			found := -1
+			for i, tr := range s.From {
+				st, ok := tr.(*ast.SimpleTable)
+				if !ok || schema == nil {
+					return 0, false
+				}
+				if t := schema.lookup(st.Name); t == nil {
+					return 0, false
+				} else if t.Column(x.Unquoted) != nil {
+					if found >= 0 {
+						return 0, false
+					}
+					found = i
+				}
+			}
+			return found, found >= 0
+		}
+		return 0, false
+	}
+	group := make([]int, len(s.From))
+	for i := range group {
+		group[i] = i
+	}
+	var root func(int) int
+	root = func(i int) int {
+		if group[i] != i {
+			group[i] = root(group[i])
+		}
+		return group[i]
+	}
+	for _, term := range andTerms(s.Where, nil) {
+		b, ok := term.(*ast.BinaryExpr)
+		if !ok || b.Op != lexer.EQ || bareColumn(b.Left) == nil || bareColumn(b.Right) == nil {
+			continue
+		}
+		l, lok := item(b.Left)
+		r, rok := item(b.Right)
+		if !lok || !rok {
+			return 0, false
+		}
+		group[root(l)] = root(r)
+	}
+	for i := 1; i < len(s.From); i++ {
+		if root(i) != root(0) {
+			return s.From[i].Pos(), true
+		}
+	}
+	return 0, false
+}
+
+// fromQualifiers calls fn with each name that qualifies the columns of a
+// FROM item: aliases, or table names both bare and as written.
+func fromQualifiers(tr ast.TableRef, fn func(string)) {
+	switch t := tr.(type) {
+	case *ast.SimpleTable:
+		if t.Alias != nil {
+			fn(t.Alias.Unquoted)
+			return
+		}
+		fn(t.Name.Parts[len(t.Name.Parts)-1].Unquoted)
+		fn(qualifiedName(t.Name))
+	case *ast.SubqueryTable:
+		if t.Alias != nil {
+			fn(t.Alias.Unquoted)
+		}
+	case *ast.PivotTable:
+		if t.Alias != nil {
+			fn(t.Alias.Unquoted)
+			return
+		}
+		fromQualifiers(t.Source, fn)
+	case *ast.JoinTable:
+		fromQualifiers(t.Left, fn)
+		fromQualifiers(t.Right, fn)
+	}
+}
+
+func hasForeignKey(s *ast.CreateTableStmt) bool {
+	for _, c := range s.Columns {
+		if c.References != nil {
+			return true
+		}
+	}
+	for _, c := range s.Constraints {
+		if c.Type == ast.ForeignKeyConstraint {
 			return true
 		}
 	}
 	return false
 }
 
-func addFinding(report *AnalysisReport, sev FindingSeverity, code, problem, recommendation string, idx int) {
+// selectStar returns the position of the first * in the select list of s.
+func selectStar(s *ast.SelectStmt) (int32, bool) {
+	for _, c := range s.Columns {
+		if c.Star {
+			if c.Expr != nil {
+				return c.Expr.Pos(), true
+			}
+			return s.TokPos, true
+		}
+	}
+	return 0, false
+}
+
+func addFinding(report *AnalysisReport, sev FindingSeverity, code, problem, recommendation string, idx int, pos int32) {
 	msg := problem
 	if recommendation != "" {
 		msg += " Recommendation: " + recommendation
@@ -285,9 +863,33 @@ func addFinding(report *AnalysisReport, sev FindingSeverity, code, problem, reco
 		Problem:        problem,
 		Recommendation: recommendation,
 		StatementIndex: idx,
+		Pos:            pos,
 	})
 }
 
+// setFindingLines fills in the line and column of each finding, as
+// lexer.ComputeLineCol does. Findings come mostly in source order, so each
+// scan resumes where the previous one stopped.
+func setFindingLines(findings []AnalysisFinding, sql string) {
+	at, line, col := 0, uint32(1), uint32(1)
+	for i := range findings {
+		f := &findings[i]
+		pos := min(int(f.Pos), len(sql))
+		if pos < at {
+			at, line, col = 0, 1, 1
+		}
+		for ; at < pos; at++ {
+			if sql[at] == '\n' {
+				line++
+				col = 1
+			} else {
+				col++
+			}
+		}
+		f.Line, f.Col = line, col
+	}
+}
+
 func (r AnalysisReport) String() string {
 	if !r.Valid {
 		if len(r.Findings) == 0 {
@@ -300,3 +902,12 @@ func (r AnalysisReport) String() string {
 	}
 	return fmt.Sprintf("valid SQL (%d statements), %d finding(s)", r.StatementCount, len(r.Findings))
 }
+
+// JSON encodes the report as an indented JSON document. Field names are
+// those of the struct tags and stay stable across releases.
+func (r AnalysisReport) JSON() ([]byte, error) {
+	if r.Findings == nil {
+		r.Findings = []AnalysisFinding{}
+	}
+	return json.MarshalIndent(r, "", "  ")
+}
@@ -1,6 +1,10 @@
 package sqlparser_test
 
 import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	sqlparser "github.com/oarkflow/sqlparser"
@@ -33,6 +37,20 @@ func TestAnalyzeSQLRiskyPatterns(t *testing.T) {
 	}
 }
 
+func TestAnalyzeSQLTempTableForeignKey(t *testing.T) {
+	report := sqlparser.AnalyzeSQL(`CREATE TEMPORARY TABLE staging (id INT, user_id INT REFERENCES users (id));
+		CREATE TABLE orders (id INT, user_id INT REFERENCES users (id))`)
+	var n int
+	for _, f := range report.Findings {
+		if f.Code == "TEMP_TABLE_FOREIGN_KEY" {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Fatalf("expected one TEMP_TABLE_FOREIGN_KEY finding, findings=%#v", report.Findings)
+	}
+}
+
 func TestAnalyzeSQLJSONBHint(t *testing.T) {
 	report := sqlparser.AnalyzeSQLWithOptions(`CREATE TABLE events (payload JSONB)`, sqlparser.AnalysisOptions{Dialect: sqlparser.DialectMySQL})
 	if !report.Valid {
@@ -67,6 +85,19 @@ func TestAnalyzeSQLDialectFunctionMismatch(t *testing.T) {
 	}
 }
 
+func TestAnalyzeSQLTruncateMultiTable(t *testing.T) {
+	for _, tt := range []struct {
+		dialect sqlparser.Dialect
+		want    bool
+	}{{sqlparser.DialectMySQL, true}, {sqlparser.DialectPostgres, false}} {
+		report := sqlparser.AnalyzeSQLWithOptions(`TRUNCATE a, b`, sqlparser.AnalysisOptions{Dialect: tt.dialect})
+		found := slices.ContainsFunc(report.Findings, func(f sqlparser.AnalysisFinding) bool { return f.Code == "TRUNCATE_MULTI_TABLE" })
+		if found != tt.want {
+			t.Errorf("%s: TRUNCATE_MULTI_TABLE reported %v, want %v", tt.dialect, found, tt.want)
+		}
+	}
+}
+
 func TestOptimizeSQLForDialect(t *testing.T) {
 	in := `INSERT INTO users (id, name) VALUES (1, IFNULL(:name, 'x')) ON DUPLICATE KEY UPDATE name = IFNULL(:name, name)`
 	out, err := sqlparser.OptimizeSQLForDialect(in, sqlparser.DialectPostgres)
@@ -80,3 +111,349 @@ func TestOptimizeSQLForDialect(t *testing.T) {
 		t.Fatalf("expected optimization actions")
 	}
 }
+
+func TestAnalyzeSQLFindingPositions(t *testing.T) {
+	sql := "SELECT id FROM users;\nSELECT id, name\n  FROM users WHERE name LIKE '%abc';\nUPDATE users SET active = 1"
+	report := sqlparser.AnalyzeSQL(sql)
+	want := map[string][2]uint32{"LIKE_LEADING_WILDCARD": {3, 30}, "UPDATE_WITHOUT_WHERE": {4, 1}}
+	for _, f := range report.Findings {
+		pos, ok := want[f.Code]
+		if !ok {
+			continue
+		}
+		delete(want, f.Code)
+		if f.Line != pos[0] || f.Col != pos[1] {
+			t.Errorf("%s at %d:%d, want %d:%d", f.Code, f.Line, f.Col, pos[0], pos[1])
+		}
+		if got := sql[f.Pos:]; f.Code == "LIKE_LEADING_WILDCARD" && got[:6] != "'%abc'" {
+			t.Errorf("%s: Pos points at %q", f.Code, got)
+		}
+	}
+	if len(want) > 0 {
+		t.Fatalf("missing findings %v in %#v", want, report.Findings)
+	}
+
+	report = sqlparser.AnalyzeSQL("SELECT 1;\nSELECT FROM")
+	if len(report.Findings) != 1 || report.Findings[0].Line != 2 || report.Findings[0].Col != 8 {
+		t.Fatalf("unexpected parse error finding %#v", report.Findings)
+	}
+}
+
+func TestAnalyzeSQLImplicitConversion(t *testing.T) {
+	schema, err := sqlparser.BuildSchema(`CREATE TABLE users (id INT PRIMARY KEY, phone VARCHAR(20), bio TEXT)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql := "SELECT id FROM users u WHERE u.phone = 5551234 OR bio IN (1, 2) OR id = '7' OR 3 < phone OR phone = '1' OR id = 1"
+	tests := []struct {
+		dialect sqlparser.Dialect
+		want    map[int]sqlparser.FindingSeverity // offset of the comparison -> severity
+	}{
+		{sqlparser.DialectMySQL, map[int]sqlparser.FindingSeverity{
+			strings.Index(sql, "u.phone"):   sqlparser.SeverityWarning,
+			strings.Index(sql, "bio IN"):    sqlparser.SeverityWarning,
+			strings.Index(sql, "id = '7'"):  sqlparser.SeverityInfo,
+			strings.Index(sql, "3 < phone"): sqlparser.SeverityWarning,
+		}},
+		{sqlparser.DialectPostgres, map[int]sqlparser.FindingSeverity{
+			strings.Index(sql, "u.phone"):   sqlparser.SeverityCritical,
+			strings.Index(sql, "bio IN"):    sqlparser.SeverityCritical,
+			strings.Index(sql, "3 < phone"): sqlparser.SeverityCritical,
+		}},
+		{sqlparser.DialectSQLite, map[int]sqlparser.FindingSeverity{}},
+	}
+	for _, tt := range tests {
+		report := sqlparser.AnalyzeSQLWithOptions(sql, sqlparser.AnalysisOptions{Dialect: tt.dialect, Schema: schema})
+		got := map[int]sqlparser.FindingSeverity{}
+		for _, f := range report.Findings {
+			if f.Code == "IMPLICIT_CONVERSION" {
+				got[int(f.Pos)] = f.Severity
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.dialect, got, tt.want)
+		}
+	}
+
+	report := sqlparser.AnalyzeSQL(sql)
+	for _, f := range report.Findings {
+		if f.Code == "IMPLICIT_CONVERSION" {
+			t.Errorf("finding without a schema: %+v", f)
+		}
+	}
+}
+
+func TestAnalyzeSQLBooleanComparisons(t *testing.T) {
+	schema, err := sqlparser.BuildSchema(`CREATE TABLE users (id INT PRIMARY KEY, active TINYINT(1), admin BOOLEAN, level TINYINT(4))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql := "SELECT id FROM users u WHERE u.active = 1 AND 0 <> admin AND level = 1 AND admin = TRUE AND active"
+	tests := []struct {
+		dialect sqlparser.Dialect
+		want    map[int]sqlparser.FindingSeverity
+	}{
+		{sqlparser.DialectMySQL, map[int]sqlparser.FindingSeverity{
+			strings.Index(sql, "u.active"): sqlparser.SeverityInfo,
+			strings.Index(sql, "0 <>"):     sqlparser.SeverityInfo,
+		}},
+		{sqlparser.DialectPostgres, map[int]sqlparser.FindingSeverity{
+			strings.Index(sql, "u.active"): sqlparser.SeverityCritical,
+			strings.Index(sql, "0 <>"):     sqlparser.SeverityCritical,
+		}},
+	}
+	for _, tt := range tests {
+		report := sqlparser.AnalyzeSQLWithOptions(sql, sqlparser.AnalysisOptions{Dialect: tt.dialect, Schema: schema})
+		got := map[int]sqlparser.FindingSeverity{}
+		for _, f := range report.Findings {
+			if f.Code == "BOOLEAN_COMPARED_WITH_INTEGER" {
+				got[int(f.Pos)] = f.Severity
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeSQLNonSargable(t *testing.T) {
+	sql := "SELECT id FROM orders o JOIN users u ON DATE(u.created_at) = o.day " +
+		"WHERE DATE(o.created_at) = '2024-01-01' AND o.total + 1 = 10 AND LOWER(o.email) IN ('a', 'b') " +
+		"AND o.note LIKE 'x%' AND o.id = 3 AND COUNT(o.id) > 1 AND ? = YEAR(o.placed)"
+	codes := func(opts sqlparser.AnalysisOptions) []int {
+		var got []int
+		for _, f := range sqlparser.AnalyzeSQLWithOptions(sql, opts).Findings {
+			if f.Code == "NON_SARGABLE_PREDICATE" {
+				got = append(got, int(f.Pos))
+			}
+		}
+		return got
+	}
+	want := []int{
+		strings.Index(sql, "DATE(o.created_at)"),
+		strings.Index(sql, "o.total + 1"),
+		strings.Index(sql, "LOWER(o.email)"),
+		strings.Index(sql, "YEAR(o.placed)"),
+	}
+	if got := codes(sqlparser.AnalysisOptions{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	schema, err := sqlparser.BuildSchema(`
+		CREATE TABLE orders (id INT PRIMARY KEY, created_at DATETIME, total INT, email TEXT, placed DATE, note TEXT, day DATE);
+		CREATE INDEX orders_created ON orders (created_at, total);
+		CREATE TABLE users (id INT PRIMARY KEY, created_at DATETIME)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := codes(sqlparser.AnalysisOptions{Schema: schema}); !reflect.DeepEqual(got, want[:1]) {
+		t.Errorf("with schema: got %v, want %v", got, want[:1])
+	}
+}
+
+func TestAnalyzeSQLImplicitCrossJoin(t *testing.T) {
+	schema, err := sqlparser.BuildSchema(`
+		CREATE TABLE users (id INT PRIMARY KEY, name TEXT);
+		CREATE TABLE orders (id INT PRIMARY KEY, user_id INT);
+		CREATE TABLE items (order_id INT, sku TEXT)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		sql  string
+		want string // the FROM item reported, or "" for none
+	}{
+		{"SELECT * FROM users u, orders o WHERE u.name = 'x'", "orders o"},
+		{"SELECT * FROM users u, orders o WHERE u.id = o.user_id", ""},
+		{"SELECT * FROM users u, orders o, items i WHERE u.id = o.user_id AND o.id > 3", "items i"},
+		{"SELECT * FROM users u, orders o, items i WHERE i.order_id = o.id AND o.user_id = u.id", ""},
+		{"SELECT * FROM users, app.orders WHERE users.id = app.orders.user_id", ""},
+		{"SELECT * FROM users, orders WHERE name = user_id", ""},
+		{"SELECT * FROM users, items WHERE id = order_id", ""},
+		{"SELECT * FROM users, items WHERE id = 1", "items WHERE"},
+		{"SELECT * FROM users u JOIN orders o ON o.user_id = u.id", ""},
+	}
+	for _, tt := range tests {
+		report := sqlparser.AnalyzeSQLWithOptions(tt.sql, sqlparser.AnalysisOptions{Schema: schema})
+		got := ""
+		for _, f := range report.Findings {
+			if f.Code == "IMPLICIT_CROSS_JOIN" {
+				got = tt.sql[f.Pos:]
+			}
+		}
+		if tt.want == "" && got != "" || !strings.HasPrefix(got, tt.want) {
+			t.Errorf("%s: reported at %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+
+	report := sqlparser.AnalyzeSQL("SELECT * FROM users, orders WHERE id = user_id")
+	for _, f := range report.Findings {
+		if f.Code == "IMPLICIT_CROSS_JOIN" {
+			t.Errorf("unresolved columns reported without a schema: %+v", f)
+		}
+	}
+}
+
+func TestAnalysisReportJSON(t *testing.T) {
+	report := sqlparser.AnalyzeSQL("SELECT 1;\nDELETE FROM logs")
+	out, err := report.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Valid    bool `json:"valid"`
+		Findings []struct {
+			Code string `json:"code"`
+			Line int    `json:"line"`
+		} `json:"findings"`
+	}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if !doc.Valid || len(doc.Findings) != 1 || doc.Findings[0].Code != "DELETE_WITHOUT_WHERE" || doc.Findings[0].Line != 2 {
+		t.Fatalf("unexpected JSON %s", out)
+	}
+}
+
+func TestAnalysisReportSARIF(t *testing.T) {
+	report := sqlparser.AnalyzeSQL("DELETE FROM logs;\nDELETE FROM jobs;\nSELECT * FROM t")
+	out, err := report.SARIF("db/cleanup.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(out, &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF %s", out)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || len(run.Results) != 3 {
+		t.Fatalf("unexpected rules or results %s", out)
+	}
+	r := run.Results[1]
+	loc := r.Locations[0].PhysicalLocation
+	if r.RuleID != "DELETE_WITHOUT_WHERE" || r.RuleIndex != 0 || r.Level != "error" || loc.ArtifactLocation.URI != "db/cleanup.sql" || loc.Region.StartLine != 2 {
+		t.Fatalf("unexpected result %+v", r)
+	}
+	if run.Results[2].Level != "warning" || run.Results[2].RuleIndex != 1 {
+		t.Fatalf("unexpected result %+v", run.Results[2])
+	}
+}
+
+func TestAnalyzeSQLRecursiveCTE(t *testing.T) {
+	tests := []struct {
+		sql     string
+		dialect sqlparser.Dialect
+		want    []string
+	}{
+		{"WITH RECURSIVE n (i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 10) SELECT i FROM n", "", nil},
+		{"WITH RECURSIVE tree AS (SELECT id FROM nodes WHERE parent IS NULL UNION ALL SELECT c.id FROM nodes c JOIN tree p ON c.parent = p.id) SELECT id FROM tree", sqlparser.DialectMySQL, nil},
+		{"WITH RECURSIVE n (i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n) SELECT i FROM n", "", []string{"RECURSIVE_CTE_UNBOUNDED"}},
+		{"WITH RECURSIVE n (i) AS (SELECT i FROM n) SELECT i FROM n", "", []string{"RECURSIVE_CTE_NO_UNION"}},
+		{"WITH RECURSIVE n (i) AS (SELECT 1 INTERSECT SELECT i FROM n WHERE i < 3) SELECT i FROM n", "", []string{"RECURSIVE_CTE_NO_UNION"}},
+		{"WITH RECURSIVE n (i) AS (SELECT i FROM n WHERE i < 3 UNION SELECT 1) SELECT i FROM n", "", []string{"RECURSIVE_CTE_NO_ANCHOR"}},
+		{"WITH RECURSIVE n (i) AS (SELECT 1 UNION ALL SELECT MAX(i) + 1 FROM n WHERE i < 3) SELECT i FROM n", sqlparser.DialectMySQL, []string{"RECURSIVE_CTE_MYSQL"}},
+		{"WITH RECURSIVE n (i) AS (SELECT 1 UNION ALL SELECT a.i + 1 FROM n a, n b WHERE a.i < 3) SELECT i FROM n", sqlparser.DialectMySQL, []string{"RECURSIVE_CTE_MYSQL"}},
+		{"WITH RECURSIVE n (i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n) DELETE FROM t WHERE id IN (SELECT i FROM n)", "", []string{"RECURSIVE_CTE_UNBOUNDED"}},
+		{"WITH n (i) AS (SELECT 1) SELECT i FROM n", "", nil},
+	}
+	for _, tt := range tests {
+		report := sqlparser.AnalyzeSQLWithOptions(tt.sql, sqlparser.AnalysisOptions{Dialect: tt.dialect})
+		var got []string
+		for _, f := range report.Findings {
+			if strings.HasPrefix(f.Code, "RECURSIVE_CTE_") {
+				got = append(got, f.Code)
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeSQLReservedWords(t *testing.T) {
+	tests := []struct {
+		sql     string
+		dialect sqlparser.Dialect
+		want    []string
+	}{
+		{"SELECT id, user FROM accounts ORDER BY id", sqlparser.DialectPostgres, []string{"user"}},
+		{"SELECT id, user FROM accounts ORDER BY id", sqlparser.DialectMySQL, nil},
+		{`SELECT id, "user" FROM accounts`, sqlparser.DialectPostgres, nil},
+		{"SELECT rank, `key` FROM scores WHERE rank > 1", sqlparser.DialectMySQL, []string{"rank", "rank"}},
+		{"CREATE TABLE t (id INT, user TEXT)", sqlparser.DialectPostgres, []string{"user"}},
+		{"SELECT id, user FROM accounts", "", nil},
+	}
+	for _, tt := range tests {
+		report := sqlparser.AnalyzeSQLWithOptions(tt.sql, sqlparser.AnalysisOptions{Dialect: tt.dialect})
+		var got []string
+		for _, f := range report.Findings {
+			if f.Code == "RESERVED_WORD_IDENTIFIER" {
+				word := tt.sql[f.Pos:]
+				if i := strings.IndexAny(word, " ,)"); i >= 0 {
+					word = word[:i]
+				}
+				got = append(got, word)
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeSQLTypeSemanticLoss(t *testing.T) {
+	const sql = "CREATE TABLE hosts (id UUID PRIMARY KEY, ip INET, name TEXT)"
+	tests := []struct {
+		dialect sqlparser.Dialect
+		want    int
+	}{
+		{sqlparser.DialectMySQL, 2},
+		{sqlparser.DialectClickHouse, 1},
+		{sqlparser.DialectPostgres, 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		report := sqlparser.AnalyzeSQLWithOptions(sql, sqlparser.AnalysisOptions{Dialect: tt.dialect})
+		got := 0
+		for _, f := range report.Findings {
+			if f.Code == "TYPE_SEMANTIC_LOSS" {
+				got++
+				if !strings.HasPrefix(sql[f.Pos:], "UUID") && !strings.HasPrefix(sql[f.Pos:], "INET") {
+					t.Errorf("%s: finding at %d: %s", tt.dialect, f.Pos, f.Message)
+				}
+			}
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %d TYPE_SEMANTIC_LOSS findings, want %d", tt.dialect, got, tt.want)
+		}
+	}
+}
@@ -60,6 +60,16 @@ func (n *StarExpr) node()      {}
 func (n *StarExpr) exprNode()  {}
 func (n *StarExpr) Pos() int32 { return n.TokPos }
 
+// TableStar is table.*, every column of one FROM item. Table is the
+// qualifier as written, e.g. t or schema.t.
+type TableStar struct {
+	Table *QualifiedIdent
+}
+
+func (n *TableStar) node()      {}
+func (n *TableStar) exprNode()  {}
+func (n *TableStar) Pos() int32 { return n.Table.Pos() }
+
 // Literal is a numeric, string, bool, hex, or bit literal.
 type Literal struct {
 	Raw    []byte
@@ -78,6 +88,14 @@ func (n *NullLit) node()      {}
 func (n *NullLit) exprNode()  {}
 func (n *NullLit) Pos() int32 { return n.TokPos }
 
+// DefaultExpr is the DEFAULT keyword used as a value, in a VALUES row or
+// an assignment.
+type DefaultExpr struct{ TokPos int32 }
+
+func (n *DefaultExpr) node()      {}
+func (n *DefaultExpr) exprNode()  {}
+func (n *DefaultExpr) Pos() int32 { return n.TokPos }
+
 // Param is a query parameter: ?, :name, @name, $N.
 type Param struct {
 	Raw    []byte
@@ -116,13 +134,26 @@ type FuncCall struct {
 	Args     []Expr
 	Distinct bool
 	Star     bool // COUNT(*)
-	TokPos   int32
+	// Over makes the call a window function.
+	Over   *WindowSpec
+	TokPos int32
 }
 
 func (n *FuncCall) node()      {}
 func (n *FuncCall) exprNode()  {}
 func (n *FuncCall) Pos() int32 { return n.TokPos }
 
+// WindowSpec is the OVER clause of a window function: OVER name, or OVER
+// ([PARTITION BY ...] [ORDER BY ...] [frame]). Frame is the ROWS, RANGE
+// or GROUPS clause as written.
+type WindowSpec struct {
+	Name        *Ident
+	PartitionBy []Expr
+	OrderBy     []OrderByItem
+	Frame       []byte
+	TokPos      int32
+}
+
 // CaseExpr is CASE ... END.
 type CaseExpr struct {
 	Operand Expr // nil for searched case
@@ -196,6 +227,70 @@ func (n *ExistsExpr) node()      {}
 func (n *ExistsExpr) exprNode()  {}
 func (n *ExistsExpr) Pos() int32 { return n.TokPos }
 
+// QuantifiedComparisonExpr is expr op ANY | SOME | ALL (subquery), or
+// PostgreSQL's expr op ANY (array). Exactly one of Subq and Array is set.
+type QuantifiedComparisonExpr struct {
+	Left       Expr
+	Op         lexer.TokenType
+	Quantifier Quantifier
+	Subq       *SelectStmt
+	Array      Expr
+	TokPos     int32
+}
+
+// Quantifier is the ANY, SOME or ALL of a quantified comparison. SOME is
+// a synonym of ANY, kept apart so the query renders as written.
+type Quantifier uint8
+
+const (
+	QuantifyAny Quantifier = iota
+	QuantifySome
+	QuantifyAll
+)
+
+func (q Quantifier) String() string {
+	switch q {
+	case QuantifySome:
+		return "SOME"
+	case QuantifyAll:
+		return "ALL"
+	}
+	return "ANY"
+}
+
+func (n *QuantifiedComparisonExpr) node()      {}
+func (n *QuantifiedComparisonExpr) exprNode()  {}
+func (n *QuantifiedComparisonExpr) Pos() int32 { return n.TokPos }
+
+// InsertedRef is a reference, in the update part of an upsert, to the
+// value the INSERT tried to write to Column.
+type InsertedRef struct {
+	Form InsertedForm
+	// Qualifier is EXCLUDED or the row alias as written; nil for VALUES().
+	Qualifier *Ident
+	// Column is a column of the target table. A reference through the
+	// column list of a MySQL row alias names the column it stands for.
+	Column *Ident
+	TokPos int32
+}
+
+// InsertedForm is how an InsertedRef was spelled.
+type InsertedForm uint8
+
+const (
+	// InsertedExcluded is PostgreSQL's and SQLite's excluded.col.
+	InsertedExcluded InsertedForm = iota
+	// InsertedValues is MySQL's VALUES(col).
+	InsertedValues
+	// InsertedAlias is MySQL 8.0's alias.col, after
+	// INSERT ... VALUES (...) AS alias.
+	InsertedAlias
+)
+
+func (n *InsertedRef) node()      {}
+func (n *InsertedRef) exprNode()  {}
+func (n *InsertedRef) Pos() int32 { return n.TokPos }
+
 // SubqueryExpr is a scalar subquery.
 type SubqueryExpr struct {
 	Subq   *SelectStmt
@@ -230,7 +325,10 @@ func (n *IntervalExpr) Pos() int32 { return n.TokPos }
 
 // ---- Data types ----
 
-// DataType represents a SQL column type.
+// DataType represents a SQL column type. An array type, written INT[],
+// ARRAY<INT> or Array(Int32), has Name ARRAY and its element type in Elem.
+// A struct type, STRUCT<a INT>, STRUCT(a INT) or Tuple(a Int32), keeps its
+// name as written and lists its members in Fields.
 type DataType struct {
 	Name      []byte
 	Precision int
@@ -240,7 +338,21 @@ type DataType struct {
 	Charset   []byte
 	Collation []byte
 	EnumVals  [][]byte // for ENUM/SET
-	TokPos    int32
+	Elem      *DataType
+	Fields    []StructField
+	// Subtype and SRID are the shape and spatial reference system of a
+	// spatial type, from PostGIS's GEOMETRY(Point, 4326) or MySQL's SRID
+	// column attribute. SRID is 0 when none is given.
+	Subtype []byte
+	SRID    int
+	TokPos  int32
+}
+
+// StructField is one member of a struct type. Name is nil for the
+// unnamed members of a ClickHouse Tuple.
+type StructField struct {
+	Name *Ident
+	Type *DataType
 }
 
 // ---- Table references ----
@@ -255,23 +367,98 @@ type TableRef interface {
 type SimpleTable struct {
 	Name  *QualifiedIdent
 	Alias *Ident
+	// IndexHints are MySQL's USE, FORCE and IGNORE INDEX clauses, in
+	// source order.
+	IndexHints []IndexHint
+	// Sample is PostgreSQL's TABLESAMPLE clause.
+	Sample *TableSample
 }
 
 func (n *SimpleTable) node()         {}
 func (n *SimpleTable) tableRefNode() {}
 func (n *SimpleTable) Pos() int32    { return n.Name.Pos() }
 
+// IndexHint is MySQL's {USE | FORCE | IGNORE} {INDEX | KEY} [FOR ...]
+// (index, ...). Indexes is empty for USE INDEX (), which uses none.
+type IndexHint struct {
+	Kind    IndexHintKind
+	For     IndexHintScope
+	Indexes []*Ident
+	TokPos  int32
+}
+
+type IndexHintKind uint8
+
+const (
+	UseIndex IndexHintKind = iota
+	ForceIndex
+	IgnoreIndex
+)
+
+// IndexHintScope is the FOR clause of an index hint: the part of the
+// query the hint applies to.
+type IndexHintScope uint8
+
+const (
+	HintAll IndexHintScope = iota
+	HintJoin
+	HintOrderBy
+	HintGroupBy
+)
+
+// TableSample is TABLESAMPLE method (args) [REPEATABLE (seed)].
+type TableSample struct {
+	Method     *Ident
+	Args       []Expr
+	Repeatable Expr
+	TokPos     int32
+}
+
 // SubqueryTable is (SELECT ...) [AS alias].
 type SubqueryTable struct {
-	Subq   *SelectStmt
-	Alias  *Ident
-	TokPos int32
+	Subq  *SelectStmt
+	Alias *Ident
+	// Columns renames the subquery's columns, as in (VALUES ...) v(id, name).
+	Columns []*Ident
+	TokPos  int32
 }
 
 func (n *SubqueryTable) node()         {}
 func (n *SubqueryTable) tableRefNode() {}
 func (n *SubqueryTable) Pos() int32    { return n.TokPos }
 
+// PivotTable is Source PIVOT (agg FOR column IN (value [AS alias], ...))
+// [AS alias], which turns the values of one column into columns, or with
+// Unpivot set Source UNPIVOT [INCLUDE NULLS] (value FOR name IN (column,
+// ...)), which turns columns back into rows.
+type PivotTable struct {
+	Source  TableRef
+	Unpivot bool
+	// Agg is the aggregate of a PIVOT; Value names the column an UNPIVOT
+	// writes the values of the listed columns to.
+	Agg   *FuncCall
+	Value *Ident
+	// For is the column a PIVOT spreads by, or the column an UNPIVOT
+	// writes the names of the listed columns to.
+	For *Ident
+	In  []PivotValue
+	// IncludeNulls keeps the rows an UNPIVOT makes from NULL values.
+	IncludeNulls bool
+	Alias        *Ident
+	TokPos       int32
+}
+
+func (n *PivotTable) node()         {}
+func (n *PivotTable) tableRefNode() {}
+func (n *PivotTable) Pos() int32    { return n.TokPos }
+
+// PivotValue is one entry of the IN list of a PIVOT, a value of the FOR
+// column, or of an UNPIVOT, a column.
+type PivotValue struct {
+	Expr  Expr
+	Alias *Ident
+}
+
 // JoinTable represents a JOIN expression.
 type JoinTable struct {
 	Left, Right TableRef
@@ -297,19 +484,32 @@ func (n *JoinTable) Pos() int32    { return n.TokPos }
 
 // ---- DML Statements ----
 
-// SelectStmt represents a SELECT statement.
+// SelectStmt represents a SELECT statement. With SetOp set it is a set
+// operation instead: its select list and FROM through HAVING are empty,
+// and With, OrderBy and Limit apply to the combined result.
 type SelectStmt struct {
-	With     *WithClause
+	With *WithClause
+	// Hints are the optimizer hints of a /*+ ... */ comment directly after
+	// SELECT, in source order.
+	Hints    []OptimizerHint
 	Distinct bool
 	Columns  []SelectColumn
 	From     []TableRef
 	Where    Expr
 	GroupBy  []Expr
 	Having   Expr
-	OrderBy  []OrderByItem
-	Limit    *LimitClause
-	SetOp    *SetOperation // UNION/INTERSECT/EXCEPT
-	TokPos   int32
+	// Qualify filters rows on window function results, after HAVING.
+	Qualify Expr
+	OrderBy []OrderByItem
+	// LimitBy is ClickHouse's LIMIT n BY expr, applied before Limit.
+	LimitBy *LimitByClause
+	Limit   *LimitClause
+	SetOp   *SetOperation // UNION/INTERSECT/EXCEPT
+	// Values holds the rows of a VALUES table constructor, which takes the
+	// place of the select list and of FROM through HAVING; only ORDER BY
+	// and LIMIT apply then.
+	Values [][]Expr
+	TokPos int32
 }
 
 func (n *SelectStmt) node()      {}
@@ -326,13 +526,41 @@ type CTE struct {
 	Name    *Ident
 	Columns []*Ident
 	Subq    *SelectStmt
+	// DML is the body of PostgreSQL's data-modifying CTE, an INSERT,
+	// UPDATE or DELETE whose RETURNING rows the CTE holds; Subq is nil
+	// then.
+	DML          Statement
+	Materialized Materialization
+}
+
+// Materialization is PostgreSQL's AS [NOT] MATERIALIZED hint on a CTE.
+type Materialization uint8
+
+const (
+	MaterializeDefault Materialization = iota
+	// MaterializeAlways is AS MATERIALIZED: the CTE is computed once.
+	MaterializeAlways
+	// MaterializeNever is AS NOT MATERIALIZED: the CTE may be inlined.
+	MaterializeNever
+)
+
+// OptimizerHint is one hint from a /*+ ... */ comment, e.g. INDEX(t idx):
+// Name is INDEX and Args is "t idx". Args is nil for a hint written without
+// parentheses.
+type OptimizerHint struct {
+	Name   []byte
+	Args   []byte
+	TokPos int32
 }
 
-// SelectColumn is a single column in a SELECT list.
+// SelectColumn is a single column in a SELECT list. Star marks a bare *;
+// table.* is a TableStar Expr. Except lists the columns left out of either
+// with BigQuery's * EXCEPT (a, b) or DuckDB's * EXCLUDE (a, b).
 type SelectColumn struct {
-	Expr  Expr
-	Alias *Ident
-	Star  bool // table.*
+	Expr   Expr
+	Alias  *Ident
+	Star   bool
+	Except []*Ident
 }
 
 // OrderByItem is a single ORDER BY key.
@@ -344,15 +572,33 @@ type OrderByItem struct {
 
 // LimitClause is LIMIT count [OFFSET skip].
 type LimitClause struct {
+	// Count is nil for LIMIT ALL and for an OFFSET without a limit.
 	Count  Expr
 	Offset Expr
+	// Fetch records the standard FETCH FIRST | NEXT spelling. WithTies is
+	// its WITH TIES, which also returns the rows that tie with the last.
+	Fetch    bool
+	WithTies bool
 }
 
-// SetOperation chains SELECT statements.
+// LimitByClause is ClickHouse's LIMIT count [OFFSET skip] BY exprs, which
+// keeps the first count rows of each group of equal By values.
+type LimitByClause struct {
+	Count  Expr
+	Offset Expr
+	By     []Expr
+	TokPos int32
+}
+
+// SetOperation combines two queries. Left and Right are SELECT blocks or
+// set operations themselves: INTERSECT binds tighter than UNION and
+// EXCEPT, which group from the left, so a UNION b INTERSECT c has the
+// INTERSECT as its Right. An operand with its own ORDER BY or LIMIT was
+// written in parentheses.
 type SetOperation struct {
-	Op    SetOp
-	All   bool
-	Right *SelectStmt
+	Op          SetOp
+	All         bool
+	Left, Right *SelectStmt
 }
 type SetOp uint8
 
@@ -364,18 +610,38 @@ const (
 
 // InsertStmt represents an INSERT statement.
 type InsertStmt struct {
-	With                *WithClause
-	Table               *QualifiedIdent
-	Columns             []*Ident
-	Values              [][]Expr // rows
-	Select              *SelectStmt
-	OnDupKey            []Assignment
-	OnConflictTarget    []*Ident
+	With    *WithClause
+	Table   *QualifiedIdent
+	Columns []*Ident
+	Values  [][]Expr // rows
+	Select  *SelectStmt
+	// DefaultValues is INSERT ... DEFAULT VALUES: one row of defaults.
+	DefaultValues bool
+	// SetForm records that the row was written as MySQL's
+	// INSERT ... SET col = value, ...; Columns and Values hold it as a
+	// single row.
+	SetForm bool
+	// RowAlias is MySQL's INSERT ... VALUES (...) AS alias [(cols)], which
+	// names the new row for ON DUPLICATE KEY UPDATE.
+	RowAlias         *Ident
+	RowAliasColumns  []*Ident
+	OnDupKey         []Assignment
+	OnConflictTarget []*Ident
+	// OnConflictConstraint is ON CONFLICT ON CONSTRAINT name, in place of
+	// a target column list.
+	OnConflictConstraint *Ident
+	// OnConflictWhere is the predicate after the target columns, which
+	// picks a partial unique index.
+	OnConflictWhere     Expr
 	OnConflictDoNothing bool
 	OnConflictUpdate    []Assignment
-	Ignore              bool
-	Replace             bool // REPLACE INTO
-	TokPos              int32
+	// OnConflictUpdateWhere is DO UPDATE ... WHERE: rows that fail it are
+	// left unchanged.
+	OnConflictUpdateWhere Expr
+	Ignore                bool
+	Replace               bool // REPLACE INTO
+	Returning             []SelectColumn
+	TokPos                int32
 }
 
 func (n *InsertStmt) node()      {}
@@ -390,13 +656,14 @@ type Assignment struct {
 
 // UpdateStmt represents an UPDATE statement.
 type UpdateStmt struct {
-	With   *WithClause
-	Tables []TableRef
-	Set    []Assignment
-	Where  Expr
-	Order  []OrderByItem
-	Limit  *LimitClause
-	TokPos int32
+	With      *WithClause
+	Tables    []TableRef
+	Set       []Assignment
+	Where     Expr
+	Order     []OrderByItem
+	Limit     *LimitClause
+	Returning []SelectColumn
+	TokPos    int32
 }
 
 func (n *UpdateStmt) node()      {}
@@ -405,13 +672,14 @@ func (n *UpdateStmt) Pos() int32 { return n.TokPos }
 
 // DeleteStmt represents a DELETE statement.
 type DeleteStmt struct {
-	With   *WithClause
-	Tables []*QualifiedIdent
-	From   []TableRef
-	Where  Expr
-	Order  []OrderByItem
-	Limit  *LimitClause
-	TokPos int32
+	With      *WithClause
+	Tables    []*QualifiedIdent
+	From      []TableRef
+	Where     Expr
+	Order     []OrderByItem
+	Limit     *LimitClause
+	Returning []SelectColumn
+	TokPos    int32
 }
 
 func (n *DeleteStmt) node()      {}
@@ -430,7 +698,17 @@ type CreateTableStmt struct {
 	Options     []TableOption
 	Select      *SelectStmt // CREATE TABLE ... AS SELECT
 	Like        *QualifiedIdent
-	TokPos      int32
+	// WithoutRowID is SQLite's WITHOUT ROWID, which clusters the table by
+	// its primary key.
+	WithoutRowID bool
+	// PostgreSQL storage clauses: INHERITS (parent, ...), USING method,
+	// WITH (storage_parameter = value, ...) and TABLESPACE name. MySQL's
+	// TABLESPACE option is read into Tablespace as well.
+	Inherits      []*QualifiedIdent
+	AccessMethod  *Ident
+	StorageParams []TableOption
+	Tablespace    *Ident
+	TokPos        int32
 }
 
 func (n *CreateTableStmt) node()      {}
@@ -470,7 +748,13 @@ type TableConstraint struct {
 	OnUpdate  RefAction
 	Check     Expr
 	IndexType []byte // BTREE, HASH
-	TokPos    int32
+	// Match is the MATCH type of a foreign key.
+	Match FKMatch
+	// Deferrable and InitiallyDeferred are [NOT] DEFERRABLE and INITIALLY
+	// DEFERRED | IMMEDIATE. INITIALLY DEFERRED implies DEFERRABLE.
+	Deferrable        bool
+	InitiallyDeferred bool
+	TokPos            int32
 }
 type ConstraintType uint8
 
@@ -494,12 +778,27 @@ const (
 	SetDefault
 )
 
+// FKMatch is the MATCH type of a foreign key, which decides how a
+// composite key with some NULL columns is checked.
+type FKMatch uint8
+
+const (
+	// MatchDefault is no MATCH clause, which behaves as MatchSimple.
+	MatchDefault FKMatch = iota
+	MatchSimple
+	MatchFull
+	MatchPartial
+)
+
 // ForeignKeyRef is a REFERENCES clause on a column.
 type ForeignKeyRef struct {
-	Table    *QualifiedIdent
-	Columns  []*Ident
-	OnDelete RefAction
-	OnUpdate RefAction
+	Table             *QualifiedIdent
+	Columns           []*Ident
+	Match             FKMatch
+	OnDelete          RefAction
+	OnUpdate          RefAction
+	Deferrable        bool
+	InitiallyDeferred bool
 }
 
 // IndexColDef is a column in an index definition.
@@ -507,14 +806,49 @@ type IndexColDef struct {
 	Name   *Ident
 	Length *int
 	Desc   bool
+	// Expr is the expression of a functional key part, such as lower(email)
+	// in PostgreSQL's (lower(email)) or MySQL's ((lower(email))); Name is
+	// nil then.
+	Expr Expr
+	// OpClass is a PostgreSQL operator class, e.g. jsonb_path_ops.
+	OpClass []byte
+	// NullsFirst is set by PostgreSQL's NULLS FIRST or NULLS LAST.
+	NullsFirst *bool
 }
 
-// TableOption is a table-level option, e.g. ENGINE=InnoDB.
+// TableOption is a table-level option, e.g. ENGINE=InnoDB. For options of
+// the CREATE TABLE grammar, Key is the canonical upper-case name (CHARSET
+// for DEFAULT CHARACTER SET) and Type says what Value holds. Value keeps
+// the source text, quotes included, except that OptionEnum values are
+// upper-cased.
 type TableOption struct {
 	Key   []byte
 	Value []byte
+	Type  TableOptionType
 }
 
+// TableOptionType is the kind of value a table option takes.
+type TableOptionType uint8
+
+const (
+	// OptionRaw is an option outside the grammar, kept as written.
+	OptionRaw TableOptionType = iota
+	// OptionName is an identifier or string naming something:
+	// ENGINE=InnoDB, CHARSET=utf8mb4. CHARSET and COLLATE also take DEFAULT.
+	OptionName
+	// OptionString is a string literal: COMMENT='...'.
+	OptionString
+	// OptionInt is an integer: AUTO_INCREMENT=100.
+	OptionInt
+	// OptionEnum is one of a fixed set of words or digits:
+	// ROW_FORMAT=DYNAMIC, CHECKSUM=1.
+	OptionEnum
+	// OptionList is a parenthesised table list: UNION=(t1, t2).
+	OptionList
+	// OptionFlag takes no value: SQLite's STRICT.
+	OptionFlag
+)
+
 // AlterTableStmt represents ALTER TABLE.
 type AlterTableStmt struct {
 	Table  *QualifiedIdent
@@ -551,17 +885,47 @@ func (c *DropColumnCmd) node()         {}
 func (c *DropColumnCmd) alterCmdNode() {}
 func (c *DropColumnCmd) Pos() int32    { return c.TokPos }
 
+// ModifyColumnCmd is MySQL's MODIFY COLUMN, or CHANGE COLUMN when
+// OldName is set, which also renames the column to Col.Name.
 type ModifyColumnCmd struct {
-	Col    *ColumnDef
-	First  bool
-	After  *Ident
-	TokPos int32
+	Col     *ColumnDef
+	OldName *Ident
+	First   bool
+	After   *Ident
+	TokPos  int32
 }
 
 func (c *ModifyColumnCmd) node()         {}
 func (c *ModifyColumnCmd) alterCmdNode() {}
 func (c *ModifyColumnCmd) Pos() int32    { return c.TokPos }
 
+// AlterColumnCmd is ALTER COLUMN name with one action: PostgreSQL's
+// [SET DATA] TYPE type [USING expr] and SET or DROP NOT NULL, and the SET
+// or DROP DEFAULT MySQL shares.
+type AlterColumnCmd struct {
+	Name    *Ident
+	Action  AlterColumnAction
+	Type    *DataType // AlterColumnType
+	Using   Expr      // AlterColumnType, or nil
+	Default Expr      // AlterColumnSetDefault
+	TokPos  int32
+}
+
+func (c *AlterColumnCmd) node()         {}
+func (c *AlterColumnCmd) alterCmdNode() {}
+func (c *AlterColumnCmd) Pos() int32    { return c.TokPos }
+
+// AlterColumnAction is what an AlterColumnCmd changes.
+type AlterColumnAction uint8
+
+const (
+	AlterColumnType AlterColumnAction = iota
+	AlterColumnSetNotNull
+	AlterColumnDropNotNull
+	AlterColumnSetDefault
+	AlterColumnDropDefault
+)
+
 type AddConstraintCmd struct {
 	Constraint *TableConstraint
 	TokPos     int32
@@ -591,12 +955,33 @@ func (c *RenameTableCmd) Pos() int32    { return c.TokPos }
 
 // CreateIndexStmt represents CREATE [UNIQUE|FULLTEXT|SPATIAL] INDEX.
 type CreateIndexStmt struct {
-	Name     *Ident
-	Table    *QualifiedIdent
-	Columns  []*IndexColDef
-	Type     ConstraintType
+	Name    *Ident
+	Table   *QualifiedIdent
+	Columns []*IndexColDef
+	Type    ConstraintType
+	// Deprecated: never set; see Method.
 	IndexAlg []byte
-	TokPos   int32
+	// Concurrently is PostgreSQL's CREATE INDEX CONCURRENTLY, which builds
+	// the index without blocking writes.
+	Concurrently bool
+	// Method is the index access method of USING, as written: gin or gist
+	// in PostgreSQL, BTREE or HASH in MySQL.
+	Method []byte
+	// Include lists the non-key columns of a covering index, from the
+	// INCLUDE (col, ...) of PostgreSQL and SQL Server.
+	Include []*Ident
+	// StorageParams are the WITH (name = value, ...) parameters, such as
+	// fillfactor.
+	StorageParams []TableOption
+	// Options are MySQL's index options, such as KEY_BLOCK_SIZE=8 and
+	// COMMENT '...', with the canonical upper-case Key.
+	Options []TableOption
+	// Invisible is MySQL's INVISIBLE: the index is maintained but the
+	// optimizer ignores it.
+	Invisible bool
+	// Where is the predicate of a partial index.
+	Where  Expr
+	TokPos int32
 }
 
 func (n *CreateIndexStmt) node()      {}
@@ -627,12 +1012,13 @@ func (n *DropIndexStmt) node()      {}
 func (n *DropIndexStmt) stmtNode()  {}
 func (n *DropIndexStmt) Pos() int32 { return n.TokPos }
 
-// CreateViewStmt represents CREATE VIEW.
+// CreateViewStmt represents CREATE [OR REPLACE] [TEMPORARY] VIEW.
 type CreateViewStmt struct {
 	Name      *QualifiedIdent
 	Columns   []*Ident
 	Select    *SelectStmt
 	OrReplace bool
+	Temporary bool
 	TokPos    int32
 }
 
@@ -640,6 +1026,105 @@ func (n *CreateViewStmt) node()      {}
 func (n *CreateViewStmt) stmtNode()  {}
 func (n *CreateViewStmt) Pos() int32 { return n.TokPos }
 
+// SequenceOptions are the clauses of CREATE SEQUENCE and ALTER SEQUENCE.
+// Numbers keep their source spelling, including a leading minus sign; a
+// nil number means the clause is absent.
+type SequenceOptions struct {
+	Type       *DataType // AS type
+	Increment  []byte
+	MinValue   []byte
+	MaxValue   []byte
+	NoMinValue bool
+	NoMaxValue bool
+	Start      []byte
+	Cache      []byte
+	Cycle      bool
+	NoCycle    bool
+	// Restart is ALTER SEQUENCE's RESTART, to RestartWith when it is set
+	// and to the start value otherwise.
+	Restart     bool
+	RestartWith []byte
+	OwnedBy     *QualifiedIdent // OWNED BY table.column
+	OwnedByNone bool
+}
+
+// CreateSequenceStmt represents CREATE [TEMPORARY] SEQUENCE.
+type CreateSequenceStmt struct {
+	Name        *QualifiedIdent
+	Temporary   bool
+	IfNotExists bool
+	Options     SequenceOptions
+	TokPos      int32
+}
+
+func (n *CreateSequenceStmt) node()      {}
+func (n *CreateSequenceStmt) stmtNode()  {}
+func (n *CreateSequenceStmt) Pos() int32 { return n.TokPos }
+
+// AlterSequenceStmt represents ALTER SEQUENCE.
+type AlterSequenceStmt struct {
+	Name     *QualifiedIdent
+	IfExists bool
+	Options  SequenceOptions
+	TokPos   int32
+}
+
+func (n *AlterSequenceStmt) node()      {}
+func (n *AlterSequenceStmt) stmtNode()  {}
+func (n *AlterSequenceStmt) Pos() int32 { return n.TokPos }
+
+// DropSequenceStmt represents DROP SEQUENCE.
+type DropSequenceStmt struct {
+	Names    []*QualifiedIdent
+	IfExists bool
+	Cascade  bool
+	TokPos   int32
+}
+
+func (n *DropSequenceStmt) node()      {}
+func (n *DropSequenceStmt) stmtNode()  {}
+func (n *DropSequenceStmt) Pos() int32 { return n.TokPos }
+
+// CreateTypeStmt represents PostgreSQL's CREATE TYPE name AS ENUM (...).
+// Other CREATE TYPE forms parse as GenericDDLStmt.
+type CreateTypeStmt struct {
+	Name     *QualifiedIdent
+	EnumVals [][]byte // quoted labels, as in DataType.EnumVals
+	TokPos   int32
+}
+
+func (n *CreateTypeStmt) node()      {}
+func (n *CreateTypeStmt) stmtNode()  {}
+func (n *CreateTypeStmt) Pos() int32 { return n.TokPos }
+
+// CreateDomainStmt represents CREATE DOMAIN. Checks refer to the value
+// being checked as VALUE.
+type CreateDomainStmt struct {
+	Name    *QualifiedIdent
+	Type    *DataType
+	Default Expr
+	NotNull bool
+	Checks  []Expr
+	TokPos  int32
+}
+
+func (n *CreateDomainStmt) node()      {}
+func (n *CreateDomainStmt) stmtNode()  {}
+func (n *CreateDomainStmt) Pos() int32 { return n.TokPos }
+
+// DropTypeStmt represents DROP TYPE and DROP DOMAIN.
+type DropTypeStmt struct {
+	Domain   bool
+	Names    []*QualifiedIdent
+	IfExists bool
+	Cascade  bool
+	TokPos   int32
+}
+
+func (n *DropTypeStmt) node()      {}
+func (n *DropTypeStmt) stmtNode()  {}
+func (n *DropTypeStmt) Pos() int32 { return n.TokPos }
+
 // CreateDatabaseStmt represents CREATE DATABASE / SCHEMA.
 type CreateDatabaseStmt struct {
 	Name        *Ident
@@ -674,10 +1159,15 @@ func (n *DropDatabaseStmt) node()      {}
 func (n *DropDatabaseStmt) stmtNode()  {}
 func (n *DropDatabaseStmt) Pos() int32 { return n.TokPos }
 
-// TruncateStmt represents TRUNCATE TABLE.
+// TruncateStmt represents TRUNCATE TABLE, with PostgreSQL's table list,
+// RESTART IDENTITY and CASCADE. CONTINUE IDENTITY and RESTRICT are the
+// defaults and are not recorded.
 type TruncateStmt struct {
-	Table  *QualifiedIdent
-	TokPos int32
+	Tables []*QualifiedIdent
+	// RestartIdentity resets the sequences owned by the tables' columns.
+	RestartIdentity bool
+	Cascade         bool
+	TokPos          int32
 }
 
 func (n *TruncateStmt) node()      {}
@@ -694,22 +1184,88 @@ func (n *UseStmt) node()      {}
 func (n *UseStmt) stmtNode()  {}
 func (n *UseStmt) Pos() int32 { return n.TokPos }
 
-// ShowStmt represents SHOW TABLES / SHOW DATABASES / etc.
-type ShowStmt struct {
-	What   []byte
-	Like   *Literal
-	Where  Expr
+// PragmaStmt represents a SQLite PRAGMA. Value is nil when the pragma is
+// queried rather than set; Call records the PRAGMA name(value) spelling.
+type PragmaStmt struct {
+	Name   *QualifiedIdent
+	Value  Expr
+	Call   bool
+	TokPos int32
+}
+
+func (n *PragmaStmt) node()      {}
+func (n *PragmaStmt) stmtNode()  {}
+func (n *PragmaStmt) Pos() int32 { return n.TokPos }
+
+// AttachStmt represents SQLite ATTACH DATABASE file AS schema, or with
+// Detach set DETACH DATABASE schema, where File is nil.
+type AttachStmt struct {
+	Detach bool
+	File   Expr
+	Schema *Ident
 	TokPos int32
 }
 
+func (n *AttachStmt) node()      {}
+func (n *AttachStmt) stmtNode()  {}
+func (n *AttachStmt) Pos() int32 { return n.TokPos }
+
+// ShowStmt represents MySQL's SHOW TABLES / SHOW COLUMNS / SHOW CREATE
+// TABLE / etc. and PostgreSQL's SHOW setting.
+type ShowStmt struct {
+	// What is the word after SHOW [GLOBAL | SESSION] [FULL] as written:
+	// TABLES, COLUMNS, INDEX, CREATE, VARIABLES, ... or a setting name.
+	What []byte
+	// Scope is the GLOBAL or SESSION of SHOW GLOBAL VARIABLES, as written.
+	Scope []byte
+	Full  bool
+	// Detail is the word completing a two-word What, as written: STATUS of
+	// SHOW TABLE STATUS and SHOW ENGINE InnoDB STATUS, LOGS of SHOW BINARY
+	// LOGS, SET of SHOW CHARACTER SET.
+	Detail []byte
+	// Engine is the storage engine of SHOW ENGINE name STATUS | MUTEX, as
+	// written.
+	Engine []byte
+	// User is the account of SHOW GRANTS FOR user, as written, such as
+	// 'app'@'%' or CURRENT_USER.
+	User []byte
+	// Object is the object type of SHOW CREATE: TABLE, VIEW, ...
+	Object []byte
+	// Name is the object of SHOW CREATE, or the table of SHOW COLUMNS and
+	// SHOW INDEX.
+	Name *QualifiedIdent
+	// Database is the FROM | IN database of SHOW TABLES, COLUMNS and INDEX.
+	Database *Ident
+	Like     *Literal
+	Where    Expr
+	TokPos   int32
+}
+
 func (n *ShowStmt) node()      {}
 func (n *ShowStmt) stmtNode()  {}
 func (n *ShowStmt) Pos() int32 { return n.TokPos }
 
-// ExplainStmt represents EXPLAIN / DESCRIBE.
+// ExplainStmt represents EXPLAIN / DESCRIBE of a statement, or MySQL's
+// DESCRIBE of a table.
 type ExplainStmt struct {
-	Stmt   Statement
-	TokPos int32
+	// Stmt is the statement explained; it is nil when Table is set.
+	Stmt Statement
+	// Table and Column are set by DESCRIBE table [column], also written
+	// EXPLAIN table.
+	Table  *QualifiedIdent
+	Column *Ident
+	// Analyze runs the statement: EXPLAIN ANALYZE, or ANALYZE in the
+	// PostgreSQL option list.
+	Analyze bool
+	// QueryPlan is SQLite's EXPLAIN QUERY PLAN.
+	QueryPlan bool
+	// Format is the output format as written, from MySQL's FORMAT=name or
+	// PostgreSQL's FORMAT option.
+	Format []byte
+	// Options are the other PostgreSQL options, such as BUFFERS or
+	// COSTS OFF, including the unparenthesized VERBOSE.
+	Options []TableOption
+	TokPos  int32
 }
 
 func (n *ExplainStmt) node()      {}
@@ -744,9 +1300,51 @@ type GenericDDLStmt struct {
 	Verb   []byte
 	Object []byte
 	Name   *Ident
+	// Body is the rest of the statement after Name, or after Object when
+	// Name is nil, as written: the parameters and body of CREATE
+	// PROCEDURE, the timing and body of CREATE TRIGGER, and so on.
+	Body   []byte
 	TokPos int32
 }
 
 func (n *GenericDDLStmt) node()      {}
 func (n *GenericDDLStmt) stmtNode()  {}
 func (n *GenericDDLStmt) Pos() int32 { return n.TokPos }
+
+// MaintenanceKind identifies the vendor statement a MaintenanceStmt models.
+type MaintenanceKind uint8
+
+const (
+	CreateExtension MaintenanceKind = iota // PostgreSQL CREATE EXTENSION
+	Vacuum                                 // PostgreSQL / SQLite VACUUM
+	Analyze                                // PostgreSQL / SQLite ANALYZE
+	Reindex                                // PostgreSQL / SQLite REINDEX
+	Cluster                                // PostgreSQL CLUSTER
+	OptimizeTable                          // MySQL OPTIMIZE TABLE
+	AnalyzeTable                           // MySQL ANALYZE TABLE
+	CheckTable                             // MySQL CHECK TABLE
+)
+
+// MaintenanceTarget is an object named by a maintenance statement, with the
+// optional column list PostgreSQL allows for VACUUM and ANALYZE.
+type MaintenanceTarget struct {
+	Name    *QualifiedIdent
+	Columns []*Ident
+}
+
+// MaintenanceStmt represents an operational statement such as CREATE
+// EXTENSION, VACUUM, ANALYZE, REINDEX, CLUSTER or MySQL OPTIMIZE/ANALYZE/CHECK
+// TABLE. Options keep their source spelling; flags such as FULL or
+// CONCURRENTLY have a nil Value.
+type MaintenanceStmt struct {
+	Kind        MaintenanceKind
+	Object      []byte // REINDEX object class: INDEX, TABLE, SCHEMA, DATABASE, SYSTEM
+	IfNotExists bool
+	Targets     []MaintenanceTarget
+	Options     []TableOption
+	TokPos      int32
+}
+
+func (n *MaintenanceStmt) node()      {}
+func (n *MaintenanceStmt) stmtNode()  {}
+func (n *MaintenanceStmt) Pos() int32 { return n.TokPos }
@@ -0,0 +1,76 @@
+package ast
+
+import (
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// Clone returns a deep copy of n on the garbage-collected heap. Parsed
+// nodes live in their parser's arena and their identifiers and literals
+// alias its input; the copy shares no memory with either, so it stays
+// valid after the parser is reset or released and the input is reused,
+// as caching a parsed statement across requests needs. A node the tree
+// refers to from two places is copied once and shared the same way.
+func Clone[N Node](n N) N {
+	c := &cloner{seen: map[unsafe.Pointer]reflect.Value{}}
+	return c.value(reflect.ValueOf(&n).Elem()).Interface().(N)
+}
+
+type cloner struct {
+	// seen maps the nodes already copied to their copies.
+	seen map[unsafe.Pointer]reflect.Value
+}
+
+func (c *cloner) value(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		if cp, ok := c.seen[v.UnsafePointer()]; ok {
+			return cp
+		}
+		cp := reflect.New(v.Type().Elem())
+		c.seen[v.UnsafePointer()] = cp
+		cp.Elem().Set(c.value(v.Elem()))
+		return cp
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(c.value(v.Elem()))
+		return cp
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			reflect.Copy(cp, v)
+			return cp
+		}
+		for i := range v.Len() {
+			cp.Index(i).Set(c.value(v.Index(i)))
+		}
+		return cp
+	case reflect.Array:
+		cp := reflect.New(v.Type()).Elem()
+		for i := range v.Len() {
+			cp.Index(i).Set(c.value(v.Index(i)))
+		}
+		return cp
+	case reflect.Struct:
+		cp := reflect.New(v.Type()).Elem()
+		for i := range v.NumField() {
+			cp.Field(i).Set(c.value(v.Field(i)))
+		}
+		return cp
+	case reflect.String:
+		cp := reflect.New(v.Type()).Elem()
+		cp.SetString(strings.Clone(v.String()))
+		return cp
+	}
+	return v
+}
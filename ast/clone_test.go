@@ -0,0 +1,63 @@
+package ast_test
+
+import (
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+func TestClone(t *testing.T) {
+	tests := []string{
+		"SELECT u.id, COUNT(*) AS n FROM users u JOIN orders o ON o.user_id = u.id WHERE u.name LIKE 'a%' GROUP BY u.id HAVING COUNT(*) > 1 ORDER BY n DESC LIMIT 10",
+		"WITH recent AS (SELECT id FROM orders WHERE created_at > ?) SELECT * FROM recent UNION ALL SELECT id FROM archive",
+		"SELECT CASE WHEN a IN (1, 2) THEN 'x' ELSE CAST(b AS TEXT) END FROM t WHERE EXISTS (SELECT 1 FROM s WHERE s.id = t.id)",
+		"INSERT INTO t (id, name) VALUES ($1, 'a'), (2, :name) ON CONFLICT (id) DO UPDATE SET name = excluded.name",
+		"UPDATE t SET a = a + 1 WHERE id BETWEEN 1 AND 10",
+		"DELETE FROM t WHERE id = @id",
+		"CREATE TABLE t (id INT PRIMARY KEY, tags TEXT[], amount DECIMAL(10, 2) NOT NULL DEFAULT 0, g GEOMETRY(Point, 4326), KEY idx (id))",
+		"CREATE UNIQUE INDEX idx_name ON t (name)",
+	}
+	for _, sql := range tests {
+		src := []byte(sql)
+		p := sqlparser.AcquireParser()
+		p.Reset(src)
+		stmt, err := p.Next()
+		if err != nil {
+			t.Fatalf("parse error: %v\nSQL: %s", err, sql)
+		}
+		clone := ast.Clone(stmt)
+		if !ast.Equal(clone, stmt, ast.EqualOptions{}) {
+			t.Errorf("Clone(%q) differs: %v", sql, ast.Diff(clone, stmt, ast.EqualOptions{}))
+		}
+
+		// The clone must survive the parser and its input being reused.
+		for i := range src {
+			src[i] = ' '
+		}
+		p.Reset([]byte("SELECT zzz FROM zzz WHERE zzz = 'zzz'"))
+		if _, err := p.Next(); err != nil {
+			t.Fatal(err)
+		}
+		sqlparser.ReleaseParser(p)
+		if want := parse(t, sql); !ast.Equal(clone, want, ast.EqualOptions{}) {
+			t.Errorf("Clone(%q) changed with the parser: %v", sql, ast.Diff(clone, want, ast.EqualOptions{}))
+		}
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	const sql = "SELECT name FROM users WHERE id = 1"
+	orig := parse(t, sql)
+	clone := ast.Clone(orig).(*ast.SelectStmt)
+	clone.Where.(*ast.BinaryExpr).Right.(*ast.Literal).Raw[0] = '2'
+	clone.Columns[0].Expr.(*ast.Ident).Unquoted = "email"
+	if want := parse(t, sql); !ast.Equal(orig, want, ast.EqualOptions{}) {
+		t.Errorf("changing the clone changed the original: %v", ast.Diff(orig, want, ast.EqualOptions{}))
+	}
+
+	var nilStmt *ast.SelectStmt
+	if got := ast.Clone(nilStmt); got != nil {
+		t.Errorf("Clone(nil) = %v, want nil", got)
+	}
+}
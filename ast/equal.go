@@ -0,0 +1,275 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// EqualOptions tunes Equal and Diff.
+type EqualOptions struct {
+	// IgnoreLiterals treats every literal and parameter as equal to any
+	// other, so statements that differ only in their values, or in
+	// inlining a value instead of binding it, compare equal.
+	IgnoreLiterals bool
+	// IgnoreIdentCase compares quoted identifiers case-insensitively too;
+	// unquoted ones are always folded to lower case by the parser.
+	IgnoreIdentCase bool
+}
+
+// Difference is one place where two nodes differ. Path leads to it from
+// the compared roots through field names and slice indexes, e.g.
+// Columns[1].Expr.Args[0]; A and B describe the values there, and APos
+// and BPos are the source positions of the innermost nodes that hold
+// them, or -1.
+type Difference struct {
+	Path       string
+	A, B       string
+	APos, BPos int32
+}
+
+func (d Difference) String() string {
+	path := d.Path
+	if path == "" {
+		path = "statement"
+	}
+	return fmt.Sprintf("%s: %s != %s", path, d.A, d.B)
+}
+
+// Equal reports whether a and b are the same statement, ignoring
+// formatting, keyword case, identifier quoting, placeholder style and
+// source positions.
+func Equal(a, b Statement, opts EqualOptions) bool {
+	c := &comparer{opts: opts, first: true}
+	c.value("", reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem(), -1, -1)
+	return len(c.diffs) == 0
+}
+
+// Diff lists the places where a and b differ in the terms of Equal, in
+// field order. Where two nodes are of different kinds, or two lists of
+// different lengths, it reports the whole node or list once rather than
+// descending into it.
+func Diff(a, b Node, opts EqualOptions) []Difference {
+	c := &comparer{opts: opts}
+	c.value("", reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem(), -1, -1)
+	return c.diffs
+}
+
+type comparer struct {
+	opts EqualOptions
+	// first stops at the first difference.
+	first bool
+	diffs []Difference
+}
+
+var (
+	nodeType  = reflect.TypeFor[Node]()
+	identType = reflect.TypeFor[*Ident]()
+	litType   = reflect.TypeFor[*Literal]()
+	paramType = reflect.TypeFor[*Param]()
+)
+
+func (c *comparer) differ(path string, a, b reflect.Value, apos, bpos int32) {
+	c.diffs = append(c.diffs, Difference{Path: path, A: describe(a), B: describe(b), APos: apos, BPos: bpos})
+}
+
+func (c *comparer) value(path string, a, b reflect.Value, apos, bpos int32) {
+	if c.first && len(c.diffs) > 0 {
+		return
+	}
+	if a.Kind() == reflect.Interface {
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				c.differ(path, a, b, apos, bpos)
+			}
+			return
+		}
+		a, b = a.Elem(), b.Elem()
+	}
+	if a.Type() != b.Type() {
+		if !c.opts.IgnoreLiterals || !isValue(a.Type()) || !isValue(b.Type()) {
+			c.differ(path, a, b, apos, bpos)
+		}
+		return
+	}
+	switch a.Kind() {
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				c.differ(path, a, b, apos, bpos)
+			}
+			return
+		}
+		if a.Type().Implements(nodeType) {
+			apos, bpos = a.Interface().(Node).Pos(), b.Interface().(Node).Pos()
+		}
+		switch a.Type() {
+		case identType:
+			x, y := a.Interface().(*Ident).Unquoted, b.Interface().(*Ident).Unquoted
+			if x != y && !(c.opts.IgnoreIdentCase && strings.EqualFold(x, y)) {
+				c.differ(path, a, b, apos, bpos)
+			}
+			return
+		case litType:
+			x, y := a.Interface().(*Literal), b.Interface().(*Literal)
+			if !c.opts.IgnoreLiterals && (x.Kind != y.Kind || !bytes.Equal(literalValue(x), literalValue(y))) {
+				c.differ(path, a, b, apos, bpos)
+			}
+			return
+		case paramType:
+			if !c.opts.IgnoreLiterals && !equalParams(a.Interface().(*Param), b.Interface().(*Param)) {
+				c.differ(path, a, b, apos, bpos)
+			}
+			return
+		}
+		c.value(path, a.Elem(), b.Elem(), apos, bpos)
+	case reflect.Struct:
+		t := a.Type()
+		for i := range t.NumField() {
+			f := t.Field(i)
+			if !f.IsExported() || f.Name == "TokPos" {
+				continue
+			}
+			c.value(joinPath(path, f.Name), a.Field(i), b.Field(i), apos, bpos)
+		}
+	case reflect.Slice:
+		if a.Type().Elem().Kind() == reflect.Uint8 {
+			if !equalText(a.Bytes(), b.Bytes()) {
+				c.differ(path, a, b, apos, bpos)
+			}
+			return
+		}
+		if a.Len() != b.Len() {
+			c.differ(path, a, b, apos, bpos)
+			return
+		}
+		for i := range a.Len() {
+			c.value(path+"["+strconv.Itoa(i)+"]", a.Index(i), b.Index(i), apos, bpos)
+		}
+	default:
+		if !a.Equal(b) {
+			c.differ(path, a, b, apos, bpos)
+		}
+	}
+}
+
+// isValue reports whether t is a literal or a parameter, which
+// IgnoreLiterals lets stand for one another.
+func isValue(t reflect.Type) bool {
+	return t == litType || t == paramType
+}
+
+// equalParams compares parameters independently of their style: :name
+// and @name by name, and $N by number. A ? is numbered by its position,
+// which shifts with the rest of the statement, so it matches any
+// positional parameter.
+func equalParams(a, b *Param) bool {
+	x, y := string(a.Raw[1:]), string(b.Raw[1:])
+	if a.Raw[0] == '?' || b.Raw[0] == '?' {
+		return positional(a.Raw) && positional(b.Raw)
+	}
+	return x == y && (a.Raw[0] == '$') == (b.Raw[0] == '$')
+}
+
+func positional(raw []byte) bool {
+	if raw[0] == '?' {
+		return true
+	}
+	if raw[0] != '$' || len(raw) == 1 {
+		return false
+	}
+	_, err := strconv.Atoi(string(raw[1:]))
+	return err == nil
+}
+
+// literalValue returns the contents of a string literal, whatever quote
+// it was written with, or the text of another literal.
+func literalValue(l *Literal) []byte {
+	raw := l.Raw
+	if l.Kind != lexer.STRING || len(raw) < 2 {
+		return raw
+	}
+	q := raw[0]
+	inner := raw[1 : len(raw)-1]
+	if !bytes.Contains(inner, []byte{q, q}) && !bytes.ContainsRune(inner, '\\') {
+		return inner
+	}
+	out := make([]byte, 0, len(inner))
+	for i := 0; i < len(inner); i++ {
+		if (inner[i] == q || inner[i] == '\\') && i+1 < len(inner) {
+			i++
+		}
+		out = append(out, inner[i])
+	}
+	return out
+}
+
+// equalText compares raw source text such as a type name, a window frame
+// or an option value: quoted text exactly, the rest ignoring case and
+// runs of whitespace.
+func equalText(a, b []byte) bool {
+	if len(a) > 0 && a[0] == '\'' || len(b) > 0 && b[0] == '\'' {
+		return bytes.Equal(a, b)
+	}
+	return strings.EqualFold(strings.Join(strings.Fields(string(a)), " "), strings.Join(strings.Fields(string(b)), " "))
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// describe renders a compared value for a Difference: identifiers,
+// literals and parameters as written, other nodes by type and lists by
+// length.
+func describe(v reflect.Value) string {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "none"
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return "none"
+		}
+		switch n := v.Interface().(type) {
+		case *Ident:
+			if len(n.Raw) == 0 {
+				return n.Unquoted
+			}
+			return string(n.Raw)
+		case *QualifiedIdent:
+			parts := make([]string, len(n.Parts))
+			for i, p := range n.Parts {
+				parts[i] = string(p.Raw)
+			}
+			return strings.Join(parts, ".")
+		case *Literal:
+			return string(n.Raw)
+		case *Param:
+			return string(n.Raw)
+		case *NullLit:
+			return "NULL"
+		}
+		return v.Elem().Type().Name()
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return strconv.Quote(string(v.Bytes()))
+		}
+		if v.Len() == 1 {
+			return "1 item"
+		}
+		return strconv.Itoa(v.Len()) + " items"
+	case reflect.Struct:
+		return v.Type().Name()
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,71 @@
+package ast_test
+
+import (
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+func parse(t *testing.T, sql string) ast.Statement {
+	t.Helper()
+	stmt, err := sqlparser.ParseStatement(sql)
+	if err != nil {
+		t.Fatalf("parse error: %v\nSQL: %s", err, sql)
+	}
+	return stmt
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		opts ast.EqualOptions
+		want bool
+	}{
+		{"SELECT id, name FROM users WHERE id = ?", "select  ID,\n\t\"name\" from `users` where (id = $1)", ast.EqualOptions{}, true},
+		{"SELECT id FROM users WHERE a <> 1", "SELECT id FROM users WHERE a != 1", ast.EqualOptions{}, true},
+		{"SELECT 'it''s'", `SELECT 'it\'s'`, ast.EqualOptions{}, true},
+		{"SELECT id FROM users WHERE name = :name", "SELECT id FROM users WHERE name = @name", ast.EqualOptions{}, true},
+		{"SELECT id FROM users WHERE id = $1", "SELECT id FROM users WHERE id = $2", ast.EqualOptions{}, false},
+		{"SELECT id FROM users WHERE id = 1", "SELECT id FROM users WHERE id = 2", ast.EqualOptions{}, false},
+		{"SELECT id FROM users WHERE id = 1", "SELECT id FROM users WHERE id = ?", ast.EqualOptions{IgnoreLiterals: true}, true},
+		{`SELECT "Id" FROM users`, "SELECT id FROM users", ast.EqualOptions{}, false},
+		{`SELECT "Id" FROM users`, "SELECT id FROM users", ast.EqualOptions{IgnoreIdentCase: true}, true},
+		{"SELECT id FROM users ORDER BY id", "SELECT id FROM users ORDER BY id DESC", ast.EqualOptions{}, false},
+		{"CREATE TABLE t (id int NOT NULL, d decimal(10, 2))", "create table T (ID INT not null, D DECIMAL(10,2))", ast.EqualOptions{}, true},
+	}
+	for _, tt := range tests {
+		if got := ast.Equal(parse(t, tt.a), parse(t, tt.b), tt.opts); got != tt.want {
+			t.Errorf("Equal(%q, %q, %+v) = %v, want %v", tt.a, tt.b, tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := parse(t, "SELECT id, name FROM users u JOIN orders o ON o.uid = u.id WHERE u.active = 1")
+	b := parse(t, "SELECT id, email FROM users u LEFT JOIN orders o ON o.uid = u.id WHERE u.active = 1 LIMIT 10")
+	diffs := ast.Diff(a, b, ast.EqualOptions{})
+	want := []string{
+		"Columns[1].Expr: name != email",
+		"From[0].Kind: 0 != 1",
+		"Limit: none != LimitClause",
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("got %v, want %v", diffs, want)
+	}
+	for i, d := range diffs {
+		if d.String() != want[i] {
+			t.Errorf("diff %d = %q, want %q", i, d, want[i])
+		}
+	}
+	if diffs[0].APos != 11 || diffs[0].BPos != 11 {
+		t.Errorf("positions: %d, %d", diffs[0].APos, diffs[0].BPos)
+	}
+
+	if d := ast.Diff(a, parse(t, "DELETE FROM users"), ast.EqualOptions{}); len(d) != 1 || d[0].String() != "statement: SelectStmt != DeleteStmt" {
+		t.Errorf("different statements: %v", d)
+	}
+	if d := ast.Diff(parse(t, "SELECT a, b"), parse(t, "SELECT a"), ast.EqualOptions{}); len(d) != 1 || d[0].String() != "Columns: 2 items != 1 item" {
+		t.Errorf("different lengths: %v", d)
+	}
+}
@@ -0,0 +1,304 @@
+// Package astbuilder builds SQL statements as ASTs from Go code, for the
+// queries a program puts together rather than parses:
+//
+//	stmt := astbuilder.Select(astbuilder.Col("id"), astbuilder.Col("name")).
+//		From("users").
+//		Where(astbuilder.Eq(astbuilder.Col("id"), astbuilder.Param("id"))).
+//		Build()
+//
+// The statements are the same ast nodes the parser produces, so they
+// render for any dialect through sqlparser.ConvertStatements, which quotes
+// identifiers and string values as the target requires, and every other
+// part of sqlparser, such as Analyze or Normalize, takes them too.
+//
+// Names given as strings are split at dots into their qualifiers, so
+// "app.users" is table users of schema app and "u.id" is column id of
+// table u. Ident makes a single name that may itself contain a dot.
+// Names are taken exactly, as quoted ones are, where the parser folds
+// unquoted names to lower case.
+// Values are never spliced into SQL text: String, Int and the like make
+// literals and Param a placeholder. The names of functions, types and
+// parameters are written into SQL as they are, so they must be plain
+// words; the functions that take them panic otherwise.
+package astbuilder
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// ---- Names ----
+
+// Ident returns the identifier name, taken whole.
+func Ident(name string) *ast.Ident {
+	return &ast.Ident{Unquoted: name}
+}
+
+// Name returns the dotted name, such as schema.table, split at its dots.
+func Name(name string) *ast.QualifiedIdent {
+	parts := strings.Split(name, ".")
+	q := &ast.QualifiedIdent{Parts: make([]*ast.Ident, len(parts))}
+	for i, part := range parts {
+		q.Parts[i] = Ident(part)
+	}
+	return q
+}
+
+// Col returns a reference to a column, qualified by its table when name
+// has a dot, as in "u.id".
+func Col(name string) ast.Expr {
+	if strings.IndexByte(name, '.') < 0 {
+		return Ident(name)
+	}
+	return Name(name)
+}
+
+// Star returns table.*, every column of one table; Select with no columns
+// selects a bare *.
+func Star(table string) ast.Expr {
+	return &ast.TableStar{Table: Name(table)}
+}
+
+// Table returns the table name, with alias unless it is empty, for use as
+// a FROM item or join operand.
+func Table(name, alias string) *ast.SimpleTable {
+	t := &ast.SimpleTable{Name: Name(name)}
+	if alias != "" {
+		t.Alias = Ident(alias)
+	}
+	return t
+}
+
+// ---- Values ----
+
+// Param returns the placeholder :name, or ? when name is empty. Rendering
+// writes each dialect's own placeholders, ? or $1, $2 and so on in order.
+func Param(name string) *ast.Param {
+	if name == "" {
+		return &ast.Param{Raw: []byte("?")}
+	}
+	mustBeWord("parameter", name)
+	return &ast.Param{Raw: []byte(":" + name)}
+}
+
+// String returns the string literal holding s. A string with a backslash
+// is an E'...' literal, which every dialect reads the same way once
+// rendered for it.
+func String(s string) *ast.Literal {
+	var b strings.Builder
+	b.Grow(len(s) + 3)
+	if strings.IndexByte(s, '\\') >= 0 {
+		b.WriteByte('E')
+	}
+	b.WriteByte('\'')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\'':
+			b.WriteString("''")
+		case '\\':
+			b.WriteString(`\\`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('\'')
+	return &ast.Literal{Raw: []byte(b.String()), Kind: lexer.STRING}
+}
+
+// Int returns the integer literal n; a negative n is negated, as the
+// parser reads -1.
+func Int(n int64) ast.Expr {
+	if n < 0 && n != math.MinInt64 {
+		return &ast.UnaryExpr{Expr: Int(-n), Op: lexer.MINUS}
+	}
+	return &ast.Literal{Raw: strconv.AppendInt(nil, n, 10), Kind: lexer.INT}
+}
+
+// Float returns the numeric literal f, negated like Int when negative. It
+// panics when f is NaN or infinite, which no SQL literal holds.
+func Float(f float64) ast.Expr {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		panic("astbuilder: Float of " + strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	if f < 0 {
+		return &ast.UnaryExpr{Expr: Float(-f), Op: lexer.MINUS}
+	}
+	raw := strconv.AppendFloat(nil, f, 'g', -1, 64)
+	if strings.IndexAny(string(raw), ".e") < 0 {
+		raw = append(raw, ".0"...)
+	}
+	return &ast.Literal{Raw: raw, Kind: lexer.FLOAT}
+}
+
+// Bool returns TRUE or FALSE.
+func Bool(v bool) *ast.Literal {
+	if v {
+		return &ast.Literal{Raw: []byte("TRUE"), Kind: lexer.TRUE_KW}
+	}
+	return &ast.Literal{Raw: []byte("FALSE"), Kind: lexer.FALSE_KW}
+}
+
+// Null returns NULL.
+func Null() ast.Expr {
+	return &ast.NullLit{}
+}
+
+// ---- Expressions ----
+
+func binary(op lexer.TokenType, left, right ast.Expr) ast.Expr {
+	return &ast.BinaryExpr{Left: left, Right: right, Op: op}
+}
+
+// Eq returns left = right.
+func Eq(left, right ast.Expr) ast.Expr { return binary(lexer.EQ, left, right) }
+
+// Ne returns left <> right.
+func Ne(left, right ast.Expr) ast.Expr { return binary(lexer.NEQ, left, right) }
+
+// Lt returns left < right.
+func Lt(left, right ast.Expr) ast.Expr { return binary(lexer.LT, left, right) }
+
+// Le returns left <= right.
+func Le(left, right ast.Expr) ast.Expr { return binary(lexer.LTE, left, right) }
+
+// Gt returns left > right.
+func Gt(left, right ast.Expr) ast.Expr { return binary(lexer.GT, left, right) }
+
+// Ge returns left >= right.
+func Ge(left, right ast.Expr) ast.Expr { return binary(lexer.GTE, left, right) }
+
+// Add returns left + right.
+func Add(left, right ast.Expr) ast.Expr { return binary(lexer.PLUS, left, right) }
+
+// Sub returns left - right.
+func Sub(left, right ast.Expr) ast.Expr { return binary(lexer.MINUS, left, right) }
+
+// Mul returns left * right.
+func Mul(left, right ast.Expr) ast.Expr { return binary(lexer.STAR, left, right) }
+
+// Div returns left / right.
+func Div(left, right ast.Expr) ast.Expr { return binary(lexer.SLASH, left, right) }
+
+// And returns the conjunction of conds, grouped from the left as the
+// parser groups a AND b AND c. Nil conditions are skipped, so optional
+// filters can be passed as they are; And of none is nil.
+func And(conds ...ast.Expr) ast.Expr { return chain(lexer.AND, conds) }
+
+// Or returns the disjunction of conds, skipping nil ones like And.
+func Or(conds ...ast.Expr) ast.Expr { return chain(lexer.OR, conds) }
+
+func chain(op lexer.TokenType, conds []ast.Expr) ast.Expr {
+	var out ast.Expr
+	for _, c := range conds {
+		switch {
+		case c == nil:
+		case out == nil:
+			out = c
+		default:
+			out = binary(op, out, c)
+		}
+	}
+	return out
+}
+
+// Not returns NOT e.
+func Not(e ast.Expr) ast.Expr {
+	return &ast.UnaryExpr{Expr: e, Op: lexer.NOT}
+}
+
+// IsNull returns e IS NULL.
+func IsNull(e ast.Expr) ast.Expr {
+	return &ast.IsNullExpr{Expr: e}
+}
+
+// IsNotNull returns e IS NOT NULL.
+func IsNotNull(e ast.Expr) ast.Expr {
+	return &ast.IsNullExpr{Expr: e, Not: true}
+}
+
+// Like returns e LIKE pattern.
+func Like(e, pattern ast.Expr) ast.Expr {
+	return &ast.LikeExpr{Expr: e, Pattern: pattern}
+}
+
+// Between returns e BETWEEN lo AND hi.
+func Between(e, lo, hi ast.Expr) ast.Expr {
+	return &ast.BetweenExpr{Expr: e, Lo: lo, Hi: hi}
+}
+
+// In returns e IN (list...).
+func In(e ast.Expr, list ...ast.Expr) ast.Expr {
+	return &ast.InExpr{Expr: e, List: list}
+}
+
+// NotIn returns e NOT IN (list...).
+func NotIn(e ast.Expr, list ...ast.Expr) ast.Expr {
+	return &ast.InExpr{Expr: e, List: list, Not: true}
+}
+
+// InSelect returns e IN (subq).
+func InSelect(e ast.Expr, subq *ast.SelectStmt) ast.Expr {
+	return &ast.InExpr{Expr: e, Subq: subq}
+}
+
+// Exists returns EXISTS (subq).
+func Exists(subq *ast.SelectStmt) ast.Expr {
+	return &ast.ExistsExpr{Subq: subq}
+}
+
+// Subquery returns (subq) as a scalar value.
+func Subquery(subq *ast.SelectStmt) ast.Expr {
+	return &ast.SubqueryExpr{Subq: subq}
+}
+
+// Func returns a call of the function name, which may be qualified by its
+// schema. Each part of name must be a plain word.
+func Func(name string, args ...ast.Expr) *ast.FuncCall {
+	for _, part := range strings.Split(name, ".") {
+		mustBeWord("function name", part)
+	}
+	return &ast.FuncCall{Name: Name(name), Args: args}
+}
+
+// CountAll returns COUNT(*).
+func CountAll() *ast.FuncCall {
+	return &ast.FuncCall{Name: Name("count"), Star: true}
+}
+
+// Cast returns CAST(e AS typ), for a type name such as "BIGINT" or
+// "DOUBLE PRECISION" without arguments: plain words separated by single
+// spaces.
+func Cast(e ast.Expr, typ string) ast.Expr {
+	for _, word := range strings.Split(typ, " ") {
+		mustBeWord("type name", word)
+	}
+	return &ast.CastExpr{Expr: e, Type: &ast.DataType{Name: []byte(typ)}}
+}
+
+// Asc returns an ascending ORDER BY key.
+func Asc(e ast.Expr) ast.OrderByItem {
+	return ast.OrderByItem{Expr: e}
+}
+
+// Desc returns a descending ORDER BY key.
+func Desc(e ast.Expr) ast.OrderByItem {
+	return ast.OrderByItem{Expr: e, Desc: true}
+}
+
+// mustBeWord panics unless name, written into SQL as it is, is a plain
+// word: a letter or underscore followed by letters, digits and
+// underscores.
+func mustBeWord(what, name string) {
+	ok := name != "" && !(name[0] >= '0' && name[0] <= '9')
+	for i := 0; ok && i < len(name); i++ {
+		c := name[i]
+		ok = c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+	}
+	if !ok {
+		panic("astbuilder: " + what + " " + strconv.Quote(name) + " is not a plain word")
+	}
+}
@@ -0,0 +1,141 @@
+package astbuilder_test
+
+import (
+	"math"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+	"github.com/oarkflow/sqlparser/ast"
+	. "github.com/oarkflow/sqlparser/astbuilder"
+)
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		stmt ast.Statement
+		want string // the same statement as SQL
+	}{
+		{Select().From("users").Where(Eq(Col("id"), Param("id"))).Build(), "SELECT * FROM users WHERE id = :id"},
+		{
+			Select(Col("u.id"), Col("u.name"), CountAll()).
+				From("app.users").As("u").
+				LeftJoin("orders", Eq(Col("o.user_id"), Col("u.id"))).As("o").
+				Where(And(IsNotNull(Col("u.email")), Or(Like(Col("u.name"), String("a%")), In(Col("u.id"), Int(1), Int(-2))))).
+				Where(Between(Col("o.total"), Float(0.5), Float(100))).
+				GroupBy(Col("u.id"), Col("u.name")).
+				Having(Gt(CountAll(), Int(1))).
+				OrderBy(Desc(Col("u.name")), Asc(Col("u.id"))).
+				Limit(10).Offset(20).
+				Build(),
+			"SELECT u.id, u.name, COUNT(*) FROM app.users u LEFT JOIN orders o ON o.user_id = u.id " +
+				"WHERE u.email IS NOT NULL AND (u.name LIKE 'a%' OR u.id IN (1, -2)) AND o.total BETWEEN 0.5 AND 100.0 " +
+				"GROUP BY u.id, u.name HAVING COUNT(*) > 1 ORDER BY u.name DESC, u.id LIMIT 10 OFFSET 20",
+		},
+		{
+			Select(Star("t")).Distinct().ColumnAs(Func("lower", Col("name")), "n").
+				From("t").Where(And(Not(Exists(Select(Int(1)).From("s").Where(Eq(Col("s.t_id"), Col("t.id"))).Build())), nil)).
+				Build(),
+			"SELECT DISTINCT t.*, lower(name) AS n FROM t WHERE NOT EXISTS (SELECT 1 FROM s WHERE s.t_id = t.id)",
+		},
+		{
+			Insert("users").Columns("name", "active", "note").Values(String("it's"), Bool(true), Null()).Values(Param(""), Bool(false), String(`a\b`)).Build(),
+			`INSERT INTO users (name, active, note) VALUES ('it''s', TRUE, NULL), (?, FALSE, E'a\\b')`,
+		},
+		{
+			Update("users").Set("visits", Add(Col("visits"), Int(1))).Where(Eq(Col("id"), Param("id"))).Returning(Col("visits")).Build(),
+			"UPDATE users SET visits = visits + 1 WHERE id = :id RETURNING visits",
+		},
+		{Delete("sessions").Where(Lt(Col("expires_at"), Func("now"))).Build(), "DELETE FROM sessions WHERE expires_at < now()"},
+	}
+	for _, tt := range tests {
+		want, err := sqlparser.ParseStatement(tt.want)
+		if err != nil {
+			t.Fatalf("parse error: %v\nSQL: %s", err, tt.want)
+		}
+		if !ast.Equal(tt.stmt, want, ast.EqualOptions{}) {
+			t.Errorf("built statement is not %s: %v", tt.want, ast.Diff(tt.stmt, want, ast.EqualOptions{}))
+		}
+	}
+}
+
+func TestBuildRenders(t *testing.T) {
+	stmt := Select(Col("id"), Col("name")).From("users").
+		Where(And(Eq(Col("id"), Param("id")), Ne(Col("name"), String("O'Brien")))).
+		Build()
+	tests := []struct {
+		target sqlparser.Dialect
+		want   string
+	}{
+		{sqlparser.DialectPostgres, `SELECT "id", "name" FROM "users" WHERE (("id" = $1) AND ("name" != 'O''Brien'))`},
+		{sqlparser.DialectMySQL, "SELECT `id`, `name` FROM `users` WHERE ((`id` = ?) AND (`name` != 'O''Brien'))"},
+	}
+	for _, tt := range tests {
+		got, _, err := sqlparser.ConvertStatements([]sqlparser.Statement{stmt}, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.target, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s:\ngot  %s\nwant %s", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestBuildCopies(t *testing.T) {
+	base := Select(Col("id")).From("users").Where(Eq(Col("active"), Bool(true)))
+	all := base.Build()
+	one := base.Where(Eq(Col("id"), Int(1))).Build()
+	if want, _ := sqlparser.ParseStatement("SELECT id FROM users WHERE active = TRUE"); !ast.Equal(all, want, ast.EqualOptions{}) {
+		t.Errorf("building on changed an earlier statement: %v", ast.Diff(all, want, ast.EqualOptions{}))
+	}
+	if want, _ := sqlparser.ParseStatement("SELECT id FROM users WHERE active = TRUE AND id = 1"); !ast.Equal(one, want, ast.EqualOptions{}) {
+		t.Errorf("got %v", ast.Diff(one, want, ast.EqualOptions{}))
+	}
+}
+
+func TestBuildBackslashString(t *testing.T) {
+	stmt := Select(String(`C:\temp`)).Build()
+	tests := []struct {
+		target sqlparser.Dialect
+		want   string
+	}{
+		{sqlparser.DialectPostgres, `SELECT 'C:\temp'`},
+		{sqlparser.DialectMySQL, `SELECT 'C:\\temp'`},
+		{sqlparser.DialectSQLite, `SELECT 'C:\temp'`},
+	}
+	for _, tt := range tests {
+		got, _, err := sqlparser.ConvertStatements([]sqlparser.Statement{stmt}, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.target, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s:\ngot  %s\nwant %s", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestBuildPanics(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func()
+	}{
+		{"func name", func() { Func("now(); DROP TABLE users; --") }},
+		{"qualified func name", func() { Func("pg_catalog.now()") }},
+		{"cast type", func() { Cast(Col("x"), "INT) FROM users; --") }},
+		{"param", func() { Param("id OR 1=1") }},
+		{"NaN", func() { Float(math.NaN()) }},
+		{"infinity", func() { Float(math.Inf(-1)) }},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: no panic", tt.name)
+				}
+			}()
+			tt.build()
+		}()
+	}
+	// Plain names still build.
+	Func("pg_catalog.now")
+	Cast(Col("x"), "DOUBLE PRECISION")
+	Param("user_id")
+}
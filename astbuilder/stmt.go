@@ -0,0 +1,243 @@
+package astbuilder
+
+import "github.com/oarkflow/sqlparser/ast"
+
+// SelectBuilder builds a SELECT. Its methods add to the statement and
+// return the builder, for chaining.
+type SelectBuilder struct {
+	stmt *ast.SelectStmt
+	// last is the table the next As names.
+	last *ast.SimpleTable
+}
+
+// Select starts a SELECT of columns, or of * when there are none.
+func Select(columns ...ast.Expr) *SelectBuilder {
+	b := &SelectBuilder{stmt: &ast.SelectStmt{}}
+	if len(columns) == 0 {
+		b.stmt.Columns = []ast.SelectColumn{{Expr: &ast.StarExpr{}, Star: true}}
+	}
+	for _, c := range columns {
+		b.stmt.Columns = append(b.stmt.Columns, ast.SelectColumn{Expr: c})
+	}
+	return b
+}
+
+// Distinct makes the SELECT a SELECT DISTINCT.
+func (b *SelectBuilder) Distinct() *SelectBuilder {
+	b.stmt.Distinct = true
+	return b
+}
+
+// ColumnAs adds the column e AS alias.
+func (b *SelectBuilder) ColumnAs(e ast.Expr, alias string) *SelectBuilder {
+	b.stmt.Columns = append(b.stmt.Columns, ast.SelectColumn{Expr: e, Alias: Ident(alias)})
+	return b
+}
+
+// From adds the table name to the FROM list.
+func (b *SelectBuilder) From(name string) *SelectBuilder {
+	b.last = Table(name, "")
+	b.stmt.From = append(b.stmt.From, b.last)
+	return b
+}
+
+// FromTable adds a FROM item of any kind, such as a subquery.
+func (b *SelectBuilder) FromTable(ref ast.TableRef) *SelectBuilder {
+	b.last = nil
+	b.stmt.From = append(b.stmt.From, ref)
+	return b
+}
+
+// As gives the table the last From or join added an alias. It panics when
+// there is none.
+func (b *SelectBuilder) As(alias string) *SelectBuilder {
+	if b.last == nil {
+		panic("astbuilder: As without a table to name")
+	}
+	b.last.Alias = Ident(alias)
+	return b
+}
+
+// Join inner joins the table name on the condition on.
+func (b *SelectBuilder) Join(name string, on ast.Expr) *SelectBuilder {
+	return b.join(ast.InnerJoin, name, on)
+}
+
+// LeftJoin left joins the table name on the condition on.
+func (b *SelectBuilder) LeftJoin(name string, on ast.Expr) *SelectBuilder {
+	return b.join(ast.LeftJoin, name, on)
+}
+
+// RightJoin right joins the table name on the condition on.
+func (b *SelectBuilder) RightJoin(name string, on ast.Expr) *SelectBuilder {
+	return b.join(ast.RightJoin, name, on)
+}
+
+// join joins name to the last FROM item, which it must follow.
+func (b *SelectBuilder) join(kind ast.JoinKind, name string, on ast.Expr) *SelectBuilder {
+	n := len(b.stmt.From)
+	if n == 0 {
+		panic("astbuilder: join without a table to join to")
+	}
+	b.last = Table(name, "")
+	b.stmt.From[n-1] = &ast.JoinTable{Left: b.stmt.From[n-1], Right: b.last, Kind: kind, On: on}
+	return b
+}
+
+// Where adds cond to the WHERE clause, joined to any conditions already
+// there with AND.
+func (b *SelectBuilder) Where(cond ast.Expr) *SelectBuilder {
+	b.stmt.Where = And(b.stmt.Where, cond)
+	return b
+}
+
+// GroupBy adds exprs to the GROUP BY clause.
+func (b *SelectBuilder) GroupBy(exprs ...ast.Expr) *SelectBuilder {
+	b.stmt.GroupBy = append(b.stmt.GroupBy, exprs...)
+	return b
+}
+
+// Having adds cond to the HAVING clause, as Where does to WHERE.
+func (b *SelectBuilder) Having(cond ast.Expr) *SelectBuilder {
+	b.stmt.Having = And(b.stmt.Having, cond)
+	return b
+}
+
+// OrderBy adds keys, made with Asc and Desc, to the ORDER BY clause.
+func (b *SelectBuilder) OrderBy(keys ...ast.OrderByItem) *SelectBuilder {
+	b.stmt.OrderBy = append(b.stmt.OrderBy, keys...)
+	return b
+}
+
+// Limit returns at most n rows.
+func (b *SelectBuilder) Limit(n int) *SelectBuilder {
+	b.limit().Count = Int(int64(n))
+	return b
+}
+
+// Offset skips the first n rows.
+func (b *SelectBuilder) Offset(n int) *SelectBuilder {
+	b.limit().Offset = Int(int64(n))
+	return b
+}
+
+func (b *SelectBuilder) limit() *ast.LimitClause {
+	if b.stmt.Limit == nil {
+		b.stmt.Limit = &ast.LimitClause{}
+	}
+	return b.stmt.Limit
+}
+
+// Build returns the statement. It is a copy, so the builder can go on to
+// build variants of it.
+func (b *SelectBuilder) Build() *ast.SelectStmt {
+	return ast.Clone(b.stmt)
+}
+
+// InsertBuilder builds an INSERT.
+type InsertBuilder struct {
+	stmt *ast.InsertStmt
+}
+
+// Insert starts an INSERT INTO the table name.
+func Insert(name string) *InsertBuilder {
+	return &InsertBuilder{stmt: &ast.InsertStmt{Table: Name(name)}}
+}
+
+// Columns adds names to the column list.
+func (b *InsertBuilder) Columns(names ...string) *InsertBuilder {
+	for _, name := range names {
+		b.stmt.Columns = append(b.stmt.Columns, Ident(name))
+	}
+	return b
+}
+
+// Values adds a row of values, one for each column.
+func (b *InsertBuilder) Values(row ...ast.Expr) *InsertBuilder {
+	b.stmt.Values = append(b.stmt.Values, row)
+	return b
+}
+
+// Select inserts the rows of subq instead of a VALUES list.
+func (b *InsertBuilder) Select(subq *ast.SelectStmt) *InsertBuilder {
+	b.stmt.Select = subq
+	return b
+}
+
+// Returning adds columns to the RETURNING clause.
+func (b *InsertBuilder) Returning(columns ...ast.Expr) *InsertBuilder {
+	b.stmt.Returning = appendColumns(b.stmt.Returning, columns)
+	return b
+}
+
+// Build returns a copy of the statement, as SelectBuilder.Build does.
+func (b *InsertBuilder) Build() *ast.InsertStmt {
+	return ast.Clone(b.stmt)
+}
+
+// UpdateBuilder builds an UPDATE.
+type UpdateBuilder struct {
+	stmt *ast.UpdateStmt
+}
+
+// Update starts an UPDATE of the table name.
+func Update(name string) *UpdateBuilder {
+	return &UpdateBuilder{stmt: &ast.UpdateStmt{Tables: []ast.TableRef{Table(name, "")}}}
+}
+
+// Set assigns value to the column name.
+func (b *UpdateBuilder) Set(name string, value ast.Expr) *UpdateBuilder {
+	b.stmt.Set = append(b.stmt.Set, ast.Assignment{Column: Ident(name), Value: value})
+	return b
+}
+
+// Where adds cond to the WHERE clause, as SelectBuilder.Where does.
+func (b *UpdateBuilder) Where(cond ast.Expr) *UpdateBuilder {
+	b.stmt.Where = And(b.stmt.Where, cond)
+	return b
+}
+
+// Returning adds columns to the RETURNING clause.
+func (b *UpdateBuilder) Returning(columns ...ast.Expr) *UpdateBuilder {
+	b.stmt.Returning = appendColumns(b.stmt.Returning, columns)
+	return b
+}
+
+// Build returns a copy of the statement, as SelectBuilder.Build does.
+func (b *UpdateBuilder) Build() *ast.UpdateStmt {
+	return ast.Clone(b.stmt)
+}
+
+// DeleteBuilder builds a DELETE.
+type DeleteBuilder struct {
+	stmt *ast.DeleteStmt
+}
+
+// Delete starts a DELETE FROM the table name.
+func Delete(name string) *DeleteBuilder {
+	return &DeleteBuilder{stmt: &ast.DeleteStmt{From: []ast.TableRef{Table(name, "")}}}
+}
+
+// Where adds cond to the WHERE clause, as SelectBuilder.Where does.
+func (b *DeleteBuilder) Where(cond ast.Expr) *DeleteBuilder {
+	b.stmt.Where = And(b.stmt.Where, cond)
+	return b
+}
+
+// Returning adds columns to the RETURNING clause.
+func (b *DeleteBuilder) Returning(columns ...ast.Expr) *DeleteBuilder {
+	b.stmt.Returning = appendColumns(b.stmt.Returning, columns)
+	return b
+}
+
+// Build returns a copy of the statement, as SelectBuilder.Build does.
+func (b *DeleteBuilder) Build() *ast.DeleteStmt {
+	return ast.Clone(b.stmt)
+}
+
+func appendColumns(list []ast.SelectColumn, columns []ast.Expr) []ast.SelectColumn {
+	for _, c := range columns {
+		list = append(list, ast.SelectColumn{Expr: c})
+	}
+	return list
+}
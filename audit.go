@@ -0,0 +1,748 @@
+package sqlparser
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/ident"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// AccessAudit is a column-level access matrix for a corpus of statements:
+// for every table column touched, which statements read it and which
+// write it. It answers data-mapping questions such as "what touches
+// users.email" and least-privilege reviews of an application's queries.
+type AccessAudit struct {
+	Columns []ColumnAccess `json:"columns"`
+
+	dialect Dialect
+}
+
+// ColumnAccess lists the statements, by index into the audited corpus,
+// that read or write one column. Column is "*" when a statement uses every
+// column and no schema was given to expand it. Table is empty when an
+// unqualified column could not be attributed to one table.
+type ColumnAccess struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	Reads  []int  `json:"reads,omitempty"`
+	Writes []int  `json:"writes,omitempty"`
+}
+
+// AuditColumnAccess resolves every column reference in stmts to the base
+// table it comes from, following aliases, joins, derived tables, CTEs and
+// correlated subqueries. Columns a statement only passes through a derived
+// table are attributed to the base columns the derived table reads.
+//
+// Writes are the columns INSERT lists (or every column when it lists none),
+// the columns UPDATE and upsert clauses assign, and every column of the
+// rows DELETE removes. Everything else a statement evaluates is a read.
+//
+// schema is optional. With it, unqualified columns in joins are attributed
+// to the table that declares them and * expands to the table's columns.
+func AuditColumnAccess(stmts []Statement, schema *Schema) *AccessAudit {
+	index := map[[2]string]*ColumnAccess{}
+	stmt := 0
+	a := &auditor{schema: schema}
+	a.visit = func(table, column string, kind accessKind) {
+		key := [2]string{ident.Key(table, a.dialect()), ident.Key(column, a.dialect())}
+		c := index[key]
+		if c == nil {
+			c = &ColumnAccess{Table: table, Column: column}
+			index[key] = c
+		}
+		list := &c.Reads
+		if kind != accessRead {
+			list = &c.Writes
+		}
+		if n := len(*list); n == 0 || (*list)[n-1] != stmt {
+			*list = append(*list, stmt)
+		}
+	}
+	for i, st := range stmts {
+		stmt = i
+		a.statement(st)
+	}
+	out := &AccessAudit{Columns: make([]ColumnAccess, 0, len(index)), dialect: a.dialect()}
+	for _, c := range index {
+		out.Columns = append(out.Columns, *c)
+	}
+	slices.SortFunc(out.Columns, func(x, y ColumnAccess) int {
+		if c := compareFold(x.Table, y.Table); c != 0 {
+			return c
+		}
+		return compareFold(x.Column, y.Column)
+	})
+	return out
+}
+
+// Column returns the entry for table.column, or nil if no statement
+// touched it. Names are matched as the dialect of the audit's schema
+// compares them.
+func (a *AccessAudit) Column(table, column string) *ColumnAccess {
+	for i := range a.Columns {
+		c := &a.Columns[i]
+		if ident.Equal(c.Table, table, a.dialect) && ident.Equal(c.Column, column, a.dialect) {
+			return c
+		}
+	}
+	return nil
+}
+
+// JSON encodes the matrix as an indented JSON document.
+func (a *AccessAudit) JSON() ([]byte, error) {
+	return json.MarshalIndent(a, "", "  ")
+}
+
+// auditor resolves the column references of statements to base tables
+// and reports each access to visit.
+type auditor struct {
+	schema *Schema
+	visit  func(table, column string, kind accessKind)
+	// spread reports a column that could come from several tables once per
+	// candidate table instead of once with an empty table.
+	spread bool
+	// table and call, when set, see every base table a FROM clause names
+	// and every function call, including those whose columns are never
+	// referenced, as in SELECT COUNT(*) FROM t.
+	table func(name string)
+	call  func(*ast.FuncCall)
+	// compare, when set, sees the operands of every comparison: the sides
+	// of =, <>, <, <=, > and >=, and the operand and items of IN and
+	// BETWEEN, with the scope resolve needs for their columns.
+	compare func(scope *auditScope, left ast.Expr, right []ast.Expr)
+	// node, when set, sees every expression, table reference and SELECT
+	// block (each side of a UNION is one) with its subquery depth: 0 in
+	// the statement itself, one more inside each subquery, derived table
+	// and CTE body.
+	node  func(n ast.Node, depth int)
+	depth int
+	// with, when set, sees every WITH clause before its CTE bodies are
+	// walked.
+	with func(w *ast.WithClause)
+}
+
+// dialect is the dialect of the auditor's schema, by which it compares
+// names.
+func (a *auditor) dialect() Dialect {
+	return a.schema.identDialect()
+}
+
+// accessKind is how a statement touches a column.
+type accessKind uint8
+
+const (
+	accessRead accessKind = iota
+	accessInsert
+	accessUpdate
+	accessDelete
+)
+
+// auditScope is one query level: the tables its FROM clause brings into
+// scope and the CTE names visible to it.
+type auditScope struct {
+	parent  *auditScope
+	sources []auditSource
+	ctes    []string
+	// aliases are the select-list aliases that ORDER BY, GROUP BY and
+	// HAVING may refer to instead of a column.
+	aliases []string
+}
+
+// auditSource is one FROM item. table is the base table, or empty for a
+// derived table or CTE, whose own reads were recorded when it was walked.
+type auditSource struct {
+	name  string
+	table string
+}
+
+// recordAll records every column of table, or * without a schema.
+func (a *auditor) recordAll(table string, kind accessKind) {
+	if a.schema != nil {
+		if t := a.schema.Table(table); t != nil {
+			for _, c := range t.Columns {
+				a.visit(table, c.Name, kind)
+			}
+			return
+		}
+	}
+	a.visit(table, "*", kind)
+}
+
+// recordStar records the reads of a star column over table, leaving out
+// the columns of its EXCEPT list when the schema knows the table.
+func (a *auditor) recordStar(table string, except []*ast.Ident) {
+	var t *Table
+	if a.schema != nil && except != nil {
+		t = a.schema.Table(table)
+	}
+	if t == nil {
+		a.recordAll(table, accessRead)
+		return
+	}
+	for _, c := range t.Columns {
+		if !slices.ContainsFunc(except, func(id *ast.Ident) bool { return ident.Equal(id.Unquoted, c.Name, a.dialect()) }) {
+			a.visit(table, c.Name, accessRead)
+		}
+	}
+}
+
+func (a *auditor) statement(stmt Statement) {
+	switch s := stmt.(type) {
+	case *ast.SelectStmt:
+		a.selectStmt(s, nil)
+	case *ast.InsertStmt:
+		a.insert(s, nil)
+	case *ast.UpdateStmt:
+		a.update(s, nil)
+	case *ast.DeleteStmt:
+		a.delete(s, nil)
+	case *ast.CreateTableStmt:
+		if s.Select != nil {
+			a.selectStmt(s.Select, nil)
+		}
+	case *ast.CreateViewStmt:
+		a.selectStmt(s.Select, nil)
+	}
+}
+
+func (a *auditor) insert(s *ast.InsertStmt, outer *auditScope) {
+	table := qualifiedName(s.Table)
+	if len(s.Columns) == 0 {
+		a.recordAll(table, accessInsert)
+	}
+	for _, c := range s.Columns {
+		a.visit(table, c.Unquoted, accessInsert)
+	}
+	scope := a.withScope(s.With, outer)
+	for _, row := range s.Values {
+		for _, e := range row {
+			a.expr(e, scope)
+		}
+	}
+	if s.Select != nil {
+		a.selectStmt(s.Select, scope)
+	}
+	// Upsert clauses see the target row and PostgreSQL's EXCLUDED row,
+	// which holds the values being inserted.
+	name := s.Table.Parts[len(s.Table.Parts)-1].Unquoted
+	upsert := &auditScope{parent: scope, sources: []auditSource{{name: name, table: table}, {name: "excluded"}}}
+	for _, as := range append(slices.Clip(s.OnDupKey), s.OnConflictUpdate...) {
+		a.visit(table, as.Column.Unquoted, accessUpdate)
+		a.expr(as.Value, upsert)
+	}
+	a.expr(s.OnConflictWhere, upsert)
+	a.expr(s.OnConflictUpdateWhere, upsert)
+	a.returning(s.Returning, &auditScope{parent: scope, sources: []auditSource{{name: name, table: table}}})
+}
+
+func (a *auditor) update(s *ast.UpdateStmt, outer *auditScope) {
+	scope := a.withScope(s.With, outer)
+	for _, ref := range s.Tables {
+		a.tableRef(ref, scope)
+	}
+	for _, as := range s.Set {
+		a.column(scope, "", as.Column.Unquoted, accessUpdate)
+		a.expr(as.Value, scope)
+	}
+	a.expr(s.Where, scope)
+	for _, o := range s.Order {
+		a.expr(o.Expr, scope)
+	}
+	a.returning(s.Returning, scope)
+}
+
+func (a *auditor) delete(s *ast.DeleteStmt, outer *auditScope) {
+	scope := a.withScope(s.With, outer)
+	for _, ref := range s.From {
+		a.tableRef(ref, scope)
+	}
+	if len(s.Tables) == 0 {
+		for _, src := range scope.sources {
+			if src.table != "" {
+				a.recordAll(src.table, accessDelete)
+			}
+		}
+	}
+	for _, q := range s.Tables {
+		name := qualifiedName(q)
+		if src := scope.source(name, a.dialect()); src != nil && src.table != "" {
+			name = src.table
+		}
+		a.recordAll(name, accessDelete)
+	}
+	a.expr(s.Where, scope)
+	for _, o := range s.Order {
+		a.expr(o.Expr, scope)
+	}
+	a.returning(s.Returning, scope)
+}
+
+// returning records the reads of a RETURNING list, which sees the rows
+// the statement wrote.
+func (a *auditor) returning(cols []ast.SelectColumn, scope *auditScope) {
+	for _, c := range cols {
+		if !c.Star {
+			a.expr(c.Expr, scope)
+			continue
+		}
+		for _, src := range scope.sources {
+			if src.table != "" {
+				a.recordAll(src.table, accessRead)
+			}
+		}
+	}
+}
+
+// withScope returns a scope holding the CTE names of w, after walking the
+// CTE bodies.
+func (a *auditor) withScope(w *ast.WithClause, outer *auditScope) *auditScope {
+	scope := &auditScope{parent: outer}
+	if w == nil {
+		return scope
+	}
+	if a.with != nil {
+		a.with(w)
+	}
+	for _, cte := range w.CTEs {
+		if w.Recursive {
+			scope.ctes = append(scope.ctes, cte.Name.Unquoted)
+		}
+		switch dml := cte.DML.(type) {
+		case nil:
+			a.subquery(cte.Subq, scope)
+		case *ast.InsertStmt:
+			a.depth++
+			a.insert(dml, scope)
+			a.depth--
+		case *ast.UpdateStmt:
+			a.depth++
+			a.update(dml, scope)
+			a.depth--
+		case *ast.DeleteStmt:
+			a.depth++
+			a.delete(dml, scope)
+			a.depth--
+		}
+		if !w.Recursive {
+			scope.ctes = append(scope.ctes, cte.Name.Unquoted)
+		}
+	}
+	return scope
+}
+
+func (a *auditor) selectStmt(s *ast.SelectStmt, outer *auditScope) {
+	if s == nil {
+		return
+	}
+	if s.With != nil {
+		outer = a.withScope(s.With, outer)
+	}
+	if s.SetOp == nil {
+		a.selectCore(s, outer)
+		return
+	}
+	a.selectStmt(s.SetOp.Left, outer)
+	a.selectStmt(s.SetOp.Right, outer)
+	// The ORDER BY of a set operation sorts by its output columns, which
+	// the first block names.
+	first := s.SetOp.Left
+	for first.SetOp != nil {
+		first = first.SetOp.Left
+	}
+	scope := &auditScope{parent: outer}
+	for _, c := range first.Columns {
+		switch e := c.Expr.(type) {
+		case *ast.Ident:
+			scope.aliases = append(scope.aliases, e.Unquoted)
+		case *ast.QualifiedIdent:
+			scope.aliases = append(scope.aliases, e.Parts[len(e.Parts)-1].Unquoted)
+		}
+		if c.Alias != nil {
+			scope.aliases = append(scope.aliases, c.Alias.Unquoted)
+		}
+	}
+	for _, o := range s.OrderBy {
+		a.expr(o.Expr, scope)
+	}
+}
+
+// subquery walks a query nested in another one.
+func (a *auditor) subquery(s *ast.SelectStmt, outer *auditScope) {
+	a.depth++
+	a.selectStmt(s, outer)
+	a.depth--
+}
+
+func (a *auditor) selectCore(s *ast.SelectStmt, outer *auditScope) {
+	if a.node != nil {
+		a.node(s, a.depth)
+	}
+	scope := &auditScope{parent: outer}
+	for _, ref := range s.From {
+		a.tableRef(ref, scope)
+	}
+	for _, c := range s.Columns {
+		switch ts, _ := c.Expr.(*ast.TableStar); {
+		case c.Star:
+			for _, src := range scope.sources {
+				if src.table != "" {
+					a.recordStar(src.table, c.Except)
+				}
+			}
+		case ts != nil && c.Except != nil:
+			if src := scope.source(qualifiedName(ts.Table), a.dialect()); src != nil && src.table != "" {
+				a.recordStar(src.table, c.Except)
+			}
+		default:
+			a.expr(c.Expr, scope)
+		}
+		if c.Alias != nil {
+			scope.aliases = append(scope.aliases, c.Alias.Unquoted)
+		}
+	}
+	a.expr(s.Where, scope)
+	for _, e := range s.GroupBy {
+		a.expr(e, scope)
+	}
+	a.expr(s.Having, scope)
+	a.expr(s.Qualify, scope)
+	for _, row := range s.Values {
+		for _, e := range row {
+			a.expr(e, scope)
+		}
+	}
+	for _, o := range s.OrderBy {
+		a.expr(o.Expr, scope)
+	}
+	if s.LimitBy != nil {
+		for _, e := range s.LimitBy.By {
+			a.expr(e, scope)
+		}
+	}
+}
+
+func (a *auditor) tableRef(ref ast.TableRef, scope *auditScope) {
+	if a.node != nil {
+		a.node(ref, a.depth)
+	}
+	switch t := ref.(type) {
+	case *ast.SimpleTable:
+		name := qualifiedName(t.Name)
+		src := auditSource{name: t.Name.Parts[len(t.Name.Parts)-1].Unquoted, table: name}
+		if len(t.Name.Parts) == 1 && scope.isCTE(name, a.dialect()) {
+			src.table = ""
+		}
+		if t.Alias != nil {
+			src.name = t.Alias.Unquoted
+		}
+		if src.table != "" && a.table != nil {
+			a.table(src.table)
+		}
+		scope.sources = append(scope.sources, src)
+	case *ast.SubqueryTable:
+		a.subquery(t.Subq, scope.parent)
+		src := auditSource{}
+		if t.Alias != nil {
+			src.name = t.Alias.Unquoted
+		}
+		scope.sources = append(scope.sources, src)
+	case *ast.PivotTable:
+		// The columns a PIVOT does not spread or aggregate group its rows
+		// and those an UNPIVOT does not list pass through, so every
+		// column of the source is read.
+		inner := &auditScope{parent: scope}
+		a.tableRef(t.Source, inner)
+		for _, src := range inner.sources {
+			if src.table != "" {
+				a.recordAll(src.table, accessRead)
+			}
+		}
+		if !t.Unpivot {
+			a.expr(t.Agg, inner)
+		}
+		src := auditSource{}
+		if t.Alias != nil {
+			src.name = t.Alias.Unquoted
+		}
+		scope.sources = append(scope.sources, src)
+	case *ast.JoinTable:
+		before := len(scope.sources)
+		a.tableRef(t.Left, scope)
+		mid := len(scope.sources)
+		a.tableRef(t.Right, scope)
+		for _, id := range t.Using {
+			for _, side := range [][]auditSource{scope.sources[before:mid], scope.sources[mid:]} {
+				if src, ok := a.attribute(side, id.Unquoted); ok && src != nil && src.table != "" {
+					a.visit(src.table, id.Unquoted, accessRead)
+				}
+			}
+		}
+		a.expr(t.On, scope)
+	}
+}
+
+func (a *auditor) expr(e ast.Expr, scope *auditScope) {
+	if _, query := e.(*ast.SelectStmt); a.node != nil && e != nil && !query {
+		a.node(e, a.depth)
+	}
+	switch ex := e.(type) {
+	case nil:
+	case *ast.Ident:
+		if !slices.ContainsFunc(scope.aliases, func(s string) bool { return ident.Equal(s, ex.Unquoted, a.dialect()) }) {
+			a.column(scope, "", ex.Unquoted, accessRead)
+		}
+	case *ast.QualifiedIdent:
+		n := len(ex.Parts)
+		if n == 1 {
+			a.expr(ex.Parts[0], scope)
+			return
+		}
+		qualifier := qualifiedName(&ast.QualifiedIdent{Parts: ex.Parts[:n-1]})
+		a.column(scope, qualifier, ex.Parts[n-1].Unquoted, accessRead)
+	case *ast.TableStar:
+		if src := scope.source(qualifiedName(ex.Table), a.dialect()); src != nil && src.table != "" {
+			a.recordAll(src.table, accessRead)
+		}
+	case *ast.BinaryExpr:
+		if a.compare != nil {
+			switch ex.Op {
+			case lexer.EQ, lexer.NEQ, lexer.LT, lexer.LTE, lexer.GT, lexer.GTE:
+				a.compare(scope, ex.Left, []ast.Expr{ex.Right})
+			}
+		}
+		a.expr(ex.Left, scope)
+		a.expr(ex.Right, scope)
+	case *ast.UnaryExpr:
+		a.expr(ex.Expr, scope)
+	case *ast.FuncCall:
+		if a.call != nil {
+			a.call(ex)
+		}
+		for _, arg := range ex.Args {
+			a.expr(arg, scope)
+		}
+		if ex.Over != nil {
+			for _, e := range ex.Over.PartitionBy {
+				a.expr(e, scope)
+			}
+			for _, o := range ex.Over.OrderBy {
+				a.expr(o.Expr, scope)
+			}
+		}
+	case *ast.CaseExpr:
+		a.expr(ex.Operand, scope)
+		for _, w := range ex.Whens {
+			a.expr(w.Cond, scope)
+			a.expr(w.Result, scope)
+		}
+		a.expr(ex.Else, scope)
+	case *ast.BetweenExpr:
+		if a.compare != nil {
+			a.compare(scope, ex.Expr, []ast.Expr{ex.Lo, ex.Hi})
+		}
+		a.expr(ex.Expr, scope)
+		a.expr(ex.Lo, scope)
+		a.expr(ex.Hi, scope)
+	case *ast.InExpr:
+		if a.compare != nil && len(ex.List) > 0 {
+			a.compare(scope, ex.Expr, ex.List)
+		}
+		a.expr(ex.Expr, scope)
+		for _, item := range ex.List {
+			a.expr(item, scope)
+		}
+		a.subquery(ex.Subq, scope)
+	case *ast.LikeExpr:
+		a.expr(ex.Expr, scope)
+		a.expr(ex.Pattern, scope)
+		a.expr(ex.Escape, scope)
+	case *ast.IsNullExpr:
+		a.expr(ex.Expr, scope)
+	case *ast.ExistsExpr:
+		a.subquery(ex.Subq, scope)
+	case *ast.SubqueryExpr:
+		a.subquery(ex.Subq, scope)
+	case *ast.QuantifiedComparisonExpr:
+		a.expr(ex.Left, scope)
+		a.expr(ex.Array, scope)
+		a.subquery(ex.Subq, scope)
+	case *ast.SelectStmt:
+		a.subquery(ex, scope)
+	case *ast.CastExpr:
+		a.expr(ex.Expr, scope)
+	case *ast.IntervalExpr:
+		a.expr(ex.Expr, scope)
+	}
+}
+
+// resolve returns the base table and column e refers to in scope, and
+// false when e is not a column reference or cannot be attributed to a
+// single base table.
+func (a *auditor) resolve(e ast.Expr, scope *auditScope) (table, column string, ok bool) {
+	switch e.(type) {
+	case *ast.Ident, *ast.QualifiedIdent:
+	default:
+		return "", "", false
+	}
+	visit, spread := a.visit, a.spread
+	defer func() { a.visit, a.spread = visit, spread }()
+	n := 0
+	a.visit = func(t, c string, _ accessKind) {
+		table, column = t, c
+		n++
+	}
+	a.spread = false
+	a.expr(e, scope)
+	return table, column, n == 1 && table != "" && column != "*"
+}
+
+// references reports whether e refers to any column, in scope or in a
+// subquery of its own.
+func (a *auditor) references(e ast.Expr, scope *auditScope) bool {
+	visit := a.visit
+	defer func() { a.visit = visit }()
+	found := false
+	a.visit = func(string, string, accessKind) { found = true }
+	a.expr(e, scope)
+	return found
+}
+
+// column resolves a column reference against scope and its enclosing
+// scopes and records it.
+func (a *auditor) column(scope *auditScope, qualifier, name string, kind accessKind) {
+	for s := scope; s != nil; s = s.parent {
+		if qualifier != "" {
+			if src := s.lookup(qualifier, a.dialect()); src != nil {
+				if src.table != "" {
+					a.visit(src.table, name, kind)
+				}
+				return
+			}
+			continue
+		}
+		if src, ok := a.attribute(s.sources, name); ok {
+			switch {
+			case src == nil && a.spread:
+				for _, c := range s.sources {
+					if c.table != "" && a.declares(c.table, name) != no {
+						a.visit(c.table, name, kind)
+					}
+				}
+			case src == nil:
+				a.visit("", name, kind)
+			case src.table != "":
+				a.visit(src.table, name, kind)
+			}
+			return
+		}
+	}
+	a.visit(qualifier, name, kind)
+}
+
+// attribute picks the source of an unqualified column among sources. It
+// reports false when the column belongs to none of them, so resolution
+// continues in the enclosing scope, and a nil source when more than one
+// could hold it.
+func (a *auditor) attribute(sources []auditSource, name string) (*auditSource, bool) {
+	switch len(sources) {
+	case 0:
+		return nil, false
+	case 1:
+		if src := &sources[0]; src.table == "" || a.declares(src.table, name) != no {
+			return src, true
+		}
+		return nil, false
+	}
+	var match []*auditSource
+	unknown := false
+	for i := range sources {
+		src := &sources[i]
+		if src.table == "" {
+			unknown = true
+			continue
+		}
+		switch a.declares(src.table, name) {
+		case yes:
+			match = append(match, src)
+		case maybe:
+			unknown = true
+		}
+	}
+	switch {
+	case len(match) == 1 && !unknown:
+		return match[0], true
+	case len(match) == 0 && !unknown:
+		return nil, false
+	}
+	return nil, true
+}
+
+type tristate uint8
+
+const (
+	maybe tristate = iota
+	yes
+	no
+)
+
+// declares reports whether the schema's table has column name; maybe when
+// there is no schema or it lacks the table.
+func (a *auditor) declares(table, name string) tristate {
+	if a.schema == nil {
+		return maybe
+	}
+	t := a.schema.Table(table)
+	switch {
+	case t == nil:
+		return maybe
+	case t.Column(name) != nil:
+		return yes
+	}
+	return no
+}
+
+// lookup finds the FROM item a qualifier names, by alias or table name,
+// comparing names as dialect does.
+func (s *auditScope) lookup(qualifier string, dialect Dialect) *auditSource {
+	for i := range s.sources {
+		src := &s.sources[i]
+		if ident.Equal(src.name, qualifier, dialect) || src.table != "" && ident.Equal(src.table, qualifier, dialect) {
+			return src
+		}
+	}
+	return nil
+}
+
+// source is lookup across s and its enclosing scopes.
+func (s *auditScope) source(qualifier string, dialect Dialect) *auditSource {
+	for ; s != nil; s = s.parent {
+		if src := s.lookup(qualifier, dialect); src != nil {
+			return src
+		}
+	}
+	return nil
+}
+
+func (s *auditScope) isCTE(name string, dialect Dialect) bool {
+	for ; s != nil; s = s.parent {
+		if slices.ContainsFunc(s.ctes, func(c string) bool { return ident.Equal(c, name, dialect) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// qualifiedName joins the resolved parts of q with dots.
+func qualifiedName(q *ast.QualifiedIdent) string {
+	if q == nil {
+		return ""
+	}
+	parts := make([]string, len(q.Parts))
+	for i, p := range q.Parts {
+		parts[i] = p.Unquoted
+	}
+	return strings.Join(parts, ".")
+}
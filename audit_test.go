@@ -0,0 +1,166 @@
+package sqlparser_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestAuditColumnAccess(t *testing.T) {
+	schema, err := sqlparser.BuildSchema(`
+CREATE TABLE users (id INT PRIMARY KEY, email TEXT, name TEXT);
+CREATE TABLE orders (id INT PRIMARY KEY, user_id INT, total INT)`)
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	stmts, err := sqlparser.ParseStatements(`
+SELECT u.email, total FROM users u JOIN orders o ON o.user_id = u.id WHERE o.total > 10;
+UPDATE users SET email = lower(name) WHERE id = 1;
+INSERT INTO orders (user_id, total) SELECT id, 0 FROM users;
+DELETE FROM orders WHERE user_id IN (SELECT id FROM users WHERE email LIKE '%@x');
+WITH big AS (SELECT user_id AS uid FROM orders WHERE total > 100) SELECT name FROM users WHERE EXISTS (SELECT 1 FROM big WHERE uid = users.id) ORDER BY name;
+SELECT n FROM (SELECT name AS n FROM users) d`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	audit := sqlparser.AuditColumnAccess(stmts, schema)
+	cases := []struct {
+		table, column string
+		reads, writes []int
+	}{
+		{"users", "email", []int{0, 3}, []int{1}},
+		{"users", "name", []int{1, 4, 5}, nil},
+		{"users", "id", []int{0, 1, 2, 3, 4}, nil},
+		{"orders", "total", []int{0, 4}, []int{2, 3}},
+		{"orders", "user_id", []int{0, 3, 4}, []int{2, 3}},
+		{"orders", "id", nil, []int{3}},
+	}
+	for _, c := range cases {
+		got := audit.Column(c.table, c.column)
+		if got == nil {
+			t.Errorf("%s.%s: not audited", c.table, c.column)
+			continue
+		}
+		if !reflect.DeepEqual(got.Reads, c.reads) || !reflect.DeepEqual(got.Writes, c.writes) {
+			t.Errorf("%s.%s: reads %v writes %v, want %v %v", c.table, c.column, got.Reads, got.Writes, c.reads, c.writes)
+		}
+	}
+	if len(audit.Columns) != len(cases) {
+		t.Errorf("unexpected columns %+v", audit.Columns)
+	}
+	out, err := audit.JSON()
+	if err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	var decoded sqlparser.AccessAudit
+	if err := json.Unmarshal(out, &decoded); err != nil || !reflect.DeepEqual(&decoded, audit) {
+		t.Fatalf("JSON round trip mismatch: %s", out)
+	}
+}
+
+func TestAuditQuantifiedComparison(t *testing.T) {
+	stmts, err := sqlparser.ParseStatements(`SELECT id FROM orders WHERE total > ALL (SELECT amount FROM refunds WHERE refunds.order_id = orders.id)`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	audit := sqlparser.AuditColumnAccess(stmts, nil)
+	for _, col := range []string{"orders.total", "orders.id", "refunds.amount", "refunds.order_id"} {
+		table, column, _ := strings.Cut(col, ".")
+		if audit.Column(table, column) == nil {
+			t.Errorf("%s: not audited in %+v", col, audit.Columns)
+		}
+	}
+}
+
+func TestAuditStarExcept(t *testing.T) {
+	schema, err := sqlparser.BuildSchema("CREATE TABLE users (id INT, email TEXT, secret TEXT); CREATE TABLE orders (id INT, total INT)")
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	res, err := sqlparser.ParseWithOptions("SELECT * EXCEPT (secret) FROM users; SELECT o.* EXCEPT (total), u.id FROM orders o, users u", sqlparser.ParseOptions{StarExcept: true})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	audit := sqlparser.AuditColumnAccess(res.Statements, schema)
+	var got []string
+	for _, c := range audit.Columns {
+		got = append(got, c.Table+"."+c.Column)
+	}
+	want := []string{"orders.id", "users.email", "users.id"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestAuditPivot(t *testing.T) {
+	schema, err := sqlparser.BuildSchema("CREATE TABLE sales (region TEXT, quarter TEXT, amount INT); CREATE TABLE regions (id TEXT, name TEXT)")
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	stmts, err := sqlparser.ParseStatements("SELECT r.name, p.q1 FROM sales PIVOT (SUM(amount) FOR quarter IN ('Q1' AS q1)) p JOIN regions r ON r.id = p.region")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	audit := sqlparser.AuditColumnAccess(stmts, schema)
+	var got []string
+	for _, c := range audit.Columns {
+		got = append(got, c.Table+"."+c.Column)
+	}
+	want := []string{"regions.id", "regions.name", "sales.amount", "sales.quarter", "sales.region"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestAuditColumnAccessWithoutSchema(t *testing.T) {
+	stmts, err := sqlparser.ParseStatements(`
+SELECT * FROM users;
+SELECT id FROM a, b;
+INSERT INTO log VALUES (1)`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	audit := sqlparser.AuditColumnAccess(stmts, nil)
+	var got []string
+	for _, c := range audit.Columns {
+		got = append(got, c.Table+"."+c.Column)
+	}
+	want := []string{".id", "log.*", "users.*"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestAuditColumnAccessDialectNames(t *testing.T) {
+	stmts, err := sqlparser.ParseStatements(`SELECT id FROM t; SELECT "ID" FROM t`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	for _, tt := range []struct {
+		dialect sqlparser.Dialect
+		ddl     string
+		want    []string
+	}{
+		{sqlparser.DialectPostgres, `CREATE TABLE t (id INT, "ID" INT)`, []string{"t.ID", "t.id"}},
+		{sqlparser.DialectMySQL, "CREATE TABLE t (id INT)", []string{"t.id"}},
+	} {
+		schema, err := sqlparser.BuildSchemaForDialect(tt.ddl, tt.dialect)
+		if err != nil {
+			t.Fatalf("schema: %v", err)
+		}
+		audit := sqlparser.AuditColumnAccess(stmts, schema)
+		var got []string
+		for _, c := range audit.Columns {
+			got = append(got, c.Table+"."+c.Column)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.dialect, got, tt.want)
+		}
+		if c := audit.Column("T", "ID"); tt.dialect == sqlparser.DialectPostgres && c != nil || tt.dialect == sqlparser.DialectMySQL && (c == nil || len(c.Reads) != 2) {
+			t.Errorf("%s: Column(T, ID) = %+v", tt.dialect, c)
+		}
+	}
+}
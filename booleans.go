@@ -0,0 +1,148 @@
+package sqlparser
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// isBoolColumn reports whether c holds booleans: a BOOLEAN, or MySQL's
+// TINYINT(1), which PostgreSQL gets as BOOLEAN.
+func isBoolColumn(c *Column) bool {
+	switch strings.ToLower(c.Type) {
+	case "bool", "boolean":
+		return true
+	case "tinyint":
+		return c.Precision == 1
+	}
+	return false
+}
+
+// isBoolType is isBoolColumn for a declared type.
+func isBoolType(dt *ast.DataType) bool {
+	return dt != nil && isBoolColumn(&Column{Type: strings.ToUpper(string(dt.Name)), Precision: dt.Precision})
+}
+
+// boolColumn reports whether table.column is a boolean column of the
+// script's DDL or, failing that, of ConvertOptions.Schema.
+func (r *dialectRenderer) boolColumn(table, column string) bool {
+	for _, s := range []*Schema{r.ddl, r.schema} {
+		if s == nil {
+			continue
+		}
+		if t := s.Table(table); t != nil {
+			c := t.Column(column)
+			return c != nil && isBoolColumn(c)
+		}
+	}
+	return false
+}
+
+// trackDDL replays the script's DDL into r.ddl for boolColumn.
+func (r *dialectRenderer) trackDDL(stmt Statement) {
+	if !r.convertBooleans || r.target != DialectPostgres {
+		return
+	}
+	if r.ddl == nil {
+		r.ddl = &Schema{Dialect: r.target}
+	}
+	r.ddl.Apply(stmt)
+}
+
+// markBoolLiterals finds the integer literals of stmt that stand for
+// booleans, for renderBool to write as TRUE and FALSE: the 1 and 0
+// compared with a boolean column, and any integer assigned or inserted
+// into one, or given as its default, which MySQL reads as true unless it
+// is 0.
+func (r *dialectRenderer) markBoolLiterals(stmt Statement) {
+	r.boolLits = nil
+	if !r.convertBooleans || r.target != DialectPostgres {
+		return
+	}
+	mark := func(e ast.Expr, stored bool) {
+		lit, ok := e.(*ast.Literal)
+		if !ok || lit.Kind != lexer.INT || !stored && string(lit.Raw) != "0" && string(lit.Raw) != "1" {
+			return
+		}
+		if r.boolLits == nil {
+			r.boolLits = map[*ast.Literal]bool{}
+		}
+		r.boolLits[lit] = strings.Trim(string(lit.Raw), "0") != ""
+	}
+	schema := r.schema
+	if schema == nil {
+		schema = r.ddl
+	}
+	a := &auditor{schema: schema, visit: func(string, string, accessKind) {}}
+	a.compare = func(scope *auditScope, left ast.Expr, right []ast.Expr) {
+		if table, name, ok := a.resolve(left, scope); ok && r.boolColumn(table, name) {
+			for _, e := range right {
+				mark(e, false)
+			}
+		}
+		if len(right) == 1 {
+			if table, name, ok := a.resolve(right[0], scope); ok && r.boolColumn(table, name) {
+				mark(left, false)
+			}
+		}
+	}
+	a.statement(stmt)
+
+	switch s := stmt.(type) {
+	case *ast.CreateTableStmt:
+		for _, col := range s.Columns {
+			if isBoolType(col.Type) {
+				mark(col.Default, true)
+			}
+		}
+	case *ast.InsertStmt:
+		table := qualifiedName(s.Table)
+		cols := identNames(s.Columns)
+		if len(cols) == 0 {
+			cols = r.columnNames(table)
+		}
+		for _, row := range s.Values {
+			for i, e := range row {
+				if i < len(cols) && r.boolColumn(table, cols[i]) {
+					mark(e, true)
+				}
+			}
+		}
+		for _, as := range append(slices.Clip(s.OnDupKey), s.OnConflictUpdate...) {
+			if r.boolColumn(table, as.Column.Unquoted) {
+				mark(as.Value, true)
+			}
+		}
+	case *ast.UpdateStmt:
+		if len(s.Tables) != 1 {
+			return
+		}
+		if t, ok := s.Tables[0].(*ast.SimpleTable); ok {
+			for _, as := range s.Set {
+				if r.boolColumn(qualifiedName(t.Name), as.Column.Unquoted) {
+					mark(as.Value, true)
+				}
+			}
+		}
+	}
+}
+
+// columnNames lists the columns of table, in order, as boolColumn finds
+// it.
+func (r *dialectRenderer) columnNames(table string) []string {
+	for _, s := range []*Schema{r.ddl, r.schema} {
+		if s == nil {
+			continue
+		}
+		if t := s.Table(table); t != nil {
+			names := make([]string, len(t.Columns))
+			for i, c := range t.Columns {
+				names[i] = c.Name
+			}
+			return names
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package sqlparser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// CacheInfo is what a query result cache needs to know about a statement:
+// the key to store a SELECT's result under and the tables that, when
+// written, make it stale.
+type CacheInfo struct {
+	// Key is the hex SHA-256 of Fingerprint and ParamShape. Results must
+	// still be looked up by Key together with the bound argument values.
+	Key string
+	// Fingerprint is the statement in canonical form: PostgreSQL syntax,
+	// upper-case keywords, quoted identifiers, single spaces and
+	// placeholders numbered $1, $2, ... Literals are kept, since they
+	// change the result.
+	Fingerprint string
+	// ParamShape lists the placeholders in the order Fingerprint numbers
+	// them, comma-separated, as written: ? for positional ones, and the
+	// name or number of named and numbered ones, e.g. "?,?" or ":id,$2".
+	// Two statements that differ only in how their arguments bind have the
+	// same Fingerprint but a different ParamShape.
+	ParamShape string
+	// Tables are, for a SELECT, the tables whose writes invalidate its
+	// result and, for any other statement, the tables it writes, whose
+	// cached results it invalidates. Names are lower-case and qualified
+	// as in the statement, so a cache that mixes schema-qualified and
+	// unqualified names must normalise them. Views are listed by name.
+	Tables []string
+	// Cacheable reports whether the result may be cached: only a SELECT
+	// that calls no volatile function such as NOW(), RAND() or nextval()
+	// and has no INSERT, UPDATE or DELETE in its WITH.
+	Cacheable bool
+	// InvalidatesAll is set for statements whose writes cannot be known,
+	// such as CALL, after which every cached result should be dropped.
+	InvalidatesAll bool
+	// Reason says why the statement is not cacheable.
+	Reason string
+}
+
+// volatileFuncs are the functions whose result can differ between two
+// runs of the same query on the same data, by lower-case name.
+var volatileFuncs = map[string]bool{
+	"now": true, "sysdate": true, "curdate": true, "curtime": true, "utc_date": true,
+	"utc_time": true, "utc_timestamp": true, "unix_timestamp": true, "current_timestamp": true,
+	"current_date": true, "current_time": true, "localtime": true, "localtimestamp": true,
+	"clock_timestamp": true, "statement_timestamp": true, "transaction_timestamp": true,
+	"timeofday": true, "rand": true, "random": true, "randomblob": true, "uuid": true, "uuid_short": true,
+	"gen_random_uuid": true, "uuid_generate_v4": true, "nextval": true, "currval": true,
+	"lastval": true, "setval": true, "last_insert_id": true, "last_insert_rowid": true,
+	"found_rows": true, "row_count": true, "changes": true, "connection_id": true,
+	"pg_backend_pid": true, "current_user": true, "session_user": true, "database": true,
+	"current_database": true, "current_schema": true, "sleep": true, "pg_sleep": true,
+	"get_lock": true, "release_lock": true, "txid_current": true,
+}
+
+// volatileNames are the SQL-standard functions written without
+// parentheses, which parse as column references.
+var volatileNames = map[string]bool{
+	"current_timestamp": true, "current_date": true, "current_time": true,
+	"localtime": true, "localtimestamp": true, "current_user": true, "session_user": true,
+}
+
+// CacheMetadata returns the result cache key of stmt and the tables it
+// depends on or invalidates. Tables come from the same resolution as
+// AuditColumnAccess and RequiredPrivileges: CTE names are not tables,
+// derived tables and subqueries contribute the tables they read, and a
+// write is any INSERT, UPDATE, DELETE, TRUNCATE, ALTER or DROP of a
+// table, plus CREATE OR REPLACE of a view.
+func CacheMetadata(stmt Statement) CacheInfo {
+	r := newDialectRenderer(ConvertOptions{Target: DialectPostgres})
+	r.recordParams = true
+	fingerprint, err := r.renderStatement(stmt)
+	info := CacheInfo{Fingerprint: fingerprint}
+	shape := make([]string, len(r.params))
+	for i, prm := range r.params {
+		shape[i] = string(prm.Raw)
+	}
+	info.ParamShape = strings.Join(shape, ",")
+	sum := sha256.Sum256([]byte(info.Fingerprint + "\x00" + info.ParamShape))
+	info.Key = hex.EncodeToString(sum[:])
+
+	tables := map[string]bool{}
+	switch s := stmt.(type) {
+	case *ast.SelectStmt:
+		info.Cacheable = true
+		volatile := func(name string) {
+			if info.Cacheable {
+				info.Cacheable = false
+				info.Reason = "calls " + name + ", whose result varies between runs"
+			}
+		}
+		a := &auditor{
+			visit: func(table, column string, kind accessKind) {
+				if kind != accessRead && info.Cacheable {
+					info.Cacheable = false
+					info.Reason = "it writes rows in WITH"
+				}
+				if table != "" {
+					tables[strings.ToLower(table)] = true
+				}
+				if volatileNames[strings.ToLower(column)] {
+					volatile(strings.ToUpper(column))
+				}
+			},
+			table: func(name string) { tables[strings.ToLower(name)] = true },
+			call: func(f *ast.FuncCall) {
+				if name := sequenceFunc(f); volatileFuncs[name] || sqliteNow(name, f) {
+					volatile(name + "()")
+				}
+			},
+		}
+		a.selectStmt(s, nil)
+	case *ast.CallStmt, *ast.DropDatabaseStmt:
+		info.InvalidatesAll = true
+		info.Reason = "its writes cannot be determined"
+	default:
+		info.Reason = "it is not a SELECT"
+		for _, p := range RequiredPrivileges(stmt) {
+			switch p.Action {
+			case "INSERT", "UPDATE", "DELETE", "TRUNCATE", "ALTER", "DROP":
+				if p.Scope == ScopeTable {
+					tables[strings.ToLower(p.Object)] = true
+				}
+			}
+		}
+		if v, ok := stmt.(*ast.CreateViewStmt); ok && v.OrReplace {
+			tables[strings.ToLower(qualifiedName(v.Name))] = true
+		}
+	}
+	if err != nil && info.Cacheable {
+		info.Cacheable = false
+		info.Reason = err.Error()
+	}
+	for t := range tables {
+		info.Tables = append(info.Tables, t)
+	}
+	slices.Sort(info.Tables)
+	return info
+}
+
+// sqliteNow reports whether f is one of SQLite's date and time functions
+// called on 'now'.
+func sqliteNow(name string, f *ast.FuncCall) bool {
+	switch name {
+	case "julianday", "unixepoch", "strftime":
+		return slices.ContainsFunc(f.Args, func(e ast.Expr) bool {
+			lit, ok := e.(*ast.Literal)
+			return ok && lit.Kind == lexer.STRING && strings.EqualFold(unquoteString(lit.Raw, ""), "now")
+		})
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package sqlparser_test
+
+import (
+	"reflect"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestCacheMetadata(t *testing.T) {
+	tests := []struct {
+		sql       string
+		tables    []string
+		cacheable bool
+		all       bool
+	}{
+		{"SELECT COUNT(*) FROM Orders o JOIN app.users u ON u.id = o.user_id WHERE o.id = ?", []string{"app.users", "orders"}, true, false},
+		{"WITH recent AS (SELECT * FROM orders) SELECT * FROM recent WHERE id IN (SELECT order_id FROM items)", []string{"items", "orders"}, true, false},
+		{"SELECT 1", nil, true, false},
+		{"SELECT id FROM sessions WHERE expires > NOW()", []string{"sessions"}, false, false},
+		{"SELECT CURRENT_DATE, id FROM t", []string{"t"}, false, false},
+		{"SELECT julianday('now') - julianday(created) FROM t", []string{"t"}, false, false},
+		{"SELECT strftime('%Y', created) FROM t", []string{"t"}, true, false},
+		{"WITH gone AS (DELETE FROM jobs WHERE done RETURNING id) SELECT id FROM gone", []string{"jobs"}, false, false},
+		{"UPDATE users SET total = 0 WHERE id IN (SELECT user_id FROM orders)", []string{"users"}, false, false},
+		{"INSERT INTO log (msg) SELECT name FROM users", []string{"log"}, false, false},
+		{"DELETE FROM sessions", []string{"sessions"}, false, false},
+		{"ALTER TABLE t RENAME TO u", []string{"t", "u"}, false, false},
+		{"CREATE OR REPLACE VIEW v AS SELECT * FROM t", []string{"v"}, false, false},
+		{"CALL archive()", nil, false, true},
+		{"BEGIN", nil, false, false},
+	}
+	for _, tt := range tests {
+		stmt, err := sqlparser.ParseStatement(tt.sql)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.sql, err)
+		}
+		info := sqlparser.CacheMetadata(stmt)
+		if !reflect.DeepEqual(info.Tables, tt.tables) || info.Cacheable != tt.cacheable || info.InvalidatesAll != tt.all {
+			t.Errorf("%s:\n got %q cacheable=%v all=%v (%s)\nwant %q cacheable=%v all=%v",
+				tt.sql, info.Tables, info.Cacheable, info.InvalidatesAll, info.Reason, tt.tables, tt.cacheable, tt.all)
+		}
+		if info.Cacheable != (info.Reason == "") {
+			t.Errorf("%s: cacheable=%v with reason %q", tt.sql, info.Cacheable, info.Reason)
+		}
+	}
+}
+
+func TestCacheMetadataKey(t *testing.T) {
+	key := func(sql string) sqlparser.CacheInfo {
+		stmt, err := sqlparser.ParseStatement(sql)
+		if err != nil {
+			t.Fatalf("%s: %v", sql, err)
+		}
+		return sqlparser.CacheMetadata(stmt)
+	}
+	a := key("select  id FROM `users` where id = ? and name = ?")
+	b := key(`SELECT id
+		FROM "users" -- by id
+		WHERE id = ? AND name = ?`)
+	if a.Key != b.Key || a.Fingerprint != `SELECT "id" FROM "users" WHERE (("id" = $1) AND ("name" = $2))` || a.ParamShape != "?,?" {
+		t.Fatalf("formatting changed the key:\n%+v\n%+v", a, b)
+	}
+	if c := key("SELECT id FROM users WHERE id = 1 AND name = ?"); c.Key == a.Key {
+		t.Errorf("a literal in place of a placeholder kept the key: %s", c.Fingerprint)
+	}
+	named := key("SELECT id FROM users WHERE id = :id AND name = :name")
+	swapped := key("SELECT id FROM users WHERE id = :name AND name = :id")
+	if named.Fingerprint != a.Fingerprint || named.Fingerprint != swapped.Fingerprint || named.Key == a.Key || named.Key == swapped.Key {
+		t.Errorf("parameter binding did not change the key:\n%+v\n%+v", named, swapped)
+	}
+}
@@ -0,0 +1,320 @@
+package sqlparser
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/ident"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// ChangeKind is the kind of row change a DML statement makes.
+type ChangeKind uint8
+
+const (
+	ChangeInsert ChangeKind = iota + 1
+	ChangeUpdate
+	ChangeDelete
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeInsert:
+		return "INSERT"
+	case ChangeUpdate:
+		return "UPDATE"
+	case ChangeDelete:
+		return "DELETE"
+	}
+	return "UNKNOWN"
+}
+
+// ChangeEvent describes the rows an INSERT, UPDATE or DELETE changes, for
+// outbox and change-data-capture layers that derive events from the SQL
+// they execute.
+type ChangeEvent struct {
+	Kind ChangeKind
+	// Table is the target table, qualified as in the statement.
+	Table string
+	// Columns are the columns an INSERT sets or an UPDATE assigns. They are
+	// nil for a DELETE, and for an INSERT without a column list on a table
+	// the schema does not know.
+	Columns []string
+	// Upsert is set for INSERT ... ON DUPLICATE KEY UPDATE and ON CONFLICT,
+	// which may update an existing row instead, and for REPLACE.
+	Upsert bool
+	// KeyColumns is the table's primary key, from the schema.
+	KeyColumns []string
+	// Keys identify the changed rows: one entry per VALUES row of an
+	// INSERT, or per key combination the WHERE clause of an UPDATE or
+	// DELETE pins with = or IN, each holding one value per KeyColumns
+	// entry. Keys is nil when the rows cannot be named that way: the
+	// primary key is unknown, an INSERT omits a key column or takes its
+	// rows from a SELECT, or a key column is not pinned to literals and
+	// placeholders.
+	Keys [][]ChangeValue
+}
+
+// ChangeValue is a key value as the statement gives it: a literal, or the
+// placeholder the value is bound to at execution.
+type ChangeValue struct {
+	// Value is the literal: nil for NULL, or an int64, float64, string or
+	// bool.
+	Value any
+	// Param is the placeholder as written, such as ?, $2 or :id, when the
+	// value is bound at execution.
+	Param string
+	// Arg is the index of the statement argument Param binds: the position
+	// of a ? among the statement's ? placeholders, or N-1 for $N. It is -1
+	// for literals and named placeholders.
+	Arg int
+}
+
+// DescribeChange returns the change event of a single-table INSERT, UPDATE
+// or DELETE, and false for any other statement, including multi-table
+// UPDATE and DELETE. schema is optional; without it, or when it lacks the
+// table, KeyColumns and Keys are nil.
+//
+// Key values of an UPDATE are those of the rows before the update, even
+// when it assigns a key column.
+func DescribeChange(stmt Statement, schema *Schema) (ChangeEvent, bool) {
+	var (
+		ev    ChangeEvent
+		table *ast.QualifiedIdent
+		alias string
+		where Expr
+	)
+	switch s := stmt.(type) {
+	case *ast.InsertStmt:
+		ev = ChangeEvent{Kind: ChangeInsert, Table: qualifiedName(s.Table)}
+		if len(s.Columns) > 0 {
+			ev.Columns = identNames(s.Columns)
+		}
+		ev.Upsert = s.Replace || len(s.OnDupKey) > 0 || len(s.OnConflictUpdate) > 0 || s.OnConflictDoNothing || len(s.OnConflictTarget) > 0 || s.OnConflictConstraint != nil
+		table = s.Table
+	case *ast.UpdateStmt:
+		t, ok := singleTable(s.Tables)
+		if !ok {
+			return ChangeEvent{}, false
+		}
+		ev = ChangeEvent{Kind: ChangeUpdate, Table: qualifiedName(t.Name)}
+		for _, a := range s.Set {
+			ev.Columns = append(ev.Columns, a.Column.Unquoted)
+		}
+		table, alias, where = t.Name, identName(t.Alias), s.Where
+	case *ast.DeleteStmt:
+		t, ok := singleTable(s.From)
+		if !ok || len(s.Tables) > 1 {
+			return ChangeEvent{}, false
+		}
+		ev = ChangeEvent{Kind: ChangeDelete, Table: qualifiedName(t.Name)}
+		table, alias, where = t.Name, identName(t.Alias), s.Where
+	default:
+		return ChangeEvent{}, false
+	}
+	var t *Table
+	if schema != nil {
+		t = schema.lookup(table)
+	}
+	if t == nil {
+		return ev, true
+	}
+	ev.KeyColumns = slices.Clone(t.PrimaryKey)
+	args := changeArgs(stmt)
+	switch s := stmt.(type) {
+	case *ast.InsertStmt:
+		if len(s.Columns) == 0 && !s.DefaultValues {
+			for _, c := range t.Columns {
+				if !c.Generated {
+					ev.Columns = append(ev.Columns, c.Name)
+				}
+			}
+		}
+		ev.Keys = insertKeys(s, ev.Columns, ev.KeyColumns, args, t.dialect)
+	default:
+		qualifier := alias
+		if qualifier == "" {
+			qualifier = table.Parts[len(table.Parts)-1].Unquoted
+		}
+		ev.Keys = whereKeys(where, qualifier, ev.KeyColumns, args, t.dialect)
+	}
+	return ev, true
+}
+
+// singleTable returns the table of a FROM list that names exactly one
+// table and no joins.
+func singleTable(refs []ast.TableRef) (*ast.SimpleTable, bool) {
+	if len(refs) != 1 {
+		return nil, false
+	}
+	t, ok := refs[0].(*ast.SimpleTable)
+	return t, ok
+}
+
+// changeArgs returns the source offsets of the ? placeholders of stmt in
+// order, which number its positional arguments.
+func changeArgs(stmt Statement) []int32 {
+	r := newDialectRenderer(ConvertOptions{})
+	r.recordParams = true
+	r.renderStatement(stmt)
+	var positional []int32
+	for _, prm := range r.params {
+		if string(prm.Raw) == "?" {
+			positional = append(positional, prm.TokPos)
+		}
+	}
+	slices.Sort(positional)
+	return positional
+}
+
+func insertKeys(s *ast.InsertStmt, cols, keyCols []string, args []int32, dialect Dialect) [][]ChangeValue {
+	if s.Select != nil || len(keyCols) == 0 {
+		return nil
+	}
+	idx := make([]int, len(keyCols))
+	for i, k := range keyCols {
+		idx[i] = slices.IndexFunc(cols, func(c string) bool { return ident.Equal(c, k, dialect) })
+		if idx[i] < 0 {
+			return nil
+		}
+	}
+	keys := make([][]ChangeValue, 0, len(s.Values))
+	for _, row := range s.Values {
+		key := make([]ChangeValue, len(idx))
+		for i, j := range idx {
+			if j >= len(row) {
+				return nil
+			}
+			v, ok := changeValue(row[j], args)
+			if !ok {
+				return nil
+			}
+			key[i] = v
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// whereKeys returns the key combinations the top-level AND terms of where
+// pin every key column to, comparing names as dialect does.
+func whereKeys(where Expr, qualifier string, keyCols []string, args []int32, dialect Dialect) [][]ChangeValue {
+	if len(keyCols) == 0 {
+		return nil
+	}
+	terms := andTerms(where, nil)
+	keys := [][]ChangeValue{nil}
+	for _, k := range keyCols {
+		values := pinnedValues(terms, qualifier, k, args, dialect)
+		if values == nil {
+			return nil
+		}
+		var next [][]ChangeValue
+		for _, key := range keys {
+			for _, v := range values {
+				next = append(next, append(slices.Clip(key), v))
+			}
+		}
+		keys = next
+	}
+	return keys
+}
+
+// andTerms appends the top-level AND terms of e to terms.
+func andTerms(e Expr, terms []Expr) []Expr {
+	if b, ok := e.(*ast.BinaryExpr); ok && (b.Op == lexer.AND || b.Op == lexer.DAMP) {
+		return andTerms(b.Right, andTerms(b.Left, terms))
+	}
+	return append(terms, e)
+}
+
+// pinnedValues returns the values the first term of the form col = v,
+// v = col or col IN (v, ...) gives column, or nil when there is none.
+func pinnedValues(terms []Expr, qualifier, column string, args []int32, dialect Dialect) []ChangeValue {
+	for _, term := range terms {
+		var exprs []Expr
+		switch e := term.(type) {
+		case *ast.BinaryExpr:
+			if e.Op != lexer.EQ {
+				continue
+			}
+			switch {
+			case isColumnRef(e.Left, qualifier, column, dialect):
+				exprs = []Expr{e.Right}
+			case isColumnRef(e.Right, qualifier, column, dialect):
+				exprs = []Expr{e.Left}
+			}
+		case *ast.InExpr:
+			if !e.Not && e.Subq == nil && isColumnRef(e.Expr, qualifier, column, dialect) {
+				exprs = e.List
+			}
+		}
+		if len(exprs) == 0 {
+			continue
+		}
+		values := make([]ChangeValue, 0, len(exprs))
+		for _, x := range exprs {
+			v, ok := changeValue(x, args)
+			if !ok {
+				return nil
+			}
+			values = append(values, v)
+		}
+		return values
+	}
+	return nil
+}
+
+// isColumnRef reports whether e names column, unqualified or qualified by
+// the target table's alias or name, as dialect compares names.
+func isColumnRef(e Expr, qualifier, column string, dialect Dialect) bool {
+	switch x := e.(type) {
+	case *ast.Ident:
+		return ident.Equal(x.Unquoted, column, dialect)
+	case *ast.QualifiedIdent:
+		n := len(x.Parts)
+		return n == 2 && ident.Equal(x.Parts[0].Unquoted, qualifier, dialect) && ident.Equal(x.Parts[1].Unquoted, column, dialect)
+	}
+	return false
+}
+
+// changeValue converts a literal or placeholder to a ChangeValue.
+func changeValue(e Expr, args []int32) (ChangeValue, bool) {
+	if prm, ok := e.(*ast.Param); ok {
+		v := ChangeValue{Param: string(prm.Raw), Arg: -1}
+		if n, err := paramArg(prm, args); err == nil {
+			v.Arg = n
+		}
+		return v, true
+	}
+	neg := false
+	if u, ok := e.(*ast.UnaryExpr); ok && u.Op == lexer.MINUS {
+		neg, e = true, u.Expr
+	}
+	switch x := e.(type) {
+	case *ast.NullLit:
+		return ChangeValue{Arg: -1}, !neg
+	case *ast.Literal:
+		raw := string(x.Raw)
+		if neg {
+			raw = "-" + raw
+		}
+		switch x.Kind {
+		case lexer.INT:
+			if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+				return ChangeValue{Value: n, Arg: -1}, true
+			}
+			// Too large for int64: keep the digits.
+			return ChangeValue{Value: raw, Arg: -1}, true
+		case lexer.FLOAT:
+			f, err := strconv.ParseFloat(raw, 64)
+			return ChangeValue{Value: f, Arg: -1}, err == nil
+		case lexer.STRING:
+			return ChangeValue{Value: unquoteString(x.Raw, ""), Arg: -1}, !neg
+		case lexer.TRUE_KW, lexer.FALSE_KW:
+			return ChangeValue{Value: x.Kind == lexer.TRUE_KW, Arg: -1}, !neg
+		}
+	}
+	return ChangeValue{}, false
+}
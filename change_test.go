@@ -0,0 +1,76 @@
+package sqlparser_test
+
+import (
+	"reflect"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestDescribeChange(t *testing.T) {
+	schema, err := sqlparser.BuildSchema(`
+		CREATE TABLE users (id BIGINT PRIMARY KEY, email TEXT, name TEXT);
+		CREATE TABLE memberships (org INT, usr INT, role TEXT, PRIMARY KEY (org, usr))`)
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	lit := func(v any) sqlparser.ChangeValue { return sqlparser.ChangeValue{Value: v, Arg: -1} }
+	arg := func(p string, n int) sqlparser.ChangeValue { return sqlparser.ChangeValue{Param: p, Arg: n} }
+	tests := []struct {
+		sql  string
+		want sqlparser.ChangeEvent
+	}{
+		{"INSERT INTO users (email, id) VALUES ('a@x', 1), (?, ?)", sqlparser.ChangeEvent{
+			Kind: sqlparser.ChangeInsert, Table: "users", Columns: []string{"email", "id"}, KeyColumns: []string{"id"},
+			Keys: [][]sqlparser.ChangeValue{{lit(int64(1))}, {arg("?", 1)}}}},
+		{"INSERT INTO users VALUES (-7, 'b@x', NULL) ON DUPLICATE KEY UPDATE name = 'x'", sqlparser.ChangeEvent{
+			Kind: sqlparser.ChangeInsert, Table: "users", Columns: []string{"id", "email", "name"}, Upsert: true,
+			KeyColumns: []string{"id"}, Keys: [][]sqlparser.ChangeValue{{lit(int64(-7))}}}},
+		{"INSERT INTO users (email) VALUES ('c@x')", sqlparser.ChangeEvent{
+			Kind: sqlparser.ChangeInsert, Table: "users", Columns: []string{"email"}, KeyColumns: []string{"id"}}},
+		{"UPDATE users u SET email = $2, name = 'n' WHERE u.id = $1 AND name IS NULL", sqlparser.ChangeEvent{
+			Kind: sqlparser.ChangeUpdate, Table: "users", Columns: []string{"email", "name"}, KeyColumns: []string{"id"},
+			Keys: [][]sqlparser.ChangeValue{{arg("$1", 0)}}}},
+		{"UPDATE users SET name = 'n' WHERE id > 5", sqlparser.ChangeEvent{
+			Kind: sqlparser.ChangeUpdate, Table: "users", Columns: []string{"name"}, KeyColumns: []string{"id"}}},
+		{"DELETE FROM memberships WHERE org = :org AND usr IN (1, 2)", sqlparser.ChangeEvent{
+			Kind: sqlparser.ChangeDelete, Table: "memberships", KeyColumns: []string{"org", "usr"},
+			Keys: [][]sqlparser.ChangeValue{{arg(":org", -1), lit(int64(1))}, {arg(":org", -1), lit(int64(2))}}}},
+		{"DELETE FROM app.audit WHERE id = 3", sqlparser.ChangeEvent{Kind: sqlparser.ChangeDelete, Table: "app.audit"}},
+	}
+	for _, tt := range tests {
+		stmt, err := sqlparser.ParseStatement(tt.sql)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.sql, err)
+		}
+		got, ok := sqlparser.DescribeChange(stmt, schema)
+		if !ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got %+v %v\nwant %+v", tt.sql, got, ok, tt.want)
+		}
+	}
+
+	// PostgreSQL tells the quoted "Id" key apart from the column id.
+	pg, err := sqlparser.BuildSchemaForDialect(`CREATE TABLE t ("Id" INT PRIMARY KEY, id INT)`, sqlparser.DialectPostgres)
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	for sql, keys := range map[string]int{`DELETE FROM t WHERE id = 1`: 0, `DELETE FROM t WHERE "Id" = 1`: 1} {
+		stmt, err := sqlparser.ParseStatement(sql)
+		if err != nil {
+			t.Fatalf("%s: %v", sql, err)
+		}
+		if got, _ := sqlparser.DescribeChange(stmt, pg); len(got.Keys) != keys {
+			t.Errorf("%s: keys %v, want %d", sql, got.Keys, keys)
+		}
+	}
+
+	for _, sql := range []string{"SELECT * FROM users", "UPDATE users u JOIN memberships m ON m.usr = u.id SET role = 'x'"} {
+		stmt, err := sqlparser.ParseStatement(sql)
+		if err != nil {
+			t.Fatalf("%s: %v", sql, err)
+		}
+		if ev, ok := sqlparser.DescribeChange(stmt, schema); ok {
+			t.Errorf("%s: unexpected event %+v", sql, ev)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package sqlparser
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// clickHouseTypes maps the lower-cased names of common SQL types to
+// ClickHouse's. Types missing here, ClickHouse's own among them, are
+// written as they are.
+var clickHouseTypes = map[string]string{
+	"tinyint": "Int8", "smallint": "Int16", "int2": "Int16", "mediumint": "Int32",
+	"int": "Int32", "integer": "Int32", "int4": "Int32", "bigint": "Int64",
+	"real": "Float32", "float4": "Float32", "float": "Float64", "float8": "Float64", "double": "Float64",
+	"bool": "Bool", "boolean": "Bool",
+	"char": "String", "varchar": "String", "nchar": "String", "nvarchar": "String",
+	"text": "String", "tinytext": "String", "mediumtext": "String", "longtext": "String",
+	"clob": "String", "json": "String", "jsonb": "String",
+	"binary": "String", "varbinary": "String", "bytea": "String",
+	"blob": "String", "tinyblob": "String", "mediumblob": "String", "longblob": "String",
+	"date": "Date", "datetime": "DateTime", "timestamp": "DateTime", "timestamptz": "DateTime",
+	"uuid": "UUID", "year": "UInt16", "citext": "String", "character": "String",
+	// PostgreSQL's own
+	"inet": "String", "cidr": "String", "macaddr": "String", "macaddr8": "String",
+	"interval": "String", "varbit": "String", "xml": "String", "tsvector": "String", "tsquery": "String",
+	"money": "Decimal(19, 2)", "hstore": "Map(String, String)", "oid": "UInt32",
+	// MySQL's CAST(x AS SIGNED) and CAST(x AS UNSIGNED)
+	"signed": "Int64", "unsigned": "UInt64",
+	// BigQuery's
+	"string": "String", "bytes": "String", "int64": "Int64", "float64": "Float64",
+}
+
+// analyticsTypes maps the type names of ClickHouse and BigQuery to the
+// type other targets get. INT8 is left out: it is one byte in ClickHouse
+// but postgres's name for BIGINT.
+var analyticsTypes = map[string]string{
+	"string": "TEXT", "int16": "SMALLINT", "int32": "INTEGER", "int64": "BIGINT",
+	"uint8": "SMALLINT", "uint16": "INTEGER", "uint32": "BIGINT", "uint64": "BIGINT",
+	"float32": "REAL", "float64": "DOUBLE PRECISION", "datetime64": "TIMESTAMP",
+}
+
+// portableType returns the type a ClickHouse or BigQuery type name becomes
+// for postgres, mysql and sqlite; the generic output keeps it.
+func (r *dialectRenderer) portableType(name string) (string, bool) {
+	typ, ok := analyticsTypes[strings.ToLower(name)]
+	if !ok || r.target == "" {
+		return "", false
+	}
+	if typ == "DOUBLE PRECISION" && r.target == DialectMySQL {
+		typ = "DOUBLE"
+	}
+	return typ, true
+}
+
+// renderClickHouseType renders a scalar type under its ClickHouse name:
+// sized integers, unsigned as UInt, every string and binary type as
+// String, timestamps with a precision as DateTime64 and an inline ENUM as
+// Enum8 or Enum16.
+func (r *dialectRenderer) renderClickHouseType(dt *ast.DataType) string {
+	name := string(dt.Name)
+	if isSerialType(dt) {
+		name = serialBaseType(name)
+	}
+	lower := strings.ToLower(name)
+	switch {
+	case len(dt.EnumVals) > 0 && lower == "enum":
+		return clickHouseEnum(dt.EnumVals)
+	case lower == "decimal" || lower == "numeric":
+		if dt.Precision == 0 {
+			return "Decimal(10, 0)"
+		}
+		return "Decimal(" + strconv.Itoa(dt.Precision) + ", " + strconv.Itoa(dt.Scale) + ")"
+	case dt.Precision > 0 && (lower == "datetime" || lower == "timestamp" || lower == "timestamptz"):
+		return "DateTime64(" + strconv.Itoa(dt.Precision) + ")"
+	}
+	ch, ok := clickHouseTypes[typeBase(lower)]
+	if !ok {
+		return r.renderDataTypeAs(dt, name)
+	}
+	r.warnTypeLoss(dt, ch)
+	if dt.Unsigned && strings.HasPrefix(ch, "Int") {
+		ch = "U" + ch
+	}
+	if dt.Zerofill {
+		r.warn(WarnZerofillDropped, dt.TokPos, "ZEROFILL dropped from %s", name)
+	}
+	return ch
+}
+
+// clickHouseEnum renders enum members as Enum8('a' = 1, ...), or Enum16
+// when there are more than Enum8 can number.
+func clickHouseEnum(vals [][]byte) string {
+	typ := "Enum8("
+	if len(vals) > 127 {
+		typ = "Enum16("
+	}
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		out[i] = quoteSQLString(unquoteString(v, "")) + " = " + strconv.Itoa(i+1)
+	}
+	return typ + strings.Join(out, ", ") + ")"
+}
+
+// renderNestedType renders an array or struct type: Array(T) and
+// Tuple(a T) in ClickHouse, T[] in postgres and the generic output, and
+// STRUCT(a T) in the generic output. Targets without the type store the
+// value as JSON.
+func (r *dialectRenderer) renderNestedType(dt *ast.DataType) string {
+	switch {
+	case r.target == DialectClickHouse && dt.Elem != nil:
+		return "Array(" + r.renderDataType(dt.Elem) + ")"
+	case r.target == DialectClickHouse:
+		return "Tuple(" + r.renderStructFields(dt.Fields) + ")"
+	case (r.target == DialectPostgres || r.target == "") && dt.Elem != nil:
+		elem := r.renderDataType(dt.Elem)
+		if dt.Elem.Fields != nil && r.target == DialectPostgres {
+			// The element already became JSONB, which holds the array too.
+			return elem
+		}
+		return elem + "[]"
+	case r.target == "":
+		return "STRUCT(" + r.renderStructFields(dt.Fields) + ")"
+	}
+	kind := "array"
+	if dt.Elem == nil {
+		kind = "struct"
+	}
+	json := "JSONB"
+	switch r.target {
+	case DialectMySQL:
+		json = "JSON"
+	case DialectSQLite:
+		json = "TEXT"
+	}
+	r.warn(WarnNestedTypeAsJSON, dt.TokPos, "%s has no %s type; %s became %s holding the value as JSON", r.target, kind, string(dt.Name), json)
+	return json
+}
+
+func (r *dialectRenderer) renderStructFields(fields []ast.StructField) string {
+	out := make([]string, len(fields))
+	for i, f := range fields {
+		out[i] = r.renderDataType(f.Type)
+		if f.Name != nil {
+			out[i] = r.renderIdent(f.Name) + " " + out[i]
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+// renderQualify renders QUALIFY, which only analytics engines have; other
+// targets keep it with a warning, since dropping the filter would return
+// rows the query excludes.
+func (r *dialectRenderer) renderQualify(s *ast.SelectStmt) string {
+	if r.target != DialectClickHouse && r.target != "" {
+		r.warn(WarnQualifyUnsupported, s.Qualify.Pos(), "%s has no QUALIFY; filter the window function in an outer query over a derived table instead", r.target)
+	}
+	return " QUALIFY " + r.renderExpr(s.Qualify)
+}
+
+// renderLimitBy renders ClickHouse's LIMIT n BY, kept with a warning for
+// the other targets, where ROW_NUMBER() OVER (PARTITION BY ...) in a
+// derived table does the same.
+func (r *dialectRenderer) renderLimitBy(lb *ast.LimitByClause) string {
+	if r.target != DialectClickHouse && r.target != "" {
+		r.warn(WarnLimitByUnsupported, lb.TokPos, "%s has no LIMIT ... BY; number the rows with ROW_NUMBER() OVER (PARTITION BY ...) in a derived table and filter on it instead", r.target)
+	}
+	var b strings.Builder
+	b.WriteString(" LIMIT " + r.renderLimit(lb.Count))
+	if lb.Offset != nil {
+		b.WriteString(" OFFSET " + r.renderLimit(lb.Offset))
+	}
+	b.WriteString(" BY ")
+	for i, e := range lb.By {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(r.renderExpr(e))
+	}
+	return b.String()
+}
@@ -0,0 +1,103 @@
+package sqlparser
+
+import (
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/ident"
+)
+
+// CoalesceInserts merges runs of consecutive INSERT ... VALUES statements
+// into the same table and columns into multi-row statements of at most
+// maxRows rows, the usual first step in speeding up an imported script. A
+// maxRows below 1 puts no limit on the merged statements.
+//
+// Statements merge only when they would have had the same effect run one
+// by one: they must agree on IGNORE, REPLACE and their ON DUPLICATE KEY
+// UPDATE or ON CONFLICT clauses, rows keep their order, and any other
+// statement ends the run. Statements with placeholders are left alone,
+// since each binds its own arguments, as are those with ON CONFLICT DO
+// UPDATE, which PostgreSQL rejects when one statement updates a row twice.
+//
+// The input statements are not modified; merged statements are new ones.
+func CoalesceInserts(stmts []Statement, maxRows int) []Statement {
+	return coalesceInserts(stmts, maxRows, "")
+}
+
+// coalesceInserts is CoalesceInserts for statements whose names compare
+// as they do in dialect.
+func coalesceInserts(stmts []Statement, maxRows int, dialect Dialect) []Statement {
+	r := newDialectRenderer(ConvertOptions{})
+	r.recordParams = true
+	out := make([]Statement, 0, len(stmts))
+	var (
+		run    *ast.InsertStmt // the statement being merged into
+		merged bool            // whether run is a copy that owns its Values
+	)
+	for _, stmt := range stmts {
+		s, ok := stmt.(*ast.InsertStmt)
+		if !ok || !r.coalescible(s) {
+			run = nil
+			out = append(out, stmt)
+			continue
+		}
+		if run != nil && r.sameInsertShape(run, s, dialect) && (maxRows < 1 || len(run.Values)+len(s.Values) <= maxRows) {
+			if !merged {
+				cp := *run
+				cp.Values = append([][]ast.Expr(nil), run.Values...)
+				run, merged = &cp, true
+				out[len(out)-1] = run
+			}
+			run.Values = append(run.Values, s.Values...)
+			continue
+		}
+		run, merged = s, false
+		out = append(out, stmt)
+	}
+	return out
+}
+
+// coalescible reports whether s is an INSERT ... VALUES that may be merged
+// with its neighbours.
+func (r *dialectRenderer) coalescible(s *ast.InsertStmt) bool {
+	if s.Select != nil || s.With != nil || len(s.Values) == 0 || len(s.OnConflictUpdate) > 0 || s.OnConflictConstraint != nil || s.OnConflictWhere != nil || len(s.Returning) > 0 {
+		return false
+	}
+	r.params = r.params[:0]
+	for _, row := range s.Values {
+		for _, e := range row {
+			r.renderExpr(e)
+		}
+	}
+	for _, a := range s.OnDupKey {
+		r.renderExpr(a.Value)
+	}
+	return len(r.params) == 0
+}
+
+// sameInsertShape reports whether b inserts into the same table and columns
+// as a, with the same modifiers and upsert clauses.
+func (r *dialectRenderer) sameInsertShape(a, b *ast.InsertStmt, dialect Dialect) bool {
+	if !ident.Equal(qualifiedName(a.Table), qualifiedName(b.Table), dialect) || a.Ignore != b.Ignore || a.Replace != b.Replace ||
+		a.OnConflictDoNothing != b.OnConflictDoNothing || len(a.Values[0]) != len(b.Values[0]) ||
+		!sameIdents(a.Columns, b.Columns, dialect) || !sameIdents(a.OnConflictTarget, b.OnConflictTarget, dialect) || len(a.OnDupKey) != len(b.OnDupKey) {
+		return false
+	}
+	for i := range a.OnDupKey {
+		x, y := a.OnDupKey[i], b.OnDupKey[i]
+		if !ident.Equal(x.Column.Unquoted, y.Column.Unquoted, dialect) || r.renderExpr(x.Value) != r.renderExpr(y.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameIdents(a, b []*ast.Ident, dialect Dialect) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !ident.Equal(a[i].Unquoted, b[i].Unquoted, dialect) {
+			return false
+		}
+	}
+	return true
+}
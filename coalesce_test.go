@@ -0,0 +1,59 @@
+package sqlparser_test
+
+import (
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestCoalesceInserts(t *testing.T) {
+	stmts, err := sqlparser.ParseStatements(`
+		INSERT INTO users (id, name) VALUES (1, 'a');
+		INSERT INTO Users (ID, Name) VALUES (2, 'b'), (3, 'c');
+		INSERT INTO users (id, name) VALUES (4, 'd');
+		INSERT INTO users (id, name) VALUES (5, 'e');
+		INSERT INTO users (name, id) VALUES ('f', 6);
+		INSERT INTO users (name, id) VALUES ('g', 7);
+		UPDATE users SET name = 'x' WHERE id = 1;
+		INSERT INTO users (name, id) VALUES ('h', 8);
+		INSERT INTO users (id, name) VALUES (9, 'i') ON DUPLICATE KEY UPDATE name = CONCAT(name, '!');
+		INSERT INTO users (id, name) VALUES (10, 'j') ON DUPLICATE KEY UPDATE name = CONCAT(name, '!');
+		INSERT INTO users (id, name) VALUES (11, 'k') ON DUPLICATE KEY UPDATE name = 'k';
+		INSERT INTO users (id, name) VALUES (12, ?);
+		INSERT INTO users (id, name) VALUES (13, ?);
+		INSERT IGNORE INTO users (id, name) VALUES (14, 'n');
+		INSERT INTO users (id, name) VALUES (15, 'o') ON CONFLICT (id) DO UPDATE SET name = 'o';
+		INSERT INTO users (id, name) VALUES (16, 'p') ON CONFLICT (id) DO UPDATE SET name = 'o'`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	got := sqlparser.CoalesceInserts(stmts, 3)
+	want := []string{
+		"INSERT INTO `users` (`id`, `name`) VALUES (1, 'a'), (2, 'b'), (3, 'c')",
+		"INSERT INTO `users` (`id`, `name`) VALUES (4, 'd'), (5, 'e')",
+		"INSERT INTO `users` (`name`, `id`) VALUES ('f', 6), ('g', 7)",
+		"UPDATE `users` SET `name` = 'x' WHERE (`id` = 1)",
+		"INSERT INTO `users` (`name`, `id`) VALUES ('h', 8)",
+		"INSERT INTO `users` (`id`, `name`) VALUES (9, 'i'), (10, 'j') ON DUPLICATE KEY UPDATE `name` = CONCAT(`name`, '!')",
+		"INSERT INTO `users` (`id`, `name`) VALUES (11, 'k') ON DUPLICATE KEY UPDATE `name` = 'k'",
+		"INSERT INTO `users` (`id`, `name`) VALUES (12, ?)",
+		"INSERT INTO `users` (`id`, `name`) VALUES (13, ?)",
+		"INSERT IGNORE INTO `users` (`id`, `name`) VALUES (14, 'n')",
+	}
+	if len(got) != len(want)+2 {
+		t.Fatalf("got %d statements, want %d", len(got), len(want)+2)
+	}
+	mysql := sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL}
+	for i, w := range want {
+		out, _, err := sqlparser.ConvertStatements(got[i:i+1], mysql)
+		if err != nil || out != w {
+			t.Errorf("statement %d:\n got %s %v\nwant %s", i, out, err, w)
+		}
+	}
+	if out, _, _ := sqlparser.ConvertStatements(stmts[:1], mysql); out != "INSERT INTO `users` (`id`, `name`) VALUES (1, 'a')" {
+		t.Errorf("the input statement was modified: %s", out)
+	}
+	if n := len(sqlparser.CoalesceInserts(stmts, 0)); n != 11 {
+		t.Errorf("without a limit got %d statements, want 11", n)
+	}
+}
@@ -0,0 +1,66 @@
+package sqlparser
+
+import (
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// QueryComplexity measures how expensive a statement looks before it runs,
+// for gateways that rate-limit or route queries by shape.
+type QueryComplexity struct {
+	// Joins counts JOIN clauses and the comma-separated FROM items after
+	// the first.
+	Joins int `json:"joins"`
+	// Subqueries counts the SELECT blocks nested in the statement:
+	// subqueries, derived tables and CTE bodies, each side of a UNION
+	// counting once.
+	Subqueries int `json:"subqueries"`
+	// SubqueryDepth is how deeply they nest: 0 without subqueries, 1 when
+	// none contains another.
+	SubqueryDepth int `json:"subqueryDepth"`
+	// Nodes counts the expression nodes the statement evaluates, including
+	// those of its subqueries.
+	Nodes int `json:"nodes"`
+	// Aggregates counts calls of aggregate functions such as COUNT and SUM.
+	Aggregates int `json:"aggregates"`
+	// Score combines the counts into one number for thresholds:
+	// Nodes + 10 per join, 15 per subquery, 25 per level of subquery depth
+	// and 5 per aggregate. The weights are a heuristic and may change.
+	Score int `json:"score"`
+}
+
+// Complexity returns the complexity of a SELECT, INSERT, UPDATE or DELETE
+// statement, or of the query of CREATE VIEW and CREATE TABLE ... AS. Other
+// statements measure zero.
+func Complexity(stmt Statement) QueryComplexity {
+	var c QueryComplexity
+	a := &auditor{visit: func(string, string, accessKind) {}}
+	a.node = func(n ast.Node, depth int) {
+		c.SubqueryDepth = max(c.SubqueryDepth, depth)
+		switch x := n.(type) {
+		case *ast.SelectStmt:
+			if depth > 0 {
+				c.Subqueries++
+			}
+			c.Joins += max(len(x.From)-1, 0)
+		case *ast.JoinTable:
+			c.Joins++
+		case *ast.QualifiedIdent:
+			// A one-part name is walked again as its Ident.
+			if len(x.Parts) > 1 {
+				c.Nodes++
+			}
+		case *ast.FuncCall:
+			c.Nodes++
+			if x.Name != nil && len(x.Name.Parts) == 1 && aggregateFuncs[strings.ToUpper(x.Name.Parts[0].Unquoted)] {
+				c.Aggregates++
+			}
+		case ast.Expr:
+			c.Nodes++
+		}
+	}
+	a.statement(stmt)
+	c.Score = c.Nodes + 10*c.Joins + 15*c.Subqueries + 25*c.SubqueryDepth + 5*c.Aggregates
+	return c
+}
@@ -0,0 +1,33 @@
+package sqlparser_test
+
+import (
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestComplexity(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want sqlparser.QueryComplexity
+	}{
+		{"SELECT id FROM users WHERE id = 1", sqlparser.QueryComplexity{Nodes: 4, Score: 4}},
+		{"SELECT u.name, COUNT(*) FROM users u JOIN orders o ON o.user_id = u.id, teams GROUP BY u.name",
+			sqlparser.QueryComplexity{Joins: 2, Nodes: 6, Aggregates: 1, Score: 31}},
+		{`WITH recent AS (SELECT user_id FROM orders WHERE placed > ?)
+			SELECT name FROM users WHERE id IN (SELECT user_id FROM recent WHERE EXISTS (SELECT 1 FROM audits a WHERE a.user_id = recent.user_id))`,
+			sqlparser.QueryComplexity{Subqueries: 3, SubqueryDepth: 2, Nodes: 13, Score: 108}},
+		{"UPDATE users SET score = (SELECT SUM(points) FROM events e WHERE e.user_id = users.id)",
+			sqlparser.QueryComplexity{Subqueries: 1, SubqueryDepth: 1, Nodes: 6, Aggregates: 1, Score: 51}},
+		{"CREATE TABLE t (id INT)", sqlparser.QueryComplexity{}},
+	}
+	for _, tt := range tests {
+		stmt, err := sqlparser.ParseStatement(tt.sql)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.sql, err)
+		}
+		if got := sqlparser.Complexity(stmt); got != tt.want {
+			t.Errorf("%s:\n got %+v\nwant %+v", tt.sql, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Package corpus runs the parser over directories of SQL files and
+// reports how much of them parses, how fast and with how many
+// allocations, so a test can catch dialect coverage regressions:
+//
+//	func TestCorpus(t *testing.T) {
+//		corpus.Check(t, "testdata/sql", corpus.Options{
+//			KnownFailures: []string{"mssql/merge.sql"},
+//		})
+//	}
+//
+// Every statement is parsed on its own, so one that fails does not hide
+// the rest of its file.
+package corpus
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"text/tabwriter"
+	"time"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+// FileResult is the outcome of parsing one file of a corpus.
+type FileResult struct {
+	// Path is the file's path relative to the corpus directory, with
+	// forward slashes.
+	Path string
+	// Statements counts the statements that parsed; Errors holds the parse
+	// errors of the others, positioned within the file.
+	Statements int
+	Errors     []error
+	Bytes      int
+	Duration   time.Duration
+	// Allocs and AllocBytes are the heap allocations made while parsing.
+	Allocs     uint64
+	AllocBytes uint64
+}
+
+// Report is the outcome of Run over a corpus.
+type Report struct {
+	Files      []FileResult
+	Statements int
+	Failed     int
+	Bytes      int64
+	Duration   time.Duration
+	Allocs     uint64
+	AllocBytes uint64
+}
+
+// SuccessRate is the share of the corpus's statements that parsed, from 0
+// to 1; an empty corpus has a rate of 1.
+func (r *Report) SuccessRate() float64 {
+	total := r.Statements + r.Failed
+	if total == 0 {
+		return 1
+	}
+	return float64(r.Statements) / float64(total)
+}
+
+// String renders the report as a table with a line per file and a total.
+func (r *Report) String() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "file\tparsed\tfailed\tbytes\ttime\tallocs\talloc bytes\t")
+	for _, f := range r.Files {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%d\t%d\t\n", f.Path, f.Statements, len(f.Errors), f.Bytes, f.Duration.Round(time.Microsecond), f.Allocs, f.AllocBytes)
+	}
+	fmt.Fprintf(w, "total\t%d\t%d\t%d\t%s\t%d\t%d\t\n", r.Statements, r.Failed, r.Bytes, r.Duration.Round(time.Microsecond), r.Allocs, r.AllocBytes)
+	w.Flush()
+	fmt.Fprintf(&b, "%.1f%% of %d statements parsed", 100*r.SuccessRate(), r.Statements+r.Failed)
+	if r.Duration > 0 {
+		fmt.Fprintf(&b, ", %.1f MB/s", float64(r.Bytes)/r.Duration.Seconds()/1e6)
+	}
+	return b.String()
+}
+
+// Load returns the paths of the .sql files under dir, relative to it and
+// sorted.
+func Load(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".sql") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	slices.Sort(paths)
+	return paths, err
+}
+
+// Run parses every .sql file under dir.
+func Run(dir string) (*Report, error) {
+	paths, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{}
+	for _, path := range paths {
+		src, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+		if err != nil {
+			return nil, err
+		}
+		f := parseFile(src)
+		f.Path = path
+		report.Files = append(report.Files, f)
+		report.Statements += f.Statements
+		report.Failed += len(f.Errors)
+		report.Bytes += int64(f.Bytes)
+		report.Duration += f.Duration
+		report.Allocs += f.Allocs
+		report.AllocBytes += f.AllocBytes
+	}
+	return report, nil
+}
+
+func parseFile(src []byte) FileResult {
+	f := FileResult{Bytes: len(src)}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for _, err := range sqlparser.NewStreamParser(bytes.NewReader(src)).Iter() {
+		if err != nil {
+			f.Errors = append(f.Errors, err)
+		} else {
+			f.Statements++
+		}
+	}
+	f.Duration = time.Since(start)
+	runtime.ReadMemStats(&after)
+	f.Allocs = after.Mallocs - before.Mallocs
+	f.AllocBytes = after.TotalAlloc - before.TotalAlloc
+	return f
+}
+
+// Options says what Check requires of a corpus.
+type Options struct {
+	// MinSuccessRate is the share of statements, from 0 to 1, that must
+	// parse. Zero instead requires every statement outside KnownFailures
+	// to parse.
+	MinSuccessRate float64
+	// KnownFailures lists files, as paths relative to the corpus directory
+	// with forward slashes, that are expected to have statements that fail
+	// to parse. A known failure that parses cleanly is logged so it can be
+	// removed from the list.
+	KnownFailures []string
+}
+
+// Check runs the corpus under dir as part of a test: it logs the report
+// and fails t for every file with unexpected parse errors, listing them,
+// and when the success rate is below opts.MinSuccessRate.
+func Check(t testing.TB, dir string, opts Options) *Report {
+	t.Helper()
+	report, err := Run(dir)
+	if err != nil {
+		t.Fatalf("corpus %s: %v", dir, err)
+	}
+	if len(report.Files) == 0 {
+		t.Fatalf("corpus %s has no .sql files", dir)
+	}
+	t.Logf("corpus %s:\n%s", dir, report)
+	for _, f := range report.Files {
+		known := slices.Contains(opts.KnownFailures, f.Path)
+		switch {
+		case len(f.Errors) > 0 && !known && opts.MinSuccessRate == 0:
+			var msgs []string
+			for _, err := range f.Errors {
+				msgs = append(msgs, "  "+err.Error())
+			}
+			t.Errorf("%s: %d of %d statements failed to parse:\n%s", f.Path, len(f.Errors), f.Statements+len(f.Errors), strings.Join(msgs, "\n"))
+		case len(f.Errors) == 0 && known:
+			t.Logf("%s parses cleanly now; remove it from KnownFailures", f.Path)
+		}
+	}
+	if rate := report.SuccessRate(); opts.MinSuccessRate > 0 && rate < opts.MinSuccessRate {
+		t.Errorf("corpus %s: %.1f%% of statements parsed, below the required %.1f%%", dir, 100*rate, 100*opts.MinSuccessRate)
+	}
+	return report
+}
+
+// Benchmark parses the corpus under dir b.N times, reporting its
+// throughput in bytes per second.
+func Benchmark(b *testing.B, dir string) {
+	paths, err := Load(dir)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var files [][]byte
+	var size int64
+	for _, path := range paths {
+		src, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+		if err != nil {
+			b.Fatal(err)
+		}
+		files = append(files, src)
+		size += int64(len(src))
+	}
+	b.SetBytes(size)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		for _, src := range files {
+			for range sqlparser.NewStreamParser(bytes.NewReader(src)).Iter() {
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package corpus_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/oarkflow/sqlparser/corpus"
+)
+
+const examples = "../examples/sql"
+
+func TestExamples(t *testing.T) {
+	report := corpus.Check(t, examples, corpus.Options{})
+	if report.Statements == 0 || report.Failed != 0 || report.Bytes == 0 {
+		t.Errorf("report: %+v", report)
+	}
+}
+
+func BenchmarkExamples(b *testing.B) {
+	corpus.Benchmark(b, examples)
+}
+
+// recorder collects the failures Check reports.
+type recorder struct {
+	testing.TB
+	errors, logs []string
+}
+
+func (r *recorder) Helper() {}
+func (r *recorder) Errorf(format string, a ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, a...))
+}
+func (r *recorder) Logf(format string, a ...any) {
+	r.logs = append(r.logs, fmt.Sprintf(format, a...))
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"ok.sql":           "SELECT 1; SELECT id FROM users;",
+		"mysql/broken.sql": "SELECT 1;\nSELECT FROM;\nDELETE FROM t WHERE",
+		"pg/fixed.sql":     "UPDATE t SET a = 1",
+		"notes.txt":        "not SQL",
+	}
+	for name, sql := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(sql), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := corpus.Run(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, f := range report.Files {
+		paths = append(paths, fmt.Sprintf("%s:%d/%d", f.Path, f.Statements, len(f.Errors)))
+	}
+	if got := strings.Join(paths, " "); got != "mysql/broken.sql:1/2 ok.sql:2/0 pg/fixed.sql:1/0" {
+		t.Errorf("files: %s", got)
+	}
+	if report.Statements != 4 || report.Failed != 2 || report.SuccessRate() != 4.0/6 {
+		t.Errorf("totals: %d parsed, %d failed", report.Statements, report.Failed)
+	}
+	if err := report.Files[0].Errors[0].Error(); !strings.Contains(err, "line 2") {
+		t.Errorf("error position: %s", err)
+	}
+
+	r := &recorder{TB: t}
+	corpus.Check(r, dir, corpus.Options{})
+	if len(r.errors) != 1 || !strings.HasPrefix(r.errors[0], "mysql/broken.sql: 2 of 3 statements failed to parse") {
+		t.Errorf("unexpected failure: %v", r.errors)
+	}
+
+	r = &recorder{TB: t}
+	corpus.Check(r, dir, corpus.Options{KnownFailures: []string{"mysql/broken.sql", "pg/fixed.sql"}})
+	if len(r.errors) != 0 || !strings.Contains(strings.Join(r.logs, "\n"), "pg/fixed.sql parses cleanly now") {
+		t.Errorf("known failures: %v %v", r.errors, r.logs)
+	}
+
+	r = &recorder{TB: t}
+	corpus.Check(r, dir, corpus.Options{MinSuccessRate: 0.9})
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "66.7% of statements parsed, below the required 90.0%") {
+		t.Errorf("success rate: %v", r.errors)
+	}
+}
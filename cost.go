@@ -0,0 +1,533 @@
+package sqlparser
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/ident"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// DefaultMaxScanRows is the scan size above which the analyzer reports
+// LARGE_SCAN when Statistics.MaxScanRows is zero.
+const DefaultMaxScanRows = 1_000_000
+
+// Selectivities of predicates on columns without statistics, PostgreSQL's
+// defaults, and the number of groups a GROUP BY is assumed to produce.
+const (
+	eqSelectivity    = 0.005
+	ineqSelectivity  = 1.0 / 3
+	rangeSelectivity = 0.005
+	defaultGroups    = 200
+)
+
+// Statistics are the table sizes the analyzer estimates query costs from,
+// standing in for a planner's catalog when there is no database connection.
+type Statistics struct {
+	// Rows is the approximate row count of each table, keyed by its name
+	// as queries write it, qualified or not; keys match as the schema's
+	// dialect compares names, without regard to case when there is none.
+	// Tables without an entry are left out of the estimates.
+	Rows map[string]int64
+	// MaxScanRows is how many rows a statement may read before LARGE_SCAN
+	// and LARGE_RESULT are reported. Zero means DefaultMaxScanRows.
+	MaxScanRows int64
+}
+
+// CostEstimate is the estimated size of running a statement.
+type CostEstimate struct {
+	// ScannedRows is how many rows the statement reads from the tables
+	// with statistics.
+	ScannedRows int64 `json:"scannedRows"`
+	// ResultRows is how many rows it returns, inserts, updates or deletes.
+	ResultRows int64 `json:"resultRows"`
+	// FullScans lists the tables it reads in full, as written.
+	FullScans []string `json:"fullScans,omitempty"`
+}
+
+// EstimateCost estimates how many rows a SELECT, INSERT, UPDATE or DELETE
+// reads and produces, the way a planner would without column statistics.
+//
+// Each FROM item is filtered by the WHERE and ON terms that compare one of
+// its columns with a constant, using PostgreSQL's default selectivities.
+// A table is read in full unless one of those columns, or for the tables
+// after the first the column it is joined on, leads an index of schema;
+// without a schema every table is read in full. An equi-join produces
+// |A|·|B| / min(rows of A, rows of B) rows, the size of a foreign-key
+// join, and FROM items that no term connects multiply. LIMIT caps the
+// result but not the scan, GROUP BY yields 200 groups and an aggregate
+// without it one row. Subqueries in expressions are costed once, even when
+// correlated.
+func EstimateCost(stmt Statement, stats *Statistics, schema *Schema) CostEstimate {
+	c := &costEstimator{stats: stats, schema: schema, rows: map[string]int64{}, ctes: map[string]float64{}}
+	if stats != nil {
+		for name, n := range stats.Rows {
+			c.rows[c.key(name)] = n
+		}
+	}
+	var result float64
+	switch s := stmt.(type) {
+	case *ast.SelectStmt:
+		result, _ = c.query(s)
+	case *ast.InsertStmt:
+		c.with(s.With)
+		if s.Select != nil {
+			result, _ = c.query(s.Select)
+		} else if s.DefaultValues {
+			result = 1
+		} else {
+			result = float64(len(s.Values))
+		}
+	case *ast.UpdateStmt:
+		c.with(s.With)
+		result, _ = c.block(s.Tables, s.Where)
+		result = limitRows(result, s.Limit)
+	case *ast.DeleteStmt:
+		c.with(s.With)
+		result, _ = c.block(s.From, s.Where)
+		result = limitRows(result, s.Limit)
+	}
+	c.est.ScannedRows = int64(math.Round(c.scanned))
+	c.est.ResultRows = int64(math.Round(result))
+	return c.est
+}
+
+type costEstimator struct {
+	stats  *Statistics
+	schema *Schema
+	// rows are the statistics by key, and ctes the estimated
+	// sizes of the CTEs in scope, -1 for those reading no table with
+	// statistics.
+	rows    map[string]int64
+	ctes    map[string]float64
+	scanned float64
+	est     CostEstimate
+}
+
+// costRelation is one table or derived table of a FROM clause.
+type costRelation struct {
+	name  string
+	names []string // the keys of the qualifiers of its columns
+	table *Table
+	// rows is its size before filtering, filtered after the terms that
+	// compare its columns with constants.
+	rows, filtered float64
+	// stored is set for base tables, which are scanned; derived tables and
+	// CTEs were costed where they are defined.
+	stored bool
+	// indexed is set when a filtered column leads an index.
+	indexed bool
+}
+
+// key returns the form of a name under which the schema's dialect
+// compares it; see ident.Key.
+func (c *costEstimator) key(name string) string {
+	return ident.Key(name, c.schema.identDialect())
+}
+
+func (c *costEstimator) with(w *ast.WithClause) {
+	if w == nil {
+		return
+	}
+	for _, cte := range w.CTEs {
+		rows, known := c.query(cte.Subq)
+		if !known {
+			rows = -1
+		}
+		c.ctes[c.key(cte.Name.Unquoted)] = rows
+	}
+}
+
+// query costs a SELECT or set operation and returns its rows, and
+// whether any of its blocks could be estimated.
+func (c *costEstimator) query(s *ast.SelectStmt) (float64, bool) {
+	if s == nil {
+		return 0, false
+	}
+	if s.SetOp == nil {
+		return c.core(s)
+	}
+	c.with(s.With)
+	var total float64
+	known := false
+	for _, operand := range []*ast.SelectStmt{s.SetOp.Left, s.SetOp.Right} {
+		if rows, ok := c.query(operand); ok {
+			total += rows
+			known = true
+		}
+	}
+	return limitRows(total, s.Limit), known
+}
+
+// core costs one SELECT block and its CTEs.
+func (c *costEstimator) core(s *ast.SelectStmt) (float64, bool) {
+	c.with(s.With)
+	if s.Values != nil {
+		return limitRows(float64(len(s.Values)), s.Limit), true
+	}
+	result, known := c.block(s.From, s.Where)
+	aggregate := false
+	for _, col := range s.Columns {
+		if f, ok := col.Expr.(*ast.FuncCall); ok && f.Over == nil && f.Name != nil && len(f.Name.Parts) == 1 && aggregateFuncs[strings.ToUpper(f.Name.Parts[0].Unquoted)] {
+			aggregate = true
+		}
+		c.subqueries(col.Expr)
+	}
+	c.subqueries(s.Having)
+	switch {
+	case len(s.GroupBy) > 0:
+		result = min(result, defaultGroups)
+	case aggregate:
+		result = 1
+	}
+	return limitRows(result, s.Limit), known
+}
+
+func limitRows(rows float64, l *ast.LimitClause) float64 {
+	if l == nil {
+		return rows
+	}
+	if lit, ok := l.Count.(*ast.Literal); ok && lit.Kind == lexer.INT {
+		if n, err := strconv.ParseFloat(string(lit.Raw), 64); err == nil {
+			return min(rows, n)
+		}
+	}
+	return rows
+}
+
+// subqueries costs the queries nested in e.
+func (c *costEstimator) subqueries(e ast.Expr) {
+	if e == nil {
+		return
+	}
+	a := &auditor{visit: func(string, string, accessKind) {}}
+	a.node = func(n ast.Node, depth int) {
+		if s, ok := n.(*ast.SelectStmt); ok && depth == 1 {
+			c.core(s)
+		}
+	}
+	a.expr(e, &auditScope{})
+}
+
+// block costs a FROM clause filtered by where and returns the rows it
+// produces, and whether it has an item with statistics or no items at all.
+func (c *costEstimator) block(from []ast.TableRef, where ast.Expr) (float64, bool) {
+	var rels []*costRelation
+	var terms []ast.Expr
+	var edges [][2]int
+	// items counts the FROM items, including those without statistics.
+	items := 0
+	var flatten func(ast.TableRef)
+	flatten = func(ref ast.TableRef) {
+		switch t := ref.(type) {
+		case *ast.SimpleTable:
+			items++
+			rel := c.relation(t)
+			if rel != nil {
+				rels = append(rels, rel)
+			}
+		case *ast.SubqueryTable:
+			items++
+			rows, known := c.query(t.Subq)
+			if !known {
+				return
+			}
+			rel := &costRelation{rows: rows}
+			if t.Alias != nil {
+				rel.name = t.Alias.Unquoted
+				rel.names = []string{c.key(t.Alias.Unquoted)}
+			}
+			rels = append(rels, rel)
+		case *ast.PivotTable:
+			items++
+		case *ast.JoinTable:
+			flatten(t.Left)
+			mid := len(rels)
+			flatten(t.Right)
+			if len(t.Using) > 0 && mid > 0 && mid < len(rels) {
+				edges = append(edges, [2]int{mid - 1, mid})
+			}
+			terms = andTerms(t.On, terms)
+		}
+	}
+	for _, ref := range from {
+		flatten(ref)
+	}
+	terms = andTerms(where, terms)
+	c.subqueries(where)
+	if items == 0 {
+		return 1, true
+	}
+
+	for _, rel := range rels {
+		rel.filtered = rel.rows
+	}
+	// joinColumns are the columns each relation is joined on.
+	joinColumns := make([][]string, len(rels))
+	for _, term := range terms {
+		if b, ok := term.(*ast.BinaryExpr); ok && b.Op == lexer.EQ && bareColumn(b.Left) != nil && bareColumn(b.Right) != nil {
+			l, r := c.owner(rels, items, b.Left), c.owner(rels, items, b.Right)
+			if l >= 0 && r >= 0 && l != r {
+				edges = append(edges, [2]int{l, r})
+				joinColumns[l] = append(joinColumns[l], columnName(b.Left))
+				joinColumns[r] = append(joinColumns[r], columnName(b.Right))
+			}
+			continue
+		}
+		col, sel, seek := termSelectivity(term)
+		if col == nil {
+			continue
+		}
+		i := c.owner(rels, items, col)
+		if i < 0 {
+			continue
+		}
+		rel := rels[i]
+		name := columnName(col)
+		if b, ok := term.(*ast.BinaryExpr); ok && b.Op == lexer.EQ && uniqueColumn(rel.table, name) && rel.rows > 0 {
+			sel = 1 / rel.rows
+		}
+		rel.filtered *= sel
+		if seek && leadsIndex(rel.table, name) {
+			rel.indexed = true
+		}
+	}
+
+	var result float64
+	joined := make([]bool, len(rels))
+	for i, rel := range rels {
+		rel.filtered = max(rel.filtered, min(rel.rows, 1))
+		lookup := false
+		switch partner := joinedWith(edges, joined, i); {
+		case i == 0:
+			result = rel.filtered
+		case partner >= 0:
+			result = result * rel.filtered / max(min(rel.rows, rels[partner].rows), 1)
+			for _, col := range joinColumns[i] {
+				lookup = lookup || leadsIndex(rel.table, col)
+			}
+		default:
+			result *= rel.filtered
+		}
+		joined[i] = true
+		if !rel.stored {
+			continue
+		}
+		switch {
+		case rel.indexed:
+			c.scanned += rel.filtered
+		case lookup:
+			c.scanned += min(rel.rows, result)
+		default:
+			c.scanned += rel.rows
+			c.est.FullScans = append(c.est.FullScans, rel.name)
+		}
+	}
+	return result, len(rels) > 0
+}
+
+// relation returns the costed form of a table, or nil when it has no
+// statistics.
+func (c *costEstimator) relation(t *ast.SimpleTable) *costRelation {
+	name := qualifiedName(t.Name)
+	last := t.Name.Parts[len(t.Name.Parts)-1].Unquoted
+	rel := &costRelation{name: name, names: []string{c.key(last), c.key(name)}}
+	if t.Alias != nil {
+		rel.names = []string{c.key(t.Alias.Unquoted)}
+	}
+	if n, ok := c.ctes[c.key(name)]; ok && len(t.Name.Parts) == 1 {
+		if n < 0 {
+			return nil
+		}
+		rel.rows = n
+		return rel
+	}
+	n, ok := c.rows[c.key(name)]
+	if !ok {
+		n, ok = c.rows[c.key(last)]
+	}
+	if !ok {
+		return nil
+	}
+	rel.rows, rel.stored = float64(n), true
+	if c.schema != nil {
+		rel.table = c.schema.lookup(t.Name)
+	}
+	return rel
+}
+
+// owner returns the index of the relation column e belongs to, or -1.
+// items is the number of FROM items, with or without statistics.
+func (c *costEstimator) owner(rels []*costRelation, items int, e ast.Expr) int {
+	if q, ok := e.(*ast.QualifiedIdent); ok && len(q.Parts) > 1 {
+		qualifier := c.key(qualifiedName(&ast.QualifiedIdent{Parts: q.Parts[:len(q.Parts)-1]}))
+		for i, rel := range rels {
+			for _, n := range rel.names {
+				if n == qualifier {
+					return i
+				}
+			}
+		}
+		return -1
+	}
+	if items == 1 && len(rels) == 1 {
+		return 0
+	}
+	found := -1
+	for i, rel := range rels {
+		if rel.table != nil && rel.table.Column(columnName(e)) != nil {
+			if found >= 0 {
+				return -1
+			}
+			found = i
+		}
+	}
+	return found
+}
+
+// joinedWith returns a relation already joined that an edge connects to
+// i, or -1.
+func joinedWith(edges [][2]int, joined []bool, i int) int {
+	for _, e := range edges {
+		switch {
+		case e[0] == i && joined[e[1]]:
+			return e[1]
+		case e[1] == i && joined[e[0]]:
+			return e[0]
+		}
+	}
+	return -1
+}
+
+// termSelectivity returns the column a term filters by comparing it with
+// constants, the fraction of rows it keeps and whether an index on the
+// column can find them; col is nil for any other term.
+func termSelectivity(term ast.Expr) (col ast.Expr, sel float64, seek bool) {
+	var subject ast.Expr
+	switch t := term.(type) {
+	case *ast.BinaryExpr:
+		subject, sel, seek = t.Left, ineqSelectivity, true
+		if bareColumn(t.Left) == nil {
+			subject = t.Right
+		}
+		other := t.Right
+		if subject == t.Right {
+			other = t.Left
+		}
+		if !constantExpr(other) {
+			return nil, 0, false
+		}
+		switch t.Op {
+		case lexer.EQ:
+			sel = eqSelectivity
+		case lexer.NEQ:
+			sel, seek = 1-eqSelectivity, false
+		case lexer.LT, lexer.LTE, lexer.GT, lexer.GTE:
+		default:
+			return nil, 0, false
+		}
+	case *ast.InExpr:
+		if len(t.List) == 0 || !allConstant(t.List) {
+			return nil, 0, false
+		}
+		subject, sel, seek = t.Expr, min(float64(len(t.List))*eqSelectivity, 1), !t.Not
+		if t.Not {
+			sel = 1 - sel
+		}
+	case *ast.BetweenExpr:
+		if !constantExpr(t.Lo) || !constantExpr(t.Hi) {
+			return nil, 0, false
+		}
+		subject, sel, seek = t.Expr, rangeSelectivity, !t.Not
+		if t.Not {
+			sel = 1 - sel
+		}
+	case *ast.LikeExpr:
+		if !constantExpr(t.Pattern) {
+			return nil, 0, false
+		}
+		subject, sel = t.Expr, eqSelectivity
+		if t.Not {
+			sel = 1 - sel
+		}
+	case *ast.IsNullExpr:
+		subject, sel, seek = t.Expr, eqSelectivity, !t.Not
+		if t.Not {
+			sel = 1 - sel
+		}
+	default:
+		return nil, 0, false
+	}
+	if bareColumn(subject) == nil {
+		return nil, 0, false
+	}
+	return subject, sel, seek
+}
+
+func constantExpr(e ast.Expr) bool {
+	if u, ok := e.(*ast.UnaryExpr); ok && u.Op == lexer.MINUS {
+		e = u.Expr
+	}
+	switch e.(type) {
+	case *ast.Literal, *ast.Param, *ast.NullLit:
+		return true
+	}
+	return false
+}
+
+func allConstant(list []ast.Expr) bool {
+	for _, e := range list {
+		if !constantExpr(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// columnName returns the unqualified name of a column reference.
+func columnName(e ast.Expr) string {
+	if q, ok := e.(*ast.QualifiedIdent); ok {
+		return q.Parts[len(q.Parts)-1].Unquoted
+	}
+	return e.(*ast.Ident).Unquoted
+}
+
+// uniqueColumn reports whether column alone is the primary key or a
+// unique index of t.
+func uniqueColumn(t *Table, column string) bool {
+	if t == nil {
+		return false
+	}
+	if len(t.PrimaryKey) == 1 && ident.Equal(t.PrimaryKey[0], column, t.dialect) {
+		return true
+	}
+	for _, idx := range t.Indexes {
+		if idx.Unique() && len(idx.Columns) == 1 && ident.Equal(idx.Columns[0], column, t.dialect) {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeCost reports statements estimated to read or produce more rows
+// than opts.Statistics allows.
+func analyzeCost(stmt Statement, idx int, report *AnalysisReport, opts AnalysisOptions) {
+	limit := opts.Statistics.MaxScanRows
+	if limit == 0 {
+		limit = DefaultMaxScanRows
+	}
+	est := EstimateCost(stmt, opts.Statistics, opts.Schema)
+	if est.ScannedRows > limit {
+		msg := fmt.Sprintf("The statement is estimated to read about %d rows", est.ScannedRows)
+		if len(est.FullScans) > 0 {
+			msg += ", scanning " + strings.Join(est.FullScans, ", ") + " in full"
+		}
+		addFinding(report, SeverityWarning, "LARGE_SCAN", msg+".", "Filter on an indexed column, add an index for the existing filters, or process the rows in batches.", idx, stmt.Pos())
+	}
+	if est.ResultRows > limit {
+		addFinding(report, SeverityWarning, "LARGE_RESULT", fmt.Sprintf("The statement is estimated to return or change about %d rows.", est.ResultRows), "Check the join conditions, and paginate, aggregate or batch the rows.", idx, stmt.Pos())
+	}
+}
@@ -0,0 +1,67 @@
+package sqlparser_test
+
+import (
+	"slices"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestEstimateCost(t *testing.T) {
+	schema, err := sqlparser.BuildSchema(`
+		CREATE TABLE customers (id INT PRIMARY KEY, email VARCHAR(100), country CHAR(2));
+		CREATE TABLE orders (id INT PRIMARY KEY, customer_id INT, status VARCHAR(10), total INT);
+		CREATE INDEX orders_customer ON orders (customer_id);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := &sqlparser.Statistics{Rows: map[string]int64{"customers": 10_000, "Orders": 1_000_000}}
+	tests := []struct {
+		sql       string
+		scanned   int64
+		result    int64
+		fullScans []string
+	}{
+		{"SELECT * FROM orders", 1_000_000, 1_000_000, []string{"orders"}},
+		{"SELECT * FROM orders WHERE id = 7", 1, 1, nil},
+		{"SELECT * FROM orders WHERE status = 'open'", 1_000_000, 5_000, []string{"orders"}},
+		{"SELECT * FROM customers c JOIN orders o ON o.customer_id = c.id WHERE c.id = 7", 101, 100, nil},
+		{"SELECT * FROM customers c JOIN orders o ON o.customer_id = c.id", 1_010_000, 1_000_000, []string{"customers"}},
+		{"SELECT * FROM customers, orders", 1_010_000, 10_000_000_000, []string{"customers", "orders"}},
+		{"SELECT COUNT(*) FROM orders WHERE total > 100", 1_000_000, 1, []string{"orders"}},
+		{"SELECT country, COUNT(*) FROM customers GROUP BY country LIMIT 10", 10_000, 10, []string{"customers"}},
+		{"SELECT * FROM customers WHERE id IN (SELECT customer_id FROM orders WHERE total > 100)", 1_010_000, 10_000, []string{"orders", "customers"}},
+		{"WITH big AS (SELECT customer_id FROM orders WHERE status = 'open') SELECT * FROM big", 1_000_000, 5_000, []string{"orders"}},
+		{"DELETE FROM orders WHERE customer_id = 7", 5_000, 5_000, nil},
+		{"SELECT * FROM unknown_table", 0, 0, nil},
+	}
+	for _, tt := range tests {
+		stmt, err := sqlparser.ParseStatement(tt.sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := sqlparser.EstimateCost(stmt, stats, schema)
+		if got.ScannedRows != tt.scanned || got.ResultRows != tt.result || !slices.Equal(got.FullScans, tt.fullScans) {
+			t.Errorf("%s: got %+v, want scanned %d, result %d, full scans %v", tt.sql, got, tt.scanned, tt.result, tt.fullScans)
+		}
+	}
+}
+
+func TestAnalyzeSQLCost(t *testing.T) {
+	hasFinding := func(report sqlparser.AnalysisReport, code string) bool {
+		return slices.ContainsFunc(report.Findings, func(f sqlparser.AnalysisFinding) bool { return f.Code == code })
+	}
+	opts := sqlparser.AnalysisOptions{Statistics: &sqlparser.Statistics{Rows: map[string]int64{"events": 50_000_000}, MaxScanRows: 100_000}}
+	report := sqlparser.AnalyzeSQLWithOptions("SELECT id FROM events WHERE kind = 'click'", opts)
+	if !hasFinding(report, "LARGE_SCAN") || !hasFinding(report, "LARGE_RESULT") {
+		t.Errorf("expected LARGE_SCAN and LARGE_RESULT: %+v", report.Findings)
+	}
+	report = sqlparser.AnalyzeSQLWithOptions("SELECT id FROM events WHERE kind = 'click' LIMIT 100", opts)
+	if !hasFinding(report, "LARGE_SCAN") || hasFinding(report, "LARGE_RESULT") {
+		t.Errorf("expected only LARGE_SCAN: %+v", report.Findings)
+	}
+	if report := sqlparser.AnalyzeSQL("SELECT id FROM events"); hasFinding(report, "LARGE_SCAN") {
+		t.Error("LARGE_SCAN reported without statistics")
+	}
+}
@@ -0,0 +1,126 @@
+package sqlparser
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// DDLOrder makes rendered CREATE TABLE and CREATE INDEX statements
+// independent of the order their parts were declared in, so generated DDL
+// diffs cleanly between runs. The zero value keeps source order.
+type DDLOrder struct {
+	// SortConstraints orders table constraints by kind, then by name, then
+	// by column list. Kinds follow ConstraintKinds; kinds it does not list,
+	// or all kinds when it is empty, follow DefaultConstraintKinds.
+	SortConstraints bool
+	ConstraintKinds []ast.ConstraintType
+	// SortOptions orders table options by key, case-insensitively. Keys
+	// listed in OptionKeys come first, in that order.
+	SortOptions bool
+	OptionKeys  []string
+	// SortIndexColumns sorts the column lists of INDEX, UNIQUE, FULLTEXT and
+	// SPATIAL definitions and of CREATE INDEX case-insensitively. Column
+	// order is significant to the database, so this is meant for
+	// normalising definitions before comparing them, not for DDL you run.
+	SortIndexColumns bool
+}
+
+// DefaultConstraintKinds is the constraint order used by
+// DDLOrder.SortConstraints for kinds not listed in ConstraintKinds.
+var DefaultConstraintKinds = []ast.ConstraintType{
+	ast.PrimaryKeyConstraint,
+	ast.UniqueConstraint,
+	ast.IndexConstraint,
+	ast.FulltextConstraint,
+	ast.SpatialConstraint,
+	ast.ForeignKeyConstraint,
+	ast.CheckConstraint,
+}
+
+func (o DDLOrder) kindRank(k ast.ConstraintType) int {
+	if i := slices.Index(o.ConstraintKinds, k); i >= 0 {
+		return i
+	}
+	return len(o.ConstraintKinds) + slices.Index(DefaultConstraintKinds, k)
+}
+
+// constraints returns cs in render order. The AST is left untouched.
+func (o DDLOrder) constraints(cs []*ast.TableConstraint) []*ast.TableConstraint {
+	if !o.SortConstraints || len(cs) < 2 {
+		return cs
+	}
+	sorted := slices.Clone(cs)
+	slices.SortStableFunc(sorted, func(a, b *ast.TableConstraint) int {
+		if c := cmp.Compare(o.kindRank(a.Type), o.kindRank(b.Type)); c != 0 {
+			return c
+		}
+		if c := compareFold(identName(a.Name), identName(b.Name)); c != 0 {
+			return c
+		}
+		return slices.CompareFunc(a.Columns, b.Columns, func(x, y *ast.IndexColDef) int {
+			return compareFold(identName(x.Name), identName(y.Name))
+		})
+	})
+	return sorted
+}
+
+// options returns opts in render order.
+func (o DDLOrder) options(opts []ast.TableOption) []ast.TableOption {
+	if !o.SortOptions || len(opts) < 2 {
+		return opts
+	}
+	rank := func(key []byte) int {
+		for i, k := range o.OptionKeys {
+			if strings.EqualFold(k, string(key)) {
+				return i
+			}
+		}
+		return len(o.OptionKeys)
+	}
+	sorted := slices.Clone(opts)
+	slices.SortStableFunc(sorted, func(a, b ast.TableOption) int {
+		if c := cmp.Compare(rank(a.Key), rank(b.Key)); c != 0 {
+			return c
+		}
+		return compareFold(string(a.Key), string(b.Key))
+	})
+	return sorted
+}
+
+// indexColumns returns the column list of an index of kind k in render
+// order.
+func (o DDLOrder) indexColumns(k ast.ConstraintType, cols []*ast.IndexColDef) []*ast.IndexColDef {
+	if !o.SortIndexColumns || len(cols) < 2 {
+		return cols
+	}
+	switch k {
+	case ast.IndexConstraint, ast.UniqueConstraint, ast.FulltextConstraint, ast.SpatialConstraint:
+	default:
+		return cols
+	}
+	sorted := slices.Clone(cols)
+	slices.SortStableFunc(sorted, func(a, b *ast.IndexColDef) int {
+		return compareFold(identName(a.Name), identName(b.Name))
+	})
+	return sorted
+}
+
+func identName(id *ast.Ident) string {
+	if id == nil {
+		return ""
+	}
+	return id.Unquoted
+}
+
+// compareFold compares a and b case-insensitively, falling back to a
+// byte-wise comparison so names differing only in case still order
+// deterministically.
+func compareFold(a, b string) int {
+	if c := strings.Compare(strings.ToLower(a), strings.ToLower(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
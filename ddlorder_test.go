@@ -0,0 +1,59 @@
+package sqlparser_test
+
+import (
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+func TestConvertDDLOrder(t *testing.T) {
+	a := "CREATE TABLE t (b INT, a INT, KEY idx_b (b, a), CONSTRAINT fk FOREIGN KEY (a) REFERENCES u (id), UNIQUE KEY uq (b), PRIMARY KEY (a)) ROW_FORMAT=DYNAMIC CHARSET=utf8mb4 ENGINE=InnoDB"
+	b := "CREATE TABLE t (b INT, a INT, PRIMARY KEY (a), UNIQUE KEY uq (b), CONSTRAINT fk FOREIGN KEY (a) REFERENCES u (id), KEY idx_b (a, b)) ENGINE=InnoDB CHARSET=utf8mb4 ROW_FORMAT=DYNAMIC"
+	opts := sqlparser.ConvertOptions{
+		Target: sqlparser.DialectMySQL,
+		Order:  sqlparser.DDLOrder{SortConstraints: true, SortOptions: true, SortIndexColumns: true},
+	}
+	outA, _, err := sqlparser.ConvertDialectWithOptions(a, opts)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	outB, _, err := sqlparser.ConvertDialectWithOptions(b, opts)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	want := "CREATE TABLE `t` (`b` INT, `a` INT, PRIMARY KEY (`a`), CONSTRAINT `uq` UNIQUE (`b`), CONSTRAINT `idx_b` INDEX (`a`, `b`), " +
+		"CONSTRAINT `fk` FOREIGN KEY (`a`) REFERENCES `u` (`id`)) CHARSET=utf8mb4 ENGINE=InnoDB ROW_FORMAT=DYNAMIC"
+	if outA != want || outB != want {
+		t.Fatalf("expected both declarations to render as\n%s\ngot\n%s\n%s", want, outA, outB)
+	}
+
+	opts.Order = sqlparser.DDLOrder{
+		SortConstraints: true,
+		ConstraintKinds: []ast.ConstraintType{ast.ForeignKeyConstraint},
+		SortOptions:     true,
+		OptionKeys:      []string{"engine"},
+	}
+	out, _, err := sqlparser.ConvertDialectWithOptions(a, opts)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	want = "CREATE TABLE `t` (`b` INT, `a` INT, CONSTRAINT `fk` FOREIGN KEY (`a`) REFERENCES `u` (`id`), PRIMARY KEY (`a`), " +
+		"CONSTRAINT `uq` UNIQUE (`b`), CONSTRAINT `idx_b` INDEX (`b`, `a`)) ENGINE=InnoDB CHARSET=utf8mb4 ROW_FORMAT=DYNAMIC"
+	if out != want {
+		t.Fatalf("unexpected custom order:\n got %s\nwant %s", out, want)
+	}
+}
+
+func TestConvertDDLOrderCreateIndex(t *testing.T) {
+	out, _, err := sqlparser.ConvertDialectWithOptions(`CREATE INDEX idx ON t ("Zeta", alpha, "Beta")`, sqlparser.ConvertOptions{
+		Target: sqlparser.DialectPostgres,
+		Order:  sqlparser.DDLOrder{SortIndexColumns: true},
+	})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if want := `CREATE INDEX "idx" ON "t" ("alpha", "Beta", "Zeta")`; out != want {
+		t.Fatalf("unexpected output:\n got %s\nwant %s", out, want)
+	}
+}
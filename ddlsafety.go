@@ -0,0 +1,107 @@
+package sqlparser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// rewritingDefaults are the PostgreSQL functions that are volatile rather
+// than stable, so a column added with one as its default is computed row
+// by row and the table is rewritten. now() and CURRENT_TIMESTAMP are
+// stable and keep ADD COLUMN a catalog-only change.
+var rewritingDefaults = map[string]bool{
+	"random": true, "gen_random_uuid": true, "uuid_generate_v4": true,
+	"clock_timestamp": true, "timeofday": true, "nextval": true,
+}
+
+// analyzeDDLSafety flags migration steps that lock or rewrite a table that
+// may already hold rows, the checks an online migration gate runs. The
+// dialect decides which operations block; without one, only those that
+// are unsafe everywhere are reported.
+func analyzeDDLSafety(stmt Statement, idx int, report *AnalysisReport, opts AnalysisOptions) {
+	switch s := stmt.(type) {
+	case *ast.CreateIndexStmt:
+		if opts.Dialect == DialectPostgres && !s.Concurrently {
+			addFinding(report, SeverityWarning, "CREATE_INDEX_BLOCKS_WRITES", fmt.Sprintf("CREATE INDEX %s locks %s against writes until the index is built.", defaultIndexName(s), qualifiedName(s.Table)), "Use CREATE INDEX CONCURRENTLY, outside a transaction block.", idx, stmt.Pos())
+		}
+	case *ast.AlterTableStmt:
+		table := qualifiedName(s.Table)
+		for _, cmd := range s.Cmds {
+			switch c := cmd.(type) {
+			case *ast.AddColumnCmd:
+				analyzeAddColumn(c, table, idx, report, opts)
+			case *ast.ModifyColumnCmd:
+				name := c.Col.Name
+				if c.OldName != nil {
+					name = c.OldName
+				}
+				analyzeColumnRewrite(table+"."+name.Unquoted, idx, c.Pos(), report, opts)
+			case *ast.AlterColumnCmd:
+				switch {
+				case c.Action == ast.AlterColumnType:
+					analyzeColumnRewrite(table+"."+c.Name.Unquoted, idx, c.Pos(), report, opts)
+				case c.Action == ast.AlterColumnSetNotNull && opts.Dialect == DialectPostgres:
+					addFinding(report, SeverityWarning, "SET_NOT_NULL_SCANS_TABLE", fmt.Sprintf("Making %s.%s NOT NULL scans the whole table under an ACCESS EXCLUSIVE lock.", table, c.Name.Unquoted), "Add CHECK (column IS NOT NULL) NOT VALID, VALIDATE it in a later step, then SET NOT NULL, which PostgreSQL 12 and later prove from the check without a scan.", idx, c.Pos())
+				}
+			case *ast.DropColumnCmd:
+				msg := fmt.Sprintf("Dropping %s.%s breaks application code that still reads or writes it", table, c.Name.Unquoted)
+				if opts.Dialect == DialectMySQL {
+					msg += ", and rebuilds the table before MySQL 8.0.29."
+				} else {
+					msg += "."
+				}
+				addFinding(report, SeverityWarning, "DROP_COLUMN", msg, "Deploy code that no longer uses the column first, then drop it in a later migration.", idx, c.Pos())
+			case *ast.AddConstraintCmd:
+				if opts.Dialect != DialectPostgres {
+					continue
+				}
+				switch c.Constraint.Type {
+				case ast.PrimaryKeyConstraint, ast.UniqueConstraint:
+					addFinding(report, SeverityWarning, "ADD_CONSTRAINT_BLOCKS_WRITES", fmt.Sprintf("Adding a PRIMARY KEY or UNIQUE constraint to %s builds its index while blocking writes.", table), "Build the index with CREATE UNIQUE INDEX CONCURRENTLY, then add the constraint with USING INDEX.", idx, c.Pos())
+				}
+			}
+		}
+	}
+}
+
+// analyzeColumnRewrite flags a change of the type of column, which
+// rewrites the table (ALTER_COLUMN_TYPE).
+func analyzeColumnRewrite(column string, idx int, pos int32, report *AnalysisReport, opts AnalysisOptions) {
+	msg := fmt.Sprintf("Changing the definition of %s rewrites the table", column)
+	switch opts.Dialect {
+	case DialectMySQL:
+		msg += " with ALGORITHM=COPY for most type changes, blocking writes for its duration."
+	case DialectPostgres:
+		msg += " and its indexes under an ACCESS EXCLUSIVE lock, unless the new type is binary-compatible (such as a longer VARCHAR)."
+	default:
+		msg += " in most databases, blocking writes for its duration."
+	}
+	addFinding(report, SeverityWarning, "ALTER_COLUMN_TYPE", msg, "Add a new column, backfill it in batches, switch the application over and drop the old column in a later migration.", idx, pos)
+}
+
+func analyzeAddColumn(c *ast.AddColumnCmd, table string, idx int, report *AnalysisReport, opts AnalysisOptions) {
+	col := c.Col
+	name := table + "." + col.Name.Unquoted
+	if (col.NotNull || col.PrimaryKey) && col.Default == nil && col.Generated == nil && !col.AutoIncrement {
+		switch opts.Dialect {
+		case DialectMySQL:
+			addFinding(report, SeverityWarning, "ADD_NOT_NULL_WITHOUT_DEFAULT", fmt.Sprintf("Column %s is added NOT NULL without a DEFAULT; MySQL fills existing rows with the type's implicit zero value.", name), "Give the column an explicit DEFAULT, or add it nullable, backfill it and then make it NOT NULL.", idx, c.Pos())
+		case DialectSQLite:
+			addFinding(report, SeverityCritical, "ADD_NOT_NULL_WITHOUT_DEFAULT", fmt.Sprintf("Column %s is added NOT NULL without a DEFAULT, which SQLite rejects.", name), "Give the column a DEFAULT.", idx, c.Pos())
+		default:
+			addFinding(report, SeverityCritical, "ADD_NOT_NULL_WITHOUT_DEFAULT", fmt.Sprintf("Column %s is added NOT NULL without a DEFAULT, which fails on a table that already has rows.", name), "Give the column a DEFAULT, or add it nullable, backfill it and then make it NOT NULL.", idx, c.Pos())
+		}
+	}
+	if opts.Dialect != DialectPostgres {
+		return
+	}
+	volatile := col.Type != nil && strings.HasSuffix(strings.ToLower(string(col.Type.Name)), "serial")
+	if f, ok := col.Default.(*ast.FuncCall); ok && rewritingDefaults[sequenceFunc(f)] {
+		volatile = true
+	}
+	if volatile {
+		addFinding(report, SeverityWarning, "ADD_COLUMN_VOLATILE_DEFAULT", fmt.Sprintf("Column %s is added with a volatile default, which rewrites the table under an ACCESS EXCLUSIVE lock.", name), "Add the column without a default (or with a constant one), set the default afterwards and backfill existing rows in batches.", idx, c.Pos())
+	}
+}
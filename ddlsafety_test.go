@@ -0,0 +1,83 @@
+package sqlparser_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestAnalyzeDDLSafety(t *testing.T) {
+	migration := `
+		ALTER TABLE users ADD COLUMN age INT NOT NULL;
+		ALTER TABLE users ADD COLUMN plan TEXT NOT NULL DEFAULT 'free';
+		ALTER TABLE users ADD COLUMN token UUID DEFAULT gen_random_uuid(), ADD COLUMN seen TIMESTAMP DEFAULT now();
+		ALTER TABLE users MODIFY COLUMN name VARCHAR(500);
+		ALTER TABLE users DROP COLUMN legacy;
+		ALTER TABLE users ADD CONSTRAINT users_email UNIQUE (email);
+		CREATE INDEX users_name ON users (name);
+		CREATE INDEX CONCURRENTLY users_plan ON users (plan);
+		ALTER TABLE users ALTER COLUMN id TYPE bigint USING id::bigint;
+		ALTER TABLE users ALTER name SET DATA TYPE text, ALTER COLUMN name SET NOT NULL;
+		ALTER TABLE users CHANGE COLUMN legacy_name name VARCHAR(500) NOT NULL;
+		ALTER TABLE users ALTER COLUMN plan SET DEFAULT 'pro', ALTER COLUMN plan DROP NOT NULL`
+	codes := func(d sqlparser.Dialect) map[string]int {
+		got := map[string]int{}
+		for _, f := range sqlparser.AnalyzeSQLWithOptions(migration, sqlparser.AnalysisOptions{Dialect: d}).Findings {
+			switch f.Code {
+			case "ADD_NOT_NULL_WITHOUT_DEFAULT", "ADD_COLUMN_VOLATILE_DEFAULT", "ALTER_COLUMN_TYPE", "DROP_COLUMN", "ADD_CONSTRAINT_BLOCKS_WRITES", "CREATE_INDEX_BLOCKS_WRITES", "SET_NOT_NULL_SCANS_TABLE":
+				got[f.Code] |= 1 << f.StatementIndex
+			}
+		}
+		return got
+	}
+	tests := []struct {
+		dialect sqlparser.Dialect
+		want    map[string]int // code -> bit set of statement indexes
+	}{
+		{sqlparser.DialectPostgres, map[string]int{
+			"ADD_NOT_NULL_WITHOUT_DEFAULT": 1 << 0, "ADD_COLUMN_VOLATILE_DEFAULT": 1 << 2, "ALTER_COLUMN_TYPE": 1<<3 | 1<<8 | 1<<9 | 1<<10,
+			"DROP_COLUMN": 1 << 4, "ADD_CONSTRAINT_BLOCKS_WRITES": 1 << 5, "CREATE_INDEX_BLOCKS_WRITES": 1 << 6, "SET_NOT_NULL_SCANS_TABLE": 1 << 9,
+		}},
+		{sqlparser.DialectMySQL, map[string]int{"ADD_NOT_NULL_WITHOUT_DEFAULT": 1 << 0, "ALTER_COLUMN_TYPE": 1<<3 | 1<<8 | 1<<9 | 1<<10, "DROP_COLUMN": 1 << 4}},
+	}
+	for _, tt := range tests {
+		if got := codes(tt.dialect); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.dialect, got, tt.want)
+		}
+	}
+
+	report := sqlparser.AnalyzeSQLWithOptions("ALTER TABLE t ADD COLUMN n INT NOT NULL", sqlparser.AnalysisOptions{Dialect: sqlparser.DialectMySQL})
+	if len(report.Findings) != 1 || report.Findings[0].Severity != sqlparser.SeverityWarning {
+		t.Errorf("MySQL fills NOT NULL columns with zero values: %+v", report.Findings)
+	}
+
+	out, err := sqlparser.ConvertDialect("ALTER TABLE t ALTER COLUMN a SET DATA TYPE bigint USING a::bigint, ALTER a DROP DEFAULT", sqlparser.DialectPostgres)
+	if want := `ALTER TABLE "t" ALTER COLUMN "a" TYPE bigint USING CAST("a" AS bigint), ALTER COLUMN "a" DROP DEFAULT`; err != nil || out != want {
+		t.Errorf("ALTER COLUMN:\n got %s %v\nwant %s", out, err, want)
+	}
+
+	out, err = sqlparser.ConvertDialect("CREATE INDEX CONCURRENTLY i ON t (a)", sqlparser.DialectMySQL)
+	if err != nil || out != "CREATE INDEX `i` ON `t` (`a`)" {
+		t.Errorf("MySQL: %s %v", out, err)
+	}
+	out, err = sqlparser.ConvertDialect("CREATE INDEX CONCURRENTLY i ON t (a)", sqlparser.DialectPostgres)
+	if err != nil || out != `CREATE INDEX CONCURRENTLY "i" ON "t" ("a")` {
+		t.Errorf("PostgreSQL: %s %v", out, err)
+	}
+
+	// Only PostgreSQL names an index itself; the others get its name.
+	out, err = sqlparser.ConvertDialect("CREATE INDEX CONCURRENTLY ON t (a, b); CREATE UNIQUE INDEX ON t ((lower(email)))", sqlparser.DialectPostgres)
+	if want := `CREATE INDEX CONCURRENTLY ON "t" ("a", "b"); CREATE UNIQUE INDEX ON "t" ((LOWER("email")))`; err != nil || out != want {
+		t.Errorf("PostgreSQL:\n got %s %v\nwant %s", out, err, want)
+	}
+	out, err = sqlparser.ConvertDialect("CREATE INDEX CONCURRENTLY ON t (a, b); CREATE UNIQUE INDEX ON t ((lower(email)))", sqlparser.DialectMySQL)
+	if want := "CREATE INDEX `t_a_b_idx` ON `t` (`a`, `b`); CREATE UNIQUE INDEX `t_lower_key` ON `t` ((LOWER(`email`)))"; err != nil || out != want {
+		t.Errorf("MySQL:\n got %s %v\nwant %s", out, err, want)
+	}
+	report = sqlparser.AnalyzeSQLWithOptions("CREATE INDEX ON t (a)", sqlparser.AnalysisOptions{Dialect: sqlparser.DialectPostgres})
+	if len(report.Findings) != 1 || !strings.Contains(report.Findings[0].Message, "t_a_idx") {
+		t.Errorf("unnamed index: %+v", report.Findings)
+	}
+}
@@ -2,11 +2,14 @@ package sqlparser
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/ident"
 	"github.com/oarkflow/sqlparser/lexer"
+	"github.com/oarkflow/sqlparser/parser"
 )
 
 type Dialect string
@@ -15,53 +18,376 @@ const (
 	DialectMySQL    Dialect = "mysql"
 	DialectPostgres Dialect = "postgres"
 	DialectSQLite   Dialect = "sqlite"
+	// DialectClickHouse is a conversion target; queries are read with
+	// ParseOptions.AnalyticsClauses for its QUALIFY and LIMIT BY.
+	DialectClickHouse Dialect = "clickhouse"
 )
 
 type ConvertOptions struct {
 	Target Dialect
-	Strict bool
+	// Source is the dialect the SQL is written in, when known. It decides
+	// how string literals with backslashes are read: as escapes in MySQL
+	// and ClickHouse, as plain characters in PostgreSQL's standard strings
+	// and SQLite. When it is empty such literals are kept as written.
+	Source Dialect
+	// Strict promotes conversion warnings to errors. When StrictCodes is
+	// non-empty only warnings with one of the listed codes are promoted.
+	Strict      bool
+	StrictCodes []string
+	// Tags is injected as a comment after every statement, in TagFormat
+	// (sqlcommenter by default). With KeepTags, tags already present in the
+	// source comments are carried over too; injected tags win on conflict.
+	Tags      Tags
+	TagFormat TagFormat
+	KeepTags  bool
+	// Timeouts injects per-statement execution time limits using the
+	// target's own construct.
+	Timeouts TimeoutPolicy
+	// InList bounds literal IN lists; see InListPolicy.
+	InList InListPolicy
+	// Order fixes the order of constraints, table options and index
+	// columns in rendered DDL.
+	Order DDLOrder
+	// Limit selects how query row limits are spelled; see LimitStyle.
+	Limit LimitStyle
+	// Quote selects which identifiers are quoted; see QuotePolicy.
+	Quote QuotePolicy
+	// Style sets the case of keywords and identifiers, the spelling of
+	// booleans and the final semicolon.
+	Style OutputStyle
+	// KeepVersionComments carries MySQL version comments (/*!40101 ... */)
+	// over verbatim, as dump files need: standalone ones stay statements of
+	// their own and those before or after a statement stay attached to it.
+	// Other targets read them as plain comments.
+	KeepVersionComments bool
+	// Schema, when set, lets * EXCEPT (...) be expanded to the columns it
+	// selects, and PIVOT and UNPIVOT over its tables be rewritten, for
+	// targets without them.
+	Schema *Schema
+	// TypeMap overrides the type each source type name is written as.
+	TypeMap TypeMap
+	// ConvertBooleans converts booleans between real ones and MySQL's
+	// integers. For MySQL and SQLite, as older servers and tools expect,
+	// BOOLEAN columns become TINYINT(1) (INTEGER in SQLite) and TRUE and
+	// FALSE become 1 and 0. For PostgreSQL, where TINYINT(1) becomes
+	// BOOLEAN, the 1 and 0 compared with a boolean column become TRUE and
+	// FALSE, as do the integers assigned or inserted into one, or given as
+	// its default, any but 0 being TRUE; the columns are those of the
+	// script's CREATE TABLE statements and of Schema.
+	ConvertBooleans bool
+}
+
+// Conversion warning codes reported by ConvertDialectWithOptions.
+const (
+	WarnUnsupportedStatement      = "UNSUPPORTED_STATEMENT"
+	WarnConflictTargetGuessed     = "CONFLICT_TARGET_GUESSED"
+	WarnConflictTargetMissing     = "CONFLICT_TARGET_MISSING"
+	WarnConflictTargetDropped     = "CONFLICT_TARGET_DROPPED"
+	WarnConflictConstraintDropped = "CONFLICT_CONSTRAINT_DROPPED"
+	WarnUpsertWhereRewritten      = "UPSERT_WHERE_REWRITTEN"
+	WarnDoNothingAsIgnore         = "DO_NOTHING_AS_IGNORE"
+	WarnIgnoreDropped             = "IGNORE_DROPPED"
+	WarnReplaceUnsupported        = "REPLACE_UNSUPPORTED"
+	WarnLimitUnsupported          = "LIMIT_UNSUPPORTED"
+	WarnUnsignedDropped           = "UNSIGNED_DROPPED"
+	WarnZerofillDropped           = "ZEROFILL_DROPPED"
+	WarnColumnCommentDropped      = "COLUMN_COMMENT_DROPPED"
+	WarnTableOptionDropped        = "TABLE_OPTION_DROPPED"
+	WarnUseUnsupported            = "USE_UNSUPPORTED"
+	WarnMaintenanceVendor         = "MAINTENANCE_VENDOR_SPECIFIC"
+	WarnTimeoutUnsupported        = "TIMEOUT_UNSUPPORTED"
+	WarnInListNotSplit            = "IN_LIST_NOT_SPLIT"
+	WarnHintDropped               = "HINT_DROPPED"
+	WarnVersionCommentDropped     = "VERSION_COMMENT_DROPPED"
+	WarnAutoIncrementDropped      = "AUTOINCREMENT_DROPPED"
+	WarnPragmaUnsupported         = "PRAGMA_UNSUPPORTED"
+	WarnAttachUnsupported         = "ATTACH_UNSUPPORTED"
+	WarnTemporaryViewUnsupported  = "TEMPORARY_VIEW_UNSUPPORTED"
+	WarnSequenceUnsupported       = "SEQUENCE_UNSUPPORTED"
+	WarnSequenceAsAutoIncrement   = "SEQUENCE_AS_AUTO_INCREMENT"
+	WarnUserTypeInlined           = "USER_TYPE_INLINED"
+	WarnEnumTypeCreated           = "ENUM_TYPE_CREATED"
+	WarnSetTypeUnsupported        = "SET_TYPE_UNSUPPORTED"
+	WarnExplainOptionDropped      = "EXPLAIN_OPTION_DROPPED"
+	WarnDescribeRewritten         = "DESCRIBE_REWRITTEN"
+	WarnShowRewritten             = "SHOW_REWRITTEN"
+	WarnShowUnsupported           = "SHOW_UNSUPPORTED"
+	WarnIndexMethodDropped        = "INDEX_METHOD_DROPPED"
+	WarnPartialIndexDropped       = "PARTIAL_INDEX_DROPPED"
+	WarnIndexOptionDropped        = "INDEX_OPTION_DROPPED"
+	WarnFKMatchDropped            = "FK_MATCH_DROPPED"
+	WarnDeferrableUnsupported     = "DEFERRABLE_UNSUPPORTED"
+	WarnQuantifiedUnsupported     = "QUANTIFIED_COMPARISON_UNSUPPORTED"
+	WarnDerivedColumnsDropped     = "DERIVED_COLUMNS_DROPPED"
+	WarnDefaultValueUnsupported   = "DEFAULT_VALUE_UNSUPPORTED"
+	WarnTruncateSplit             = "TRUNCATE_SPLIT"
+	WarnTruncateOptionDropped     = "TRUNCATE_OPTION_DROPPED"
+	WarnReturningUnsupported      = "RETURNING_UNSUPPORTED"
+	WarnMaterializedDropped       = "MATERIALIZED_DROPPED"
+	WarnDataModifyingCTE          = "DATA_MODIFYING_CTE_UNSUPPORTED"
+	WarnIndexHintDropped          = "INDEX_HINT_DROPPED"
+	WarnTableSampleDropped        = "TABLESAMPLE_DROPPED"
+	WarnStarExceptDropped         = "STAR_EXCEPT_DROPPED"
+	WarnNestedTypeAsJSON          = "NESTED_TYPE_AS_JSON"
+	WarnQualifyUnsupported        = "QUALIFY_UNSUPPORTED"
+	WarnLimitByUnsupported        = "LIMIT_BY_UNSUPPORTED"
+	WarnUpsertUnsupported         = "UPSERT_UNSUPPORTED"
+	WarnPivotRewritten            = "PIVOT_REWRITTEN"
+	WarnPivotUnsupported          = "PIVOT_UNSUPPORTED"
+	WarnReservedWordQuoted        = "RESERVED_WORD_QUOTED"
+	WarnTinyintAsBoolean          = "TINYINT_AS_BOOLEAN"
+	WarnTypeApproximated          = "TYPE_APPROXIMATED"
+	WarnSpatialExtension          = "SPATIAL_EXTENSION_REQUIRED"
+	WarnIndexDropped              = "INDEX_DROPPED"
+	WarnBodyNotConverted          = "BODY_NOT_CONVERTED"
+)
+
+// ConversionWarning describes a lossy or guessed rewrite made while
+// converting SQL to another dialect. Pos is the byte offset of the node
+// that triggered the warning, or -1 when it is not tied to a node.
+type ConversionWarning struct {
+	Code    string
+	Message string
+	Pos     int32
+}
+
+// ConversionError is returned when strict mode promotes a warning to an error.
+type ConversionError struct {
+	Warning ConversionWarning
+}
+
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Warning.Code, e.Warning.Message)
 }
 
 func ConvertDialect(sql string, target Dialect) (string, error) {
-	return ConvertDialectWithOptions(sql, ConvertOptions{Target: target})
+	out, _, err := ConvertDialectWithOptions(sql, ConvertOptions{Target: target})
+	return out, err
 }
 
-func ConvertDialectWithOptions(sql string, opts ConvertOptions) (string, error) {
-	stmts, err := ParseStatements(sql)
+// ConvertDialectWithOptions converts sql to opts.Target and reports every
+// lossy or guessed rewrite as a ConversionWarning. A PostgreSQL or SQLite
+// opts.Source reads backslashes in strings as those dialects do.
+func ConvertDialectWithOptions(sql string, opts ConvertOptions) (string, []ConversionWarning, error) {
+	parseOpts := ParseOptions{StandardStrings: opts.Source == DialectPostgres || opts.Source == DialectSQLite}
+	stmts, err := parser.NewStringWithOptions(sql, parseOpts).ParseAll()
 	if err != nil {
-		return "", err
+		return "", nil, err
+	}
+	r := newDialectRenderer(opts)
+	if opts.KeepTags {
+		r.stmtTags = statementTags(sql, parseOpts)
 	}
-	r := &dialectRenderer{
-		target: opts.Target,
-		strict: opts.Strict,
+	if opts.KeepVersionComments {
+		r.versionComments, r.versionAfter = statementVersionComments(sql)
 	}
-	return r.renderStatements(stmts)
+	out, err := r.renderStatements(stmts)
+	return out, r.warnings, err
+}
+
+// ConvertStatements renders already parsed statements, such as the output
+// of CoalesceInserts, for opts.Target. KeepTags and KeepVersionComments
+// need the source text and are ignored.
+func ConvertStatements(stmts []Statement, opts ConvertOptions) (string, []ConversionWarning, error) {
+	r := newDialectRenderer(opts)
+	out, err := r.renderStatements(stmts)
+	return out, r.warnings, err
 }
 
 type dialectRenderer struct {
-	target     Dialect
-	strict     bool
-	paramIndex int
+	target      Dialect
+	source      Dialect
+	strict      bool
+	strictCodes []string
+	paramIndex  int
+	warnings    []ConversionWarning
+	err         error
+	tags        Tags
+	tagFormat   TagFormat
+	stmtTags    []Tags
+	timeouts    TimeoutPolicy
+	selectHint  string // optimizer hint for the next top-level SELECT, e.g. MAX_EXECUTION_TIME(100)
+	inTx        bool
+	inList      InListPolicy
+	order       DDLOrder
+	limitStyle  LimitStyle
+	quote       QuotePolicy
+	style       OutputStyle
+	schema      *Schema
+	typeMap     TypeMap
+	// convertBooleans is ConvertOptions.ConvertBooleans; ddl is the schema
+	// of the script's own DDL so far and boolLits the integer literals of
+	// the statement being rendered that stand for booleans, with their
+	// values, for PostgreSQL.
+	convertBooleans bool
+	ddl             *Schema
+	boolLits        map[*ast.Literal]bool
+	// bareIdents are the identifiers rendered without quotes, which
+	// applyStyle must not take for keywords.
+	bareIdents map[string]bool
+	// verbatim are the texts copied into the output as written, in order,
+	// such as the bodies of GenericDDL statements, which applyStyle leaves
+	// alone.
+	verbatim []string
+	// reserved are the unquoted source identifiers that are reserved words
+	// of the target; see checkReserved.
+	reserved []*ast.Ident
+	// recordParams makes renderExpr collect every placeholder it renders,
+	// in output order, so PlanInList can line arguments up with them.
+	recordParams bool
+	params       []*ast.Param
+	// literals says whether renderExpr keeps constant literals, lifts them
+	// into placeholders collecting their values in lifted (Parameterize) or
+	// redacts them (RedactLiterals). keepLimit exempts LIMIT and OFFSET.
+	literals  literalMode
+	lifted    []BoundValue
+	keepLimit bool
+	// versionComments are the kept version comments of each statement and
+	// versionAfter those following the last one.
+	versionComments []versionComments
+	versionAfter    []string
+	// sequences maps the sequences of the script that number a column to
+	// that column; see collectSequences.
+	sequences map[string]seqColumn
+	// identityStart is the first value of the identity column of the
+	// CREATE TABLE being rendered, from MySQL's AUTO_INCREMENT option.
+	identityStart []byte
+	// enumTypes and domains are the script's CREATE TYPE ... AS ENUM and
+	// CREATE DOMAIN statements, by typeKey; see collectUserTypes.
+	enumTypes map[string]*ast.CreateTypeStmt
+	domains   map[string]*ast.CreateDomainStmt
+	// table is the table whose columns are being rendered,
+	// pendingTypes the CREATE TYPE statements its inline ENUM columns
+	// need first and pendingIndexes the CREATE INDEX statements of the
+	// inline indexes that follow it.
+	table          *ast.QualifiedIdent
+	pendingTypes   []string
+	pendingIndexes []string
+	// rowidKey is the column of that table that takes over its
+	// PRIMARY KEY constraint for SQLite; see sqliteRowidKey.
+	rowidKey *ast.ColumnDef
+	// domainValue is what the VALUE of a domain CHECK renders as.
+	domainValue string
+}
+
+func newDialectRenderer(opts ConvertOptions) *dialectRenderer {
+	return &dialectRenderer{
+		target:      opts.Target,
+		source:      opts.Source,
+		strict:      opts.Strict,
+		strictCodes: opts.StrictCodes,
+		tags:        opts.Tags,
+		tagFormat:   opts.TagFormat,
+		timeouts:    opts.Timeouts,
+		inList:      opts.InList,
+		order:       opts.Order,
+		limitStyle:  opts.Limit,
+		quote:       opts.Quote,
+		style:       opts.Style,
+		schema:      opts.Schema,
+		typeMap:     opts.TypeMap,
+
+		convertBooleans: opts.ConvertBooleans,
+	}
+}
+
+// warn records a conversion warning and, in strict mode, the first
+// warning whose code is promoted becomes the conversion error.
+func (r *dialectRenderer) warn(code string, pos int32, format string, args ...any) {
+	w := ConversionWarning{Code: code, Message: fmt.Sprintf(format, args...), Pos: pos}
+	r.warnings = append(r.warnings, w)
+	if r.err == nil && r.isFatal(code) {
+		r.err = &ConversionError{Warning: w}
+	}
+}
+
+func (r *dialectRenderer) isFatal(code string) bool {
+	if !r.strict {
+		return false
+	}
+	if len(r.strictCodes) == 0 {
+		return true
+	}
+	for _, c := range r.strictCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *dialectRenderer) renderStatements(stmts []Statement) (string, error) {
+	r.sequences = collectSequences(stmts)
+	r.enumTypes, r.domains = collectUserTypes(stmts)
 	var b strings.Builder
 	for i, stmt := range stmts {
-		if i > 0 {
-			b.WriteString("; ")
-		}
-		s, err := r.renderStatement(stmt)
+		s, err := r.renderTopLevel(i, stmt)
 		if err != nil {
 			return "", err
 		}
+		if s == "" {
+			continue // omitted for the target
+		}
+		if b.Len() > 0 {
+			b.WriteString(r.separator())
+		}
 		b.WriteString(s)
 	}
-	return b.String(), nil
+	for _, c := range r.versionAfter {
+		if b.Len() > 0 {
+			b.WriteString(r.separator())
+		}
+		b.WriteString(c)
+	}
+	return r.applyStyle(b.String()), nil
+}
+
+// renderTopLevel renders the i-th statement of the script together with its
+// timeout construct and tag comment.
+func (r *dialectRenderer) renderTopLevel(i int, stmt Statement) (string, error) {
+	r.markBoolLiterals(stmt)
+	s, err := r.applyTimeout(stmt, func() (string, error) {
+		s, err := r.renderStatement(stmt)
+		if err != nil || s == "" {
+			return "", err
+		}
+		s = r.wrapVersionComments(i, s)
+		var tags Tags
+		if i < len(r.stmtTags) {
+			tags = mergeTags(tags, r.stmtTags[i])
+		}
+		tags = mergeTags(tags, r.tags)
+		if c := formatTagComment(tags, r.tagFormat); c != "" {
+			s += " " + c
+		}
+		return s, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if r.err != nil {
+		return "", r.err
+	}
+	if i < len(r.versionComments) {
+		for _, c := range slices.Backward(r.versionComments[i].before) {
+			if s == "" {
+				s = c
+				continue
+			}
+			s = c + r.separator() + s
+		}
+	}
+	r.trackTx(stmt)
+	r.trackDDL(stmt)
+	return s, nil
 }
 
 func (r *dialectRenderer) renderStatement(stmt Statement) (string, error) {
 	switch s := stmt.(type) {
 	case *ast.SelectStmt:
+		if out, ok := r.renderSetval(s); ok {
+			return out, nil
+		}
 		return r.renderSelect(s)
 	case *ast.InsertStmt:
 		return r.renderInsert(s)
@@ -70,9 +396,9 @@ func (r *dialectRenderer) renderStatement(stmt Statement) (string, error) {
 	case *ast.DeleteStmt:
 		return r.renderDelete(s)
 	case *ast.CreateTableStmt:
-		return r.renderCreateTable(s)
+		return r.withPendingTypes(r.renderCreateTable(s))
 	case *ast.AlterTableStmt:
-		return r.renderAlterTable(s)
+		return r.withPendingTypes(r.renderAlterTable(s))
 	case *ast.DropTableStmt:
 		return r.renderDropTable(s)
 	case *ast.CreateIndexStmt:
@@ -88,31 +414,85 @@ func (r *dialectRenderer) renderStatement(stmt Statement) (string, error) {
 	case *ast.DropDatabaseStmt:
 		return r.renderDropDatabase(s)
 	case *ast.TruncateStmt:
-		return "TRUNCATE TABLE " + r.renderQualifiedIdent(s.Table), nil
+		return r.renderTruncate(s), nil
 	case *ast.UseStmt:
+		if r.target == DialectPostgres || r.target == DialectSQLite {
+			r.warn(WarnUseUnsupported, s.TokPos, "USE is not supported by %s; select the database on the connection instead", r.target)
+		}
 		return "USE " + r.renderIdent(s.Database), nil
 	case *ast.ShowStmt:
 		return r.renderShow(s)
 	case *ast.ExplainStmt:
-		inner, err := r.renderStatement(s.Stmt)
-		if err != nil {
-			return "", err
-		}
-		return "EXPLAIN " + inner, nil
+		return r.renderExplain(s)
 	case *ast.CallStmt:
 		return r.renderCall(s)
 	case *ast.TransactionStmt:
 		return r.renderTx(s), nil
 	case *ast.GenericDDLStmt:
 		return r.renderGenericDDL(s), nil
+	case *ast.MaintenanceStmt:
+		return r.renderMaintenance(s), nil
+	case *ast.PragmaStmt:
+		return r.renderPragma(s), nil
+	case *ast.AttachStmt:
+		return r.renderAttach(s), nil
+	case *ast.CreateSequenceStmt:
+		return r.renderCreateSequence(s), nil
+	case *ast.AlterSequenceStmt:
+		return r.renderAlterSequence(s), nil
+	case *ast.DropSequenceStmt:
+		return r.renderDropSequence(s), nil
+	case *ast.CreateTypeStmt:
+		return r.renderCreateType(s), nil
+	case *ast.CreateDomainStmt:
+		return r.renderCreateDomain(s), nil
+	case *ast.DropTypeStmt:
+		return r.renderDropType(s), nil
 	default:
-		if r.strict {
-			return "", fmt.Errorf("unsupported statement type %T", s)
+		pos := int32(-1)
+		if s != nil {
+			pos = s.Pos()
 		}
+		r.warn(WarnUnsupportedStatement, pos, "unsupported statement type %T was omitted", s)
 		return "", nil
 	}
 }
 
+func (r *dialectRenderer) renderSelectColumns(cols []ast.SelectColumn, from []ast.TableRef) string {
+	var b strings.Builder
+	for i, c := range cols {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		switch {
+		case c.Except != nil:
+			b.WriteString(r.renderStarExcept(c, from))
+		case c.Star:
+			b.WriteByte('*')
+		default:
+			b.WriteString(r.renderExpr(c.Expr))
+		}
+		if c.Alias != nil {
+			b.WriteString(" AS ")
+			b.WriteString(r.renderIdent(c.Alias))
+		}
+	}
+	return b.String()
+}
+
+// renderReturning renders the RETURNING list of an INSERT, UPDATE or
+// DELETE, which mysql and ClickHouse lack.
+func (r *dialectRenderer) renderReturning(cols []ast.SelectColumn, pos int32) string {
+	if len(cols) == 0 {
+		return ""
+	}
+	if r.target == DialectMySQL || r.target == DialectClickHouse {
+		r.warn(WarnReturningUnsupported, pos, "RETURNING is not supported by %s and was dropped; read the rows back with a SELECT", r.target)
+		return ""
+	}
+	return " RETURNING " + r.renderSelectColumns(cols, nil)
+}
+
 func (r *dialectRenderer) renderWith(w *ast.WithClause) string {
 	if w == nil {
 		return ""
@@ -137,8 +517,26 @@ func (r *dialectRenderer) renderWith(w *ast.WithClause) string {
 			}
 			b.WriteString(")")
 		}
-		sub, _ := r.renderSelect(cte.Subq)
-		b.WriteString(" AS (")
+		b.WriteString(" AS ")
+		if cte.Materialized != ast.MaterializeDefault {
+			if r.target == DialectMySQL {
+				r.warn(WarnMaterializedDropped, cte.Name.TokPos, "mysql has no MATERIALIZED hint on CTEs; use the MERGE / NO_MERGE optimizer hints instead")
+			} else if cte.Materialized == ast.MaterializeAlways {
+				b.WriteString("MATERIALIZED ")
+			} else {
+				b.WriteString("NOT MATERIALIZED ")
+			}
+		}
+		var sub string
+		if cte.DML != nil {
+			if r.target == DialectMySQL || r.target == DialectSQLite {
+				r.warn(WarnDataModifyingCTE, cte.Name.TokPos, "%s does not allow INSERT, UPDATE or DELETE in WITH; run %s as a statement of its own", r.target, cte.Name.Unquoted)
+			}
+			sub, _ = r.renderStatement(cte.DML)
+		} else {
+			sub, _ = r.renderSelect(cte.Subq)
+		}
+		b.WriteByte('(')
 		b.WriteString(sub)
 		b.WriteByte(')')
 	}
@@ -147,117 +545,259 @@ func (r *dialectRenderer) renderWith(w *ast.WithClause) string {
 }
 
 func (r *dialectRenderer) renderSelect(s *ast.SelectStmt) (string, error) {
+	// An armed hint goes to the first SELECT after the WITH: the left
+	// operand of a set operation takes it, for the whole statement.
+	armed := r.selectHint
+	r.selectHint = ""
 	var b strings.Builder
 	b.WriteString(r.renderWith(s.With))
-	b.WriteString("SELECT ")
-	if s.Distinct {
-		b.WriteString("DISTINCT ")
+	r.selectHint = armed
+	if s.SetOp != nil {
+		left, err := r.renderSetOperand(s.SetOp.Left, s.SetOp.Op, false)
+		if err != nil {
+			return "", err
+		}
+		right, err := r.renderSetOperand(s.SetOp.Right, s.SetOp.Op, true)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(left)
+		switch s.SetOp.Op {
+		case ast.Union:
+			b.WriteString(" UNION ")
+		case ast.Intersect:
+			b.WriteString(" INTERSECT ")
+		case ast.Except:
+			b.WriteString(" EXCEPT ")
+		}
+		if s.SetOp.All {
+			b.WriteString("ALL ")
+		}
+		b.WriteString(right)
+		if r.top(s.Limit) {
+			// TOP belongs to a SELECT block, not to the combined result.
+			defer func(style LimitStyle) { r.limitStyle = style }(r.limitStyle)
+			r.limitStyle = LimitFetch
+		}
+	} else if s.Values != nil {
+		if r.selectHint != "" {
+			r.selectHint = ""
+			r.warn(WarnTimeoutUnsupported, s.Pos(), "MAX_EXECUTION_TIME only applies to SELECT in MySQL; no timeout was added")
+		}
+		b.WriteString(r.renderValuesRows(s.Values))
+	} else {
+		b.WriteString("SELECT ")
+		if hints := r.renderHints(s); hints != "" {
+			b.WriteString(hints + " ")
+		}
+		if s.Distinct {
+			b.WriteString("DISTINCT ")
+		}
+		b.WriteString(r.renderTop(s.Limit))
+		b.WriteString(r.renderSelectColumns(s.Columns, s.From))
+		if len(s.From) > 0 {
+			b.WriteString(" FROM ")
+			for i, tr := range s.From {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(r.renderTableRef(tr))
+			}
+		}
+		if s.Where != nil {
+			b.WriteString(" WHERE ")
+			b.WriteString(r.renderExpr(s.Where))
+		}
+		if len(s.GroupBy) > 0 {
+			b.WriteString(" GROUP BY ")
+			for i, e := range s.GroupBy {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(r.renderSortKey(e))
+			}
+		}
+		if s.Having != nil {
+			b.WriteString(" HAVING ")
+			b.WriteString(r.renderExpr(s.Having))
+		}
+		if s.Qualify != nil {
+			b.WriteString(r.renderQualify(s))
+		}
+	}
+	if len(s.OrderBy) > 0 {
+		b.WriteString(" ORDER BY ")
+		b.WriteString(r.renderOrderBy(s.OrderBy))
+	} else {
+		b.WriteString(r.fetchOrder(s.Limit, s.SetOp != nil))
+	}
+	if s.LimitBy != nil {
+		b.WriteString(r.renderLimitBy(s.LimitBy))
+	}
+	if s.Limit != nil {
+		b.WriteString(r.renderLimitClause(s.Limit))
 	}
-	for i, c := range s.Columns {
+	return b.String(), nil
+}
+
+func (r *dialectRenderer) renderOrderBy(items []ast.OrderByItem) string {
+	var b strings.Builder
+	for i, it := range items {
 		if i > 0 {
 			b.WriteString(", ")
 		}
-		if c.Star {
-			b.WriteByte('*')
+		b.WriteString(r.renderSortKey(it.Expr))
+		if it.Desc {
+			b.WriteString(" DESC")
 		} else {
-			b.WriteString(r.renderExpr(c.Expr))
-		}
-		if c.Alias != nil {
-			b.WriteString(" AS ")
-			b.WriteString(r.renderIdent(c.Alias))
+			b.WriteString(" ASC")
 		}
 	}
-	if len(s.From) > 0 {
-		b.WriteString(" FROM ")
-		for i, tr := range s.From {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			b.WriteString(r.renderTableRef(tr))
+	return b.String()
+}
+
+// renderSetOperand renders one side of a set operation, in parentheses
+// when it has its own WITH, ORDER BY or LIMIT or would otherwise group
+// differently: INTERSECT binds tighter than UNION and EXCEPT, which group
+// from the left. sqlite accepts no parentheses there and gives every set
+// operation the same precedence, so it gets a derived table instead, which
+// a left operand only needs for its own clauses.
+func (r *dialectRenderer) renderSetOperand(s *ast.SelectStmt, op ast.SetOp, right bool) (string, error) {
+	out, err := r.renderSelect(s)
+	if err != nil {
+		return "", err
+	}
+	own := s.With != nil || len(s.OrderBy) > 0 || s.LimitBy != nil || s.Limit != nil
+	grouped := false
+	if s.SetOp != nil {
+		if right {
+			grouped = r.target == DialectSQLite || setOpPrecedence(s.SetOp.Op) <= setOpPrecedence(op)
+		} else {
+			grouped = r.target != DialectSQLite && setOpPrecedence(s.SetOp.Op) < setOpPrecedence(op)
 		}
 	}
-	if s.Where != nil {
-		b.WriteString(" WHERE ")
-		b.WriteString(r.renderExpr(s.Where))
+	switch {
+	case !own && !grouped:
+		return out, nil
+	case r.target == DialectSQLite:
+		return "SELECT * FROM (" + out + ")", nil
 	}
-	if len(s.GroupBy) > 0 {
-		b.WriteString(" GROUP BY ")
-		for i, e := range s.GroupBy {
-			if i > 0 {
+	return "(" + out + ")", nil
+}
+
+func setOpPrecedence(op ast.SetOp) int {
+	if op == ast.Intersect {
+		return 2
+	}
+	return 1
+}
+
+// renderValuesRows renders VALUES (...), (...), spelling the rows ROW(...)
+// for MySQL.
+func (r *dialectRenderer) renderValuesRows(rows [][]ast.Expr) string {
+	var b strings.Builder
+	b.WriteString("VALUES ")
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(r.valuesRowStart())
+		for j, e := range row {
+			if j > 0 {
 				b.WriteString(", ")
 			}
 			b.WriteString(r.renderExpr(e))
 		}
+		b.WriteByte(')')
 	}
-	if s.Having != nil {
-		b.WriteString(" HAVING ")
-		b.WriteString(r.renderExpr(s.Having))
+	return b.String()
+}
+
+func (r *dialectRenderer) valuesRowStart() string {
+	if r.target == DialectMySQL {
+		return "ROW("
 	}
-	if len(s.OrderBy) > 0 {
-		b.WriteString(" ORDER BY ")
-		for i, it := range s.OrderBy {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			b.WriteString(r.renderExpr(it.Expr))
-			if it.Desc {
-				b.WriteString(" DESC")
-			} else {
-				b.WriteString(" ASC")
+	return "("
+}
+
+// renderDerivedTable renders a subquery in FROM with its alias and column
+// list. SQLite has no column lists, so a VALUES table is wrapped in a
+// SELECT that renames its column1, column2, ... instead.
+func (r *dialectRenderer) renderDerivedTable(t *ast.SubqueryTable) string {
+	sub, _ := r.renderSelect(t.Subq)
+	if len(t.Columns) > 0 && r.target == DialectSQLite {
+		if t.Subq.Values == nil {
+			r.warn(WarnDerivedColumnsDropped, t.TokPos, "sqlite has no derived table column lists; columns of %s keep the subquery's names", identName(t.Alias))
+		} else {
+			cols := make([]string, len(t.Columns))
+			for i, c := range t.Columns {
+				cols[i] = "column" + strconv.Itoa(i+1) + " AS " + r.renderIdent(c)
 			}
+			sub = "SELECT " + strings.Join(cols, ", ") + " FROM (" + sub + ")"
 		}
 	}
-	if s.Limit != nil {
-		b.WriteString(" LIMIT ")
-		b.WriteString(r.renderExpr(s.Limit.Count))
-		if s.Limit.Offset != nil {
-			b.WriteString(" OFFSET ")
-			b.WriteString(r.renderExpr(s.Limit.Offset))
-		}
-	}
-	if s.SetOp != nil {
-		cur := s.SetOp
-		for cur != nil {
-			b.WriteByte(' ')
-			switch cur.Op {
-			case ast.Union:
-				b.WriteString("UNION")
-			case ast.Intersect:
-				b.WriteString("INTERSECT")
-			case ast.Except:
-				b.WriteString("EXCEPT")
-			}
-			if cur.All {
-				b.WriteString(" ALL")
-			}
-			right, err := r.renderSelect(cur.Right)
-			if err != nil {
-				return "", err
+	out := "(" + sub + ")"
+	if t.Alias != nil {
+		out += " " + r.renderIdent(t.Alias)
+		if len(t.Columns) > 0 && r.target != DialectSQLite {
+			cols := make([]string, len(t.Columns))
+			for i, c := range t.Columns {
+				cols[i] = r.renderIdent(c)
 			}
-			b.WriteByte(' ')
-			b.WriteString(right)
-			cur = cur.Right.SetOp
+			out += " (" + strings.Join(cols, ", ") + ")"
 		}
 	}
-	return b.String(), nil
+	return out
 }
 
 func (r *dialectRenderer) renderInsert(s *ast.InsertStmt) (string, error) {
 	var b strings.Builder
 	b.WriteString(r.renderWith(s.With))
+	ignore := s.Ignore
+	if r.target == DialectMySQL && s.OnConflictDoNothing && len(s.OnConflictUpdate) == 0 {
+		r.warn(WarnDoNothingAsIgnore, s.TokPos, "ON CONFLICT DO NOTHING rewritten as INSERT IGNORE, which also suppresses other errors")
+		ignore = true
+	}
 	if s.Replace {
+		if r.target == DialectPostgres {
+			r.warn(WarnReplaceUnsupported, s.TokPos, "REPLACE INTO is not supported by postgres; rewrite as INSERT ... ON CONFLICT DO UPDATE")
+		}
 		b.WriteString("REPLACE INTO ")
 	} else {
 		b.WriteString("INSERT ")
-		if s.Ignore && r.target == DialectMySQL {
-			b.WriteString("IGNORE ")
+		if ignore {
+			if r.target == DialectMySQL {
+				b.WriteString("IGNORE ")
+			} else {
+				r.warn(WarnIgnoreDropped, s.TokPos, "INSERT IGNORE has no %s equivalent and was dropped", r.target)
+			}
 		}
 		b.WriteString("INTO ")
 	}
 	b.WriteString(r.renderQualifiedIdent(s.Table))
-	if len(s.Columns) > 0 {
+	cols, rows, defaults := s.Columns, s.Values, s.DefaultValues
+	if r.target == DialectSQLite {
+		cols, rows, defaults = dropDefaultColumns(cols, rows, defaults)
+	}
+	setForm := s.SetForm && len(rows) == 1 && (r.target == DialectMySQL || r.target == "")
+	switch {
+	case defaults && r.target == DialectMySQL:
+		b.WriteString(" () VALUES ()")
+	case defaults:
+		b.WriteString(" DEFAULT VALUES")
+	case setForm:
+		b.WriteString(" SET ")
+		for i, col := range cols {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(r.renderIdent(col))
+			b.WriteString(" = ")
+			b.WriteString(r.renderExpr(rows[0][i]))
+		}
+	case len(cols) > 0:
 		b.WriteString(" (")
-		for i, col := range s.Columns {
+		for i, col := range cols {
 			if i > 0 {
 				b.WriteString(", ")
 			}
@@ -265,9 +805,9 @@ func (r *dialectRenderer) renderInsert(s *ast.InsertStmt) (string, error) {
 		}
 		b.WriteString(")")
 	}
-	if len(s.Values) > 0 {
+	if len(rows) > 0 && !setForm {
 		b.WriteString(" VALUES ")
-		for i, row := range s.Values {
+		for i, row := range rows {
 			if i > 0 {
 				b.WriteString(", ")
 			}
@@ -288,21 +828,43 @@ func (r *dialectRenderer) renderInsert(s *ast.InsertStmt) (string, error) {
 		b.WriteByte(' ')
 		b.WriteString(sel)
 	}
+	// References through the row alias's column list name the target
+	// columns, so the list is not needed.
+	if s.RowAlias != nil && len(rows) > 0 && r.target == DialectMySQL {
+		b.WriteString(" AS " + r.renderIdent(s.RowAlias))
+	}
 	switch r.target {
+	case DialectClickHouse:
+		if len(s.OnDupKey) > 0 || len(s.OnConflictUpdate) > 0 || s.OnConflictDoNothing {
+			r.warn(WarnUpsertUnsupported, s.TokPos, "clickhouse has no upsert and the conflict clause was dropped; use a ReplacingMergeTree table to keep the latest row per key")
+		}
 	case DialectMySQL:
 		assign := s.OnDupKey
 		if len(assign) == 0 {
 			assign = s.OnConflictUpdate
 		}
+		if len(assign) > 0 && (len(s.OnConflictTarget) > 0 || s.OnConflictConstraint != nil) {
+			r.warn(WarnConflictTargetDropped, s.TokPos, "ON CONFLICT target dropped; ON DUPLICATE KEY UPDATE fires on any unique key")
+		}
+		var cond string
+		if s.OnConflictUpdateWhere != nil && len(s.OnDupKey) == 0 {
+			cond = r.renderExpr(s.OnConflictUpdateWhere)
+			r.warn(WarnUpsertWhereRewritten, s.OnConflictUpdateWhere.Pos(), "DO UPDATE ... WHERE rewritten as IF() around each assignment; MySQL applies assignments in order, so the condition sees columns updated before it")
+		}
 		if len(assign) > 0 {
 			b.WriteString(" ON DUPLICATE KEY UPDATE ")
 			for i, a := range assign {
 				if i > 0 {
 					b.WriteString(", ")
 				}
-				b.WriteString(r.renderIdent(a.Column))
+				col := r.renderIdent(a.Column)
+				b.WriteString(col)
 				b.WriteString(" = ")
-				b.WriteString(r.renderExpr(a.Value))
+				if cond != "" {
+					b.WriteString("IF(" + cond + ", " + r.renderExpr(a.Value) + ", " + col + ")")
+				} else {
+					b.WriteString(r.renderExpr(a.Value))
+				}
 			}
 		}
 	case DialectPostgres, DialectSQLite:
@@ -313,11 +875,17 @@ func (r *dialectRenderer) renderInsert(s *ast.InsertStmt) (string, error) {
 			assign = s.OnDupKey
 		}
 		if len(assign) > 0 || doNothing {
-			if len(target) == 0 && len(assign) > 0 {
+			constraint := s.OnConflictConstraint
+			if constraint != nil && r.target == DialectSQLite {
+				r.warn(WarnConflictConstraintDropped, constraint.Pos(), "sqlite has no ON CONFLICT ON CONSTRAINT; dropped constraint %q, so any uniqueness conflict matches", constraint.Unquoted)
+				constraint = nil
+			}
+			if len(target) == 0 && s.OnConflictConstraint == nil && len(assign) > 0 {
 				if len(s.Columns) > 0 {
 					target = []*ast.Ident{s.Columns[0]}
-				} else if r.strict {
-					return "", fmt.Errorf("cannot rewrite ON DUPLICATE KEY without conflict target")
+					r.warn(WarnConflictTargetGuessed, s.TokPos, "ON DUPLICATE KEY has no conflict target; assumed first column %q", s.Columns[0].Unquoted)
+				} else {
+					r.warn(WarnConflictTargetMissing, s.TokPos, "cannot rewrite ON DUPLICATE KEY without conflict target")
 				}
 			}
 			b.WriteString(" ON CONFLICT")
@@ -330,6 +898,11 @@ func (r *dialectRenderer) renderInsert(s *ast.InsertStmt) (string, error) {
 					b.WriteString(r.renderIdent(c))
 				}
 				b.WriteByte(')')
+				if s.OnConflictWhere != nil {
+					b.WriteString(" WHERE " + r.renderExpr(s.OnConflictWhere))
+				}
+			} else if constraint != nil {
+				b.WriteString(" ON CONSTRAINT " + r.renderIdent(constraint))
 			}
 			if doNothing && len(assign) == 0 {
 				b.WriteString(" DO NOTHING")
@@ -343,12 +916,58 @@ func (r *dialectRenderer) renderInsert(s *ast.InsertStmt) (string, error) {
 					b.WriteString(" = ")
 					b.WriteString(r.renderExpr(a.Value))
 				}
+				if s.OnConflictUpdateWhere != nil && len(s.OnConflictUpdate) > 0 {
+					b.WriteString(" WHERE " + r.renderExpr(s.OnConflictUpdateWhere))
+				}
 			}
 		}
 	}
+	b.WriteString(r.renderReturning(s.Returning, s.TokPos))
 	return b.String(), nil
 }
 
+// dropDefaultColumns removes the columns that are DEFAULT in every row,
+// since sqlite has no DEFAULT value; leaving them out has the same effect.
+// A single row left with no columns becomes DEFAULT VALUES.
+func dropDefaultColumns(cols []*ast.Ident, rows [][]ast.Expr, defaults bool) ([]*ast.Ident, [][]ast.Expr, bool) {
+	if len(cols) == 0 || len(rows) == 0 {
+		return cols, rows, defaults
+	}
+	var keep []int
+	for i := range cols {
+		for _, row := range rows {
+			if i >= len(row) {
+				return cols, rows, defaults
+			}
+			if _, ok := row[i].(*ast.DefaultExpr); !ok {
+				keep = append(keep, i)
+				break
+			}
+		}
+	}
+	switch {
+	case len(keep) == len(cols):
+		return cols, rows, defaults
+	case len(keep) == 0:
+		if len(rows) == 1 {
+			return nil, nil, true
+		}
+		return cols, rows, defaults
+	}
+	kept := make([]*ast.Ident, len(keep))
+	for i, k := range keep {
+		kept[i] = cols[k]
+	}
+	out := make([][]ast.Expr, len(rows))
+	for j, row := range rows {
+		out[j] = make([]ast.Expr, len(keep))
+		for i, k := range keep {
+			out[j][i] = row[k]
+		}
+	}
+	return kept, out, false
+}
+
 func (r *dialectRenderer) renderUpdate(s *ast.UpdateStmt) (string, error) {
 	var b strings.Builder
 	b.WriteString(r.renderWith(s.With))
@@ -384,10 +1003,14 @@ func (r *dialectRenderer) renderUpdate(s *ast.UpdateStmt) (string, error) {
 			}
 		}
 	}
-	if s.Limit != nil {
+	if s.Limit != nil && s.Limit.Count != nil {
+		if r.target == DialectPostgres {
+			r.warn(WarnLimitUnsupported, s.TokPos, "UPDATE ... LIMIT is not supported by postgres")
+		}
 		b.WriteString(" LIMIT ")
-		b.WriteString(r.renderExpr(s.Limit.Count))
+		b.WriteString(r.renderLimit(s.Limit.Count))
 	}
+	b.WriteString(r.renderReturning(s.Returning, s.TokPos))
 	return b.String(), nil
 }
 
@@ -417,26 +1040,45 @@ func (r *dialectRenderer) renderDelete(s *ast.DeleteStmt) (string, error) {
 			}
 		}
 	}
-	if s.Limit != nil {
+	if s.Limit != nil && s.Limit.Count != nil {
+		if r.target == DialectPostgres {
+			r.warn(WarnLimitUnsupported, s.TokPos, "DELETE ... LIMIT is not supported by postgres")
+		}
 		b.WriteString(" LIMIT ")
-		b.WriteString(r.renderExpr(s.Limit.Count))
+		b.WriteString(r.renderLimit(s.Limit.Count))
 	}
+	b.WriteString(r.renderReturning(s.Returning, s.TokPos))
 	return b.String(), nil
 }
 
 func (r *dialectRenderer) renderCreateTable(s *ast.CreateTableStmt) (string, error) {
 	var b strings.Builder
-	b.WriteString("CREATE TABLE ")
+	b.WriteString("CREATE ")
+	if s.Temporary {
+		b.WriteString("TEMPORARY ")
+	}
+	b.WriteString("TABLE ")
 	if s.IfNotExists {
 		b.WriteString("IF NOT EXISTS ")
 	}
 	b.WriteString(r.renderQualifiedIdent(s.Table))
+	r.table = s.Table
+	defer func() { r.table = nil }()
+	if r.target == DialectPostgres {
+		r.identityStart = autoIncrementStart(s)
+		defer func() { r.identityStart = nil }()
+	}
 	if s.Like != nil {
 		b.WriteString(" LIKE ")
 		b.WriteString(r.renderQualifiedIdent(s.Like))
 		return b.String(), nil
 	}
 	if len(s.Columns) > 0 || len(s.Constraints) > 0 {
+		var rowidPK *ast.TableConstraint
+		if r.target == DialectSQLite {
+			r.rowidKey, rowidPK = sqliteRowidKey(s)
+			defer func() { r.rowidKey = nil }()
+		}
 		b.WriteString(" (")
 		wrote := false
 		for _, col := range s.Columns {
@@ -446,21 +1088,62 @@ func (r *dialectRenderer) renderCreateTable(s *ast.CreateTableStmt) (string, err
 			wrote = true
 			b.WriteString(r.renderColumnDef(col))
 		}
-		for _, c := range s.Constraints {
+		for _, c := range r.order.constraints(s.Constraints) {
+			if c == rowidPK || r.dropsInlineIndex(c) {
+				continue
+			}
 			if wrote {
 				b.WriteString(", ")
 			}
 			wrote = true
 			b.WriteString(r.renderConstraint(c))
 		}
+		for _, col := range s.Columns {
+			if fk := r.columnForeignKey(col); fk != nil {
+				b.WriteString(", " + r.renderConstraint(fk))
+			}
+		}
 		b.WriteByte(')')
 	}
-	for _, opt := range s.Options {
+	r.writeTableStorage(&b, s)
+	strict := false
+	for _, opt := range r.order.options(s.Options) {
+		if r.identityStart != nil && strings.EqualFold(string(opt.Key), "AUTO_INCREMENT") {
+			continue // the identity column starts there
+		}
+		if opt.Type == ast.OptionFlag { // STRICT
+			if r.target == DialectSQLite {
+				strict = true
+			} else {
+				r.warn(WarnTableOptionDropped, s.TokPos, "table option %s is SQLite-specific and was dropped", opt.Key)
+			}
+			continue
+		}
+		if r.target != DialectMySQL {
+			r.warn(WarnTableOptionDropped, s.TokPos, "table option %s is MySQL-specific and was dropped", opt.Key)
+			continue
+		}
 		b.WriteByte(' ')
-		b.WriteString(string(opt.Key))
+		b.Write(opt.Key)
 		if len(opt.Value) > 0 {
 			b.WriteByte('=')
-			b.WriteString(string(opt.Value))
+			b.Write(opt.Value)
+		}
+	}
+	if strict {
+		b.WriteString(" STRICT")
+	}
+	if s.WithoutRowID {
+		switch r.target {
+		case DialectSQLite:
+			if strict {
+				b.WriteByte(',')
+			}
+			b.WriteString(" WITHOUT ROWID")
+		case DialectMySQL:
+			// InnoDB already clusters every table by its primary key.
+		default:
+			r.warn(WarnTableOptionDropped, s.TokPos, "WITHOUT ROWID is SQLite-specific and was dropped")
 		}
 	}
 	if s.Select != nil {
@@ -474,66 +1157,355 @@ func (r *dialectRenderer) renderCreateTable(s *ast.CreateTableStmt) (string, err
 	return b.String(), nil
 }
 
+// sqliteRowidKey returns the auto-increment column of s that its
+// single-column PRIMARY KEY constraint names, and the constraint. SQLite
+// only takes AUTOINCREMENT on a column declared INTEGER PRIMARY KEY, so
+// the key moves onto the column.
+func sqliteRowidKey(s *ast.CreateTableStmt) (*ast.ColumnDef, *ast.TableConstraint) {
+	for _, c := range s.Constraints {
+		if c.Type != ast.PrimaryKeyConstraint || len(c.Columns) != 1 || c.Columns[0].Name == nil || c.Columns[0].Desc {
+			continue
+		}
+		for _, col := range s.Columns {
+			_, nextval := nextvalSequence(col.Default)
+			autoInc := col.AutoIncrement || nextval || col.Type != nil && isSerialType(col.Type)
+			if autoInc && ident.Equal(col.Name.Unquoted, c.Columns[0].Name.Unquoted, DialectSQLite) {
+				return col, c
+			}
+		}
+	}
+	return nil, nil
+}
+
+// writeTableStorage writes the INHERITS, USING, WITH (...) and TABLESPACE
+// clauses of a CREATE TABLE. MySQL keeps only TABLESPACE.
+func (r *dialectRenderer) writeTableStorage(b *strings.Builder, s *ast.CreateTableStmt) {
+	if r.target != DialectPostgres {
+		if len(s.Inherits) > 0 {
+			r.warn(WarnTableOptionDropped, s.TokPos, "INHERITS is PostgreSQL-specific and was dropped; inherited columns are missing from the table")
+		}
+		if s.AccessMethod != nil {
+			r.warn(WarnTableOptionDropped, s.TokPos, "table access method USING %s is PostgreSQL-specific and was dropped", s.AccessMethod.Unquoted)
+		}
+		if len(s.StorageParams) > 0 {
+			r.warn(WarnTableOptionDropped, s.TokPos, "storage parameters WITH (...) are PostgreSQL-specific and were dropped")
+		}
+		if s.Tablespace != nil {
+			if r.target == DialectMySQL {
+				b.WriteString(" TABLESPACE " + r.renderIdent(s.Tablespace))
+			} else {
+				r.warn(WarnTableOptionDropped, s.TokPos, "TABLESPACE is not supported by %s and was dropped", r.target)
+			}
+		}
+		return
+	}
+	if len(s.Inherits) > 0 {
+		b.WriteString(" INHERITS (")
+		for i, q := range s.Inherits {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(r.renderQualifiedIdent(q))
+		}
+		b.WriteByte(')')
+	}
+	if s.AccessMethod != nil {
+		b.WriteString(" USING " + s.AccessMethod.Unquoted)
+	}
+	if len(s.StorageParams) > 0 {
+		writeStorageParams(b, s.StorageParams)
+	}
+	if s.Tablespace != nil {
+		b.WriteString(" TABLESPACE " + r.renderIdent(s.Tablespace))
+	}
+}
+
 func (r *dialectRenderer) renderAlterTable(s *ast.AlterTableStmt) (string, error) {
 	var b strings.Builder
 	b.WriteString("ALTER TABLE ")
 	b.WriteString(r.renderQualifiedIdent(s.Table))
+	r.table = s.Table
+	defer func() { r.table = nil }()
 	for i, cmd := range s.Cmds {
 		if i == 0 {
 			b.WriteByte(' ')
 		} else {
-			b.WriteString(", ")
+			b.WriteString(", ")
+		}
+		b.WriteString(r.renderAlterCmd(cmd))
+	}
+	return b.String(), nil
+}
+
+func (r *dialectRenderer) renderDropTable(s *ast.DropTableStmt) (string, error) {
+	var b strings.Builder
+	b.WriteString("DROP TABLE ")
+	if s.IfExists {
+		b.WriteString("IF EXISTS ")
+	}
+	for i, t := range s.Tables {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(r.renderQualifiedIdent(t))
+	}
+	if s.Cascade {
+		b.WriteString(" CASCADE")
+	}
+	return b.String(), nil
+}
+
+// renderTruncate renders TRUNCATE TABLE. MySQL truncates one table per
+// statement, always resets AUTO_INCREMENT and has no CASCADE, so a table
+// list becomes one statement per table. SQLite has no TRUNCATE at all: each
+// table is emptied by a DELETE FROM, and RESTART IDENTITY deletes the
+// table's row of sqlite_sequence, which holds the last AUTOINCREMENT value.
+func (r *dialectRenderer) renderTruncate(s *ast.TruncateStmt) string {
+	pg := r.target == DialectPostgres || r.target == ""
+	if s.Cascade && !pg {
+		r.warn(WarnTruncateOptionDropped, s.TokPos, "%s has no TRUNCATE ... CASCADE; truncate the referencing tables first", r.target)
+	}
+	if r.target == DialectSQLite {
+		var parts []string
+		for _, t := range s.Tables {
+			parts = append(parts, "DELETE FROM "+r.renderQualifiedIdent(t))
+		}
+		for _, t := range s.Tables {
+			if !s.RestartIdentity {
+				break
+			}
+			// sqlite_sequence lives in the schema of the table it counts
+			// for.
+			seq := "sqlite_sequence"
+			if n := len(t.Parts); n > 1 {
+				seq = r.renderIdent(t.Parts[n-2]) + "." + seq
+			}
+			name, _ := r.quoteString(t.Parts[len(t.Parts)-1].Unquoted)
+			parts = append(parts, "DELETE FROM "+seq+" WHERE name = "+name)
+		}
+		return strings.Join(parts, r.separator())
+	}
+	if r.target == DialectMySQL {
+		if len(s.Tables) > 1 {
+			r.warn(WarnTruncateSplit, s.TokPos, "mysql truncates one table per statement; split into %d statements", len(s.Tables))
+		}
+		parts := make([]string, len(s.Tables))
+		for i, t := range s.Tables {
+			parts[i] = "TRUNCATE TABLE " + r.renderQualifiedIdent(t)
+		}
+		return strings.Join(parts, r.separator())
+	}
+	var b strings.Builder
+	b.WriteString("TRUNCATE TABLE ")
+	for i, t := range s.Tables {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(r.renderQualifiedIdent(t))
+	}
+	if pg && s.RestartIdentity {
+		b.WriteString(" RESTART IDENTITY")
+	}
+	if pg && s.Cascade {
+		b.WriteString(" CASCADE")
+	}
+	return b.String()
+}
+
+func (r *dialectRenderer) renderCreateIndex(s *ast.CreateIndexStmt) (string, error) {
+	var b strings.Builder
+	b.WriteString("CREATE ")
+	b.WriteString(r.indexKind(s.Type, &ast.Ident{Unquoted: defaultIndexName(s)}, s.Pos()))
+	b.WriteString("INDEX ")
+	// MySQL builds InnoDB indexes online anyway, and SQLite has nothing
+	// to block.
+	if s.Concurrently && (r.target == DialectPostgres || r.target == "") {
+		b.WriteString("CONCURRENTLY ")
+	}
+	switch {
+	case s.Name != nil:
+		b.WriteString(r.renderIdent(s.Name) + " ")
+	case r.target != DialectPostgres && r.target != "":
+		// The others need a name; they get the one PostgreSQL chooses.
+		b.WriteString(r.renderIdent(&ast.Ident{Unquoted: defaultIndexName(s)}) + " ")
+	}
+	b.WriteString("ON ")
+	b.WriteString(r.renderQualifiedIdent(s.Table))
+	method := r.indexMethod(s)
+	if method != "" && r.target != DialectMySQL {
+		b.WriteString(" USING ")
+		b.WriteString(method)
+	}
+	b.WriteString(" (")
+	for i, c := range r.order.indexColumns(s.Type, s.Columns) {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(r.renderIndexKeyPart(c, s.Pos()))
+	}
+	b.WriteByte(')')
+	if method != "" && r.target == DialectMySQL {
+		b.WriteString(" USING ")
+		b.WriteString(method)
+	}
+	r.writeIndexOptions(&b, s)
+	if s.Where != nil {
+		if r.target == DialectMySQL {
+			r.warn(WarnPartialIndexDropped, s.Where.Pos(), "mysql has no partial indexes; index %s covers every row", defaultIndexName(s))
+		} else {
+			b.WriteString(" WHERE ")
+			b.WriteString(r.renderExpr(s.Where))
+		}
+	}
+	return b.String(), nil
+}
+
+// defaultIndexName returns the name of the index, or for the CREATE INDEX
+// ON t (a, b) PostgreSQL allows, the name PostgreSQL gives it: t_a_b_idx,
+// or t_a_b_key for a unique index, with a function's name standing for a
+// key part that calls it and expr for other expressions.
+func defaultIndexName(s *ast.CreateIndexStmt) string {
+	if s.Name != nil {
+		return s.Name.Unquoted
+	}
+	parts := []string{s.Table.Parts[len(s.Table.Parts)-1].Unquoted}
+	for _, c := range s.Columns {
+		switch e := c.Expr.(type) {
+		case nil:
+			parts = append(parts, identName(c.Name))
+		case *ast.FuncCall:
+			parts = append(parts, e.Name.Parts[len(e.Name.Parts)-1].Unquoted)
+		default:
+			parts = append(parts, "expr")
+		}
+	}
+	if s.Type == ast.UniqueConstraint {
+		return strings.Join(parts, "_") + "_key"
+	}
+	return strings.Join(parts, "_") + "_idx"
+}
+
+// writeIndexOptions writes the INCLUDE and WITH (...) clauses of a CREATE
+// INDEX for PostgreSQL, and the index options and INVISIBLE for MySQL.
+func (r *dialectRenderer) writeIndexOptions(b *strings.Builder, s *ast.CreateIndexStmt) {
+	pg := r.target == DialectPostgres || r.target == ""
+	my := r.target == DialectMySQL || r.target == ""
+	if len(s.Include) > 0 {
+		if pg {
+			b.WriteString(" INCLUDE (")
+			for i, id := range s.Include {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(r.renderIdent(id))
+			}
+			b.WriteByte(')')
+		} else {
+			r.warn(WarnIndexOptionDropped, s.Pos(), "%s has no covering indexes; INCLUDE columns of index %s dropped", r.target, identName(s.Name))
+		}
+	}
+	if len(s.StorageParams) > 0 {
+		if pg {
+			writeStorageParams(b, s.StorageParams)
+		} else {
+			r.warn(WarnIndexOptionDropped, s.Pos(), "storage parameters WITH (...) of index %s are PostgreSQL-specific and were dropped", identName(s.Name))
+		}
+	}
+	for _, opt := range s.Options {
+		if !my {
+			r.warn(WarnIndexOptionDropped, s.Pos(), "index option %s is MySQL-specific and was dropped", opt.Key)
+			continue
+		}
+		b.WriteByte(' ')
+		b.Write(opt.Key)
+		if string(opt.Key) == "COMMENT" {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte('=')
+		}
+		b.Write(opt.Value)
+	}
+	if s.Invisible {
+		if my {
+			b.WriteString(" INVISIBLE")
+		} else {
+			r.warn(WarnIndexOptionDropped, s.Pos(), "%s has no invisible indexes; the planner may use index %s", r.target, identName(s.Name))
 		}
-		b.WriteString(r.renderAlterCmd(cmd))
 	}
-	return b.String(), nil
 }
 
-func (r *dialectRenderer) renderDropTable(s *ast.DropTableStmt) (string, error) {
-	var b strings.Builder
-	b.WriteString("DROP TABLE ")
-	if s.IfExists {
-		b.WriteString("IF EXISTS ")
-	}
-	for i, t := range s.Tables {
+// writeStorageParams writes the WITH (name = value, ...) clause of a
+// PostgreSQL table or index.
+func writeStorageParams(b *strings.Builder, params []ast.TableOption) {
+	b.WriteString(" WITH (")
+	for i, o := range params {
 		if i > 0 {
 			b.WriteString(", ")
 		}
-		b.WriteString(r.renderQualifiedIdent(t))
-	}
-	if s.Cascade {
-		b.WriteString(" CASCADE")
+		b.WriteString(strings.ToLower(string(o.Key)))
+		if o.Value != nil {
+			b.WriteString(" = " + string(o.Value))
+		}
 	}
-	return b.String(), nil
+	b.WriteByte(')')
 }
 
-func (r *dialectRenderer) renderCreateIndex(s *ast.CreateIndexStmt) (string, error) {
-	var b strings.Builder
-	b.WriteString("CREATE ")
-	if s.Type == ast.UniqueConstraint {
-		b.WriteString("UNIQUE ")
+// indexMethod returns the USING method of s for the target, or "" when it
+// has none or the target lacks it.
+func (r *dialectRenderer) indexMethod(s *ast.CreateIndexStmt) string {
+	if s.Method == nil {
+		if s.Type == ast.SpatialConstraint && r.target == DialectPostgres {
+			return "GIST"
+		}
+		return ""
 	}
-	b.WriteString("INDEX ")
-	b.WriteString(r.renderIdent(s.Name))
-	b.WriteString(" ON ")
-	b.WriteString(r.renderQualifiedIdent(s.Table))
-	b.WriteString(" (")
-	for i, c := range s.Columns {
-		if i > 0 {
-			b.WriteString(", ")
+	method := string(s.Method)
+	switch r.target {
+	case DialectMySQL:
+		if upper := strings.ToUpper(method); upper == "BTREE" || upper == "HASH" {
+			return upper
 		}
-		b.WriteString(r.renderIdent(c.Name))
+	case DialectSQLite:
+	default:
+		return method
+	}
+	r.warn(WarnIndexMethodDropped, s.Pos(), "%s has no %s indexes; index %s uses the default method", r.target, method, identName(s.Name))
+	return ""
+}
+
+// renderIndexKeyPart renders a column or expression of CREATE INDEX.
+func (r *dialectRenderer) renderIndexKeyPart(c *ast.IndexColDef, pos int32) string {
+	var out string
+	if c.Expr != nil {
+		out = "(" + r.renderExpr(c.Expr) + ")"
+	} else {
+		out = r.renderIdent(c.Name)
 		if c.Length != nil {
-			b.WriteByte('(')
-			b.WriteString(strconv.Itoa(*c.Length))
-			b.WriteByte(')')
+			out += "(" + strconv.Itoa(*c.Length) + ")"
 		}
-		if c.Desc {
-			b.WriteString(" DESC")
+	}
+	if c.OpClass != nil {
+		if r.target == DialectPostgres || r.target == "" {
+			out += " " + string(c.OpClass)
+		} else {
+			r.warn(WarnIndexMethodDropped, pos, "%s has no operator classes; %s dropped", r.target, c.OpClass)
 		}
 	}
-	b.WriteByte(')')
-	return b.String(), nil
+	if c.Desc {
+		out += " DESC"
+	}
+	if c.NullsFirst != nil {
+		nulls := " NULLS LAST"
+		if *c.NullsFirst {
+			nulls = " NULLS FIRST"
+		}
+		if r.target == DialectPostgres || r.target == "" {
+			out += nulls
+		} else {
+			r.warn(WarnIndexOptionDropped, pos, "%s indexes have no null ordering;%s dropped", r.target, nulls)
+		}
+	}
+	return out
 }
 
 func (r *dialectRenderer) renderDropIndex(s *ast.DropIndexStmt) (string, error) {
@@ -557,6 +1529,13 @@ func (r *dialectRenderer) renderCreateView(s *ast.CreateViewStmt) (string, error
 	if s.OrReplace {
 		b.WriteString("OR REPLACE ")
 	}
+	if s.Temporary {
+		if r.target == DialectMySQL {
+			r.warn(WarnTemporaryViewUnsupported, s.TokPos, "mysql has no temporary views; the view was created as a regular view")
+		} else {
+			b.WriteString("TEMPORARY ")
+		}
+	}
 	b.WriteString("VIEW ")
 	b.WriteString(r.renderQualifiedIdent(s.Name))
 	if len(s.Columns) > 0 {
@@ -619,17 +1598,6 @@ func (r *dialectRenderer) renderDropDatabase(s *ast.DropDatabaseStmt) (string, e
 	return out + r.renderIdent(s.Name), nil
 }
 
-func (r *dialectRenderer) renderShow(s *ast.ShowStmt) (string, error) {
-	out := "SHOW " + string(s.What)
-	if s.Like != nil {
-		out += " LIKE " + r.renderExpr(s.Like)
-	}
-	if s.Where != nil {
-		out += " WHERE " + r.renderExpr(s.Where)
-	}
-	return out, nil
-}
-
 func (r *dialectRenderer) renderCall(s *ast.CallStmt) (string, error) {
 	var b strings.Builder
 	b.WriteString("CALL ")
@@ -677,66 +1645,276 @@ func (r *dialectRenderer) renderTx(s *ast.TransactionStmt) string {
 	}
 }
 
+// renderGenericDDL renders a statement the parser does not model. Its
+// body is copied as written, so unless the SQL is known to be written for
+// the target, it may not be valid there.
 func (r *dialectRenderer) renderGenericDDL(s *ast.GenericDDLStmt) string {
-	out := strings.ToUpper(string(s.Verb)) + " " + strings.ToUpper(string(s.Object))
+	what := strings.ToUpper(string(s.Verb)) + " " + strings.ToUpper(string(s.Object))
+	out := what
 	if s.Name != nil {
 		out += " " + r.renderIdent(s.Name)
 	}
+	if len(s.Body) > 0 {
+		if r.source == "" || r.source != r.target {
+			r.warn(WarnBodyNotConverted, s.TokPos, "the rest of %s was copied as written and not converted to %s", what, r.target)
+		}
+		body := string(s.Body)
+		r.verbatim = append(r.verbatim, body)
+		out += " " + body
+	}
 	return out
 }
 
+var maintenanceVerbs = [...]string{
+	ast.CreateExtension: "CREATE EXTENSION",
+	ast.Vacuum:          "VACUUM",
+	ast.Analyze:         "ANALYZE",
+	ast.Reindex:         "REINDEX",
+	ast.Cluster:         "CLUSTER",
+	ast.OptimizeTable:   "OPTIMIZE",
+	ast.AnalyzeTable:    "ANALYZE",
+	ast.CheckTable:      "CHECK",
+}
+
+// maintenanceSupported reports whether target understands statements of
+// the given kind. Maintenance statements are emitted as-is either way.
+func maintenanceSupported(kind ast.MaintenanceKind, target Dialect) bool {
+	switch kind {
+	case ast.CreateExtension, ast.Cluster:
+		return target == DialectPostgres
+	case ast.Vacuum, ast.Analyze, ast.Reindex:
+		return target == DialectPostgres || target == DialectSQLite
+	default:
+		return target == DialectMySQL
+	}
+}
+
+// sqliteMaintenanceForm reports whether s is in the form SQLite accepts:
+// VACUUM, ANALYZE and REINDEX take at most one name, without the options,
+// object class and column lists of PostgreSQL.
+func sqliteMaintenanceForm(s *ast.MaintenanceStmt) bool {
+	if len(s.Options) > 0 || s.Object != nil || len(s.Targets) > 1 {
+		return false
+	}
+	return len(s.Targets) == 0 || len(s.Targets[0].Columns) == 0
+}
+
+func (r *dialectRenderer) renderMaintenance(s *ast.MaintenanceStmt) string {
+	verb := maintenanceVerbs[s.Kind]
+	switch {
+	case !maintenanceSupported(s.Kind, r.target):
+		r.warn(WarnMaintenanceVendor, s.TokPos, "%s is not supported by %s and was emitted unchanged", verb, r.target)
+	case r.target == DialectSQLite && !sqliteMaintenanceForm(s):
+		r.warn(WarnMaintenanceVendor, s.TokPos, "%s takes only a name in sqlite; its PostgreSQL options were emitted unchanged", verb)
+	}
+	var b strings.Builder
+	b.WriteString(verb)
+	var trailing []ast.TableOption
+	switch s.Kind {
+	case ast.CreateExtension:
+		if s.IfNotExists {
+			b.WriteString(" IF NOT EXISTS")
+		}
+		r.writeMaintenanceTargets(&b, s)
+		for _, o := range s.Options {
+			b.WriteString(" " + strings.ToUpper(string(o.Key)))
+			if o.Value != nil {
+				b.WriteString(" " + string(o.Value))
+			}
+		}
+		return b.String()
+	case ast.OptimizeTable, ast.AnalyzeTable, ast.CheckTable:
+		for _, o := range s.Options {
+			if s.Kind == ast.CheckTable {
+				trailing = append(trailing, o)
+				continue
+			}
+			b.WriteString(" " + strings.ToUpper(string(o.Key)))
+		}
+		b.WriteString(" TABLE")
+		r.writeMaintenanceTargets(&b, s)
+		for _, o := range trailing {
+			b.WriteString(" " + strings.ToUpper(string(o.Key)))
+			if o.Value != nil {
+				b.WriteString(" " + strings.ToUpper(string(o.Value)))
+			}
+		}
+		return b.String()
+	}
+	var opts []string
+	for _, o := range s.Options {
+		switch {
+		case strings.EqualFold(string(o.Key), "concurrently"), strings.EqualFold(string(o.Key), "using"):
+			trailing = append(trailing, o)
+		case o.Value != nil:
+			opts = append(opts, strings.ToUpper(string(o.Key))+" "+string(o.Value))
+		default:
+			opts = append(opts, strings.ToUpper(string(o.Key)))
+		}
+	}
+	if len(opts) > 0 {
+		b.WriteString(" (" + strings.Join(opts, ", ") + ")")
+	}
+	if s.Object != nil {
+		b.WriteString(" " + strings.ToUpper(string(s.Object)))
+	}
+	for _, o := range trailing {
+		if strings.EqualFold(string(o.Key), "concurrently") {
+			b.WriteString(" CONCURRENTLY")
+		}
+	}
+	r.writeMaintenanceTargets(&b, s)
+	for _, o := range trailing {
+		if strings.EqualFold(string(o.Key), "using") {
+			b.WriteString(" USING " + string(o.Value))
+		}
+	}
+	return b.String()
+}
+
+func (r *dialectRenderer) writeMaintenanceTargets(b *strings.Builder, s *ast.MaintenanceStmt) {
+	for i, t := range s.Targets {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte(' ')
+		b.WriteString(r.renderQualifiedIdent(t.Name))
+		if len(t.Columns) > 0 {
+			b.WriteString(" (")
+			for j, c := range t.Columns {
+				if j > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(r.renderIdent(c))
+			}
+			b.WriteByte(')')
+		}
+	}
+}
+
 func (r *dialectRenderer) renderColumnDef(c *ast.ColumnDef) string {
+	// Without sequences, a SERIAL or DEFAULT nextval(...) column is an
+	// AUTO_INCREMENT one.
+	_, nextval := nextvalSequence(c.Default)
+	fromSeq := r.target != DialectPostgres && (nextval || c.Type != nil && isSerialType(c.Type))
+	autoInc := c.AutoIncrement || fromSeq
+	primaryKey := c.PrimaryKey || c == r.rowidKey
+	// SQLite only accepts AUTOINCREMENT on an INTEGER PRIMARY KEY column.
+	sqliteAutoInc := autoInc && r.target == DialectSQLite
+	if sqliteAutoInc && !primaryKey {
+		r.warn(WarnAutoIncrementDropped, c.TokPos, "SQLite only supports AUTOINCREMENT on a column declared INTEGER PRIMARY KEY; it was dropped")
+		sqliteAutoInc = false
+	}
+	typ, domain := r.resolveDomain(c.Type)
 	var b strings.Builder
 	b.WriteString(r.renderIdent(c.Name))
-	if c.Type != nil {
+	if sqliteAutoInc {
+		b.WriteString(" INTEGER")
+	} else if typ != nil {
 		b.WriteByte(' ')
-		b.WriteString(r.renderDataType(c.Type))
+		b.WriteString(r.renderColumnType(c.Name, typ))
+		r.writeSRID(&b, typ)
 	}
-	if c.NotNull {
+	if c.NotNull || domain != nil && domain.NotNull {
 		b.WriteString(" NOT NULL")
+	} else if r.target == DialectClickHouse && !c.PrimaryKey && typ != nil && typ.Elem == nil && typ.Fields == nil && !isClickHouseShape(typ) {
+		// ClickHouse columns are NOT NULL unless declared otherwise;
+		// arrays, tuples and geo types cannot be nullable.
+		b.WriteString(" NULL")
 	}
-	if c.Default != nil {
+	def := c.Default
+	if def == nil && domain != nil {
+		def = domain.Default
+	}
+	if def != nil && !(fromSeq && nextval) {
 		b.WriteString(" DEFAULT ")
-		b.WriteString(r.renderExpr(c.Default))
+		if r.target == DialectMySQL && isUUIDCall(def) {
+			// MySQL only takes a function call as a default in parentheses.
+			b.WriteString("(" + r.renderExpr(def) + ")")
+		} else {
+			b.WriteString(r.renderExpr(def))
+		}
 	}
-	if c.AutoIncrement {
-		if r.target == DialectPostgres {
+	if autoInc {
+		switch r.target {
+		case DialectPostgres:
 			// keep conservative and dialect-safe without mutating type inference
 			b.WriteString(" GENERATED BY DEFAULT AS IDENTITY")
-		} else {
+			if r.identityStart != nil {
+				b.WriteString(" (START WITH " + string(r.identityStart) + ")")
+			}
+		case DialectSQLite:
+			// written after PRIMARY KEY below
+		case DialectClickHouse:
+			r.warn(WarnAutoIncrementDropped, c.TokPos, "clickhouse has no auto-increment columns; AUTO_INCREMENT was dropped from %s, so ids must be supplied on insert", c.Name.Unquoted)
+		default:
 			b.WriteString(" AUTO_INCREMENT")
 		}
 	}
-	if c.PrimaryKey {
+	if primaryKey {
 		b.WriteString(" PRIMARY KEY")
 	}
+	if sqliteAutoInc {
+		b.WriteString(" AUTOINCREMENT")
+	}
 	if c.Unique {
 		b.WriteString(" UNIQUE")
 	}
+	if ref := c.References; ref != nil && r.target != DialectMySQL {
+		r.writeReferences(&b, ref, c.TokPos)
+		if ref.Deferrable {
+			r.writeDeferrable(&b, true, ref.InitiallyDeferred, c.TokPos)
+		}
+	}
+	r.writeTypeChecks(&b, c.Name, typ, domain)
 	if c.Comment != nil {
-		b.WriteString(" COMMENT ")
-		b.WriteString(r.renderExpr(c.Comment))
+		if r.target == DialectMySQL {
+			b.WriteString(" COMMENT ")
+			b.WriteString(r.renderExpr(c.Comment))
+		} else {
+			r.warn(WarnColumnCommentDropped, c.TokPos, "inline column COMMENT is not supported by %s and was dropped", r.target)
+		}
 	}
 	return b.String()
 }
 
 func (r *dialectRenderer) renderDataType(dt *ast.DataType) string {
+	if dt.Elem != nil || dt.Fields != nil {
+		return r.renderNestedType(dt)
+	}
+	if typ, ok := r.overrideType(dt); ok {
+		return typ
+	}
+	if typ, ok := r.renderSpatialType(dt); ok {
+		return typ
+	}
+	if r.target == DialectClickHouse {
+		return r.renderClickHouseType(dt)
+	}
 	name := string(dt.Name)
-	switch {
-	case strings.EqualFold(name, "jsonb"):
-		if r.target == DialectMySQL {
-			name = "JSON"
-		}
-		if r.target == DialectSQLite {
-			name = "TEXT"
-		}
-	case strings.EqualFold(name, "json"):
-		if r.target == DialectSQLite {
-			name = "TEXT"
-		}
+	if portable, ok := r.portableType(name); ok {
+		name = portable
+	}
+	if r.target != DialectPostgres && isSerialType(dt) {
+		return r.renderDataTypeAs(dt, serialBaseType(name))
+	}
+	name, sized := r.mapType(dt, name)
+	if !sized {
+		t := *dt
+		t.Precision, t.Scale = 0, 0
+		dt = &t
 	}
+	return r.renderDataTypeAs(dt, name)
+}
+
+// renderDataTypeAs renders dt under name, with its enum members,
+// precision and MySQL's UNSIGNED and ZEROFILL.
+func (r *dialectRenderer) renderDataTypeAs(dt *ast.DataType, name string) string {
 	var b strings.Builder
 	b.WriteString(name)
+	if len(dt.EnumVals) > 0 {
+		b.WriteString("(" + r.enumList(dt.EnumVals) + ")")
+	}
 	if dt.Precision > 0 {
 		b.WriteByte('(')
 		b.WriteString(strconv.Itoa(dt.Precision))
@@ -746,18 +1924,33 @@ func (r *dialectRenderer) renderDataType(dt *ast.DataType) string {
 		}
 		b.WriteByte(')')
 	}
-	if dt.Unsigned && r.target == DialectMySQL {
-		b.WriteString(" UNSIGNED")
+	if dt.Unsigned {
+		if r.target == DialectMySQL {
+			b.WriteString(" UNSIGNED")
+		} else {
+			r.warn(WarnUnsignedDropped, dt.TokPos, "UNSIGNED dropped from %s; the value range is no longer enforced", name)
+		}
 	}
-	if dt.Zerofill && r.target == DialectMySQL {
-		b.WriteString(" ZEROFILL")
+	if dt.Zerofill {
+		if r.target == DialectMySQL {
+			b.WriteString(" ZEROFILL")
+		} else {
+			r.warn(WarnZerofillDropped, dt.TokPos, "ZEROFILL dropped from %s", name)
+		}
 	}
 	return b.String()
 }
 
 func (r *dialectRenderer) renderConstraint(c *ast.TableConstraint) string {
 	var b strings.Builder
-	if c.Name != nil {
+	if c.Type == ast.FulltextConstraint || c.Type == ast.SpatialConstraint {
+		b.WriteString(r.indexKind(c.Type, c.Name, c.TokPos))
+		b.WriteString("INDEX")
+		if c.Name != nil {
+			b.WriteByte(' ')
+			b.WriteString(r.renderIdent(c.Name))
+		}
+	} else if c.Name != nil {
 		b.WriteString("CONSTRAINT ")
 		b.WriteString(r.renderIdent(c.Name))
 		b.WriteByte(' ')
@@ -776,29 +1969,125 @@ func (r *dialectRenderer) renderConstraint(c *ast.TableConstraint) string {
 	}
 	if len(c.Columns) > 0 {
 		b.WriteString(" (")
-		for i, col := range c.Columns {
+		for i, col := range r.order.indexColumns(c.Type, c.Columns) {
 			if i > 0 {
 				b.WriteString(", ")
 			}
-			b.WriteString(r.renderIdent(col.Name))
+			if col.Expr != nil {
+				b.WriteString("(" + r.renderExpr(col.Expr) + ")")
+			} else {
+				b.WriteString(r.renderIdent(col.Name))
+			}
 		}
 		b.WriteByte(')')
 	}
 	if c.RefTable != nil {
-		b.WriteString(" REFERENCES ")
-		b.WriteString(r.renderQualifiedIdent(c.RefTable))
-		if len(c.RefCols) > 0 {
-			b.WriteString(" (")
-			for i, col := range c.RefCols {
-				if i > 0 {
-					b.WriteString(", ")
-				}
-				b.WriteString(r.renderIdent(col))
+		r.writeReferences(&b, &ast.ForeignKeyRef{Table: c.RefTable, Columns: c.RefCols, Match: c.Match, OnDelete: c.OnDelete, OnUpdate: c.OnUpdate}, c.TokPos)
+	}
+	if c.Deferrable {
+		r.writeDeferrable(&b, c.Type == ast.ForeignKeyConstraint, c.InitiallyDeferred, c.TokPos)
+	}
+	return b.String()
+}
+
+// writeReferences writes the REFERENCES clause of a foreign key, without
+// its deferrability.
+func (r *dialectRenderer) writeReferences(b *strings.Builder, ref *ast.ForeignKeyRef, pos int32) {
+	b.WriteString(" REFERENCES ")
+	b.WriteString(r.renderQualifiedIdent(ref.Table))
+	if len(ref.Columns) > 0 {
+		b.WriteString(" (")
+		for i, col := range ref.Columns {
+			if i > 0 {
+				b.WriteString(", ")
 			}
-			b.WriteByte(')')
+			b.WriteString(r.renderIdent(col))
 		}
+		b.WriteByte(')')
+	}
+	r.writeFKMatch(b, ref.Match, pos)
+	writeRefAction(b, "DELETE", ref.OnDelete)
+	writeRefAction(b, "UPDATE", ref.OnUpdate)
+}
+
+// columnForeignKey returns the foreign key of a column-level REFERENCES as
+// a table constraint for MySQL, which parses column-level REFERENCES but
+// creates no foreign key for it; it returns nil for the other targets,
+// which keep the REFERENCES on the column.
+func (r *dialectRenderer) columnForeignKey(c *ast.ColumnDef) *ast.TableConstraint {
+	ref := c.References
+	if ref == nil || r.target != DialectMySQL {
+		return nil
+	}
+	return &ast.TableConstraint{
+		Type:              ast.ForeignKeyConstraint,
+		Columns:           []*ast.IndexColDef{{Name: c.Name}},
+		RefTable:          ref.Table,
+		RefCols:           ref.Columns,
+		OnDelete:          ref.OnDelete,
+		OnUpdate:          ref.OnUpdate,
+		Match:             ref.Match,
+		Deferrable:        ref.Deferrable,
+		InitiallyDeferred: ref.InitiallyDeferred,
+		TokPos:            c.TokPos,
+	}
+}
+
+// writeFKMatch writes the MATCH type of a foreign key. MySQL and SQLite
+// parse MATCH but check every foreign key as MATCH SIMPLE.
+func (r *dialectRenderer) writeFKMatch(b *strings.Builder, m ast.FKMatch, pos int32) {
+	var name string
+	switch m {
+	case ast.MatchDefault:
+		return
+	case ast.MatchSimple:
+		name = "SIMPLE"
+	case ast.MatchFull:
+		name = "FULL"
+	case ast.MatchPartial:
+		name = "PARTIAL"
+	}
+	if r.target == DialectPostgres || r.target == "" {
+		b.WriteString(" MATCH " + name)
+		return
+	}
+	if m != ast.MatchSimple {
+		r.warn(WarnFKMatchDropped, pos, "%s ignores MATCH %s; the foreign key is checked as MATCH SIMPLE", r.target, name)
+	}
+}
+
+// writeRefAction writes ON DELETE or ON UPDATE, unless the action is the
+// default NO ACTION.
+func writeRefAction(b *strings.Builder, event string, a ast.RefAction) {
+	var action string
+	switch a {
+	case ast.Restrict:
+		action = "RESTRICT"
+	case ast.Cascade:
+		action = "CASCADE"
+	case ast.SetNull:
+		action = "SET NULL"
+	case ast.SetDefault:
+		action = "SET DEFAULT"
+	default:
+		return
+	}
+	b.WriteString(" ON " + event + " " + action)
+}
+
+// writeDeferrable writes DEFERRABLE [INITIALLY DEFERRED]. SQLite defers
+// only foreign keys, and MySQL checks every constraint immediately.
+func (r *dialectRenderer) writeDeferrable(b *strings.Builder, foreignKey, initiallyDeferred bool, pos int32) {
+	switch {
+	case r.target == DialectPostgres || r.target == "",
+		r.target == DialectSQLite && foreignKey:
+		b.WriteString(" DEFERRABLE")
+		if initiallyDeferred {
+			b.WriteString(" INITIALLY DEFERRED")
+		}
+	default:
+		r.warn(WarnDeferrableUnsupported, pos, "%s has no deferrable constraints; DEFERRABLE was dropped and the constraint is checked immediately", r.target)
 	}
-	return b.String()
 }
 
 func (r *dialectRenderer) renderAlterCmd(cmd ast.AlterCmd) string {
@@ -811,17 +2100,44 @@ func (r *dialectRenderer) renderAlterCmd(cmd ast.AlterCmd) string {
 		if c.After != nil {
 			out += " AFTER " + r.renderIdent(c.After)
 		}
+		if fk := r.columnForeignKey(c.Col); fk != nil {
+			out += ", ADD " + r.renderConstraint(fk)
+		}
 		return out
 	case *ast.DropColumnCmd:
 		return "DROP COLUMN " + r.renderIdent(c.Name)
 	case *ast.ModifyColumnCmd:
 		out := "MODIFY COLUMN " + r.renderColumnDef(c.Col)
+		if c.OldName != nil {
+			out = "CHANGE COLUMN " + r.renderIdent(c.OldName) + " " + r.renderColumnDef(c.Col)
+		}
 		if c.First {
 			out += " FIRST"
 		}
 		if c.After != nil {
 			out += " AFTER " + r.renderIdent(c.After)
 		}
+		if fk := r.columnForeignKey(c.Col); fk != nil {
+			out += ", ADD " + r.renderConstraint(fk)
+		}
+		return out
+	case *ast.AlterColumnCmd:
+		out := "ALTER COLUMN " + r.renderIdent(c.Name)
+		switch c.Action {
+		case ast.AlterColumnType:
+			out += " TYPE " + r.renderDataType(c.Type)
+			if c.Using != nil {
+				out += " USING " + r.renderExpr(c.Using)
+			}
+		case ast.AlterColumnSetNotNull:
+			out += " SET NOT NULL"
+		case ast.AlterColumnDropNotNull:
+			out += " DROP NOT NULL"
+		case ast.AlterColumnSetDefault:
+			out += " SET DEFAULT " + r.renderExpr(c.Default)
+		case ast.AlterColumnDropDefault:
+			out += " DROP DEFAULT"
+		}
 		return out
 	case *ast.AddConstraintCmd:
 		return "ADD " + r.renderConstraint(c.Constraint)
@@ -841,14 +2157,17 @@ func (r *dialectRenderer) renderTableRef(tr ast.TableRef) string {
 		if t.Alias != nil {
 			out += " " + r.renderIdent(t.Alias)
 		}
-		return out
-	case *ast.SubqueryTable:
-		sub, _ := r.renderSelect(t.Subq)
-		out := "(" + sub + ")"
-		if t.Alias != nil {
-			out += " " + r.renderIdent(t.Alias)
+		for _, h := range t.IndexHints {
+			out += r.renderIndexHint(h)
+		}
+		if t.Sample != nil {
+			out += r.renderTableSample(t.Sample)
 		}
 		return out
+	case *ast.SubqueryTable:
+		return r.renderDerivedTable(t)
+	case *ast.PivotTable:
+		return r.renderPivot(t)
 	case *ast.JoinTable:
 		out := r.renderTableRef(t.Left) + " "
 		switch t.Kind {
@@ -885,25 +2204,104 @@ func (r *dialectRenderer) renderTableRef(tr ast.TableRef) string {
 	}
 }
 
+// renderIndexHint renders a MySQL index hint. sqlite's INDEXED BY takes
+// the place of FORCE INDEX on one index; other targets have no hints.
+func (r *dialectRenderer) renderIndexHint(h ast.IndexHint) string {
+	switch {
+	case r.target == DialectMySQL || r.target == "":
+	case r.target == DialectSQLite && h.Kind == ast.ForceIndex && h.For == ast.HintAll && len(h.Indexes) == 1:
+		return " INDEXED BY " + r.renderIdent(h.Indexes[0])
+	default:
+		r.warn(WarnIndexHintDropped, h.TokPos, "index hints are not supported by %s and were dropped", r.target)
+		return ""
+	}
+	out := [...]string{ast.UseIndex: " USE INDEX", ast.ForceIndex: " FORCE INDEX", ast.IgnoreIndex: " IGNORE INDEX"}[h.Kind]
+	switch h.For {
+	case ast.HintJoin:
+		out += " FOR JOIN"
+	case ast.HintOrderBy:
+		out += " FOR ORDER BY"
+	case ast.HintGroupBy:
+		out += " FOR GROUP BY"
+	}
+	out += " ("
+	for i, id := range h.Indexes {
+		if i > 0 {
+			out += ", "
+		}
+		out += r.renderIdent(id)
+	}
+	return out + ")"
+}
+
+// renderTableSample renders TABLESAMPLE, which only postgres has.
+func (r *dialectRenderer) renderTableSample(t *ast.TableSample) string {
+	if r.target != DialectPostgres && r.target != "" {
+		r.warn(WarnTableSampleDropped, t.TokPos, "TABLESAMPLE is not supported by %s and was dropped; the query reads the whole table", r.target)
+		return ""
+	}
+	out := " TABLESAMPLE " + strings.ToUpper(t.Method.Unquoted) + " ("
+	for i, e := range t.Args {
+		if i > 0 {
+			out += ", "
+		}
+		out += r.renderExpr(e)
+	}
+	out += ")"
+	if t.Repeatable != nil {
+		out += " REPEATABLE (" + r.renderExpr(t.Repeatable) + ")"
+	}
+	return out
+}
+
 func (r *dialectRenderer) renderExpr(expr Expr) string {
 	switch e := expr.(type) {
 	case *ast.Ident:
+		if r.domainValue != "" && strings.EqualFold(string(e.Raw), "value") {
+			return r.domainValue
+		}
 		return r.renderIdent(e)
 	case *ast.QualifiedIdent:
 		return r.renderQualifiedIdent(e)
 	case *ast.StarExpr:
 		return "*"
+	case *ast.TableStar:
+		return r.renderQualifiedIdent(e.Table) + ".*"
 	case *ast.Literal:
+		if out, ok := r.liftLiteral(e); ok {
+			return out
+		}
+		if out, ok := r.renderBool(e); ok {
+			return out
+		}
+		if out, ok := r.renderString(e); ok {
+			return out
+		}
 		return string(e.Raw)
 	case *ast.NullLit:
 		return "NULL"
+	case *ast.DefaultExpr:
+		if r.target == DialectSQLite {
+			r.warn(WarnDefaultValueUnsupported, e.TokPos, "sqlite has no DEFAULT value; leave the column out of the INSERT instead")
+		}
+		return "DEFAULT"
 	case *ast.Param:
+		if r.recordParams {
+			r.params = append(r.params, e)
+		}
 		return r.renderParam(e.Raw)
 	case *ast.BinaryExpr:
 		return "(" + r.renderExpr(e.Left) + " " + r.opString(e.Op) + " " + r.renderExpr(e.Right) + ")"
 	case *ast.UnaryExpr:
+		if out, ok := r.liftLiteral(e); ok {
+			return out
+		}
 		return "(" + r.opString(e.Op) + " " + r.renderExpr(e.Expr) + ")"
 	case *ast.FuncCall:
+		if out, ok := r.renderSequenceFunc(e); ok {
+			return out
+		}
+		r.checkSpatialFunc(e)
 		var b strings.Builder
 		b.WriteString(r.renderFunctionName(e.Name))
 		b.WriteByte('(')
@@ -921,6 +2319,9 @@ func (r *dialectRenderer) renderExpr(expr Expr) string {
 			}
 		}
 		b.WriteByte(')')
+		if e.Over != nil {
+			b.WriteString(r.renderWindow(e.Over))
+		}
 		return b.String()
 	case *ast.CaseExpr:
 		var b strings.Builder
@@ -957,6 +2358,8 @@ func (r *dialectRenderer) renderExpr(expr Expr) string {
 		if e.Subq != nil {
 			sub, _ := r.renderSelect(e.Subq)
 			out += sub
+		} else if r.inList.oversized(e) {
+			out += r.renderValuesList(e)
 		} else {
 			for i, it := range e.List {
 				if i > 0 {
@@ -993,8 +2396,12 @@ func (r *dialectRenderer) renderExpr(expr Expr) string {
 	case *ast.SubqueryExpr:
 		sub, _ := r.renderSelect(e.Subq)
 		return "(" + sub + ")"
+	case *ast.InsertedRef:
+		return r.renderInserted(e)
+	case *ast.QuantifiedComparisonExpr:
+		return r.renderQuantified(e)
 	case *ast.CastExpr:
-		return "CAST(" + r.renderExpr(e.Expr) + " AS " + r.renderDataType(e.Type) + ")"
+		return "CAST(" + r.renderExpr(e.Expr) + " AS " + r.renderCastType(e.Type) + ")"
 	case *ast.SelectStmt:
 		s, _ := r.renderSelect(e)
 		return "(" + s + ")"
@@ -1003,12 +2410,92 @@ func (r *dialectRenderer) renderExpr(expr Expr) string {
 	}
 }
 
+// renderInserted renders a reference to the new row of an upsert: as
+// excluded.col for postgres and sqlite, and for mysql as written, with
+// excluded.col becoming VALUES(col).
+func (r *dialectRenderer) renderInserted(e *ast.InsertedRef) string {
+	col := r.renderIdent(e.Column)
+	form := e.Form
+	switch r.target {
+	case DialectPostgres, DialectSQLite:
+		form = ast.InsertedExcluded
+	case DialectMySQL:
+		if form == ast.InsertedExcluded {
+			form = ast.InsertedValues
+		}
+	}
+	switch form {
+	case ast.InsertedValues:
+		return "VALUES(" + col + ")"
+	case ast.InsertedAlias:
+		return r.renderIdent(e.Qualifier) + "." + col
+	}
+	return "excluded." + col
+}
+
+// renderQuantified renders op ANY | SOME | ALL (...). Only PostgreSQL
+// compares against arrays, and SQLite has no quantified comparisons, so
+// there = ANY and <> ALL over a subquery become IN and NOT IN.
+func (r *dialectRenderer) renderQuantified(e *ast.QuantifiedComparisonExpr) string {
+	var inner string
+	if e.Subq != nil {
+		inner, _ = r.renderSelect(e.Subq)
+	} else {
+		inner = r.renderExpr(e.Array)
+	}
+	switch {
+	case e.Array != nil && r.target != DialectPostgres && r.target != "":
+		r.warn(WarnQuantifiedUnsupported, e.TokPos, "%s cannot compare against an array with %s", r.target, e.Quantifier)
+	case r.target == DialectSQLite:
+		switch {
+		case e.Op == lexer.EQ && e.Quantifier != ast.QuantifyAll:
+			return r.renderExpr(e.Left) + " IN (" + inner + ")"
+		case e.Op == lexer.NEQ && e.Quantifier == ast.QuantifyAll:
+			return r.renderExpr(e.Left) + " NOT IN (" + inner + ")"
+		}
+		r.warn(WarnQuantifiedUnsupported, e.TokPos, "sqlite has no %s %s comparisons; rewrite with MIN, MAX or EXISTS", r.opString(e.Op), e.Quantifier)
+	}
+	return "(" + r.renderExpr(e.Left) + " " + r.opString(e.Op) + " " + e.Quantifier.String() + " (" + inner + "))"
+}
+
+// renderWindow renders the OVER clause of a window function.
+func (r *dialectRenderer) renderWindow(w *ast.WindowSpec) string {
+	if w.Name != nil {
+		return " OVER " + r.renderIdent(w.Name)
+	}
+	var parts []string
+	if len(w.PartitionBy) > 0 {
+		keys := make([]string, len(w.PartitionBy))
+		for i, e := range w.PartitionBy {
+			keys[i] = r.renderExpr(e)
+		}
+		parts = append(parts, "PARTITION BY "+strings.Join(keys, ", "))
+	}
+	if len(w.OrderBy) > 0 {
+		parts = append(parts, "ORDER BY "+r.renderOrderBy(w.OrderBy))
+	}
+	if w.Frame != nil {
+		parts = append(parts, string(w.Frame))
+	}
+	return " OVER (" + strings.Join(parts, " ") + ")"
+}
+
 func (r *dialectRenderer) renderFunctionName(name *ast.QualifiedIdent) string {
 	if name == nil || len(name.Parts) == 0 {
 		return ""
 	}
 	if len(name.Parts) == 1 {
 		fn := strings.ToUpper(name.Parts[0].Unquoted)
+		if uuidFunctions[fn] {
+			switch r.target {
+			case DialectPostgres:
+				return "GEN_RANDOM_UUID"
+			case DialectMySQL:
+				return "UUID"
+			case DialectClickHouse:
+				return "generateUUIDv4"
+			}
+		}
 		switch r.target {
 		case DialectPostgres, DialectSQLite:
 			if fn == "IFNULL" {
@@ -1024,6 +2511,17 @@ func (r *dialectRenderer) renderFunctionName(name *ast.QualifiedIdent) string {
 	return r.renderQualifiedIdent(name)
 }
 
+// uuidFunctions are the functions that make a random UUID: PostgreSQL's
+// gen_random_uuid and uuid-ossp's uuid_generate_v4, MySQL's UUID and
+// ClickHouse's generateUUIDv4.
+var uuidFunctions = map[string]bool{"GEN_RANDOM_UUID": true, "UUID_GENERATE_V4": true, "UUID": true, "GENERATEUUIDV4": true}
+
+// isUUIDCall reports whether e calls one of uuidFunctions.
+func isUUIDCall(e ast.Expr) bool {
+	fc, ok := e.(*ast.FuncCall)
+	return ok && fc.Name != nil && len(fc.Name.Parts) == 1 && uuidFunctions[strings.ToUpper(fc.Name.Parts[0].Unquoted)]
+}
+
 func (r *dialectRenderer) renderParam(raw []byte) string {
 	if r.target == DialectPostgres {
 		r.paramIndex++
@@ -1119,8 +2617,24 @@ func (r *dialectRenderer) renderIdent(id *ast.Ident) string {
 	if name == "*" {
 		return "*"
 	}
+	r.checkReserved(id)
+	name = r.identCase(name)
+	bare := false
+	switch r.quote {
+	case QuoteWhenNeeded:
+		bare = !r.needsQuotes(name)
+	case QuotePreserve:
+		bare = !quoted(id) && !r.needsQuotes(name)
+	}
+	if bare {
+		if r.bareIdents == nil {
+			r.bareIdents = map[string]bool{}
+		}
+		r.bareIdents[name] = true
+		return name
+	}
 	switch r.target {
-	case DialectMySQL:
+	case DialectMySQL, DialectClickHouse:
 		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
 	default:
 		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
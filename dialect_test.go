@@ -1,6 +1,8 @@
 package sqlparser_test
 
 import (
+	"errors"
+	"slices"
 	"strings"
 	"testing"
 
@@ -113,6 +115,598 @@ func TestConvertOnConflictToOnDupKey(t *testing.T) {
 	}
 }
 
+func TestConvertOnConflictClauses(t *testing.T) {
+	tests := []struct {
+		src      string
+		target   sqlparser.Dialect
+		want     string
+		warnings []string
+	}{
+		{"INSERT INTO t (id, n) VALUES (1, 1) ON CONFLICT ON CONSTRAINT t_pkey DO UPDATE SET n = 2 WHERE t.n < 2", sqlparser.DialectPostgres,
+			`INSERT INTO "t" ("id", "n") VALUES (1, 1) ON CONFLICT ON CONSTRAINT "t_pkey" DO UPDATE SET "n" = 2 WHERE ("t"."n" < 2)`, nil},
+		{"INSERT INTO t (id, n) VALUES (1, 1) ON CONFLICT ON CONSTRAINT t_pkey DO NOTHING", sqlparser.DialectSQLite,
+			`INSERT INTO "t" ("id", "n") VALUES (1, 1) ON CONFLICT DO NOTHING`, []string{sqlparser.WarnConflictConstraintDropped}},
+		{"INSERT INTO t (id, n) VALUES (1, 1) ON CONFLICT (id) WHERE n > 0 DO UPDATE SET n = 2 WHERE t.n < 2", sqlparser.DialectSQLite,
+			`INSERT INTO "t" ("id", "n") VALUES (1, 1) ON CONFLICT ("id") WHERE ("n" > 0) DO UPDATE SET "n" = 2 WHERE ("t"."n" < 2)`, nil},
+		{"INSERT INTO t (id, n) VALUES (1, 1) ON CONFLICT (id) DO UPDATE SET n = 2 WHERE t.n < 2", sqlparser.DialectMySQL,
+			"INSERT INTO `t` (`id`, `n`) VALUES (1, 1) ON DUPLICATE KEY UPDATE `n` = IF((`t`.`n` < 2), 2, `n`)",
+			[]string{sqlparser.WarnConflictTargetDropped, sqlparser.WarnUpsertWhereRewritten}},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(tt.src, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s %q: %v", tt.target, tt.src, err)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if out != tt.want || !slices.Equal(codes, tt.warnings) {
+			t.Errorf("%s %q:\n got %s %v\nwant %s %v", tt.target, tt.src, out, codes, tt.want, tt.warnings)
+		}
+	}
+}
+
+func TestConvertInsertedRefs(t *testing.T) {
+	tests := []struct {
+		src    string
+		target sqlparser.Dialect
+		want   string
+	}{
+		{"INSERT INTO t (a, b) VALUES (1, 2) ON CONFLICT (a) DO UPDATE SET b = excluded.b", sqlparser.DialectMySQL,
+			"INSERT INTO `t` (`a`, `b`) VALUES (1, 2) ON DUPLICATE KEY UPDATE `b` = VALUES(`b`)"},
+		{"INSERT INTO t (a, b) VALUES (1, 2) ON CONFLICT (a) DO UPDATE SET b = excluded.b", sqlparser.DialectSQLite,
+			`INSERT INTO "t" ("a", "b") VALUES (1, 2) ON CONFLICT ("a") DO UPDATE SET "b" = excluded."b"`},
+		{"INSERT INTO t (a, b) VALUES (1, 2) ON DUPLICATE KEY UPDATE b = VALUES(b)", sqlparser.DialectMySQL,
+			"INSERT INTO `t` (`a`, `b`) VALUES (1, 2) ON DUPLICATE KEY UPDATE `b` = VALUES(`b`)"},
+		{"INSERT INTO t (a, b) VALUES (1, 2) AS new (m, n) ON DUPLICATE KEY UPDATE b = new.n + m", sqlparser.DialectMySQL,
+			"INSERT INTO `t` (`a`, `b`) VALUES (1, 2) AS `new` ON DUPLICATE KEY UPDATE `b` = (`new`.`b` + `new`.`a`)"},
+		{"INSERT INTO t (a, b) VALUES (1, 2) AS new ON DUPLICATE KEY UPDATE b = new.b", sqlparser.DialectPostgres,
+			`INSERT INTO "t" ("a", "b") VALUES (1, 2) ON CONFLICT ("a") DO UPDATE SET "b" = excluded."b"`},
+	}
+	for _, tt := range tests {
+		out, _, err := sqlparser.ConvertDialectWithOptions(tt.src, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s %q: %v", tt.target, tt.src, err)
+		}
+		if out != tt.want {
+			t.Errorf("%s %q:\n got %s\nwant %s", tt.target, tt.src, out, tt.want)
+		}
+	}
+}
+
+func TestConvertTruncate(t *testing.T) {
+	src := "TRUNCATE a, b RESTART IDENTITY CASCADE"
+	tests := []struct {
+		target   sqlparser.Dialect
+		want     string
+		warnings []string
+	}{
+		{sqlparser.DialectPostgres, `TRUNCATE TABLE "a", "b" RESTART IDENTITY CASCADE`, nil},
+		{sqlparser.DialectMySQL, "TRUNCATE TABLE `a`; TRUNCATE TABLE `b`",
+			[]string{sqlparser.WarnTruncateOptionDropped, sqlparser.WarnTruncateSplit}},
+		{sqlparser.DialectSQLite, `DELETE FROM "a"; DELETE FROM "b"; DELETE FROM sqlite_sequence WHERE name = 'a'; DELETE FROM sqlite_sequence WHERE name = 'b'`,
+			[]string{sqlparser.WarnTruncateOptionDropped}},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(src, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.target, err)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if out != tt.want || !slices.Equal(codes, tt.warnings) {
+			t.Errorf("%s:\n got %s %v\nwant %s %v", tt.target, out, codes, tt.want, tt.warnings)
+		}
+	}
+
+	// An attached database keeps its own sqlite_sequence.
+	out, _, err := sqlparser.ConvertDialectWithOptions("TRUNCATE aux.logs RESTART IDENTITY", sqlparser.ConvertOptions{Target: sqlparser.DialectSQLite})
+	if want := `DELETE FROM "aux"."logs"; DELETE FROM "aux".sqlite_sequence WHERE name = 'logs'`; err != nil || out != want {
+		t.Errorf("attached:\n got %s %v\nwant %s", out, err, want)
+	}
+}
+
+func TestConvertWritableCTE(t *testing.T) {
+	src := "WITH gone AS MATERIALIZED (DELETE FROM jobs WHERE done RETURNING id) SELECT id FROM gone"
+	tests := []struct {
+		target   sqlparser.Dialect
+		want     string
+		warnings []string
+	}{
+		{sqlparser.DialectPostgres, `WITH "gone" AS MATERIALIZED (DELETE FROM "jobs" WHERE "done" RETURNING "id") SELECT "id" FROM "gone"`, nil},
+		{sqlparser.DialectSQLite, `WITH "gone" AS MATERIALIZED (DELETE FROM "jobs" WHERE "done" RETURNING "id") SELECT "id" FROM "gone"`,
+			[]string{sqlparser.WarnDataModifyingCTE}},
+		{sqlparser.DialectMySQL, "WITH `gone` AS (DELETE FROM `jobs` WHERE `done`) SELECT `id` FROM `gone`",
+			[]string{sqlparser.WarnMaterializedDropped, sqlparser.WarnDataModifyingCTE, sqlparser.WarnReturningUnsupported}},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(src, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.target, err)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if out != tt.want || !slices.Equal(codes, tt.warnings) {
+			t.Errorf("%s:\n got %s %v\nwant %s %v", tt.target, out, codes, tt.want, tt.warnings)
+		}
+	}
+}
+
+func TestConvertSetOperations(t *testing.T) {
+	tests := []struct {
+		src    string
+		target sqlparser.Dialect
+		want   string
+	}{
+		{"SELECT a FROM t UNION SELECT b FROM u ORDER BY a LIMIT 5", sqlparser.DialectMySQL,
+			"SELECT `a` FROM `t` UNION SELECT `b` FROM `u` ORDER BY `a` ASC LIMIT 5"},
+		{"SELECT 1 UNION SELECT 2 UNION SELECT 3", sqlparser.DialectPostgres,
+			"SELECT 1 UNION SELECT 2 UNION SELECT 3"},
+		{"(SELECT 1 UNION SELECT 2) INTERSECT SELECT 3", sqlparser.DialectPostgres,
+			"(SELECT 1 UNION SELECT 2) INTERSECT SELECT 3"},
+		{"SELECT 1 EXCEPT (SELECT 2 EXCEPT SELECT 3)", sqlparser.DialectPostgres,
+			"SELECT 1 EXCEPT (SELECT 2 EXCEPT SELECT 3)"},
+		{"(SELECT a FROM t LIMIT 3) UNION ALL (SELECT b FROM u) ORDER BY 1", sqlparser.DialectPostgres,
+			`(SELECT "a" FROM "t" LIMIT 3) UNION ALL SELECT "b" FROM "u" ORDER BY 1 ASC`},
+		// sqlite evaluates set operations left to right and rejects
+		// parenthesized operands.
+		{"(SELECT 1 UNION SELECT 2) INTERSECT SELECT 3", sqlparser.DialectSQLite,
+			"SELECT 1 UNION SELECT 2 INTERSECT SELECT 3"},
+		{"SELECT 1 UNION SELECT 2 INTERSECT SELECT 3", sqlparser.DialectSQLite,
+			"SELECT 1 UNION SELECT * FROM (SELECT 2 INTERSECT SELECT 3)"},
+		{"(SELECT a FROM t LIMIT 3) UNION ALL SELECT b FROM u", sqlparser.DialectSQLite,
+			`SELECT * FROM (SELECT "a" FROM "t" LIMIT 3) UNION ALL SELECT "b" FROM "u"`},
+	}
+	for _, tt := range tests {
+		out, _, err := sqlparser.ConvertDialectWithOptions(tt.src, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.src, err)
+		}
+		if out != tt.want {
+			t.Errorf("%s (%s):\n got %s\nwant %s", tt.src, tt.target, out, tt.want)
+		}
+	}
+}
+
+func TestConvertIndexHintsAndSample(t *testing.T) {
+	tests := []struct {
+		src      string
+		target   sqlparser.Dialect
+		want     string
+		warnings []string
+	}{
+		{"SELECT * FROM t FORCE INDEX (a) USE INDEX FOR JOIN (b, c)", sqlparser.DialectMySQL,
+			"SELECT * FROM `t` FORCE INDEX (`a`) USE INDEX FOR JOIN (`b`, `c`)", nil},
+		{"SELECT * FROM t x FORCE INDEX (a)", sqlparser.DialectSQLite, `SELECT * FROM "t" "x" INDEXED BY "a"`, nil},
+		{"SELECT * FROM t IGNORE INDEX (a)", sqlparser.DialectSQLite, `SELECT * FROM "t"`, []string{sqlparser.WarnIndexHintDropped}},
+		{"SELECT * FROM t USE INDEX (a)", sqlparser.DialectPostgres, `SELECT * FROM "t"`, []string{sqlparser.WarnIndexHintDropped}},
+		{"SELECT * FROM t TABLESAMPLE system (10) REPEATABLE (1)", sqlparser.DialectPostgres,
+			`SELECT * FROM "t" TABLESAMPLE SYSTEM (10) REPEATABLE (1)`, nil},
+		{"SELECT * FROM t TABLESAMPLE SYSTEM (10)", sqlparser.DialectMySQL, "SELECT * FROM `t`", []string{sqlparser.WarnTableSampleDropped}},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(tt.src, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.src, err)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if out != tt.want || !slices.Equal(codes, tt.warnings) {
+			t.Errorf("%s (%s):\n got %s %v\nwant %s %v", tt.src, tt.target, out, codes, tt.want, tt.warnings)
+		}
+	}
+}
+
+func TestConvertStarExcept(t *testing.T) {
+	schema, err := sqlparser.BuildSchema("CREATE TABLE users (id INT, name TEXT, secret TEXT); CREATE TABLE orders (id INT, uid INT, total INT)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		src      string
+		target   sqlparser.Dialect
+		schema   *sqlparser.Schema
+		want     string
+		warnings []string
+	}{
+		{"SELECT u.*, COUNT(o.*) FROM users u JOIN orders o ON o.uid = u.id GROUP BY u.id", sqlparser.DialectMySQL, nil,
+			"SELECT `u`.*, COUNT(`o`.*) FROM `users` `u` JOIN `orders` `o` ON (`o`.`uid` = `u`.`id`) GROUP BY `u`.`id`", nil},
+		{"SELECT * EXCEPT (secret) FROM users", "", nil, `SELECT * EXCEPT ("secret") FROM "users"`, nil},
+		{"SELECT * EXCEPT (secret) FROM users", sqlparser.DialectPostgres, schema, `SELECT "id", "name" FROM "users"`, nil},
+		{"SELECT u.* EXCLUDE (secret), o.total FROM users u JOIN orders o USING (id)", sqlparser.DialectMySQL, schema,
+			"SELECT `u`.`id`, `u`.`name`, `o`.`total` FROM `users` `u` JOIN `orders` `o` USING (`id`)", nil},
+		{"SELECT * EXCEPT (id, uid) FROM users, orders", sqlparser.DialectSQLite, schema,
+			`SELECT "users"."name", "users"."secret", "orders"."total" FROM "users", "orders"`, nil},
+		{"SELECT * EXCEPT (secret) FROM users", sqlparser.DialectPostgres, nil, `SELECT * FROM "users"`, []string{sqlparser.WarnStarExceptDropped}},
+		{"SELECT * EXCEPT (id) FROM users JOIN orders USING (id)", sqlparser.DialectPostgres, schema,
+			`SELECT * FROM "users" JOIN "orders" USING ("id")`, []string{sqlparser.WarnStarExceptDropped}},
+		{"SELECT x.* EXCEPT (id) FROM (SELECT * FROM users) x", sqlparser.DialectPostgres, schema,
+			`SELECT "x".* FROM (SELECT * FROM "users") "x"`, []string{sqlparser.WarnStarExceptDropped}},
+	}
+	for _, tt := range tests {
+		res, err := sqlparser.ParseWithOptions(tt.src, sqlparser.ParseOptions{StarExcept: true})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.src, err)
+		}
+		out, warnings, err := sqlparser.ConvertStatements(res.Statements, sqlparser.ConvertOptions{Target: tt.target, Schema: tt.schema})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.src, err)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if out != tt.want || !slices.Equal(codes, tt.warnings) {
+			t.Errorf("%s (%s):\n got %s %v\nwant %s %v", tt.src, tt.target, out, codes, tt.want, tt.warnings)
+		}
+	}
+}
+
+func TestConvertPivot(t *testing.T) {
+	schema, err := sqlparser.BuildSchema("CREATE TABLE sales (region TEXT, quarter TEXT, amount INT); CREATE TABLE quarters (region TEXT, q1 INT, q2 INT)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		src      string
+		target   sqlparser.Dialect
+		schema   *sqlparser.Schema
+		want     string
+		warnings []string
+	}{
+		{"SELECT * FROM sales PIVOT (SUM(amount) FOR quarter IN ('Q1' AS q1, 'Q2')) AS p", "", schema,
+			`SELECT * FROM "sales" PIVOT (SUM("amount") FOR "quarter" IN ('Q1' AS "q1", 'Q2')) "p"`, nil},
+		{"SELECT * FROM sales PIVOT (SUM(amount) FOR quarter IN ('Q1' AS q1, 'Q2')) AS p", sqlparser.DialectPostgres, schema,
+			`SELECT * FROM (SELECT "region", SUM(CASE WHEN ("quarter" = 'Q1') THEN "amount" END) AS "q1", SUM(CASE WHEN ("quarter" = 'Q2') THEN "amount" END) AS "Q2" FROM "sales" GROUP BY "region") "p"`,
+			[]string{sqlparser.WarnPivotRewritten}},
+		{`SELECT region, "Q1" FROM sales PIVOT (COUNT(*) FOR quarter IN ("Q1")) p`, sqlparser.DialectMySQL, schema,
+			"SELECT `region`, `Q1` FROM (SELECT `region`, `amount`, COUNT(CASE WHEN (`quarter` = 'Q1') THEN 1 END) AS `Q1` FROM `sales` GROUP BY `region`, `amount`) `p`",
+			[]string{sqlparser.WarnPivotRewritten}},
+		{"SELECT * FROM (SELECT region, quarter, amount AS n FROM sales) s PIVOT (MAX(n) FOR quarter IN ('Q1'))", sqlparser.DialectSQLite, nil,
+			`SELECT * FROM (SELECT "region", MAX(CASE WHEN ("quarter" = 'Q1') THEN "n" END) AS "Q1" FROM (SELECT "region", "quarter", "amount" AS "n" FROM "sales") "s" GROUP BY "region") "s"`,
+			[]string{sqlparser.WarnPivotRewritten}},
+		{"SELECT * FROM quarters UNPIVOT (amount FOR quarter IN (q1, q2 AS second)) u", sqlparser.DialectPostgres, schema,
+			`SELECT * FROM (SELECT "region", 'q1' AS "quarter", "q1" AS "amount" FROM "quarters" WHERE "q1" IS NOT NULL UNION ALL SELECT "region", 'second' AS "quarter", "q2" AS "amount" FROM "quarters" WHERE "q2" IS NOT NULL) "u"`,
+			[]string{sqlparser.WarnPivotRewritten}},
+		{"SELECT * FROM quarters UNPIVOT INCLUDE NULLS (amount FOR quarter IN (q1))", sqlparser.DialectMySQL, schema,
+			"SELECT * FROM (SELECT `region`, `q2`, 'q1' AS `quarter`, `q1` AS `amount` FROM `quarters`) `quarters`",
+			[]string{sqlparser.WarnPivotRewritten}},
+		{"SELECT * FROM sales PIVOT (SUM(amount) FOR quarter IN ('Q1')) p", sqlparser.DialectPostgres, nil,
+			`SELECT * FROM "sales" PIVOT (SUM("amount") FOR "quarter" IN ('Q1')) "p"`, []string{sqlparser.WarnPivotUnsupported}},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(tt.src, sqlparser.ConvertOptions{Target: tt.target, Schema: tt.schema})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.src, err)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if out != tt.want || !slices.Equal(codes, tt.warnings) {
+			t.Errorf("%s (%s):\n got %s %v\nwant %s %v", tt.src, tt.target, out, codes, tt.want, tt.warnings)
+		}
+	}
+}
+
+func TestConvertClickHouse(t *testing.T) {
+	tests := []struct {
+		src      string
+		target   sqlparser.Dialect
+		want     string
+		warnings []string
+	}{
+		{"CREATE TABLE events (id BIGINT UNSIGNED NOT NULL PRIMARY KEY, name VARCHAR(64), kind ENUM('a','b') NOT NULL, amount DECIMAL(12,2), at TIMESTAMP(3), tags TEXT[], meta STRUCT<k STRING, v INT64>)", sqlparser.DialectClickHouse,
+			"CREATE TABLE `events` (`id` UInt64 NOT NULL PRIMARY KEY, `name` String NULL, `kind` Enum8('a' = 1, 'b' = 2) NOT NULL, `amount` Decimal(12, 2) NULL, `at` DateTime64(3) NULL, `tags` Array(String), `meta` Tuple(`k` String, `v` Int64))", nil},
+		{"CREATE TABLE t (id INT AUTO_INCREMENT PRIMARY KEY)", sqlparser.DialectClickHouse, "CREATE TABLE `t` (`id` Int32 PRIMARY KEY)", []string{sqlparser.WarnAutoIncrementDropped}},
+		{"CREATE TABLE c (s String NOT NULL, n Int32, p Tuple(String, Array(Float64)))", sqlparser.DialectPostgres,
+			`CREATE TABLE "c" ("s" TEXT NOT NULL, "n" INTEGER, "p" JSONB)`, []string{sqlparser.WarnNestedTypeAsJSON}},
+		{"CREATE TABLE c (tags Array(String), f Float64)", sqlparser.DialectMySQL, "CREATE TABLE `c` (`tags` JSON, `f` DOUBLE)", []string{sqlparser.WarnNestedTypeAsJSON}},
+		{"CREATE TABLE c (tags ARRAY<STRING>)", sqlparser.DialectPostgres, `CREATE TABLE "c" ("tags" TEXT[])`, nil},
+		{"SELECT user_id, row_number() OVER (PARTITION BY user_id ORDER BY ts DESC) AS rn FROM events QUALIFY rn = 1 ORDER BY user_id LIMIT 2 BY user_id LIMIT 10", sqlparser.DialectClickHouse,
+			"SELECT `user_id`, ROW_NUMBER() OVER (PARTITION BY `user_id` ORDER BY `ts` DESC) AS `rn` FROM `events` QUALIFY (`rn` = 1) ORDER BY `user_id` ASC LIMIT 2 BY `user_id` LIMIT 10", nil},
+		{"SELECT a FROM t QUALIFY rank() OVER (ORDER BY b) = 1 LIMIT 1 BY a", sqlparser.DialectPostgres,
+			`SELECT "a" FROM "t" QUALIFY (RANK() OVER (ORDER BY "b" ASC) = 1) LIMIT 1 BY "a"`, []string{sqlparser.WarnQualifyUnsupported, sqlparser.WarnLimitByUnsupported}},
+		{"INSERT INTO t (id) VALUES (?) ON CONFLICT (id) DO NOTHING RETURNING id", sqlparser.DialectClickHouse, "INSERT INTO `t` (`id`) VALUES (?)",
+			[]string{sqlparser.WarnUpsertUnsupported, sqlparser.WarnReturningUnsupported}},
+	}
+	for _, tt := range tests {
+		res, err := sqlparser.ParseWithOptions(tt.src, sqlparser.ParseOptions{AnalyticsClauses: true})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.src, err)
+		}
+		out, warnings, err := sqlparser.ConvertStatements(res.Statements, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.src, err)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if out != tt.want || !slices.Equal(codes, tt.warnings) {
+			t.Errorf("%s (%s):\n got %s %v\nwant %s %v", tt.src, tt.target, out, codes, tt.want, tt.warnings)
+		}
+	}
+}
+
+func TestConvertLimitStyle(t *testing.T) {
+	tests := []struct {
+		src      string
+		target   sqlparser.Dialect
+		style    sqlparser.LimitStyle
+		want     string
+		warnings []string
+	}{
+		{"SELECT id FROM t LIMIT 10 OFFSET 20", sqlparser.DialectPostgres, sqlparser.LimitFetch,
+			`SELECT "id" FROM "t" ORDER BY (SELECT NULL) OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY`, nil},
+		{"SELECT id FROM t ORDER BY id LIMIT 10", sqlparser.DialectPostgres, sqlparser.LimitFetch,
+			`SELECT "id" FROM "t" ORDER BY "id" ASC OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY`, nil},
+		{"SELECT id FROM t LIMIT 10", sqlparser.DialectPostgres, sqlparser.LimitTop, `SELECT TOP (10) "id" FROM "t"`, nil},
+		{"SELECT id FROM t LIMIT 10 OFFSET 20", sqlparser.DialectPostgres, sqlparser.LimitTop,
+			`SELECT "id" FROM "t" ORDER BY (SELECT NULL) OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY`, nil},
+		{"SELECT id FROM t UNION SELECT id FROM u LIMIT 10", sqlparser.DialectPostgres, sqlparser.LimitTop,
+			`SELECT "id" FROM "t" UNION SELECT "id" FROM "u" ORDER BY 1 OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY`, nil},
+		{"SELECT id FROM t OFFSET 20 ROWS FETCH FIRST 10 ROWS ONLY", sqlparser.DialectPostgres, sqlparser.LimitKeep,
+			`SELECT "id" FROM "t" OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY`, nil},
+		{"SELECT id FROM t OFFSET 20 ROWS FETCH FIRST 10 ROWS ONLY", sqlparser.DialectMySQL, sqlparser.LimitKeep,
+			"SELECT `id` FROM `t` LIMIT 10 OFFSET 20", nil},
+		{"SELECT id FROM t ORDER BY id FETCH FIRST 3 ROWS WITH TIES", sqlparser.DialectSQLite, sqlparser.LimitKeep,
+			`SELECT "id" FROM "t" ORDER BY "id" ASC LIMIT 3`, []string{sqlparser.WarnLimitUnsupported}},
+		{"SELECT id FROM t LIMIT ALL OFFSET 5", sqlparser.DialectPostgres, sqlparser.LimitKeep, `SELECT "id" FROM "t" OFFSET 5`, nil},
+		{"SELECT id FROM t OFFSET 5", sqlparser.DialectMySQL, sqlparser.LimitKeep, "SELECT `id` FROM `t` LIMIT 18446744073709551615 OFFSET 5", nil},
+		{"SELECT id FROM t OFFSET 5", sqlparser.DialectSQLite, sqlparser.LimitKeep, `SELECT "id" FROM "t" LIMIT -1 OFFSET 5`, nil},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(tt.src, sqlparser.ConvertOptions{Target: tt.target, Limit: tt.style})
+		if err != nil {
+			t.Fatalf("%s %q: %v", tt.target, tt.src, err)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if out != tt.want || !slices.Equal(codes, tt.warnings) {
+			t.Errorf("%s %q:\n got %s %v\nwant %s %v", tt.target, tt.src, out, codes, tt.want, tt.warnings)
+		}
+	}
+}
+
+func TestConvertQuotePolicy(t *testing.T) {
+	tests := []struct {
+		src    string
+		target sqlparser.Dialect
+		policy sqlparser.QuotePolicy
+		want   string
+	}{
+		{"SELECT id, name FROM users u WHERE u.id = 1", sqlparser.DialectMySQL, sqlparser.QuoteAlways,
+			"SELECT `id`, `name` FROM `users` `u` WHERE (`u`.`id` = 1)"},
+		{"SELECT id, name FROM users u WHERE u.id = 1", sqlparser.DialectMySQL, sqlparser.QuoteWhenNeeded,
+			"SELECT id, name FROM users u WHERE (u.id = 1)"},
+		{"SELECT `key`, `rank`, `user`, `first name`, `2fa` FROM t", sqlparser.DialectMySQL, sqlparser.QuoteWhenNeeded,
+			"SELECT `key`, `rank`, user, `first name`, `2fa` FROM t"},
+		{"SELECT `key`, `rank`, `user`, `first name`, `2fa` FROM t", sqlparser.DialectPostgres, sqlparser.QuoteWhenNeeded,
+			`SELECT "key", rank, "user", "first name", "2fa" FROM t`},
+		{`SELECT "UserId", userid FROM "Orders"`, sqlparser.DialectPostgres, sqlparser.QuoteWhenNeeded,
+			`SELECT "UserId", userid FROM "Orders"`},
+		{`SELECT "UserId", userid FROM "Orders"`, sqlparser.DialectMySQL, sqlparser.QuoteWhenNeeded,
+			"SELECT UserId, userid FROM Orders"},
+		{`SELECT "abort", "sample" FROM t`, sqlparser.DialectSQLite, sqlparser.QuoteWhenNeeded, `SELECT "abort", sample FROM t`},
+		{`SELECT "abort", "sample" FROM t`, sqlparser.DialectClickHouse, sqlparser.QuoteWhenNeeded, "SELECT abort, `sample` FROM t"},
+		{"SELECT `id`, name FROM `users` WHERE `order` = 1", sqlparser.DialectPostgres, sqlparser.QuotePreserve,
+			`SELECT "id", name FROM "users" WHERE ("order" = 1)`},
+		{"SELECT id, user FROM users", sqlparser.DialectPostgres, sqlparser.QuotePreserve, `SELECT id, "user" FROM users`},
+	}
+	for _, tt := range tests {
+		out, _, err := sqlparser.ConvertDialectWithOptions(tt.src, sqlparser.ConvertOptions{Target: tt.target, Quote: tt.policy})
+		if err != nil {
+			t.Fatalf("%s %q: %v", tt.target, tt.src, err)
+		}
+		if out != tt.want {
+			t.Errorf("%s %q:\n got %s\nwant %s", tt.target, tt.src, out, tt.want)
+		}
+		if _, err := sqlparser.ParseStatements(out); err != nil {
+			t.Errorf("%s %q: output does not parse: %v", tt.target, tt.src, err)
+		}
+	}
+}
+
+func TestConvertWarnsOnReservedWords(t *testing.T) {
+	out, warnings, err := sqlparser.ConvertDialectWithOptions("SELECT user, `order` FROM accounts WHERE user <> '' ORDER BY user",
+		sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres, Quote: sqlparser.QuoteWhenNeeded})
+	want := `SELECT "user", "order" FROM accounts WHERE ("user" != '') ORDER BY "user" ASC`
+	if err != nil || out != want {
+		t.Fatalf("unexpected conversion:\n got %s %v\nwant %s", out, err, want)
+	}
+	if len(warnings) != 3 {
+		t.Fatalf("expected a warning for each unquoted user, got %v", warnings)
+	}
+	for _, w := range warnings {
+		if w.Code != sqlparser.WarnReservedWordQuoted || w.Pos < 0 {
+			t.Errorf("unexpected warning %v", w)
+		}
+	}
+	if _, warnings, _ := sqlparser.ConvertDialectWithOptions("SELECT user FROM accounts", sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL}); len(warnings) != 0 {
+		t.Errorf("user is not reserved in mysql, got %v", warnings)
+	}
+}
+
+func TestConvertOutputStyle(t *testing.T) {
+	const src = "select count(*) as n, lower(name) from users -- note\n where active = true and kind = 'SELECT' and created > cast(:since as timestamp) group by name"
+	tests := []struct {
+		target sqlparser.Dialect
+		quote  sqlparser.QuotePolicy
+		style  sqlparser.OutputStyle
+		want   string
+	}{
+		{sqlparser.DialectMySQL, sqlparser.QuoteAlways, sqlparser.OutputStyle{},
+			"SELECT COUNT(*) AS `n`, LOWER(`name`) FROM `users` WHERE (((`active` = true) AND (`kind` = 'SELECT')) AND (`created` > CAST(? AS DATETIME))) GROUP BY `name`"},
+		{sqlparser.DialectMySQL, sqlparser.QuoteAlways, sqlparser.OutputStyle{Keywords: sqlparser.KeywordUpper, Booleans: sqlparser.BoolNumbers, Semicolon: true},
+			"SELECT COUNT(*) AS `n`, LOWER(`name`) FROM `users` WHERE (((`active` = 1) AND (`kind` = 'SELECT')) AND (`created` > CAST(? AS DATETIME))) GROUP BY `name`;"},
+		{sqlparser.DialectPostgres, sqlparser.QuoteWhenNeeded, sqlparser.OutputStyle{Keywords: sqlparser.KeywordLower, Booleans: sqlparser.BoolNumbers},
+			"select count(*) as n, lower(name) from users where (((active = true) and (kind = 'SELECT')) and (created > cast($1 as timestamp))) group by name"},
+		{sqlparser.DialectPostgres, sqlparser.QuoteAlways, sqlparser.OutputStyle{Identifiers: sqlparser.IdentUpper, Booleans: sqlparser.BoolKeywords},
+			`SELECT COUNT(*) AS "N", LOWER("NAME") FROM "USERS" WHERE ((("ACTIVE" = TRUE) AND ("KIND" = 'SELECT')) AND ("CREATED" > CAST($1 AS timestamp))) GROUP BY "NAME"`},
+	}
+	for _, tt := range tests {
+		out, _, err := sqlparser.ConvertDialectWithOptions(src, sqlparser.ConvertOptions{Target: tt.target, Quote: tt.quote, Style: tt.style})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != tt.want {
+			t.Errorf("%s %+v:\n got %s\nwant %s", tt.target, tt.style, out, tt.want)
+		}
+	}
+
+	// Identifiers left unquoted keep their case, and so do ClickHouse's
+	// type names.
+	out, _, err := sqlparser.ConvertDialectWithOptions("CREATE TABLE `Events` (`Id` UUID, `At` DATETIME, `rows` INT)", sqlparser.ConvertOptions{
+		Target: sqlparser.DialectClickHouse, Quote: sqlparser.QuoteWhenNeeded, Style: sqlparser.OutputStyle{Keywords: sqlparser.KeywordLower},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "create table Events (Id UUID null, At DateTime null, rows Int32 null)"; out != want {
+		t.Errorf("clickhouse:\n got %s\nwant %s", out, want)
+	}
+
+	// A PostgreSQL string ending in a backslash ends at its quote.
+	out, _, err = sqlparser.ConvertDialectWithOptions(`select 'a\\' from t where x = 'select'`, sqlparser.ConvertOptions{
+		Source: sqlparser.DialectMySQL, Target: sqlparser.DialectPostgres, Quote: sqlparser.QuoteWhenNeeded, Style: sqlparser.OutputStyle{Keywords: sqlparser.KeywordUpper},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `SELECT 'a\' FROM t WHERE (x = 'select')`; out != want {
+		t.Errorf("postgres backslash:\n got %s\nwant %s", out, want)
+	}
+
+	// Text copied as written keeps its case.
+	out, _, err = sqlparser.ConvertDialectWithOptions("create trigger trg before insert on orders for each row set new.total = 1", sqlparser.ConvertOptions{
+		Source: sqlparser.DialectMySQL, Target: sqlparser.DialectMySQL, Quote: sqlparser.QuoteWhenNeeded, Style: sqlparser.OutputStyle{Keywords: sqlparser.KeywordUpper},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "CREATE TRIGGER trg before insert on orders for each row set new.total = 1"; out != want {
+		t.Errorf("passthrough:\n got %s\nwant %s", out, want)
+	}
+}
+
+func TestConvertRoutineBody(t *testing.T) {
+	src := "CREATE TRIGGER trg BEFORE INSERT ON t FOR EACH ROW SET NEW.a = 1"
+	tests := []struct {
+		source, target sqlparser.Dialect
+		want           string
+		warnings       []string
+	}{
+		{sqlparser.DialectMySQL, sqlparser.DialectMySQL, "CREATE TRIGGER `trg` BEFORE INSERT ON t FOR EACH ROW SET NEW.a = 1", nil},
+		{"", sqlparser.DialectMySQL, "CREATE TRIGGER `trg` BEFORE INSERT ON t FOR EACH ROW SET NEW.a = 1", []string{sqlparser.WarnBodyNotConverted}},
+		{sqlparser.DialectMySQL, sqlparser.DialectPostgres, `CREATE TRIGGER "trg" BEFORE INSERT ON t FOR EACH ROW SET NEW.a = 1`, []string{sqlparser.WarnBodyNotConverted}},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(src, sqlparser.ConvertOptions{Source: tt.source, Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.target, err)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if out != tt.want || !slices.Equal(codes, tt.warnings) {
+			t.Errorf("%s -> %s:\n got %s %v\nwant %s %v", tt.source, tt.target, out, codes, tt.want, tt.warnings)
+		}
+	}
+}
+
+func TestConvertStatementSeparator(t *testing.T) {
+	src := "DELIMITER //\nCREATE PROCEDURE p() BEGIN UPDATE t SET a = 1; DELETE FROM u; END //\nDELIMITER ;\nTRUNCATE a, b"
+	stmts, err := sqlparser.ParseStatements(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stmts) != 2 {
+		t.Fatalf("expected the procedure and TRUNCATE, got %d statements", len(stmts))
+	}
+	tests := []struct {
+		style sqlparser.OutputStyle
+		want  string
+	}{
+		{sqlparser.OutputStyle{}, "CREATE PROCEDURE `p` () BEGIN UPDATE t SET a = 1; DELETE FROM u; END; TRUNCATE TABLE `a`; TRUNCATE TABLE `b`"},
+		{sqlparser.OutputStyle{Separator: ";\n", Semicolon: true}, "CREATE PROCEDURE `p` () BEGIN UPDATE t SET a = 1; DELETE FROM u; END;\nTRUNCATE TABLE `a`;\nTRUNCATE TABLE `b`;"},
+	}
+	for _, tt := range tests {
+		out, _, err := sqlparser.ConvertDialectWithOptions(src, sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL, Style: tt.style})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != tt.want {
+			t.Errorf("%+v:\n got %q\nwant %q", tt.style, out, tt.want)
+		}
+	}
+}
+
+func TestConvertStringLiterals(t *testing.T) {
+	const src = `SELECT 'plain', 'it\'s', 'C:\\temp', 'a\nb', E'x\ty' FROM t WHERE name LIKE '10\%'`
+	tests := []struct {
+		target sqlparser.Dialect
+		want   string
+	}{
+		{sqlparser.DialectMySQL, `SELECT 'plain', 'it''s', 'C:\\temp', 'a\nb', 'x\ty' FROM ` + "`t` WHERE `name`" + ` LIKE '10\\%'`},
+		{sqlparser.DialectClickHouse, `SELECT 'plain', 'it''s', 'C:\\temp', 'a\nb', 'x\ty' FROM ` + "`t` WHERE `name`" + ` LIKE '10\\%'`},
+		{sqlparser.DialectPostgres, `SELECT 'plain', 'it''s', 'C:\temp', E'a\nb', E'x\ty' FROM "t" WHERE "name" LIKE '10\%'`},
+		{sqlparser.DialectSQLite, "SELECT 'plain', 'it''s', 'C:\\temp', 'a\nb', 'x\ty' FROM \"t\" WHERE \"name\" LIKE '10\\%'"},
+	}
+	for _, tt := range tests {
+		out, _, err := sqlparser.ConvertDialectWithOptions(src, sqlparser.ConvertOptions{Target: tt.target, Source: sqlparser.DialectMySQL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != tt.want {
+			t.Errorf("%s:\n got %q\nwant %q", tt.target, out, tt.want)
+		}
+	}
+}
+
+func TestConvertStandardStrings(t *testing.T) {
+	const src = `INSERT INTO t VALUES ('C:\Users\tmp', 'a\nb', E'x\ty')`
+	tests := []struct {
+		source, target sqlparser.Dialect
+		want           string
+	}{
+		{sqlparser.DialectPostgres, sqlparser.DialectPostgres, `INSERT INTO "t" VALUES ('C:\Users\tmp', 'a\nb', E'x\ty')`},
+		{sqlparser.DialectPostgres, sqlparser.DialectMySQL, "INSERT INTO `t` VALUES ('C:\\\\Users\\\\tmp', 'a\\\\nb', 'x\\ty')"},
+		{sqlparser.DialectPostgres, sqlparser.DialectSQLite, "INSERT INTO \"t\" VALUES ('C:\\Users\\tmp', 'a\\nb', 'x\ty')"},
+		// Without a source, literals with backslashes are kept as written.
+		{"", sqlparser.DialectPostgres, `INSERT INTO "t" VALUES ('C:\Users\tmp', 'a\nb', E'x\ty')`},
+		{"", sqlparser.DialectMySQL, "INSERT INTO `t` VALUES ('C:\\Users\\tmp', 'a\\nb', 'x\\ty')"},
+	}
+	for _, tt := range tests {
+		out, _, err := sqlparser.ConvertDialectWithOptions(src, sqlparser.ConvertOptions{Target: tt.target, Source: tt.source})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != tt.want {
+			t.Errorf("%q to %s:\n got %q\nwant %q", tt.source, tt.target, out, tt.want)
+		}
+	}
+
+	// A standard string may end in a backslash.
+	out, _, err := sqlparser.ConvertDialectWithOptions(`SELECT 'C:\dir\' AS p`, sqlparser.ConvertOptions{Source: sqlparser.DialectSQLite, Target: sqlparser.DialectMySQL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT 'C:\\\\dir\\\\' AS `p`"; out != want {
+		t.Errorf("trailing backslash:\n got %q\nwant %q", out, want)
+	}
+}
+
 func TestConvertAutoIncrementToIdentity(t *testing.T) {
 	in := `CREATE TABLE users (id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY, name VARCHAR(32))`
 	out, err := sqlparser.ConvertDialect(in, sqlparser.DialectPostgres)
@@ -123,3 +717,790 @@ func TestConvertAutoIncrementToIdentity(t *testing.T) {
 		t.Fatalf("expected AUTO_INCREMENT->IDENTITY rewrite, got: %s", out)
 	}
 }
+
+func TestConvertCreateTableStorageClauses(t *testing.T) {
+	const ddl = `CREATE TABLE c (x INT) INHERITS (p) USING heap WITH (fillfactor=70) TABLESPACE ts`
+	out, err := sqlparser.ConvertDialect(ddl, sqlparser.DialectPostgres)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if out != `CREATE TABLE "c" ("x" INT) INHERITS ("p") USING heap WITH (fillfactor = 70) TABLESPACE "ts"` {
+		t.Fatalf("unexpected Postgres output: %s", out)
+	}
+	out, warnings, err := sqlparser.ConvertDialectWithOptions(ddl, sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if out != "CREATE TABLE `c` (`x` INT) TABLESPACE `ts`" {
+		t.Fatalf("unexpected MySQL output: %s", out)
+	}
+	if len(warnings) != 3 {
+		t.Fatalf("expected INHERITS, USING and WITH to be reported, got %#v", warnings)
+	}
+}
+
+func TestConvertSQLiteConstructs(t *testing.T) {
+	const ddl = `CREATE TABLE kv (id INTEGER PRIMARY KEY AUTOINCREMENT, v TEXT) WITHOUT ROWID`
+	out, warnings, err := sqlparser.ConvertDialectWithOptions(ddl, sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL})
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("convert failed: %v %#v", err, warnings)
+	}
+	if out != "CREATE TABLE `kv` (`id` INTEGER AUTO_INCREMENT PRIMARY KEY, `v` TEXT)" {
+		t.Fatalf("unexpected MySQL output: %s", out)
+	}
+	out, warnings, _ = sqlparser.ConvertDialectWithOptions(ddl, sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres})
+	if out != `CREATE TABLE "kv" ("id" INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY, "v" TEXT)` {
+		t.Fatalf("unexpected Postgres output: %s", out)
+	}
+	if len(warnings) != 1 || warnings[0].Code != sqlparser.WarnTableOptionDropped {
+		t.Fatalf("expected WITHOUT ROWID warning, got %#v", warnings)
+	}
+
+	out, err = sqlparser.ConvertDialect("CREATE TABLE t (id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY)", sqlparser.DialectSQLite)
+	if err != nil || out != `CREATE TABLE "t" ("id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT)` {
+		t.Fatalf("unexpected SQLite output: %s %v", out, err)
+	}
+	// A table-level key on the AUTO_INCREMENT column moves onto it.
+	out, warnings, err = sqlparser.ConvertDialectWithOptions("CREATE TABLE t (id INT NOT NULL AUTO_INCREMENT, name TEXT, PRIMARY KEY (ID))",
+		sqlparser.ConvertOptions{Target: sqlparser.DialectSQLite})
+	if err != nil || len(warnings) != 0 || out != `CREATE TABLE "t" ("id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "name" TEXT)` {
+		t.Fatalf("unexpected SQLite output: %s %v %v", out, warnings, err)
+	}
+
+	out, warnings, err = sqlparser.ConvertDialectWithOptions("PRAGMA foreign_keys = OFF; PRAGMA journal_mode = WAL; ATTACH 'a.db' AS a",
+		sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if out != "SET FOREIGN_KEY_CHECKS = 0; PRAGMA journal_mode = WAL; ATTACH DATABASE 'a.db' AS a" {
+		t.Fatalf("unexpected output: %s", out)
+	}
+	if len(warnings) != 2 || warnings[0].Code != sqlparser.WarnPragmaUnsupported || warnings[1].Code != sqlparser.WarnAttachUnsupported {
+		t.Fatalf("unexpected warnings %#v", warnings)
+	}
+}
+
+func TestConvertWarnsOnGuessedConflictTarget(t *testing.T) {
+	in := `INSERT INTO users (id, name) VALUES (1, 'a') ON DUPLICATE KEY UPDATE name = 'b'`
+	out, warnings, err := sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if !strings.Contains(out, `ON CONFLICT ("id")`) {
+		t.Fatalf("expected guessed conflict target, got: %s", out)
+	}
+	if len(warnings) != 1 || warnings[0].Code != sqlparser.WarnConflictTargetGuessed {
+		t.Fatalf("expected %s warning, got %#v", sqlparser.WarnConflictTargetGuessed, warnings)
+	}
+	if warnings[0].Pos != 0 {
+		t.Fatalf("expected warning at INSERT position, got %d", warnings[0].Pos)
+	}
+}
+
+func TestConvertStrictCodesSelective(t *testing.T) {
+	in := `CREATE TABLE t (id INT ZEROFILL COMMENT 'pk') ENGINE=InnoDB`
+	_, warnings, err := sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{
+		Target:      sqlparser.DialectPostgres,
+		Strict:      true,
+		StrictCodes: []string{sqlparser.WarnConflictTargetGuessed},
+	})
+	if err != nil {
+		t.Fatalf("expected non-listed codes to stay warnings, got %v", err)
+	}
+	if len(warnings) != 3 {
+		t.Fatalf("expected 3 warnings, got %#v", warnings)
+	}
+
+	_, _, err = sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{
+		Target:      sqlparser.DialectPostgres,
+		Strict:      true,
+		StrictCodes: []string{sqlparser.WarnTableOptionDropped},
+	})
+	var convErr *sqlparser.ConversionError
+	if !errors.As(err, &convErr) || convErr.Warning.Code != sqlparser.WarnTableOptionDropped {
+		t.Fatalf("expected %s conversion error, got %v", sqlparser.WarnTableOptionDropped, err)
+	}
+}
+
+func TestConvertForeignKeyMatchAndDeferrable(t *testing.T) {
+	in := "CREATE TABLE orders (id INT, user_id INT, " +
+		"CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) MATCH FULL ON DELETE SET NULL DEFERRABLE INITIALLY DEFERRED, " +
+		"UNIQUE (id) DEFERRABLE)"
+	tests := []struct {
+		target   sqlparser.Dialect
+		want     string
+		warnings []string
+	}{
+		{sqlparser.DialectPostgres,
+			`CREATE TABLE "orders" ("id" INT, "user_id" INT, CONSTRAINT "fk_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") MATCH FULL ON DELETE SET NULL DEFERRABLE INITIALLY DEFERRED, UNIQUE ("id") DEFERRABLE)`,
+			nil},
+		{sqlparser.DialectSQLite,
+			`CREATE TABLE "orders" ("id" INT, "user_id" INT, CONSTRAINT "fk_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE SET NULL DEFERRABLE INITIALLY DEFERRED, UNIQUE ("id"))`,
+			[]string{sqlparser.WarnFKMatchDropped, sqlparser.WarnDeferrableUnsupported}},
+		{sqlparser.DialectMySQL,
+			"CREATE TABLE `orders` (`id` INT, `user_id` INT, CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE SET NULL, UNIQUE (`id`))",
+			[]string{sqlparser.WarnFKMatchDropped, sqlparser.WarnDeferrableUnsupported, sqlparser.WarnDeferrableUnsupported}},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.target, err)
+		}
+		if out != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.target, out, tt.want)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if !slices.Equal(codes, tt.warnings) {
+			t.Errorf("%s: warnings %v, want %v", tt.target, codes, tt.warnings)
+		}
+	}
+
+	_, _, err := sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{
+		Target:      sqlparser.DialectMySQL,
+		Strict:      true,
+		StrictCodes: []string{sqlparser.WarnDeferrableUnsupported},
+	})
+	var convErr *sqlparser.ConversionError
+	if !errors.As(err, &convErr) || convErr.Warning.Code != sqlparser.WarnDeferrableUnsupported {
+		t.Fatalf("expected %s conversion error, got %v", sqlparser.WarnDeferrableUnsupported, err)
+	}
+}
+
+func TestConvertColumnReferences(t *testing.T) {
+	tests := []struct {
+		in       string
+		target   sqlparser.Dialect
+		want     string
+		warnings []string
+	}{
+		{"CREATE TABLE c (pid INT REFERENCES p (id) MATCH FULL ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED)", sqlparser.DialectPostgres,
+			`CREATE TABLE "c" ("pid" INT REFERENCES "p" ("id") MATCH FULL ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED)`, nil},
+		{"CREATE TABLE c (pid INT REFERENCES p (id) MATCH FULL ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED)", sqlparser.DialectSQLite,
+			`CREATE TABLE "c" ("pid" INT REFERENCES "p" ("id") ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED)`,
+			[]string{sqlparser.WarnFKMatchDropped}},
+		// MySQL creates no foreign key for a column-level REFERENCES.
+		{"CREATE TABLE c (pid INT REFERENCES p (id) MATCH FULL ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED)", sqlparser.DialectMySQL,
+			"CREATE TABLE `c` (`pid` INT, FOREIGN KEY (`pid`) REFERENCES `p` (`id`) ON DELETE CASCADE)",
+			[]string{sqlparser.WarnFKMatchDropped, sqlparser.WarnDeferrableUnsupported}},
+		{"ALTER TABLE c ADD COLUMN qid INT REFERENCES q (id)", sqlparser.DialectMySQL,
+			"ALTER TABLE `c` ADD COLUMN `qid` INT, ADD FOREIGN KEY (`qid`) REFERENCES `q` (`id`)", nil},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(tt.in, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.target, err)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if out != tt.want || !slices.Equal(codes, tt.warnings) {
+			t.Errorf("%s:\n got %s %v\nwant %s %v", tt.target, out, codes, tt.want, tt.warnings)
+		}
+	}
+}
+
+func TestConvertTableOptionsCanonical(t *testing.T) {
+	in := "CREATE TABLE t (id INT) engine InnoDB, DEFAULT CHARACTER SET utf8mb4 comment 'x' row_format=compact STRICT"
+	out, warnings, err := sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	want := "CREATE TABLE `t` (`id` INT) ENGINE=InnoDB CHARSET=utf8mb4 COMMENT='x' ROW_FORMAT=COMPACT"
+	if out != want || len(warnings) != 1 || warnings[0].Code != sqlparser.WarnTableOptionDropped {
+		t.Fatalf("unexpected conversion:\n got %s %v\nwant %s", out, warnings, want)
+	}
+
+	out, warnings, err = sqlparser.ConvertDialectWithOptions(in+", WITHOUT ROWID", sqlparser.ConvertOptions{Target: sqlparser.DialectSQLite})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if want := `CREATE TABLE "t" ("id" INT) STRICT, WITHOUT ROWID`; out != want || len(warnings) != 4 {
+		t.Fatalf("unexpected conversion:\n got %s %v\nwant %s", out, warnings, want)
+	}
+}
+
+func TestConvertTemporary(t *testing.T) {
+	in := "CREATE TEMP TABLE scratch (id INT); CREATE TEMPORARY VIEW recent AS SELECT id FROM scratch"
+	out, warnings, err := sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if want := `CREATE TEMPORARY TABLE "scratch" ("id" INT); CREATE TEMPORARY VIEW "recent" AS SELECT "id" FROM "scratch"`; out != want || len(warnings) != 0 {
+		t.Fatalf("unexpected conversion:\n got %s %v\nwant %s", out, warnings, want)
+	}
+
+	out, warnings, err = sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if want := "CREATE TEMPORARY TABLE `scratch` (`id` INT); CREATE VIEW `recent` AS SELECT `id` FROM `scratch`"; out != want ||
+		len(warnings) != 1 || warnings[0].Code != sqlparser.WarnTemporaryViewUnsupported {
+		t.Fatalf("unexpected conversion:\n got %s %v\nwant %s", out, warnings, want)
+	}
+}
+
+func TestConvertSequences(t *testing.T) {
+	in := `CREATE TABLE orders (id BIGINT DEFAULT nextval('public.orders_id_seq') NOT NULL PRIMARY KEY, n INT);
+		CREATE SEQUENCE public.orders_id_seq START WITH 1 CACHE 1;
+		ALTER SEQUENCE public.orders_id_seq OWNED BY orders.id;
+		SELECT pg_catalog.setval('public.orders_id_seq', 42, true);
+		CREATE TABLE items (id SERIAL PRIMARY KEY);
+		ALTER SEQUENCE items_id_seq RESTART WITH 100;
+		INSERT INTO orders (id, n) VALUES (nextval('orders_id_seq'), currval('orders_id_seq'))`
+	tests := []struct {
+		target sqlparser.Dialect
+		want   string
+		codes  []string
+	}{
+		{sqlparser.DialectMySQL, "CREATE TABLE `orders` (`id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY, `n` INT); " +
+			"ALTER TABLE `orders` AUTO_INCREMENT = 43; CREATE TABLE `items` (`id` INT AUTO_INCREMENT PRIMARY KEY); " +
+			"ALTER TABLE `items` AUTO_INCREMENT = 100; INSERT INTO `orders` (`id`, `n`) VALUES (NULL, LAST_INSERT_ID())",
+			[]string{sqlparser.WarnSequenceAsAutoIncrement, sqlparser.WarnSequenceAsAutoIncrement, sqlparser.WarnSequenceAsAutoIncrement}},
+		{sqlparser.DialectSQLite, `CREATE TABLE "orders" ("id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "n" INT); ` +
+			`DELETE FROM sqlite_sequence WHERE name = 'orders'; INSERT INTO sqlite_sequence (name, seq) VALUES ('orders', 42); ` +
+			`CREATE TABLE "items" ("id" INTEGER PRIMARY KEY AUTOINCREMENT); ` +
+			`DELETE FROM sqlite_sequence WHERE name = 'items'; INSERT INTO sqlite_sequence (name, seq) VALUES ('items', 99); ` +
+			`INSERT INTO "orders" ("id", "n") VALUES (NULL, last_insert_rowid())`,
+			[]string{sqlparser.WarnSequenceAsAutoIncrement, sqlparser.WarnSequenceAsAutoIncrement, sqlparser.WarnSequenceAsAutoIncrement}},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: convert failed: %v", tt.target, err)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if out != tt.want || !slices.Equal(codes, tt.codes) {
+			t.Errorf("%s:\n got %s %v\nwant %s %v", tt.target, out, codes, tt.want, tt.codes)
+		}
+	}
+
+	// pg_dump names the sequence as a regclass.
+	out, warnings, err := sqlparser.ConvertDialectWithOptions("CREATE TABLE t (id integer DEFAULT nextval('public.t_id_seq'::regclass) NOT NULL)",
+		sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL})
+	if want := "CREATE TABLE `t` (`id` integer NOT NULL AUTO_INCREMENT)"; err != nil || out != want {
+		t.Fatalf("unexpected conversion:\n got %s %v %v\nwant %s", out, warnings, err, want)
+	}
+
+	out, warnings, err = sqlparser.ConvertDialectWithOptions("CREATE SEQUENCE s INCREMENT 5 NOCYCLE; DROP SEQUENCE s; SELECT 1",
+		sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL})
+	if err != nil || out != "SELECT 1" || len(warnings) != 2 || warnings[0].Code != sqlparser.WarnSequenceUnsupported {
+		t.Fatalf("unexpected conversion: %s %v %v", out, warnings, err)
+	}
+	out, _, err = sqlparser.ConvertDialectWithOptions("CREATE SEQUENCE IF NOT EXISTS s AS BIGINT INCREMENT 5 NOCYCLE OWNED BY t.id; ALTER SEQUENCE s RESTART",
+		sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres})
+	if want := `CREATE SEQUENCE IF NOT EXISTS "s" AS BIGINT INCREMENT BY 5 NO CYCLE OWNED BY "t"."id"; ALTER SEQUENCE "s" RESTART`; err != nil || out != want {
+		t.Fatalf("unexpected conversion:\n got %s %v\nwant %s", out, err, want)
+	}
+	out, warnings, err = sqlparser.ConvertDialectWithOptions("CREATE TABLE t (id INT AUTO_INCREMENT PRIMARY KEY) AUTO_INCREMENT=500; SELECT LAST_INSERT_ID()",
+		sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres})
+	if want := `CREATE TABLE "t" ("id" INT GENERATED BY DEFAULT AS IDENTITY (START WITH 500) PRIMARY KEY); SELECT lastval()`; err != nil || out != want || len(warnings) != 0 {
+		t.Fatalf("unexpected conversion:\n got %s %v %v\nwant %s", out, warnings, err, want)
+	}
+}
+
+func TestConvertUserTypes(t *testing.T) {
+	in := `CREATE TYPE public.mood AS ENUM ('sad', 'it''s ok');
+		CREATE DOMAIN posint AS INTEGER NOT NULL DEFAULT 1 CHECK (VALUE > 0);
+		CREATE TABLE people (m public.mood NOT NULL, n posint);
+		DROP TYPE mood`
+	tests := []struct {
+		target sqlparser.Dialect
+		want   string
+	}{
+		{sqlparser.DialectMySQL, "CREATE TABLE `people` (`m` ENUM('sad', 'it''s ok') NOT NULL, `n` INTEGER NOT NULL DEFAULT 1 CHECK ((`n` > 0)))"},
+		{sqlparser.DialectSQLite, `CREATE TABLE "people" ("m" TEXT NOT NULL CHECK ("m" IN ('sad', 'it''s ok')), "n" INTEGER NOT NULL DEFAULT 1 CHECK (("n" > 0)))`},
+		{sqlparser.DialectPostgres, `CREATE TYPE "public"."mood" AS ENUM ('sad', 'it''s ok'); CREATE DOMAIN "posint" AS INTEGER DEFAULT 1 NOT NULL CHECK ((VALUE > 0)); ` +
+			`CREATE TABLE "people" ("m" public.mood NOT NULL, "n" posint); DROP TYPE "mood"`},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: convert failed: %v", tt.target, err)
+		}
+		if out != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.target, out, tt.want)
+		}
+		if inlined := tt.target != sqlparser.DialectPostgres; inlined != (len(warnings) == 3) {
+			t.Errorf("%s: unexpected warnings %v", tt.target, warnings)
+		}
+	}
+
+	out, warnings, err := sqlparser.ConvertDialectWithOptions("CREATE TABLE app.t (s ENUM('a', 'b\\'c') NOT NULL DEFAULT 'a', z SET('x'))",
+		sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres, Source: sqlparser.DialectMySQL})
+	want := `CREATE TYPE "app"."t_s" AS ENUM ('a', 'b''c'); CREATE TABLE "app"."t" ("s" "app"."t_s" NOT NULL DEFAULT 'a', "z" TEXT)`
+	if err != nil || out != want || len(warnings) != 2 || warnings[0].Code != sqlparser.WarnEnumTypeCreated || warnings[1].Code != sqlparser.WarnSetTypeUnsupported {
+		t.Fatalf("unexpected conversion:\n got %s %v %v\nwant %s", out, warnings, err, want)
+	}
+	out, err = sqlparser.ConvertDialect("CREATE TABLE t (s ENUM('a', 'b'), z SET('x', 'y'))", sqlparser.DialectMySQL)
+	if want := "CREATE TABLE `t` (`s` ENUM('a', 'b'), `z` SET('x', 'y'))"; err != nil || out != want {
+		t.Fatalf("unexpected conversion:\n got %s %v\nwant %s", out, err, want)
+	}
+}
+
+func TestConvertDataTypes(t *testing.T) {
+	const in = "CREATE TABLE t (a TINYINT(1), b INT(11) UNSIGNED NOT NULL, c BIGINT UNSIGNED, d DATETIME(3), " +
+		"e LONGTEXT, f VARBINARY(16), g YEAR(4), h DOUBLE(10,2), i BYTEA, j UUID, k TIMESTAMPTZ, l VARCHAR, m JSONB)"
+	tests := []struct {
+		target sqlparser.Dialect
+		want   string
+	}{
+		{sqlparser.DialectPostgres, "CREATE TABLE t (a BOOLEAN, b BIGINT NOT NULL CHECK (b >= 0), c NUMERIC(20) CHECK (c >= 0), d TIMESTAMP(3), " +
+			"e TEXT, f BYTEA, g SMALLINT, h DOUBLE PRECISION, i BYTEA, j UUID, k TIMESTAMPTZ, l VARCHAR, m JSONB)"},
+		{sqlparser.DialectMySQL, "CREATE TABLE t (a TINYINT(1), b INT(11) UNSIGNED NOT NULL, c BIGINT UNSIGNED, d DATETIME(3), " +
+			"e LONGTEXT, f VARBINARY(16), g YEAR(4), h DOUBLE(10,2), i LONGBLOB, j CHAR(36), k DATETIME, l TEXT, m JSON)"},
+		{sqlparser.DialectSQLite, "CREATE TABLE t (a TINYINT(1), b INT(11) NOT NULL CHECK (b >= 0), c BIGINT CHECK (c >= 0), d DATETIME(3), " +
+			"e LONGTEXT, f VARBINARY(16), g YEAR(4), h DOUBLE(10,2), i BLOB, j TEXT, k TIMESTAMPTZ, l VARCHAR, m TEXT)"},
+		{sqlparser.DialectClickHouse, "CREATE TABLE t (a Int8 NULL, b UInt32 NOT NULL, c UInt64 NULL, d DateTime64(3) NULL, " +
+			"e String NULL, f String NULL, g UInt16 NULL, h Float64 NULL, i String NULL, j UUID NULL, k DateTime NULL, l String NULL, m String NULL)"},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{Target: tt.target, Quote: sqlparser.QuoteWhenNeeded})
+		if err != nil {
+			t.Fatalf("%s: convert failed: %v", tt.target, err)
+		}
+		if out != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.target, out, tt.want)
+		}
+		if boolean := tt.target == sqlparser.DialectPostgres; boolean != (len(warnings) == 1 && warnings[0].Code == sqlparser.WarnTinyintAsBoolean) {
+			t.Errorf("%s: unexpected warnings %v", tt.target, warnings)
+		}
+	}
+
+	out, warnings, err := sqlparser.ConvertDialectWithOptions("SELECT CAST(a AS UNSIGNED), CAST(b AS INT UNSIGNED) FROM t",
+		sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres, Quote: sqlparser.QuoteWhenNeeded})
+	want := "SELECT CAST(a AS NUMERIC(20)), CAST(b AS BIGINT) FROM t"
+	if err != nil || out != want || len(warnings) != 1 || warnings[0].Code != sqlparser.WarnUnsignedDropped {
+		t.Fatalf("unexpected conversion:\n got %s %v %v\nwant %s", out, warnings, err, want)
+	}
+}
+
+func TestConvertMySQLCast(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"SELECT CAST(a AS bigint), a::int, CAST(a AS int8)", "SELECT CAST(a AS SIGNED), CAST(a AS SIGNED), CAST(a AS SIGNED)"},
+		{"SELECT CAST(a AS text), CAST(a AS varchar(40)), CAST(a AS character varying)", "SELECT CAST(a AS CHAR), CAST(a AS CHAR(40)), CAST(a AS CHAR)"},
+		{"SELECT CAST(a AS numeric(10,2)), CAST(a AS real), CAST(a AS double precision)", "SELECT CAST(a AS DECIMAL(10,2)), CAST(a AS DOUBLE), CAST(a AS DOUBLE)"},
+		{"SELECT CAST(a AS timestamp(3)), CAST(a AS timestamptz), CAST(a AS date), CAST(a AS time)", "SELECT CAST(a AS DATETIME(3)), CAST(a AS DATETIME), CAST(a AS DATE), CAST(a AS TIME)"},
+		{"SELECT CAST(a AS jsonb), CAST(a AS bytea), CAST(a AS uuid)", "SELECT CAST(a AS JSON), CAST(a AS BINARY), CAST(a AS CHAR(36))"},
+		{"SELECT CAST(a AS SIGNED), CAST(a AS UNSIGNED), CAST(a AS INT UNSIGNED)", "SELECT CAST(a AS SIGNED), CAST(a AS UNSIGNED), CAST(a AS UNSIGNED)"},
+	}
+	for _, tt := range tests {
+		out, _, err := sqlparser.ConvertDialectWithOptions(tt.in, sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL, Quote: sqlparser.QuoteWhenNeeded})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.in, err)
+		}
+		if out != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.in, out, tt.want)
+		}
+	}
+}
+
+func TestConvertPostgresTypes(t *testing.T) {
+	const in = "CREATE TABLE hosts (id UUID PRIMARY KEY DEFAULT gen_random_uuid(), ip INET, mac MACADDR, price MONEY, " +
+		"ttl INTERVAL DAY TO SECOND, seen TIMESTAMP WITH TIME ZONE, score DOUBLE PRECISION)"
+	tests := []struct {
+		target sqlparser.Dialect
+		want   string
+		losses int
+	}{
+		{sqlparser.DialectPostgres, "CREATE TABLE hosts (id UUID DEFAULT GEN_RANDOM_UUID() PRIMARY KEY, ip INET, mac MACADDR, price MONEY, " +
+			"ttl INTERVAL DAY TO SECOND, seen TIMESTAMPTZ, score DOUBLE PRECISION)", 0},
+		{sqlparser.DialectMySQL, "CREATE TABLE hosts (id CHAR(36) DEFAULT (UUID()) PRIMARY KEY, ip VARCHAR(43), mac CHAR(17), price DECIMAL(19,2), " +
+			"ttl VARCHAR(64), seen DATETIME, score DOUBLE)", 6},
+		{sqlparser.DialectSQLite, "CREATE TABLE hosts (id TEXT DEFAULT GEN_RANDOM_UUID() PRIMARY KEY, ip TEXT, mac TEXT, price MONEY, " +
+			"ttl TEXT, seen TIMESTAMPTZ, score DOUBLE)", 4},
+		{sqlparser.DialectClickHouse, "CREATE TABLE hosts (id UUID DEFAULT generateUUIDv4() PRIMARY KEY, ip String NULL, mac String NULL, price Decimal(19, 2) NULL, " +
+			"ttl String NULL, seen DateTime NULL, score Float64 NULL)", 3},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{Target: tt.target, Quote: sqlparser.QuoteWhenNeeded})
+		if err != nil {
+			t.Fatalf("%s: convert failed: %v", tt.target, err)
+		}
+		if out != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.target, out, tt.want)
+		}
+		losses := 0
+		for _, w := range warnings {
+			if w.Code == sqlparser.WarnTypeApproximated {
+				losses++
+			}
+		}
+		if losses != tt.losses {
+			t.Errorf("%s: got %d %s warnings, want %d: %v", tt.target, losses, sqlparser.WarnTypeApproximated, tt.losses, warnings)
+		}
+	}
+
+	out, err := sqlparser.ConvertDialect("SELECT id::text, '10.0.0.1'::inet FROM hosts", sqlparser.DialectMySQL)
+	if want := "SELECT CAST(`id` AS CHAR), CAST('10.0.0.1' AS CHAR(43)) FROM `hosts`"; err != nil || out != want {
+		t.Errorf("unexpected conversion:\n got %s %v\nwant %s", out, err, want)
+	}
+}
+
+func TestConvertSpatial(t *testing.T) {
+	const in = "CREATE TABLE places (g GEOMETRY(Point, 4326), p POINT NOT NULL SRID 4326, area MULTIPOLYGON, shape GEOMETRY, SPATIAL INDEX idx_p (p)); " +
+		"CREATE SPATIAL INDEX idx_g ON places (g); " +
+		"SELECT ST_Distance(g, ST_GeomFromText('POINT(1 2)', 4326)) FROM places"
+	tests := []struct {
+		target sqlparser.Dialect
+		want   string
+		codes  []string
+	}{
+		{sqlparser.DialectPostgres, "CREATE TABLE places (g GEOMETRY(Point, 4326), p GEOMETRY(Point, 4326) NOT NULL, area GEOMETRY(MultiPolygon), shape GEOMETRY); " +
+			"CREATE INDEX idx_p ON places USING GIST (p); " +
+			"CREATE INDEX idx_g ON places USING GIST (g); " +
+			"SELECT ST_DISTANCE(g, ST_GEOMFROMTEXT('POINT(1 2)', 4326)) FROM places",
+			nil},
+		{sqlparser.DialectMySQL, "CREATE TABLE places (g POINT SRID 4326, p POINT SRID 4326 NOT NULL, area MULTIPOLYGON, shape GEOMETRY, SPATIAL INDEX idx_p (p)); " +
+			"CREATE SPATIAL INDEX idx_g ON places (g); " +
+			"SELECT ST_DISTANCE(g, ST_GEOMFROMTEXT('POINT(1 2)', 4326)) FROM places",
+			nil},
+		{sqlparser.DialectSQLite, "CREATE TABLE places (g POINT, p POINT NOT NULL, area MULTIPOLYGON, shape GEOMETRY); " +
+			"CREATE INDEX idx_g ON places (g); " +
+			"SELECT ST_DISTANCE(g, ST_GEOMFROMTEXT('POINT(1 2)', 4326)) FROM places",
+			[]string{sqlparser.WarnSpatialExtension, sqlparser.WarnSpatialExtension, sqlparser.WarnSpatialExtension, sqlparser.WarnSpatialExtension,
+				sqlparser.WarnIndexDropped, sqlparser.WarnSpatialExtension, sqlparser.WarnSpatialExtension, sqlparser.WarnSpatialExtension}},
+		{sqlparser.DialectClickHouse, "CREATE TABLE places (g Point, p Point NOT NULL, area MultiPolygon, shape String NULL); " +
+			"CREATE INDEX idx_g ON places (g); " +
+			"SELECT ST_DISTANCE(g, ST_GEOMFROMTEXT('POINT(1 2)', 4326)) FROM places",
+			[]string{sqlparser.WarnTypeApproximated, sqlparser.WarnIndexDropped, sqlparser.WarnIndexMethodDropped}},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{Target: tt.target, Quote: sqlparser.QuoteWhenNeeded})
+		if err != nil {
+			t.Fatalf("%s: convert failed: %v", tt.target, err)
+		}
+		if out != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.target, out, tt.want)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if !slices.Equal(codes, tt.codes) {
+			t.Errorf("%s: got warnings %v, want %v", tt.target, codes, tt.codes)
+		}
+	}
+
+	out, warnings, err := sqlparser.ConvertDialectWithOptions("CREATE TABLE docs (id INT, body TEXT, FULLTEXT KEY (body))", sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres})
+	if want := `CREATE TABLE "docs" ("id" INT, "body" TEXT); CREATE INDEX ON "docs" ("body")`; err != nil || out != want || len(warnings) != 1 || warnings[0].Code != sqlparser.WarnIndexMethodDropped {
+		t.Errorf("inline FULLTEXT key:\n got %s %v %v\nwant %s", out, warnings, err, want)
+	}
+}
+
+func TestConvertBooleans(t *testing.T) {
+	const in = "CREATE TABLE users (id INT, active TINYINT(1) NOT NULL DEFAULT 1, admin BOOLEAN DEFAULT FALSE, n INT DEFAULT 1); " +
+		"INSERT INTO users VALUES (1, 5, 0, 1); " +
+		"UPDATE users SET active = 0, admin = 2, n = 1 WHERE admin = 1 AND n = 1; " +
+		"SELECT u.id FROM users u WHERE u.active IN (1, 0) AND u.admin = TRUE"
+	tests := []struct {
+		target sqlparser.Dialect
+		want   string
+	}{
+		{sqlparser.DialectPostgres, "CREATE TABLE users (id INT, active BOOLEAN NOT NULL DEFAULT TRUE, admin BOOLEAN DEFAULT FALSE, n INT DEFAULT 1); " +
+			"INSERT INTO users VALUES (1, TRUE, FALSE, 1); " +
+			"UPDATE users SET active = FALSE, admin = TRUE, n = 1 WHERE ((admin = TRUE) AND (n = 1)); " +
+			"SELECT u.id FROM users u WHERE (u.active IN (TRUE, FALSE) AND (u.admin = TRUE))"},
+		{sqlparser.DialectMySQL, "CREATE TABLE users (id INT, active TINYINT(1) NOT NULL DEFAULT 1, admin TINYINT(1) DEFAULT 0, n INT DEFAULT 1); " +
+			"INSERT INTO users VALUES (1, 5, 0, 1); " +
+			"UPDATE users SET active = 0, admin = 2, n = 1 WHERE ((admin = 1) AND (n = 1)); " +
+			"SELECT u.id FROM users u WHERE (u.active IN (1, 0) AND (u.admin = 1))"},
+		{sqlparser.DialectSQLite, "CREATE TABLE users (id INT, active TINYINT(1) NOT NULL DEFAULT 1, admin INTEGER DEFAULT 0, n INT DEFAULT 1); " +
+			"INSERT INTO users VALUES (1, 5, 0, 1); " +
+			"UPDATE users SET active = 0, admin = 2, n = 1 WHERE ((admin = 1) AND (n = 1)); " +
+			"SELECT u.id FROM users u WHERE (u.active IN (1, 0) AND (u.admin = 1))"},
+	}
+	for _, tt := range tests {
+		out, _, err := sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{Target: tt.target, Quote: sqlparser.QuoteWhenNeeded, ConvertBooleans: true})
+		if err != nil {
+			t.Fatalf("%s: convert failed: %v", tt.target, err)
+		}
+		if out != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.target, out, tt.want)
+		}
+	}
+
+	// Without the option, and for columns only a schema declares.
+	out, _, err := sqlparser.ConvertDialectWithOptions("SELECT id FROM users WHERE active = 1", sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres, Quote: sqlparser.QuoteWhenNeeded})
+	if want := "SELECT id FROM users WHERE (active = 1)"; err != nil || out != want {
+		t.Errorf("without ConvertBooleans:\n got %s %v\nwant %s", out, err, want)
+	}
+	schema, err := sqlparser.BuildSchema("CREATE TABLE users (id INT, active BOOL)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _, err = sqlparser.ConvertDialectWithOptions("SELECT id FROM users WHERE active = 1", sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres, Quote: sqlparser.QuoteWhenNeeded, ConvertBooleans: true, Schema: schema})
+	if want := "SELECT id FROM users WHERE (active = TRUE)"; err != nil || out != want {
+		t.Errorf("with a schema:\n got %s %v\nwant %s", out, err, want)
+	}
+}
+
+func TestConvertTypeMap(t *testing.T) {
+	in := "CREATE TABLE t (at DATETIME(3), code CHAR(8), doc JSON, n INT UNSIGNED, s ENUM('a', 'b'))"
+	out, warnings, err := sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{
+		Target:  sqlparser.DialectPostgres,
+		Quote:   sqlparser.QuoteWhenNeeded,
+		TypeMap: sqlparser.TypeMap{"DateTime": "TIMESTAMPTZ", "char": "VARCHAR", "json": "JSONB", "int": "NUMERIC(10)", "enum": "TEXT"},
+	})
+	want := "CREATE TABLE t (at TIMESTAMPTZ(3), code VARCHAR(8), doc JSONB, n NUMERIC(10), s TEXT)"
+	if err != nil || out != want {
+		t.Fatalf("unexpected conversion:\n got %s %v\nwant %s", out, err, want)
+	}
+	if len(warnings) != 1 || warnings[0].Code != sqlparser.WarnUnsignedDropped {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+}
+
+func TestConvertCreateIndex(t *testing.T) {
+	src := "CREATE INDEX CONCURRENTLY docs_body ON docs USING gin (body jsonb_path_ops) WHERE deleted_at IS NULL; " +
+		"CREATE UNIQUE INDEX users_email ON users ((lower(email)), tenant_id DESC) USING BTREE"
+	tests := []struct {
+		target   sqlparser.Dialect
+		want     string
+		warnings []string
+	}{
+		{sqlparser.DialectPostgres,
+			`CREATE INDEX CONCURRENTLY "docs_body" ON "docs" USING gin ("body" jsonb_path_ops) WHERE "deleted_at" IS NULL; ` +
+				`CREATE UNIQUE INDEX "users_email" ON "users" USING BTREE ((LOWER("email")), "tenant_id" DESC)`,
+			nil},
+		{sqlparser.DialectMySQL,
+			"CREATE INDEX `docs_body` ON `docs` (`body`); CREATE UNIQUE INDEX `users_email` ON `users` ((LOWER(`email`)), `tenant_id` DESC) USING BTREE",
+			[]string{sqlparser.WarnIndexMethodDropped, sqlparser.WarnIndexMethodDropped, sqlparser.WarnPartialIndexDropped}},
+		{sqlparser.DialectSQLite,
+			`CREATE INDEX "docs_body" ON "docs" ("body") WHERE "deleted_at" IS NULL; CREATE UNIQUE INDEX "users_email" ON "users" ((LOWER("email")), "tenant_id" DESC)`,
+			[]string{sqlparser.WarnIndexMethodDropped, sqlparser.WarnIndexMethodDropped, sqlparser.WarnIndexMethodDropped}},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(src, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.target, err)
+		}
+		if out != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.target, out, tt.want)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if !slices.Equal(codes, tt.warnings) {
+			t.Errorf("%s: warnings %v, want %v", tt.target, codes, tt.warnings)
+		}
+	}
+}
+
+func TestConvertCreateIndexOptions(t *testing.T) {
+	src := "CREATE INDEX orders_user ON orders (user_id) INCLUDE (total) WITH (FILLFACTOR = 70); " +
+		"CREATE INDEX t_a ON t (a) KEY_BLOCK_SIZE 8 COMMENT 'lookup' INVISIBLE; " +
+		"CREATE INDEX users_seen ON users (lower(email) DESC NULLS LAST)"
+	tests := []struct {
+		target   sqlparser.Dialect
+		want     string
+		warnings []string
+	}{
+		{sqlparser.DialectPostgres,
+			`CREATE INDEX "orders_user" ON "orders" ("user_id") INCLUDE ("total") WITH (fillfactor = 70); CREATE INDEX "t_a" ON "t" ("a"); ` +
+				`CREATE INDEX "users_seen" ON "users" ((LOWER("email")) DESC NULLS LAST)`,
+			[]string{sqlparser.WarnIndexOptionDropped, sqlparser.WarnIndexOptionDropped, sqlparser.WarnIndexOptionDropped}},
+		{sqlparser.DialectMySQL,
+			"CREATE INDEX `orders_user` ON `orders` (`user_id`); CREATE INDEX `t_a` ON `t` (`a`) KEY_BLOCK_SIZE=8 COMMENT 'lookup' INVISIBLE; " +
+				"CREATE INDEX `users_seen` ON `users` ((LOWER(`email`)) DESC)",
+			[]string{sqlparser.WarnIndexOptionDropped, sqlparser.WarnIndexOptionDropped, sqlparser.WarnIndexOptionDropped}},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(src, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.target, err)
+		}
+		if out != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.target, out, tt.want)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if !slices.Equal(codes, tt.warnings) {
+			t.Errorf("%s: warnings %v, want %v", tt.target, codes, tt.warnings)
+		}
+	}
+}
+
+func TestConvertQuantifiedComparison(t *testing.T) {
+	src := "SELECT id FROM orders WHERE user_id = ANY (SELECT id FROM vip) AND status <> ALL (SELECT s FROM closed) AND total > ALL (SELECT total FROM refunds) AND tag = ANY (?)"
+	tests := []struct {
+		target   sqlparser.Dialect
+		want     string
+		warnings []string
+	}{
+		{sqlparser.DialectPostgres,
+			`SELECT "id" FROM "orders" WHERE (((("user_id" = ANY (SELECT "id" FROM "vip")) AND ("status" != ALL (SELECT "s" FROM "closed"))) AND ("total" > ALL (SELECT "total" FROM "refunds"))) AND ("tag" = ANY ($1)))`,
+			nil},
+		{sqlparser.DialectMySQL,
+			"SELECT `id` FROM `orders` WHERE ((((`user_id` = ANY (SELECT `id` FROM `vip`)) AND (`status` != ALL (SELECT `s` FROM `closed`))) AND (`total` > ALL (SELECT `total` FROM `refunds`))) AND (`tag` = ANY (?)))",
+			[]string{sqlparser.WarnQuantifiedUnsupported}},
+		{sqlparser.DialectSQLite,
+			`SELECT "id" FROM "orders" WHERE ((("user_id" IN (SELECT "id" FROM "vip") AND "status" NOT IN (SELECT "s" FROM "closed")) AND ("total" > ALL (SELECT "total" FROM "refunds"))) AND ("tag" = ANY (?)))`,
+			[]string{sqlparser.WarnQuantifiedUnsupported, sqlparser.WarnQuantifiedUnsupported}},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(src, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.target, err)
+		}
+		if out != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.target, out, tt.want)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if !slices.Equal(codes, tt.warnings) {
+			t.Errorf("%s: warnings %v, want %v", tt.target, codes, tt.warnings)
+		}
+	}
+}
+
+func TestConvertValuesTable(t *testing.T) {
+	src := "VALUES (1, 'a'); SELECT v.name FROM (VALUES (1, 'a'), (2, 'b')) v (id, name)"
+	tests := []struct {
+		target sqlparser.Dialect
+		want   string
+	}{
+		{sqlparser.DialectPostgres, `VALUES (1, 'a'); SELECT "v"."name" FROM (VALUES (1, 'a'), (2, 'b')) "v" ("id", "name")`},
+		{sqlparser.DialectMySQL, "VALUES ROW(1, 'a'); SELECT `v`.`name` FROM (VALUES ROW(1, 'a'), ROW(2, 'b')) `v` (`id`, `name`)"},
+		{sqlparser.DialectSQLite, `VALUES (1, 'a'); SELECT "v"."name" FROM (SELECT column1 AS "id", column2 AS "name" FROM (VALUES (1, 'a'), (2, 'b'))) "v"`},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(src, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.target, err)
+		}
+		if out != tt.want || len(warnings) != 0 {
+			t.Errorf("%s:\n got %s %v\nwant %s", tt.target, out, warnings, tt.want)
+		}
+	}
+
+	_, warnings, err := sqlparser.ConvertDialectWithOptions("SELECT n FROM (SELECT 1) s (n)", sqlparser.ConvertOptions{Target: sqlparser.DialectSQLite})
+	if err != nil || len(warnings) != 1 || warnings[0].Code != sqlparser.WarnDerivedColumnsDropped {
+		t.Fatalf("expected %s, got %v %v", sqlparser.WarnDerivedColumnsDropped, warnings, err)
+	}
+}
+
+func TestConvertInsertDefaultsAndSet(t *testing.T) {
+	tests := []struct {
+		src      string
+		target   sqlparser.Dialect
+		want     string
+		warnings []string
+	}{
+		{"INSERT INTO t DEFAULT VALUES", sqlparser.DialectMySQL, "INSERT INTO `t` () VALUES ()", nil},
+		{"INSERT INTO t () VALUES ()", sqlparser.DialectPostgres, `INSERT INTO "t" DEFAULT VALUES`, nil},
+		{"INSERT INTO t SET a = 1, b = DEFAULT", sqlparser.DialectMySQL, "INSERT INTO `t` SET `a` = 1, `b` = DEFAULT", nil},
+		{"INSERT INTO t SET a = 1, b = DEFAULT", sqlparser.DialectPostgres, `INSERT INTO "t" ("a", "b") VALUES (1, DEFAULT)`, nil},
+		{"INSERT INTO t SET a = 1, b = DEFAULT", sqlparser.DialectSQLite, `INSERT INTO "t" ("a") VALUES (1)`, nil},
+		{"INSERT INTO t (a, b) VALUES (DEFAULT, DEFAULT)", sqlparser.DialectSQLite, `INSERT INTO "t" DEFAULT VALUES`, nil},
+		{"INSERT INTO t (a, b) VALUES (1, DEFAULT), (DEFAULT, 2)", sqlparser.DialectSQLite, `INSERT INTO "t" ("a", "b") VALUES (1, DEFAULT), (DEFAULT, 2)`,
+			[]string{sqlparser.WarnDefaultValueUnsupported, sqlparser.WarnDefaultValueUnsupported}},
+		{"UPDATE t SET a = DEFAULT", sqlparser.DialectSQLite, `UPDATE "t" SET "a" = DEFAULT`, []string{sqlparser.WarnDefaultValueUnsupported}},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(tt.src, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s %q: %v", tt.target, tt.src, err)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if out != tt.want || !slices.Equal(codes, tt.warnings) {
+			t.Errorf("%s %q:\n got %s %v\nwant %s %v", tt.target, tt.src, out, codes, tt.want, tt.warnings)
+		}
+	}
+}
+
+func TestConvertMaintenanceStatements(t *testing.T) {
+	out, warnings, err := sqlparser.ConvertDialectWithOptions(`REINDEX (VERBOSE) TABLE CONCURRENTLY users; CLUSTER users USING users_pkey`,
+		sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if out != `REINDEX (VERBOSE) TABLE CONCURRENTLY "users"; CLUSTER "users" USING users_pkey` {
+		t.Fatalf("unexpected output: %s", out)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("expected no warnings, got %#v", warnings)
+	}
+
+	_, warnings, err = sqlparser.ConvertDialectWithOptions(`OPTIMIZE TABLE t1`, sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].Code != sqlparser.WarnMaintenanceVendor {
+		t.Fatalf("expected %s warning, got %#v", sqlparser.WarnMaintenanceVendor, warnings)
+	}
+
+	// SQLite takes VACUUM, ANALYZE and REINDEX with a name only.
+	for _, src := range []string{"VACUUM FULL", "VACUUM (VERBOSE, ANALYZE) t", "ANALYZE VERBOSE t", "ANALYZE t (a, b)", "REINDEX TABLE CONCURRENTLY t"} {
+		_, warnings, err = sqlparser.ConvertDialectWithOptions(src, sqlparser.ConvertOptions{Target: sqlparser.DialectSQLite})
+		if err != nil {
+			t.Fatalf("%s: convert failed: %v", src, err)
+		}
+		if len(warnings) != 1 || warnings[0].Code != sqlparser.WarnMaintenanceVendor {
+			t.Errorf("%s: expected %s warning, got %#v", src, sqlparser.WarnMaintenanceVendor, warnings)
+		}
+		if _, _, err = sqlparser.ConvertDialectWithOptions(src, sqlparser.ConvertOptions{Target: sqlparser.DialectSQLite, Strict: true}); err == nil {
+			t.Errorf("%s: expected an error under Strict", src)
+		}
+	}
+	for _, src := range []string{"VACUUM", "ANALYZE t", "REINDEX t"} {
+		_, warnings, err = sqlparser.ConvertDialectWithOptions(src, sqlparser.ConvertOptions{Target: sqlparser.DialectSQLite})
+		if err != nil || len(warnings) != 0 {
+			t.Errorf("%s: expected no warnings, got %#v, %v", src, warnings, err)
+		}
+	}
+}
+
+func TestConvertExplain(t *testing.T) {
+	tests := []struct {
+		in     string
+		target sqlparser.Dialect
+		want   string
+		codes  []string
+	}{
+		{"EXPLAIN ANALYZE FORMAT=JSON SELECT 1", sqlparser.DialectPostgres, "EXPLAIN (ANALYZE, FORMAT JSON) SELECT 1", nil},
+		{"EXPLAIN (ANALYZE, FORMAT JSON, BUFFERS) SELECT 1", sqlparser.DialectMySQL, "EXPLAIN ANALYZE FORMAT=JSON SELECT 1",
+			[]string{sqlparser.WarnExplainOptionDropped}},
+		{"EXPLAIN FORMAT=TREE SELECT 1", sqlparser.DialectPostgres, "EXPLAIN SELECT 1", nil},
+		{"EXPLAIN (FORMAT YAML, COSTS off) SELECT 1", sqlparser.DialectPostgres, "EXPLAIN (FORMAT YAML, COSTS off) SELECT 1", nil},
+		{"EXPLAIN ANALYZE SELECT 1", sqlparser.DialectSQLite, "EXPLAIN QUERY PLAN SELECT 1", []string{sqlparser.WarnExplainOptionDropped}},
+		{"EXPLAIN QUERY PLAN SELECT 1", sqlparser.DialectMySQL, "EXPLAIN SELECT 1", nil},
+		{"EXPLAIN SELECT 1", sqlparser.DialectSQLite, "EXPLAIN SELECT 1", nil},
+		{"DESCRIBE users", sqlparser.DialectMySQL, "DESCRIBE `users`", nil},
+		{"DESC app.users email", sqlparser.DialectPostgres, "SELECT column_name, data_type, is_nullable, column_default FROM information_schema.columns " +
+			"WHERE table_schema = 'app' AND table_name = 'users' AND column_name = 'email' ORDER BY ordinal_position",
+			[]string{sqlparser.WarnDescribeRewritten}},
+		{"EXPLAIN users", sqlparser.DialectSQLite, "PRAGMA table_info('users')", []string{sqlparser.WarnDescribeRewritten}},
+		{"DESCRIBE users email", sqlparser.DialectSQLite, "SELECT * FROM pragma_table_info('users') WHERE name = 'email'",
+			[]string{sqlparser.WarnDescribeRewritten}},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(tt.in, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: convert failed: %v", tt.in, err)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if out != tt.want || !slices.Equal(codes, tt.codes) {
+			t.Errorf("%s -> %s:\n got %s %v\nwant %s %v", tt.in, tt.target, out, codes, tt.want, tt.codes)
+		}
+	}
+}
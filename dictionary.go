@@ -0,0 +1,130 @@
+package sqlparser
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// DictionaryFormat selects the layout DataDictionary produces.
+type DictionaryFormat uint8
+
+const (
+	// DictionaryCSV emits RFC 4180 CSV with CRLF line endings and a header
+	// row, which spreadsheet applications open directly.
+	DictionaryCSV DictionaryFormat = iota
+	// DictionaryMarkdown emits a GitHub-flavoured Markdown table.
+	DictionaryMarkdown
+)
+
+var dictionaryHeader = []string{"Table", "Column", "Type", "Nullable", "Default", "Comment", "References"}
+
+// DataDictionary lists every column of every table as one row: table,
+// column, declared type, nullability (YES or NO), default expression,
+// comment and the table.column a foreign key points at. Tables and columns
+// keep their declaration order.
+func (s *Schema) DataDictionary(format DictionaryFormat) ([]byte, error) {
+	rows := [][]string{dictionaryHeader}
+	r := newDialectRenderer(ConvertOptions{})
+	for _, t := range s.Tables {
+		for _, c := range t.Columns {
+			nullable := "NO"
+			if c.Nullable {
+				nullable = "YES"
+			}
+			def := ""
+			if c.Default != nil {
+				def = defaultText(r, c.Default)
+			}
+			rows = append(rows, []string{t.schemaKey(), c.Name, c.typeText(), nullable, def, c.Comment, t.reference(c.Name)})
+		}
+	}
+
+	var buf bytes.Buffer
+	if format == DictionaryMarkdown {
+		for i, row := range rows {
+			writeMarkdownRow(&buf, row)
+			if i == 0 {
+				buf.WriteString("|" + strings.Repeat(" --- |", len(row)) + "\n")
+			}
+		}
+		return buf.Bytes(), nil
+	}
+	w := csv.NewWriter(&buf)
+	w.UseCRLF = true
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// typeText renders the column type the way it was declared, e.g.
+// DECIMAL(10,2), INT UNSIGNED or ENUM('a','b').
+func (c *Column) typeText() string {
+	var b strings.Builder
+	b.WriteString(c.Type)
+	switch {
+	case len(c.EnumValues) > 0:
+		b.WriteByte('(')
+		for i, v := range c.EnumValues {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString("'" + strings.ReplaceAll(v, "'", "''") + "'")
+		}
+		b.WriteByte(')')
+	case c.Precision > 0:
+		b.WriteString("(" + strconv.Itoa(c.Precision))
+		if c.Scale > 0 {
+			b.WriteString("," + strconv.Itoa(c.Scale))
+		}
+		b.WriteByte(')')
+	}
+	if c.Unsigned {
+		b.WriteString(" UNSIGNED")
+	}
+	return b.String()
+}
+
+// reference returns "table.column" for the foreign key covering column
+// name, or "" when there is none.
+func (t *Table) reference(name string) string {
+	for _, fk := range t.ForeignKeys {
+		for i, col := range fk.Columns {
+			if !strings.EqualFold(col, name) {
+				continue
+			}
+			if i < len(fk.RefColumns) {
+				return fk.RefTable + "." + fk.RefColumns[i]
+			}
+			return fk.RefTable
+		}
+	}
+	return ""
+}
+
+// defaultText renders a DEFAULT expression as SQL, without the
+// parentheses renderExpr puts around a negative number.
+func defaultText(r *dialectRenderer, e ast.Expr) string {
+	if u, ok := e.(*ast.UnaryExpr); ok && u.Op == lexer.MINUS {
+		if lit, ok := u.Expr.(*ast.Literal); ok {
+			return "-" + string(lit.Raw)
+		}
+	}
+	return r.renderExpr(e)
+}
+
+func writeMarkdownRow(buf *bytes.Buffer, row []string) {
+	buf.WriteByte('|')
+	for _, cell := range row {
+		cell = strings.ReplaceAll(cell, "|", `\|`)
+		cell = strings.ReplaceAll(cell, "\r\n", "<br>")
+		cell = strings.ReplaceAll(cell, "\n", "<br>")
+		buf.WriteString(" " + cell + " |")
+	}
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,58 @@
+package sqlparser_test
+
+import (
+	"strings"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestSchemaDataDictionary(t *testing.T) {
+	s, err := sqlparser.BuildSchema(`
+CREATE TABLE users (
+	id BIGINT UNSIGNED NOT NULL PRIMARY KEY,
+	email VARCHAR(255) NOT NULL COMMENT 'login, unique',
+	balance DECIMAL(10,2) DEFAULT -1,
+	status ENUM('new', 'gone') DEFAULT 'new'
+);
+CREATE TABLE orders (
+	id INT PRIMARY KEY,
+	user_id BIGINT NOT NULL,
+	note TEXT COMMENT 'a | b',
+	FOREIGN KEY (user_id) REFERENCES users (id)
+)`)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	out, err := s.DataDictionary(sqlparser.DictionaryCSV)
+	if err != nil {
+		t.Fatalf("csv export failed: %v", err)
+	}
+	want := "Table,Column,Type,Nullable,Default,Comment,References\r\n" +
+		"users,id,BIGINT UNSIGNED,NO,,,\r\n" +
+		"users,email,VARCHAR(255),NO,,\"login, unique\",\r\n" +
+		"users,balance,\"DECIMAL(10,2)\",YES,-1,,\r\n" +
+		"users,status,\"ENUM('new','gone')\",YES,'new',,\r\n" +
+		"orders,id,INT,NO,,,\r\n" +
+		"orders,user_id,BIGINT,NO,,,users.id\r\n" +
+		"orders,note,TEXT,YES,,a | b,\r\n"
+	if string(out) != want {
+		t.Fatalf("unexpected CSV:\n%s\nwant:\n%s", out, want)
+	}
+
+	out, err = s.DataDictionary(sqlparser.DictionaryMarkdown)
+	if err != nil {
+		t.Fatalf("markdown export failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	if len(lines) != 9 {
+		t.Fatalf("expected header, separator and 7 rows, got:\n%s", out)
+	}
+	if lines[1] != "| --- | --- | --- | --- | --- | --- | --- |" {
+		t.Fatalf("unexpected separator %q", lines[1])
+	}
+	if lines[8] != `| orders | note | TEXT | YES |  | a \| b |  |` {
+		t.Fatalf("pipe not escaped: %q", lines[8])
+	}
+}
@@ -0,0 +1,200 @@
+package sqlparser
+
+import (
+	"html"
+	"slices"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ident"
+)
+
+// ERDFormat selects the diagram language ERD produces.
+type ERDFormat uint8
+
+const (
+	// ERDDot emits a Graphviz digraph with one HTML-table node per table
+	// and crow's foot arrows between columns.
+	ERDDot ERDFormat = iota
+	// ERDMermaid emits a Mermaid erDiagram.
+	ERDMermaid
+)
+
+// ERD draws the schema as an entity-relationship diagram. Every table is
+// an entity listing its columns with PK, FK and UK markers; every foreign
+// key to a table in the schema is a relationship. Cardinality is inferred:
+// the referencing side is "one" when its columns are the primary key or a
+// unique index, "many" otherwise, and the referenced side is optional when
+// any foreign key column is nullable.
+func (s *Schema) ERD(format ERDFormat) string {
+	if format == ERDMermaid {
+		return s.mermaidERD()
+	}
+	return s.dotERD()
+}
+
+// erdRelation is a foreign key between two tables of the schema.
+type erdRelation struct {
+	child, parent *Table
+	fk            *ForeignKey
+	// unique: each parent row has at most one child row. optional: a
+	// child row may have no parent.
+	unique, optional bool
+}
+
+func (s *Schema) relations() []erdRelation {
+	var out []erdRelation
+	for _, t := range s.Tables {
+		for _, fk := range t.ForeignKeys {
+			ref := s.Table(fk.RefTable)
+			if ref == nil {
+				continue
+			}
+			out = append(out, erdRelation{
+				child:    t,
+				parent:   ref,
+				fk:       fk,
+				unique:   t.uniqueOn(fk.Columns),
+				optional: t.anyNullable(fk.Columns),
+			})
+		}
+	}
+	return out
+}
+
+// uniqueOn reports whether the primary key or a unique index covers
+// exactly cols, in any order.
+func (t *Table) uniqueOn(cols []string) bool {
+	same := func(a []string) bool {
+		if len(a) != len(cols) {
+			return false
+		}
+		for _, c := range cols {
+			if !slices.ContainsFunc(a, func(x string) bool { return ident.Equal(x, c, t.dialect) }) {
+				return false
+			}
+		}
+		return true
+	}
+	if len(t.PrimaryKey) > 0 && same(t.PrimaryKey) {
+		return true
+	}
+	for _, idx := range t.Indexes {
+		if idx.Unique() && same(idx.Columns) {
+			return true
+		}
+	}
+	return false
+}
+
+// columnKeys returns the PK, FK and UK markers of column c.
+func (t *Table) columnKeys(c *Column) []string {
+	has := func(names []string) bool {
+		return slices.ContainsFunc(names, func(n string) bool { return ident.Equal(n, c.Name, t.dialect) })
+	}
+	var keys []string
+	if has(t.PrimaryKey) {
+		keys = append(keys, "PK")
+	}
+	if slices.ContainsFunc(t.ForeignKeys, func(fk *ForeignKey) bool { return has(fk.Columns) }) {
+		keys = append(keys, "FK")
+	}
+	if slices.ContainsFunc(t.Indexes, func(idx *Index) bool { return idx.Unique() && has(idx.Columns) }) {
+		keys = append(keys, "UK")
+	}
+	return keys
+}
+
+func (s *Schema) dotERD() string {
+	var b strings.Builder
+	b.WriteString("digraph schema {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=plaintext];\n")
+	b.WriteString("  edge [dir=both];\n")
+	for _, t := range s.Tables {
+		b.WriteString("  " + dotID(t.schemaKey()) + " [label=<")
+		b.WriteString(`<table border="0" cellborder="1" cellspacing="0">`)
+		b.WriteString(`<tr><td bgcolor="lightgrey"><b>` + html.EscapeString(t.schemaKey()) + "</b></td></tr>")
+		for _, c := range t.Columns {
+			cell := html.EscapeString(c.Name + " " + c.typeText())
+			if keys := t.columnKeys(c); len(keys) > 0 {
+				cell += " <i>" + strings.Join(keys, ", ") + "</i>"
+			}
+			b.WriteString(`<tr><td port="` + html.EscapeString(c.Name) + `" align="left">` + cell + "</td></tr>")
+		}
+		b.WriteString("</table>>];\n")
+	}
+	for _, rel := range s.relations() {
+		from, to := dotID(rel.child.schemaKey()), dotID(rel.parent.schemaKey())
+		if len(rel.fk.Columns) == 1 {
+			from += ":" + dotID(rel.fk.Columns[0])
+		}
+		if len(rel.fk.RefColumns) == 1 {
+			to += ":" + dotID(rel.fk.RefColumns[0])
+		}
+		tail, head := "crowodot", "teetee"
+		if rel.unique {
+			tail = "teeodot"
+		}
+		if rel.optional {
+			head = "teeodot"
+		}
+		b.WriteString("  " + from + " -> " + to + " [arrowtail=" + tail + ", arrowhead=" + head + "];\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func (s *Schema) mermaidERD() string {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	for _, t := range s.Tables {
+		b.WriteString("  " + mermaidName(t.schemaKey()) + " {\n")
+		for _, c := range t.Columns {
+			b.WriteString("    " + mermaidName(c.Type) + " " + mermaidName(c.Name))
+			if keys := t.columnKeys(c); len(keys) > 0 {
+				b.WriteString(" " + strings.Join(keys, ", "))
+			}
+			if c.Comment != "" {
+				b.WriteString(` "` + strings.ReplaceAll(c.Comment, `"`, "'") + `"`)
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString("  }\n")
+	}
+	for _, rel := range s.relations() {
+		parent, child := "||", "o{"
+		if rel.optional {
+			parent = "|o"
+		}
+		if rel.unique {
+			child = "o|"
+		}
+		label := rel.fk.Name
+		if label == "" {
+			label = strings.Join(rel.fk.Columns, ", ")
+		}
+		b.WriteString("  " + mermaidName(rel.parent.schemaKey()) + " " + parent + "--" + child + " " +
+			mermaidName(rel.child.schemaKey()) + ` : "` + strings.ReplaceAll(label, `"`, "'") + "\"\n")
+	}
+	return b.String()
+}
+
+// dotID quotes a Graphviz identifier.
+func dotID(s string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+}
+
+// mermaidName replaces characters Mermaid entity and attribute names cannot
+// hold with '_'.
+func mermaidName(s string) string {
+	out := []byte(s)
+	for i, c := range out {
+		if !(c == '_' || c == '-' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			out[i] = '_'
+		}
+	}
+	if len(out) == 0 {
+		return "_"
+	}
+	return string(out)
+}
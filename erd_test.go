@@ -0,0 +1,60 @@
+package sqlparser_test
+
+import (
+	"strings"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+const erdSchema = `
+CREATE TABLE users (id BIGINT PRIMARY KEY, email VARCHAR(255) NOT NULL UNIQUE);
+CREATE TABLE profiles (user_id BIGINT PRIMARY KEY REFERENCES users (id), bio TEXT);
+CREATE TABLE orders (
+	id INT PRIMARY KEY,
+	user_id BIGINT NOT NULL,
+	coupon_id INT,
+	CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id),
+	FOREIGN KEY (coupon_id) REFERENCES coupons (id)
+)`
+
+func TestSchemaERDMermaid(t *testing.T) {
+	s, err := sqlparser.BuildSchema(erdSchema)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	out := s.ERD(sqlparser.ERDMermaid)
+	for _, want := range []string{
+		"erDiagram\n",
+		"  users {\n    BIGINT id PK\n    VARCHAR email UK\n  }\n",
+		"    BIGINT user_id PK, FK\n",
+		`  users ||--o| profiles : "user_id"`,
+		`  users ||--o{ orders : "fk_orders_user"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	// coupons is not part of the schema, so it gets no relationship.
+	if strings.Contains(out, "coupons") {
+		t.Fatalf("relationship to an unknown table:\n%s", out)
+	}
+}
+
+func TestSchemaERDDot(t *testing.T) {
+	s, err := sqlparser.BuildSchema(erdSchema)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	out := s.ERD(sqlparser.ERDDot)
+	for _, want := range []string{
+		"digraph schema {\n",
+		`<td port="email" align="left">email VARCHAR(255) <i>UK</i></td>`,
+		`"profiles":"user_id" -> "users":"id" [arrowtail=teeodot, arrowhead=teetee];`,
+		`"orders":"user_id" -> "users":"id" [arrowtail=crowodot, arrowhead=teetee];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,450 @@
+// Package eval evaluates constant SQL expressions: arithmetic, string
+// concatenation, comparisons, LIKE, IN, BETWEEN, CASE, a handful of string
+// and numeric functions, and boolean logic with SQL's NULL semantics. Fold
+// replaces the constant parts of an expression with their values, so a
+// WHERE clause such as tenant_id = 40 + 2 can be read as tenant_id = 42.
+package eval
+
+import (
+	"cmp"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// Value is the value of a constant expression.
+type Value struct {
+	Kind Kind
+	Num  float64 // numbers, and 0 or 1 for booleans
+	Text string
+}
+
+// Kind is the type of a Value.
+type Kind uint8
+
+const (
+	Null Kind = iota
+	Number
+	Text
+	Bool
+)
+
+var null = Value{}
+
+func boolValue(b bool) Value {
+	if b {
+		return Value{Kind: Bool, Num: 1}
+	}
+	return Value{Kind: Bool}
+}
+
+// Number converts v for arithmetic and numeric comparison. Text converts
+// only when it is entirely a number.
+func (v Value) Number() (float64, bool) {
+	switch v.Kind {
+	case Number, Bool:
+		return v.Num, true
+	case Text:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v.Text), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// String returns v as text, the way a database casts it to a string.
+func (v Value) String() string {
+	switch v.Kind {
+	case Number:
+		return strconv.FormatFloat(v.Num, 'f', -1, 64)
+	case Bool:
+		if v.Num != 0 {
+			return "true"
+		}
+		return "false"
+	}
+	return v.Text
+}
+
+// Truth is the value of a condition in SQL's three-valued logic.
+type Truth uint8
+
+const (
+	Unknown Truth = iota
+	True
+	False
+)
+
+// Truth reports whether v is true, false or unknown (NULL) as a condition.
+func (v Value) Truth() Truth {
+	if v.Kind == Null {
+		return Unknown
+	}
+	if n, ok := v.Number(); ok && n == 0 || v.Kind == Text && !ok {
+		return False
+	}
+	return True
+}
+
+// Not negates t; NOT of unknown is unknown.
+func (t Truth) Not() Truth {
+	switch t {
+	case True:
+		return False
+	case False:
+		return True
+	}
+	return Unknown
+}
+
+func (t Truth) and(u Truth) Truth {
+	switch {
+	case t == False || u == False:
+		return False
+	case t == True && u == True:
+		return True
+	}
+	return Unknown
+}
+
+func (t Truth) or(u Truth) Truth {
+	switch {
+	case t == True || u == True:
+		return True
+	case t == False && u == False:
+		return False
+	}
+	return Unknown
+}
+
+func truthValue(t Truth) Value {
+	if t == Unknown {
+		return null
+	}
+	return boolValue(t == True)
+}
+
+// Eval evaluates e with column references bound to row, whose keys
+// are lower-case column names. It follows SQL's three-valued logic: NULL
+// propagates through operators and comparisons, AND and OR short-circuit
+// on a decisive operand. ok is false when e uses anything the evaluator
+// does not model, such as unbound columns, parameters, subqueries, casts
+// and functions other than a handful of string and numeric ones.
+func Eval(e ast.Expr, row map[string]Value) (v Value, ok bool) {
+	switch x := e.(type) {
+	case *ast.Literal:
+		switch x.Kind {
+		case lexer.INT, lexer.FLOAT:
+			f, err := strconv.ParseFloat(string(x.Raw), 64)
+			return Value{Kind: Number, Num: f}, err == nil
+		case lexer.STRING:
+			// MySQL reads a backslash as an escape and a standard string
+			// keeps it, so a literal that decodes differently under the
+			// two has no value without its dialect.
+			s, ok := lexer.DecodeString(x.Raw, true)
+			if std, _ := lexer.DecodeString(x.Raw, false); !ok || std != s {
+				return Value{}, false
+			}
+			return Value{Kind: Text, Text: s}, true
+		case lexer.TRUE_KW:
+			return boolValue(true), true
+		case lexer.FALSE_KW:
+			return boolValue(false), true
+		}
+	case *ast.NullLit:
+		return null, true
+	case *ast.Ident:
+		v, ok = row[strings.ToLower(x.Unquoted)]
+		return v, ok
+	case *ast.QualifiedIdent:
+		if len(x.Parts) > 0 {
+			v, ok = row[strings.ToLower(x.Parts[len(x.Parts)-1].Unquoted)]
+		}
+		return v, ok
+	case *ast.UnaryExpr:
+		v, ok := Eval(x.Expr, row)
+		if !ok {
+			return v, false
+		}
+		switch x.Op {
+		case lexer.NOT, lexer.BANG:
+			return truthValue(v.Truth().Not()), true
+		case lexer.MINUS, lexer.PLUS:
+			if v.Kind == Null {
+				return v, true
+			}
+			n, ok := v.Number()
+			if x.Op == lexer.MINUS {
+				n = -n
+			}
+			return Value{Kind: Number, Num: n}, ok
+		}
+	case *ast.BinaryExpr:
+		return evalBinary(x, row)
+	case *ast.BetweenExpr:
+		val, ok1 := Eval(x.Expr, row)
+		lo, ok2 := Eval(x.Lo, row)
+		hi, ok3 := Eval(x.Hi, row)
+		if !ok1 || !ok2 || !ok3 {
+			return null, false
+		}
+		t := compareValues(val, lo, lexer.GTE).and(compareValues(val, hi, lexer.LTE))
+		if x.Not {
+			t = t.Not()
+		}
+		return truthValue(t), true
+	case *ast.InExpr:
+		if x.Subq != nil {
+			return null, false
+		}
+		val, ok := Eval(x.Expr, row)
+		if !ok {
+			return null, false
+		}
+		t := False
+		for _, item := range x.List {
+			iv, ok := Eval(item, row)
+			if !ok {
+				return null, false
+			}
+			t = t.or(compareValues(val, iv, lexer.EQ))
+		}
+		if x.Not {
+			t = t.Not()
+		}
+		return truthValue(t), true
+	case *ast.IsNullExpr:
+		val, ok := Eval(x.Expr, row)
+		return boolValue((val.Kind == Null) != x.Not), ok
+	case *ast.LikeExpr:
+		if x.Escape != nil {
+			return null, false
+		}
+		val, ok1 := Eval(x.Expr, row)
+		pat, ok2 := Eval(x.Pattern, row)
+		if !ok1 || !ok2 {
+			return null, false
+		}
+		if val.Kind == Null || pat.Kind == Null {
+			return null, true
+		}
+		return boolValue(matchLike(val.String(), pat.String()) != x.Not), true
+	case *ast.CaseExpr:
+		return evalCase(x, row)
+	case *ast.FuncCall:
+		return evalFunc(x, row)
+	}
+	return null, false
+}
+
+func evalBinary(x *ast.BinaryExpr, row map[string]Value) (Value, bool) {
+	l, okL := Eval(x.Left, row)
+	r, okR := Eval(x.Right, row)
+	switch x.Op {
+	case lexer.AND, lexer.DAMP:
+		// A false operand decides AND even when the other is unknown.
+		switch {
+		case okL && okR:
+			return truthValue(l.Truth().and(r.Truth())), true
+		case okL && l.Truth() == False, okR && r.Truth() == False:
+			return boolValue(false), true
+		}
+		return null, false
+	case lexer.OR:
+		switch {
+		case okL && okR:
+			return truthValue(l.Truth().or(r.Truth())), true
+		case okL && l.Truth() == True, okR && r.Truth() == True:
+			return boolValue(true), true
+		}
+		return null, false
+	}
+	if !okL || !okR {
+		return null, false
+	}
+	switch x.Op {
+	case lexer.EQ, lexer.NEQ, lexer.LT, lexer.LTE, lexer.GT, lexer.GTE:
+		return truthValue(compareValues(l, r, x.Op)), true
+	}
+	if l.Kind == Null || r.Kind == Null {
+		return null, true
+	}
+	if x.Op == lexer.DBAR {
+		return Value{Kind: Text, Text: l.String() + r.String()}, true
+	}
+	a, okA := l.Number()
+	b, okB := r.Number()
+	if !okA || !okB {
+		return null, false
+	}
+	switch x.Op {
+	case lexer.PLUS:
+		return Value{Kind: Number, Num: a + b}, true
+	case lexer.MINUS:
+		return Value{Kind: Number, Num: a - b}, true
+	case lexer.STAR:
+		return Value{Kind: Number, Num: a * b}, true
+	case lexer.SLASH, lexer.PERCENT:
+		if b == 0 {
+			// MySQL yields NULL, PostgreSQL raises an error.
+			return null, false
+		}
+		if x.Op == lexer.PERCENT {
+			return Value{Kind: Number, Num: math.Mod(a, b)}, true
+		}
+		return Value{Kind: Number, Num: a / b}, true
+	}
+	return null, false
+}
+
+// compareValues applies the comparison op. A number compared with a value
+// that converts to one compares numerically; anything else compares as
+// text.
+func compareValues(l, r Value, op lexer.TokenType) Truth {
+	if l.Kind == Null || r.Kind == Null {
+		return Unknown
+	}
+	var c int
+	a, okA := l.Number()
+	b, okB := r.Number()
+	switch {
+	case okA && okB && (l.Kind != Text || r.Kind != Text):
+		c = cmp.Compare(a, b)
+	default:
+		c = strings.Compare(l.String(), r.String())
+	}
+	var t bool
+	switch op {
+	case lexer.EQ:
+		t = c == 0
+	case lexer.NEQ:
+		t = c != 0
+	case lexer.LT:
+		t = c < 0
+	case lexer.LTE:
+		t = c <= 0
+	case lexer.GT:
+		t = c > 0
+	case lexer.GTE:
+		t = c >= 0
+	}
+	if t {
+		return True
+	}
+	return False
+}
+
+func evalCase(x *ast.CaseExpr, row map[string]Value) (Value, bool) {
+	var operand Value
+	if x.Operand != nil {
+		v, ok := Eval(x.Operand, row)
+		if !ok {
+			return null, false
+		}
+		operand = v
+	}
+	for _, w := range x.Whens {
+		cond, ok := Eval(w.Cond, row)
+		if !ok {
+			return null, false
+		}
+		t := cond.Truth()
+		if x.Operand != nil {
+			t = compareValues(operand, cond, lexer.EQ)
+		}
+		if t == True {
+			return Eval(w.Result, row)
+		}
+	}
+	if x.Else == nil {
+		return null, true
+	}
+	return Eval(x.Else, row)
+}
+
+func evalFunc(x *ast.FuncCall, row map[string]Value) (Value, bool) {
+	if x.Name == nil || len(x.Name.Parts) != 1 || x.Star || x.Distinct || x.Over != nil {
+		return null, false
+	}
+	args := make([]Value, len(x.Args))
+	for i, a := range x.Args {
+		v, ok := Eval(a, row)
+		if !ok {
+			return null, false
+		}
+		args[i] = v
+	}
+	name := strings.ToLower(x.Name.Parts[0].Unquoted)
+	if name == "coalesce" || name == "ifnull" {
+		for _, a := range args {
+			if a.Kind != Null {
+				return a, true
+			}
+		}
+		return null, len(args) > 0
+	}
+	if len(args) != 1 {
+		return null, false
+	}
+	a := args[0]
+	if a.Kind == Null {
+		return null, true
+	}
+	switch name {
+	case "length", "char_length", "character_length":
+		return Value{Kind: Number, Num: float64(utf8.RuneCountInString(a.String()))}, true
+	case "lower", "lcase":
+		return Value{Kind: Text, Text: strings.ToLower(a.String())}, true
+	case "upper", "ucase":
+		return Value{Kind: Text, Text: strings.ToUpper(a.String())}, true
+	case "trim":
+		return Value{Kind: Text, Text: strings.Trim(a.String(), " ")}, true
+	case "abs":
+		n, ok := a.Number()
+		return Value{Kind: Number, Num: math.Abs(n)}, ok
+	}
+	return null, false
+}
+
+// matchLike reports whether s matches the LIKE pattern, with % for any
+// run of characters, _ for one character and backslash escaping either.
+// Matching is case-sensitive, as in PostgreSQL.
+func matchLike(s, pattern string) bool {
+	for len(pattern) > 0 {
+		r, n := utf8.DecodeRuneInString(pattern)
+		pattern = pattern[n:]
+		switch r {
+		case '%':
+			for i := 0; i <= len(s); i++ {
+				if i > 0 && !utf8.RuneStart(s[i-1]) {
+					continue
+				}
+				if matchLike(s[i:], pattern) {
+					return true
+				}
+			}
+			return false
+		case '_':
+			if s == "" {
+				return false
+			}
+			_, m := utf8.DecodeRuneInString(s)
+			s = s[m:]
+			continue
+		case '\\':
+			if pattern != "" {
+				r, n = utf8.DecodeRuneInString(pattern)
+				pattern = pattern[n:]
+			}
+		}
+		c, m := utf8.DecodeRuneInString(s)
+		if s == "" || c != r {
+			return false
+		}
+		s = s[m:]
+	}
+	return s == ""
+}
@@ -0,0 +1,398 @@
+package eval
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// maxExact is the largest integer a float64 holds exactly.
+const maxExact = 1 << 53
+
+// Fold returns e with every constant subexpression replaced by its value,
+// so WHERE tenant_id = 40 + 2 AND 1 = 1 becomes WHERE tenant_id = 42. It
+// also drops the decisive or neutral side of AND and OR: FALSE AND x is
+// FALSE and TRUE AND x is x, and the CASE branches that are never taken:
+// CASE WHEN FALSE THEN a WHEN x THEN b END is CASE WHEN x THEN b END.
+//
+// A node is folded only when its operands fold to literals and its value
+// is one that every dialect reads the same way: integers within 2^53,
+// booleans, NULL and strings without backslashes. Strings are compared
+// only when they are the same, since collations differ on the rest.
+// Arithmetic on decimal literals and divisions with a remainder are kept,
+// since databases differ on their precision and on integer division.
+// Subqueries are not entered. e itself is not modified; nodes on the path to a folded one
+// are copied.
+func Fold(e ast.Expr) ast.Expr {
+	switch x := e.(type) {
+	case *ast.BinaryExpr:
+		l, r := Fold(x.Left), Fold(x.Right)
+		switch x.Op {
+		case lexer.AND, lexer.DAMP, lexer.OR:
+			if folded, ok := foldLogic(x, l, r); ok {
+				return folded
+			}
+		}
+		if l != x.Left || r != x.Right {
+			cp := *x
+			cp.Left, cp.Right = l, r
+			x = &cp
+		}
+		switch x.Op {
+		case lexer.PLUS, lexer.MINUS, lexer.STAR, lexer.SLASH, lexer.PERCENT:
+			// '1' + '2' is 3 in MySQL and an error in PostgreSQL.
+			if !ofKind(lexer.INT, l, r) {
+				return x
+			}
+		case lexer.DBAR:
+			// || is OR in MySQL, so only strings are joined.
+			if !ofKind(lexer.STRING, l, r) {
+				return x
+			}
+		case lexer.EQ, lexer.NEQ, lexer.LT, lexer.LTE, lexer.GT, lexer.GTE:
+			if !sameText(l, r) {
+				return x
+			}
+		}
+		return constant(x, l, r)
+	case *ast.UnaryExpr:
+		inner := Fold(x.Expr)
+		if inner == x.Expr {
+			if _, lit := inner.(*ast.Literal); lit && (x.Op == lexer.MINUS || x.Op == lexer.PLUS) {
+				// A signed number is already as simple as it gets.
+				return x
+			}
+		} else {
+			cp := *x
+			cp.Expr = inner
+			x = &cp
+		}
+		return constant(x, inner)
+	case *ast.FuncCall:
+		args, changed := foldList(x.Args)
+		if changed {
+			cp := *x
+			cp.Args = args
+			x = &cp
+		}
+		return constant(x, args...)
+	case *ast.CaseExpr:
+		cp := *x
+		changed := false
+		if x.Operand != nil {
+			cp.Operand = Fold(x.Operand)
+			changed = cp.Operand != x.Operand
+		}
+		cp.Whens = make([]ast.WhenClause, 0, len(x.Whens))
+		for _, w := range x.Whens {
+			w := ast.WhenClause{Cond: Fold(w.Cond), Result: Fold(w.Result)}
+			always, never := decideWhen(cp.Operand, w.Cond)
+			if never {
+				continue
+			}
+			if always {
+				// The branches after it and ELSE are never reached.
+				if len(cp.Whens) == 0 {
+					return w.Result
+				}
+				cp.Else = w.Result
+				break
+			}
+			cp.Whens = append(cp.Whens, w)
+		}
+		if x.Else != nil && cp.Else == x.Else {
+			cp.Else = Fold(x.Else)
+		}
+		if len(cp.Whens) == 0 {
+			if cp.Else == nil {
+				return &ast.NullLit{TokPos: x.TokPos}
+			}
+			return cp.Else
+		}
+		changed = changed || len(cp.Whens) != len(x.Whens) || cp.Else != x.Else
+		// The conditions, or the operand and the values it is compared
+		// with, must agree on their kind, and so must the results.
+		var conds, results []ast.Expr
+		if cp.Operand != nil {
+			conds = append(conds, cp.Operand)
+		}
+		for i, w := range cp.Whens {
+			changed = changed || w != x.Whens[i]
+			conds = append(conds, w.Cond)
+			results = append(results, w.Result)
+		}
+		if cp.Else != nil {
+			results = append(results, cp.Else)
+		}
+		operands := append(conds, results...)
+		if changed {
+			x = &cp
+		}
+		if !allConstant(operands) || !sameKind(conds...) || !sameKind(results...) || cp.Operand != nil && !sameText(conds...) {
+			return x
+		}
+		return constant(x)
+	case *ast.BetweenExpr:
+		v, lo, hi := Fold(x.Expr), Fold(x.Lo), Fold(x.Hi)
+		if v != x.Expr || lo != x.Lo || hi != x.Hi {
+			cp := *x
+			cp.Expr, cp.Lo, cp.Hi = v, lo, hi
+			x = &cp
+		}
+		if !sameText(v, lo, hi) {
+			return x
+		}
+		return constant(x, v, lo, hi)
+	case *ast.InExpr:
+		if x.Subq != nil {
+			return x
+		}
+		v := Fold(x.Expr)
+		list, changed := foldList(x.List)
+		if changed || v != x.Expr {
+			cp := *x
+			cp.Expr, cp.List = v, list
+			x = &cp
+		}
+		operands := append([]ast.Expr{v}, list...)
+		if !sameText(operands...) {
+			return x
+		}
+		return constant(x, operands...)
+	case *ast.LikeExpr:
+		v, pat := Fold(x.Expr), Fold(x.Pattern)
+		if v != x.Expr || pat != x.Pattern {
+			cp := *x
+			cp.Expr, cp.Pattern = v, pat
+			x = &cp
+		}
+		// Whether a string matches depends on the collation as much as on
+		// the pattern: 'abc' LIKE 'ABC' is true in MySQL.
+		if !ofKind(0, v) || !ofKind(0, pat) {
+			return x
+		}
+		return constant(x, v, pat)
+	case *ast.IsNullExpr:
+		v := Fold(x.Expr)
+		if v != x.Expr {
+			cp := *x
+			cp.Expr = v
+			x = &cp
+		}
+		return constant(x, v)
+	case *ast.CastExpr:
+		// The cast itself is left to the database, which knows its types.
+		if v := Fold(x.Expr); v != x.Expr {
+			cp := *x
+			cp.Expr = v
+			return &cp
+		}
+	}
+	return e
+}
+
+func foldList(list []ast.Expr) ([]ast.Expr, bool) {
+	var out []ast.Expr
+	for i, e := range list {
+		f := Fold(e)
+		if f != e && out == nil {
+			out = append(make([]ast.Expr, 0, len(list)), list[:i]...)
+		}
+		if out != nil {
+			out = append(out, f)
+		}
+	}
+	if out == nil {
+		return list, false
+	}
+	return out, true
+}
+
+// foldLogic simplifies AND and OR when one folded operand is a constant
+// that decides the result or leaves it to the other.
+func foldLogic(x *ast.BinaryExpr, l, r ast.Expr) (ast.Expr, bool) {
+	and := x.Op != lexer.OR
+	for _, side := range [2][2]ast.Expr{{l, r}, {r, l}} {
+		// Only TRUE and FALSE: MySQL's 1 AND x is 1 or 0, not x.
+		if k, _ := literalKind(side[0]); k != lexer.TRUE_KW {
+			continue
+		}
+		v, ok := Eval(side[0], nil)
+		if !ok {
+			continue
+		}
+		switch t := v.Truth(); {
+		case and && t == False, !and && t == True:
+			return literal(boolValue(t == True), x.TokPos), true
+		case and && t == True, !and && t == False:
+			if isConstant(side[1]) {
+				// Both sides are constant: evaluate as usual.
+				return nil, false
+			}
+			return side[1], true
+		}
+	}
+	return nil, false
+}
+
+// decideWhen reports whether a WHEN branch with cond is always or never
+// taken, comparing cond with operand in a simple CASE. As in foldLogic,
+// only TRUE, FALSE and NULL decide a searched CASE.
+func decideWhen(operand, cond ast.Expr) (always, never bool) {
+	if operand != nil {
+		if !isConstant(operand) || !isConstant(cond) || !sameKind(operand, cond) || !sameText(operand, cond) {
+			return false, false
+		}
+		o, ok1 := Eval(operand, nil)
+		c, ok2 := Eval(cond, nil)
+		if !ok1 || !ok2 {
+			return false, false
+		}
+		t := compareValues(o, c, lexer.EQ)
+		return t == True, t != True
+	}
+	if _, null := cond.(*ast.NullLit); null {
+		return false, true
+	}
+	if k, _ := literalKind(cond); k != lexer.TRUE_KW {
+		return false, false
+	}
+	v, ok := Eval(cond, nil)
+	if !ok {
+		return false, false
+	}
+	return v.Truth() == True, v.Truth() != True
+}
+
+func isConstant(e ast.Expr) bool {
+	switch e.(type) {
+	case *ast.Literal, *ast.NullLit:
+		return true
+	}
+	return false
+}
+
+// constant replaces e by its value when all of operands are constants of
+// one kind and the value can be written as a literal. Operands that mix
+// numbers, strings and booleans are left alone: '10' = 10 is true in MySQL
+// and PostgreSQL but false in SQLite, and TRUE = 1 is an error in
+// PostgreSQL.
+func constant(e ast.Expr, operands ...ast.Expr) ast.Expr {
+	if !allConstant(operands) || !sameKind(operands...) {
+		return e
+	}
+	v, ok := Eval(e, nil)
+	if !ok {
+		return e
+	}
+	if lit := literal(v, e.Pos()); lit != nil {
+		return lit
+	}
+	return e
+}
+
+func allConstant(list []ast.Expr) bool {
+	for _, e := range list {
+		if !isConstant(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// literalKind classifies a constant for sameKind: booleans together, and
+// NULL as the zero kind, which goes with anything. ok is false for
+// decimals, integers beyond 2^53 and typed literals such as hex strings,
+// which are never folded.
+func literalKind(e ast.Expr) (kind lexer.TokenType, ok bool) {
+	lit, isLit := e.(*ast.Literal)
+	if !isLit {
+		return 0, true
+	}
+	switch lit.Kind {
+	case lexer.INT:
+		// Larger integers would be compared as the nearest float64, which
+		// makes 9007199254740993 equal 9007199254740992.
+		n, err := strconv.ParseUint(string(lit.Raw), 10, 64)
+		return lit.Kind, err == nil && n <= maxExact
+	case lexer.STRING:
+		return lit.Kind, true
+	case lexer.TRUE_KW, lexer.FALSE_KW:
+		return lexer.TRUE_KW, true
+	}
+	return 0, false
+}
+
+func sameKind(list ...ast.Expr) bool {
+	var kind lexer.TokenType
+	for _, e := range list {
+		k, ok := literalKind(e)
+		switch {
+		case !ok, k != 0 && kind != 0 && k != kind:
+			return false
+		case k != 0:
+			kind = k
+		}
+	}
+	return true
+}
+
+// sameText reports whether the strings among list, if any, are all the
+// same. Only then do comparisons of them fold: MySQL's default collations
+// ignore case and trailing spaces, so 'a' = 'A' and 'a' = 'a ' are true
+// there and false elsewhere.
+func sameText(list ...ast.Expr) bool {
+	var text []byte
+	for _, e := range list {
+		lit, ok := e.(*ast.Literal)
+		if !ok || lit.Kind != lexer.STRING {
+			continue
+		}
+		if text != nil && !bytes.Equal(lit.Raw, text) {
+			return false
+		}
+		text = lit.Raw
+	}
+	return true
+}
+
+// ofKind reports whether the constants of list are all NULL or of kind.
+func ofKind(kind lexer.TokenType, list ...ast.Expr) bool {
+	for _, e := range list {
+		if k, ok := literalKind(e); !ok || k != 0 && k != kind {
+			return false
+		}
+	}
+	return true
+}
+
+// literal writes v as a literal at pos, or returns nil when v has no
+// literal that reads the same in every dialect.
+func literal(v Value, pos int32) ast.Expr {
+	switch v.Kind {
+	case Null:
+		return &ast.NullLit{TokPos: pos}
+	case Bool:
+		if v.Num != 0 {
+			return &ast.Literal{Raw: []byte("TRUE"), Kind: lexer.TRUE_KW, TokPos: pos}
+		}
+		return &ast.Literal{Raw: []byte("FALSE"), Kind: lexer.FALSE_KW, TokPos: pos}
+	case Number:
+		// 2^53 itself may be a rounded 2^53 + 1.
+		if v.Num != math.Trunc(v.Num) || math.Abs(v.Num) >= maxExact {
+			return nil
+		}
+		return &ast.Literal{Raw: strconv.AppendInt(nil, int64(v.Num), 10), Kind: lexer.INT, TokPos: pos}
+	case Text:
+		if strings.ContainsRune(v.Text, '\\') {
+			// MySQL would read the backslash as an escape.
+			return nil
+		}
+		return &ast.Literal{Raw: []byte("'" + strings.ReplaceAll(v.Text, "'", "''") + "'"), Kind: lexer.STRING, TokPos: pos}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package sqlparser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// ExplainOptions selects the plan ExplainFor asks for.
+type ExplainOptions struct {
+	// Analyze executes the statement and reports actual row counts and
+	// timings: EXPLAIN ANALYZE. Take care with statements that write.
+	Analyze bool
+}
+
+var (
+	explainJSON    = []byte("JSON")
+	explainBuffers = ast.TableOption{Key: []byte("BUFFERS")}
+)
+
+// ExplainFor renders stmt wrapped in the EXPLAIN form that returns a
+// machine-readable plan on target: EXPLAIN FORMAT=JSON in MySQL,
+// EXPLAIN (FORMAT JSON) in PostgreSQL, with ANALYZE and BUFFERS when
+// opts.Analyze is set, and EXPLAIN QUERY PLAN in SQLite, which has no
+// ANALYZE and reports it with a warning. MySQL needs version 8.3 or later
+// for EXPLAIN ANALYZE FORMAT=JSON.
+//
+// An EXPLAIN statement is re-wrapped with the new options; DESCRIBE of a
+// table has no plan and is an error.
+func ExplainFor(stmt Statement, target Dialect, opts ExplainOptions) (string, []ConversionWarning, error) {
+	if e, ok := stmt.(*ast.ExplainStmt); ok {
+		if e.Stmt == nil {
+			return "", nil, errors.New("sqlparser: DESCRIBE of a table has no query plan")
+		}
+		stmt = e.Stmt
+	}
+	x := &ast.ExplainStmt{Stmt: stmt, Analyze: opts.Analyze, TokPos: stmt.Pos()}
+	switch target {
+	case DialectSQLite:
+		x.QueryPlan = true
+	case DialectPostgres:
+		x.Format = explainJSON
+		if opts.Analyze {
+			x.Options = []ast.TableOption{explainBuffers}
+		}
+	default:
+		x.Format = explainJSON
+	}
+	r := newDialectRenderer(ConvertOptions{Target: target})
+	out, err := r.renderStatements([]Statement{x})
+	return out, r.warnings, err
+}
+
+// renderExplain renders EXPLAIN in the target's own syntax: PostgreSQL's
+// option list, MySQL's ANALYZE and FORMAT=name, and SQLite's EXPLAIN QUERY
+// PLAN. Options the target lacks are dropped with a warning.
+func (r *dialectRenderer) renderExplain(s *ast.ExplainStmt) (string, error) {
+	if s.Table != nil {
+		return r.renderDescribe(s), nil
+	}
+	inner, err := r.renderStatement(s.Stmt)
+	if err != nil {
+		return "", err
+	}
+	format := strings.ToUpper(string(s.Format))
+	var b strings.Builder
+	b.WriteString("EXPLAIN ")
+	switch r.target {
+	case DialectPostgres:
+		var opts []string
+		if s.Analyze {
+			opts = append(opts, "ANALYZE")
+		}
+		switch format {
+		case "", "TEXT", "TREE", "TRADITIONAL":
+			// Text output is the default, and the nearest to MySQL's.
+		case "JSON", "XML", "YAML":
+			opts = append(opts, "FORMAT "+format)
+		default:
+			r.dropExplainOption(s, "FORMAT "+format)
+		}
+		for _, o := range s.Options {
+			opts = append(opts, explainOption(o))
+		}
+		if len(opts) > 0 {
+			b.WriteString("(" + strings.Join(opts, ", ") + ") ")
+		}
+	case DialectMySQL:
+		if s.Analyze {
+			b.WriteString("ANALYZE ")
+		}
+		switch format {
+		case "", "TEXT":
+		case "JSON", "TREE", "TRADITIONAL":
+			b.WriteString("FORMAT=" + format + " ")
+		default:
+			r.dropExplainOption(s, "FORMAT "+format)
+		}
+		for _, o := range s.Options {
+			r.dropExplainOption(s, explainOption(o))
+		}
+	case DialectSQLite:
+		if s.Analyze {
+			r.dropExplainOption(s, "ANALYZE")
+		}
+		if format != "" {
+			r.dropExplainOption(s, "FORMAT "+format)
+		}
+		for _, o := range s.Options {
+			r.dropExplainOption(s, explainOption(o))
+		}
+		// Plain EXPLAIN lists SQLite's bytecode; a statement asking for
+		// plan options wants the plan.
+		if s.QueryPlan || s.Analyze || format != "" || len(s.Options) > 0 {
+			b.WriteString("QUERY PLAN ")
+		}
+	default:
+		if s.QueryPlan {
+			b.WriteString("QUERY PLAN ")
+		}
+		if len(s.Options) > 0 {
+			opts := make([]string, 0, len(s.Options)+2)
+			if s.Analyze {
+				opts = append(opts, "ANALYZE")
+			}
+			if format != "" {
+				opts = append(opts, "FORMAT "+format)
+			}
+			for _, o := range s.Options {
+				opts = append(opts, explainOption(o))
+			}
+			b.WriteString("(" + strings.Join(opts, ", ") + ") ")
+			break
+		}
+		if s.Analyze {
+			b.WriteString("ANALYZE ")
+		}
+		if format != "" {
+			b.WriteString("FORMAT=" + format + " ")
+		}
+	}
+	b.WriteString(inner)
+	return b.String(), nil
+}
+
+func (r *dialectRenderer) dropExplainOption(s *ast.ExplainStmt, opt string) {
+	r.warn(WarnExplainOptionDropped, s.TokPos, "EXPLAIN option %s is not supported by %s and was dropped", opt, r.target)
+}
+
+func explainOption(o ast.TableOption) string {
+	if o.Value == nil {
+		return strings.ToUpper(string(o.Key))
+	}
+	return strings.ToUpper(string(o.Key)) + " " + string(o.Value)
+}
+
+// renderDescribe renders MySQL's DESCRIBE table [column]. PostgreSQL reads
+// the columns from information_schema and SQLite from table_info; both
+// return different columns than MySQL does.
+func (r *dialectRenderer) renderDescribe(s *ast.ExplainStmt) string {
+	n := len(s.Table.Parts)
+	table := quoteSQLString(s.Table.Parts[n-1].Unquoted)
+	switch r.target {
+	case DialectPostgres:
+		r.warn(WarnDescribeRewritten, s.TokPos, "DESCRIBE %s became a query on information_schema.columns", qualifiedName(s.Table))
+		conds := []string{"table_schema = " + catalogSchema(s.Table, nil), "table_name = " + table}
+		if s.Column != nil {
+			conds = append(conds, "column_name = "+quoteSQLString(s.Column.Unquoted))
+		}
+		return catalogQuery(pgColumns, "information_schema.columns", conds, "ordinal_position")
+	case DialectSQLite:
+		r.warn(WarnDescribeRewritten, s.TokPos, "DESCRIBE %s became a table_info pragma", qualifiedName(s.Table))
+		if s.Column == nil {
+			out := "PRAGMA "
+			if n > 1 {
+				out += r.renderIdent(s.Table.Parts[n-2]) + "."
+			}
+			return out + "table_info(" + table + ")"
+		}
+		args := table
+		if n > 1 {
+			args += ", " + quoteSQLString(s.Table.Parts[n-2].Unquoted)
+		}
+		return "SELECT * FROM pragma_table_info(" + args + ") WHERE name = " + quoteSQLString(s.Column.Unquoted)
+	}
+	out := "DESCRIBE " + r.renderQualifiedIdent(s.Table)
+	if s.Column != nil {
+		out += " " + r.renderIdent(s.Column)
+	}
+	return out
+}
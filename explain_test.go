@@ -0,0 +1,43 @@
+package sqlparser_test
+
+import (
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestExplainFor(t *testing.T) {
+	tests := []struct {
+		sql     string
+		target  sqlparser.Dialect
+		analyze bool
+		want    string
+		warns   int
+	}{
+		{"SELECT * FROM users WHERE id = ?", sqlparser.DialectMySQL, false, "EXPLAIN FORMAT=JSON SELECT * FROM `users` WHERE (`id` = ?)", 0},
+		{"SELECT * FROM users WHERE id = ?", sqlparser.DialectPostgres, true,
+			`EXPLAIN (ANALYZE, FORMAT JSON, BUFFERS) SELECT * FROM "users" WHERE ("id" = $1)`, 0},
+		{"SELECT 1", sqlparser.DialectPostgres, false, "EXPLAIN (FORMAT JSON) SELECT 1", 0},
+		{"DELETE FROM users", sqlparser.DialectSQLite, false, `EXPLAIN QUERY PLAN DELETE FROM "users"`, 0},
+		{"SELECT 1", sqlparser.DialectSQLite, true, "EXPLAIN QUERY PLAN SELECT 1", 1},
+		{"EXPLAIN ANALYZE SELECT 1", sqlparser.DialectMySQL, false, "EXPLAIN FORMAT=JSON SELECT 1", 0},
+	}
+	for _, tt := range tests {
+		stmt, err := sqlparser.ParseStatement(tt.sql)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.sql, err)
+		}
+		out, warnings, err := sqlparser.ExplainFor(stmt, tt.target, sqlparser.ExplainOptions{Analyze: tt.analyze})
+		if err != nil || out != tt.want || len(warnings) != tt.warns {
+			t.Errorf("%s -> %s:\n got %s %v %v\nwant %s", tt.sql, tt.target, out, warnings, err, tt.want)
+		}
+	}
+
+	stmt, err := sqlparser.ParseStatement("DESCRIBE users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := sqlparser.ExplainFor(stmt, sqlparser.DialectMySQL, sqlparser.ExplainOptions{}); err == nil {
+		t.Error("expected an error for DESCRIBE")
+	}
+}
@@ -0,0 +1,719 @@
+package sqlparser
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// FixtureOptions controls GenerateFixtures.
+type FixtureOptions struct {
+	// Rows is the number of rows generated per table, 10 when zero.
+	// RowsPerTable overrides it by table name.
+	Rows         int
+	RowsPerTable map[string]int
+	// Seed selects the pseudo-random sequence. The same schema, options
+	// and seed always produce the same statements.
+	Seed uint64
+	// Target selects identifier quoting; MySQL when empty.
+	Target Dialect
+}
+
+// GenerateFixtures produces one multi-row INSERT per table with
+// deterministic pseudo-random data that satisfies the schema: NOT NULL
+// columns get values, ENUM and SET columns get members, primary keys and
+// unique indexes get distinct values, and foreign keys reference rows
+// generated for the parent table, which is inserted first. CHECK
+// constraints of the forms col op constant (with <, <=, >, >=, =, <>),
+// col BETWEEN a AND b and col IN (...), alone or joined by AND, narrow the
+// generated values; other CHECK expressions are not evaluated.
+//
+// Generated columns are left out; AUTO_INCREMENT and SERIAL columns are
+// given explicit sequential values so foreign keys can refer to them.
+// Foreign keys that form a cycle are broken by setting nullable foreign
+// key columns to NULL; a cycle of NOT NULL foreign keys is an error.
+func (s *Schema) GenerateFixtures(opts FixtureOptions) ([]string, error) {
+	if opts.Target == "" {
+		opts.Target = DialectMySQL
+	}
+	g := &fixtureGen{
+		schema: s,
+		opts:   opts,
+		rng:    fixtureRand{state: opts.Seed},
+		rows:   map[*Table][][]string{},
+		r:      newDialectRenderer(ConvertOptions{Target: opts.Target}),
+	}
+	order, nulled, err := s.insertOrder()
+	if err != nil {
+		return nil, err
+	}
+	g.nulled = nulled
+	out := make([]string, 0, len(order))
+	for _, t := range order {
+		stmt, err := g.table(t)
+		if err != nil {
+			return nil, err
+		}
+		if stmt != "" {
+			out = append(out, stmt)
+		}
+	}
+	return out, nil
+}
+
+// insertOrder sorts the tables so that every table comes after the tables
+// its foreign keys reference. Foreign keys that must be left NULL to break
+// a cycle are returned in nulled.
+func (s *Schema) insertOrder() ([]*Table, map[*ForeignKey]bool, error) {
+	nulled := map[*ForeignKey]bool{}
+	done := map[*Table]bool{}
+	var order []*Table
+	for len(order) < len(s.Tables) {
+		progress := false
+		for _, t := range s.Tables {
+			if done[t] {
+				continue
+			}
+			ready := true
+			for _, fk := range t.ForeignKeys {
+				ref := s.Table(fk.RefTable)
+				if ref != nil && ref != t && !done[ref] && !nulled[fk] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				done[t] = true
+				order = append(order, t)
+				progress = true
+			}
+		}
+		if progress {
+			continue
+		}
+		// Every remaining table waits on another: break the cycle at the
+		// first foreign key whose columns may be NULL.
+		broken := false
+		for _, t := range s.Tables {
+			if done[t] {
+				continue
+			}
+			for _, fk := range t.ForeignKeys {
+				if ref := s.Table(fk.RefTable); ref != nil && ref != t && !done[ref] && !nulled[fk] && t.allNullable(fk.Columns) {
+					nulled[fk] = true
+					broken = true
+					break
+				}
+			}
+			if broken {
+				break
+			}
+		}
+		if !broken {
+			var names []string
+			for _, t := range s.Tables {
+				if !done[t] {
+					names = append(names, t.schemaKey())
+				}
+			}
+			return nil, nil, fmt.Errorf("foreign keys form a NOT NULL cycle between tables %s", strings.Join(names, ", "))
+		}
+	}
+	return order, nulled, nil
+}
+
+func (t *Table) allNullable(cols []string) bool {
+	for _, name := range cols {
+		if c := t.Column(name); c == nil || !c.Nullable {
+			return false
+		}
+	}
+	return true
+}
+
+type fixtureGen struct {
+	schema *Schema
+	opts   FixtureOptions
+	rng    fixtureRand
+	nulled map[*ForeignKey]bool
+	// rows holds the generated SQL literals of each table, by column
+	// position, for foreign keys to pick from.
+	rows map[*Table][][]string
+	r    *dialectRenderer
+}
+
+func (g *fixtureGen) rowCount(t *Table) int {
+	if n, ok := g.opts.RowsPerTable[t.Name]; ok {
+		return n
+	}
+	if g.opts.Rows > 0 {
+		return g.opts.Rows
+	}
+	return 10
+}
+
+func (g *fixtureGen) table(t *Table) (string, error) {
+	n := g.rowCount(t)
+	rows := make([][]string, n)
+	for i := range rows {
+		rows[i] = make([]string, len(t.Columns))
+	}
+	g.rows[t] = rows
+	if n == 0 {
+		return "", nil
+	}
+	fkCol := map[string]bool{}
+	for _, fk := range t.ForeignKeys {
+		if g.schema.Table(fk.RefTable) != nil {
+			for _, c := range fk.Columns {
+				fkCol[strings.ToLower(c)] = true
+			}
+		}
+	}
+	uniqueCols := map[string]bool{}
+	for _, key := range t.uniqueKeys() {
+		if len(key) == 1 {
+			uniqueCols[strings.ToLower(key[0])] = true
+		}
+	}
+	for ci, c := range t.Columns {
+		if c.Generated || fkCol[strings.ToLower(c.Name)] {
+			continue
+		}
+		dom := columnDomain(c, t.Checks)
+		for i := range rows {
+			v, err := g.value(c, dom, i, uniqueCols[strings.ToLower(c.Name)])
+			if err != nil {
+				return "", fmt.Errorf("%s.%s: %w", t.schemaKey(), c.Name, err)
+			}
+			rows[i][ci] = v
+		}
+	}
+	for _, fk := range t.ForeignKeys {
+		if err := g.foreignKey(t, fk, rows); err != nil {
+			return "", err
+		}
+	}
+	if err := g.checkUnique(t, rows); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("INSERT INTO ")
+	if t.Namespace != "" {
+		b.WriteString(g.r.renderIdent(&ast.Ident{Unquoted: t.Namespace}) + ".")
+	}
+	b.WriteString(g.r.renderIdent(&ast.Ident{Unquoted: t.Name}) + " (")
+	first := true
+	for _, c := range t.Columns {
+		if c.Generated {
+			continue
+		}
+		if !first {
+			b.WriteString(", ")
+		}
+		first = false
+		b.WriteString(g.r.renderIdent(&ast.Ident{Unquoted: c.Name}))
+	}
+	b.WriteString(") VALUES ")
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		first := true
+		for ci, c := range t.Columns {
+			if c.Generated {
+				continue
+			}
+			if !first {
+				b.WriteString(", ")
+			}
+			first = false
+			b.WriteString(row[ci])
+		}
+		b.WriteByte(')')
+	}
+	return b.String(), nil
+}
+
+// uniqueKeys returns the primary key and the column lists of the unique
+// indexes.
+func (t *Table) uniqueKeys() [][]string {
+	var keys [][]string
+	if len(t.PrimaryKey) > 0 {
+		keys = append(keys, t.PrimaryKey)
+	}
+	for _, idx := range t.Indexes {
+		if idx.Unique() {
+			keys = append(keys, idx.Columns)
+		}
+	}
+	return keys
+}
+
+// foreignKey fills the columns of fk with the values of a parent row. A
+// self-reference picks among the rows up to and including the current one.
+func (g *fixtureGen) foreignKey(t *Table, fk *ForeignKey, rows [][]string) error {
+	parent := g.schema.Table(fk.RefTable)
+	if parent == nil {
+		return nil
+	}
+	cols := make([]int, len(fk.Columns))
+	for i, name := range fk.Columns {
+		cols[i] = t.columnIndex(name)
+	}
+	refCols := fk.RefColumns
+	if len(refCols) == 0 {
+		refCols = parent.PrimaryKey
+	}
+	refs := make([]int, len(refCols))
+	for i, name := range refCols {
+		refs[i] = parent.columnIndex(name)
+	}
+	nullable := t.allNullable(fk.Columns)
+	unique := t.uniqueOn(fk.Columns)
+	parentRows := g.rows[parent]
+	for i, row := range rows {
+		candidates := len(parentRows)
+		if parent == t {
+			candidates = i + 1
+		}
+		if g.nulled[fk] || candidates == 0 || len(refs) != len(cols) {
+			if !nullable {
+				return fmt.Errorf("%s: foreign key (%s) is NOT NULL but %s has no rows to reference", t.schemaKey(), strings.Join(fk.Columns, ", "), parent.schemaKey())
+			}
+			for _, ci := range cols {
+				if ci >= 0 {
+					row[ci] = "NULL"
+				}
+			}
+			continue
+		}
+		k := g.rng.intn(candidates)
+		if unique {
+			// One child row per parent row keeps the key distinct.
+			if i >= candidates {
+				return fmt.Errorf("%s: unique foreign key (%s) needs at least %d rows in %s", t.schemaKey(), strings.Join(fk.Columns, ", "), i+1, parent.schemaKey())
+			}
+			k = i
+		}
+		pick := parentRows[k]
+		for k, ci := range cols {
+			if ci < 0 || refs[k] < 0 {
+				continue
+			}
+			if parent == t && pick[refs[k]] == "" {
+				return fmt.Errorf("%s: foreign key (%s) references its own columns", t.schemaKey(), strings.Join(fk.Columns, ", "))
+			}
+			row[ci] = pick[refs[k]]
+		}
+	}
+	return nil
+}
+
+// checkUnique verifies the multi-column unique keys, which value does not
+// enforce, and reports the first duplicate.
+func (g *fixtureGen) checkUnique(t *Table, rows [][]string) error {
+	for _, key := range t.uniqueKeys() {
+		if len(key) < 2 {
+			continue
+		}
+		seen := map[string]bool{}
+		for _, row := range rows {
+			var b strings.Builder
+			hasNull := false
+			for _, name := range key {
+				v := row[t.columnIndex(name)]
+				hasNull = hasNull || v == "NULL"
+				b.WriteString(v + "\x00")
+			}
+			if hasNull {
+				continue
+			}
+			if seen[b.String()] {
+				return fmt.Errorf("%s: could not generate distinct values for unique key (%s); lower the row count", t.schemaKey(), strings.Join(key, ", "))
+			}
+			seen[b.String()] = true
+		}
+	}
+	return nil
+}
+
+// fixtureDomain is the set of values a column may take under its CHECK
+// constraints.
+type fixtureDomain struct {
+	lo, hi  float64
+	loOpen  bool
+	hiOpen  bool
+	in      []string // SQL literals
+	not     []string // SQL literals excluded by <>
+	bounded bool
+}
+
+// columnDomain derives the domain of c from the CHECK expressions that
+// constrain it alone.
+func columnDomain(c *Column, checks []ast.Expr) fixtureDomain {
+	d := fixtureDomain{lo: math.Inf(-1), hi: math.Inf(1)}
+	for _, e := range checks {
+		d.narrow(c.Name, e)
+	}
+	return d
+}
+
+func (d *fixtureDomain) narrow(col string, e ast.Expr) {
+	switch x := e.(type) {
+	case *ast.BinaryExpr:
+		if x.Op == lexer.AND {
+			d.narrow(col, x.Left)
+			d.narrow(col, x.Right)
+			return
+		}
+		op, lit := x.Op, x.Right
+		if !isColumn(x.Left, col) {
+			if !isColumn(x.Right, col) {
+				return
+			}
+			lit = x.Left
+			switch op { // constant op col: mirror the comparison
+			case lexer.LT:
+				op = lexer.GT
+			case lexer.LTE:
+				op = lexer.GTE
+			case lexer.GT:
+				op = lexer.LT
+			case lexer.GTE:
+				op = lexer.LTE
+			}
+		}
+		raw, num, ok := constant(lit)
+		if !ok {
+			return
+		}
+		switch op {
+		case lexer.EQ:
+			d.in = []string{raw}
+		case lexer.NEQ:
+			d.not = append(d.not, raw)
+		case lexer.LT, lexer.LTE:
+			if num < d.hi || num == d.hi && op == lexer.LT {
+				d.hi, d.hiOpen, d.bounded = num, op == lexer.LT, true
+			}
+		case lexer.GT, lexer.GTE:
+			if num > d.lo || num == d.lo && op == lexer.GT {
+				d.lo, d.loOpen, d.bounded = num, op == lexer.GT, true
+			}
+		}
+	case *ast.BetweenExpr:
+		if x.Not || !isColumn(x.Expr, col) {
+			return
+		}
+		_, lo, okLo := constant(x.Lo)
+		_, hi, okHi := constant(x.Hi)
+		if okLo && okHi {
+			d.lo, d.hi = max(d.lo, lo), min(d.hi, hi)
+			d.bounded = true
+		}
+	case *ast.InExpr:
+		if x.Not || x.Subq != nil || !isColumn(x.Expr, col) {
+			return
+		}
+		var in []string
+		for _, item := range x.List {
+			raw, _, ok := constant(item)
+			if !ok {
+				return
+			}
+			in = append(in, raw)
+		}
+		d.in = in
+	}
+}
+
+func isColumn(e ast.Expr, col string) bool {
+	switch x := e.(type) {
+	case *ast.Ident:
+		return strings.EqualFold(x.Unquoted, col)
+	case *ast.QualifiedIdent:
+		return len(x.Parts) > 0 && strings.EqualFold(x.Parts[len(x.Parts)-1].Unquoted, col)
+	}
+	return false
+}
+
+// constant returns the SQL text of a literal and, for numbers, its value.
+func constant(e ast.Expr) (raw string, num float64, ok bool) {
+	switch x := e.(type) {
+	case *ast.Literal:
+		raw = string(x.Raw)
+		if x.Kind == lexer.INT || x.Kind == lexer.FLOAT {
+			num, _ = strconv.ParseFloat(raw, 64)
+		} else {
+			num = math.NaN()
+		}
+		return raw, num, true
+	case *ast.UnaryExpr:
+		if x.Op != lexer.MINUS {
+			return "", 0, false
+		}
+		if raw, num, ok := constant(x.Expr); ok && !math.IsNaN(num) {
+			return "-" + raw, -num, true
+		}
+	}
+	return "", 0, false
+}
+
+// fixtureKind groups column types by the values generated for them.
+type fixtureKind uint8
+
+const (
+	fixtureText fixtureKind = iota
+	fixtureInt
+	fixtureDecimal
+	fixtureFloat
+	fixtureBool
+	fixtureDate
+	fixtureDateTime
+	fixtureTime
+	fixtureUUID
+	fixtureJSON
+	fixtureEnum
+)
+
+// fixtureKindOf classifies c and returns the integer range its type
+// allows, for integer kinds.
+func fixtureKindOf(c *Column) (kind fixtureKind, lo, hi float64) {
+	lo, hi = math.Inf(-1), math.Inf(1)
+	bits := 0
+	switch strings.ToLower(c.Type) {
+	case "tinyint":
+		if c.Precision == 1 {
+			return fixtureBool, lo, hi
+		}
+		bits = 8
+	case "smallint", "int2", "smallserial", "year":
+		bits = 16
+	case "mediumint":
+		bits = 24
+	case "int", "integer", "int4", "serial":
+		bits = 32
+	case "bigint", "int8", "bigserial":
+		bits = 64
+	case "bool", "boolean":
+		return fixtureBool, lo, hi
+	case "bit":
+		if c.Precision <= 1 {
+			return fixtureBool, lo, hi
+		}
+		return fixtureText, lo, hi
+	case "decimal", "numeric", "dec", "money":
+		return fixtureDecimal, lo, hi
+	case "float", "real", "float4", "double", "float8":
+		return fixtureFloat, lo, hi
+	case "date":
+		return fixtureDate, lo, hi
+	case "datetime", "timestamp", "timestamptz":
+		return fixtureDateTime, lo, hi
+	case "time", "timetz":
+		return fixtureTime, lo, hi
+	case "uuid":
+		return fixtureUUID, lo, hi
+	case "json", "jsonb":
+		return fixtureJSON, lo, hi
+	case "enum", "set":
+		return fixtureEnum, lo, hi
+	default:
+		return fixtureText, lo, hi
+	}
+	if c.Unsigned {
+		return fixtureInt, 0, math.Ldexp(1, bits) - 1
+	}
+	return fixtureInt, -math.Ldexp(1, bits-1), math.Ldexp(1, bits-1) - 1
+}
+
+func isSerial(c *Column) bool {
+	switch strings.ToLower(c.Type) {
+	case "serial", "smallserial", "bigserial":
+		return true
+	}
+	return c.AutoIncrement
+}
+
+// value generates the SQL literal of column c for row i. unique columns
+// get values distinct from every other row.
+func (g *fixtureGen) value(c *Column, d fixtureDomain, i int, unique bool) (string, error) {
+	if len(d.in) > 0 {
+		var allowed []string
+		for _, v := range d.in {
+			if !slices.Contains(d.not, v) {
+				allowed = append(allowed, v)
+			}
+		}
+		switch {
+		case len(allowed) == 0:
+			return "", fmt.Errorf("CHECK constraints leave no value")
+		case unique && i >= len(allowed):
+			return "", fmt.Errorf("CHECK constraints allow only %d distinct values", len(allowed))
+		case unique:
+			return allowed[i], nil
+		}
+		return allowed[g.rng.intn(len(allowed))], nil
+	}
+	if c.Nullable && !unique && !d.bounded && g.rng.intn(10) == 0 {
+		return "NULL", nil
+	}
+	kind, lo, hi := fixtureKindOf(c)
+	switch kind {
+	case fixtureInt:
+		lo, hi = math.Max(lo, math.Ceil(d.lo)), math.Min(hi, math.Floor(d.hi))
+		if d.loOpen && lo == d.lo {
+			lo++
+		}
+		if d.hiOpen && hi == d.hi {
+			hi--
+		}
+		if math.IsInf(lo, -1) || !d.bounded && lo < 1 {
+			lo = 1
+		}
+		if math.IsInf(hi, 1) || !d.bounded && hi > lo+9999 {
+			hi = lo + 9999
+		}
+		if unique || isSerial(c) {
+			if v := lo + float64(i); v <= hi {
+				return strconv.FormatFloat(v, 'f', 0, 64), nil
+			}
+			return "", fmt.Errorf("CHECK constraints allow fewer than %d distinct values", i+1)
+		}
+		if hi < lo {
+			return "", fmt.Errorf("CHECK constraints leave no value")
+		}
+		for range 100 {
+			v := strconv.FormatFloat(lo+float64(g.rng.intn(int(hi-lo)+1)), 'f', 0, 64)
+			if !slices.Contains(d.not, v) {
+				return v, nil
+			}
+		}
+		return "", fmt.Errorf("CHECK constraints leave no value")
+	case fixtureDecimal, fixtureFloat:
+		scale := 2
+		if kind == fixtureDecimal {
+			scale = c.Scale
+		}
+		top := 10000.0
+		if kind == fixtureDecimal && c.Precision > 0 {
+			top = math.Min(top, math.Pow10(c.Precision-c.Scale)-1)
+		}
+		lo, hi := math.Max(d.lo, 0), math.Min(d.hi, top)
+		if d.bounded && d.lo < 0 {
+			lo = d.lo
+		}
+		if hi < lo {
+			return "", fmt.Errorf("CHECK constraints leave no value")
+		}
+		step := math.Pow10(-scale)
+		if unique {
+			v := lo + step*float64(i+1)
+			if v > hi {
+				return "", fmt.Errorf("CHECK constraints allow fewer than %d distinct values", i+1)
+			}
+			return strconv.FormatFloat(v, 'f', scale, 64), nil
+		}
+		v := lo + (hi-lo)*g.rng.float()
+		if d.loOpen && v <= d.lo {
+			v = d.lo + step
+		}
+		if d.hiOpen && v >= d.hi {
+			v = d.hi - step
+		}
+		return strconv.FormatFloat(v, 'f', scale, 64), nil
+	case fixtureBool:
+		if unique && i > 1 {
+			return "", fmt.Errorf("a unique boolean column holds at most 2 rows")
+		}
+		if i%2 == 0 && unique || !unique && g.rng.intn(2) == 0 {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case fixtureDate, fixtureDateTime:
+		day := i
+		if !unique {
+			day = g.rng.intn(3650)
+		}
+		t := fixtureEpoch.AddDate(0, 0, day)
+		if kind == fixtureDate {
+			return "'" + t.Format("2006-01-02") + "'", nil
+		}
+		return "'" + t.Add(time.Duration(g.rng.intn(86400))*time.Second).Format("2006-01-02 15:04:05") + "'", nil
+	case fixtureTime:
+		sec := i % 86400
+		if !unique {
+			sec = g.rng.intn(86400)
+		}
+		return fmt.Sprintf("'%02d:%02d:%02d'", sec/3600, sec/60%60, sec%60), nil
+	case fixtureUUID:
+		a, b := g.rng.next(), g.rng.next()
+		return fmt.Sprintf("'%08x-%04x-4%03x-8%03x-%012x'", a>>32, a>>16&0xffff, a&0xfff, b>>52&0xfff, b&0xffffffffffff), nil
+	case fixtureJSON:
+		return fmt.Sprintf(`'{"n": %d}'`, g.rng.intn(1000)), nil
+	case fixtureEnum:
+		if len(c.EnumValues) == 0 {
+			return "", fmt.Errorf("%s column has no members", c.Type)
+		}
+		if unique && i >= len(c.EnumValues) {
+			return "", fmt.Errorf("%s allows only %d distinct values", c.Type, len(c.EnumValues))
+		}
+		k := i
+		if !unique {
+			k = g.rng.intn(len(c.EnumValues))
+		}
+		return quoteSQLString(c.EnumValues[k]), nil
+	}
+	s := c.Name + "_" + strconv.Itoa(i+1)
+	if !unique {
+		s = c.Name + "_" + strconv.Itoa(g.rng.intn(100000))
+	}
+	if n := c.Precision; n > 0 && len(s) > n {
+		if unique {
+			// Keep the distinguishing row number.
+			s = strconv.Itoa(i + 1)
+			if len(s) > n {
+				return "", fmt.Errorf("%s(%d) is too short for %d distinct values", c.Type, n, i+1)
+			}
+		} else {
+			s = s[len(s)-n:]
+		}
+	}
+	return quoteSQLString(s), nil
+}
+
+var fixtureEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func quoteSQLString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// fixtureRand is a splitmix64 generator. It is spelled out rather than
+// taken from math/rand so the sequence for a seed never changes between
+// Go releases.
+type fixtureRand struct{ state uint64 }
+
+func (r *fixtureRand) next() uint64 {
+	r.state += 0x9e3779b97f4a7c15
+	z := r.state
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+func (r *fixtureRand) intn(n int) int {
+	return int(r.next() % uint64(n))
+}
+
+func (r *fixtureRand) float() float64 {
+	return float64(r.next()>>11) / (1 << 53)
+}
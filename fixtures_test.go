@@ -0,0 +1,137 @@
+package sqlparser_test
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+const fixtureDDL = `
+CREATE TABLE orders (
+  id INT AUTO_INCREMENT PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users (id),
+  status ENUM('new', 'paid') NOT NULL,
+  qty SMALLINT NOT NULL CHECK (qty BETWEEN 1 AND 5),
+  total DECIMAL(8,2) CHECK (total > 0),
+  parent_id INT REFERENCES orders (id)
+);
+CREATE TABLE users (id BIGSERIAL PRIMARY KEY, email VARCHAR(40) NOT NULL UNIQUE, kind CHAR(1) CHECK (kind IN ('a', 'b')));
+CREATE TABLE profiles (user_id BIGINT PRIMARY KEY REFERENCES users (id), bio TEXT)`
+
+// fixtureRows parses the generated INSERTs into literal text per table and
+// column.
+func fixtureRows(t *testing.T, stmts []string) (order []string, rows map[string][]map[string]string) {
+	t.Helper()
+	rows = map[string][]map[string]string{}
+	for _, sql := range stmts {
+		stmt, err := sqlparser.ParseStatement(sql)
+		if err != nil {
+			t.Fatalf("generated INSERT does not parse: %v\n%s", err, sql)
+		}
+		ins := stmt.(*sqlparser.InsertStmt)
+		table := ins.Table.Parts[0].Unquoted
+		order = append(order, table)
+		for _, values := range ins.Values {
+			row := map[string]string{}
+			for i, v := range values {
+				switch v := v.(type) {
+				case *ast.Literal:
+					row[ins.Columns[i].Unquoted] = strings.Trim(string(v.Raw), "'")
+				case *ast.NullLit:
+				default:
+					row[ins.Columns[i].Unquoted] = "expr"
+				}
+			}
+			rows[table] = append(rows[table], row)
+		}
+	}
+	return order, rows
+}
+
+func TestGenerateFixtures(t *testing.T) {
+	schema, err := sqlparser.BuildSchema(fixtureDDL)
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	opts := sqlparser.FixtureOptions{Rows: 20, RowsPerTable: map[string]int{"profiles": 5}, Seed: 42}
+	stmts, err := schema.GenerateFixtures(opts)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	again, _ := schema.GenerateFixtures(opts)
+	if !reflect.DeepEqual(stmts, again) {
+		t.Fatalf("same seed produced different fixtures")
+	}
+	opts.Seed = 7
+	if other, _ := schema.GenerateFixtures(opts); reflect.DeepEqual(stmts, other) {
+		t.Fatalf("different seeds produced the same fixtures")
+	}
+
+	order, rows := fixtureRows(t, stmts)
+	if !reflect.DeepEqual(order, []string{"users", "profiles", "orders"}) {
+		t.Fatalf("parents must be inserted first, got %v", order)
+	}
+	users := map[string]bool{}
+	emails := map[string]bool{}
+	for _, u := range rows["users"] {
+		users[u["id"]] = true
+		if emails[u["email"]] || u["email"] == "" {
+			t.Fatalf("duplicate or missing unique email %q", u["email"])
+		}
+		emails[u["email"]] = true
+		if k, ok := u["kind"]; ok && k != "a" && k != "b" {
+			t.Fatalf("kind %q violates its CHECK", k)
+		}
+	}
+	orders := map[string]bool{}
+	for _, o := range rows["orders"] {
+		orders[o["id"]] = true
+	}
+	for _, o := range rows["orders"] {
+		if !users[o["user_id"]] {
+			t.Fatalf("order references missing user %q", o["user_id"])
+		}
+		if p, ok := o["parent_id"]; ok && !orders[p] {
+			t.Fatalf("order references missing parent %q", p)
+		}
+		if o["status"] != "new" && o["status"] != "paid" {
+			t.Fatalf("status %q is not an ENUM member", o["status"])
+		}
+		if q, _ := strconv.Atoi(o["qty"]); q < 1 || q > 5 {
+			t.Fatalf("qty %q violates its CHECK", o["qty"])
+		}
+		if v, ok := o["total"]; ok {
+			if f, _ := strconv.ParseFloat(v, 64); f <= 0 {
+				t.Fatalf("total %q violates its CHECK", v)
+			}
+		}
+	}
+	seen := map[string]bool{}
+	for _, p := range rows["profiles"] {
+		if seen[p["user_id"]] || !users[p["user_id"]] {
+			t.Fatalf("profile key %q is duplicated or dangling", p["user_id"])
+		}
+		seen[p["user_id"]] = true
+	}
+}
+
+func TestGenerateFixturesErrors(t *testing.T) {
+	schema, err := sqlparser.BuildSchema(`
+CREATE TABLE a (id INT PRIMARY KEY, b_id INT NOT NULL REFERENCES b (id));
+CREATE TABLE b (id INT PRIMARY KEY, a_id INT NOT NULL REFERENCES a (id))`)
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	if _, err := schema.GenerateFixtures(sqlparser.FixtureOptions{}); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected a NOT NULL cycle error, got %v", err)
+	}
+
+	schema, _ = sqlparser.BuildSchema(`CREATE TABLE t (flag TINYINT PRIMARY KEY CHECK (flag IN (0, 1)))`)
+	if _, err := schema.GenerateFixtures(sqlparser.FixtureOptions{Rows: 3}); err == nil {
+		t.Fatalf("expected an error for a key with too few allowed values")
+	}
+}
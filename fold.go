@@ -0,0 +1,181 @@
+package sqlparser
+
+import (
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/eval"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// FoldConstants replaces the constant subexpressions of stmts with their
+// values, using eval.Fold, so that WHERE tenant_id = 40 + 2 AND 1 = 1
+// reads as WHERE tenant_id = 42 to a router looking for partition keys.
+// It folds WHERE, HAVING, QUALIFY, join conditions, LIMIT and OFFSET,
+// VALUES rows and assignments, at every level of the statements.
+//
+// A select list expression is folded only when it has an alias, since its
+// text is otherwise the column's name, and a GROUP BY or ORDER BY key is
+// left alone when it would fold to a number, which would then name a
+// column by position. Each statement is replaced in stmts by its
+// rewritten copy on the heap, and stmts is returned.
+func FoldConstants(stmts []Statement) []Statement {
+	for _, stmt := range detach(stmts) {
+		(&folder{}).statement(stmt)
+	}
+	return stmts
+}
+
+// FoldConstantsPass folds constants with FoldConstants.
+func FoldConstantsPass() Pass {
+	return NewPass("fold-constants", func(_ *PipelineContext, stmts []Statement) ([]Statement, error) {
+		return FoldConstants(stmts), nil
+	})
+}
+
+// EliminateDeadCode removes the conditions and CASE branches of stmts
+// that are decided in advance, such as the WHERE 1 = 1 scaffolding that
+// query builders generate to append AND terms to. It folds constants with
+// FoldConstants, which drops TRUE AND x, FALSE OR x and CASE branches
+// that are never taken, then drops the conditions left TRUE: WHERE TRUE,
+// HAVING TRUE and QUALIFY TRUE go, and an inner join ON TRUE becomes a
+// CROSS JOIN. UPDATE and DELETE keep WHERE TRUE, which shows that they
+// are meant to change every row. Like FoldConstants, it replaces the
+// statements of stmts by rewritten copies and returns stmts.
+func EliminateDeadCode(stmts []Statement) []Statement {
+	for _, stmt := range detach(stmts) {
+		eliminateDeadCode(stmt)
+	}
+	return stmts
+}
+
+// EliminateDeadCodePass removes dead code with EliminateDeadCode.
+func EliminateDeadCodePass() Pass {
+	return NewPass("eliminate-dead-code", func(_ *PipelineContext, stmts []Statement) ([]Statement, error) {
+		return EliminateDeadCode(stmts), nil
+	})
+}
+
+// eliminateDeadCode rewrites stmt with EliminateDeadCode and returns how
+// many expressions it simplified.
+func eliminateDeadCode(stmt Statement) int {
+	f := &folder{}
+	f.statement(stmt)
+	for _, s := range selectBlocks(stmt) {
+		for _, cond := range []*ast.Expr{&s.Where, &s.Having, &s.Qualify} {
+			if isTrue(*cond) {
+				*cond = nil
+				f.changed++
+			}
+		}
+		eachJoin(s.From, func(j *ast.JoinTable) {
+			if j.Kind == ast.InnerJoin && isTrue(j.On) {
+				j.Kind, j.On = ast.CrossJoin, nil
+				f.changed++
+			}
+		})
+	}
+	return f.changed
+}
+
+// isTrue reports whether e is the literal TRUE.
+func isTrue(e ast.Expr) bool {
+	lit, ok := e.(*ast.Literal)
+	return ok && lit.Kind == lexer.TRUE_KW
+}
+
+// folder folds the expressions of a statement and counts those that
+// changed.
+type folder struct {
+	changed int
+}
+
+func (f *folder) statement(stmt Statement) {
+	a := &auditor{
+		visit: func(string, string, accessKind) {},
+		node: func(n ast.Node, _ int) {
+			switch x := n.(type) {
+			case *ast.SelectStmt:
+				f.selectStmt(x)
+			case *ast.JoinTable:
+				x.On = f.fold(x.On)
+			}
+		},
+	}
+	switch s := stmt.(type) {
+	case *ast.InsertStmt:
+		f.rows(s.Values)
+		f.assignments(s.OnDupKey)
+		f.assignments(s.OnConflictUpdate)
+		s.OnConflictUpdateWhere = f.fold(s.OnConflictUpdateWhere)
+	case *ast.UpdateStmt:
+		f.assignments(s.Set)
+		s.Where = f.fold(s.Where)
+		f.limit(s.Limit)
+	case *ast.DeleteStmt:
+		s.Where = f.fold(s.Where)
+		f.limit(s.Limit)
+	}
+	a.statement(stmt)
+}
+
+func (f *folder) selectStmt(s *ast.SelectStmt) {
+	for i := range s.Columns {
+		if c := &s.Columns[i]; c.Alias != nil {
+			c.Expr = f.fold(c.Expr)
+		}
+	}
+	s.Where = f.fold(s.Where)
+	for i, e := range s.GroupBy {
+		s.GroupBy[i] = f.key(e)
+	}
+	s.Having = f.fold(s.Having)
+	s.Qualify = f.fold(s.Qualify)
+	for i := range s.OrderBy {
+		s.OrderBy[i].Expr = f.key(s.OrderBy[i].Expr)
+	}
+	f.limit(s.Limit)
+	f.rows(s.Values)
+}
+
+func (f *folder) rows(rows [][]ast.Expr) {
+	for _, row := range rows {
+		for i, e := range row {
+			row[i] = f.fold(e)
+		}
+	}
+}
+
+func (f *folder) assignments(set []ast.Assignment) {
+	for i := range set {
+		set[i].Value = f.fold(set[i].Value)
+	}
+}
+
+func (f *folder) limit(l *ast.LimitClause) {
+	if l != nil {
+		l.Count = f.fold(l.Count)
+		l.Offset = f.fold(l.Offset)
+	}
+}
+
+// key folds a GROUP BY or ORDER BY key unless it becomes a number.
+func (f *folder) key(e ast.Expr) ast.Expr {
+	folded := eval.Fold(e)
+	if lit, ok := folded.(*ast.Literal); ok && folded != e && (lit.Kind == lexer.INT || lit.Kind == lexer.FLOAT) {
+		return e
+	}
+	if folded != e {
+		f.changed++
+	}
+	return folded
+}
+
+func (f *folder) fold(e ast.Expr) ast.Expr {
+	if e == nil {
+		return nil
+	}
+	folded := eval.Fold(e)
+	if folded != e {
+		f.changed++
+	}
+	return folded
+}
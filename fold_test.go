@@ -0,0 +1,96 @@
+package sqlparser_test
+
+import (
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestFoldConstants(t *testing.T) {
+	stmts, err := sqlparser.ParseStatements(`
+		SELECT id, 1 + 1, 2 * 3 AS six FROM events e JOIN tenants t ON t.id = e.tenant_id AND 1 = 1
+			WHERE e.tenant_id = 40 + 2 AND e.day > (SELECT 10 - 3 AS d) ORDER BY 1 + 1, id LIMIT 5 * 2;
+		INSERT INTO events (id, tag) VALUES (2 * 21, 'a' || 'b');
+		UPDATE events SET n = 3 - 1 WHERE tenant_id = 6 * 7 OR 1 = 0;
+		DELETE FROM events WHERE day < 100 - 30`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	stmts = sqlparser.FoldConstants(stmts)
+	want := []string{
+		`SELECT "id", (1 + 1), 6 AS "six" FROM "events" "e" JOIN "tenants" "t" ON ("t"."id" = "e"."tenant_id") WHERE (("e"."tenant_id" = 42) AND ("e"."day" > (SELECT 7 AS "d"))) ORDER BY (1 + 1) ASC, "id" ASC LIMIT 10`,
+		`INSERT INTO "events" ("id", "tag") VALUES (42, 'ab')`,
+		`UPDATE "events" SET "n" = 2 WHERE ("tenant_id" = 42)`,
+		`DELETE FROM "events" WHERE ("day" < 70)`,
+	}
+	for i, stmt := range stmts {
+		got, _, err := sqlparser.ConvertStatements([]sqlparser.Statement{stmt}, sqlparser.ConvertOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want[i] {
+			t.Errorf("statement %d:\ngot  %s\nwant %s", i, got, want[i])
+		}
+	}
+
+	p, err := sqlparser.LookupPass("fold-constants", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := (&sqlparser.Pipeline{Convert: sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL}, Passes: []sqlparser.Pass{p}}).RunSQL("SELECT * FROM t WHERE k = 2 + 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT * FROM `t` WHERE (`k` = 4)"; res.SQL != want {
+		t.Errorf("pipeline: got %s, want %s", res.SQL, want)
+	}
+}
+
+func TestFoldConstantsSurvivesGC(t *testing.T) {
+	checkRewriteSurvivesGC(t, "SELECT a FROM t WHERE x = 40 + 2 AND 'a' || 'b' = y LIMIT 2 * 5", sqlparser.FoldConstants)
+}
+
+func TestEliminateDeadCode(t *testing.T) {
+	tests := []struct{ src, want string }{
+		{"SELECT id FROM t WHERE 1 = 1 AND a = 2", `SELECT "id" FROM "t" WHERE ("a" = 2)`},
+		{"SELECT id FROM t WHERE 1 = 1", `SELECT "id" FROM "t"`},
+		{"SELECT id FROM t WHERE a = 2 OR FALSE", `SELECT "id" FROM "t" WHERE ("a" = 2)`},
+		{"SELECT a, COUNT(*) FROM t GROUP BY a HAVING TRUE AND TRUE", `SELECT "a", COUNT(*) FROM "t" GROUP BY "a"`},
+		{"SELECT CASE WHEN 1 = 0 THEN 'x' WHEN a > 1 THEN 'y' ELSE 'z' END AS c FROM t",
+			`SELECT CASE WHEN ("a" > 1) THEN 'y' ELSE 'z' END AS "c" FROM "t"`},
+		{"SELECT id FROM t WHERE CASE WHEN 2 > 1 THEN a ELSE b END = 1", `SELECT "id" FROM "t" WHERE ("a" = 1)`},
+		{"SELECT * FROM a JOIN b ON 1 = 1 LEFT JOIN c ON TRUE", `SELECT * FROM "a" CROSS JOIN "b" LEFT JOIN "c" ON TRUE`},
+		{"SELECT id FROM t WHERE id IN (SELECT uid FROM u WHERE TRUE AND v = 1)",
+			`SELECT "id" FROM "t" WHERE "id" IN (SELECT "uid" FROM "u" WHERE ("v" = 1))`},
+		{"DELETE FROM t WHERE 1 = 1", `DELETE FROM "t" WHERE TRUE`},
+		// Kept: MySQL's 1 is not TRUE everywhere, and the select list
+		// names unaliased columns.
+		{"SELECT id FROM t WHERE 1", `SELECT "id" FROM "t" WHERE 1`},
+		{"SELECT CASE WHEN FALSE THEN 1 ELSE a END FROM t", `SELECT CASE WHEN FALSE THEN 1 ELSE "a" END FROM "t"`},
+	}
+	for _, tt := range tests {
+		stmts, err := sqlparser.ParseStatements(tt.src)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.src, err)
+		}
+		got, _, err := sqlparser.ConvertStatements(sqlparser.EliminateDeadCode(stmts), sqlparser.ConvertOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s\ngot  %s\nwant %s", tt.src, got, tt.want)
+		}
+	}
+
+	opt, err := sqlparser.OptimizeSQLForDialect("SELECT id FROM t WHERE 1 = 1 AND a = 2", sqlparser.DialectMySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opt.Rewrites) != 1 || opt.Rewrites[0].Rule != "eliminate-dead-code" || opt.Rewrites[0].After != "SELECT `id` FROM `t` WHERE (`a` = 2)" {
+		t.Errorf("rewrites: %+v", opt.Rewrites)
+	}
+}
+
+func TestEliminateDeadCodeSurvivesGC(t *testing.T) {
+	checkRewriteSurvivesGC(t, "SELECT a FROM t WHERE 1 = 1 AND x = 40 + 2 AND CASE WHEN 1 = 0 THEN a ELSE b END = 3", sqlparser.EliminateDeadCode)
+}
@@ -0,0 +1,236 @@
+package sqlparser
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/oarkflow/sqlparser/ident"
+)
+
+// GraphQLOptions tunes GraphQLSDL. Nil name functions use the defaults:
+// PascalCase type names and camelCase field names.
+type GraphQLOptions struct {
+	TypeName  func(table string) string
+	FieldName func(column string) string
+	// NoRelations omits the object fields derived from foreign keys.
+	NoRelations bool
+}
+
+// GraphQLSDL generates GraphQL type definitions for every table: one
+// object type per table with a field per column, NOT NULL columns wrapped
+// as non-null, and relation fields in both directions for each foreign key
+// to a table in the schema. Custom scalars (BigInt, Decimal, Date,
+// DateTime, Time, JSON) are declared when used; ENUM columns get an enum
+// type of their own.
+func (s *Schema) GraphQLSDL(opts GraphQLOptions) string {
+	g := &graphqlGen{schema: s, opts: opts, scalars: map[string]bool{}}
+	if g.opts.TypeName == nil {
+		g.opts.TypeName = func(t string) string { return graphqlName(t, true) }
+	}
+	if g.opts.FieldName == nil {
+		g.opts.FieldName = func(c string) string { return graphqlName(c, false) }
+	}
+	for _, t := range s.Tables {
+		g.objectType(t)
+	}
+
+	var b strings.Builder
+	scalars := make([]string, 0, len(g.scalars))
+	for name := range g.scalars {
+		scalars = append(scalars, name)
+	}
+	slices.Sort(scalars)
+	for _, name := range scalars {
+		b.WriteString("scalar " + name + "\n")
+	}
+	for _, def := range g.defs {
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(def)
+	}
+	return b.String()
+}
+
+type graphqlGen struct {
+	schema  *Schema
+	opts    GraphQLOptions
+	scalars map[string]bool
+	defs    []string // object and enum definitions in output order
+	enums   []string
+}
+
+func (g *graphqlGen) objectType(t *Table) {
+	typeName := g.opts.TypeName(t.Name)
+	used := map[string]bool{}
+	var b strings.Builder
+	b.WriteString("type " + typeName + " {\n")
+	for _, c := range t.Columns {
+		name := g.opts.FieldName(c.Name)
+		used[name] = true
+		writeDescription(&b, c.Comment)
+		typ := g.fieldType(t, c, typeName+graphqlName(c.Name, true))
+		if !c.Nullable {
+			typ += "!"
+		}
+		b.WriteString("  " + name + ": " + typ + "\n")
+	}
+	if !g.opts.NoRelations {
+		for _, fk := range t.ForeignKeys {
+			ref := g.schema.Table(fk.RefTable)
+			if ref == nil {
+				continue
+			}
+			name := uniqueField(g.opts.FieldName(relationName(fk, ref)), used)
+			typ := g.opts.TypeName(ref.Name)
+			if !t.anyNullable(fk.Columns) {
+				typ += "!"
+			}
+			b.WriteString("  " + name + ": " + typ + "\n")
+		}
+		for _, src := range g.schema.Tables {
+			for _, fk := range src.ForeignKeys {
+				if g.schema.Table(fk.RefTable) != t {
+					continue
+				}
+				name := uniqueField(g.opts.FieldName(src.Name), used)
+				b.WriteString("  " + name + ": [" + g.opts.TypeName(src.Name) + "!]!\n")
+			}
+		}
+	}
+	b.WriteString("}\n")
+	g.defs = append(g.defs, b.String())
+	g.defs = append(g.defs, g.enums...)
+	g.enums = g.enums[:0]
+}
+
+// fieldType maps a column to a GraphQL type name, declaring custom scalars
+// and enums as needed.
+func (g *graphqlGen) fieldType(t *Table, c *Column, enumName string) string {
+	if len(t.PrimaryKey) == 1 && ident.Equal(t.PrimaryKey[0], c.Name, t.dialect) {
+		return "ID"
+	}
+	custom := func(name string) string {
+		g.scalars[name] = true
+		return name
+	}
+	switch strings.ToLower(c.Type) {
+	case "tinyint", "bit":
+		if c.Precision == 1 {
+			return "Boolean"
+		}
+		return "Int"
+	case "bool", "boolean":
+		return "Boolean"
+	case "smallint", "mediumint", "int", "integer", "int2", "int4", "serial", "smallserial", "year":
+		return "Int"
+	case "bigint", "int8", "bigserial":
+		return custom("BigInt")
+	case "float", "real", "float4", "double", "float8":
+		return "Float"
+	case "decimal", "numeric", "dec", "money":
+		return custom("Decimal")
+	case "date":
+		return custom("Date")
+	case "datetime", "timestamp", "timestamptz":
+		return custom("DateTime")
+	case "time", "timetz":
+		return custom("Time")
+	case "json", "jsonb":
+		return custom("JSON")
+	case "uuid":
+		return "ID"
+	case "enum":
+		if len(c.EnumValues) == 0 {
+			return "String"
+		}
+		var b strings.Builder
+		b.WriteString("enum " + enumName + " {\n")
+		for _, v := range c.EnumValues {
+			b.WriteString("  " + strings.ToUpper(graphqlIdent(v)) + "\n")
+		}
+		b.WriteString("}\n")
+		g.enums = append(g.enums, b.String())
+		return enumName
+	}
+	return "String"
+}
+
+func (t *Table) anyNullable(cols []string) bool {
+	for _, name := range cols {
+		if c := t.Column(name); c == nil || c.Nullable {
+			return true
+		}
+	}
+	return false
+}
+
+// relationName names the field that follows fk: user_id becomes user, and
+// anything else falls back to the referenced table.
+func relationName(fk *ForeignKey, ref *Table) string {
+	if len(fk.Columns) == 1 {
+		col := strings.ToLower(fk.Columns[0])
+		for _, suffix := range []string{"_id", "id"} {
+			if base, ok := strings.CutSuffix(col, suffix); ok && base != "" {
+				return strings.TrimSuffix(base, "_")
+			}
+		}
+	}
+	return ref.Name
+}
+
+func uniqueField(name string, used map[string]bool) string {
+	for used[name] {
+		name += "Ref"
+	}
+	used[name] = true
+	return name
+}
+
+func writeDescription(b *strings.Builder, text string) {
+	if text == "" {
+		return
+	}
+	b.WriteString(`  """` + strings.ReplaceAll(text, `"""`, `\"""`) + `"""` + "\n")
+}
+
+// graphqlName converts a snake_case SQL name to PascalCase or camelCase.
+func graphqlName(s string, upper bool) string {
+	var b strings.Builder
+	next := upper
+	for _, r := range graphqlIdent(s) {
+		if r == '_' {
+			if b.Len() > 0 {
+				next = true
+			}
+			continue
+		}
+		if next {
+			r = unicode.ToUpper(r)
+			next = false
+		} else if b.Len() == 0 {
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	out := b.String()
+	if out == "" || out[0] >= '0' && out[0] <= '9' {
+		out = "_" + out
+	}
+	return out
+}
+
+// graphqlIdent replaces characters GraphQL names cannot hold with '_'.
+func graphqlIdent(s string) string {
+	out := []byte(s)
+	for i, c := range out {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			out[i] = '_'
+		}
+	}
+	if len(out) == 0 || out[0] >= '0' && out[0] <= '9' {
+		out = append([]byte{'_'}, out...)
+	}
+	return string(out)
+}
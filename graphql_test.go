@@ -0,0 +1,64 @@
+package sqlparser_test
+
+import (
+	"strings"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestSchemaGraphQLSDL(t *testing.T) {
+	s, err := sqlparser.BuildSchema(`
+CREATE TABLE users (
+	id BIGINT NOT NULL PRIMARY KEY,
+	display_name VARCHAR(64) NOT NULL COMMENT 'shown in the UI',
+	role ENUM('admin', 'member') NOT NULL,
+	created_at TIMESTAMP
+);
+CREATE TABLE orders (
+	id INT PRIMARY KEY,
+	user_id BIGINT NOT NULL,
+	total DECIMAL(10,2),
+	CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id)
+)`)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	want := `scalar BigInt
+scalar DateTime
+scalar Decimal
+
+type Users {
+  id: ID!
+  """shown in the UI"""
+  displayName: String!
+  role: UsersRole!
+  createdAt: DateTime
+  orders: [Orders!]!
+}
+
+enum UsersRole {
+  ADMIN
+  MEMBER
+}
+
+type Orders {
+  id: ID!
+  userId: BigInt!
+  total: Decimal
+  user: Users!
+}
+`
+	if got := s.GraphQLSDL(sqlparser.GraphQLOptions{}); got != want {
+		t.Fatalf("unexpected SDL:\n%s\nwant:\n%s", got, want)
+	}
+
+	got := s.GraphQLSDL(sqlparser.GraphQLOptions{
+		TypeName:    func(table string) string { return "T_" + table },
+		FieldName:   func(col string) string { return col },
+		NoRelations: true,
+	})
+	if want := "type T_orders {\n  id: ID!\n  user_id: BigInt!\n  total: Decimal\n}\n"; !strings.Contains(got, want) {
+		t.Fatalf("custom names not applied:\n%s", got)
+	}
+}
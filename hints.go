@@ -0,0 +1,128 @@
+package sqlparser
+
+import (
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// renderHints returns the /*+ ... */ comment for a SELECT: its own hints
+// followed by any hint armed for it, such as a timeout. Only MySQL reads
+// these hints; for other targets the statement's hints are dropped with a
+// warning.
+func (r *dialectRenderer) renderHints(s *ast.SelectStmt) string {
+	armed := r.selectHint
+	r.selectHint = ""
+	if len(s.Hints) == 0 && armed == "" {
+		return ""
+	}
+	if r.target != DialectMySQL {
+		if len(s.Hints) > 0 {
+			r.warn(WarnHintDropped, s.Hints[0].TokPos, "optimizer hints dropped; %s does not support /*+ ... */ hints", r.target)
+		}
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("/*+")
+	for _, h := range s.Hints {
+		b.WriteByte(' ')
+		b.Write(h.Name)
+		if h.Args != nil {
+			b.WriteByte('(')
+			b.Write(h.Args)
+			b.WriteByte(')')
+		}
+	}
+	if armed != "" {
+		b.WriteString(" " + armed)
+	}
+	b.WriteString(" */")
+	return b.String()
+}
+
+// versionComments holds the MySQL version comments (/*! ... */) found around
+// one statement of a script.
+type versionComments struct {
+	// before are comments that formed statements of their own, such as the
+	// /*!40101 SET NAMES utf8 */; lines of a dump, ahead of this statement.
+	before []string
+	// leading precede the statement's first token.
+	leading []string
+	// trailing follow the statement's last token.
+	trailing []string
+	// inner sit between the statement's tokens, where a rendered statement
+	// has no place to keep them.
+	inner []lexer.Token
+}
+
+// statementVersionComments collects the version comments of sql per
+// statement, aligned with ParseStatements. Comments after the last
+// statement are returned separately.
+func statementVersionComments(sql string) (stmts []versionComments, after []string) {
+	l := lexer.NewString(sql)
+	l.SetOptions(lexer.Options{EmitComments: true})
+	var cur versionComments
+	var pending []lexer.Token // version comments since the last real token
+	inStmt := false
+	for {
+		tok := l.Next()
+		switch tok.Type {
+		case lexer.COMMENT:
+			if isVersionComment(tok.Raw) {
+				pending = append(pending, tok)
+			}
+			continue
+		case lexer.SEMICOLON, lexer.EOF:
+			if inStmt {
+				for _, c := range pending {
+					cur.trailing = append(cur.trailing, string(c.Raw))
+				}
+				stmts = append(stmts, cur)
+				cur, inStmt = versionComments{}, false
+			} else {
+				for _, c := range pending {
+					cur.before = append(cur.before, string(c.Raw))
+				}
+			}
+			pending = pending[:0]
+			if tok.Type == lexer.EOF {
+				return stmts, cur.before
+			}
+		default:
+			if inStmt {
+				cur.inner = append(cur.inner, pending...)
+			} else {
+				for _, c := range pending {
+					cur.leading = append(cur.leading, string(c.Raw))
+				}
+			}
+			pending = pending[:0]
+			inStmt = true
+		}
+	}
+}
+
+// wrapVersionComments places the version comments kept for the i-th
+// statement around its rendered text s. Comments that sat between the
+// statement's tokens cannot be placed and are dropped with a warning.
+func (r *dialectRenderer) wrapVersionComments(i int, s string) string {
+	if i >= len(r.versionComments) {
+		return s
+	}
+	vc := r.versionComments[i]
+	for _, c := range vc.inner {
+		r.warn(WarnVersionCommentDropped, c.Pos, "version comment %s inside the statement was dropped; only comments before or after a statement are kept", c.Raw)
+	}
+	if len(vc.leading) > 0 {
+		s = strings.Join(vc.leading, " ") + " " + s
+	}
+	if len(vc.trailing) > 0 {
+		s += " " + strings.Join(vc.trailing, " ")
+	}
+	return s
+}
+
+func isVersionComment(raw []byte) bool {
+	return len(raw) > 3 && raw[0] == '/' && raw[1] == '*' && (raw[2] == '!' || raw[2] == 'M' && raw[3] == '!')
+}
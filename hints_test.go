@@ -0,0 +1,60 @@
+package sqlparser_test
+
+import (
+	"testing"
+	"time"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestConvertKeepsOptimizerHints(t *testing.T) {
+	const sql = "SELECT /*+ INDEX(t idx_a) SET_VAR(sort_buffer_size = 16M) */ a FROM t"
+	out, _, err := sqlparser.ConvertDialectWithOptions(sql, sqlparser.ConvertOptions{
+		Target:   sqlparser.DialectMySQL,
+		Timeouts: sqlparser.TimeoutPolicy{Default: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	want := "SELECT /*+ INDEX(t idx_a) SET_VAR(sort_buffer_size = 16M) MAX_EXECUTION_TIME(1000) */ `a` FROM `t`"
+	if out != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+
+	out, warnings, err := sqlparser.ConvertDialectWithOptions(sql, sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if out != `SELECT "a" FROM "t"` {
+		t.Fatalf("hints should be dropped for postgres: %s", out)
+	}
+	if len(warnings) != 1 || warnings[0].Code != sqlparser.WarnHintDropped {
+		t.Fatalf("expected HINT_DROPPED, got %+v", warnings)
+	}
+}
+
+func TestConvertKeepsVersionComments(t *testing.T) {
+	const dump = `/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+/*!40101 SET NAMES utf8mb4 */;
+CREATE TABLE t (id INT) /*!50100 PARTITION BY HASH (id) */;
+INSERT INTO t /*!99999 IGNORE */ VALUES (1);
+/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;`
+	out, warnings, err := sqlparser.ConvertDialectWithOptions(dump, sqlparser.ConvertOptions{
+		Target:              sqlparser.DialectMySQL,
+		KeepVersionComments: true,
+	})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	want := "/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */; " +
+		"/*!40101 SET NAMES utf8mb4 */; " +
+		"CREATE TABLE `t` (`id` INT) /*!50100 PARTITION BY HASH (id) */; " +
+		"INSERT INTO `t` VALUES (1); " +
+		"/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */"
+	if out != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+	if len(warnings) != 1 || warnings[0].Code != sqlparser.WarnVersionCommentDropped {
+		t.Fatalf("expected VERSION_COMMENT_DROPPED for the inner comment, got %+v", warnings)
+	}
+}
@@ -0,0 +1,57 @@
+// Package ident compares SQL identifiers the way each database does.
+// PostgreSQL folds unquoted names to lower case and then compares them
+// exactly, so "Users" and users are different tables. MySQL compares
+// column names without regard to case, and table names too on the
+// case-insensitive file systems of Windows and macOS, which is the rule
+// followed here. SQLite ignores the case of ASCII letters, quoted or not,
+// and ClickHouse compares every name exactly.
+//
+// Dialects are named as sqlparser.Dialect names them: "mysql", "postgres",
+// "sqlite" and "clickhouse". An empty or unknown dialect compares names
+// without regard to case, which never tells apart two names a database
+// would take for one.
+package ident
+
+import "strings"
+
+// Equal reports whether the resolved names a and b, as in Ident.Unquoted,
+// name the same object in dialect.
+func Equal[D ~string](a, b string, dialect D) bool {
+	if a == b {
+		return true
+	}
+	switch dialect {
+	case "postgres", "clickhouse":
+		return false
+	case "sqlite":
+		return Key(a, dialect) == Key(b, dialect)
+	}
+	return strings.EqualFold(a, b)
+}
+
+// Key returns the form of the resolved name under which Equal names are
+// the same, for use as a map key.
+func Key[D ~string](name string, dialect D) string {
+	switch dialect {
+	case "postgres", "clickhouse":
+		return name
+	case "sqlite":
+		return lowerASCII(name)
+	}
+	return strings.ToLower(name)
+}
+
+func lowerASCII(s string) string {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= 'A' && c <= 'Z' {
+			b := []byte(s)
+			for j := i; j < len(b); j++ {
+				if c := b[j]; c >= 'A' && c <= 'Z' {
+					b[j] = c + 'a' - 'A'
+				}
+			}
+			return string(b)
+		}
+	}
+	return s
+}
@@ -0,0 +1,35 @@
+package ident_test
+
+import (
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+	"github.com/oarkflow/sqlparser/ident"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		dialect sqlparser.Dialect
+		want    bool
+	}{
+		{"users", "users", sqlparser.DialectPostgres, true},
+		{"Users", "users", sqlparser.DialectPostgres, false},
+		{"Users", "users", sqlparser.DialectClickHouse, false},
+		{"Users", "users", sqlparser.DialectMySQL, true},
+		{"Users", "USERS", sqlparser.DialectSQLite, true},
+		// SQLite only folds ASCII letters.
+		{"Ärger", "ärger", sqlparser.DialectSQLite, false},
+		{"Ärger", "ärger", sqlparser.DialectMySQL, true},
+		{"Users", "users", "", true},
+		{"users", "orders", "", false},
+	}
+	for _, tt := range tests {
+		if got := ident.Equal(tt.a, tt.b, tt.dialect); got != tt.want {
+			t.Errorf("Equal(%q, %q, %q) = %v, want %v", tt.a, tt.b, tt.dialect, got, tt.want)
+		}
+		if got := ident.Key(tt.a, tt.dialect) == ident.Key(tt.b, tt.dialect); got != tt.want {
+			t.Errorf("Key(%q) == Key(%q) for %q is %v, want %v", tt.a, tt.b, tt.dialect, got, tt.want)
+		}
+	}
+}
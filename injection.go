@@ -0,0 +1,184 @@
+package sqlparser
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// exfiltrationTables and exfiltrationFuncs are the catalogs and the
+// functions UNION-based injection reads to map or identify a database,
+// lower-cased.
+var (
+	exfiltrationTables = map[string]bool{
+		"information_schema": true, "pg_catalog": true, "pg_shadow": true, "pg_user": true,
+		"pg_roles": true, "sqlite_master": true, "sqlite_schema": true,
+	}
+	exfiltrationFuncs = map[string]bool{
+		"version": true, "user": true, "current_user": true, "session_user": true,
+		"system_user": true, "database": true, "current_database": true,
+		"load_file": true, "pg_read_file": true,
+	}
+)
+
+// ScanInjection flags the marks SQL injection leaves in query text, for
+// scanning logged queries in a WAF pipeline. It is meant for statements
+// built without placeholders, and works on tokens, so text that does not
+// parse is scanned too. The findings are:
+//
+//   - INJECTION_TAUTOLOGY: OR followed by a comparison of literals that is
+//     always true, such as OR '1'='1', or by a bare true literal.
+//   - INJECTION_STACKED_QUERY: a statement after a ; that directly follows
+//     a string, as in name = 'x'; SELECT ..., or a write after one that
+//     follows a number, as in id = 1; DROP TABLE users.
+//   - INJECTION_COMMENT_TAIL: a comment that runs to the end of the text
+//     and either holds a quote or follows a string literal without a
+//     space, as in 'admin'--'.
+//   - INJECTION_UNION_EXFILTRATION: a UNION SELECT whose columns are all
+//     NULL or numbers, the probe for a column count, or that reads the
+//     catalog or identifies the server.
+//
+// These are heuristics: they can flag legitimate scripts, which is why
+// multi-statement text should not be scanned, and miss obfuscated attacks.
+// Report.Valid says whether the text parses.
+func ScanInjection(sql string) AnalysisReport {
+	var report AnalysisReport
+	if stmts, err := ParseStatements(sql); err == nil {
+		report.Valid = true
+		report.StatementCount = len(stmts)
+	}
+	var toks []lexer.Token
+	l := lexer.NewWithOptions([]byte(sql), lexer.Options{EmitComments: true})
+	for t := l.Next(); t.Type != lexer.EOF; t = l.Next() {
+		toks = append(toks, t)
+	}
+	stmt := 0
+	for i, t := range toks {
+		switch t.Type {
+		case lexer.OR:
+			if tautology(toks[i+1:]) {
+				addFinding(&report, SeverityCritical, "INJECTION_TAUTOLOGY", "OR is followed by a condition that is always true, the usual way injected input widens a WHERE clause to every row.", "Bind user input as parameters instead of concatenating it into the SQL text.", stmt, t.Pos)
+			}
+		case lexer.SEMICOLON:
+			if i > 0 && stackedQuery(toks[i-1], toks[i+1:]) {
+				addFinding(&report, SeverityCritical, "INJECTION_STACKED_QUERY", "A statement follows a ; that directly ends a literal, the shape of input that closes a quoted value and stacks its own query.", "Bind user input as parameters, and disable multi-statement execution in the driver.", stmt, t.Pos)
+			}
+			stmt++
+		case lexer.COMMENT:
+			if i != len(toks)-1 || i == 0 {
+				continue
+			}
+			body := string(t.Raw)
+			glued := toks[i-1].Type == lexer.STRING && toks[i-1].Pos+int32(len(toks[i-1].Raw)) == t.Pos
+			if glued || strings.ContainsAny(body, `'"`) {
+				addFinding(&report, SeverityWarning, "INJECTION_COMMENT_TAIL", "The query ends in a comment glued to a literal or holding a quote, the usual way injected input discards the rest of the original query.", "Bind user input as parameters instead of concatenating it into the SQL text.", stmt, t.Pos)
+			}
+		case lexer.UNION:
+			if reason := unionExfiltration(toks[i+1:]); reason != "" {
+				addFinding(&report, SeverityCritical, "INJECTION_UNION_EXFILTRATION", "UNION SELECT "+reason+", the pattern of UNION-based injection.", "Bind user input as parameters, and run application queries as a role that cannot read the catalog.", stmt, t.Pos)
+			}
+		}
+	}
+	setFindingLines(report.Findings, sql)
+	return report
+}
+
+// tautology reports whether toks, the tokens after an OR, start with a
+// condition that is always true: equal literals compared with =, different
+// ones compared with <>, or a true literal on its own.
+func tautology(toks []lexer.Token) bool {
+	if len(toks) >= 3 && isLiteralToken(toks[0].Type) && isLiteralToken(toks[2].Type) {
+		switch toks[1].Type {
+		case lexer.EQ:
+			return literalValue(toks[0]) == literalValue(toks[2])
+		case lexer.NEQ:
+			return literalValue(toks[0]) != literalValue(toks[2])
+		}
+	}
+	if len(toks) == 0 || !(toks[0].Type == lexer.TRUE_KW || toks[0].Type == lexer.INT && literalValue(toks[0]) != "0") {
+		return false
+	}
+	if len(toks) == 1 {
+		return true
+	}
+	switch toks[1].Type {
+	case lexer.COMMENT, lexer.SEMICOLON, lexer.RPAREN, lexer.OR, lexer.AND:
+		return true
+	}
+	return false
+}
+
+// unionExfiltration describes what the UNION SELECT at the start of toks
+// reads, or returns "" for an ordinary one.
+func unionExfiltration(toks []lexer.Token) string {
+	if len(toks) > 0 && (toks[0].Type == lexer.ALL || toks[0].Type == lexer.DISTINCT) {
+		toks = toks[1:]
+	}
+	if len(toks) == 0 || toks[0].Type != lexer.SELECT {
+		return ""
+	}
+	// probe stays set while the select list holds only NULLs and numbers.
+	probe := true
+	for i := 1; i < len(toks); i++ {
+		t := toks[i]
+		switch t.Type {
+		case lexer.UNION, lexer.SEMICOLON, lexer.COMMENT, lexer.FROM:
+			if probe && i > 1 {
+				return "selects only NULLs and numbers"
+			}
+			if t.Type != lexer.FROM {
+				return ""
+			}
+			probe = false
+		case lexer.NULL_KW, lexer.INT, lexer.COMMA:
+		default:
+			probe = false
+			name := strings.ToLower(string(t.Raw))
+			switch {
+			case t.Type == lexer.NAMEDPARAM && name == "@version":
+				return "reads @@version"
+			case exfiltrationTables[name]:
+				return "reads " + name
+			case exfiltrationFuncs[name] && i+1 < len(toks) && toks[i+1].Type == lexer.LPAREN:
+				return "calls " + name + "()"
+			}
+		}
+	}
+	if probe && len(toks) > 1 {
+		return "selects only NULLs and numbers"
+	}
+	return ""
+}
+
+func isLiteralToken(t lexer.TokenType) bool {
+	return t == lexer.STRING || t == lexer.INT || t == lexer.FLOAT
+}
+
+// literalValue returns a literal token's value for comparison: strings
+// unquoted, numbers in canonical form.
+func literalValue(t lexer.Token) string {
+	if t.Type == lexer.STRING {
+		return unquoteString(t.Raw, "")
+	}
+	if f, err := strconv.ParseFloat(string(t.Raw), 64); err == nil {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return string(t.Raw)
+}
+
+// stackedQuery reports whether the ; between prev and rest looks like the
+// end of injected input: it closes a string and a statement follows, or
+// it ends a number and a write follows.
+func stackedQuery(prev lexer.Token, rest []lexer.Token) bool {
+	for _, t := range rest {
+		switch t.Type {
+		case lexer.COMMENT, lexer.SEMICOLON:
+			continue
+		case lexer.INSERT, lexer.UPDATE, lexer.DELETE, lexer.DROP, lexer.ALTER, lexer.CREATE, lexer.TRUNCATE:
+			return isLiteralToken(prev.Type)
+		}
+		return prev.Type == lexer.STRING
+	}
+	return false
+}
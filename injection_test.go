@@ -0,0 +1,47 @@
+package sqlparser_test
+
+import (
+	"reflect"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestScanInjection(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []string
+	}{
+		{"SELECT * FROM users WHERE name = 'x' OR '1'='1'", []string{"INJECTION_TAUTOLOGY"}},
+		{"SELECT * FROM users WHERE id = 5 OR 1=1", []string{"INJECTION_TAUTOLOGY"}},
+		{"SELECT * FROM users WHERE id = 5 OR 'a' <> 'b'", []string{"INJECTION_TAUTOLOGY"}},
+		{"SELECT * FROM users WHERE name = '' OR 1 -- '", []string{"INJECTION_TAUTOLOGY", "INJECTION_COMMENT_TAIL"}},
+		{"SELECT * FROM users WHERE name = 'admin'--' AND pass = 'x'", []string{"INJECTION_COMMENT_TAIL"}},
+		{"SELECT * FROM users WHERE name = 'x'; DROP TABLE users", []string{"INJECTION_STACKED_QUERY"}},
+		{"SELECT * FROM users WHERE id = 1; DELETE FROM users", []string{"INJECTION_STACKED_QUERY"}},
+		{"SELECT name FROM items WHERE id = 1 UNION SELECT NULL, NULL, 3", []string{"INJECTION_UNION_EXFILTRATION"}},
+		{"SELECT name FROM items WHERE id = 1 UNION ALL SELECT table_name FROM information_schema.tables", []string{"INJECTION_UNION_EXFILTRATION"}},
+		{"SELECT name FROM items WHERE id = 1 UNION SELECT @@version -- ", []string{"INJECTION_UNION_EXFILTRATION"}},
+		{"SELECT name FROM items WHERE id = 1 UNION SELECT version()", []string{"INJECTION_UNION_EXFILTRATION"}},
+
+		// Ordinary queries.
+		{"SELECT * FROM users WHERE 1=1 AND name = 'x'", nil},
+		{"SELECT * FROM users WHERE a = 1 OR b = 2 -- list users", nil},
+		{"SELECT name FROM a UNION SELECT user FROM b", nil},
+		{"SELECT id FROM a UNION ALL SELECT id FROM b WHERE n = 1", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, f := range sqlparser.ScanInjection(tt.sql).Findings {
+			got = append(got, f.Code)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+
+	report := sqlparser.ScanInjection("SELECT 1;\nSELECT * FROM t WHERE a = '' OR 'x'='x'")
+	if f := report.Findings[0]; !report.Valid || f.StatementIndex != 1 || f.Line != 2 || f.Col != 30 {
+		t.Errorf("unexpected report %+v", report.Findings)
+	}
+}
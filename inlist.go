@@ -0,0 +1,407 @@
+package sqlparser
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// InListStrategy selects how an IN list longer than InListPolicy.MaxSize is
+// handled.
+type InListStrategy uint8
+
+const (
+	// InListKeep leaves oversized lists as written.
+	InListKeep InListStrategy = iota
+	// InListValues rewrites the list as a semi-join against a VALUES table,
+	// x IN (VALUES (1), (2), ...), using VALUES ROW(...) for MySQL. PostgreSQL
+	// types placeholders inside VALUES as text, so lists of placeholders
+	// compared with non-text columns need a CAST on the first element.
+	InListValues
+	// InListSplit executes the statement once per chunk of at most MaxSize
+	// elements. Only PlanInList can split; ConvertDialectWithOptions returns a
+	// single script and rewrites with InListValues instead.
+	InListSplit
+)
+
+// InListPolicy bounds the size of literal IN lists. A zero MaxSize disables
+// the policy.
+type InListPolicy struct {
+	MaxSize  int
+	Strategy InListStrategy
+}
+
+func (p InListPolicy) oversized(e *ast.InExpr) bool {
+	return p.MaxSize > 0 && p.Strategy != InListKeep && e.Subq == nil && len(e.List) > p.MaxSize
+}
+
+// PlannedStatement is one execution of a plan built by PlanInList.
+type PlannedStatement struct {
+	SQL  string
+	Args []any
+}
+
+// PlanInList converts sql like ConvertDialectWithOptions but returns one
+// PlannedStatement per execution. With opts.InList.Strategy set to
+// InListSplit, a statement whose oversized IN list can be split runs once per
+// chunk of the list; every other statement, and every oversized list that
+// cannot be split, is planned as a single execution with InListValues.
+//
+// A list can be split when it is a plain IN (not NOT IN) that is a top-level
+// AND term of the WHERE clause of a SELECT, UPDATE or DELETE without
+// DISTINCT, GROUP BY, HAVING, ORDER BY, LIMIT, set operations or aggregates.
+// Chunks are disjoint slices of the list, so a value repeated in different
+// chunks matches its rows once per chunk.
+//
+// args are the positional arguments of the whole script. Each planned
+// statement gets the arguments its placeholders refer to, in the order the
+// target dialect numbers them. A nil args plans SQL only.
+func PlanInList(sql string, args []any, opts ConvertOptions) ([]PlannedStatement, []ConversionWarning, error) {
+	stmts, err := ParseStatements(sql)
+	if err != nil {
+		return nil, nil, err
+	}
+	var positional []int32
+	if args != nil {
+		collect := newDialectRenderer(ConvertOptions{Target: opts.Target})
+		collect.recordParams = true
+		for _, stmt := range stmts {
+			collect.renderStatement(stmt)
+		}
+		for _, prm := range collect.params {
+			if string(prm.Raw) == "?" {
+				positional = append(positional, prm.TokPos)
+			}
+		}
+		slices.Sort(positional)
+	}
+
+	r := newDialectRenderer(opts)
+	r.recordParams = args != nil
+	if opts.KeepTags {
+		r.stmtTags = StatementTags(sql)
+	}
+	var plan []PlannedStatement
+	emit := func(i int, stmt Statement) error {
+		r.paramIndex = 0
+		r.params = r.params[:0]
+		s, err := r.renderTopLevel(i, stmt)
+		if err != nil {
+			return err
+		}
+		ps := PlannedStatement{SQL: s}
+		if args != nil {
+			ps.Args = make([]any, 0, len(r.params))
+			for _, prm := range r.params {
+				n, err := paramArg(prm, positional)
+				if err != nil {
+					return err
+				}
+				if n >= len(args) {
+					return fmt.Errorf("sqlparser: parameter %s refers to argument %d but only %d were given", prm.Raw, n+1, len(args))
+				}
+				ps.Args = append(ps.Args, args[n])
+			}
+		}
+		plan = append(plan, ps)
+		return nil
+	}
+	for i, stmt := range stmts {
+		target := r.inList.splitTarget(stmt)
+		if target == nil {
+			if err := emit(i, stmt); err != nil {
+				return nil, r.warnings, err
+			}
+			continue
+		}
+		list := target.List
+		for lo := 0; lo < len(list); lo += r.inList.MaxSize {
+			target.List = list[lo:min(lo+r.inList.MaxSize, len(list))]
+			if err := emit(i, stmt); err != nil {
+				target.List = list
+				return nil, r.warnings, err
+			}
+		}
+		target.List = list
+	}
+	return plan, r.warnings, nil
+}
+
+// paramArg returns the index into the script arguments that prm refers to.
+// positional holds the offsets of every ? placeholder in source order.
+func paramArg(prm *ast.Param, positional []int32) (int, error) {
+	raw := prm.Raw
+	switch {
+	case len(raw) == 1 && raw[0] == '?':
+		n, _ := slices.BinarySearch(positional, prm.TokPos)
+		return n, nil
+	case len(raw) > 1 && raw[0] == '$':
+		if n, err := strconv.Atoi(string(raw[1:])); err == nil && n > 0 {
+			return n - 1, nil
+		}
+	}
+	return 0, fmt.Errorf("sqlparser: parameter %s cannot be mapped to a positional argument", raw)
+}
+
+// splitTarget returns the IN list PlanInList splits stmt on, or nil when the
+// policy does not split or stmt has no list that can be split safely.
+func (p InListPolicy) splitTarget(stmt Statement) *ast.InExpr {
+	if p.Strategy != InListSplit || p.MaxSize <= 0 {
+		return nil
+	}
+	var where Expr
+	switch s := stmt.(type) {
+	case *ast.SelectStmt:
+		if s.Distinct || len(s.GroupBy) > 0 || s.Having != nil || len(s.OrderBy) > 0 || s.Limit != nil || s.SetOp != nil {
+			return nil
+		}
+		for _, c := range s.Columns {
+			if hasAggregate(c.Expr) {
+				return nil
+			}
+		}
+		where = s.Where
+	case *ast.UpdateStmt:
+		if s.Limit != nil {
+			return nil
+		}
+		where = s.Where
+	case *ast.DeleteStmt:
+		if s.Limit != nil {
+			return nil
+		}
+		where = s.Where
+	}
+	return p.findConjunct(where)
+}
+
+func (p InListPolicy) findConjunct(e Expr) *ast.InExpr {
+	switch ex := e.(type) {
+	case *ast.BinaryExpr:
+		if ex.Op != lexer.AND && ex.Op != lexer.DAMP {
+			return nil
+		}
+		if in := p.findConjunct(ex.Left); in != nil {
+			return in
+		}
+		return p.findConjunct(ex.Right)
+	case *ast.InExpr:
+		if !ex.Not && p.oversized(ex) {
+			return ex
+		}
+	}
+	return nil
+}
+
+var aggregateFuncs = map[string]bool{
+	"COUNT": true, "SUM": true, "AVG": true, "MIN": true, "MAX": true,
+	"GROUP_CONCAT": true, "STRING_AGG": true, "ARRAY_AGG": true, "JSON_AGG": true,
+	"JSON_ARRAYAGG": true, "JSON_OBJECTAGG": true, "BOOL_AND": true, "BOOL_OR": true,
+}
+
+func hasAggregate(e Expr) bool {
+	switch ex := e.(type) {
+	case *ast.FuncCall:
+		if ex.Over == nil && ex.Name != nil && len(ex.Name.Parts) == 1 && aggregateFuncs[strings.ToUpper(ex.Name.Parts[0].Unquoted)] {
+			return true
+		}
+		for _, a := range ex.Args {
+			if hasAggregate(a) {
+				return true
+			}
+		}
+	case *ast.BinaryExpr:
+		return hasAggregate(ex.Left) || hasAggregate(ex.Right)
+	case *ast.UnaryExpr:
+		return hasAggregate(ex.Expr)
+	case *ast.CastExpr:
+		return hasAggregate(ex.Expr)
+	case *ast.CaseExpr:
+		if hasAggregate(ex.Operand) || hasAggregate(ex.Else) {
+			return true
+		}
+		for _, w := range ex.Whens {
+			if hasAggregate(w.Cond) || hasAggregate(w.Result) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// renderValuesList renders an oversized IN list as a VALUES table.
+func (r *dialectRenderer) renderValuesList(e *ast.InExpr) string {
+	if r.inList.Strategy == InListSplit {
+		r.warn(WarnInListNotSplit, e.TokPos, "IN list of %d elements cannot be split into separate executions; rewritten as a VALUES join", len(e.List))
+	}
+	row := r.valuesRowStart()
+	var b strings.Builder
+	b.WriteString("VALUES ")
+	for i, it := range e.List {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(row)
+		b.WriteString(r.renderExpr(it))
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+// JoinInLists rewrites IN lists of more than minSize integer literals,
+// which ORMs generate by the thousand and planners handle poorly, into
+// VALUES tables the database can hash. A list that is a top-level AND term
+// of a SELECT's WHERE becomes a join against its distinct values:
+//
+//	SELECT * FROM orders WHERE status = 'open' AND id IN (1, 2, ...)
+//	SELECT * FROM orders JOIN (VALUES (1), (2), ...) in_list (v) ON id = in_list.v WHERE status = 'open'
+//
+// Other lists, under NOT, OR or in UPDATE and DELETE, and lists in a
+// SELECT * that the join's column would widen, become a semi-join,
+// id IN (VALUES (1), (2), ...). Lists of strings or placeholders are left
+// alone: PostgreSQL types them as text inside VALUES, which no longer
+// compares with dates or numbers. MySQL reads VALUES tables from 8.0.19,
+// as VALUES ROW(1), ...; see InListPolicy for rendering only.
+// The statements of stmts are replaced by rewritten copies, and stmts is
+// returned.
+func JoinInLists(stmts []Statement, minSize int) []Statement {
+	for _, stmt := range detach(stmts) {
+		joinInLists(stmt, minSize)
+	}
+	return stmts
+}
+
+// JoinInListsPass rewrites IN lists with JoinInLists.
+func JoinInListsPass(minSize int) Pass {
+	return NewPass("join-in-lists", func(_ *PipelineContext, stmts []Statement) ([]Statement, error) {
+		return JoinInLists(stmts, minSize), nil
+	})
+}
+
+// joinInLists rewrites the lists of stmt and returns how many it
+// rewrote.
+func joinInLists(stmt Statement, minSize int) int {
+	n := 0
+	for _, s := range selectBlocks(stmt) {
+		if s.Where == nil || slices.ContainsFunc(s.Columns, func(c ast.SelectColumn) bool { return c.Star }) {
+			continue
+		}
+		terms := andTerms(s.Where, nil)
+		joined := false
+		for i, term := range terms {
+			in, ok := term.(*ast.InExpr)
+			if !ok || in.Not || !intList(in, minSize) {
+				continue
+			}
+			alias, col := inListNames(stmt)
+			values := &ast.SubqueryTable{
+				Subq:    &ast.SelectStmt{Values: distinctRows(in.List), TokPos: in.TokPos},
+				Alias:   &ast.Ident{Unquoted: alias},
+				Columns: []*ast.Ident{{Unquoted: col}},
+				TokPos:  in.TokPos,
+			}
+			eq := &ast.BinaryExpr{
+				Left:   in.Expr,
+				Right:  &ast.QualifiedIdent{Parts: []*ast.Ident{{Unquoted: alias}, {Unquoted: col}}},
+				Op:     lexer.EQ,
+				TokPos: in.TokPos,
+			}
+			if len(s.From) == 1 {
+				s.From[0] = &ast.JoinTable{Left: s.From[0], Right: values, Kind: ast.InnerJoin, On: eq, TokPos: in.TokPos}
+				terms[i] = nil
+			} else {
+				// ON could not see the other FROM items, so the join
+				// condition stays in WHERE.
+				s.From = append(s.From, values)
+				terms[i] = eq
+			}
+			joined = true
+			n++
+		}
+		if joined {
+			s.Where = conjoin(terms...)
+		}
+	}
+	a := &auditor{
+		visit: func(string, string, accessKind) {},
+		node: func(node ast.Node, _ int) {
+			if in, ok := node.(*ast.InExpr); ok && intList(in, minSize) {
+				rows := make([][]ast.Expr, len(in.List))
+				for i, e := range in.List {
+					rows[i] = []ast.Expr{e}
+				}
+				in.Subq, in.List = &ast.SelectStmt{Values: rows, TokPos: in.TokPos}, nil
+				n++
+			}
+		},
+	}
+	a.statement(stmt)
+	return n
+}
+
+// countIntLists returns how many lists of stmt JoinInLists would rewrite.
+func countIntLists(stmt Statement, minSize int) int {
+	n := 0
+	a := &auditor{
+		visit: func(string, string, accessKind) {},
+		node: func(node ast.Node, _ int) {
+			if in, ok := node.(*ast.InExpr); ok && intList(in, minSize) {
+				n++
+			}
+		},
+	}
+	a.statement(stmt)
+	return n
+}
+
+// intList reports whether in compares with more than minSize integer
+// literals.
+func intList(in *ast.InExpr, minSize int) bool {
+	if in.Subq != nil || len(in.List) <= minSize {
+		return false
+	}
+	for _, e := range in.List {
+		if lit, ok := e.(*ast.Literal); !ok || lit.Kind != lexer.INT {
+			return false
+		}
+	}
+	return true
+}
+
+// distinctRows returns the integers of list as VALUES rows, each once, so
+// that a row joined with them is not repeated.
+func distinctRows(list []ast.Expr) [][]ast.Expr {
+	seen := map[string]bool{}
+	var rows [][]ast.Expr
+	for _, e := range list {
+		key := string(e.(*ast.Literal).Raw)
+		if n, err := strconv.ParseInt(key, 10, 64); err == nil {
+			key = strconv.FormatInt(n, 10)
+		}
+		if !seen[key] {
+			seen[key] = true
+			rows = append(rows, []ast.Expr{e})
+		}
+	}
+	return rows
+}
+
+// inListNames picks the alias and column of a VALUES table joined into
+// stmt, in_list and v unless stmt already uses those names.
+func inListNames(stmt Statement) (alias, col string) {
+	text, _ := newDialectRenderer(ConvertOptions{}).renderStatement(stmt)
+	text = strings.ToLower(text)
+	for i := 1; ; i++ {
+		alias, col = "in_list", "v"
+		if i > 1 {
+			alias, col = alias+strconv.Itoa(i), col+strconv.Itoa(i)
+		}
+		if !strings.Contains(text, `"`+alias+`"`) && !strings.Contains(text, `"`+col+`"`) {
+			return alias, col
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package sqlparser_test
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestConvertInListValues(t *testing.T) {
+	policy := sqlparser.InListPolicy{MaxSize: 2, Strategy: sqlparser.InListValues}
+	in := `SELECT id FROM t WHERE id IN (1, 2, 3) AND k IN (4, 5)`
+	out, _, err := sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres, InList: policy})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if want := `SELECT "id" FROM "t" WHERE ("id" IN (VALUES (1), (2), (3)) AND "k" IN (4, 5))`; out != want {
+		t.Fatalf("unexpected output:\n got %s\nwant %s", out, want)
+	}
+	out, _, err = sqlparser.ConvertDialectWithOptions(in, sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL, InList: policy})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if !strings.Contains(out, "IN (VALUES ROW(1), ROW(2), ROW(3))") {
+		t.Fatalf("expected mysql row constructors, got: %s", out)
+	}
+}
+
+func TestPlanInListSplit(t *testing.T) {
+	plan, warnings, err := sqlparser.PlanInList(
+		`SELECT id FROM t WHERE status = ? AND id IN (?, ?, ?, ?, ?)`,
+		[]any{"active", 1, 2, 3, 4, 5},
+		sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres, InList: sqlparser.InListPolicy{MaxSize: 2, Strategy: sqlparser.InListSplit}},
+	)
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("expected no warnings, got %#v", warnings)
+	}
+	want := []sqlparser.PlannedStatement{
+		{SQL: `SELECT "id" FROM "t" WHERE (("status" = $1) AND "id" IN ($2, $3))`, Args: []any{"active", 1, 2}},
+		{SQL: `SELECT "id" FROM "t" WHERE (("status" = $1) AND "id" IN ($2, $3))`, Args: []any{"active", 3, 4}},
+		{SQL: `SELECT "id" FROM "t" WHERE (("status" = $1) AND "id" IN ($2))`, Args: []any{"active", 5}},
+	}
+	if !reflect.DeepEqual(plan, want) {
+		t.Fatalf("unexpected plan:\n got %#v\nwant %#v", plan, want)
+	}
+}
+
+func TestPlanInListFallsBackToValues(t *testing.T) {
+	plan, warnings, err := sqlparser.PlanInList(
+		`SELECT COUNT(*) FROM t WHERE id IN (?, ?, ?); DELETE FROM t WHERE id IN ($3, $2, $1)`,
+		[]any{1, 2, 3},
+		sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres, InList: sqlparser.InListPolicy{MaxSize: 2, Strategy: sqlparser.InListSplit}},
+	)
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	if len(plan) != 3 {
+		t.Fatalf("expected 1 aggregate execution and 2 delete chunks, got %#v", plan)
+	}
+	if plan[0].SQL != `SELECT COUNT(*) FROM "t" WHERE "id" IN (VALUES ($1), ($2), ($3))` {
+		t.Fatalf("unexpected aggregate rewrite: %s", plan[0].SQL)
+	}
+	if !reflect.DeepEqual(plan[1].Args, []any{3, 2}) || !reflect.DeepEqual(plan[2].Args, []any{1}) {
+		t.Fatalf("numbered placeholders mapped wrong: %#v", plan)
+	}
+	if len(warnings) != 1 || warnings[0].Code != sqlparser.WarnInListNotSplit {
+		t.Fatalf("expected %s warning, got %#v", sqlparser.WarnInListNotSplit, warnings)
+	}
+}
+
+func TestPlanInListNamedParams(t *testing.T) {
+	_, _, err := sqlparser.PlanInList(`SELECT id FROM t WHERE id IN (:a, :b)`, []any{1, 2},
+		sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL})
+	if err == nil {
+		t.Fatalf("expected named parameters to be rejected when args are given")
+	}
+}
+
+func TestJoinInLists(t *testing.T) {
+	tests := []struct{ src, want string }{
+		{"SELECT id FROM orders WHERE status = 'open' AND id IN (3, 1, 2, 3)",
+			`SELECT "id" FROM "orders" JOIN (VALUES (3), (1), (2)) "in_list" ("v") ON ("id" = "in_list"."v") WHERE ("status" = 'open')`},
+		{"SELECT o.id FROM orders o, users u WHERE u.id = o.uid AND o.id IN (1, 2, 3)",
+			`SELECT "o"."id" FROM "orders" "o", "users" "u", (VALUES (1), (2), (3)) "in_list" ("v") WHERE (("u"."id" = "o"."uid") AND ("o"."id" = "in_list"."v"))`},
+		{"SELECT v FROM t WHERE v IN (1, 2, 3)",
+			`SELECT "v" FROM "t" JOIN (VALUES (1), (2), (3)) "in_list2" ("v2") ON ("v" = "in_list2"."v2")`},
+		// Semi-joins.
+		{"SELECT * FROM t WHERE id IN (1, 2, 3)", `SELECT * FROM "t" WHERE "id" IN (VALUES (1), (2), (3))`},
+		{"SELECT id FROM t WHERE id NOT IN (1, 2, 3) OR k IN (4, 5, 6)",
+			`SELECT "id" FROM "t" WHERE ("id" NOT IN (VALUES (1), (2), (3)) OR "k" IN (VALUES (4), (5), (6)))`},
+		{"DELETE FROM t WHERE id IN (1, 2, 3)", `DELETE FROM "t" WHERE "id" IN (VALUES (1), (2), (3))`},
+		// Left alone.
+		{"SELECT id FROM t WHERE id IN (1, 2)", `SELECT "id" FROM "t" WHERE "id" IN (1, 2)`},
+		{"SELECT id FROM t WHERE d IN ('2024-01-01', '2024-01-02', '2024-01-03')",
+			`SELECT "id" FROM "t" WHERE "d" IN ('2024-01-01', '2024-01-02', '2024-01-03')`},
+		{"SELECT id FROM t WHERE id IN (?, ?, ?)", `SELECT "id" FROM "t" WHERE "id" IN ($1, $2, $3)`},
+	}
+	for _, tt := range tests {
+		stmts, err := sqlparser.ParseStatements(tt.src)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.src, err)
+		}
+		got, _, err := sqlparser.ConvertStatements(sqlparser.JoinInLists(stmts, 2), sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s\ngot  %s\nwant %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestJoinInListsSurvivesGC(t *testing.T) {
+	checkRewriteSurvivesGC(t, "SELECT a FROM t WHERE b = 1 AND id IN (1, 2, 3, 4, 5); DELETE FROM t WHERE id IN (1, 2, 3)", func(stmts []sqlparser.Statement) []sqlparser.Statement {
+		return sqlparser.JoinInLists(stmts, 2)
+	})
+}
+
+func TestOptimizeSQLForDialectInLists(t *testing.T) {
+	ids := make([]string, 1001)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+	}
+	in := "SELECT name FROM users WHERE id IN (" + strings.Join(ids, ", ") + ")"
+
+	out, err := sqlparser.OptimizeSQLForDialect(in, sqlparser.DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Rewrites) != 1 || out.Rewrites[0].Rule != "join-in-lists" {
+		t.Fatalf("rewrites: %+v", out.Rewrites)
+	}
+	if want := `SELECT "name" FROM "users" JOIN (VALUES (0), (1), `; !strings.HasPrefix(out.OptimizedSQL, want) || out.Rewrites[0].After != out.OptimizedSQL {
+		t.Errorf("optimized SQL: %.80s", out.OptimizedSQL)
+	}
+
+	out, err = sqlparser.OptimizeSQLForDialect(in, sqlparser.DialectMySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Rewrites) != 0 || !strings.Contains(out.OptimizedSQL, "IN (0, 1, ") {
+		t.Errorf("mysql rewrites %+v, SQL %.80s", out.Rewrites, out.OptimizedSQL)
+	}
+	if !slices.ContainsFunc(out.Actions, func(a string) bool { return strings.Contains(a, "CREATE TEMPORARY TABLE") }) {
+		t.Errorf("no temporary table guidance in %q", out.Actions)
+	}
+}
@@ -0,0 +1,199 @@
+package sqlparser
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// SchemaFormat selects the document JSONSchema produces.
+type SchemaFormat uint8
+
+const (
+	// JSONSchemaDraft2020 emits a JSON Schema 2020-12 document with one
+	// entry per table under $defs.
+	JSONSchemaDraft2020 SchemaFormat = iota
+	// OpenAPI31 emits {"components": {"schemas": ...}} for OpenAPI 3.1,
+	// whose schemas are JSON Schema 2020-12; nullability uses type arrays.
+	OpenAPI31
+	// OpenAPI30 emits OpenAPI 3.0 component schemas, marking nullable
+	// columns with "nullable": true.
+	OpenAPI30
+)
+
+// JSONSchema describes the row shape of every table as a JSON document in
+// the given format. Each table becomes an object schema keyed by its name
+// (namespace.name when qualified); NOT NULL columns are required and
+// nullable columns also accept null. Output is deterministic.
+//
+// DECIMAL and NUMERIC map to strings with format "decimal" because JSON
+// numbers cannot carry their precision; TINYINT(1) and BIT(1) map to
+// booleans, following the MySQL convention.
+func (s *Schema) JSONSchema(format SchemaFormat) ([]byte, error) {
+	defs := make(map[string]any, len(s.Tables))
+	for _, t := range s.Tables {
+		defs[t.schemaKey()] = t.rowSchema(format)
+	}
+	var doc map[string]any
+	switch format {
+	case OpenAPI30, OpenAPI31:
+		doc = map[string]any{"components": map[string]any{"schemas": defs}}
+	default:
+		doc = map[string]any{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"$defs":   defs,
+		}
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func (t *Table) schemaKey() string {
+	if t.Namespace != "" {
+		return t.Namespace + "." + t.Name
+	}
+	return t.Name
+}
+
+func (t *Table) rowSchema(format SchemaFormat) map[string]any {
+	props := make(map[string]any, len(t.Columns))
+	required := []string{}
+	for _, c := range t.Columns {
+		props[c.Name] = c.jsonSchema(format, t.dialect)
+		if !c.Nullable {
+			required = append(required, c.Name)
+		}
+	}
+	return map[string]any{
+		"title":                t.schemaKey(),
+		"type":                 "object",
+		"properties":           props,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+func (c *Column) jsonSchema(format SchemaFormat, dialect Dialect) map[string]any {
+	m := map[string]any{}
+	typ := ""
+	switch strings.ToLower(c.Type) {
+	case "tinyint", "bit":
+		if c.Precision == 1 {
+			typ = "boolean"
+			break
+		}
+		typ = "integer"
+	case "smallint", "mediumint", "int", "integer", "int2", "int4", "serial", "smallserial", "year":
+		typ = "integer"
+		m["format"] = "int32"
+	case "bigint", "int8", "bigserial":
+		typ = "integer"
+		m["format"] = "int64"
+	case "bool", "boolean":
+		typ = "boolean"
+	case "float", "real", "float4":
+		typ = "number"
+		m["format"] = "float"
+	case "double", "float8":
+		typ = "number"
+		m["format"] = "double"
+	case "decimal", "numeric", "dec", "money":
+		typ = "string"
+		m["format"] = "decimal"
+	case "char", "varchar", "character", "nchar", "nvarchar", "varchar2":
+		typ = "string"
+		if c.Precision > 0 {
+			m["maxLength"] = c.Precision
+		}
+	case "text", "tinytext", "mediumtext", "longtext", "citext", "clob", "string":
+		typ = "string"
+	case "uuid":
+		typ = "string"
+		m["format"] = "uuid"
+	case "date":
+		typ = "string"
+		m["format"] = "date"
+	case "datetime", "timestamp", "timestamptz":
+		typ = "string"
+		m["format"] = "date-time"
+	case "time", "timetz":
+		typ = "string"
+		m["format"] = "time"
+	case "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob", "bytea":
+		typ = "string"
+		if format == JSONSchemaDraft2020 {
+			m["contentEncoding"] = "base64"
+		} else {
+			m["format"] = "byte"
+		}
+	case "enum":
+		typ = "string"
+		enum := make([]any, len(c.EnumValues))
+		for i, v := range c.EnumValues {
+			enum[i] = v
+		}
+		if c.Nullable && format != OpenAPI30 {
+			enum = append(enum, nil)
+		}
+		m["enum"] = enum
+	case "set":
+		typ = "string"
+	case "json", "jsonb":
+		// Any JSON value.
+	default:
+		typ = "string"
+	}
+	if typ == "integer" && c.Unsigned {
+		m["minimum"] = 0
+	}
+	if typ != "" {
+		switch {
+		case !c.Nullable:
+			m["type"] = typ
+		case format == OpenAPI30:
+			m["type"] = typ
+			m["nullable"] = true
+		default:
+			m["type"] = []string{typ, "null"}
+		}
+	}
+	if c.Comment != "" {
+		m["description"] = c.Comment
+	}
+	if v, ok := literalJSON(c.Default, dialect); ok {
+		if n, isNum := v.(json.Number); isNum && typ == "boolean" {
+			v = n != "0"
+		}
+		m["default"] = v
+	}
+	if c.AutoIncrement || c.Generated {
+		m["readOnly"] = true
+	}
+	return m
+}
+
+// literalJSON converts a constant DEFAULT expression, written in dialect,
+// to its JSON value.
+func literalJSON(e ast.Expr, dialect Dialect) (any, bool) {
+	switch ex := e.(type) {
+	case *ast.NullLit:
+		return nil, true
+	case *ast.UnaryExpr:
+		if lit, ok := ex.Expr.(*ast.Literal); ok && ex.Op == lexer.MINUS && (lit.Kind == lexer.INT || lit.Kind == lexer.FLOAT) {
+			return json.Number("-" + string(lit.Raw)), true
+		}
+	case *ast.Literal:
+		switch ex.Kind {
+		case lexer.STRING:
+			return unquoteString(ex.Raw, dialect), true
+		case lexer.INT, lexer.FLOAT:
+			return json.Number(ex.Raw), true
+		case lexer.TRUE_KW:
+			return true, true
+		case lexer.FALSE_KW:
+			return false, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,70 @@
+package sqlparser_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestSchemaJSONSchema(t *testing.T) {
+	s, err := sqlparser.BuildSchema(`CREATE TABLE users (
+		id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		email VARCHAR(255) NOT NULL COMMENT 'login address',
+		active TINYINT(1) NOT NULL DEFAULT 1,
+		status ENUM('new', 'gone'),
+		created_at TIMESTAMP NULL
+	)`)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	out, err := s.JSONSchema(sqlparser.JSONSchemaDraft2020)
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	var doc struct {
+		Schema string                     `json:"$schema"`
+		Defs   map[string]json.RawMessage `json:"$defs"`
+	}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	var users map[string]any
+	if err := json.Unmarshal(doc.Defs["users"], &users); err != nil {
+		t.Fatalf("users schema missing: %s", out)
+	}
+	if !reflect.DeepEqual(users["required"], []any{"id", "email", "active"}) {
+		t.Fatalf("unexpected required list %v", users["required"])
+	}
+	props := users["properties"].(map[string]any)
+	want := map[string]any{
+		"id":         map[string]any{"type": "integer", "format": "int64", "minimum": float64(0), "readOnly": true},
+		"email":      map[string]any{"type": "string", "maxLength": float64(255), "description": "login address"},
+		"active":     map[string]any{"type": "boolean", "default": true},
+		"status":     map[string]any{"type": []any{"string", "null"}, "enum": []any{"new", "gone", nil}},
+		"created_at": map[string]any{"type": []any{"string", "null"}, "format": "date-time"},
+	}
+	if !reflect.DeepEqual(props, want) {
+		t.Fatalf("unexpected properties:\n got %v\nwant %v", props, want)
+	}
+
+	out, err = s.JSONSchema(sqlparser.OpenAPI30)
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	var api struct {
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]any `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(out, &api); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	created := api.Components.Schemas["users"].Properties["created_at"]
+	if created["type"] != "string" || created["nullable"] != true {
+		t.Fatalf("expected OpenAPI 3.0 nullable string, got %v", created)
+	}
+}
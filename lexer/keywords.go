@@ -1,5 +1,7 @@
 package lexer
 
+import "strings"
+
 // keywords maps lowercase SQL keywords to their token types.
 // Uses a hash-map approach with FNV-1a for O(1) lookup with zero allocations.
 
@@ -16,6 +18,9 @@ const kwMapMask = kwMapSize - 1
 // Each slot holds a small bucket (typically 0-1 entries after hashing).
 var kwMap [kwMapSize][]kwEntry
 
+// keywordNames gives TokenType.String the spelling of keyword tokens.
+var keywordNames = map[TokenType]string{}
+
 // fnv1a computes FNV-1a hash of bytes.
 func fnv1a(b []byte) uint32 {
 	h := uint32(2166136261)
@@ -179,6 +184,9 @@ func init() {
 	for _, e := range words {
 		h := fnv1aStr(e.word) & kwMapMask
 		kwMap[h] = append(kwMap[h], e)
+		if _, ok := keywordNames[e.tok]; !ok {
+			keywordNames[e.tok] = strings.ToUpper(e.word)
+		}
 	}
 }
 
@@ -215,3 +223,9 @@ func bytesEqualString(b []byte, s string) bool {
 	}
 	return true
 }
+
+// IsKeyword reports whether word, in any case, is one of the keywords the
+// lexer gives a token of its own rather than IDENT.
+func IsKeyword(word string) bool {
+	return lookupKeyword([]byte(strings.ToLower(word))) != IDENT
+}
@@ -1,6 +1,9 @@
 package lexer
 
-import "unsafe"
+import (
+	"bytes"
+	"unsafe"
+)
 
 // Token represents a single SQL token. It holds a slice into the original
 // input to avoid copying bytes. All string data is borrowed from the source.
@@ -20,13 +23,41 @@ type Token struct {
 // Line/column tracking is intentionally omitted from the hot path.
 // Use ComputeLineCol(pos) when needed (e.g. error reporting).
 type Lexer struct {
-	src []byte
-	pos int
+	src  []byte
+	pos  int
+	opts Options
+	// inVersion is set between the opening and closing markers of a
+	// /*! ... */ comment whose body is being lexed as SQL.
+	inVersion bool
+	// delim is the statement delimiter set by a DELIMITER line, or nil
+	// while it is the semicolon. stmtStart is the offset just past the
+	// last delimiter, where a DELIMITER line may start.
+	delim     []byte
+	stmtStart int
 
 	// scratch is reused to build lowercased keyword candidates.
 	scratch [64]byte
 }
 
+// Options controls which trivia tokens the Lexer reports. The zero value
+// skips comments and whitespace, which is what the parser expects.
+type Options struct {
+	// EmitComments reports --, # and /* */ comments as COMMENT tokens.
+	EmitComments bool
+	// EmitWhitespace reports each run of spaces, tabs and line breaks as a
+	// single WHITESPACE token.
+	EmitWhitespace bool
+	// ExecuteVersionComments lexes the body of MySQL version comments
+	// (/*!40101 ... */, /*M!100100 ... */) as SQL, the way mysql does, and
+	// drops only their markers. Otherwise they are comments like any other.
+	ExecuteVersionComments bool
+	// StandardStrings reads backslashes in quoted strings and names as
+	// ordinary characters, as PostgreSQL and SQLite do, so 'a\' is a
+	// whole string. Only E'...' strings take backslash escapes. Otherwise
+	// a backslash escapes the next character, as in MySQL.
+	StandardStrings bool
+}
+
 // New creates a Lexer for the given SQL source.
 func New(src []byte) *Lexer {
 	return &Lexer{src: src}
@@ -38,22 +69,52 @@ func NewString(src string) *Lexer {
 	return &Lexer{src: b}
 }
 
+// NewWithOptions creates a Lexer for the given SQL source using opts.
+func NewWithOptions(src []byte, opts Options) *Lexer {
+	return &Lexer{src: src, opts: opts}
+}
+
+// SetOptions changes which trivia tokens the Lexer reports from now on.
+func (l *Lexer) SetOptions(opts Options) { l.opts = opts }
+
 // Init initialises a Lexer in-place (for embedded use, avoids heap alloc).
 func (l *Lexer) Init(src []byte) {
 	l.src = src
 	l.pos = 0
+	l.inVersion = false
+	l.delim = nil
+	l.stmtStart = 0
 }
 
 // InitString initialises a Lexer in-place from a string.
 func (l *Lexer) InitString(src string) {
 	l.src = unsafe.Slice(unsafe.StringData(src), len(src))
 	l.pos = 0
+	l.inVersion = false
+	l.delim = nil
+	l.stmtStart = 0
 }
 
 // Reset reuses the lexer with new source, avoiding allocating a new lexer.
 func (l *Lexer) Reset(src []byte) {
 	l.src = src
 	l.pos = 0
+	l.inVersion = false
+	l.delim = nil
+	l.stmtStart = 0
+}
+
+// Delimiter returns the statement delimiter set by the last DELIMITER
+// line, or nil while it is the semicolon.
+func (l *Lexer) Delimiter() []byte { return l.delim }
+
+// SetDelimiter sets the statement delimiter as a DELIMITER line would. A
+// nil or ";" delimiter restores the semicolon.
+func (l *Lexer) SetDelimiter(d []byte) {
+	if string(d) == ";" {
+		d = nil
+	}
+	l.delim = d
 }
 
 // Source returns the underlying source bytes.
@@ -136,12 +197,25 @@ func (l *Lexer) Next() Token {
 		start := pos
 		b := src[pos]
 
+		if l.delim != nil && b == l.delim[0] && bytes.HasPrefix(src[pos:], l.delim) {
+			// The custom delimiter ends the statement like a semicolon.
+			l.pos = pos + len(l.delim)
+			l.stmtStart = l.pos
+			return Token{Type: SEMICOLON, Raw: src[pos:l.pos], Pos: int32(pos)}
+		}
+
 		switch charClass[b] {
 		case cNewL:
+			if l.opts.EmitWhitespace {
+				return l.lexWhitespace(start)
+			}
 			pos++
 			continue
 
 		case cCR:
+			if l.opts.EmitWhitespace {
+				return l.lexWhitespace(start)
+			}
 			pos++
 			if pos < n && src[pos] == '\n' {
 				pos++
@@ -149,6 +223,9 @@ func (l *Lexer) Next() Token {
 			continue
 
 		case cSpace:
+			if l.opts.EmitWhitespace {
+				return l.lexWhitespace(start)
+			}
 			pos++
 			for pos < n && isSpaceTab[src[pos]] {
 				pos++
@@ -162,6 +239,9 @@ func (l *Lexer) Next() Token {
 				for pos < n && src[pos] != '\n' {
 					pos++
 				}
+				if l.opts.EmitComments {
+					return l.comment(start, pos)
+				}
 				continue
 			}
 			// Might be -> or ->> or just -
@@ -178,9 +258,19 @@ func (l *Lexer) Next() Token {
 			for pos < n && src[pos] != '\n' {
 				pos++
 			}
+			if l.opts.EmitComments {
+				return l.comment(start, pos)
+			}
 			continue
 
 		case cSlash:
+			if l.opts.ExecuteVersionComments {
+				if end := versionCommentStart(src, pos); end > pos {
+					pos = end
+					l.inVersion = true
+					continue
+				}
+			}
 			if pos+1 < n && src[pos+1] == '*' {
 				// Block comment /* ... */
 				pos += 2
@@ -191,6 +281,13 @@ func (l *Lexer) Next() Token {
 					}
 					pos++
 				}
+				if pos < start+4 || src[pos-2] != '*' || src[pos-1] != '/' {
+					// Unterminated: the comment runs to the end of input.
+					pos = n
+				}
+				if l.opts.EmitComments {
+					return l.comment(start, pos)
+				}
 				continue
 			}
 			l.pos = pos
@@ -215,18 +312,19 @@ func (l *Lexer) Next() Token {
 
 		case cSQ:
 			l.pos = pos
-			return l.lexQuoted(start, '\'', STRING)
+			return l.lexQuoted(start, '\'', STRING, !l.opts.StandardStrings)
 
 		case cDQ:
 			l.pos = pos
-			return l.lexQuoted(start, '"', DQUOTE)
+			return l.lexQuoted(start, '"', DQUOTE, !l.opts.StandardStrings)
 
 		case cBT:
 			l.pos = pos
-			return l.lexQuoted(start, '`', BACKTICK)
+			return l.lexQuoted(start, '`', BACKTICK, false)
 
 		case cAlpha:
 			// Check for hex/bit string literals: x'...' X'...' b'...' B'...'
+			// and escape strings: e'...' E'...'
 			if pos+1 < n && src[pos+1] == '\'' {
 				if b == 'x' || b == 'X' {
 					l.pos = pos
@@ -236,11 +334,31 @@ func (l *Lexer) Next() Token {
 					l.pos = pos
 					return l.lexBitLit(start)
 				}
+				if b == 'e' || b == 'E' {
+					// PostgreSQL escape string, E'...'.
+					l.pos = pos + 1
+					return l.lexQuoted(start, '\'', STRING, true)
+				}
+			}
+			if b == 'd' || b == 'D' {
+				if tok, ok := l.lexDelimiter(start); ok {
+					return tok
+				}
+			}
+			if (b == 'u' || b == 'U') && pos+2 < n && src[pos+1] == '&' && src[pos+2] == '"' {
+				// PostgreSQL Unicode identifier, U&"...".
+				l.pos = pos + 2
+				return l.lexQuoted(start, '"', DQUOTE, !l.opts.StandardStrings)
 			}
 			l.pos = pos
 			return l.lexIdent(start)
 
 		default:
+			if b == '*' && l.inVersion && pos+1 < n && src[pos+1] == '/' {
+				pos += 2
+				l.inVersion = false
+				continue
+			}
 			l.pos = pos
 			return l.lexPunct(start)
 		}
@@ -249,6 +367,45 @@ func (l *Lexer) Next() Token {
 	return Token{Type: EOF, Pos: int32(pos)}
 }
 
+// versionCommentStart returns the offset just past the opening marker and
+// version number of a version comment starting at pos, or pos if there is
+// none.
+func versionCommentStart(src []byte, pos int) int {
+	i := pos + 2
+	if i < len(src) && src[i] == 'M' {
+		i++ // MariaDB
+	}
+	if i+1 > len(src) || src[pos+1] != '*' || src[i] != '!' {
+		return pos
+	}
+	i++
+	for end := i + 6; i < end && i < len(src) && src[i] >= '0' && src[i] <= '9'; i++ {
+	}
+	return i
+}
+
+// lexWhitespace scans a run of spaces, tabs and line breaks.
+func (l *Lexer) lexWhitespace(start int) Token {
+	src := l.src
+	pos := start
+	for pos < len(src) {
+		switch charClass[src[pos]] {
+		case cSpace, cNewL, cCR:
+			pos++
+			continue
+		}
+		break
+	}
+	l.pos = pos
+	return Token{Type: WHITESPACE, Raw: src[start:pos], Pos: int32(start)}
+}
+
+// comment returns the comment token spanning src[start:end].
+func (l *Lexer) comment(start, end int) Token {
+	l.pos = end
+	return Token{Type: COMMENT, Raw: l.src[start:end], Pos: int32(start)}
+}
+
 // lexIdent scans an identifier or keyword.
 func (l *Lexer) lexIdent(start int) Token {
 	src := l.src
@@ -257,6 +414,12 @@ func (l *Lexer) lexIdent(start int) Token {
 	for pos < n && identContTable[src[pos]] {
 		pos++
 	}
+	if l.delim != nil {
+		// END$$ is END followed by the delimiter.
+		if i := bytes.Index(src[start:pos], l.delim); i > 0 {
+			pos = start + i
+		}
+	}
 	l.pos = pos
 	raw := src[start:pos]
 
@@ -279,6 +442,45 @@ func (l *Lexer) lexIdent(start int) Token {
 	return Token{Type: tok, Raw: raw, Pos: int32(start)}
 }
 
+// atStatementStart reports whether only whitespace and comments separate
+// pos from the last delimiter.
+func (l *Lexer) atStatementStart(pos int) bool {
+	if pos < l.stmtStart {
+		return false
+	}
+	between := Lexer{src: l.src[l.stmtStart:pos]}
+	return between.Next().Type == EOF
+}
+
+// lexDelimiter scans a mysql client DELIMITER line, such as DELIMITER $$,
+// and makes its argument the statement delimiter. Between DELIMITER $$ and
+// DELIMITER ; a stored routine can use semicolons in its body: they lex as
+// SEMICOLON tokens of their own, which the parser tells apart from the
+// delimiter by Raw.
+func (l *Lexer) lexDelimiter(start int) (Token, bool) {
+	src := l.src
+	const word = "delimiter"
+	pos := start + len(word)
+	if pos >= len(src) || !isSpaceTab[src[pos]] || !bytes.EqualFold(src[start:pos], []byte(word)) ||
+		!l.atStatementStart(start) {
+		return Token{}, false
+	}
+	for pos < len(src) && isSpaceTab[src[pos]] {
+		pos++
+	}
+	arg := pos
+	for pos < len(src) && !isSpaceTab[src[pos]] && src[pos] != '\n' && src[pos] != '\r' {
+		pos++
+	}
+	if pos == arg {
+		return Token{}, false
+	}
+	l.SetDelimiter(src[arg:pos])
+	l.pos = pos
+	l.stmtStart = pos
+	return Token{Type: DELIMITER, Raw: src[start:pos], Pos: int32(start)}, true
+}
+
 // lexNumber scans integer or float literals.
 func (l *Lexer) lexNumber(start int) Token {
 	src := l.src
@@ -310,8 +512,9 @@ func (l *Lexer) lexNumber(start int) Token {
 	return Token{Type: TokenType(typ), Raw: src[start:pos], Pos: int32(start)}
 }
 
-// lexQuoted scans a single, double, or backtick quoted string.
-func (l *Lexer) lexQuoted(start int, delim byte, typ TokenType) Token {
+// lexQuoted scans a single, double, or backtick quoted string, in which a
+// backslash escapes the next character when backslashes is set.
+func (l *Lexer) lexQuoted(start int, delim byte, typ TokenType, backslashes bool) Token {
 	src := l.src
 	pos := l.pos + 1 // skip opening delimiter
 	n := len(src)
@@ -326,7 +529,7 @@ func (l *Lexer) lexQuoted(start int, delim byte, typ TokenType) Token {
 			}
 			break
 		}
-		if c == '\\' && delim != '`' {
+		if c == '\\' && backslashes {
 			pos++
 			if pos < n {
 				pos++
@@ -366,7 +569,7 @@ func (l *Lexer) lexHex0x(start int) Token {
 
 func (l *Lexer) lexBitLit(start int) Token {
 	l.pos++ // skip b/B
-	return l.lexQuoted(l.pos-1, '\'', BITLIT)
+	return l.lexQuoted(l.pos-1, '\'', BITLIT, true)
 }
 
 // lexPunct handles single and multi-character punctuation/operators.
@@ -403,6 +606,9 @@ func (l *Lexer) lexPunct(start int) Token {
 		typ = COMMA
 	case ';':
 		typ = SEMICOLON
+		if l.delim == nil {
+			l.stmtStart = l.pos
+		}
 	case '*':
 		typ = STAR
 	case '%':
@@ -502,8 +708,11 @@ func (l *Lexer) lexPunct(start int) Token {
 			typ = DOT
 		}
 	case ':':
-		// named parameter :name
-		if p := peek(); isAlphaB(p) || p == '_' {
+		// named parameter :name, or PostgreSQL's :: cast
+		if p := peek(); p == ':' {
+			advance()
+			typ = DCOLON
+		} else if isAlphaB(p) || p == '_' {
 			for l.pos < len(src) && identContTable[src[l.pos]] {
 				advance()
 			}
@@ -584,8 +793,15 @@ func isHexDigit(c byte) bool {
 
 // Tokenize breaks SQL source into tokens. Provide a pre-allocated buf to avoid allocation.
 func Tokenize(src []byte, buf []Token) []Token {
+	return TokenizeWithOptions(src, buf, Options{})
+}
+
+// TokenizeWithOptions is Tokenize with explicit lexer options. With both
+// EmitComments and EmitWhitespace set, concatenating the Raw bytes of the
+// returned tokens reproduces src exactly.
+func TokenizeWithOptions(src []byte, buf []Token, opts Options) []Token {
 	buf = buf[:0]
-	l := Lexer{src: src}
+	l := Lexer{src: src, opts: opts}
 	for {
 		t := l.Next()
 		buf = append(buf, t)
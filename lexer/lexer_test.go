@@ -25,6 +25,7 @@ func TestLexerStrings(t *testing.T) {
 		{"'hello'", STRING, "'hello'"},
 		{"'it''s'", STRING, "'it''s'"},
 		{"'escape\\n'", STRING, "'escape\\n'"},
+		{"E'tab\\t\\''", STRING, "E'tab\\t\\''"},
 		{`"column"`, DQUOTE, `"column"`},
 		{"`table`", BACKTICK, "`table`"},
 	}
@@ -40,6 +41,24 @@ func TestLexerStrings(t *testing.T) {
 	}
 }
 
+func TestLexerStandardStrings(t *testing.T) {
+	tests := []struct {
+		input string
+		raw   string
+	}{
+		{`'a\' x`, `'a\'`},
+		{`'a\'' b' x`, `'a\'' b'`},
+		{`"c\" x`, `"c\"`},
+		{`E'a\'b' x`, `E'a\'b'`},
+	}
+	for _, tt := range tests {
+		l := NewWithOptions([]byte(tt.input), Options{StandardStrings: true})
+		if tok := l.Next(); string(tok.Raw) != tt.raw {
+			t.Errorf("input %q: expected raw %q, got %q", tt.input, tt.raw, tok.Raw)
+		}
+	}
+}
+
 func TestLexerNumbers(t *testing.T) {
 	tests := []struct {
 		input string
@@ -128,6 +147,16 @@ func TestLexerNamedParams(t *testing.T) {
 	}
 }
 
+func TestLexerCastOperator(t *testing.T) {
+	l := New([]byte("a::uuid, :b"))
+	expected := []TokenType{IDENT, DCOLON, IDENT, COMMA, NAMEDPARAM, EOF}
+	for i, exp := range expected {
+		if tok := l.Next(); tok.Type != exp {
+			t.Fatalf("token %d: expected %s, got %s (%q)", i, exp, tok.Type, tok.Raw)
+		}
+	}
+}
+
 func TestLexerComments(t *testing.T) {
 	// Single-line comment
 	l := New([]byte("SELECT -- comment\nid"))
@@ -282,6 +311,80 @@ func TestLexerDotDot(t *testing.T) {
 	}
 }
 
+func TestLexerEmitTrivia(t *testing.T) {
+	input := "SELECT a, -- note\n\tb /* x */ FROM t # tail"
+	toks := TokenizeWithOptions([]byte(input), nil, Options{EmitComments: true, EmitWhitespace: true})
+	var out []byte
+	var comments []string
+	for _, tok := range toks {
+		out = append(out, tok.Raw...)
+		if tok.Type == COMMENT {
+			comments = append(comments, string(tok.Raw))
+		}
+	}
+	if string(out) != input {
+		t.Fatalf("round trip mismatch: %q", out)
+	}
+	want := []string{"-- note", "/* x */", "# tail"}
+	if len(comments) != len(want) {
+		t.Fatalf("comments = %q, want %q", comments, want)
+	}
+	for i := range want {
+		if comments[i] != want[i] {
+			t.Fatalf("comment %d = %q, want %q", i, comments[i], want[i])
+		}
+	}
+}
+
+func TestLexerEmitCommentsOnly(t *testing.T) {
+	l := NewWithOptions([]byte("SELECT /* unterminated"), Options{EmitComments: true})
+	expected := []TokenType{SELECT, COMMENT, EOF}
+	for i, exp := range expected {
+		tok := l.Next()
+		if tok.Type != exp {
+			t.Fatalf("token %d: expected %s, got %s (%q)", i, exp, tok.Type, tok.Raw)
+		}
+	}
+}
+
+func TestLexerVersionComments(t *testing.T) {
+	input := "/*!40101 SET x = 1 */; SELECT /*M!100100 a, */ b /*! * */ FROM t /* c */"
+	toks := TokenizeWithOptions([]byte(input), nil, Options{ExecuteVersionComments: true})
+	expected := []TokenType{SET, IDENT, EQ, INT, SEMICOLON, SELECT, IDENT, COMMA, IDENT, STAR, FROM, IDENT, EOF}
+	if len(toks) != len(expected) {
+		t.Fatalf("expected %d tokens, got %d: %v", len(expected), len(toks), toks)
+	}
+	for i, exp := range expected {
+		if toks[i].Type != exp {
+			t.Fatalf("token %d: expected %s, got %s (%q)", i, exp, toks[i].Type, toks[i].Raw)
+		}
+	}
+
+	// Without the option a version comment is an ordinary comment.
+	toks = Tokenize([]byte(input), nil)
+	if toks[0].Type != SEMICOLON {
+		t.Fatalf("version comment should be skipped, got %s", toks[0].Type)
+	}
+}
+
+func TestLexerDelimiter(t *testing.T) {
+	input := "SELECT 1;\nDELIMITER $$\nCREATE PROCEDURE p() BEGIN SELECT 2; END$$\ndelimiter ;\nSELECT delimiter FROM t"
+	toks := Tokenize([]byte(input), nil)
+	expected := []TokenType{SELECT, INT, SEMICOLON, DELIMITER, CREATE, PROCEDURE, IDENT, LPAREN, RPAREN, IDENT,
+		SELECT, INT, SEMICOLON, END, SEMICOLON, DELIMITER, SELECT, IDENT, FROM, IDENT, EOF}
+	if len(toks) != len(expected) {
+		t.Fatalf("expected %d tokens, got %d: %v", len(expected), len(toks), toks)
+	}
+	for i, exp := range expected {
+		if toks[i].Type != exp {
+			t.Fatalf("token %d: expected %s, got %s (%q)", i, exp, toks[i].Type, toks[i].Raw)
+		}
+	}
+	if string(toks[3].Raw) != "DELIMITER $$" || string(toks[12].Raw) != ";" || string(toks[14].Raw) != "$$" {
+		t.Fatalf("unexpected raw tokens %q, %q, %q", toks[3].Raw, toks[12].Raw, toks[14].Raw)
+	}
+}
+
 // Benchmarks
 
 func BenchmarkLexerNext(b *testing.B) {
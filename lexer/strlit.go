@@ -0,0 +1,77 @@
+package lexer
+
+import "strings"
+
+// DecodeString returns the value of a string literal quoted with ' or ",
+// resolving doubled quotes and reading backslash escapes the way MySQL
+// does, or PostgreSQL for an E'...' literal, unless backslashes is false
+// and the literal is a standard string. ok is false for a literal it
+// cannot read and for escapes it does not know, such as PostgreSQL's
+// octal and Unicode ones, which are left to the source spelling.
+func DecodeString(raw []byte, backslashes bool) (string, bool) {
+	if len(raw) == 0 {
+		return "", false
+	}
+	escape := raw[0] == 'e' || raw[0] == 'E'
+	if escape {
+		raw = raw[1:]
+	}
+	if len(raw) < 2 || raw[0] != '\'' && (escape || raw[0] != '"') || raw[len(raw)-1] != raw[0] {
+		return "", false
+	}
+	q := raw[0]
+	body := raw[1 : len(raw)-1]
+	var b strings.Builder
+	b.Grow(len(body))
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == q && i+1 < len(body) && body[i+1] == q:
+			i++
+		case c == '\\' && i+1 < len(body) && (backslashes || escape):
+			i++
+			switch e := body[i]; e {
+			case '0':
+				if escape {
+					return "", false
+				}
+				c = 0
+			case 'b':
+				c = '\b'
+			case 'f':
+				if !escape {
+					c = 'f'
+					break
+				}
+				c = '\f'
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'Z':
+				if escape {
+					c = 'Z'
+					break
+				}
+				c = 0x1a
+			case '%', '_':
+				// MySQL keeps the backslash, for LIKE patterns.
+				if !escape {
+					b.WriteByte('\\')
+				}
+				c = e
+			case 'x', 'u', 'U', '1', '2', '3', '4', '5', '6', '7':
+				if escape {
+					return "", false
+				}
+				c = e
+			default:
+				c = e
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), true
+}
@@ -214,13 +214,20 @@ const (
 	VARBINARY
 	VARCHAR
 	YEAR
+
+	// Appended so the values above stay stable
+	DCOLON    // ::
+	DELIMITER // mysql client DELIMITER line
 )
 
 // String returns a human-readable representation of the token type.
 func (t TokenType) String() string {
-	if int(t) < len(tokenNames) {
+	if int(t) < len(tokenNames) && tokenNames[t] != "" {
 		return tokenNames[t]
 	}
+	if name, ok := keywordNames[t]; ok {
+		return name
+	}
 	return "UNKNOWN"
 }
 
@@ -229,6 +236,7 @@ var tokenNames = [...]string{
 	EOF:        "EOF",
 	COMMENT:    "COMMENT",
 	WHITESPACE: "WHITESPACE",
+	DELIMITER:  "DELIMITER",
 	IDENT:      "IDENT",
 	INT:        "INT",
 	FLOAT:      "FLOAT",
@@ -247,6 +255,7 @@ var tokenNames = [...]string{
 	COMMA:      ",",
 	SEMICOLON:  ";",
 	COLON:      ":",
+	DCOLON:     "::",
 	DOT:        ".",
 	DOTDOT:     "..",
 	STAR:       "*",
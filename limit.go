@@ -0,0 +1,99 @@
+package sqlparser
+
+import (
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// LimitStyle selects how ConvertDialectWithOptions spells the row limit of
+// a query.
+type LimitStyle uint8
+
+const (
+	// LimitKeep uses LIMIT ... OFFSET, except that postgres output keeps
+	// the FETCH FIRST spelling of the input.
+	LimitKeep LimitStyle = iota
+	// LimitFetch emits the standard OFFSET n ROWS FETCH NEXT m ROWS ONLY,
+	// which Oracle, SQL Server and DB2 accept in place of LIMIT.
+	LimitFetch
+	// LimitTop emits SQL Server's SELECT TOP (m) for a limit without an
+	// offset, and OFFSET ... FETCH otherwise.
+	LimitTop
+)
+
+// top reports whether l is rendered as TOP (m) after SELECT.
+func (r *dialectRenderer) top(l *ast.LimitClause) bool {
+	return r.limitStyle == LimitTop && l != nil && l.Count != nil && l.Offset == nil
+}
+
+// renderTop renders the TOP (m) that LimitTop puts after SELECT, or "".
+func (r *dialectRenderer) renderTop(l *ast.LimitClause) string {
+	if !r.top(l) {
+		return ""
+	}
+	top := "TOP (" + r.renderLimit(l.Count) + ") "
+	if l.WithTies {
+		top += "WITH TIES "
+	}
+	return top
+}
+
+// fetchOrder returns the ORDER BY that a query with limit l but no ORDER
+// BY of its own needs when LimitFetch or LimitTop spell l as OFFSET ...
+// FETCH, which SQL Server accepts only after one, or "". (SELECT NULL)
+// keeps the rows unordered; after UNION, where the keys must be result
+// columns, the first column orders them instead.
+func (r *dialectRenderer) fetchOrder(l *ast.LimitClause, setOp bool) string {
+	if r.limitStyle == LimitKeep || l == nil || r.top(l) || l.Count == nil && l.Offset == nil {
+		return ""
+	}
+	if setOp {
+		return " ORDER BY 1"
+	}
+	return " ORDER BY (SELECT NULL)"
+}
+
+// renderLimitClause renders the LIMIT, OFFSET or FETCH that ends a query.
+// Without a count, mysql and sqlite need a LIMIT before OFFSET, so they get
+// the largest count they accept.
+func (r *dialectRenderer) renderLimitClause(l *ast.LimitClause) string {
+	if r.top(l) {
+		return ""
+	}
+	var b strings.Builder
+	if r.limitStyle != LimitKeep || l.Fetch && (r.target == DialectPostgres || r.target == "") {
+		switch {
+		case l.Offset != nil:
+			b.WriteString(" OFFSET " + r.renderLimit(l.Offset) + " ROWS")
+		case r.limitStyle != LimitKeep && l.Count != nil:
+			// SQL Server reads FETCH only after an OFFSET.
+			b.WriteString(" OFFSET 0 ROWS")
+		}
+		if l.Count != nil {
+			b.WriteString(" FETCH NEXT " + r.renderLimit(l.Count) + " ROWS")
+			if l.WithTies {
+				b.WriteString(" WITH TIES")
+			} else {
+				b.WriteString(" ONLY")
+			}
+		}
+		return b.String()
+	}
+	if l.WithTies {
+		r.warn(WarnLimitUnsupported, l.Count.Pos(), "FETCH ... WITH TIES is not supported by %s; rows tied with the last one are dropped", r.target)
+	}
+	switch {
+	case l.Count != nil:
+		b.WriteString(" LIMIT " + r.renderLimit(l.Count))
+	case l.Offset == nil:
+	case r.target == DialectMySQL:
+		b.WriteString(" LIMIT 18446744073709551615")
+	case r.target == DialectSQLite:
+		b.WriteString(" LIMIT -1")
+	}
+	if l.Offset != nil {
+		b.WriteString(" OFFSET " + r.renderLimit(l.Offset))
+	}
+	return b.String()
+}
@@ -0,0 +1,492 @@
+package sqlparser
+
+import (
+	"slices"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/ident"
+)
+
+// ColumnLineage is where one output column of a query comes from.
+type ColumnLineage struct {
+	// Name is the column's alias or, for a plain column reference, the
+	// column's name. It is empty for an expression without an alias, and
+	// "*" for the columns of a table the schema does not know.
+	Name string `json:"name"`
+	// Sources are the base table columns whose values flow into the
+	// column, sorted. Columns that only filter, join, group or sort the
+	// rows are not sources. Column is "*" for every column of a table the
+	// schema does not know.
+	Sources []ColumnRef `json:"sources"`
+	// Direct is set when the column passes a single source column through
+	// unchanged, possibly renamed, rather than computing a value from it.
+	Direct bool `json:"direct"`
+}
+
+// ColumnRef names a column of a base table. Table is empty when an
+// unqualified column could not be attributed to a table.
+type ColumnRef struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+}
+
+// Lineage maps each output column of a query back to the base table
+// columns it derives from, following aliases, joins, derived tables, CTEs,
+// including recursive ones, scalar subqueries and set operations, whose
+// columns derive from the matching columns of every side. It takes a
+// SELECT, or the query of INSERT ... SELECT, CREATE VIEW or CREATE TABLE
+// ... AS SELECT, whose columns are then named after the target's column
+// list; for other statements it returns nil.
+//
+// schema is optional. With it, * expands to the table's columns and an
+// unqualified column in a join is attributed to the table that declares
+// it; without it such a column is attributed to every table that could
+// declare it.
+func Lineage(stmt Statement, schema *Schema) []ColumnLineage {
+	l := &lineageWalker{schema: schema}
+	switch s := stmt.(type) {
+	case *ast.SelectStmt:
+		return l.query(s, nil)
+	case *ast.InsertStmt:
+		if s.Select == nil {
+			return nil
+		}
+		return renameLineage(l.query(s.Select, l.with(s.With, nil)), s.Columns)
+	case *ast.CreateViewStmt:
+		return renameLineage(l.query(s.Select, nil), s.Columns)
+	case *ast.CreateTableStmt:
+		if s.Select != nil {
+			return l.query(s.Select, nil)
+		}
+	}
+	return nil
+}
+
+type lineageWalker struct {
+	schema *Schema
+}
+
+// dialect is the dialect of the walker's schema, by which it compares
+// names.
+func (l *lineageWalker) dialect() Dialect {
+	return l.schema.identDialect()
+}
+
+// lineageRel is the relation a FROM item or CTE stands for.
+type lineageRel struct {
+	// table is the base table, whose columns are listed in cols when the
+	// schema knows it and resolve by name otherwise.
+	table string
+	cols  []ColumnLineage
+	known bool
+	// source and computed describe a PIVOT or UNPIVOT: columns of source
+	// pass through, and any other column is computed from computed.
+	source   *lineageRel
+	computed []ColumnRef
+}
+
+type lineageScope struct {
+	parent  *lineageScope
+	sources []lineageSource
+	ctes    []lineageSource
+}
+
+// lineageSource is a FROM item or CTE with the name it is referred to by.
+type lineageSource struct {
+	name string
+	rel  *lineageRel
+}
+
+// column looks name up in r, comparing names as dialect does: yes when r
+// has it, maybe when r may have it and no when it certainly does not.
+func (r *lineageRel) column(name string, dialect Dialect) (ColumnLineage, tristate) {
+	switch {
+	case r.source != nil:
+		if c, t := r.source.column(name, dialect); t != no {
+			return c, t
+		}
+		return ColumnLineage{Name: name, Sources: r.computed}, maybe
+	case r.known:
+		for _, c := range r.cols {
+			if ident.Equal(c.Name, name, dialect) {
+				return c, yes
+			}
+		}
+		// A * over a table the schema does not know may hold the column.
+		var refs []ColumnRef
+		for _, c := range r.cols {
+			if c.Name == "*" {
+				for _, ref := range c.Sources {
+					refs = append(refs, ColumnRef{Table: ref.Table, Column: name})
+				}
+			}
+		}
+		if refs != nil {
+			return ColumnLineage{Name: name, Sources: refs, Direct: len(refs) == 1}, maybe
+		}
+		return ColumnLineage{}, no
+	case r.table != "":
+		return ColumnLineage{Name: name, Sources: []ColumnRef{{Table: r.table, Column: name}}, Direct: true}, maybe
+	}
+	return ColumnLineage{Name: name}, maybe
+}
+
+// all returns the columns * expands to over r.
+func (r *lineageRel) all() []ColumnLineage {
+	switch {
+	case r.known:
+		return r.cols
+	case r.table != "":
+		return []ColumnLineage{{Name: "*", Sources: []ColumnRef{{Table: r.table, Column: "*"}}}}
+	case r.source != nil:
+		return append(slices.Clip(r.source.all()), ColumnLineage{Name: "*", Sources: r.computed})
+	}
+	return []ColumnLineage{{Name: "*"}}
+}
+
+func (l *lineageWalker) baseTable(name string) *lineageRel {
+	r := &lineageRel{table: name}
+	if l.schema == nil {
+		return r
+	}
+	t := l.schema.Table(name)
+	if t == nil {
+		return r
+	}
+	r.known = true
+	for _, c := range t.Columns {
+		r.cols = append(r.cols, ColumnLineage{Name: c.Name, Sources: []ColumnRef{{Table: name, Column: c.Name}}, Direct: true})
+	}
+	return r
+}
+
+// with returns a scope holding the CTEs of w.
+func (l *lineageWalker) with(w *ast.WithClause, outer *lineageScope) *lineageScope {
+	scope := &lineageScope{parent: outer}
+	if w == nil {
+		return scope
+	}
+	for _, cte := range w.CTEs {
+		var rel *lineageRel
+		switch dml := cte.DML.(type) {
+		case nil:
+			if w.Recursive && cte.Subq.SetOp != nil {
+				// The recursive part sees the rows of the anchor first.
+				anchor := &lineageRel{known: true, cols: renameLineage(l.query(cte.Subq.SetOp.Left, scope), cte.Columns)}
+				scope.ctes = append(scope.ctes, lineageSource{name: cte.Name.Unquoted, rel: anchor})
+				*anchor = lineageRel{known: true, cols: renameLineage(l.query(cte.Subq, scope), cte.Columns)}
+				continue
+			}
+			rel = &lineageRel{known: true, cols: renameLineage(l.query(cte.Subq, scope), cte.Columns)}
+		case *ast.InsertStmt:
+			rel = l.baseTable(qualifiedName(dml.Table))
+		case *ast.UpdateStmt:
+			rel = l.dmlTable(dml.Tables)
+		case *ast.DeleteStmt:
+			rel = l.dmlTable(dml.From)
+		}
+		scope.ctes = append(scope.ctes, lineageSource{name: cte.Name.Unquoted, rel: rel})
+	}
+	return scope
+}
+
+// dmlTable is the relation of the RETURNING rows of an UPDATE or DELETE.
+func (l *lineageWalker) dmlTable(refs []ast.TableRef) *lineageRel {
+	if t, ok := singleTable(refs); ok {
+		return l.baseTable(qualifiedName(t.Name))
+	}
+	return &lineageRel{}
+}
+
+// query returns the lineage of the output columns of s.
+func (l *lineageWalker) query(s *ast.SelectStmt, outer *lineageScope) []ColumnLineage {
+	if s.With != nil {
+		outer = l.with(s.With, outer)
+	}
+	if s.SetOp != nil {
+		left, right := l.query(s.SetOp.Left, outer), l.query(s.SetOp.Right, outer)
+		for i := range min(len(left), len(right)) {
+			c := &left[i]
+			direct := c.Direct && right[i].Direct && slices.Equal(c.Sources, right[i].Sources)
+			c.Sources = sortRefs(append(slices.Clip(c.Sources), right[i].Sources...), l.dialect())
+			c.Direct = direct
+		}
+		return left
+	}
+	scope := &lineageScope{parent: outer}
+	if s.Values != nil {
+		var out []ColumnLineage
+		for _, row := range s.Values {
+			for i, e := range row {
+				if i == len(out) {
+					out = append(out, ColumnLineage{})
+				}
+				out[i].Sources = append(out[i].Sources, l.exprSources(e, scope)...)
+			}
+		}
+		for i := range out {
+			out[i].Sources = sortRefs(out[i].Sources, l.dialect())
+		}
+		return out
+	}
+	for _, ref := range s.From {
+		l.tableRef(ref, scope)
+	}
+	var out []ColumnLineage
+	for _, c := range s.Columns {
+		ts, _ := c.Expr.(*ast.TableStar)
+		switch {
+		case c.Star:
+			for _, src := range scope.sources {
+				out = appendExcept(out, src.rel.all(), c.Except, l.dialect())
+			}
+		case ts != nil:
+			if src := scope.find(qualifiedName(ts.Table), l.dialect()); src != nil {
+				out = appendExcept(out, src.rel.all(), c.Except, l.dialect())
+			}
+		default:
+			col := ColumnLineage{Sources: l.exprSources(c.Expr, scope)}
+			switch e := c.Expr.(type) {
+			case *ast.Ident:
+				col.Name = e.Unquoted
+			case *ast.QualifiedIdent:
+				col.Name = e.Parts[len(e.Parts)-1].Unquoted
+			}
+			switch c.Expr.(type) {
+			case *ast.Ident, *ast.QualifiedIdent:
+				_, col.Direct = l.resolve(c.Expr, scope)
+			}
+			if c.Alias != nil {
+				col.Name = c.Alias.Unquoted
+			}
+			out = append(out, col)
+		}
+	}
+	return out
+}
+
+// appendExcept appends the columns of cols not named in except.
+func appendExcept(out, cols []ColumnLineage, except []*ast.Ident, dialect Dialect) []ColumnLineage {
+	for _, c := range cols {
+		if !slices.ContainsFunc(except, func(id *ast.Ident) bool { return ident.Equal(id.Unquoted, c.Name, dialect) }) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func (l *lineageWalker) tableRef(ref ast.TableRef, scope *lineageScope) {
+	switch t := ref.(type) {
+	case *ast.SimpleTable:
+		name := qualifiedName(t.Name)
+		src := lineageSource{name: t.Name.Parts[len(t.Name.Parts)-1].Unquoted}
+		if cte := scope.cte(name, l.dialect()); len(t.Name.Parts) == 1 && cte != nil {
+			src.rel = cte
+		} else {
+			src.rel = l.baseTable(name)
+		}
+		if t.Alias != nil {
+			src.name = t.Alias.Unquoted
+		}
+		scope.sources = append(scope.sources, src)
+	case *ast.SubqueryTable:
+		src := lineageSource{rel: &lineageRel{known: true, cols: renameLineage(l.query(t.Subq, scope.parent), t.Columns)}}
+		if t.Alias != nil {
+			src.name = t.Alias.Unquoted
+		}
+		scope.sources = append(scope.sources, src)
+	case *ast.PivotTable:
+		inner := &lineageScope{parent: scope.parent}
+		l.tableRef(t.Source, inner)
+		rel := &lineageRel{source: &lineageRel{}}
+		if len(inner.sources) == 1 {
+			rel.source = inner.sources[0].rel
+		}
+		if t.Unpivot {
+			for _, v := range t.In {
+				rel.computed = append(rel.computed, l.exprSources(v.Expr, inner)...)
+			}
+		} else {
+			rel.computed = l.exprSources(t.Agg, inner)
+		}
+		rel.computed = sortRefs(rel.computed, l.dialect())
+		src := lineageSource{rel: rel}
+		if t.Alias != nil {
+			src.name = t.Alias.Unquoted
+		}
+		scope.sources = append(scope.sources, src)
+	case *ast.JoinTable:
+		l.tableRef(t.Left, scope)
+		l.tableRef(t.Right, scope)
+	}
+}
+
+// exprSources returns the base columns whose values flow into e, sorted.
+func (l *lineageWalker) exprSources(e ast.Expr, scope *lineageScope) []ColumnRef {
+	var refs []ColumnRef
+	var walk func(e ast.Expr)
+	walk = func(e ast.Expr) {
+		switch x := e.(type) {
+		case nil:
+		case *ast.Ident, *ast.QualifiedIdent:
+			c, _ := l.resolve(x, scope)
+			refs = append(refs, c...)
+		case *ast.TableStar:
+			if src := scope.find(qualifiedName(x.Table), l.dialect()); src != nil {
+				for _, c := range src.rel.all() {
+					refs = append(refs, c.Sources...)
+				}
+			}
+		case *ast.BinaryExpr:
+			walk(x.Left)
+			walk(x.Right)
+		case *ast.UnaryExpr:
+			walk(x.Expr)
+		case *ast.FuncCall:
+			for _, arg := range x.Args {
+				walk(arg)
+			}
+		case *ast.CaseExpr:
+			walk(x.Operand)
+			for _, w := range x.Whens {
+				walk(w.Cond)
+				walk(w.Result)
+			}
+			walk(x.Else)
+		case *ast.BetweenExpr:
+			walk(x.Expr)
+			walk(x.Lo)
+			walk(x.Hi)
+		case *ast.InExpr:
+			walk(x.Expr)
+			for _, item := range x.List {
+				walk(item)
+			}
+		case *ast.LikeExpr:
+			walk(x.Expr)
+			walk(x.Pattern)
+		case *ast.IsNullExpr:
+			walk(x.Expr)
+		case *ast.QuantifiedComparisonExpr:
+			walk(x.Left)
+			walk(x.Array)
+		case *ast.CastExpr:
+			walk(x.Expr)
+		case *ast.IntervalExpr:
+			walk(x.Expr)
+		case *ast.SubqueryExpr:
+			walk(x.Subq)
+		case *ast.SelectStmt:
+			// A scalar subquery's value is that of its one column.
+			if cols := l.query(x, scope); len(cols) > 0 {
+				refs = append(refs, cols[0].Sources...)
+			}
+		}
+	}
+	walk(e)
+	return sortRefs(refs, l.dialect())
+}
+
+// resolve returns the sources of a column reference in scope, and whether
+// it names exactly one column passed through unchanged.
+func (l *lineageWalker) resolve(e ast.Expr, scope *lineageScope) ([]ColumnRef, bool) {
+	var qualifier, name string
+	switch x := e.(type) {
+	case *ast.Ident:
+		name = x.Unquoted
+	case *ast.QualifiedIdent:
+		n := len(x.Parts)
+		name = x.Parts[n-1].Unquoted
+		if n > 1 {
+			qualifier = qualifiedName(&ast.QualifiedIdent{Parts: x.Parts[:n-1]})
+		}
+	}
+	for s := scope; s != nil; s = s.parent {
+		if qualifier != "" {
+			if src := s.lookup(qualifier, l.dialect()); src != nil {
+				c, _ := src.rel.column(name, l.dialect())
+				return c.Sources, c.Direct
+			}
+			continue
+		}
+		var found, possible []ColumnLineage
+		for _, src := range s.sources {
+			switch c, t := src.rel.column(name, l.dialect()); t {
+			case yes:
+				found = append(found, c)
+			case maybe:
+				possible = append(possible, c)
+			}
+		}
+		if len(found) == 0 && len(possible) == 0 {
+			continue
+		}
+		if len(found) == 1 && len(possible) == 0 || len(found) == 0 && len(possible) == 1 {
+			c := slices.Concat(found, possible)[0]
+			return c.Sources, c.Direct
+		}
+		// Ambiguous without a schema: any of the candidates.
+		var refs []ColumnRef
+		for _, c := range slices.Concat(found, possible) {
+			refs = append(refs, c.Sources...)
+		}
+		return refs, false
+	}
+	return []ColumnRef{{Table: qualifier, Column: name}}, true
+}
+
+// lookup finds the FROM item a qualifier names, by alias or table name,
+// comparing names as dialect does.
+func (s *lineageScope) lookup(qualifier string, dialect Dialect) *lineageSource {
+	for i := range s.sources {
+		src := &s.sources[i]
+		if ident.Equal(src.name, qualifier, dialect) || src.rel.table != "" && ident.Equal(src.rel.table, qualifier, dialect) {
+			return src
+		}
+	}
+	return nil
+}
+
+// find is lookup across s and its enclosing scopes.
+func (s *lineageScope) find(qualifier string, dialect Dialect) *lineageSource {
+	for ; s != nil; s = s.parent {
+		if src := s.lookup(qualifier, dialect); src != nil {
+			return src
+		}
+	}
+	return nil
+}
+
+// cte returns the relation of the CTE name visible in s, or nil.
+func (s *lineageScope) cte(name string, dialect Dialect) *lineageRel {
+	for ; s != nil; s = s.parent {
+		for i := len(s.ctes) - 1; i >= 0; i-- {
+			if ident.Equal(s.ctes[i].name, name, dialect) {
+				return s.ctes[i].rel
+			}
+		}
+	}
+	return nil
+}
+
+// renameLineage names the columns after names, when given.
+func renameLineage(cols []ColumnLineage, names []*ast.Ident) []ColumnLineage {
+	for i := range min(len(cols), len(names)) {
+		cols[i].Name = names[i].Unquoted
+	}
+	return cols
+}
+
+// sortRefs sorts refs and removes duplicates, the names dialect takes
+// for the same.
+func sortRefs(refs []ColumnRef, dialect Dialect) []ColumnRef {
+	slices.SortFunc(refs, func(a, b ColumnRef) int {
+		if c := compareFold(a.Table, b.Table); c != 0 {
+			return c
+		}
+		return compareFold(a.Column, b.Column)
+	})
+	return slices.CompactFunc(refs, func(a, b ColumnRef) bool {
+		return ident.Equal(a.Table, b.Table, dialect) && ident.Equal(a.Column, b.Column, dialect)
+	})
+}
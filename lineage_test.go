@@ -0,0 +1,82 @@
+package sqlparser_test
+
+import (
+	"strings"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+// lineageString renders lineage compactly: name=source for a column passed
+// through, name~source,source for a computed one.
+func lineageString(cols []sqlparser.ColumnLineage) string {
+	parts := make([]string, len(cols))
+	for i, c := range cols {
+		refs := make([]string, len(c.Sources))
+		for j, r := range c.Sources {
+			refs[j] = r.Table + "." + r.Column
+		}
+		op := "~"
+		if c.Direct {
+			op = "="
+		}
+		parts[i] = c.Name + op + strings.Join(refs, ",")
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestLineage(t *testing.T) {
+	schema, err := sqlparser.BuildSchema(`
+CREATE TABLE users (id INT PRIMARY KEY, email TEXT, name TEXT);
+CREATE TABLE orders (id INT PRIMARY KEY, user_id INT, total INT)`)
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	tests := []struct {
+		sql    string
+		schema *sqlparser.Schema
+		want   string
+	}{
+		{"SELECT u.email AS mail, total, total * 2 FROM users u JOIN orders o ON o.user_id = u.id WHERE o.id > 1", schema,
+			"mail=users.email total=orders.total ~orders.total"},
+		{"SELECT * FROM users", schema, "id=users.id email=users.email name=users.name"},
+		{"SELECT * EXCEPT (email) FROM users", schema, "id=users.id name=users.name"},
+		{"SELECT o.*, u.name FROM users u JOIN orders o USING (id)", schema,
+			"id=orders.id user_id=orders.user_id total=orders.total name=users.name"},
+		{"SELECT * FROM users", nil, "*~users.*"},
+		{"SELECT n, upper(n) AS loud FROM (SELECT name AS n FROM users) d", schema, "n=users.name loud~users.name"},
+		{"SELECT x FROM (SELECT id, email FROM users) d (x, y)", schema, "x=users.id"},
+		{"WITH spend AS (SELECT user_id, sum(total) AS amount FROM orders GROUP BY user_id) SELECT u.name, s.amount FROM users u JOIN spend s ON s.user_id = u.id", schema,
+			"name=users.name amount~orders.total"},
+		{"WITH RECURSIVE t (n) AS (SELECT id FROM users UNION ALL SELECT n + 1 FROM t WHERE n < 10) SELECT n FROM t", schema, "n~users.id"},
+		{"SELECT id, email FROM users UNION SELECT id, user_id FROM orders", schema, "id~orders.id,users.id email~orders.user_id,users.email"},
+		{"SELECT id, (SELECT max(total) FROM orders WHERE orders.user_id = users.id) AS top FROM users", schema, "id=users.id top~orders.total"},
+		{"SELECT CASE WHEN total > 100 THEN 'big' ELSE name END AS label FROM orders, users", schema, "label~orders.total,users.name"},
+		{"SELECT name FROM a JOIN b ON a.id = b.id", nil, "name~a.name,b.name"},
+		{"SELECT d.name FROM (SELECT * FROM people) d", nil, "name=people.name"},
+		{"SELECT 1 AS one, now() AS ts", nil, "one~ ts~"},
+	}
+	for _, tt := range tests {
+		res, err := sqlparser.ParseWithOptions(tt.sql, sqlparser.ParseOptions{StarExcept: true})
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.sql, err)
+		}
+		if got := lineageString(sqlparser.Lineage(res.Statements[0], tt.schema)); got != tt.want {
+			t.Errorf("%s\ngot  %s\nwant %s", tt.sql, got, tt.want)
+		}
+	}
+
+	for _, tt := range []struct{ sql, want string }{
+		{"INSERT INTO archive (uid, mail) SELECT id, lower(email) FROM users", "uid=users.id mail~users.email"},
+		{"CREATE VIEW v (who) AS SELECT name FROM users", "who=users.name"},
+		{"UPDATE users SET name = 'x'", ""},
+	} {
+		stmt, err := sqlparser.ParseStatement(tt.sql)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.sql, err)
+		}
+		if got := lineageString(sqlparser.Lineage(stmt, schema)); got != tt.want {
+			t.Errorf("%s\ngot  %s\nwant %s", tt.sql, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,475 @@
+package sqlparser
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/ident"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// NormalizeOptions selects the optional rewrites of NormalizeWithOptions.
+type NormalizeOptions struct {
+	// ExpandBetween rewrites x BETWEEN a AND b as x >= a AND x <= b, and
+	// x NOT BETWEEN a AND b as x < a OR x > b, when x is a column.
+	ExpandBetween bool
+}
+
+// Normalize rewrites stmt into a canonical form with the default options;
+// see NormalizeWithOptions.
+func Normalize(stmt Statement) Statement {
+	return NormalizeWithOptions(stmt, NormalizeOptions{})
+}
+
+// NormalizeWithOptions rewrites stmt into a canonical form, so that
+// queries that differ only in how they are written render, fingerprint and
+// diff the same way. In WHERE, HAVING, QUALIFY and join conditions, at
+// every level of stmt:
+//
+//   - NOT is pushed down to the comparisons it negates: NOT (a = 1 OR
+//     b IS NULL) becomes a != 1 AND b IS NOT NULL;
+//   - the terms of AND and OR are sorted, and a comparison with a constant
+//     puts the constant on the right, so 1 = a becomes a = 1;
+//   - comma joins and inner joins become a chain of explicit JOINs whose
+//     ON holds the conditions that link each table to the ones before it,
+//     and WHERE the rest: FROM a, b WHERE b.a_id = a.id AND a.x = 1 and
+//     FROM a JOIN b ON a.id = b.a_id WHERE a.x = 1 both become the latter.
+//
+// Terms with parameters keep their order, since parameters are numbered
+// in the order they are written, and a FROM clause is left as written
+// when the query has parameters or a condition that could not be tied to
+// its tables, such as one with an unqualified column or a subquery, would
+// have to move. The rewrites preserve the result under SQL's three-valued
+// logic, except that AND and OR are not guaranteed to evaluate their terms
+// in the order written anyway. stmt is left as it is: the rewritten copy
+// is returned.
+func NormalizeWithOptions(stmt Statement, opts NormalizeOptions) Statement {
+	stmt = detach([]Statement{stmt})[0]
+	n := &normalizer{opts: opts, r: newDialectRenderer(ConvertOptions{})}
+	switch s := stmt.(type) {
+	case *ast.UpdateStmt:
+		s.Where = n.pred(s.Where)
+	case *ast.DeleteStmt:
+		s.Where = n.pred(s.Where)
+	}
+	for _, s := range selectBlocks(stmt) {
+		n.selectBlock(s)
+	}
+	return stmt
+}
+
+// NormalizePass normalizes statements with NormalizeWithOptions.
+func NormalizePass(opts NormalizeOptions) Pass {
+	return NewPass("normalize", func(_ *PipelineContext, stmts []Statement) ([]Statement, error) {
+		for i, stmt := range stmts {
+			stmts[i] = NormalizeWithOptions(stmt, opts)
+		}
+		return stmts, nil
+	})
+}
+
+// ExplicitJoins rewrites comma joins as explicit JOINs, at every level of
+// stmts, moving the WHERE conditions that link a table to the ones before
+// it into the join's ON: FROM a, b WHERE b.a_id = a.id AND a.x = 1 becomes
+// FROM a JOIN b ON b.a_id = a.id WHERE a.x = 1, and tables that nothing
+// links are CROSS JOINed. Existing inner joins gain the WHERE conditions
+// that link their tables too.
+//
+// Conditions with parameters stay in WHERE, so that parameters keep their
+// order, as do conditions with a subquery or an unqualified column, which
+// cannot be tied to their tables without a schema. A FROM clause with an
+// outer, natural or USING join after its first item is left as written.
+// stmts get rewritten copies of their statements and are returned.
+func ExplicitJoins(stmts []Statement) []Statement {
+	for _, stmt := range detach(stmts) {
+		for _, s := range selectBlocks(stmt) {
+			if from, where, ok := explicitJoins(s.From, s.Where, true); ok {
+				s.From, s.Where = from, where
+			}
+		}
+	}
+	return stmts
+}
+
+// ExplicitJoinsPass rewrites comma joins with ExplicitJoins.
+func ExplicitJoinsPass() Pass {
+	return NewPass("explicit-joins", func(_ *PipelineContext, stmts []Statement) ([]Statement, error) {
+		return ExplicitJoins(stmts), nil
+	})
+}
+
+type normalizer struct {
+	opts NormalizeOptions
+	// r renders terms to sort them.
+	r *dialectRenderer
+}
+
+func (n *normalizer) selectBlock(s *ast.SelectStmt) {
+	s.Where = n.pred(s.Where)
+	s.Having = n.pred(s.Having)
+	s.Qualify = n.pred(s.Qualify)
+	eachJoin(s.From, func(j *ast.JoinTable) { j.On = n.pred(j.On) })
+	if hasParam(s) {
+		return
+	}
+	if from, where, ok := explicitJoins(s.From, s.Where, false); ok {
+		s.From, s.Where = from, n.pred(where)
+		eachJoin(s.From, func(j *ast.JoinTable) { j.On = n.pred(j.On) })
+	}
+}
+
+// eachJoin calls f on every join of refs.
+func eachJoin(refs []ast.TableRef, f func(*ast.JoinTable)) {
+	for _, ref := range refs {
+		if j, ok := ref.(*ast.JoinTable); ok {
+			eachJoin([]ast.TableRef{j.Left, j.Right}, f)
+			f(j)
+		}
+	}
+}
+
+// pred normalizes a condition.
+func (n *normalizer) pred(e ast.Expr) ast.Expr {
+	switch x := e.(type) {
+	case *ast.BinaryExpr:
+		switch x.Op {
+		case lexer.AND, lexer.DAMP, lexer.OR:
+			return n.chain(x)
+		case lexer.EQ, lexer.NEQ, lexer.LT, lexer.LTE, lexer.GT, lexer.GTE:
+			return n.comparison(x)
+		}
+	case *ast.UnaryExpr:
+		if x.Op == lexer.NOT {
+			neg := negate(x.Expr)
+			if u, ok := neg.(*ast.UnaryExpr); ok && u.Op == lexer.NOT {
+				// Nothing to push the NOT into.
+				return neg
+			}
+			return n.pred(neg)
+		}
+	case *ast.BetweenExpr:
+		if n.opts.ExpandBetween && bareColumn(x.Expr) != nil {
+			if x.Not {
+				return n.pred(&ast.BinaryExpr{
+					Left:   &ast.BinaryExpr{Left: x.Expr, Right: x.Lo, Op: lexer.LT, TokPos: x.TokPos},
+					Right:  &ast.BinaryExpr{Left: x.Expr, Right: x.Hi, Op: lexer.GT, TokPos: x.TokPos},
+					Op:     lexer.OR,
+					TokPos: x.TokPos,
+				})
+			}
+			return n.pred(&ast.BinaryExpr{
+				Left:   &ast.BinaryExpr{Left: x.Expr, Right: x.Lo, Op: lexer.GTE, TokPos: x.TokPos},
+				Right:  &ast.BinaryExpr{Left: x.Expr, Right: x.Hi, Op: lexer.LTE, TokPos: x.TokPos},
+				Op:     lexer.AND,
+				TokPos: x.TokPos,
+			})
+		}
+	}
+	return e
+}
+
+// chain normalizes and sorts the terms of an AND or OR chain. Terms with
+// parameters go last, in the order written.
+func (n *normalizer) chain(x *ast.BinaryExpr) ast.Expr {
+	or := x.Op == lexer.OR
+	split := func(e ast.Expr, terms []ast.Expr) []ast.Expr {
+		if or {
+			return orTerms(e, terms)
+		}
+		return andTerms(e, terms)
+	}
+	var terms []ast.Expr
+	for _, t := range split(x, nil) {
+		// A normalized term may be a chain of the same kind, as NOT (a OR b)
+		// in an AND is.
+		terms = split(n.pred(t), terms)
+	}
+	var plain, params []ast.Expr
+	for _, t := range terms {
+		if exprHasParam(t) {
+			params = append(params, t)
+		} else {
+			plain = append(plain, t)
+		}
+	}
+	keys := make(map[ast.Expr]string, len(plain))
+	for _, t := range plain {
+		keys[t] = n.r.renderExpr(t)
+	}
+	slices.SortStableFunc(plain, func(a, b ast.Expr) int { return strings.Compare(keys[a], keys[b]) })
+	op := lexer.AND
+	if or {
+		op = lexer.OR
+	}
+	var out ast.Expr
+	for _, t := range append(plain, params...) {
+		if out == nil {
+			out = t
+			continue
+		}
+		out = &ast.BinaryExpr{Left: out, Right: t, Op: op, TokPos: out.Pos()}
+	}
+	return out
+}
+
+// comparison puts a constant operand on the right, and orders the
+// operands of = and != that are both columns or both constants.
+func (n *normalizer) comparison(x *ast.BinaryExpr) ast.Expr {
+	l, r := constantExpr(x.Left), constantExpr(x.Right)
+	swap := l && !r
+	if l == r && (x.Op == lexer.EQ || x.Op == lexer.NEQ) && !exprHasParam(x) {
+		swap = n.r.renderExpr(x.Left) > n.r.renderExpr(x.Right)
+	}
+	if !swap || exprHasParam(x.Left) && exprHasParam(x.Right) {
+		return x
+	}
+	return &ast.BinaryExpr{Left: x.Right, Right: x.Left, Op: mirroredOps[x.Op], TokPos: x.TokPos}
+}
+
+// mirroredOps gives the operator that compares the same way with its
+// operands swapped.
+var mirroredOps = map[lexer.TokenType]lexer.TokenType{
+	lexer.EQ: lexer.EQ, lexer.NEQ: lexer.NEQ,
+	lexer.LT: lexer.GT, lexer.GT: lexer.LT, lexer.LTE: lexer.GTE, lexer.GTE: lexer.LTE,
+}
+
+// negatedOps gives the operator that is true where the other is false,
+// and NULL where it is NULL.
+var negatedOps = map[lexer.TokenType]lexer.TokenType{
+	lexer.EQ: lexer.NEQ, lexer.NEQ: lexer.EQ,
+	lexer.LT: lexer.GTE, lexer.GTE: lexer.LT, lexer.GT: lexer.LTE, lexer.LTE: lexer.GT,
+}
+
+// negate returns NOT e with the NOT pushed into e as far as it goes.
+func negate(e ast.Expr) ast.Expr {
+	switch x := e.(type) {
+	case *ast.BinaryExpr:
+		switch x.Op {
+		case lexer.AND, lexer.DAMP:
+			return &ast.BinaryExpr{Left: negate(x.Left), Right: negate(x.Right), Op: lexer.OR, TokPos: x.TokPos}
+		case lexer.OR:
+			return &ast.BinaryExpr{Left: negate(x.Left), Right: negate(x.Right), Op: lexer.AND, TokPos: x.TokPos}
+		}
+		if op, ok := negatedOps[x.Op]; ok {
+			cp := *x
+			cp.Op = op
+			return &cp
+		}
+	case *ast.UnaryExpr:
+		if x.Op == lexer.NOT {
+			return x.Expr
+		}
+	case *ast.InExpr:
+		cp := *x
+		cp.Not = !x.Not
+		return &cp
+	case *ast.LikeExpr:
+		cp := *x
+		cp.Not = !x.Not
+		return &cp
+	case *ast.BetweenExpr:
+		cp := *x
+		cp.Not = !x.Not
+		return &cp
+	case *ast.IsNullExpr:
+		cp := *x
+		cp.Not = !x.Not
+		return &cp
+	case *ast.ExistsExpr:
+		cp := *x
+		cp.Not = !x.Not
+		return &cp
+	}
+	return &ast.UnaryExpr{Expr: e, Op: lexer.NOT, TokPos: e.Pos()}
+}
+
+// exprHasParam reports whether e holds a parameter.
+func exprHasParam(e ast.Expr) bool {
+	found := false
+	a := &auditor{
+		visit: func(string, string, accessKind) {},
+		node: func(n ast.Node, _ int) {
+			if _, ok := n.(*ast.Param); ok {
+				found = true
+			}
+		},
+	}
+	a.expr(e, &auditScope{})
+	return found
+}
+
+// explicitJoins turns the comma and inner joins of from into a chain of
+// JOINs whose ON conditions link each table to the ones before it, moving
+// such conditions out of where, and returns the new FROM and WHERE. ok is
+// false when the FROM clause is to be left as written.
+//
+// With keepOn, the ON conditions stay on their joins and only WHERE
+// conditions without parameters move, so parameters keep their order;
+// otherwise every condition that refers to a single table goes to WHERE.
+func explicitJoins(from []ast.TableRef, where ast.Expr, keepOn bool) ([]ast.TableRef, ast.Expr, bool) {
+	var items []ast.TableRef
+	var terms []joinTerm
+	for _, ref := range from {
+		items, terms = innerItems(ref, items, terms)
+	}
+	if len(items) < 2 {
+		return nil, nil, false
+	}
+	names := make([][]string, len(items))
+	for i, item := range items {
+		if _, ok := item.(*ast.JoinTable); ok && i > 0 {
+			// The renderer has no parentheses for a join on the right of
+			// another.
+			return nil, nil, false
+		}
+		names[i] = tableNames(item, nil)
+	}
+	on := make([][]ast.Expr, len(items))
+	var rest []ast.Expr
+	for _, t := range terms {
+		if keepOn {
+			on[t.item] = append(on[t.item], t.expr)
+			continue
+		}
+		last, ok := linkedItem(t.expr, names)
+		switch {
+		case !ok:
+			// A join condition that cannot be placed keeps the FROM
+			// clause as written.
+			return nil, nil, false
+		case last < 0:
+			rest = append(rest, t.expr)
+		default:
+			on[last] = append(on[last], t.expr)
+		}
+	}
+	var whereTerms []ast.Expr
+	if where != nil {
+		whereTerms = andTerms(where, nil)
+	}
+	moved := 0
+	for _, term := range whereTerms {
+		last, ok := linkedItem(term, names)
+		if !ok || last < 0 || exprHasParam(term) {
+			rest = append(rest, term)
+			continue
+		}
+		on[last] = append(on[last], term)
+		moved++
+	}
+	if keepOn && moved == 0 && len(from) < 2 {
+		return nil, nil, false
+	}
+	joined := items[0]
+	for i, item := range items[1:] {
+		j := &ast.JoinTable{Left: joined, Right: item, Kind: ast.CrossJoin, TokPos: item.Pos()}
+		if cond := conjoin(on[i+1]...); cond != nil {
+			j.Kind, j.On = ast.InnerJoin, cond
+		}
+		joined = j
+	}
+	return []ast.TableRef{joined}, conjoin(rest...), true
+}
+
+// joinTerm is an AND term of the ON condition of the join whose last FROM
+// item is items[item].
+type joinTerm struct {
+	expr ast.Expr
+	item int
+}
+
+// innerItems appends to items the FROM items that ref joins with inner
+// or cross joins, and to terms the AND terms of their ON conditions.
+// Outer, natural and USING joins are items of their own.
+func innerItems(ref ast.TableRef, items []ast.TableRef, terms []joinTerm) ([]ast.TableRef, []joinTerm) {
+	j, ok := ref.(*ast.JoinTable)
+	if !ok || (j.Kind != ast.InnerJoin && j.Kind != ast.CrossJoin) || len(j.Using) > 0 {
+		return append(items, ref), terms
+	}
+	items, terms = innerItems(j.Left, items, terms)
+	items, terms = innerItems(j.Right, items, terms)
+	if j.On != nil {
+		for _, t := range andTerms(j.On, nil) {
+			terms = append(terms, joinTerm{t, len(items) - 1})
+		}
+	}
+	return items, terms
+}
+
+// tableNames appends the names that qualify the columns of ref, as
+// ident.Key gives them for an unknown dialect.
+func tableNames(ref ast.TableRef, names []string) []string {
+	switch t := ref.(type) {
+	case *ast.SimpleTable:
+		if t.Alias != nil {
+			return append(names, ident.Key(t.Alias.Unquoted, ""))
+		}
+		return append(names, ident.Key(t.Name.Parts[len(t.Name.Parts)-1].Unquoted, ""))
+	case *ast.SubqueryTable:
+		if t.Alias != nil {
+			return append(names, ident.Key(t.Alias.Unquoted, ""))
+		}
+	case *ast.PivotTable:
+		if t.Alias != nil {
+			return append(names, ident.Key(t.Alias.Unquoted, ""))
+		}
+	case *ast.JoinTable:
+		return tableNames(t.Right, tableNames(t.Left, names))
+	}
+	return names
+}
+
+// linkedItem returns the last of the items term refers to when it refers
+// to two or more, and -1 when it refers to fewer. ok is false when a
+// column of term cannot be tied to one item, or term holds a subquery,
+// which may refer to any of them.
+func linkedItem(term ast.Expr, names [][]string) (last int, ok bool) {
+	seen := map[int]bool{}
+	last, ok = -1, true
+	a := &auditor{
+		visit: func(string, string, accessKind) {},
+		node: func(n ast.Node, _ int) {
+			var qualifier string
+			switch x := n.(type) {
+			case *ast.SelectStmt, *ast.SubqueryExpr, *ast.ExistsExpr, *ast.QuantifiedComparisonExpr:
+				ok = false
+				return
+			case *ast.InExpr:
+				ok = ok && x.Subq == nil
+				return
+			case *ast.Ident:
+				ok = false
+				return
+			case *ast.QualifiedIdent:
+				if len(x.Parts) < 2 {
+					ok = false
+					return
+				}
+				qualifier = ident.Key(x.Parts[len(x.Parts)-2].Unquoted, "")
+			default:
+				return
+			}
+			item := -1
+			for i, list := range names {
+				if slices.Contains(list, qualifier) {
+					if item >= 0 {
+						ok = false
+					}
+					item = i
+				}
+			}
+			if item < 0 {
+				ok = false
+				return
+			}
+			seen[item] = true
+			last = max(last, item)
+		},
+	}
+	a.expr(term, &auditScope{})
+	if len(seen) < 2 {
+		last = -1
+	}
+	return last, ok
+}
@@ -0,0 +1,129 @@
+package sqlparser_test
+
+import (
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		src, want string
+		opts      sqlparser.NormalizeOptions
+	}{
+		{src: "SELECT id FROM t WHERE b = 2 AND a = 1", want: `SELECT "id" FROM "t" WHERE (("a" = 1) AND ("b" = 2))`},
+		{src: "SELECT id FROM t WHERE 1 = a OR 5 < b", want: `SELECT "id" FROM "t" WHERE (("a" = 1) OR ("b" > 5))`},
+		{src: "SELECT id FROM t WHERE NOT (a = 1 OR b IS NULL)", want: `SELECT "id" FROM "t" WHERE ("b" IS NOT NULL AND ("a" != 1))`},
+		{src: "SELECT id FROM t WHERE NOT NOT (a IN (1, 2)) AND NOT (c LIKE 'x%')", want: `SELECT "id" FROM "t" WHERE ("a" IN (1, 2) AND "c" NOT LIKE 'x%')`},
+		{src: "SELECT id FROM t WHERE NOT (a > 1 AND (b < 2 OR c))", want: `SELECT "id" FROM "t" WHERE (("a" <= 1) OR (("b" >= 2) AND (NOT "c")))`},
+		{src: "SELECT id FROM t WHERE y.b = x.a", want: `SELECT "id" FROM "t" WHERE ("x"."a" = "y"."b")`},
+		{src: "SELECT id FROM t WHERE b = $2 AND a = $1 AND c = 3", want: `SELECT "id" FROM "t" WHERE ((("c" = 3) AND ("b" = $1)) AND ("a" = $2))`},
+		{src: "SELECT id FROM t WHERE a BETWEEN 1 AND 5", want: `SELECT "id" FROM "t" WHERE "a" BETWEEN 1 AND 5`},
+		{src: "SELECT id FROM t WHERE a BETWEEN 1 AND 5 AND NOT (b BETWEEN 2 AND 3)", opts: sqlparser.NormalizeOptions{ExpandBetween: true},
+			want: `SELECT "id" FROM "t" WHERE ((("a" <= 5) AND ("a" >= 1)) AND (("b" < 2) OR ("b" > 3)))`},
+		{src: "SELECT a.id FROM a, b WHERE b.a_id = a.id AND a.x = 1",
+			want: `SELECT "a"."id" FROM "a" JOIN "b" ON ("a"."id" = "b"."a_id") WHERE ("a"."x" = 1)`},
+		{src: "SELECT a.id FROM a JOIN b ON a.id = b.a_id AND a.x = 1",
+			want: `SELECT "a"."id" FROM "a" JOIN "b" ON ("a"."id" = "b"."a_id") WHERE ("a"."x" = 1)`},
+		{src: "SELECT a.id FROM a, b, c WHERE c.b_id = b.id AND b.a_id = a.id",
+			want: `SELECT "a"."id" FROM "a" JOIN "b" ON ("a"."id" = "b"."a_id") JOIN "c" ON ("b"."id" = "c"."b_id")`},
+		{src: "SELECT a.id FROM a, b", want: `SELECT "a"."id" FROM "a" CROSS JOIN "b"`},
+		{src: "SELECT a.id FROM a LEFT JOIN b ON b.a_id = a.id, c WHERE c.b_id = b.id",
+			want: `SELECT "a"."id" FROM "a" LEFT JOIN "b" ON ("a"."id" = "b"."a_id") JOIN "c" ON ("b"."id" = "c"."b_id")`},
+		// Kept: a join condition that cannot be tied to its tables.
+		{src: "SELECT x FROM a JOIN b ON id = b.a_id", want: `SELECT "x" FROM "a" JOIN "b" ON ("b"."a_id" = "id")`},
+		{src: "SELECT a.id FROM a, b WHERE a.id = b.a_id AND b.v = $1", want: `SELECT "a"."id" FROM "a", "b" WHERE (("a"."id" = "b"."a_id") AND ("b"."v" = $1))`},
+		{src: "UPDATE t SET v = 1 WHERE NOT (b = 2 AND a = 1)", want: `UPDATE "t" SET "v" = 1 WHERE (("a" != 1) OR ("b" != 2))`},
+		{src: "SELECT id FROM t WHERE id IN (SELECT uid FROM u WHERE 3 = k AND NOT j)",
+			want: `SELECT "id" FROM "t" WHERE "id" IN (SELECT "uid" FROM "u" WHERE (("k" = 3) AND (NOT "j")))`},
+	}
+	for _, tt := range tests {
+		stmts, err := sqlparser.ParseStatements(tt.src)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.src, err)
+		}
+		for i, stmt := range stmts {
+			stmts[i] = sqlparser.NormalizeWithOptions(stmt, tt.opts)
+		}
+		got, _, err := sqlparser.ConvertStatements(stmts, sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s\ngot  %s\nwant %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeSurvivesGC(t *testing.T) {
+	checkRewriteSurvivesGC(t, "SELECT a, b FROM t, u WHERE t.id = u.id AND 1 = x AND NOT (y = 2 OR z IS NULL)", func(stmts []sqlparser.Statement) []sqlparser.Statement {
+		for i, stmt := range stmts {
+			stmts[i] = sqlparser.Normalize(stmt)
+		}
+		return stmts
+	})
+}
+
+func TestNormalizeSameForm(t *testing.T) {
+	pairs := [][2]string{
+		{"SELECT * FROM a, b WHERE a.id = b.a_id AND 1 = a.x", "SELECT * FROM a JOIN b ON b.a_id = a.id WHERE a.x = 1"},
+		{"SELECT * FROM t WHERE NOT (x <> 1 OR y >= 2)", "SELECT * FROM t WHERE 2 > y AND x = 1"},
+	}
+	for _, p := range pairs {
+		var out [2]string
+		for i, src := range p {
+			stmt, err := sqlparser.ParseStatement(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out[i], _, err = sqlparser.ConvertStatements([]sqlparser.Statement{sqlparser.Normalize(stmt)}, sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if out[0] != out[1] {
+			t.Errorf("%s\n%s\nnormalize to\n%s\n%s", p[0], p[1], out[0], out[1])
+		}
+	}
+}
+
+func TestExplicitJoins(t *testing.T) {
+	tests := []struct{ src, want string }{
+		{"SELECT a.id FROM a, b WHERE a.id = b.a_id", "SELECT `a`.`id` FROM `a` JOIN `b` ON (`a`.`id` = `b`.`a_id`)"},
+		{"SELECT a.id FROM a, b, c WHERE c.b_id = b.id AND a.x = 1 AND b.a_id = a.id AND c.v = ?",
+			"SELECT `a`.`id` FROM `a` JOIN `b` ON (`b`.`a_id` = `a`.`id`) JOIN `c` ON (`c`.`b_id` = `b`.`id`) WHERE ((`a`.`x` = 1) AND (`c`.`v` = ?))"},
+		{"SELECT o.id FROM orders o, users u WHERE o.uid = u.id AND (u.x = 1 OR o.y = 2)",
+			"SELECT `o`.`id` FROM `orders` `o` JOIN `users` `u` ON ((`o`.`uid` = `u`.`id`) AND ((`u`.`x` = 1) OR (`o`.`y` = 2)))"},
+		{"SELECT * FROM a JOIN b ON a.id = b.a_id AND b.v = ?, c WHERE c.b_id = b.id",
+			"SELECT * FROM `a` JOIN `b` ON ((`a`.`id` = `b`.`a_id`) AND (`b`.`v` = ?)) JOIN `c` ON (`c`.`b_id` = `b`.`id`)"},
+		{"SELECT * FROM a, b", "SELECT * FROM `a` CROSS JOIN `b`"},
+		{"SELECT * FROM a LEFT JOIN b ON a.id = b.a_id, c WHERE c.b_id = b.id",
+			"SELECT * FROM `a` LEFT JOIN `b` ON (`a`.`id` = `b`.`a_id`) JOIN `c` ON (`c`.`b_id` = `b`.`id`)"},
+		{"SELECT id FROM t WHERE id IN (SELECT u.id FROM u, v WHERE u.id = v.uid)",
+			"SELECT `id` FROM `t` WHERE `id` IN (SELECT `u`.`id` FROM `u` JOIN `v` ON (`u`.`id` = `v`.`uid`))"},
+		// Kept in WHERE: conditions that cannot be tied to their tables.
+		{"SELECT * FROM a, b WHERE id = b.a_id AND a.id = b.x + ?",
+			"SELECT * FROM `a` CROSS JOIN `b` WHERE ((`id` = `b`.`a_id`) AND (`a`.`id` = (`b`.`x` + ?)))"},
+		// Kept as written.
+		{"SELECT * FROM a JOIN b ON a.id = b.a_id WHERE a.x = 1", "SELECT * FROM `a` JOIN `b` ON (`a`.`id` = `b`.`a_id`) WHERE (`a`.`x` = 1)"},
+		{"SELECT * FROM a, b LEFT JOIN c ON c.id = b.c_id WHERE a.id = b.a_id",
+			"SELECT * FROM `a`, `b` LEFT JOIN `c` ON (`c`.`id` = `b`.`c_id`) WHERE (`a`.`id` = `b`.`a_id`)"},
+	}
+	for _, tt := range tests {
+		stmts, err := sqlparser.ParseStatements(tt.src)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.src, err)
+		}
+		got, _, err := sqlparser.ConvertStatements(sqlparser.ExplicitJoins(stmts), sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s\ngot  %s\nwant %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestExplicitJoinsSurvivesGC(t *testing.T) {
+	checkRewriteSurvivesGC(t, "SELECT a, b FROM t, u, v WHERE t.id = u.id AND v.uid = u.id AND t.x = 1", sqlparser.ExplicitJoins)
+}
@@ -0,0 +1,93 @@
+package sqlparser
+
+import (
+	"unsafe"
+
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// ObfuscateOptions tunes Obfuscate for a particular log source.
+type ObfuscateOptions struct {
+	// DoubleQuotedStrings treats "..." as a string literal (MySQL without
+	// ANSI_QUOTES) instead of a quoted identifier.
+	DoubleQuotedStrings bool
+}
+
+// Obfuscate replaces every literal in sql with ?, strips comments and
+// collapses runs of whitespace to a single space. It works on the token stream
+// only, so it never fails: truncated or syntactically invalid SQL is
+// obfuscated as far as it goes.
+func Obfuscate(sql string) string {
+	return ObfuscateWithOptions(sql, ObfuscateOptions{})
+}
+
+// ObfuscateWithOptions is Obfuscate with explicit options.
+func ObfuscateWithOptions(sql string, opts ObfuscateOptions) string {
+	src := unsafe.Slice(unsafe.StringData(sql), len(sql))
+	out := AppendObfuscated(make([]byte, 0, len(sql)), src, opts)
+	if len(out) == 0 {
+		return ""
+	}
+	return unsafe.String(&out[0], len(out))
+}
+
+// AppendObfuscated appends the obfuscated form of src to dst and returns the
+// extended buffer. Reusing dst across calls makes obfuscation allocation-free.
+func AppendObfuscated(dst, src []byte, opts ObfuscateOptions) []byte {
+	var l lexer.Lexer
+	l.Init(src)
+	prev := lexer.ILLEGAL
+	prevEnd := int32(0)
+	wrote := false
+	tok := l.Next()
+	for tok.Type != lexer.EOF {
+		next := l.Next()
+		raw := tok.Raw
+		typ := tok.Type
+		if typ == lexer.MINUS && isObfuscatedLiteral(next.Type, opts) && !endsOperand(prev, wrote) {
+			// Fold unary minus into the literal: -5 becomes ?.
+			tok = next
+			continue
+		}
+		if isObfuscatedLiteral(typ, opts) {
+			raw = obfuscatedMarker
+			typ = lexer.QUESTION
+		}
+		if wrote && tok.Pos > prevEnd {
+			// Whitespace or a comment separated the tokens in the source.
+			dst = append(dst, ' ')
+		}
+		dst = append(dst, raw...)
+		wrote = true
+		prev = typ
+		prevEnd = tok.Pos + int32(len(tok.Raw))
+		tok = next
+	}
+	return dst
+}
+
+var obfuscatedMarker = []byte{'?'}
+
+func isObfuscatedLiteral(t lexer.TokenType, opts ObfuscateOptions) bool {
+	switch t {
+	case lexer.STRING, lexer.INT, lexer.FLOAT, lexer.HEXLIT, lexer.BITLIT:
+		return true
+	case lexer.DQUOTE:
+		return opts.DoubleQuotedStrings
+	}
+	return false
+}
+
+// endsOperand reports whether a token of type t can end an operand, in which
+// case a following minus is binary rather than a sign.
+func endsOperand(t lexer.TokenType, wrote bool) bool {
+	if !wrote {
+		return false
+	}
+	switch t {
+	case lexer.IDENT, lexer.BACKTICK, lexer.DQUOTE, lexer.QUESTION, lexer.NAMEDPARAM,
+		lexer.RPAREN, lexer.RBRACKET, lexer.NULL_KW, lexer.TRUE_KW, lexer.FALSE_KW:
+		return true
+	}
+	return false
+}
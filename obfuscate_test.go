@@ -0,0 +1,58 @@
+package sqlparser_test
+
+import (
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestObfuscate(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{
+			"SELECT *\n  FROM users WHERE id = 42 AND name = 'bob' -- trailing\n",
+			"SELECT * FROM users WHERE id = ? AND name = ?",
+		},
+		{
+			"SELECT a - 1, -2.5 /* note */ FROM t WHERE x IN (1,2, 0xFF)",
+			"SELECT a - ?, ? FROM t WHERE x IN (?,?, ?)",
+		},
+		{
+			"UPDATE t SET v = COALESCE(v, 'x') WHERE k = :key",
+			"UPDATE t SET v = COALESCE(v, ?) WHERE k = :key",
+		},
+		{
+			// Truncated mid-literal: everything still obfuscated.
+			"INSERT INTO logs (msg) VALUES ('secret tok",
+			"INSERT INTO logs (msg) VALUES (?",
+		},
+	}
+	for _, tt := range tests {
+		if got := sqlparser.Obfuscate(tt.in); got != tt.want {
+			t.Errorf("Obfuscate(%q)\n got: %s\nwant: %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestObfuscateDoubleQuotedStrings(t *testing.T) {
+	in := `SELECT "x" FROM t`
+	if got := sqlparser.Obfuscate(in); got != `SELECT "x" FROM t` {
+		t.Fatalf("expected identifier to be kept, got %s", got)
+	}
+	got := sqlparser.ObfuscateWithOptions(in, sqlparser.ObfuscateOptions{DoubleQuotedStrings: true})
+	if got != "SELECT ? FROM t" {
+		t.Fatalf("expected double-quoted string to be obfuscated, got %s", got)
+	}
+}
+
+func BenchmarkObfuscate(b *testing.B) {
+	src := []byte("SELECT u.id, u.name FROM users u WHERE u.email = 'a@example.com' AND u.age > 30 LIMIT 10")
+	buf := make([]byte, 0, len(src))
+	b.SetBytes(int64(len(src)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = sqlparser.AppendObfuscated(buf[:0], src, sqlparser.ObfuscateOptions{})
+	}
+}
@@ -0,0 +1,999 @@
+package sqlparser
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/ident"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// InlineCTEs replaces each non-recursive CTE that its query reads exactly
+// once with a derived table at the place it is read, for engines such as
+// MySQL before 8.0, which has no CTEs, or PostgreSQL before 12, which
+// always materializes them. A CTE stays when it is read more than once or
+// not at all, refers to itself, is AS MATERIALIZED, is an INSERT, UPDATE
+// or DELETE, or is read with index hints or TABLESAMPLE, and when a WITH
+// nested in the query defines the same name. A WITH left empty is
+// dropped. stmts get rewritten copies of their statements and are
+// returned.
+func InlineCTEs(stmts []Statement) []Statement {
+	for _, stmt := range detach(stmts) {
+		for _, o := range withOwners(stmt) {
+			inlineWith(o)
+		}
+	}
+	return stmts
+}
+
+// PushDownPredicates moves each WHERE condition that reads only the
+// columns of one derived table, or of a CTE read once, into that table's
+// query, so it filters rows before the table is materialized:
+//
+//	SELECT * FROM (SELECT user_id, SUM(total) AS spent FROM orders GROUP BY user_id) s WHERE s.user_id = 7
+//
+// filters orders on user_id = 7 inside the derived table. MySQL before
+// 8.0.22 does not push conditions into the derived tables it
+// materializes, those with GROUP BY, DISTINCT, UNION or LIMIT, and
+// PostgreSQL before 12 never pushes them into CTEs, nor later versions
+// into CTEs AS MATERIALIZED. Later versions of both, and SQLite, push
+// most conditions down themselves.
+//
+// A condition on an aggregate goes to HAVING, and one on a grouping key
+// to WHERE; a set operation gets it in each of its SELECTs. Conditions
+// stay put when the query has LIMIT or window functions, which they
+// would change, when the table is on the nullable side of an outer join,
+// and when they hold subqueries, ? parameters or functions such as RAND()
+// that differ between calls. Each of stmts is replaced by its rewritten
+// copy, and stmts is returned.
+func PushDownPredicates(stmts []Statement) []Statement {
+	for _, stmt := range detach(stmts) {
+		pushDownPredicates(stmt)
+	}
+	return stmts
+}
+
+// OrToUnion splits a query filtered by an OR of conditions on different
+// indexed columns into one UNION ALL branch per column, so each branch
+// can seek its own index instead of the whole query scanning the table:
+//
+//	SELECT id FROM users WHERE email = 'a@b.c' OR phone = '555'
+//	SELECT id FROM users WHERE email = 'a@b.c'
+//	UNION ALL SELECT id FROM users WHERE phone = '555' AND (NOT (email = 'a@b.c') OR (email = 'a@b.c') IS NULL)
+//
+// Each branch excludes the rows of the ones before it, so no row is
+// returned twice; the IS NULL test is left out when schema declares the
+// columns NOT NULL. Other AND terms of the WHERE go into every branch, and
+// ORDER BY and LIMIT apply to the union when they sort by output columns.
+//
+// Only SELECTs from a single table of schema are split, and only when
+// every side of the OR compares a column that leads an index with
+// constants, at least two such columns differ, and the query has no
+// DISTINCT, grouping, aggregates, window functions or parameters, which
+// repeating the query would renumber. Whether the split
+// pays off depends on the data, so no default pipeline runs it. The
+// statements of stmts are replaced by rewritten copies, and stmts is
+// returned.
+func OrToUnion(stmts []Statement, schema *Schema) []Statement {
+	for _, stmt := range detach(stmts) {
+		orToUnion(stmt, schema)
+	}
+	return stmts
+}
+
+// FlattenSubqueries removes derived tables that add nothing: SELECT *
+// FROM (query) x becomes the query itself, and a derived table that is a
+// bare SELECT * FROM t becomes t under the derived table's alias. It
+// repeats until no such table is left, at every level of stmts, whose
+// statements are replaced by rewritten copies; stmts is returned.
+func FlattenSubqueries(stmts []Statement) []Statement {
+	for _, stmt := range detach(stmts) {
+		for changed := true; changed; {
+			changed = false
+			for _, s := range selectBlocks(stmt) {
+				changed = flattenSelect(s) || changed
+			}
+		}
+	}
+	return stmts
+}
+
+// InlineCTEsPass inlines CTEs with InlineCTEs.
+func InlineCTEsPass() Pass {
+	return NewPass("inline-ctes", func(_ *PipelineContext, stmts []Statement) ([]Statement, error) {
+		return InlineCTEs(stmts), nil
+	})
+}
+
+// FlattenSubqueriesPass flattens derived tables with FlattenSubqueries.
+func FlattenSubqueriesPass() Pass {
+	return NewPass("flatten-subqueries", func(_ *PipelineContext, stmts []Statement) ([]Statement, error) {
+		return FlattenSubqueries(stmts), nil
+	})
+}
+
+// PushDownPredicatesPass pushes conditions down with PushDownPredicates.
+func PushDownPredicatesPass() Pass {
+	return NewPass("push-down-predicates", func(_ *PipelineContext, stmts []Statement) ([]Statement, error) {
+		return PushDownPredicates(stmts), nil
+	})
+}
+
+// OrToUnionPass splits OR conditions with OrToUnion and the pipeline's
+// schema.
+func OrToUnionPass() Pass {
+	return NewPass("or-to-union", func(ctx *PipelineContext, stmts []Statement) ([]Statement, error) {
+		return OrToUnion(stmts, ctx.Schema), nil
+	})
+}
+
+// selectBlocks returns the SELECT blocks of stmt at every level.
+func selectBlocks(stmt Statement) []*ast.SelectStmt {
+	var blocks []*ast.SelectStmt
+	a := &auditor{
+		visit: func(string, string, accessKind) {},
+		node: func(n ast.Node, _ int) {
+			if s, ok := n.(*ast.SelectStmt); ok {
+				blocks = append(blocks, s)
+			}
+		},
+	}
+	a.statement(stmt)
+	return blocks
+}
+
+// withOwner is a statement or query with a WITH clause.
+type withOwner struct {
+	with **ast.WithClause
+	body Statement
+}
+
+// withOwners returns the statements and queries of stmt that have a WITH
+// clause, outermost first.
+func withOwners(stmt Statement) []withOwner {
+	var owners []withOwner
+	seen := map[*ast.SelectStmt]bool{}
+	addSelect := func(s *ast.SelectStmt) {
+		if s != nil && s.With != nil && !seen[s] {
+			seen[s] = true
+			owners = append(owners, withOwner{with: &s.With, body: s})
+		}
+	}
+	switch s := stmt.(type) {
+	case *ast.SelectStmt:
+		addSelect(s)
+	case *ast.InsertStmt:
+		if s.With != nil {
+			owners = append(owners, withOwner{with: &s.With, body: s})
+		}
+		addSelect(s.Select)
+	case *ast.UpdateStmt:
+		if s.With != nil {
+			owners = append(owners, withOwner{with: &s.With, body: s})
+		}
+	case *ast.DeleteStmt:
+		if s.With != nil {
+			owners = append(owners, withOwner{with: &s.With, body: s})
+		}
+	case *ast.CreateViewStmt:
+		addSelect(s.Select)
+	case *ast.CreateTableStmt:
+		addSelect(s.Select)
+	}
+	a := &auditor{
+		visit: func(string, string, accessKind) {},
+		node: func(n ast.Node, _ int) {
+			switch x := n.(type) {
+			case *ast.SelectStmt:
+				addSelect(x)
+			case *ast.SubqueryTable:
+				addSelect(x.Subq)
+			case *ast.SubqueryExpr:
+				addSelect(x.Subq)
+			case *ast.ExistsExpr:
+				addSelect(x.Subq)
+			case *ast.InExpr:
+				addSelect(x.Subq)
+			case *ast.QuantifiedComparisonExpr:
+				addSelect(x.Subq)
+			}
+		},
+		with: func(w *ast.WithClause) {
+			for _, cte := range w.CTEs {
+				addSelect(cte.Subq)
+			}
+		},
+	}
+	a.statement(stmt)
+	return owners
+}
+
+func refSlots(refs []ast.TableRef) []*ast.TableRef {
+	slots := make([]*ast.TableRef, len(refs))
+	for i := range refs {
+		slots[i] = &refs[i]
+	}
+	return slots
+}
+
+// tableSlots returns the places that hold a FROM item in the statement
+// or query n, at every level, and whether a WITH clause in it defines a
+// CTE called name.
+func tableSlots(n Statement, name string) (slots []*ast.TableRef, shadowed bool) {
+	a := &auditor{
+		visit: func(string, string, accessKind) {},
+		node: func(n ast.Node, _ int) {
+			switch x := n.(type) {
+			case *ast.SelectStmt:
+				slots = append(slots, refSlots(x.From)...)
+			case *ast.JoinTable:
+				slots = append(slots, &x.Left, &x.Right)
+			case *ast.PivotTable:
+				slots = append(slots, &x.Source)
+			}
+		},
+		with: func(w *ast.WithClause) {
+			shadowed = shadowed || slices.ContainsFunc(w.CTEs, func(c ast.CTE) bool { return ident.Equal(c.Name.Unquoted, name, "") })
+		},
+	}
+	switch s := n.(type) {
+	case *ast.UpdateStmt:
+		slots = append(slots, refSlots(s.Tables)...)
+	case *ast.DeleteStmt:
+		slots = append(slots, refSlots(s.From)...)
+	}
+	a.statement(n)
+	return slots, shadowed
+}
+
+// inlineWith inlines the CTEs of o's WITH clause that are read once.
+func inlineWith(o withOwner) {
+	w := *o.with
+	for i := 0; i < len(w.CTEs); {
+		slot := onlyRead(o, i)
+		if slot == nil {
+			i++
+			continue
+		}
+		cte := w.CTEs[i]
+		t := (*slot).(*ast.SimpleTable)
+		alias := t.Alias
+		if alias == nil {
+			alias = cte.Name
+		}
+		*slot = &ast.SubqueryTable{Subq: renameColumns(cte.Subq, cte.Columns), Alias: alias, Columns: remainingColumns(cte.Subq, cte.Columns), TokPos: t.Pos()}
+		w.CTEs = slices.Delete(w.CTEs, i, i+1)
+	}
+	if len(w.CTEs) == 0 {
+		*o.with = nil
+	}
+}
+
+// onlyRead returns the one place that reads the i-th CTE of o, or nil
+// when the CTE cannot be inlined.
+func onlyRead(o withOwner, i int) *ast.TableRef {
+	if (*o.with).CTEs[i].Materialized == ast.MaterializeAlways {
+		return nil
+	}
+	slot := soleRead(o, i)
+	if slot == nil {
+		return nil
+	}
+	if t := (*slot).(*ast.SimpleTable); len(t.IndexHints) > 0 || t.Sample != nil {
+		return nil
+	}
+	return slot
+}
+
+// soleRead returns the one place that reads the i-th CTE of o, or nil
+// when it is read elsewhere too, reads itself or is not a query.
+func soleRead(o withOwner, i int) *ast.TableRef {
+	w := *o.with
+	cte := w.CTEs[i]
+	if cte.Subq == nil {
+		return nil
+	}
+	name := cte.Name.Unquoted
+	if self, shadowed := tableSlots(cte.Subq, name); shadowed || len(cteReads(self, name)) > 0 {
+		return nil
+	}
+	// The CTE may be read by the body, without its WITH, and by the CTEs
+	// after it.
+	*o.with = nil
+	slots, shadowed := tableSlots(o.body, name)
+	*o.with = w
+	for _, later := range w.CTEs[i+1:] {
+		s, sh := tableSlots(cteBody(later), name)
+		slots, shadowed = append(slots, s...), shadowed || sh
+	}
+	reads := cteReads(slots, name)
+	if shadowed || len(reads) != 1 {
+		return nil
+	}
+	return reads[0]
+}
+
+func cteBody(cte ast.CTE) Statement {
+	if cte.Subq != nil {
+		return cte.Subq
+	}
+	return cte.DML
+}
+
+// cteReads returns the slots that read the CTE name.
+func cteReads(slots []*ast.TableRef, name string) []*ast.TableRef {
+	var reads []*ast.TableRef
+	for _, slot := range slots {
+		if t, ok := (*slot).(*ast.SimpleTable); ok && len(t.Name.Parts) == 1 && ident.Equal(t.Name.Parts[0].Unquoted, name, "") {
+			reads = append(reads, slot)
+		}
+	}
+	return reads
+}
+
+// renameColumns applies the column list of a CTE as aliases on the first
+// block of its query, which names the columns, so the derived table does
+// not need a column list, which MySQL before 8.0.19 lacks. It returns q
+// unchanged when a column of that block is a star.
+func renameColumns(q *ast.SelectStmt, names []*ast.Ident) *ast.SelectStmt {
+	first := firstBlock(q)
+	if len(names) == 0 || len(first.Columns) != len(names) || slices.ContainsFunc(first.Columns, isStarColumn) {
+		return q
+	}
+	for i := range first.Columns {
+		first.Columns[i].Alias = names[i]
+	}
+	return q
+}
+
+// remainingColumns is the column list the derived table still needs
+// after renameColumns.
+func remainingColumns(q *ast.SelectStmt, names []*ast.Ident) []*ast.Ident {
+	first := firstBlock(q)
+	if len(first.Columns) == len(names) && !slices.ContainsFunc(first.Columns, isStarColumn) {
+		return nil
+	}
+	return names
+}
+
+func firstBlock(q *ast.SelectStmt) *ast.SelectStmt {
+	for q.SetOp != nil {
+		q = q.SetOp.Left
+	}
+	return q
+}
+
+func isStarColumn(c ast.SelectColumn) bool {
+	_, ts := c.Expr.(*ast.TableStar)
+	return c.Star || ts
+}
+
+// flattenSelect removes a derived table from the FROM of s when it adds
+// nothing, and reports whether it did.
+func flattenSelect(s *ast.SelectStmt) bool {
+	if len(s.From) != 1 {
+		return false
+	}
+	d, ok := s.From[0].(*ast.SubqueryTable)
+	if !ok || len(d.Columns) > 0 {
+		return false
+	}
+	if len(s.Columns) == 1 && s.Columns[0].Star && s.Columns[0].Except == nil && s.With == nil && len(s.Hints) == 0 &&
+		!s.Distinct && s.Where == nil && len(s.GroupBy) == 0 && s.Having == nil && s.Qualify == nil &&
+		len(s.OrderBy) == 0 && s.LimitBy == nil && s.Limit == nil {
+		// SELECT * FROM (query) x is the query.
+		pos := s.TokPos
+		*s = *d.Subq
+		s.TokPos = pos
+		return true
+	}
+	inner := d.Subq
+	if inner.SetOp != nil || inner.With != nil || inner.Values != nil || len(inner.From) != 1 ||
+		len(inner.Columns) != 1 || !inner.Columns[0].Star || inner.Columns[0].Except != nil || len(inner.Hints) > 0 ||
+		inner.Distinct || inner.Where != nil || len(inner.GroupBy) > 0 || inner.Having != nil || inner.Qualify != nil ||
+		len(inner.OrderBy) > 0 || inner.LimitBy != nil || inner.Limit != nil {
+		return false
+	}
+	t, ok := inner.From[0].(*ast.SimpleTable)
+	if !ok {
+		return false
+	}
+	// (SELECT * FROM t) x is t AS x.
+	cp := *t
+	if d.Alias != nil {
+		cp.Alias = d.Alias
+	}
+	s.From[0] = &cp
+	return true
+}
+
+// pushDownPredicates pushes down the conditions of stmt and returns how
+// many it moved. A condition moved into a query may move on into the
+// tables of that query.
+func pushDownPredicates(stmt Statement) int {
+	moved := 0
+	for changed := true; changed; {
+		changed = false
+		ctes := map[*ast.TableRef]*ast.CTE{}
+		for _, o := range withOwners(stmt) {
+			for i := range (*o.with).CTEs {
+				if slot := soleRead(o, i); slot != nil {
+					ctes[slot] = &(*o.with).CTEs[i]
+				}
+			}
+		}
+		for _, s := range selectBlocks(stmt) {
+			if n := pushDownWhere(s, ctes); n > 0 {
+				moved += n
+				changed = true
+			}
+		}
+	}
+	return moved
+}
+
+// pushSource is a derived table or CTE read that conditions can move
+// into: the name that qualifies its columns, their names, and its query.
+type pushSource struct {
+	name  string
+	cols  []string
+	query *ast.SelectStmt
+}
+
+// pushDownWhere moves the terms of s's WHERE that it can into the tables
+// of s, and returns how many it moved. ctes are the CTE reads that can
+// take conditions.
+func pushDownWhere(s *ast.SelectStmt, ctes map[*ast.TableRef]*ast.CTE) int {
+	if s.Where == nil {
+		return 0
+	}
+	var sources []pushSource
+	for _, slot := range filteredSlots(refSlots(s.From), nil) {
+		if src, ok := newPushSource(*slot, ctes[slot]); ok {
+			sources = append(sources, src)
+		}
+	}
+	if len(sources) == 0 {
+		return 0
+	}
+	// Unqualified columns are only attributed when the table is alone.
+	_, join := s.From[0].(*ast.JoinTable)
+	alone := len(s.From) == 1 && !join
+	var kept []ast.Expr
+	for _, term := range andTerms(s.Where, nil) {
+		if !pushTerm(term, sources, alone) {
+			kept = append(kept, term)
+		}
+	}
+	moved := len(andTerms(s.Where, nil)) - len(kept)
+	if moved > 0 {
+		s.Where = conjoin(kept...)
+	}
+	return moved
+}
+
+// filteredSlots appends to out the FROM items among slots whose rows a
+// WHERE condition filters out: not those on the nullable side of an
+// outer join, where it turns matches into NULLs instead.
+func filteredSlots(slots []*ast.TableRef, out []*ast.TableRef) []*ast.TableRef {
+	for _, slot := range slots {
+		j, ok := (*slot).(*ast.JoinTable)
+		if !ok {
+			out = append(out, slot)
+			continue
+		}
+		switch j.Kind {
+		case ast.LeftJoin:
+			out = filteredSlots([]*ast.TableRef{&j.Left}, out)
+		case ast.RightJoin:
+			out = filteredSlots([]*ast.TableRef{&j.Right}, out)
+		case ast.FullJoin:
+		default:
+			out = filteredSlots([]*ast.TableRef{&j.Left, &j.Right}, out)
+		}
+	}
+	return out
+}
+
+// newPushSource describes ref, a derived table or a read of cte, or
+// reports false when it is neither or its columns are not known.
+func newPushSource(ref ast.TableRef, cte *ast.CTE) (pushSource, bool) {
+	var src pushSource
+	var names []*ast.Ident
+	switch t := ref.(type) {
+	case *ast.SubqueryTable:
+		if t.Alias != nil {
+			src.name = t.Alias.Unquoted
+		}
+		src.query, names = t.Subq, t.Columns
+	case *ast.SimpleTable:
+		if cte == nil {
+			return src, false
+		}
+		src.name = cte.Name.Unquoted
+		if t.Alias != nil {
+			src.name = t.Alias.Unquoted
+		}
+		src.query, names = cte.Subq, cte.Columns
+	default:
+		return src, false
+	}
+	first := firstBlock(src.query)
+	if slices.ContainsFunc(first.Columns, isStarColumn) {
+		return src, false
+	}
+	for _, n := range names {
+		src.cols = append(src.cols, n.Unquoted)
+	}
+	if len(names) == 0 {
+		for _, c := range first.Columns {
+			src.cols = append(src.cols, selectColumnName(c))
+		}
+	}
+	return src, true
+}
+
+// selectColumnName is the name a select list column goes by, or "" when
+// it has none that a condition can use.
+func selectColumnName(c ast.SelectColumn) string {
+	if c.Alias != nil {
+		return c.Alias.Unquoted
+	}
+	switch e := c.Expr.(type) {
+	case *ast.Ident:
+		return e.Unquoted
+	case *ast.QualifiedIdent:
+		return e.Parts[len(e.Parts)-1].Unquoted
+	}
+	return ""
+}
+
+// pushTerm moves term into the one source whose columns it reads, and
+// reports whether it did.
+func pushTerm(term ast.Expr, sources []pushSource, alone bool) bool {
+	var src *pushSource
+	cols := map[ast.Expr]int{}
+	resolve := func(ref ast.Expr) ast.Expr {
+		var qualifier, name string
+		switch x := ref.(type) {
+		case *ast.Ident:
+			name = x.Unquoted
+		case *ast.QualifiedIdent:
+			if len(x.Parts) != 2 {
+				return nil
+			}
+			qualifier, name = x.Parts[0].Unquoted, x.Parts[1].Unquoted
+		}
+		var from *pushSource
+		if qualifier == "" && alone {
+			from = &sources[0]
+		}
+		for i := range sources {
+			if qualifier != "" && ident.Equal(sources[i].name, qualifier, "") {
+				from = &sources[i]
+			}
+		}
+		if from == nil || src != nil && from != src {
+			return nil
+		}
+		src = from
+		same := func(c string) bool { return ident.Equal(c, name, "") }
+		i := slices.IndexFunc(src.cols, same)
+		if i < 0 || slices.IndexFunc(src.cols[i+1:], same) >= 0 {
+			return nil
+		}
+		cols[ref] = i
+		return ref
+	}
+	if pushCopy(term, resolve) == nil || src == nil {
+		return false
+	}
+	blocks := filterBlocks(src.query)
+	if blocks == nil {
+		return false
+	}
+	conds := make([]ast.Expr, len(blocks))
+	having := make([]bool, len(blocks))
+	for k, b := range blocks {
+		if len(b.Columns) != len(src.cols) {
+			return false
+		}
+		grouped := len(b.GroupBy) > 0 || b.Having != nil || slices.ContainsFunc(b.Columns, func(c ast.SelectColumn) bool { return hasAggregate(c.Expr) })
+		for _, i := range cols {
+			switch e := b.Columns[i].Expr; {
+			case hasAggregate(e):
+				having[k] = true
+			case grouped && !groupKey(b, i):
+				return false
+			}
+		}
+		conds[k] = pushCopy(term, func(ref ast.Expr) ast.Expr { return pushCopy(b.Columns[cols[ref]].Expr, nil) })
+		if conds[k] == nil {
+			return false
+		}
+	}
+	for k, b := range blocks {
+		if having[k] {
+			b.Having = conjoin(b.Having, conds[k])
+		} else {
+			b.Where = conjoin(b.Where, conds[k])
+		}
+	}
+	return true
+}
+
+// filterBlocks returns the SELECT blocks of q that a condition on its
+// rows goes into, or nil when filtering them first would change q's
+// result.
+func filterBlocks(q *ast.SelectStmt) []*ast.SelectStmt {
+	if q.Limit != nil || q.LimitBy != nil {
+		return nil
+	}
+	if q.SetOp != nil {
+		l, r := filterBlocks(q.SetOp.Left), filterBlocks(q.SetOp.Right)
+		if l == nil || r == nil {
+			return nil
+		}
+		return append(l, r...)
+	}
+	if q.Values != nil || q.Qualify != nil || slices.ContainsFunc(q.Columns, func(c ast.SelectColumn) bool { return isStarColumn(c) || hasWindow(c.Expr) }) {
+		return nil
+	}
+	return []*ast.SelectStmt{q}
+}
+
+func hasWindow(e ast.Expr) bool {
+	found := false
+	a := &auditor{
+		visit: func(string, string, accessKind) {},
+		node: func(n ast.Node, depth int) {
+			if f, ok := n.(*ast.FuncCall); ok && depth == 0 && f.Over != nil {
+				found = true
+			}
+		},
+	}
+	a.expr(e, &auditScope{})
+	return found
+}
+
+// groupKey reports whether the i-th column of b is one of its GROUP BY
+// keys, written out, by alias or by position.
+func groupKey(b *ast.SelectStmt, i int) bool {
+	r := newDialectRenderer(ConvertOptions{})
+	c := b.Columns[i]
+	text := r.renderExpr(c.Expr)
+	for _, k := range b.GroupBy {
+		switch x := k.(type) {
+		case *ast.Literal:
+			if x.Kind == lexer.INT && string(x.Raw) == strconv.Itoa(i+1) {
+				return true
+			}
+		case *ast.Ident:
+			if c.Alias != nil && ident.Equal(x.Unquoted, c.Alias.Unquoted, "") {
+				return true
+			}
+		}
+		if r.renderExpr(k) == text {
+			return true
+		}
+	}
+	return false
+}
+
+// conjoin ANDs the non-nil terms together, or returns nil if there are
+// none.
+func conjoin(terms ...ast.Expr) ast.Expr {
+	var e ast.Expr
+	for _, t := range terms {
+		switch {
+		case t == nil:
+		case e == nil:
+			e = t
+		default:
+			e = &ast.BinaryExpr{Left: e, Right: t, Op: lexer.AND, TokPos: e.Pos()}
+		}
+	}
+	return e
+}
+
+// perCallFuncs are the volatileFuncs whose result differs between calls
+// within one statement, unlike NOW(), so a condition calling them filters
+// differently when it runs on other rows.
+var perCallFuncs = map[string]bool{
+	"rand": true, "random": true, "randomblob": true, "uuid": true, "uuid_short": true,
+	"gen_random_uuid": true, "uuid_generate_v4": true, "nextval": true, "setval": true,
+	"clock_timestamp": true, "timeofday": true, "sysdate": true, "sleep": true, "pg_sleep": true,
+	"get_lock": true, "release_lock": true,
+}
+
+// pushCopy returns a copy of e with each column reference replaced by
+// col(ref), or copied when col is nil. It returns nil when col does, or
+// when e holds what cannot move into another query: a subquery, a
+// window function, a function in perCallFuncs, or a ? parameter, whose position
+// among the others would change.
+func pushCopy(e ast.Expr, col func(ast.Expr) ast.Expr) ast.Expr {
+	c := &pushCopier{col: col, ok: true}
+	out := c.copy(e)
+	if !c.ok {
+		return nil
+	}
+	return out
+}
+
+type pushCopier struct {
+	col func(ast.Expr) ast.Expr
+	ok  bool
+}
+
+func (c *pushCopier) copy(e ast.Expr) ast.Expr {
+	switch x := e.(type) {
+	case nil:
+		return nil
+	case *ast.Ident, *ast.QualifiedIdent:
+		if c.col == nil {
+			return copyColumnRef(x)
+		}
+		r := c.col(x)
+		c.ok = c.ok && r != nil
+		return r
+	case *ast.Literal:
+		cp := *x
+		return &cp
+	case *ast.NullLit:
+		cp := *x
+		return &cp
+	case *ast.Param:
+		if string(x.Raw) == "?" {
+			break
+		}
+		cp := *x
+		return &cp
+	case *ast.BinaryExpr:
+		cp := *x
+		cp.Left, cp.Right = c.copy(x.Left), c.copy(x.Right)
+		return &cp
+	case *ast.UnaryExpr:
+		cp := *x
+		cp.Expr = c.copy(x.Expr)
+		return &cp
+	case *ast.FuncCall:
+		if x.Over != nil || x.Name != nil && perCallFuncs[strings.ToLower(x.Name.Parts[len(x.Name.Parts)-1].Unquoted)] {
+			break
+		}
+		cp := *x
+		cp.Args = c.list(x.Args)
+		return &cp
+	case *ast.CaseExpr:
+		cp := *x
+		cp.Operand, cp.Else = c.copy(x.Operand), c.copy(x.Else)
+		cp.Whens = make([]ast.WhenClause, len(x.Whens))
+		for i, w := range x.Whens {
+			cp.Whens[i] = ast.WhenClause{Cond: c.copy(w.Cond), Result: c.copy(w.Result)}
+		}
+		return &cp
+	case *ast.BetweenExpr:
+		cp := *x
+		cp.Expr, cp.Lo, cp.Hi = c.copy(x.Expr), c.copy(x.Lo), c.copy(x.Hi)
+		return &cp
+	case *ast.InExpr:
+		if x.Subq != nil {
+			break
+		}
+		cp := *x
+		cp.Expr, cp.List = c.copy(x.Expr), c.list(x.List)
+		return &cp
+	case *ast.LikeExpr:
+		cp := *x
+		cp.Expr, cp.Pattern, cp.Escape = c.copy(x.Expr), c.copy(x.Pattern), c.copy(x.Escape)
+		return &cp
+	case *ast.IsNullExpr:
+		cp := *x
+		cp.Expr = c.copy(x.Expr)
+		return &cp
+	case *ast.CastExpr:
+		cp := *x
+		cp.Expr = c.copy(x.Expr)
+		return &cp
+	case *ast.IntervalExpr:
+		cp := *x
+		cp.Expr = c.copy(x.Expr)
+		return &cp
+	}
+	c.ok = false
+	return nil
+}
+
+func (c *pushCopier) list(l []ast.Expr) []ast.Expr {
+	out := make([]ast.Expr, len(l))
+	for i, e := range l {
+		out[i] = c.copy(e)
+	}
+	return out
+}
+
+func copyColumnRef(e ast.Expr) ast.Expr {
+	switch x := e.(type) {
+	case *ast.Ident:
+		cp := *x
+		return &cp
+	case *ast.QualifiedIdent:
+		parts := make([]*ast.Ident, len(x.Parts))
+		for i, p := range x.Parts {
+			cp := *p
+			parts[i] = &cp
+		}
+		return &ast.QualifiedIdent{Parts: parts}
+	}
+	return e
+}
+
+// orToUnion splits the ORs of stmt and returns how many it split.
+func orToUnion(stmt Statement, schema *Schema) int {
+	if schema == nil {
+		return 0
+	}
+	n := 0
+	for _, s := range selectBlocks(stmt) {
+		if splitOr(s, schema) {
+			n++
+		}
+	}
+	return n
+}
+
+// splitOr turns s into a UNION ALL of its OR's branches, and reports
+// whether it did.
+func splitOr(s *ast.SelectStmt, schema *Schema) bool {
+	if s.Where == nil || len(s.From) != 1 || s.Distinct || len(s.Hints) > 0 || len(s.GroupBy) > 0 || s.Having != nil ||
+		s.Qualify != nil || s.LimitBy != nil || s.Values != nil {
+		return false
+	}
+	t, ok := s.From[0].(*ast.SimpleTable)
+	if !ok || t.Sample != nil {
+		return false
+	}
+	table := schema.lookup(t.Name)
+	if table == nil || slices.ContainsFunc(s.Columns, func(c ast.SelectColumn) bool { return hasAggregate(c.Expr) || hasWindow(c.Expr) }) || hasParam(s) {
+		return false
+	}
+	for _, o := range s.OrderBy {
+		// The union can only sort by its output columns.
+		id, ok := o.Expr.(*ast.Ident)
+		if !ok {
+			return false
+		}
+		star := slices.ContainsFunc(s.Columns, func(c ast.SelectColumn) bool { return c.Star }) && table.Column(id.Unquoted) != nil
+		if !star && !slices.ContainsFunc(s.Columns, func(c ast.SelectColumn) bool { return ident.Equal(selectColumnName(c), id.Unquoted, table.dialect) }) {
+			return false
+		}
+	}
+	terms := andTerms(s.Where, nil)
+	for i, term := range terms {
+		groups := indexedDisjuncts(term, table)
+		if len(groups) < 2 {
+			continue
+		}
+		var union *ast.SelectStmt
+		var guards []ast.Expr
+		for _, g := range groups {
+			branch := &ast.SelectStmt{
+				Columns: slices.Clone(s.Columns),
+				From:    slices.Clone(s.From),
+				TokPos:  s.TokPos,
+			}
+			rest := slices.Clone(terms)
+			rest[i] = conjoin(append([]ast.Expr{g}, guards...)...)
+			branch.Where = conjoin(rest...)
+			guards = append(guards, notTrue(g, table))
+			if union == nil {
+				union = branch
+			} else {
+				union = &ast.SelectStmt{SetOp: &ast.SetOperation{Op: ast.Union, All: true, Left: union, Right: branch}, TokPos: s.TokPos}
+			}
+		}
+		union.With, union.OrderBy, union.Limit = s.With, s.OrderBy, s.Limit
+		*s = *union
+		return true
+	}
+	return false
+}
+
+// hasParam reports whether s holds a parameter, which would bind to the
+// wrong argument once s is repeated: converting renumbers parameters in
+// the order they are written.
+func hasParam(s *ast.SelectStmt) bool {
+	found := false
+	a := &auditor{
+		visit: func(string, string, accessKind) {},
+		node: func(n ast.Node, _ int) {
+			if _, ok := n.(*ast.Param); ok {
+				found = true
+			}
+		},
+	}
+	a.selectStmt(s, &auditScope{})
+	return found
+}
+
+// indexedDisjuncts returns the sides of the OR term grouped by the
+// indexed column each compares with constants, ORed together again
+// within a group, or nil when a side compares none.
+func indexedDisjuncts(term ast.Expr, table *Table) []ast.Expr {
+	var cols []string
+	var groups [][]ast.Expr
+	for _, d := range orTerms(term, nil) {
+		col := ""
+		for _, e := range andTerms(d, nil) {
+			if c, _, seek := termSelectivity(e); c != nil && seek && leadsIndex(table, columnName(c)) {
+				col = ident.Key(columnName(c), table.dialect)
+				break
+			}
+		}
+		if col == "" {
+			return nil
+		}
+		if i := slices.Index(cols, col); i >= 0 {
+			groups[i] = append(groups[i], d)
+			continue
+		}
+		cols = append(cols, col)
+		groups = append(groups, []ast.Expr{d})
+	}
+	out := make([]ast.Expr, len(groups))
+	for i, g := range groups {
+		out[i] = g[0]
+		for _, d := range g[1:] {
+			out[i] = &ast.BinaryExpr{Left: out[i], Right: d, Op: lexer.OR, TokPos: out[i].Pos()}
+		}
+	}
+	return out
+}
+
+// orTerms appends the top-level OR terms of e to terms.
+func orTerms(e ast.Expr, terms []ast.Expr) []ast.Expr {
+	if b, ok := e.(*ast.BinaryExpr); ok && b.Op == lexer.OR {
+		return orTerms(b.Right, orTerms(b.Left, terms))
+	}
+	return append(terms, e)
+}
+
+// notTrue returns a condition that holds where e is false or NULL.
+func notTrue(e ast.Expr, table *Table) ast.Expr {
+	not := &ast.UnaryExpr{Expr: e, Op: lexer.NOT, TokPos: e.Pos()}
+	if neverNull(e, table) {
+		return not
+	}
+	return &ast.BinaryExpr{Left: not, Right: &ast.IsNullExpr{Expr: e, TokPos: e.Pos()}, Op: lexer.OR, TokPos: e.Pos()}
+}
+
+// neverNull reports whether e cannot be NULL: it combines literals and
+// NOT NULL columns of table with operators that only return NULL for a
+// NULL operand, unlike division, which is NULL in MySQL when dividing by
+// zero.
+func neverNull(e ast.Expr, table *Table) bool {
+	switch x := e.(type) {
+	case *ast.Literal:
+		return true
+	case *ast.Ident, *ast.QualifiedIdent:
+		c := table.Column(columnName(x))
+		return c != nil && !c.Nullable
+	case *ast.BinaryExpr:
+		if x.Op == lexer.SLASH || x.Op == lexer.PERCENT {
+			return false
+		}
+		return neverNull(x.Left, table) && neverNull(x.Right, table)
+	case *ast.UnaryExpr:
+		return neverNull(x.Expr, table)
+	case *ast.IsNullExpr:
+		return true
+	case *ast.BetweenExpr:
+		return neverNull(x.Expr, table) && neverNull(x.Lo, table) && neverNull(x.Hi, table)
+	case *ast.InExpr:
+		return x.Subq == nil && neverNull(x.Expr, table) && !slices.ContainsFunc(x.List, func(e ast.Expr) bool { return !neverNull(e, table) })
+	case *ast.LikeExpr:
+		return x.Escape == nil && neverNull(x.Expr, table) && neverNull(x.Pattern, table)
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+package sqlparser_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestInlineCTEs(t *testing.T) {
+	tests := []struct{ src, want string }{
+		{"WITH big AS (SELECT id FROM orders WHERE total > 100) SELECT b.id FROM big b JOIN users u ON u.id = b.id",
+			"SELECT `b`.`id` FROM (SELECT `id` FROM `orders` WHERE (`total` > 100)) `b` JOIN `users` `u` ON (`u`.`id` = `b`.`id`)"},
+		{"WITH a AS (SELECT 1 AS n), b AS (SELECT n FROM a) SELECT * FROM b",
+			"SELECT * FROM (SELECT `n` FROM (SELECT 1 AS `n`) `a`) `b`"},
+		{"WITH t (x, y) AS (SELECT id, name FROM users) SELECT x FROM t",
+			"SELECT `x` FROM (SELECT `id` AS `x`, `name` AS `y` FROM `users`) `t`"},
+		{"WITH a AS (SELECT id FROM users), b AS (SELECT id FROM orders) SELECT * FROM a UNION SELECT * FROM a JOIN b USING (id)",
+			"WITH `a` AS (SELECT `id` FROM `users`) SELECT * FROM `a` UNION SELECT * FROM `a` JOIN (SELECT `id` FROM `orders`) `b` USING (`id`)"},
+		{"WITH a AS (SELECT id FROM users) SELECT id FROM users WHERE id IN (SELECT id FROM a)",
+			"SELECT `id` FROM `users` WHERE `id` IN (SELECT `id` FROM (SELECT `id` FROM `users`) `a`)"},
+		{"WITH unused AS (SELECT 1) SELECT 2", "WITH `unused` AS (SELECT 1) SELECT 2"},
+		{"WITH RECURSIVE n (i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 5) SELECT i FROM n",
+			"WITH RECURSIVE `n` (`i`) AS (SELECT 1 UNION ALL SELECT (`i` + 1) FROM `n` WHERE (`i` < 5)) SELECT `i` FROM `n`"},
+		{"WITH a AS (SELECT 1 AS x) UPDATE t SET v = 1 WHERE id IN (SELECT x FROM a)",
+			"UPDATE `t` SET `v` = 1 WHERE `id` IN (SELECT `x` FROM (SELECT 1 AS `x`) `a`)"},
+		{"INSERT INTO t (id) WITH a AS (SELECT 1 AS x) SELECT x FROM a",
+			"INSERT INTO `t` (`id`) SELECT `x` FROM (SELECT 1 AS `x`) `a`"},
+	}
+	for _, tt := range tests {
+		stmts, err := sqlparser.ParseStatements(tt.src)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.src, err)
+		}
+		got, _, err := sqlparser.ConvertStatements(sqlparser.InlineCTEs(stmts), sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s\ngot  %s\nwant %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestFlattenSubqueries(t *testing.T) {
+	tests := []struct{ src, want string }{
+		{"SELECT * FROM (SELECT id, name FROM users WHERE active) x", `SELECT "id", "name" FROM "users" WHERE "active"`},
+		{"SELECT * FROM (SELECT * FROM (SELECT id FROM users) y) x", `SELECT "id" FROM "users"`},
+		{"SELECT x.id FROM (SELECT * FROM users) x WHERE x.id > 1", `SELECT "x"."id" FROM "users" "x" WHERE ("x"."id" > 1)`},
+		{"SELECT * FROM (SELECT a FROM t UNION SELECT b FROM u) x", `SELECT "a" FROM "t" UNION SELECT "b" FROM "u"`},
+		{"SELECT id FROM users WHERE id IN (SELECT * FROM (SELECT uid FROM orders) o)", `SELECT "id" FROM "users" WHERE "id" IN (SELECT "uid" FROM "orders")`},
+		// Kept: the outer query or the derived table does something.
+		{"SELECT * FROM (SELECT id FROM users) x WHERE id > 1", `SELECT * FROM (SELECT "id" FROM "users") "x" WHERE ("id" > 1)`},
+		{"SELECT DISTINCT * FROM (SELECT id FROM users) x", `SELECT DISTINCT * FROM (SELECT "id" FROM "users") "x"`},
+		{"SELECT * FROM (SELECT id FROM users) x (n)", `SELECT * FROM (SELECT "id" FROM "users") "x" ("n")`},
+		{"SELECT * FROM (SELECT id FROM users) x, orders", `SELECT * FROM (SELECT "id" FROM "users") "x", "orders"`},
+	}
+	for _, tt := range tests {
+		stmts, err := sqlparser.ParseStatements(tt.src)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.src, err)
+		}
+		got, _, err := sqlparser.ConvertStatements(sqlparser.FlattenSubqueries(stmts), sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s\ngot  %s\nwant %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestInlineAndFlattenSurviveGC(t *testing.T) {
+	checkRewriteSurvivesGC(t, "WITH c AS (SELECT a FROM t WHERE b = 1) SELECT a FROM c WHERE a > 2", sqlparser.InlineCTEs)
+	checkRewriteSurvivesGC(t, "SELECT * FROM (SELECT * FROM t) x WHERE x.a = 1", sqlparser.FlattenSubqueries)
+}
+
+func TestOptimizerPasses(t *testing.T) {
+	var list []sqlparser.Pass
+	for _, name := range []string{"inline-ctes", "flatten-subqueries"} {
+		p, err := sqlparser.LookupPass(name, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list = append(list, p)
+	}
+	res, err := (&sqlparser.Pipeline{Convert: sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL}, Passes: list}).RunSQL("WITH a AS (SELECT id FROM users WHERE active) SELECT * FROM a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT `id` FROM `users` WHERE `active`"; res.SQL != want {
+		t.Errorf("pipeline: got %s, want %s", res.SQL, want)
+	}
+}
+
+func TestPushDownPredicates(t *testing.T) {
+	tests := []struct{ src, want string }{
+		{"SELECT * FROM (SELECT user_id, SUM(total) AS spent FROM orders GROUP BY user_id) s WHERE s.user_id = 7 AND s.spent > 100",
+			`SELECT * FROM (SELECT "user_id", SUM("total") AS "spent" FROM "orders" WHERE ("user_id" = 7) GROUP BY "user_id" HAVING (SUM("total") > 100)) "s"`},
+		{"WITH r AS MATERIALIZED (SELECT region, amount FROM sales) SELECT * FROM r WHERE region = 'EU'",
+			`WITH "r" AS MATERIALIZED (SELECT "region", "amount" FROM "sales" WHERE ("region" = 'EU')) SELECT * FROM "r"`},
+		{"SELECT * FROM (SELECT id FROM a UNION ALL SELECT uid FROM b) x WHERE x.id IN (1, 2)",
+			`SELECT * FROM (SELECT "id" FROM "a" WHERE "id" IN (1, 2) UNION ALL SELECT "uid" FROM "b" WHERE "uid" IN (1, 2)) "x"`},
+		{"SELECT * FROM (SELECT a AS k, COUNT(*) c FROM t GROUP BY 1) x (g, n) WHERE g = 1 OR g = 2",
+			`SELECT * FROM (SELECT "a" AS "k", COUNT(*) AS "c" FROM "t" WHERE (("a" = 1) OR ("a" = 2)) GROUP BY 1) "x" ("g", "n")`},
+		{"SELECT * FROM (SELECT uid, n FROM o) x LEFT JOIN users u ON x.uid = u.id WHERE x.n > 1 AND u.id > 2",
+			`SELECT * FROM (SELECT "uid", "n" FROM "o" WHERE ("n" > 1)) "x" LEFT JOIN "users" "u" ON ("x"."uid" = "u"."id") WHERE ("u"."id" > 2)`},
+		// Through two levels.
+		{"SELECT * FROM (SELECT a FROM (SELECT lower(b) AS a FROM t) y) x WHERE a LIKE 'q%'",
+			`SELECT * FROM (SELECT "a" FROM (SELECT LOWER("b") AS "a" FROM "t" WHERE LOWER("b") LIKE 'q%') "y") "x"`},
+		// Kept.
+		{"SELECT * FROM users u LEFT JOIN (SELECT uid, n FROM o) x ON x.uid = u.id WHERE x.n > 1",
+			`SELECT * FROM "users" "u" LEFT JOIN (SELECT "uid", "n" FROM "o") "x" ON ("x"."uid" = "u"."id") WHERE ("x"."n" > 1)`},
+		{"SELECT * FROM (SELECT id FROM t LIMIT 10) x WHERE id > 1",
+			`SELECT * FROM (SELECT "id" FROM "t" LIMIT 10) "x" WHERE ("id" > 1)`},
+		{"SELECT * FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY id) rn FROM t) x WHERE rn = 1",
+			`SELECT * FROM (SELECT "id", ROW_NUMBER() OVER (ORDER BY "id" ASC) AS "rn" FROM "t") "x" WHERE ("rn" = 1)`},
+		{"SELECT * FROM (SELECT id FROM t) x WHERE id > RAND() AND id = ?",
+			`SELECT * FROM (SELECT "id" FROM "t") "x" WHERE (("id" > RAND()) AND ("id" = $1))`},
+		{"SELECT * FROM (SELECT a, b FROM t GROUP BY a, b) x, u WHERE x.a = u.a",
+			`SELECT * FROM (SELECT "a", "b" FROM "t" GROUP BY "a", "b") "x", "u" WHERE ("x"."a" = "u"."a")`},
+		{"WITH c AS (SELECT a FROM t) SELECT * FROM c WHERE a = 1 UNION SELECT * FROM c WHERE a = 2",
+			`WITH "c" AS (SELECT "a" FROM "t") SELECT * FROM "c" WHERE ("a" = 1) UNION SELECT * FROM "c" WHERE ("a" = 2)`},
+	}
+	for _, tt := range tests {
+		stmts, err := sqlparser.ParseStatements(tt.src)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.src, err)
+		}
+		got, _, err := sqlparser.ConvertStatements(sqlparser.PushDownPredicates(stmts), sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s\ngot  %s\nwant %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestPushDownPredicatesSurvivesGC(t *testing.T) {
+	checkRewriteSurvivesGC(t, "SELECT * FROM (SELECT user_id, SUM(total) AS spent FROM orders WHERE total > 0 GROUP BY user_id HAVING COUNT(*) > 1) s WHERE s.user_id = 7 AND s.spent > 3", sqlparser.PushDownPredicates)
+}
+
+func TestOptimizeSQLForDialectPushDown(t *testing.T) {
+	in := "SELECT 1; SELECT * FROM (SELECT user_id, SUM(total) AS spent FROM orders GROUP BY user_id) s WHERE s.user_id = 7"
+	out, err := sqlparser.OptimizeSQLForDialect(in, sqlparser.DialectMySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Rewrites) != 1 {
+		t.Fatalf("rewrites: %+v", out.Rewrites)
+	}
+	rw := out.Rewrites[0]
+	if rw.Rule != "push-down-predicates" || rw.StatementIndex != 1 || !slices.Contains(out.Actions, rw.Action) {
+		t.Errorf("rewrite %+v, actions %q", rw, out.Actions)
+	}
+	if want := "SELECT * FROM (SELECT `user_id`, SUM(`total`) AS `spent` FROM `orders` GROUP BY `user_id`) `s` WHERE (`s`.`user_id` = 7)"; rw.Before != want {
+		t.Errorf("before: got %s, want %s", rw.Before, want)
+	}
+	if want := "SELECT * FROM (SELECT `user_id`, SUM(`total`) AS `spent` FROM `orders` WHERE (`user_id` = 7) GROUP BY `user_id`) `s`"; rw.After != want || !strings.Contains(out.OptimizedSQL, want) {
+		t.Errorf("after: got %s, want %s\nSQL: %s", rw.After, want, out.OptimizedSQL)
+	}
+}
+
+func TestOrToUnion(t *testing.T) {
+	schema, err := sqlparser.BuildSchema(`
+		CREATE TABLE users (id INT PRIMARY KEY, email TEXT NOT NULL, phone TEXT, name TEXT, active INT);
+		CREATE INDEX users_email ON users (email);
+		CREATE INDEX users_phone ON users (phone);`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct{ src, want string }{
+		{"SELECT id FROM users WHERE email = 'a' OR phone = '5'",
+			`SELECT "id" FROM "users" WHERE ("email" = 'a') UNION ALL SELECT "id" FROM "users" WHERE (("phone" = '5') AND (NOT ("email" = 'a')))`},
+		{"SELECT id, name FROM users WHERE active = 1 AND (phone = '5' OR email IN ('a', 'b') OR phone IS NULL) ORDER BY name LIMIT 10",
+			`SELECT "id", "name" FROM "users" WHERE (("active" = 1) AND (("phone" = '5') OR "phone" IS NULL)) UNION ALL ` +
+				`SELECT "id", "name" FROM "users" WHERE (("active" = 1) AND ("email" IN ('a', 'b') AND ((NOT (("phone" = '5') OR "phone" IS NULL)) OR (("phone" = '5') OR "phone" IS NULL) IS NULL))) ORDER BY "name" ASC LIMIT 10`},
+		{"SELECT * FROM users WHERE id = 7 OR email = 'a' ORDER BY id",
+			`SELECT * FROM "users" WHERE ("id" = 7) UNION ALL SELECT * FROM "users" WHERE (("email" = 'a') AND (NOT ("id" = 7))) ORDER BY "id" ASC`},
+		// Kept.
+		{"SELECT id FROM users WHERE email = 'a' OR email = 'b'", `SELECT "id" FROM "users" WHERE (("email" = 'a') OR ("email" = 'b'))`},
+		{"SELECT id FROM users WHERE email = 'a' OR name = 'b'", `SELECT "id" FROM "users" WHERE (("email" = 'a') OR ("name" = 'b'))`},
+		{"SELECT COUNT(*) FROM users WHERE email = 'a' OR phone = '5'", `SELECT COUNT(*) FROM "users" WHERE (("email" = 'a') OR ("phone" = '5'))`},
+		{"SELECT DISTINCT name FROM users WHERE email = 'a' OR phone = '5'", `SELECT DISTINCT "name" FROM "users" WHERE (("email" = 'a') OR ("phone" = '5'))`},
+		{"SELECT id FROM users u WHERE email = 'a' OR phone = '5' ORDER BY u.id", `SELECT "id" FROM "users" "u" WHERE (("email" = 'a') OR ("phone" = '5')) ORDER BY "u"."id" ASC`},
+		{"SELECT id FROM users WHERE id = ? OR email = 'a'", `SELECT "id" FROM "users" WHERE (("id" = $1) OR ("email" = 'a'))`},
+		{"SELECT id FROM orders WHERE email = 'a' OR phone = '5'", `SELECT "id" FROM "orders" WHERE (("email" = 'a') OR ("phone" = '5'))`},
+	}
+	for _, tt := range tests {
+		stmts, err := sqlparser.ParseStatements(tt.src)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.src, err)
+		}
+		got, _, err := sqlparser.ConvertStatements(sqlparser.OrToUnion(stmts, schema), sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s\ngot  %s\nwant %s", tt.src, got, tt.want)
+		}
+	}
+
+	in := "SELECT id FROM users WHERE email = 'a' OR phone = '5'"
+	out, err := sqlparser.OptimizeSQLForDialect(in, sqlparser.DialectMySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Rewrites) != 0 {
+		t.Errorf("split without the option: %+v", out.Rewrites)
+	}
+	out, err = sqlparser.OptimizeSQLWithOptions(in, sqlparser.OptimizationOptions{Dialect: sqlparser.DialectMySQL, Schema: schema, OrToUnion: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Rewrites) != 1 || out.Rewrites[0].Rule != "or-to-union" || !strings.Contains(out.OptimizedSQL, "UNION ALL") {
+		t.Errorf("rewrites %+v, SQL %s", out.Rewrites, out.OptimizedSQL)
+	}
+}
+
+func TestOrToUnionSurvivesGC(t *testing.T) {
+	schema, err := sqlparser.BuildSchema("CREATE TABLE users (id INT, email TEXT, phone TEXT, INDEX (email), INDEX (phone))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRewriteSurvivesGC(t, "SELECT id FROM users WHERE email = 'a' OR phone = 'b'", func(stmts []sqlparser.Statement) []sqlparser.Statement {
+		return sqlparser.OrToUnion(stmts, schema)
+	})
+}
@@ -0,0 +1,143 @@
+package sqlparser
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// BoundType is the SQL type Parameterize infers for a lifted literal.
+type BoundType string
+
+const (
+	BoundInteger BoundType = "integer"
+	// BoundNumeric is a decimal or exponent literal, or an integer too
+	// large for int64. Its value keeps the digits as a string, so no
+	// precision is lost binding it to a DECIMAL column.
+	BoundNumeric BoundType = "numeric"
+	BoundString  BoundType = "string"
+	BoundBoolean BoundType = "boolean"
+)
+
+// BoundValue is a literal Parameterize lifted out of a statement.
+type BoundValue struct {
+	// Value is an int64 for BoundInteger, a bool for BoundBoolean and a
+	// string otherwise.
+	Value any       `json:"value"`
+	Type  BoundType `json:"type"`
+}
+
+// Parameterize turns the constant literals of a single SELECT, INSERT,
+// UPDATE or DELETE into placeholders of the target dialect and returns the
+// rewritten statement with the lifted values in placeholder order, so a
+// proxy can run ad-hoc queries as prepared statements and share their
+// plans. A negated number is lifted as one negative value.
+//
+// NULL, hex and bit literals stay in the text, as do integers used as
+// positions in GROUP BY and ORDER BY. Other statements are rendered
+// without lifting, and a statement that already has placeholders is
+// rejected, since its arguments could not be merged with the lifted ones.
+func Parameterize(sql string, target Dialect) (string, []BoundValue, error) {
+	stmts, err := ParseStatements(sql)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(stmts) != 1 {
+		return "", nil, fmt.Errorf("sqlparser: Parameterize takes one statement, got %d", len(stmts))
+	}
+	stmt := stmts[0]
+	r := newDialectRenderer(ConvertOptions{Target: target})
+	r.recordParams = true
+	switch stmt.(type) {
+	case *ast.SelectStmt, *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+		r.literals = literalsLifted
+	}
+	out, err := r.renderStatement(stmt)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(r.params) > 0 {
+		return "", nil, fmt.Errorf("sqlparser: statement already has placeholder %s", r.params[0].Raw)
+	}
+	return out, r.lifted, nil
+}
+
+type literalMode uint8
+
+const (
+	literalsKept literalMode = iota
+	literalsLifted
+	literalsRedacted
+)
+
+// liftLiteral renders e, a literal or a negated number, as a placeholder
+// recording its value when literals are lifted, or as '?' when they are
+// redacted.
+func (r *dialectRenderer) liftLiteral(e ast.Expr) (string, bool) {
+	switch r.literals {
+	case literalsLifted:
+		v, ok := boundValue(e)
+		if !ok {
+			return "", false
+		}
+		r.lifted = append(r.lifted, v)
+		return r.renderParam(nil), true
+	case literalsRedacted:
+		if redactable(e) {
+			return redactedLiteral, true
+		}
+	}
+	return "", false
+}
+
+// renderLimit renders a LIMIT or OFFSET count.
+func (r *dialectRenderer) renderLimit(e ast.Expr) string {
+	if !r.keepLimit {
+		return r.renderExpr(e)
+	}
+	mode := r.literals
+	r.literals = literalsKept
+	out := r.renderExpr(e)
+	r.literals = mode
+	return out
+}
+
+// renderSortKey renders a GROUP BY or ORDER BY key. A bare integer refers
+// to a column of the select list, so it is never lifted or redacted.
+func (r *dialectRenderer) renderSortKey(e ast.Expr) string {
+	if lit, ok := e.(*ast.Literal); ok && lit.Kind == lexer.INT {
+		return string(lit.Raw)
+	}
+	return r.renderExpr(e)
+}
+
+func boundValue(e ast.Expr) (BoundValue, bool) {
+	neg := false
+	if u, ok := e.(*ast.UnaryExpr); ok && u.Op == lexer.MINUS {
+		neg, e = true, u.Expr
+	}
+	lit, ok := e.(*ast.Literal)
+	if !ok {
+		return BoundValue{}, false
+	}
+	raw := string(lit.Raw)
+	if neg {
+		raw = "-" + raw
+	}
+	switch lit.Kind {
+	case lexer.INT:
+		if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return BoundValue{Value: n, Type: BoundInteger}, true
+		}
+		return BoundValue{Value: raw, Type: BoundNumeric}, true
+	case lexer.FLOAT:
+		return BoundValue{Value: raw, Type: BoundNumeric}, true
+	case lexer.STRING:
+		return BoundValue{Value: unquoteString(lit.Raw, ""), Type: BoundString}, !neg
+	case lexer.TRUE_KW, lexer.FALSE_KW:
+		return BoundValue{Value: lit.Kind == lexer.TRUE_KW, Type: BoundBoolean}, !neg
+	}
+	return BoundValue{}, false
+}
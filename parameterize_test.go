@@ -0,0 +1,62 @@
+package sqlparser_test
+
+import (
+	"reflect"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestParameterize(t *testing.T) {
+	type bv = sqlparser.BoundValue
+	tests := []struct {
+		sql    string
+		target sqlparser.Dialect
+		want   string
+		values []bv
+	}{
+		{
+			"SELECT name, 1 FROM users WHERE id = 42 AND name <> 'bob''s' AND active = TRUE AND deleted IS NULL GROUP BY 1 ORDER BY 2 DESC LIMIT 10",
+			sqlparser.DialectPostgres,
+			`SELECT "name", $1 FROM "users" WHERE (((("id" = $2) AND ("name" != $3)) AND ("active" = $4)) AND "deleted" IS NULL) GROUP BY 1 ORDER BY 2 DESC LIMIT $5`,
+			[]bv{{int64(1), sqlparser.BoundInteger}, {int64(42), sqlparser.BoundInteger}, {"bob's", sqlparser.BoundString}, {true, sqlparser.BoundBoolean}, {int64(10), sqlparser.BoundInteger}},
+		},
+		{
+			"UPDATE accounts SET balance = balance - 19.99 WHERE id = -7",
+			sqlparser.DialectMySQL,
+			"UPDATE `accounts` SET `balance` = (`balance` - ?) WHERE (`id` = ?)",
+			[]bv{{"19.99", sqlparser.BoundNumeric}, {int64(-7), sqlparser.BoundInteger}},
+		},
+		{
+			"INSERT INTO t (a, b) VALUES (99999999999999999999, NULL)",
+			sqlparser.DialectSQLite,
+			`INSERT INTO "t" ("a", "b") VALUES (?, NULL)`,
+			[]bv{{"99999999999999999999", sqlparser.BoundNumeric}},
+		},
+		{
+			"CREATE TABLE t (n INT DEFAULT 0)",
+			sqlparser.DialectPostgres,
+			`CREATE TABLE "t" ("n" INT DEFAULT 0)`,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		got, values, err := sqlparser.Parameterize(tt.sql, tt.target)
+		if err != nil {
+			t.Errorf("%s: %v", tt.sql, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s:\ngot  %s\nwant %s", tt.sql, got, tt.want)
+		}
+		if !reflect.DeepEqual(values, tt.values) {
+			t.Errorf("%s: values %#v, want %#v", tt.sql, values, tt.values)
+		}
+	}
+
+	for _, sql := range []string{"SELECT 1; SELECT 2", "SELECT * FROM t WHERE id = ? AND n = 1", "SELECT FROM"} {
+		if _, _, err := sqlparser.Parameterize(sql, sqlparser.DialectMySQL); err == nil {
+			t.Errorf("%s: expected an error", sql)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package parser
 
-import "unsafe"
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"unsafe"
+)
 
 // arena is a monotonic bump allocator.
 // It pre-allocates a large slab and hands out slices from it.
@@ -9,17 +14,60 @@ import "unsafe"
 // When the current slab is exhausted, a new (larger) slab is allocated.
 // All slabs are released together on reset().
 //
-// For a typical SQL statement the primary slab (64 KiB) is sufficient,
+// For a typical SQL statement the primary slab (8 KiB) is sufficient,
 // meaning the entire parse produces zero net allocations after warm-up.
 type arena struct {
 	slabs [][]byte
 	cur   []byte
 	off   int
+	keep  *arenaKeep
+
+	// max caps the total slab bytes; 0 means unlimited.
+	max int
+	// total is the capacity of all live slabs, used the bytes handed out
+	// since the last reset and high the largest used seen so far.
+	total int
+	used  int
+	high  int
+}
+
+// ArenaStats describes a parser's arena memory.
+type ArenaStats struct {
+	// Slabs is the number of slabs currently held.
+	Slabs int
+	// Bytes is the total capacity of those slabs.
+	Bytes int
+	// Used is the number of bytes allocated by the current parse.
+	Used int
+	// HighWater is the largest Used seen since the parser was created.
+	HighWater int
+}
+
+// ErrArenaLimit is reported, wrapped in a ParseError, when a statement needs
+// more arena memory than Options.MaxArenaBytes allows.
+var ErrArenaLimit = errors.New("arena memory limit exceeded")
+
+// arenaLimitPanic unwinds a parse that hit the arena cap; ParseOne and
+// ParseAll recover it into a ParseError.
+type arenaLimitPanic struct{}
+
+// arenaKeep ties together everything an AST may point at. Slab memory is
+// not scanned by the garbage collector, so pointers stored inside nodes are
+// invisible to it. Every slab therefore starts with a pointer to the shared
+// arenaKeep: any reachable node keeps all slabs of its arena, and the source
+// its identifiers alias, alive.
+type arenaKeep struct {
+	slabs []unsafe.Pointer
+	src   []byte
 }
 
 const (
 	initialSlabSize = 8 * 1024 // 8 KiB
 	growFactor      = 2
+
+	// maxRetainedSlab bounds the slab kept by a pooled parser, so one huge
+	// statement does not pin its memory for the lifetime of the pool.
+	maxRetainedSlab = 64 * 1024
 )
 
 func (a *arena) alloc(n int) []byte {
@@ -30,36 +78,140 @@ func (a *arena) alloc(n int) []byte {
 		if size < n+8 {
 			size = n + initialSlabSize
 		}
-		slab := make([]byte, size)
+		size = slabClass(size)
+		if a.max > 0 && a.total+size > a.max {
+			panic(arenaLimitPanic{})
+		}
+		a.used += len(a.cur) - a.off // the unused tail is lost
+		slab := a.newSlab(size)
 		a.slabs = append(a.slabs, slab)
 		a.cur = slab
 		a.off = 0
 	}
 	out := a.cur[a.off : a.off+n]
 	a.off += n
+	a.used += n
 	return out[:n]
 }
 
+// copyBytes copies b into the arena, so a node can keep bytes built on the
+// heap: slabs are not scanned, and the heap copy would be collected.
+func (a *arena) copyBytes(b []byte) []byte {
+	out := a.alloc(len(b))
+	copy(out, b)
+	return out[:len(b):len(b)]
+}
+
+func (a *arena) stats() ArenaStats {
+	return ArenaStats{
+		Slabs:     len(a.slabs),
+		Bytes:     a.total,
+		Used:      a.used,
+		HighWater: max(a.high, a.used),
+	}
+}
+
 // reset releases all slabs and reinitialises the arena.
 // The first slab is retained to avoid re-allocation on the next parse.
 func (a *arena) reset() {
 	if len(a.slabs) > 0 {
 		first := a.slabs[0]
+		clear(a.slabs[1:])
 		a.slabs = a.slabs[:1]
+		clear(a.keep.slabs[1:])
+		a.keep.slabs = a.keep.slabs[:1]
 		a.cur = first
 		a.off = 0
+		a.total = len(first)
 	}
+	a.high = max(a.high, a.used)
+	a.used = 0
+}
+
+// release prepares the arena for a pool: the retained slab is zeroed so
+// stale ASTs cannot observe the next parse, and dropped entirely when it is
+// oversized.
+func (a *arena) release() {
+	a.reset()
+	if a.keep != nil {
+		a.keep.src = nil
+	}
+	if len(a.cur) > maxRetainedSlab {
+		*a = arena{}
+		return
+	}
+	clear(a.cur)
+	a.max, a.high = 0, 0
+}
+
+// handOff leaves the slabs to the ASTs already in them and starts over
+// with none, keeping the limit, the high-water mark and the source.
+func (a *arena) handOff() {
+	var src []byte
+	if a.keep != nil {
+		src = a.keep.src
+	}
+	*a = arena{max: a.max, high: max(a.high, a.used)}
+	a.setSource(src)
+}
+
+// setSource records the input the next parse aliases.
+func (a *arena) setSource(src []byte) {
+	if a.keep == nil {
+		a.keep = &arenaKeep{}
+	}
+	a.keep.src = src
 }
 
 // ensure the first slab exists
 func (a *arena) init() {
 	if a.cur == nil {
-		slab := make([]byte, initialSlabSize)
+		slab := a.newSlab(initialSlabSize)
 		a.slabs = append(a.slabs, slab)
 		a.cur = slab
 	}
 }
 
+// newSlab allocates a slab of at least size bytes whose header points at
+// the arena's arenaKeep.
+func (a *arena) newSlab(size int) []byte {
+	if a.keep == nil {
+		a.keep = &arenaKeep{}
+	}
+	size = slabClass(size)
+	a.total += size
+	base := reflect.New(slabType(size)).UnsafePointer()
+	*(**arenaKeep)(base) = a.keep
+	a.keep.slabs = append(a.keep.slabs, base)
+	return unsafe.Slice((*byte)(unsafe.Add(base, unsafe.Sizeof(uintptr(0)))), size)
+}
+
+// slabClass rounds size up to a power of two so only a handful of slab
+// types are ever built.
+func slabClass(size int) int {
+	c := initialSlabSize
+	for c < size {
+		c <<= 1
+	}
+	return c
+}
+
+var slabTypes sync.Map // int -> reflect.Type
+
+// slabType returns struct { K *arenaKeep; D [size]byte }: the collector
+// scans K and treats D as plain bytes.
+func slabType(size int) reflect.Type {
+	if t, ok := slabTypes.Load(size); ok {
+		return t.(reflect.Type)
+	}
+	t := reflect.StructOf([]reflect.StructField{
+		{Name: "K", Type: reflect.TypeFor[*arenaKeep]()},
+		{Name: "D", Type: reflect.ArrayOf(size, reflect.TypeFor[byte]())},
+	})
+	slabTypes.Store(size, t)
+	return t
+}
+
 // allocPtr returns a pointer into the arena for a single value of size n.
 func (a *arena) allocPtr(n uintptr) unsafe.Pointer {
 	b := a.alloc(int(n))
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// parseExplain parses EXPLAIN, DESCRIBE and DESC. The statement may be
+// preceded by a PostgreSQL option list, EXPLAIN (ANALYZE, FORMAT JSON), by
+// the unparenthesized ANALYZE and VERBOSE, by MySQL's FORMAT=name or by
+// SQLite's QUERY PLAN. A name in place of the statement is MySQL's
+// DESCRIBE table [column].
+func (p *Parser) parseExplain() (*ast.ExplainStmt, error) {
+	pos := p.tok.Pos
+	p.advance() // EXPLAIN | DESCRIBE | DESC
+	stmt := arenaNode(&p.arena, ast.ExplainStmt{TokPos: pos})
+	if p.is(lexer.LPAREN) {
+		if err := p.parseExplainOptions(stmt); err != nil {
+			return nil, err
+		}
+	}
+	for {
+		switch {
+		case p.is(lexer.ANALYZE):
+			p.advance()
+			stmt.Analyze = true
+			continue
+		case p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "verbose"):
+			stmt.Options = arenaAppend(&p.arena, stmt.Options, ast.TableOption{Key: p.advance().Raw})
+			continue
+		case p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "query") && equalASCIIFold(p.peekToken().Raw, "plan"):
+			p.advance()
+			p.advance()
+			stmt.QueryPlan = true
+			continue
+		case p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "format") && p.peekToken().Type == lexer.EQ:
+			p.advance()
+			p.advance()
+			if !isWordToken(p.tok) {
+				return nil, p.errorf("expected format name, got %q", p.tok.Raw)
+			}
+			stmt.Format = p.advance().Raw
+			continue
+		}
+		break
+	}
+	if p.is(lexer.IDENT) || p.is(lexer.BACKTICK) || p.is(lexer.DQUOTE) {
+		return p.parseDescribeTable(stmt)
+	}
+	inner, err := p.parseStatement()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Stmt = inner
+	return stmt, nil
+}
+
+// parseExplainOptions parses the PostgreSQL option list
+// (name [value], ...), keeping ANALYZE and FORMAT in their own fields.
+func (p *Parser) parseExplainOptions(stmt *ast.ExplainStmt) error {
+	p.advance() // (
+	for {
+		if !isWordToken(p.tok) {
+			return p.errorf("expected option name, got %q", p.tok.Raw)
+		}
+		opt := ast.TableOption{Key: p.advance().Raw}
+		if !p.is(lexer.COMMA) && !p.is(lexer.RPAREN) {
+			opt.Value = p.advance().Raw
+		}
+		switch {
+		case equalASCIIFold(opt.Key, "format"):
+			stmt.Format = opt.Value
+		case equalASCIIFold(opt.Key, "analyze"):
+			stmt.Analyze = opt.Value == nil || !isFalseOption(opt.Value)
+		default:
+			stmt.Options = arenaAppend(&p.arena, stmt.Options, opt)
+		}
+		if !p.tryEat(lexer.COMMA) {
+			break
+		}
+	}
+	_, err := p.eat(lexer.RPAREN)
+	return err
+}
+
+// isFalseOption reports whether a PostgreSQL boolean option value turns the
+// option off.
+func isFalseOption(v []byte) bool {
+	return equalASCIIFold(v, "false") || equalASCIIFold(v, "off") || string(v) == "0"
+}
+
+// parseDescribeTable parses the table [column] of DESCRIBE table.
+func (p *Parser) parseDescribeTable(stmt *ast.ExplainStmt) (*ast.ExplainStmt, error) {
+	name, err := p.parseQualifiedIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Table = name
+	if p.is(lexer.IDENT) || p.is(lexer.BACKTICK) || p.is(lexer.DQUOTE) {
+		if stmt.Column, err = p.parseIdent(); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"bytes"
+
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// parseHints reads the optimizer hints of a /*+ ... */ comment in the
+// source between start and end, the gap between SELECT and the token after
+// it. The lexer skips comments, so the hints are recovered from the source.
+func (p *Parser) parseHints(start, end int32) []ast.OptimizerHint {
+	src := p.lex.Source()
+	gap := src[start:end]
+	i := bytes.Index(gap, []byte("/*+"))
+	if i < 0 {
+		return nil
+	}
+	body := gap[i+3:]
+	if j := bytes.Index(body, []byte("*/")); j >= 0 {
+		body = body[:j]
+	}
+	base := start + int32(i) + 3
+	var hints []ast.OptimizerHint
+	for k := 0; k < len(body); {
+		c := body[k]
+		if !isHintNameByte(c) {
+			k++
+			continue
+		}
+		h := ast.OptimizerHint{TokPos: base + int32(k)}
+		nameStart := k
+		for k < len(body) && isHintNameByte(body[k]) {
+			k++
+		}
+		h.Name = body[nameStart:k]
+		for k < len(body) && isSpaceByte(body[k]) {
+			k++
+		}
+		if k < len(body) && body[k] == '(' {
+			depth := 0
+			argStart := k + 1
+			for ; k < len(body); k++ {
+				if body[k] == '(' {
+					depth++
+				} else if body[k] == ')' {
+					if depth--; depth == 0 {
+						break
+					}
+				}
+			}
+			h.Args = body[argStart:min(k, len(body))]
+			if trimmed := bytes.TrimSpace(h.Args); trimmed != nil {
+				h.Args = trimmed
+			} else {
+				h.Args = h.Args[:0] // keep () distinct from no parentheses
+			}
+			k++
+		}
+		hints = arenaAppend(&p.arena, hints, h)
+	}
+	return hints
+}
+
+func isHintNameByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
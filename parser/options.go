@@ -0,0 +1,209 @@
+package parser
+
+import (
+	"errors"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// Options tunes parser behaviour. The zero value is the strict parser used
+// by New, ParseStatement and ParseStatements.
+type Options struct {
+	// AllowIncomplete accepts a statement that is cut off mid-way, as happens
+	// with length-limited log lines. Instead of failing at end of input the
+	// parser returns the statement built so far and Incomplete reports true.
+	// Fields the input never reached are left nil.
+	AllowIncomplete bool
+
+	// MaxArenaBytes caps the arena memory a parser may hold. A statement
+	// that needs more fails with a ParseError wrapping ErrArenaLimit instead
+	// of growing the arena further. Slabs are sized in powers of two from
+	// 8 KiB, so the cap is reached in those steps. Zero means no limit.
+	MaxArenaBytes int
+
+	// MaxExpressionDepth bounds how deeply expressions and subqueries may
+	// nest, so hostile input cannot exhaust the stack of the recursive
+	// descent. Zero uses DefaultMaxExpressionDepth; a negative value
+	// disables the check.
+	MaxExpressionDepth int
+
+	// MaxStatementLength caps the length in bytes of a single statement.
+	// The parser stops at the first token past the limit instead of reading
+	// the rest. Zero means no limit.
+	MaxStatementLength int
+
+	// MaxErrors is the number of syntax errors reported for one statement
+	// before the parser gives up on it. Above 1, an error inside a clause of
+	// a SELECT, UPDATE or DELETE is recorded and parsing resumes at the next
+	// clause keyword; the statement then fails with ParseErrors listing every
+	// problem found. Zero or 1 stops at the first error.
+	MaxErrors int
+
+	// ExecuteVersionComments parses the body of MySQL version comments
+	// (/*!40101 ... */) as SQL, as mysql itself does. By default they are
+	// skipped like any other comment.
+	ExecuteVersionComments bool
+
+	// StandardStrings reads backslashes in quoted strings as ordinary
+	// characters, as PostgreSQL and SQLite do, so 'C:\dir\' is a whole
+	// string; see lexer.Options.StandardStrings. By default a backslash
+	// escapes the next character, as in MySQL.
+	StandardStrings bool
+
+	// StarExcept accepts BigQuery's * EXCEPT (col, ...) and DuckDB's
+	// * EXCLUDE (col, ...) after * or table.* in a select list. By default
+	// EXCEPT there starts a set operation, as in standard SQL.
+	StarExcept bool
+
+	// AnalyticsClauses accepts the clauses of analytics engines: QUALIFY,
+	// which filters on window functions after HAVING, and ClickHouse's
+	// LIMIT n [OFFSET m] BY expr, ... before the final LIMIT.
+	AnalyticsClauses bool
+}
+
+// DefaultMaxExpressionDepth is the nesting limit used when
+// Options.MaxExpressionDepth is zero. It is far beyond hand-written SQL and
+// far below what exhausts a goroutine stack.
+const DefaultMaxExpressionDepth = 1000
+
+var (
+	// ErrDepthLimit is reported, wrapped in a ParseError, when expressions or
+	// subqueries nest deeper than Options.MaxExpressionDepth.
+	ErrDepthLimit = errors.New("expression nesting limit exceeded")
+	// ErrStatementTooLong is reported, wrapped in a ParseError, when a
+	// statement is longer than Options.MaxStatementLength.
+	ErrStatementTooLong = errors.New("statement length limit exceeded")
+)
+
+// statementLengthPanic unwinds a parse that ran past MaxStatementLength;
+// parseTopLevel recovers it into a ParseError.
+type statementLengthPanic struct{}
+
+// NewWithOptions creates a Parser for the given SQL bytes using opts.
+func NewWithOptions(src []byte, opts Options) *Parser {
+	p := New(src)
+	p.setOptions(opts)
+	return p
+}
+
+// NewStringWithOptions creates a Parser for a SQL string using opts.
+func NewStringWithOptions(src string, opts Options) *Parser {
+	p := NewString(src)
+	p.setOptions(opts)
+	return p
+}
+
+func (p *Parser) setOptions(opts Options) {
+	p.opts = opts
+	if opts.ExecuteVersionComments || opts.StandardStrings {
+		// The first token was lexed before the options were known.
+		p.lex.SetOptions(lexer.Options{ExecuteVersionComments: opts.ExecuteVersionComments, StandardStrings: opts.StandardStrings})
+		p.init(p.lex.Source())
+	}
+	p.arena.max = opts.MaxArenaBytes
+	switch {
+	case opts.MaxExpressionDepth > 0:
+		p.maxDepth = opts.MaxExpressionDepth
+	case opts.MaxExpressionDepth < 0:
+		p.maxDepth = 0
+	default:
+		p.maxDepth = DefaultMaxExpressionDepth
+	}
+}
+
+// descend enters one level of expression or subquery nesting. Every
+// successful call must be paired with p.depth--.
+func (p *Parser) descend() error {
+	p.depth++
+	if p.maxDepth > 0 && p.depth > p.maxDepth {
+		p.depth--
+		e := p.errorf("expression nesting exceeds the limit of %d", p.maxDepth)
+		e.err = ErrDepthLimit
+		return e
+	}
+	return nil
+}
+
+// ArenaStats reports the parser's current arena memory use.
+func (p *Parser) ArenaStats() ArenaStats { return p.arena.stats() }
+
+// Incomplete reports whether the input ended before the last statement
+// returned by ParseOne or ParseAll was complete. It is only ever true when
+// Options.AllowIncomplete is set.
+func (p *Parser) Incomplete() bool { return p.incomplete }
+
+// StatementEnd returns the offset in the input where the last statement
+// returned by ParseOne or ParseAll ended: that of the semicolon or
+// delimiter after it, or of the end of input. Inside a routine body
+// defined after a DELIMITER line, semicolons do not end the statement.
+func (p *Parser) StatementEnd() int { return int(p.end) }
+
+// track records stmt as the statement being parsed, so that a truncated
+// parse can return it. Only the outermost statement is recorded.
+func (p *Parser) track(stmt ast.Statement) {
+	if p.partial == nil {
+		p.partial = stmt
+	}
+}
+
+// recoverTruncated turns a parse error caused by running out of input into
+// the partial statement when AllowIncomplete is set.
+func (p *Parser) recoverTruncated(err error) (ast.Statement, bool) {
+	if !p.opts.AllowIncomplete || p.tok.Type != lexer.EOF || p.partial == nil {
+		return nil, false
+	}
+	if list, ok := err.(ParseErrors); ok && len(list) > 1 {
+		return nil, false // real syntax errors before the cut-off
+	}
+	p.incomplete = true
+	return p.partial, true
+}
+
+// checkTruncated flags a statement that parsed cleanly but whose last token
+// is a quoted literal or identifier missing its closing quote.
+func (p *Parser) checkTruncated(stmt ast.Statement) {
+	if !p.opts.AllowIncomplete || p.tok.Type != lexer.EOF {
+		return
+	}
+	var l lexer.Lexer
+	l.Init(p.lex.Source()[stmt.Pos():])
+	var last lexer.Token
+	for t := l.Next(); t.Type != lexer.EOF; t = l.Next() {
+		last = t
+	}
+	if unterminated(last) {
+		p.incomplete = true
+	}
+}
+
+// unterminated reports whether a quoted token runs to end of input without
+// its closing delimiter.
+func unterminated(t lexer.Token) bool {
+	raw := t.Raw
+	open := 0
+	switch t.Type {
+	case lexer.STRING, lexer.DQUOTE, lexer.BACKTICK:
+	case lexer.HEXLIT, lexer.BITLIT:
+		if len(raw) < 2 || raw[1] != '\'' {
+			return false // 0x... form
+		}
+		open = 1
+	default:
+		return false
+	}
+	delim := raw[open]
+	for i := open + 1; i < len(raw); i++ {
+		switch c := raw[i]; {
+		case c == delim:
+			if i+1 < len(raw) && raw[i+1] == delim {
+				i++
+				continue
+			}
+			return false
+		case c == '\\' && delim != '`':
+			i++
+		}
+	}
+	return true
+}
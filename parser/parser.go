@@ -6,8 +6,12 @@ package parser
 import (
 	"bytes"
 	"fmt"
+	"iter"
+	"math"
 	"strconv"
+	"strings"
 	"sync"
+	"unicode/utf8"
 	"unsafe"
 
 	"github.com/oarkflow/sqlparser/ast"
@@ -20,12 +24,50 @@ type ParseError struct {
 	Pos  int32
 	Line uint32
 	Col  uint32
+
+	// Token is the token the parser stopped at. Its Raw is a copy, so the
+	// error stays usable after the source buffer is reused.
+	Token lexer.Token
+	// Expected lists the token types that would have been accepted at Pos,
+	// when the parser knows them.
+	Expected []lexer.TokenType
+	// Snippet is the source line containing Pos, prefixed with its line
+	// number, followed by a line with carets under the offending token.
+	Snippet string
+
+	// err is the sentinel behind a resource-limit failure, if any.
+	err error
 }
 
 func (e *ParseError) Error() string {
 	return fmt.Sprintf("parse error at line %d col %d: %s", e.Line, e.Col, e.Msg)
 }
 
+// Detail renders the error for display to a person: the message, the
+// expected tokens if known, and the source excerpt.
+func (e *ParseError) Detail() string {
+	var b strings.Builder
+	b.WriteString(e.Error())
+	if len(e.Expected) > 0 {
+		b.WriteString("\nexpected one of: ")
+		for i, t := range e.Expected {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(t.String())
+		}
+	}
+	if e.Snippet != "" {
+		b.WriteByte('\n')
+		b.WriteString(e.Snippet)
+	}
+	return b.String()
+}
+
+// Unwrap returns the sentinel error (such as ErrArenaLimit) that caused the
+// failure, or nil for ordinary syntax errors.
+func (e *ParseError) Unwrap() error { return e.err }
+
 // Parser converts a stream of tokens into an AST.
 // It maintains a 2-token lookahead for decisions that require peeking ahead.
 type Parser struct {
@@ -37,36 +79,106 @@ type Parser struct {
 	// arena is a monotonic allocator that owns all AST node memory.
 	// Reusing the arena across parse calls (after Reset) avoids GC spikes.
 	arena arena
+
+	opts Options
+	// partial is the outermost statement of the current parse, kept so a
+	// truncated statement can still be returned under AllowIncomplete.
+	partial    ast.Statement
+	incomplete bool
+
+	// depth is the current expression / subquery nesting, bounded by
+	// maxDepth (0 = unbounded). stmtEnd is the offset the statement being
+	// parsed may not run past under Options.MaxStatementLength, or 0.
+	depth    int
+	maxDepth int
+	stmtEnd  int32
+	// end is the offset where the last statement parsed ended; see
+	// StatementEnd.
+	end int32
+
+	// errs holds the syntax errors recovered from in the current statement
+	// under Options.MaxErrors.
+	errs []*ParseError
+
+	// upsert is the INSERT whose update part is being parsed, so that
+	// references to the new row parse as InsertedRef; upsertForm is
+	// InsertedExcluded for ON CONFLICT and InsertedAlias for ON DUPLICATE
+	// KEY UPDATE.
+	upsert     *ast.InsertStmt
+	upsertForm ast.InsertedForm
 }
 
-// parserPool amortises Parser allocation for the convenience API
-// (ParseStatement / ParseStatements).
+// parserPool backs Acquire / Release.
 var parserPool = sync.Pool{
-	New: func() any { return &Parser{} },
+	New: func() any { return &Parser{maxDepth: DefaultMaxExpressionDepth} },
 }
 
 // New creates a Parser for the given SQL bytes.
 func New(src []byte) *Parser {
-	p := &Parser{}
-	p.lex.Init(src)
-	p.tok = p.lex.Next()
+	p := &Parser{maxDepth: DefaultMaxExpressionDepth}
+	p.init(src)
 	return p
 }
 
 // NewString creates a Parser for a SQL string.
 func NewString(src string) *Parser {
-	p := &Parser{}
-	p.lex.InitString(src)
-	p.tok = p.lex.Next()
+	p := &Parser{maxDepth: DefaultMaxExpressionDepth}
+	p.init(unsafe.Slice(unsafe.StringData(src), len(src)))
 	return p
 }
 
 // Reset reuses the parser with new input, reusing internal memory.
+// Statements returned before the call must no longer be used.
 func (p *Parser) Reset(src []byte) {
+	p.arena.reset()
+	p.init(src)
+}
+
+// Retain gives the statements parsed so far the parser's arena, so they
+// stay valid after Reset and Release, and makes later statements use a new
+// one. The next parse cannot reuse the warm slab, so it allocates.
+func (p *Parser) Retain() {
+	p.arena.handOff()
+}
+
+func (p *Parser) init(src []byte) {
 	p.lex.Init(src)
 	p.tok = p.lex.Next()
 	p.hasPeek = false
-	p.arena.reset()
+	p.arena.setSource(src)
+	p.partial = nil
+	p.incomplete = false
+	p.errs = p.errs[:0]
+	p.upsert = nil
+}
+
+// SetDelimiter sets the statement delimiter, as a DELIMITER line in the
+// input would, until the next Reset. Input split on a custom delimiter
+// elsewhere, as StreamParser does, needs it so the semicolons of a stored
+// routine body do not end the statement.
+func (p *Parser) SetDelimiter(d []byte) { p.lex.SetDelimiter(d) }
+
+// Acquire returns a Parser from a shared pool. Give it input with Reset and
+// hand it back with Release once no statement it produced is in use.
+func Acquire() *Parser {
+	return parserPool.Get().(*Parser)
+}
+
+// Release returns p to the pool. The retained arena memory is zeroed, so a
+// statement that is wrongly kept past Release reads empty nodes rather than
+// another caller's query.
+func Release(p *Parser) {
+	p.arena.release()
+	p.lex.Init(nil)
+	p.tok = lexer.Token{}
+	p.peek = lexer.Token{}
+	p.hasPeek = false
+	p.opts = Options{}
+	p.lex.SetOptions(lexer.Options{})
+	p.maxDepth = DefaultMaxExpressionDepth
+	p.partial = nil
+	p.incomplete = false
+	parserPool.Put(p)
 }
 
 // ParseOne parses a single SQL statement.
@@ -75,10 +187,15 @@ func (p *Parser) ParseOne() (ast.Statement, error) {
 	if p.tok.Type == lexer.EOF {
 		return nil, nil
 	}
-	stmt, err := p.parseStatement()
+	p.partial = nil
+	stmt, err := p.parseTopLevel()
 	if err != nil {
+		if partial, ok := p.recoverTruncated(err); ok {
+			return partial, nil
+		}
 		return nil, err
 	}
+	p.checkTruncated(stmt)
 	p.skipSemis()
 	return stmt, nil
 }
@@ -91,37 +208,48 @@ func (p *Parser) ParseAll() ([]ast.Statement, error) {
 		if p.tok.Type == lexer.EOF {
 			break
 		}
-		stmt, err := p.parseStatement()
+		p.partial = nil
+		stmt, err := p.parseTopLevel()
 		if err != nil {
+			if partial, ok := p.recoverTruncated(err); ok {
+				return append(stmts, partial), nil
+			}
 			return stmts, err
 		}
+		p.checkTruncated(stmt)
 		stmts = append(stmts, stmt)
 	}
 	return stmts, nil
 }
 
+// Iter returns an iterator over the remaining statements. Iteration stops
+// after the first error, which is yielded with a nil statement.
+func (p *Parser) Iter() iter.Seq2[ast.Statement, error] {
+	return func(yield func(ast.Statement, error) bool) {
+		for {
+			stmt, err := p.ParseOne()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if stmt == nil || !yield(stmt, nil) {
+				return
+			}
+		}
+	}
+}
+
 // ParseStatement is the public entrypoint for parsing a single statement.
+// The returned AST owns its arena, so it stays valid independently of later
+// calls; use a reused Parser or Acquire when allocation matters more.
 func ParseStatement(src string) (ast.Statement, error) {
-	p := parserPool.Get().(*Parser)
-	p.lex.InitString(src)
-	p.tok = p.lex.Next()
-	p.hasPeek = false
-	p.arena.reset()
-	stmt, err := p.ParseOne()
-	parserPool.Put(p)
-	return stmt, err
+	return NewString(src).ParseOne()
 }
 
-// ParseStatements parses multiple statements.
+// ParseStatements parses multiple statements. Like ParseStatement, the
+// returned ASTs stay valid independently of later calls.
 func ParseStatements(src string) ([]ast.Statement, error) {
-	p := parserPool.Get().(*Parser)
-	p.lex.InitString(src)
-	p.tok = p.lex.Next()
-	p.hasPeek = false
-	p.arena.reset()
-	stmts, err := p.ParseAll()
-	parserPool.Put(p)
-	return stmts, err
+	return NewString(src).ParseAll()
 }
 
 // ---- internal helpers ----
@@ -134,6 +262,10 @@ func (p *Parser) advance() lexer.Token {
 	} else {
 		p.tok = p.lex.Next()
 	}
+	if p.stmtEnd > 0 && p.tok.Pos+int32(len(p.tok.Raw)) > p.stmtEnd &&
+		p.tok.Type != lexer.SEMICOLON && p.tok.Type != lexer.DELIMITER && p.tok.Type != lexer.EOF {
+		panic(statementLengthPanic{})
+	}
 	return prev
 }
 
@@ -146,7 +278,7 @@ func (p *Parser) peekToken() lexer.Token {
 }
 
 func (p *Parser) skipSemis() {
-	for p.tok.Type == lexer.SEMICOLON {
+	for p.tok.Type == lexer.SEMICOLON || p.tok.Type == lexer.DELIMITER {
 		p.advance()
 	}
 }
@@ -161,14 +293,14 @@ func (p *Parser) isKeyword(kw lexer.TokenType) bool {
 
 func (p *Parser) eat(typ lexer.TokenType) (lexer.Token, error) {
 	if p.tok.Type != typ {
-		return p.tok, p.errorf("expected %s, got %s (%q)", typ, p.tok.Type, p.tok.Raw)
+		return p.tok, p.expectf([]lexer.TokenType{typ}, "expected %s, got %s (%q)", typ, p.tok.Type, p.tok.Raw)
 	}
 	return p.advance(), nil
 }
 
 func (p *Parser) eatKeyword(kw lexer.TokenType) error {
 	if p.tok.Type != kw {
-		return p.errorf("expected keyword %s, got %q", kw, p.tok.Raw)
+		return p.expectf([]lexer.TokenType{kw}, "expected keyword %s, got %q", kw, p.tok.Raw)
 	}
 	p.advance()
 	return nil
@@ -191,15 +323,95 @@ func (p *Parser) tryEatKeyword(kw lexer.TokenType) bool {
 }
 
 func (p *Parser) errorf(format string, args ...any) *ParseError {
-	line, col := lexer.ComputeLineCol(p.lex.Source(), int(p.tok.Pos))
+	src := p.lex.Source()
+	line, col := lexer.ComputeLineCol(src, int(p.tok.Pos))
+	tok := p.tok
+	tok.Raw = bytes.Clone(tok.Raw)
 	return &ParseError{
-		Msg:  fmt.Sprintf(format, args...),
-		Pos:  p.tok.Pos,
-		Line: line,
-		Col:  col,
+		Msg:     fmt.Sprintf(format, args...),
+		Pos:     p.tok.Pos,
+		Line:    line,
+		Col:     col,
+		Token:   tok,
+		Snippet: sourceSnippet(src, int(p.tok.Pos), len(p.tok.Raw), line),
+	}
+}
+
+// expectf is errorf for a failure where the acceptable tokens are known.
+func (p *Parser) expectf(expected []lexer.TokenType, format string, args ...any) *ParseError {
+	e := p.errorf(format, args...)
+	e.Expected = expected
+	return e
+}
+
+// sourceSnippet renders the line of src containing pos with carets under
+// the n bytes starting there.
+func sourceSnippet(src []byte, pos, n int, line uint32) string {
+	pos = min(pos, len(src))
+	start := bytes.LastIndexByte(src[:pos], '\n') + 1
+	end := len(src)
+	if i := bytes.IndexByte(src[pos:], '\n'); i >= 0 {
+		end = pos + i
+	}
+	text := bytes.TrimRight(src[start:end], "\r")
+	n = max(1, min(n, len(text)-(pos-start)))
+
+	prefix := strconv.FormatUint(uint64(line), 10) + " | "
+	var b strings.Builder
+	b.WriteString(prefix)
+	b.Write(text)
+	b.WriteByte('\n')
+	b.WriteString(strings.Repeat(" ", len(prefix)-2))
+	b.WriteString("| ")
+	for _, c := range src[start:pos] {
+		if c == '\t' {
+			b.WriteByte('\t')
+		} else {
+			b.WriteByte(' ')
+		}
 	}
+	b.WriteString(strings.Repeat("^", n))
+	return b.String()
+}
+
+var (
+	identTokens      = []lexer.TokenType{lexer.IDENT, lexer.BACKTICK, lexer.DQUOTE}
+	constraintStarts = []lexer.TokenType{lexer.PRIMARY, lexer.UNIQUE, lexer.INDEX, lexer.KEY, lexer.FOREIGN, lexer.CHECK}
+)
+
+// statementStarts are the tokens parseStatement dispatches on; IDENT-led
+// statements (BEGIN, COMMIT, CALL, ...) are recognised by word.
+var statementStarts = []lexer.TokenType{
+	lexer.SELECT, lexer.WITH, lexer.INSERT, lexer.REPLACE, lexer.UPDATE, lexer.DELETE,
+	lexer.CREATE, lexer.ALTER, lexer.DROP, lexer.TRUNCATE, lexer.USE, lexer.ROLLBACK,
+	lexer.SET, lexer.SHOW, lexer.EXPLAIN, lexer.DESC, lexer.ANALYZE, lexer.CHECK, lexer.VALUES, lexer.LPAREN, lexer.IDENT,
 }
 
+// Constant byte strings stored in AST nodes. They must be package-level:
+// arena memory is not scanned by the garbage collector, so a per-call heap
+// copy referenced only from a node could be freed underneath it.
+var (
+	verbCreate = []byte("create")
+	verbAlter  = []byte("alter")
+	verbDrop   = []byte("drop")
+
+	actionBegin            = []byte("begin")
+	actionCommit           = []byte("commit")
+	actionRollback         = []byte("rollback")
+	actionStartTransaction = []byte("start_transaction")
+	actionSavepoint        = []byte("savepoint")
+	actionReleaseSavepoint = []byte("release_savepoint")
+	actionSetTransaction   = []byte("set_transaction")
+
+	typeArray = []byte("ARRAY")
+
+	literalOne = []byte("1")
+)
+
+// nullsFirst and nullsLast are what IndexColDef.NullsFirst points at:
+// nodes live in arena memory, which the garbage collector does not scan.
+var nullsFirst, nullsLast = true, false
+
 func arenaNode[T any](a *arena, v T) *T {
 	n := (*T)(a.allocPtr(unsafe.Sizeof(v)))
 	*n = v
@@ -208,9 +420,42 @@ func arenaNode[T any](a *arena, v T) *T {
 
 // ---- statement dispatch ----
 
+// parseTopLevel parses one statement, turning a resource-limit abort deep
+// inside the parser into a ParseError.
+func (p *Parser) parseTopLevel() (stmt ast.Statement, err error) {
+	p.depth = 0
+	p.errs = p.errs[:0]
+	if n := p.opts.MaxStatementLength; n > 0 && n <= math.MaxInt32-int(p.tok.Pos) {
+		p.stmtEnd = p.tok.Pos + int32(n)
+	}
+	defer func() {
+		p.stmtEnd = 0
+		if r := recover(); r != nil {
+			var e *ParseError
+			switch r.(type) {
+			case arenaLimitPanic:
+				e = p.errorf("statement exceeds the arena limit of %d bytes", p.arena.max)
+				e.err = ErrArenaLimit
+			case statementLengthPanic:
+				e = p.errorf("statement exceeds the length limit of %d bytes", p.opts.MaxStatementLength)
+				e.err = ErrStatementTooLong
+			default:
+				panic(r)
+			}
+			stmt, err = nil, e
+		}
+		if err = p.collectErrors(err); err != nil {
+			stmt = nil
+		}
+	}()
+	stmt, err = p.parseStatement()
+	p.end = p.tok.Pos
+	return stmt, err
+}
+
 func (p *Parser) parseStatement() (ast.Statement, error) {
 	switch p.tok.Type {
-	case lexer.SELECT:
+	case lexer.SELECT, lexer.VALUES, lexer.LPAREN:
 		return p.parseSelect()
 	case lexer.WITH:
 		return p.parseWithStatement()
@@ -238,22 +483,29 @@ func (p *Parser) parseStatement() (ast.Statement, error) {
 		return p.parseSetStmt()
 	case lexer.SHOW:
 		return p.parseShow()
-	case lexer.EXPLAIN:
+	case lexer.EXPLAIN, lexer.DESC:
 		return p.parseExplain()
+	case lexer.ANALYZE:
+		return p.parseAnalyze()
+	case lexer.CHECK:
+		return p.parseMySQLTableMaintenance(ast.CheckTable)
 	case lexer.IDENT:
 		return p.parseIdentLedStatement()
 	default:
-		return nil, p.errorf("unexpected token %q at start of statement", p.tok.Raw)
+		return nil, p.expectf(statementStarts, "unexpected token %q at start of statement", p.tok.Raw)
 	}
 }
 
 func (p *Parser) parseWithStatement() (ast.Statement, error) {
 	with, err := p.parseWith()
+	// A CTE body is not the statement being parsed; let the main statement
+	// register itself instead.
+	p.partial = nil
 	if err != nil {
 		return nil, err
 	}
 	switch p.tok.Type {
-	case lexer.SELECT:
+	case lexer.SELECT, lexer.VALUES:
 		stmt, err := p.parseSelect()
 		if err != nil {
 			return nil, err
@@ -289,7 +541,8 @@ func (p *Parser) parseWithStatement() (ast.Statement, error) {
 		stmt.With = with
 		return stmt, nil
 	default:
-		return nil, p.errorf("WITH must be followed by SELECT/INSERT/UPDATE/DELETE, got %q", p.tok.Raw)
+		return nil, p.expectf([]lexer.TokenType{lexer.SELECT, lexer.INSERT, lexer.REPLACE, lexer.UPDATE, lexer.DELETE},
+			"WITH must be followed by SELECT/INSERT/UPDATE/DELETE, got %q", p.tok.Raw)
 	}
 }
 
@@ -307,14 +560,32 @@ func (p *Parser) parseIdentLedStatement() (ast.Statement, error) {
 		return p.parseReleaseSavepoint()
 	case equalASCIIFold(p.tok.Raw, "call"):
 		return p.parseCall()
+	case equalASCIIFold(p.tok.Raw, "describe"):
+		return p.parseExplain()
+	case equalASCIIFold(p.tok.Raw, "vacuum"):
+		return p.parseVacuum()
+	case equalASCIIFold(p.tok.Raw, "reindex"):
+		return p.parseReindex()
+	case equalASCIIFold(p.tok.Raw, "cluster"):
+		return p.parseCluster()
+	case equalASCIIFold(p.tok.Raw, "optimize"):
+		return p.parseMySQLTableMaintenance(ast.OptimizeTable)
+	case equalASCIIFold(p.tok.Raw, "pragma"):
+		return p.parsePragma()
+	case equalASCIIFold(p.tok.Raw, "attach"), equalASCIIFold(p.tok.Raw, "detach"):
+		return p.parseAttach()
 	default:
-		return nil, p.errorf("unexpected token %q at start of statement", p.tok.Raw)
+		return nil, p.expectf(statementStarts, "unexpected token %q at start of statement", p.tok.Raw)
 	}
 }
 
 // ---- SELECT ----
 
 func (p *Parser) parseSelect() (*ast.SelectStmt, error) {
+	if err := p.descend(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
 	pos := p.tok.Pos
 	var with *ast.WithClause
 	var err error
@@ -324,50 +595,104 @@ func (p *Parser) parseSelect() (*ast.SelectStmt, error) {
 			return nil, err
 		}
 	}
-	stmt, err := p.parseSelectCore(pos)
+	stmt, err := p.parseSetExpr(0)
 	if err != nil {
 		return nil, err
 	}
-	stmt.With = with
+	// Only a parenthesized query already has these; the outer clauses
+	// cannot be merged into its own.
+	if with != nil {
+		if stmt.With != nil {
+			return nil, p.errorf("a parenthesized query with its own WITH cannot take another; wrap it in SELECT * FROM (...)")
+		}
+		stmt.With, stmt.TokPos = with, pos
+	}
+	if (len(stmt.OrderBy) > 0 || stmt.LimitBy != nil || stmt.Limit != nil) && (p.is(lexer.ORDER) || p.is(lexer.LIMIT) || p.is(lexer.OFFSET) || p.isFetch()) {
+		return nil, p.errorf("a parenthesized query with its own ORDER BY or LIMIT cannot take another; wrap it in SELECT * FROM (...)")
+	}
+	if err := p.parseOrderLimit(stmt); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
 
-	// Handle UNION / INTERSECT / EXCEPT
+// setOpPrecedence returns the set operation the current token starts and
+// how tightly it binds: INTERSECT before UNION and EXCEPT.
+func (p *Parser) setOpPrecedence() (ast.SetOp, int, bool) {
+	switch p.tok.Type {
+	case lexer.UNION:
+		return ast.Union, 1, true
+	case lexer.EXCEPT:
+		return ast.Except, 1, true
+	case lexer.INTERSECT:
+		return ast.Intersect, 2, true
+	}
+	return 0, 0, false
+}
+
+// parseSetExpr parses SELECT blocks joined by set operations binding at
+// least as tightly as minPrec, grouping operators of equal precedence from
+// the left.
+func (p *Parser) parseSetExpr(minPrec int) (*ast.SelectStmt, error) {
+	left, err := p.parseSetOperand()
+	if err != nil {
+		return nil, err
+	}
 	for {
-		var op ast.SetOp
-		switch p.tok.Type {
-		case lexer.UNION:
-			op = ast.Union
-		case lexer.INTERSECT:
-			op = ast.Intersect
-		case lexer.EXCEPT:
-			op = ast.Except
-		default:
-			return stmt, nil
+		op, prec, ok := p.setOpPrecedence()
+		if !ok || prec < minPrec {
+			return left, nil
 		}
 		p.advance()
 		all := p.tryEatKeyword(lexer.ALL)
-		right, err := p.parseSelectCore(p.tok.Pos)
+		if !all {
+			p.tryEatKeyword(lexer.DISTINCT)
+		}
+		right, err := p.parseSetExpr(prec + 1)
 		if err != nil {
 			return nil, err
 		}
-		cur := stmt
-		for cur.SetOp != nil {
-			cur = cur.SetOp.Right
-		}
-		cur.SetOp = arenaNode(&p.arena, ast.SetOperation{Op: op, All: all, Right: right})
+		setOp := arenaNode(&p.arena, ast.SetOperation{Op: op, All: all, Left: left, Right: right})
+		left = arenaNode(&p.arena, ast.SelectStmt{SetOp: setOp, TokPos: left.TokPos})
 	}
 }
 
+// parseSetOperand parses one operand of a set operation: a SELECT block,
+// VALUES, or a query in parentheses, which may have its own WITH, ORDER
+// BY and LIMIT.
+func (p *Parser) parseSetOperand() (*ast.SelectStmt, error) {
+	if !p.is(lexer.LPAREN) {
+		return p.parseSelectCore(p.tok.Pos)
+	}
+	p.advance()
+	stmt, err := p.parseSelect()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.eat(lexer.RPAREN); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
 func (p *Parser) parseSelectCore(pos int32) (*ast.SelectStmt, error) {
+	if p.is(lexer.VALUES) {
+		return p.parseValuesCore(pos)
+	}
+	kwEnd := p.tok.Pos + int32(len(p.tok.Raw))
 	if err := p.eatKeyword(lexer.SELECT); err != nil {
 		return nil, err
 	}
 	stmt := arenaNode(&p.arena, ast.SelectStmt{TokPos: pos})
+	p.track(stmt)
+	if p.tok.Pos > kwEnd+1 {
+		stmt.Hints = p.parseHints(kwEnd, p.tok.Pos)
+	}
 	stmt.Distinct = p.tryEatKeyword(lexer.DISTINCT)
 	_ = p.tryEatKeyword(lexer.ALL)
 
 	// Column list
 	cols, err := p.parseSelectColumns()
-	if err != nil {
+	if err != nil && !p.resync(err) {
 		return nil, err
 	}
 	stmt.Columns = cols
@@ -375,7 +700,7 @@ func (p *Parser) parseSelectCore(pos int32) (*ast.SelectStmt, error) {
 	// FROM
 	if p.tryEatKeyword(lexer.FROM) {
 		refs, err := p.parseTableRefs()
-		if err != nil {
+		if err != nil && !p.resync(err) {
 			return nil, err
 		}
 		stmt.From = refs
@@ -384,7 +709,7 @@ func (p *Parser) parseSelectCore(pos int32) (*ast.SelectStmt, error) {
 	// WHERE
 	if p.tryEatKeyword(lexer.WHERE) {
 		where, err := p.parseExpr(0)
-		if err != nil {
+		if err != nil && !p.resync(err) {
 			return nil, err
 		}
 		stmt.Where = where
@@ -395,7 +720,7 @@ func (p *Parser) parseSelectCore(pos int32) (*ast.SelectStmt, error) {
 		p.advance()
 		p.advance()
 		grp, err := p.parseExprList()
-		if err != nil {
+		if err != nil && !p.resync(err) {
 			return nil, err
 		}
 		stmt.GroupBy = grp
@@ -404,33 +729,210 @@ func (p *Parser) parseSelectCore(pos int32) (*ast.SelectStmt, error) {
 	// HAVING
 	if p.tryEatKeyword(lexer.HAVING) {
 		hav, err := p.parseExpr(0)
-		if err != nil {
+		if err != nil && !p.resync(err) {
 			return nil, err
 		}
 		stmt.Having = hav
 	}
 
+	// QUALIFY
+	if p.isQualify() {
+		p.advance()
+		q, err := p.parseExpr(0)
+		if err != nil && !p.resync(err) {
+			return nil, err
+		}
+		stmt.Qualify = q
+	}
+	return stmt, nil
+}
+
+// isQualify reports whether the current token is QUALIFY and
+// Options.AnalyticsClauses allows it.
+func (p *Parser) isQualify() bool {
+	return p.opts.AnalyticsClauses && p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "qualify")
+}
+
+// parseValuesCore parses VALUES (...), (...) used as a table: on its own,
+// in a set operation, a CTE or FROM.
+func (p *Parser) parseValuesCore(pos int32) (*ast.SelectStmt, error) {
+	p.advance() // VALUES
+	stmt := arenaNode(&p.arena, ast.SelectStmt{TokPos: pos})
+	p.track(stmt)
+	rows, err := p.parseValuesRows(false)
+	if err != nil && !p.resync(err) {
+		return nil, err
+	}
+	stmt.Values = rows
+	return stmt, nil
+}
+
+// parseValuesRows parses the rows after VALUES. MySQL spells a row of a
+// standalone VALUES as ROW(...), which is accepted everywhere. With
+// defaults, the rows of an INSERT, a value may be the keyword DEFAULT. On
+// error the rows read so far are returned too, for incomplete input.
+func (p *Parser) parseValuesRows(defaults bool) ([][]ast.Expr, error) {
+	var rows [][]ast.Expr
+	for {
+		if p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "row") && p.peekToken().Type == lexer.LPAREN {
+			p.advance()
+		}
+		if _, err := p.eat(lexer.LPAREN); err != nil {
+			return rows, err
+		}
+		var row []ast.Expr
+		for {
+			e, err := p.parseValue(defaults)
+			if err != nil {
+				return rows, err
+			}
+			row = arenaAppend(&p.arena, row, e)
+			if !p.tryEat(lexer.COMMA) {
+				break
+			}
+		}
+		rows = arenaAppend(&p.arena, rows, row)
+		if _, err := p.eat(lexer.RPAREN); err != nil {
+			return rows, err
+		}
+		if !p.tryEat(lexer.COMMA) {
+			return rows, nil
+		}
+	}
+}
+
+// parseOrderLimit parses the ORDER BY and LIMIT / OFFSET of a SELECT or
+// VALUES.
+func (p *Parser) parseOrderLimit(stmt *ast.SelectStmt) error {
 	// ORDER BY
 	if p.is(lexer.ORDER) && p.peekToken().Type == lexer.BY {
 		p.advance()
 		p.advance()
 		ord, err := p.parseOrderBy()
-		if err != nil {
-			return nil, err
+		if err != nil && !p.resync(err) {
+			return err
 		}
 		stmt.OrderBy = ord
 	}
 
-	// LIMIT / OFFSET
-	if p.tryEatKeyword(lexer.LIMIT) {
+	// LIMIT / OFFSET, after ClickHouse's LIMIT ... BY
+	if p.is(lexer.LIMIT) {
+		pos := p.advance().Pos
 		lim, err := p.parseLimit()
-		if err != nil {
-			return nil, err
+		if err != nil && !p.resync(err) {
+			return err
+		}
+		stmt.Limit = lim
+		if err == nil && p.opts.AnalyticsClauses && p.is(lexer.BY) {
+			if stmt.LimitBy, err = p.parseLimitBy(lim, pos); err != nil && !p.resync(err) {
+				return err
+			}
+			stmt.Limit = nil
+			if p.tryEatKeyword(lexer.LIMIT) {
+				lim, err := p.parseLimit()
+				if err != nil && !p.resync(err) {
+					return err
+				}
+				stmt.Limit = lim
+			}
+		}
+	}
+	if p.is(lexer.OFFSET) || p.isFetch() {
+		lim, err := p.parseOffsetFetch(stmt.Limit)
+		if err != nil && !p.resync(err) {
+			return err
 		}
 		stmt.Limit = lim
 	}
+	return nil
+}
 
-	return stmt, nil
+// parseLimitBy parses the BY list of ClickHouse's LIMIT ... BY, whose
+// count and offset were read as lim.
+func (p *Parser) parseLimitBy(lim *ast.LimitClause, pos int32) (*ast.LimitByClause, error) {
+	lb := arenaNode(&p.arena, ast.LimitByClause{Count: lim.Count, Offset: lim.Offset, TokPos: pos})
+	if lim.Count == nil {
+		return lb, p.errorf("LIMIT ALL cannot be followed by BY")
+	}
+	p.advance() // BY
+	by, err := p.parseExprList()
+	lb.By = by
+	return lb, err
+}
+
+// isFetch reports whether the current token starts FETCH FIRST | NEXT.
+func (p *Parser) isFetch() bool {
+	if !p.is(lexer.IDENT) || !equalASCIIFold(p.tok.Raw, "fetch") {
+		return false
+	}
+	next := p.peekToken()
+	return next.Type == lexer.FIRST || next.Type == lexer.IDENT && equalASCIIFold(next.Raw, "next")
+}
+
+// isRowWord reports whether the current token is ROW or ROWS.
+func (p *Parser) isRowWord() bool {
+	return p.is(lexer.IDENT) && (equalASCIIFold(p.tok.Raw, "row") || equalASCIIFold(p.tok.Raw, "rows"))
+}
+
+// parseOffsetFetch parses what may follow or replace LIMIT: an OFFSET n
+// [ROW | ROWS] on its own or before PostgreSQL's LIMIT, and the standard
+// FETCH {FIRST | NEXT} [m] {ROW | ROWS} {ONLY | WITH TIES}.
+func (p *Parser) parseOffsetFetch(lim *ast.LimitClause) (*ast.LimitClause, error) {
+	limited := lim != nil
+	if lim == nil {
+		lim = arenaNode(&p.arena, ast.LimitClause{})
+	}
+	if lim.Offset == nil && p.tryEatKeyword(lexer.OFFSET) {
+		off, err := p.parseExpr(0)
+		if err != nil {
+			return lim, err
+		}
+		lim.Offset = off
+		if p.isRowWord() {
+			p.advance()
+		}
+	}
+	if limited {
+		return lim, nil
+	}
+	if p.tryEatKeyword(lexer.LIMIT) {
+		if p.tryEatKeyword(lexer.ALL) {
+			return lim, nil
+		}
+		count, err := p.parseExpr(0)
+		lim.Count = count
+		return lim, err
+	}
+	if !p.isFetch() {
+		return lim, nil
+	}
+	p.advance() // FETCH
+	p.advance() // FIRST | NEXT
+	lim.Fetch = true
+	if p.isRowWord() {
+		lim.Count = arenaNode(&p.arena, ast.Literal{Raw: literalOne, Kind: lexer.INT, TokPos: p.tok.Pos})
+	} else {
+		count, err := p.parseExpr(0)
+		if err != nil {
+			return lim, err
+		}
+		lim.Count = count
+	}
+	if !p.isRowWord() {
+		return lim, p.errorf("expected ROW or ROWS in FETCH clause, got %q", p.tok.Raw)
+	}
+	p.advance()
+	switch {
+	case p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "only"):
+		p.advance()
+	case p.is(lexer.WITH) && equalASCIIFold(p.peekToken().Raw, "ties"):
+		p.advance()
+		p.advance()
+		lim.WithTies = true
+	default:
+		return lim, p.errorf("expected ONLY or WITH TIES in FETCH clause, got %q", p.tok.Raw)
+	}
+	return lim, nil
 }
 
 func (p *Parser) parseWith() (*ast.WithClause, error) {
@@ -458,14 +960,31 @@ func (p *Parser) parseWith() (*ast.WithClause, error) {
 		if err := p.eatKeyword(lexer.AS); err != nil {
 			return nil, err
 		}
+		switch {
+		case p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "materialized"):
+			p.advance()
+			cte.Materialized = ast.MaterializeAlways
+		case p.is(lexer.NOT) && equalASCIIFold(p.peekToken().Raw, "materialized"):
+			p.advance()
+			p.advance()
+			cte.Materialized = ast.MaterializeNever
+		}
 		if _, err := p.eat(lexer.LPAREN); err != nil {
 			return nil, err
 		}
-		sq, err := p.parseSelect()
+		switch p.tok.Type {
+		case lexer.INSERT:
+			cte.DML, err = p.parseInsert()
+		case lexer.UPDATE:
+			cte.DML, err = p.parseUpdate()
+		case lexer.DELETE:
+			cte.DML, err = p.parseDelete()
+		default:
+			cte.Subq, err = p.parseSelect()
+		}
 		if err != nil {
 			return nil, err
 		}
-		cte.Subq = sq
 		if _, err := p.eat(lexer.RPAREN); err != nil {
 			return nil, err
 		}
@@ -494,15 +1013,19 @@ func (p *Parser) parseSelectColumns() ([]ast.SelectColumn, error) {
 
 func (p *Parser) parseSelectColumn() (ast.SelectColumn, error) {
 	if p.is(lexer.STAR) {
-		p.advance()
-		return ast.SelectColumn{Star: true, Expr: arenaNode(&p.arena, ast.StarExpr{TokPos: p.tok.Pos})}, nil
+		t := p.advance()
+		col := ast.SelectColumn{Star: true, Expr: arenaNode(&p.arena, ast.StarExpr{TokPos: t.Pos})}
+		return col, p.parseStarExcept(&col)
 	}
 	expr, err := p.parseExpr(0)
 	if err != nil {
 		return ast.SelectColumn{}, err
 	}
 	col := ast.SelectColumn{Expr: expr}
-	if p.tryEatKeyword(lexer.AS) || p.is(lexer.IDENT) || p.is(lexer.BACKTICK) || p.is(lexer.DQUOTE) {
+	if _, ok := expr.(*ast.TableStar); ok {
+		return col, p.parseStarExcept(&col)
+	}
+	if p.tryEatKeyword(lexer.AS) || p.is(lexer.IDENT) && !p.isAliasStop() || p.is(lexer.BACKTICK) || p.is(lexer.DQUOTE) {
 		alias, err := p.parseIdent()
 		if err != nil {
 			return ast.SelectColumn{}, err
@@ -512,6 +1035,26 @@ func (p *Parser) parseSelectColumn() (ast.SelectColumn, error) {
 	return col, nil
 }
 
+// parseStarExcept parses the EXCEPT (col, ...) or EXCLUDE (col, ...) list
+// after a star column when Options.StarExcept allows it.
+func (p *Parser) parseStarExcept(col *ast.SelectColumn) error {
+	if !p.opts.StarExcept || p.peekToken().Type != lexer.LPAREN {
+		return nil
+	}
+	if !p.is(lexer.EXCEPT) && !(p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "exclude")) {
+		return nil
+	}
+	p.advance()
+	p.advance() // (
+	cols, err := p.parseIdentList()
+	if err != nil {
+		return err
+	}
+	col.Except = cols
+	_, err = p.eat(lexer.RPAREN)
+	return err
+}
+
 // ---- Table references ----
 
 func (p *Parser) parseTableRefs() ([]ast.TableRef, error) {
@@ -536,7 +1079,7 @@ func (p *Parser) parseTableRef() (ast.TableRef, error) {
 	var err error
 	if p.is(lexer.LPAREN) {
 		p.advance()
-		if p.is(lexer.SELECT) || p.is(lexer.WITH) {
+		if p.is(lexer.SELECT) || p.is(lexer.WITH) || p.is(lexer.VALUES) {
 			sq, err := p.parseSelect()
 			if err != nil {
 				return nil, err
@@ -546,9 +1089,24 @@ func (p *Parser) parseTableRef() (ast.TableRef, error) {
 			}
 			sub := arenaNode(&p.arena, ast.SubqueryTable{Subq: sq, TokPos: sq.TokPos})
 			sub.Alias, _ = p.parseOptionalAlias()
+			if sub.Alias != nil && p.is(lexer.LPAREN) {
+				p.advance()
+				cols, err := p.parseIdentList()
+				if err != nil {
+					return nil, err
+				}
+				if _, err := p.eat(lexer.RPAREN); err != nil {
+					return nil, err
+				}
+				sub.Columns = cols
+			}
 			left = sub
 		} else {
 			// Parenthesized join
+			if err := p.descend(); err != nil {
+				return nil, err
+			}
+			defer func() { p.depth-- }()
 			inner, err := p.parseTableRef()
 			if err != nil {
 				return nil, err
@@ -565,8 +1123,21 @@ func (p *Parser) parseTableRef() (ast.TableRef, error) {
 		}
 		st := arenaNode(&p.arena, ast.SimpleTable{Name: name})
 		st.Alias, _ = p.parseOptionalAlias()
+		if st.IndexHints, err = p.parseIndexHints(); err != nil {
+			return nil, err
+		}
+		if p.isTableSample() {
+			if st.Sample, err = p.parseTableSample(); err != nil {
+				return nil, err
+			}
+		}
 		left = st
 	}
+	for p.isPivot() {
+		if left, err = p.parsePivot(left); err != nil {
+			return nil, err
+		}
+	}
 
 	// JOIN chains
 	for {
@@ -664,41 +1235,244 @@ func (p *Parser) parseJoin(left ast.TableRef) (ast.TableRef, error) {
 }
 
 func (p *Parser) parseOptionalAlias() (*ast.Ident, error) {
-	p.tryEatKeyword(lexer.AS)
-	if p.is(lexer.IDENT) || p.is(lexer.BACKTICK) || p.is(lexer.DQUOTE) {
+	as := p.tryEatKeyword(lexer.AS)
+	if p.is(lexer.IDENT) && (as || !p.isAliasStop()) || p.is(lexer.BACKTICK) || p.is(lexer.DQUOTE) {
 		return p.parseIdent()
 	}
 	return nil, nil
 }
 
-// ---- Expression parsing (Pratt / top-down operator precedence) ----
-
-type precedence int
+// isAliasStop reports whether the current word starts a clause rather than
+// an alias written without AS: FETCH FIRST, RETURNING, FORCE INDEX,
+// TABLESAMPLE, QUALIFY or PIVOT.
+func (p *Parser) isAliasStop() bool {
+	return p.isFetch() || p.isReturning() || p.isIndexHint() || p.isTableSample() || p.isQualify() || p.isPivot()
+}
 
-const (
-	precLowest     precedence = 0
-	precOr         precedence = 1
-	precAnd        precedence = 2
-	precNot        precedence = 3
-	precComparison precedence = 4
-	precBitOr      precedence = 5
-	precBitAnd     precedence = 6
-	precShift      precedence = 7
-	precAddSub     precedence = 8
-	precMulDiv     precedence = 9
-	precUnary      precedence = 10
-	precPostfix    precedence = 11
-)
+// isIndexHint reports whether the current token starts a MySQL index hint.
+func (p *Parser) isIndexHint() bool {
+	if next := p.peekToken().Type; next != lexer.INDEX && next != lexer.KEY {
+		return false
+	}
+	return p.is(lexer.USE) || p.is(lexer.IGNORE) || p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "force")
+}
 
-func tokenPrec(t lexer.TokenType) (precedence, bool) {
-	switch t {
-	case lexer.OR:
-		return precOr, true
-	case lexer.AND, lexer.DAMP:
-		return precAnd, true
-	case lexer.EQ, lexer.NEQ, lexer.LT, lexer.GT, lexer.LTE, lexer.GTE:
-		return precComparison, true
-	case lexer.ATGT, lexer.LTAT, lexer.QUESTION, lexer.QMARKPIPE, lexer.QMARKAMP:
+// parseIndexHints parses the index hints after a table name and alias.
+func (p *Parser) parseIndexHints() ([]ast.IndexHint, error) {
+	var hints []ast.IndexHint
+	for p.isIndexHint() {
+		hint := ast.IndexHint{TokPos: p.tok.Pos}
+		switch {
+		case p.is(lexer.USE):
+			hint.Kind = ast.UseIndex
+		case p.is(lexer.IGNORE):
+			hint.Kind = ast.IgnoreIndex
+		default:
+			hint.Kind = ast.ForceIndex
+		}
+		p.advance()
+		p.advance() // INDEX | KEY
+		if p.tryEatKeyword(lexer.FOR) {
+			switch {
+			case p.tryEatKeyword(lexer.JOIN):
+				hint.For = ast.HintJoin
+			case p.is(lexer.ORDER) && p.peekToken().Type == lexer.BY:
+				p.advance()
+				p.advance()
+				hint.For = ast.HintOrderBy
+			case p.is(lexer.GROUP) && p.peekToken().Type == lexer.BY:
+				p.advance()
+				p.advance()
+				hint.For = ast.HintGroupBy
+			default:
+				return nil, p.expectf([]lexer.TokenType{lexer.JOIN, lexer.ORDER, lexer.GROUP}, "expected JOIN, ORDER BY or GROUP BY after FOR, got %q", p.tok.Raw)
+			}
+		}
+		if _, err := p.eat(lexer.LPAREN); err != nil {
+			return nil, err
+		}
+		for !p.is(lexer.RPAREN) || hint.Kind != ast.UseIndex && len(hint.Indexes) == 0 {
+			var id *ast.Ident
+			if p.is(lexer.PRIMARY) {
+				id = arenaNode(&p.arena, ast.Ident{Raw: p.tok.Raw, Unquoted: "PRIMARY", TokPos: p.tok.Pos})
+				p.advance()
+			} else {
+				var err error
+				if id, err = p.parseIdent(); err != nil {
+					return nil, err
+				}
+			}
+			hint.Indexes = arenaAppend(&p.arena, hint.Indexes, id)
+			if !p.tryEat(lexer.COMMA) {
+				break
+			}
+		}
+		if _, err := p.eat(lexer.RPAREN); err != nil {
+			return nil, err
+		}
+		hints = arenaAppend(&p.arena, hints, hint)
+	}
+	return hints, nil
+}
+
+// isTableSample reports whether the current token starts TABLESAMPLE
+// method.
+func (p *Parser) isTableSample() bool {
+	return p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "tablesample") && p.peekToken().Type == lexer.IDENT
+}
+
+// parseTableSample parses TABLESAMPLE method (args) [REPEATABLE (seed)].
+func (p *Parser) parseTableSample() (*ast.TableSample, error) {
+	sample := arenaNode(&p.arena, ast.TableSample{TokPos: p.tok.Pos})
+	p.advance() // TABLESAMPLE
+	method, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	sample.Method = method
+	if _, err := p.eat(lexer.LPAREN); err != nil {
+		return nil, err
+	}
+	if sample.Args, err = p.parseExprList(); err != nil {
+		return nil, err
+	}
+	if _, err := p.eat(lexer.RPAREN); err != nil {
+		return nil, err
+	}
+	if p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "repeatable") {
+		p.advance()
+		if _, err := p.eat(lexer.LPAREN); err != nil {
+			return nil, err
+		}
+		if sample.Repeatable, err = p.parseExpr(0); err != nil {
+			return nil, err
+		}
+		if _, err := p.eat(lexer.RPAREN); err != nil {
+			return nil, err
+		}
+	}
+	return sample, nil
+}
+
+// isPivot reports whether the current token starts PIVOT (...) or
+// UNPIVOT [INCLUDE | EXCLUDE NULLS] (...).
+func (p *Parser) isPivot() bool {
+	if !p.is(lexer.IDENT) {
+		return false
+	}
+	next := p.peekToken()
+	switch {
+	case equalASCIIFold(p.tok.Raw, "pivot"):
+		return next.Type == lexer.LPAREN
+	case equalASCIIFold(p.tok.Raw, "unpivot"):
+		return next.Type == lexer.LPAREN || next.Type == lexer.IDENT && (equalASCIIFold(next.Raw, "include") || equalASCIIFold(next.Raw, "exclude"))
+	}
+	return false
+}
+
+// parsePivot parses the PIVOT or UNPIVOT clause after source and its
+// alias.
+func (p *Parser) parsePivot(source ast.TableRef) (ast.TableRef, error) {
+	pt := arenaNode(&p.arena, ast.PivotTable{Source: source, TokPos: p.tok.Pos})
+	pt.Unpivot = equalASCIIFold(p.advance().Raw, "unpivot")
+	if pt.Unpivot && p.is(lexer.IDENT) {
+		pt.IncludeNulls = equalASCIIFold(p.advance().Raw, "include")
+		if !p.is(lexer.IDENT) || !equalASCIIFold(p.tok.Raw, "nulls") {
+			return nil, p.errorf("expected NULLS, got %q", p.tok.Raw)
+		}
+		p.advance()
+	}
+	if _, err := p.eat(lexer.LPAREN); err != nil {
+		return nil, err
+	}
+	if pt.Unpivot {
+		value, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		pt.Value = value
+	} else {
+		agg, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		fc, ok := agg.(*ast.FuncCall)
+		if !ok {
+			return nil, p.errorf("PIVOT expects an aggregate function call before FOR")
+		}
+		pt.Agg = fc
+	}
+	if err := p.eatKeyword(lexer.FOR); err != nil {
+		return nil, err
+	}
+	col, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	pt.For = col
+	if err := p.eatKeyword(lexer.IN); err != nil {
+		return nil, err
+	}
+	if _, err := p.eat(lexer.LPAREN); err != nil {
+		return nil, err
+	}
+	for {
+		var v ast.PivotValue
+		if pt.Unpivot {
+			id, err := p.parseIdent()
+			if err != nil {
+				return nil, err
+			}
+			v.Expr = id
+		} else if v.Expr, err = p.parseExpr(0); err != nil {
+			return nil, err
+		}
+		if v.Alias, err = p.parseOptionalAlias(); err != nil {
+			return nil, err
+		}
+		pt.In = arenaAppend(&p.arena, pt.In, v)
+		if !p.tryEat(lexer.COMMA) {
+			break
+		}
+	}
+	if _, err := p.eat(lexer.RPAREN); err != nil {
+		return nil, err
+	}
+	if _, err := p.eat(lexer.RPAREN); err != nil {
+		return nil, err
+	}
+	pt.Alias, _ = p.parseOptionalAlias()
+	return pt, nil
+}
+
+// ---- Expression parsing (Pratt / top-down operator precedence) ----
+
+type precedence int
+
+const (
+	precLowest     precedence = 0
+	precOr         precedence = 1
+	precAnd        precedence = 2
+	precNot        precedence = 3
+	precComparison precedence = 4
+	precBitOr      precedence = 5
+	precBitAnd     precedence = 6
+	precShift      precedence = 7
+	precAddSub     precedence = 8
+	precMulDiv     precedence = 9
+	precUnary      precedence = 10
+	precPostfix    precedence = 11
+)
+
+func tokenPrec(t lexer.TokenType) (precedence, bool) {
+	switch t {
+	case lexer.OR:
+		return precOr, true
+	case lexer.AND, lexer.DAMP:
+		return precAnd, true
+	case lexer.EQ, lexer.NEQ, lexer.LT, lexer.GT, lexer.LTE, lexer.GTE:
+		return precComparison, true
+	case lexer.ATGT, lexer.LTAT, lexer.QUESTION, lexer.QMARKPIPE, lexer.QMARKAMP:
 		return precComparison, true
 	case lexer.PIPE:
 		return precBitOr, true
@@ -840,6 +1614,14 @@ func (p *Parser) parseExpr(minPrec precedence) (ast.Expr, error) {
 		op := p.tok.Type
 		pos := p.tok.Pos
 		p.advance()
+		if q, ok := p.quantifier(op); ok {
+			qc, err := p.parseQuantifiedRHS(left, op, q, pos)
+			if err != nil {
+				return nil, err
+			}
+			left = qc
+			continue
+		}
 		right, err := p.parseExpr(prec)
 		if err != nil {
 			return nil, err
@@ -849,6 +1631,53 @@ func (p *Parser) parseExpr(minPrec precedence) (ast.Expr, error) {
 	return left, nil
 }
 
+// quantifier reports whether the current token is the ANY, SOME or ALL of
+// a quantified comparison after the comparison operator op.
+func (p *Parser) quantifier(op lexer.TokenType) (ast.Quantifier, bool) {
+	switch op {
+	case lexer.EQ, lexer.NEQ, lexer.LT, lexer.GT, lexer.LTE, lexer.GTE:
+	default:
+		return 0, false
+	}
+	if p.peekToken().Type != lexer.LPAREN {
+		return 0, false
+	}
+	switch {
+	case p.is(lexer.ALL):
+		return ast.QuantifyAll, true
+	case p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "any"):
+		return ast.QuantifyAny, true
+	case p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "some"):
+		return ast.QuantifySome, true
+	}
+	return 0, false
+}
+
+// parseQuantifiedRHS parses the (subquery) or (array) after ANY, SOME or
+// ALL.
+func (p *Parser) parseQuantifiedRHS(left ast.Expr, op lexer.TokenType, q ast.Quantifier, pos int32) (ast.Expr, error) {
+	p.advance() // ANY, SOME or ALL
+	p.advance() // (
+	qc := arenaNode(&p.arena, ast.QuantifiedComparisonExpr{Left: left, Op: op, Quantifier: q, TokPos: pos})
+	if p.is(lexer.SELECT) || p.is(lexer.WITH) {
+		sq, err := p.parseSelect()
+		if err != nil {
+			return nil, err
+		}
+		qc.Subq = sq
+	} else {
+		arr, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		qc.Array = arr
+	}
+	if _, err := p.eat(lexer.RPAREN); err != nil {
+		return nil, err
+	}
+	return qc, nil
+}
+
 func (p *Parser) parseInRHS(left ast.Expr, pos int32, not bool) (ast.Expr, error) {
 	if _, err := p.eat(lexer.LPAREN); err != nil {
 		return nil, err
@@ -874,6 +1703,10 @@ func (p *Parser) parseInRHS(left ast.Expr, pos int32, not bool) (ast.Expr, error
 }
 
 func (p *Parser) parseUnary() (ast.Expr, error) {
+	if err := p.descend(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
 	switch p.tok.Type {
 	case lexer.MINUS:
 		pos := p.tok.Pos
@@ -922,7 +1755,20 @@ func (p *Parser) parseUnary() (ast.Expr, error) {
 		}
 		return arenaNode(&p.arena, ast.ExistsExpr{Subq: sq, TokPos: pos}), nil
 	}
-	return p.parsePrimary()
+	e, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	// PostgreSQL's x::type casts bind tighter than any operator.
+	for p.is(lexer.DCOLON) {
+		p.advance()
+		dt, err := p.parseDataType()
+		if err != nil {
+			return nil, err
+		}
+		e = arenaNode(&p.arena, ast.CastExpr{Expr: e, Type: dt, TokPos: e.Pos()})
+	}
+	return e, nil
 }
 
 func (p *Parser) parsePrimary() (ast.Expr, error) {
@@ -983,11 +1829,34 @@ func (p *Parser) parsePrimary() (ast.Expr, error) {
 		if p.is(lexer.LPAREN) {
 			return p.parseFuncCall(name)
 		}
+		if ref := p.insertedRef(name); ref != nil {
+			return ref, nil
+		}
+		if n := len(name.Parts); n > 1 && name.Parts[n-1].Unquoted == "*" {
+			name.Parts = name.Parts[:n-1]
+			return arenaNode(&p.arena, ast.TableStar{Table: name}), nil
+		}
 		if len(name.Parts) == 1 {
 			return name.Parts[0], nil
 		}
 		return name, nil
 
+	// MySQL's VALUES(col) in ON DUPLICATE KEY UPDATE.
+	case lexer.VALUES:
+		if p.upsert == nil || p.upsertForm != ast.InsertedAlias || p.peekToken().Type != lexer.LPAREN {
+			break
+		}
+		pos := p.advance().Pos
+		p.advance() // (
+		col, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.eat(lexer.RPAREN); err != nil {
+			return nil, err
+		}
+		return arenaNode(&p.arena, ast.InsertedRef{Form: ast.InsertedValues, Column: col, TokPos: pos}), nil
+
 	// Handle keywords that can be used as function names (e.g. REPLACE, LEFT...)
 	case lexer.REPLACE, lexer.LEFT, lexer.RIGHT, lexer.INSERT:
 		part := arenaNode(&p.arena, ast.Ident{Raw: p.tok.Raw, Unquoted: lowerASCIIStringArena(&p.arena, p.tok.Raw), TokPos: p.tok.Pos})
@@ -999,6 +1868,17 @@ func (p *Parser) parsePrimary() (ast.Expr, error) {
 			return p.parseFuncCall(name)
 		}
 		return name.Parts[0], nil
+
+	// Date and time type names double as functions, as in DATE(created_at).
+	case lexer.DATE, lexer.TIME, lexer.TIMESTAMP, lexer.YEAR:
+		if p.peekToken().Type != lexer.LPAREN {
+			break
+		}
+		part := arenaNode(&p.arena, ast.Ident{Raw: p.tok.Raw, Unquoted: lowerASCIIStringArena(&p.arena, p.tok.Raw), TokPos: p.tok.Pos})
+		var parts []*ast.Ident
+		parts = arenaAppend(&p.arena, parts, part)
+		p.advance()
+		return p.parseFuncCall(arenaNode(&p.arena, ast.QualifiedIdent{Parts: parts}))
 	}
 
 	return nil, p.errorf("unexpected token %q in expression", p.tok.Raw)
@@ -1070,14 +1950,12 @@ func (p *Parser) parseFuncCall(name *ast.QualifiedIdent) (*ast.FuncCall, error)
 	pos := p.tok.Pos
 	p.advance() // (
 	fc := arenaNode(&p.arena, ast.FuncCall{Name: name, TokPos: pos})
-	if p.is(lexer.RPAREN) {
-		p.advance()
-		return fc, nil
-	}
-	if p.is(lexer.STAR) {
+	switch {
+	case p.is(lexer.RPAREN):
+	case p.is(lexer.STAR):
 		p.advance()
 		fc.Star = true
-	} else {
+	default:
 		fc.Distinct = p.tryEatKeyword(lexer.DISTINCT)
 		args, err := p.parseExprList()
 		if err != nil {
@@ -1088,9 +1966,58 @@ func (p *Parser) parseFuncCall(name *ast.QualifiedIdent) (*ast.FuncCall, error)
 	if _, err := p.eat(lexer.RPAREN); err != nil {
 		return nil, err
 	}
+	if p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "over") {
+		over, err := p.parseOver()
+		if err != nil {
+			return nil, err
+		}
+		fc.Over = over
+	}
 	return fc, nil
 }
 
+// parseOver parses the OVER clause of a window function. The frame is
+// kept as written from ROWS, RANGE or GROUPS to the closing parenthesis.
+func (p *Parser) parseOver() (*ast.WindowSpec, error) {
+	w := arenaNode(&p.arena, ast.WindowSpec{TokPos: p.advance().Pos})
+	if !p.is(lexer.LPAREN) {
+		name, err := p.parseIdent()
+		w.Name = name
+		return w, err
+	}
+	p.advance()
+	if p.is(lexer.PARTITION) && p.peekToken().Type == lexer.BY {
+		p.advance()
+		p.advance()
+		exprs, err := p.parseExprList()
+		if err != nil {
+			return nil, err
+		}
+		w.PartitionBy = exprs
+	}
+	if p.is(lexer.ORDER) && p.peekToken().Type == lexer.BY {
+		p.advance()
+		p.advance()
+		ord, err := p.parseOrderBy()
+		if err != nil {
+			return nil, err
+		}
+		w.OrderBy = ord
+	}
+	if p.is(lexer.IDENT) && (equalASCIIFold(p.tok.Raw, "rows") || equalASCIIFold(p.tok.Raw, "range") || equalASCIIFold(p.tok.Raw, "groups")) {
+		start, end := p.tok.Pos, p.tok.Pos
+		for !p.is(lexer.RPAREN) && !p.is(lexer.EOF) && !p.is(lexer.SEMICOLON) {
+			end = p.tok.Pos + int32(len(p.tok.Raw))
+			p.advance()
+		}
+		w.Frame = p.lex.Source()[start:end]
+	}
+	if _, err := p.eat(lexer.RPAREN); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
 func (p *Parser) parseExprList() ([]ast.Expr, error) {
 	var exprs []ast.Expr
 	for {
@@ -1128,6 +2055,17 @@ func (p *Parser) parseOrderBy() ([]ast.OrderByItem, error) {
 }
 
 func (p *Parser) parseLimit() (*ast.LimitClause, error) {
+	if p.tryEatKeyword(lexer.ALL) {
+		lim := arenaNode(&p.arena, ast.LimitClause{})
+		if p.tryEatKeyword(lexer.OFFSET) {
+			off, err := p.parseExpr(0)
+			if err != nil {
+				return nil, err
+			}
+			lim.Offset = off
+		}
+		return lim, nil
+	}
 	count, err := p.parseExpr(0)
 	if err != nil {
 		return nil, err
@@ -1157,6 +2095,7 @@ func (p *Parser) parseInsert() (*ast.InsertStmt, error) {
 	pos := p.tok.Pos
 	p.advance() // INSERT
 	stmt := arenaNode(&p.arena, ast.InsertStmt{TokPos: pos})
+	p.track(stmt)
 	stmt.Ignore = p.tryEatKeyword(lexer.IGNORE)
 	p.tryEatKeyword(lexer.INTO)
 	name, err := p.parseQualifiedIdent()
@@ -1165,41 +2104,8 @@ func (p *Parser) parseInsert() (*ast.InsertStmt, error) {
 	}
 	stmt.Table = name
 
-	if p.is(lexer.LPAREN) {
-		p.advance()
-		cols, err := p.parseIdentList()
-		if err != nil {
-			return nil, err
-		}
-		stmt.Columns = cols
-		if _, err := p.eat(lexer.RPAREN); err != nil {
-			return nil, err
-		}
-	}
-
-	if p.is(lexer.SELECT) || p.is(lexer.WITH) {
-		sq, err := p.parseSelect()
-		if err != nil {
-			return nil, err
-		}
-		stmt.Select = sq
-	} else if p.tryEatKeyword(lexer.VALUES) {
-		for {
-			if _, err := p.eat(lexer.LPAREN); err != nil {
-				return nil, err
-			}
-			row, err := p.parseExprList()
-			if err != nil {
-				return nil, err
-			}
-			stmt.Values = arenaAppend(&p.arena, stmt.Values, row)
-			if _, err := p.eat(lexer.RPAREN); err != nil {
-				return nil, err
-			}
-			if !p.tryEat(lexer.COMMA) {
-				break
-			}
-		}
+	if err := p.parseInsertSource(stmt); err != nil {
+		return nil, err
 	}
 
 	// ON DUPLICATE KEY UPDATE
@@ -1212,7 +2118,9 @@ func (p *Parser) parseInsert() (*ast.InsertStmt, error) {
 			if err := p.eatKeyword(lexer.UPDATE); err != nil {
 				return nil, err
 			}
+			p.upsert, p.upsertForm = stmt, ast.InsertedAlias
 			asgn, err := p.parseAssignments()
+			p.upsert = nil
 			if err != nil {
 				return nil, err
 			}
@@ -1220,7 +2128,15 @@ func (p *Parser) parseInsert() (*ast.InsertStmt, error) {
 		} else if next.Type == lexer.IDENT && bytes.EqualFold(next.Raw, []byte("conflict")) {
 			p.advance() // ON
 			p.advance() // CONFLICT
-			if p.is(lexer.LPAREN) {
+			if p.is(lexer.ON) && p.peekToken().Type == lexer.CONSTRAINT {
+				p.advance() // ON
+				p.advance() // CONSTRAINT
+				name, err := p.parseIdent()
+				if err != nil {
+					return nil, err
+				}
+				stmt.OnConflictConstraint = name
+			} else if p.is(lexer.LPAREN) {
 				p.advance()
 				cols, err := p.parseIdentList()
 				if err != nil {
@@ -1230,6 +2146,13 @@ func (p *Parser) parseInsert() (*ast.InsertStmt, error) {
 				if _, err := p.eat(lexer.RPAREN); err != nil {
 					return nil, err
 				}
+				if p.tryEatKeyword(lexer.WHERE) {
+					w, err := p.parseExpr(0)
+					if err != nil {
+						return nil, err
+					}
+					stmt.OnConflictWhere = w
+				}
 			}
 			if !(p.is(lexer.IDENT) && bytes.EqualFold(p.tok.Raw, []byte("do"))) {
 				return nil, p.errorf("expected DO in ON CONFLICT clause, got %q", p.tok.Raw)
@@ -1242,17 +2165,23 @@ func (p *Parser) parseInsert() (*ast.InsertStmt, error) {
 				if err := p.eatKeyword(lexer.SET); err != nil {
 					return nil, err
 				}
-				asgn, err := p.parseAssignments()
+				p.upsert, p.upsertForm = stmt, ast.InsertedExcluded
+				err := p.parseConflictUpdate(stmt)
+				p.upsert = nil
 				if err != nil {
 					return nil, err
 				}
-				stmt.OnConflictUpdate = asgn
 			} else {
 				return nil, p.errorf("expected NOTHING or UPDATE in ON CONFLICT DO clause, got %q", p.tok.Raw)
 			}
 		}
 	}
 
+	ret, err := p.parseReturning()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Returning = ret
 	return stmt, nil
 }
 
@@ -1260,6 +2189,7 @@ func (p *Parser) parseReplace() (*ast.InsertStmt, error) {
 	pos := p.tok.Pos
 	p.advance() // REPLACE
 	stmt := arenaNode(&p.arena, ast.InsertStmt{TokPos: pos, Replace: true})
+	p.track(stmt)
 	p.tryEatKeyword(lexer.INTO)
 	name, err := p.parseQualifiedIdent()
 	if err != nil {
@@ -1267,37 +2197,180 @@ func (p *Parser) parseReplace() (*ast.InsertStmt, error) {
 	}
 	stmt.Table = name
 
-	if p.is(lexer.LPAREN) {
+	if err := p.parseInsertSource(stmt); err != nil {
+		return nil, err
+	}
+	ret, err := p.parseReturning()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Returning = ret
+	return stmt, nil
+}
+
+// parseReturning parses the RETURNING list that ends an INSERT, UPDATE or
+// DELETE, or returns nil when there is none.
+func (p *Parser) parseReturning() ([]ast.SelectColumn, error) {
+	if !p.isReturning() {
+		return nil, nil
+	}
+	p.advance()
+	return p.parseSelectColumns()
+}
+
+func (p *Parser) isReturning() bool {
+	return p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "returning")
+}
+
+// parseConflictUpdate parses the assignments and WHERE of ON CONFLICT DO
+// UPDATE SET.
+func (p *Parser) parseConflictUpdate(stmt *ast.InsertStmt) error {
+	asgn, err := p.parseAssignments()
+	if err != nil {
+		return err
+	}
+	stmt.OnConflictUpdate = asgn
+	if p.tryEatKeyword(lexer.WHERE) {
+		w, err := p.parseExpr(0)
+		if err != nil {
+			return err
+		}
+		stmt.OnConflictUpdateWhere = w
+	}
+	return nil
+}
+
+// insertedRef returns the reference to the new row of an upsert that name
+// stands for, or nil: excluded.col under ON CONFLICT, and under ON
+// DUPLICATE KEY UPDATE alias.col or a column of the row alias's list.
+func (p *Parser) insertedRef(name *ast.QualifiedIdent) *ast.InsertedRef {
+	s := p.upsert
+	if s == nil {
+		return nil
+	}
+	col := name.Parts[len(name.Parts)-1]
+	switch len(name.Parts) {
+	case 1:
+		if c, ok := rowAliasColumn(s, col); ok && p.upsertForm == ast.InsertedAlias {
+			return arenaNode(&p.arena, ast.InsertedRef{Form: ast.InsertedAlias, Qualifier: s.RowAlias, Column: c, TokPos: col.TokPos})
+		}
+	case 2:
+		q := name.Parts[0]
+		switch {
+		case p.upsertForm == ast.InsertedExcluded && strings.EqualFold(q.Unquoted, "excluded"):
+			return arenaNode(&p.arena, ast.InsertedRef{Form: ast.InsertedExcluded, Qualifier: q, Column: col, TokPos: q.TokPos})
+		case p.upsertForm == ast.InsertedAlias && s.RowAlias != nil && strings.EqualFold(q.Unquoted, s.RowAlias.Unquoted):
+			if c, ok := rowAliasColumn(s, col); ok {
+				col = c
+			}
+			return arenaNode(&p.arena, ast.InsertedRef{Form: ast.InsertedAlias, Qualifier: q, Column: col, TokPos: q.TokPos})
+		}
+	}
+	return nil
+}
+
+// rowAliasColumn returns the target column that col names through the
+// column list of the row alias of s.
+func rowAliasColumn(s *ast.InsertStmt, col *ast.Ident) (*ast.Ident, bool) {
+	for i, c := range s.RowAliasColumns {
+		if strings.EqualFold(c.Unquoted, col.Unquoted) && i < len(s.Columns) {
+			return s.Columns[i], true
+		}
+	}
+	return nil, false
+}
+
+// parseInsertSource parses the column list and rows of an INSERT or
+// REPLACE: a query, VALUES, DEFAULT VALUES, or MySQL's SET col = value, ...
+// which is stored as a column list and one row. MySQL's () VALUES () is
+// read as DEFAULT VALUES.
+func (p *Parser) parseInsertSource(stmt *ast.InsertStmt) error {
+	if p.is(lexer.LPAREN) && p.peekToken().Type == lexer.RPAREN {
 		p.advance()
+		p.advance()
+		if err := p.eatKeyword(lexer.VALUES); err != nil {
+			return err
+		}
+		if _, err := p.eat(lexer.LPAREN); err != nil {
+			return err
+		}
+		if _, err := p.eat(lexer.RPAREN); err != nil {
+			return err
+		}
+		stmt.DefaultValues = true
+		return nil
+	}
+	if p.tryEat(lexer.LPAREN) {
 		cols, err := p.parseIdentList()
 		if err != nil {
-			return nil, err
+			return err
 		}
 		stmt.Columns = cols
 		if _, err := p.eat(lexer.RPAREN); err != nil {
-			return nil, err
+			return err
+		}
+	}
+	switch {
+	case p.is(lexer.SELECT) || p.is(lexer.WITH):
+		sq, err := p.parseSelect()
+		if err != nil {
+			return err
+		}
+		stmt.Select = sq
+	case p.tryEatKeyword(lexer.VALUES):
+		rows, err := p.parseValuesRows(true)
+		stmt.Values = rows
+		if err != nil {
+			return err
+		}
+		return p.parseRowAlias(stmt)
+	case p.is(lexer.DEFAULT) && p.peekToken().Type == lexer.VALUES:
+		if len(stmt.Columns) > 0 {
+			return p.errorf("DEFAULT VALUES does not take a column list")
+		}
+		p.advance() // DEFAULT
+		p.advance() // VALUES
+		stmt.DefaultValues = true
+	case p.is(lexer.SET) && len(stmt.Columns) == 0:
+		p.advance()
+		asgn, err := p.parseAssignments()
+		if err != nil {
+			return err
 		}
+		row := arenaMakeSlice[ast.Expr](&p.arena, len(asgn), len(asgn))
+		for i, a := range asgn {
+			stmt.Columns = arenaAppend(&p.arena, stmt.Columns, a.Column)
+			row[i] = a.Value
+		}
+		stmt.Values = arenaAppend(&p.arena, stmt.Values, row)
+		stmt.SetForm = true
+		return p.parseRowAlias(stmt)
 	}
+	return nil
+}
 
-	if p.tryEatKeyword(lexer.VALUES) {
-		for {
-			if _, err := p.eat(lexer.LPAREN); err != nil {
-				return nil, err
-			}
-			row, err := p.parseExprList()
-			if err != nil {
-				return nil, err
-			}
-			stmt.Values = arenaAppend(&p.arena, stmt.Values, row)
-			if _, err := p.eat(lexer.RPAREN); err != nil {
-				return nil, err
-			}
-			if !p.tryEat(lexer.COMMA) {
-				break
-			}
+// parseRowAlias parses MySQL's AS alias [(cols)] after the row of an
+// INSERT.
+func (p *Parser) parseRowAlias(stmt *ast.InsertStmt) error {
+	if !p.tryEatKeyword(lexer.AS) {
+		return nil
+	}
+	alias, err := p.parseIdent()
+	if err != nil {
+		return err
+	}
+	stmt.RowAlias = alias
+	if p.tryEat(lexer.LPAREN) {
+		cols, err := p.parseIdentList()
+		if err != nil {
+			return err
+		}
+		stmt.RowAliasColumns = cols
+		if _, err := p.eat(lexer.RPAREN); err != nil {
+			return err
 		}
 	}
-	return stmt, nil
+	return nil
 }
 
 // ---- UPDATE ----
@@ -1306,8 +2379,9 @@ func (p *Parser) parseUpdate() (*ast.UpdateStmt, error) {
 	pos := p.tok.Pos
 	p.advance()
 	stmt := arenaNode(&p.arena, ast.UpdateStmt{TokPos: pos})
+	p.track(stmt)
 	refs, err := p.parseTableRefs()
-	if err != nil {
+	if err != nil && !p.resync(err) {
 		return nil, err
 	}
 	stmt.Tables = refs
@@ -1315,13 +2389,13 @@ func (p *Parser) parseUpdate() (*ast.UpdateStmt, error) {
 		return nil, err
 	}
 	asgn, err := p.parseAssignments()
-	if err != nil {
+	if err != nil && !p.resync(err) {
 		return nil, err
 	}
 	stmt.Set = asgn
 	if p.tryEatKeyword(lexer.WHERE) {
 		w, err := p.parseExpr(0)
-		if err != nil {
+		if err != nil && !p.resync(err) {
 			return nil, err
 		}
 		stmt.Where = w
@@ -1330,18 +2404,23 @@ func (p *Parser) parseUpdate() (*ast.UpdateStmt, error) {
 		p.advance()
 		p.advance()
 		ord, err := p.parseOrderBy()
-		if err != nil {
+		if err != nil && !p.resync(err) {
 			return nil, err
 		}
 		stmt.Order = ord
 	}
 	if p.tryEatKeyword(lexer.LIMIT) {
 		lim, err := p.parseLimit()
-		if err != nil {
+		if err != nil && !p.resync(err) {
 			return nil, err
 		}
 		stmt.Limit = lim
 	}
+	ret, err := p.parseReturning()
+	if err != nil && !p.resync(err) {
+		return nil, err
+	}
+	stmt.Returning = ret
 	return stmt, nil
 }
 
@@ -1351,15 +2430,16 @@ func (p *Parser) parseDelete() (*ast.DeleteStmt, error) {
 	pos := p.tok.Pos
 	p.advance()
 	stmt := arenaNode(&p.arena, ast.DeleteStmt{TokPos: pos})
+	p.track(stmt)
 	p.tryEatKeyword(lexer.FROM)
 	refs, err := p.parseTableRefs()
-	if err != nil {
+	if err != nil && !p.resync(err) {
 		return nil, err
 	}
 	stmt.From = refs
 	if p.tryEatKeyword(lexer.WHERE) {
 		w, err := p.parseExpr(0)
-		if err != nil {
+		if err != nil && !p.resync(err) {
 			return nil, err
 		}
 		stmt.Where = w
@@ -1368,18 +2448,23 @@ func (p *Parser) parseDelete() (*ast.DeleteStmt, error) {
 		p.advance()
 		p.advance()
 		ord, err := p.parseOrderBy()
-		if err != nil {
+		if err != nil && !p.resync(err) {
 			return nil, err
 		}
 		stmt.Order = ord
 	}
 	if p.tryEatKeyword(lexer.LIMIT) {
 		lim, err := p.parseLimit()
-		if err != nil {
+		if err != nil && !p.resync(err) {
 			return nil, err
 		}
 		stmt.Limit = lim
 	}
+	ret, err := p.parseReturning()
+	if err != nil && !p.resync(err) {
+		return nil, err
+	}
+	stmt.Returning = ret
 	return stmt, nil
 }
 
@@ -1388,37 +2473,52 @@ func (p *Parser) parseDelete() (*ast.DeleteStmt, error) {
 func (p *Parser) parseCreate() (ast.Statement, error) {
 	p.advance() // CREATE
 	orReplace := false
-	if p.is(lexer.IDENT) && bytes.EqualFold(p.tok.Raw, []byte("or")) {
+	if p.is(lexer.OR) {
 		p.advance() // OR
 		if err := p.eatKeyword(lexer.REPLACE); err != nil {
 			return nil, err
 		}
 		orReplace = true
 	}
+	p.skipDefiner()
 	temporary := false
-	if p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "temporary") {
+	if p.is(lexer.IDENT) && (equalASCIIFold(p.tok.Raw, "temporary") || equalASCIIFold(p.tok.Raw, "temp")) {
 		p.advance()
 		temporary = true
 	}
-	_ = temporary
 	switch p.tok.Type {
 	case lexer.DATABASE:
 		return p.parseCreateDatabase()
 	case lexer.TABLE:
-		return p.parseCreateTable(orReplace)
+		return p.parseCreateTable(temporary)
 	case lexer.VIEW:
-		return p.parseCreateView(orReplace)
+		return p.parseCreateView(orReplace, temporary)
 	case lexer.INDEX, lexer.UNIQUE:
 		return p.parseCreateIndex()
 	case lexer.FUNCTION, lexer.PROCEDURE, lexer.TRIGGER:
-		return p.parseGenericDDL([]byte("create"), p.tok.Raw)
+		return p.parseGenericDDL(verbCreate, p.tok.Raw)
 	case lexer.IDENT:
 		if equalASCIIFold(p.tok.Raw, "schema") {
 			return p.parseCreateDatabase()
 		}
-		return p.parseGenericDDL([]byte("create"), p.tok.Raw)
+		if equalASCIIFold(p.tok.Raw, "extension") {
+			return p.parseCreateExtension()
+		}
+		if equalASCIIFold(p.tok.Raw, "sequence") {
+			return p.parseCreateSequence(temporary)
+		}
+		if equalASCIIFold(p.tok.Raw, "type") {
+			return p.parseCreateType()
+		}
+		if equalASCIIFold(p.tok.Raw, "domain") {
+			return p.parseCreateDomain()
+		}
+		if (p.isWord("fulltext") || p.isWord("spatial")) && p.peekToken().Type == lexer.INDEX {
+			return p.parseCreateIndex()
+		}
+		return p.parseGenericDDL(verbCreate, p.tok.Raw)
 	default:
-		return p.parseGenericDDL([]byte("create"), p.tok.Raw)
+		return p.parseGenericDDL(verbCreate, p.tok.Raw)
 	}
 }
 
@@ -1429,10 +2529,10 @@ func (p *Parser) parseCreateDatabase() (*ast.CreateDatabaseStmt, error) {
 	if p.is(lexer.IF) {
 		p.advance()
 		if !p.tryEatKeyword(lexer.NOT) {
-			return nil, p.errorf("expected NOT in IF NOT EXISTS")
+			return nil, p.expectf([]lexer.TokenType{lexer.NOT}, "expected NOT in IF NOT EXISTS")
 		}
 		if !p.tryEatKeyword(lexer.EXISTS) {
-			return nil, p.errorf("expected EXISTS in IF NOT EXISTS")
+			return nil, p.expectf([]lexer.TokenType{lexer.EXISTS}, "expected EXISTS in IF NOT EXISTS")
 		}
 		stmt.IfNotExists = true
 	}
@@ -1454,10 +2554,11 @@ func (p *Parser) parseCreateDatabase() (*ast.CreateDatabaseStmt, error) {
 	return stmt, nil
 }
 
-func (p *Parser) parseCreateTable(orReplace bool) (*ast.CreateTableStmt, error) {
+func (p *Parser) parseCreateTable(temporary bool) (*ast.CreateTableStmt, error) {
 	pos := p.tok.Pos
 	p.advance() // TABLE
-	stmt := arenaNode(&p.arena, ast.CreateTableStmt{TokPos: pos})
+	stmt := arenaNode(&p.arena, ast.CreateTableStmt{Temporary: temporary, TokPos: pos})
+	p.track(stmt)
 	if p.is(lexer.IF) {
 		p.advance()
 		p.advance() // NOT
@@ -1493,12 +2594,13 @@ func (p *Parser) parseCreateTable(orReplace bool) (*ast.CreateTableStmt, error)
 		}
 	}
 
-	// Table options (ENGINE=..., CHARSET=..., etc.)
-	for p.is(lexer.IDENT) || p.is(lexer.ENGINE) || p.is(lexer.COMMENT_KW) {
-		key := p.advance().Raw
-		p.tryEat(lexer.EQ)
-		val := p.advance().Raw
-		stmt.Options = arenaAppend(&p.arena, stmt.Options, ast.TableOption{Key: key, Value: val})
+	if err := p.parseCreateTableStorage(stmt); err != nil {
+		return nil, err
+	}
+
+	// Table options (ENGINE=..., CHARSET=..., STRICT, WITHOUT ROWID, etc.)
+	if err := p.parseTableOptions(stmt); err != nil {
+		return nil, err
 	}
 
 	// AS SELECT
@@ -1512,6 +2614,88 @@ func (p *Parser) parseCreateTable(orReplace bool) (*ast.CreateTableStmt, error)
 	return stmt, nil
 }
 
+// parseCreateTableStorage parses the PostgreSQL clauses that follow the
+// column list: INHERITS, USING, WITH (...) and TABLESPACE, in any order.
+func (p *Parser) parseCreateTableStorage(stmt *ast.CreateTableStmt) error {
+	for {
+		switch {
+		case p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "inherits") && p.peekToken().Type == lexer.LPAREN:
+			p.advance()
+			p.advance()
+			for {
+				parent, err := p.parseQualifiedIdent()
+				if err != nil {
+					return err
+				}
+				stmt.Inherits = arenaAppend(&p.arena, stmt.Inherits, parent)
+				if !p.tryEat(lexer.COMMA) {
+					break
+				}
+			}
+			if _, err := p.eat(lexer.RPAREN); err != nil {
+				return err
+			}
+		case p.is(lexer.USING):
+			p.advance()
+			method, err := p.parseIdent()
+			if err != nil {
+				return err
+			}
+			stmt.AccessMethod = method
+		case p.is(lexer.WITH) && p.peekToken().Type == lexer.LPAREN:
+			params, err := p.parseStorageParams()
+			if err != nil {
+				return err
+			}
+			stmt.StorageParams = params
+		case p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "tablespace") && p.peekToken().Type != lexer.EQ:
+			p.advance()
+			ts, err := p.parseIdent()
+			if err != nil {
+				return err
+			}
+			stmt.Tablespace = ts
+		default:
+			return nil
+		}
+	}
+}
+
+// parseStorageParams parses WITH (name [= value], ...), the storage
+// parameters of a PostgreSQL table or index.
+func (p *Parser) parseStorageParams() ([]ast.TableOption, error) {
+	p.advance() // WITH
+	p.advance() // (
+	var params []ast.TableOption
+	for {
+		if !isWordToken(p.tok) {
+			return nil, p.expectf(identTokens, "expected storage parameter name, got %q", p.tok.Raw)
+		}
+		start := p.advance()
+		end := start.Pos + int32(len(start.Raw))
+		for p.is(lexer.DOT) && isWordToken(p.peekToken()) { // toast.autovacuum_enabled
+			p.advance()
+			part := p.advance()
+			end = part.Pos + int32(len(part.Raw))
+		}
+		opt := ast.TableOption{Key: p.lex.Source()[start.Pos:end]}
+		if p.tryEat(lexer.EQ) {
+			if p.is(lexer.RPAREN) || p.is(lexer.COMMA) || p.is(lexer.EOF) {
+				return nil, p.errorf("expected value for storage parameter %q", opt.Key)
+			}
+			opt.Value = p.advance().Raw
+		}
+		params = arenaAppend(&p.arena, params, opt)
+		if !p.tryEat(lexer.COMMA) {
+			break
+		}
+	}
+	if _, err := p.eat(lexer.RPAREN); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
 func (p *Parser) parseCreateTableBody() ([]*ast.ColumnDef, []*ast.TableConstraint, error) {
 	var cols []*ast.ColumnDef
 	var constraints []*ast.TableConstraint
@@ -1583,6 +2767,17 @@ func (p *Parser) parseColumnDef() (*ast.ColumnDef, error) {
 		case lexer.AUTO_INCREMENT:
 			p.advance()
 			col.AutoIncrement = true
+		case lexer.IDENT:
+			switch {
+			case equalASCIIFold(p.tok.Raw, "autoincrement"): // SQLite spelling
+				p.advance()
+				col.AutoIncrement = true
+			case equalASCIIFold(p.tok.Raw, "srid") && p.peekToken().Type == lexer.INT: // MySQL
+				p.advance()
+				col.Type.SRID, _ = strconv.Atoi(string(p.advance().Raw))
+			default:
+				return col, nil
+			}
 		case lexer.PRIMARY:
 			p.advance()
 			p.tryEatKeyword(lexer.KEY)
@@ -1633,9 +2828,32 @@ func (p *Parser) parseDataType() (*ast.DataType, error) {
 	name := p.tok.Raw
 	pos := p.tok.Pos
 	p.advance()
+	// A schema-qualified user type, e.g. public.mood, keeps its dotted
+	// source spelling as the name.
+	for p.is(lexer.DOT) && p.peekToken().Type == lexer.IDENT {
+		p.advance()
+		last := p.advance()
+		name = p.lex.Source()[pos : last.Pos+int32(len(last.Raw))]
+	}
+	name = p.parseTypeWords(name)
 	dt := arenaNode(&p.arena, ast.DataType{Name: name, TokPos: pos})
 
-	if p.is(lexer.LPAREN) {
+	nested := p.is(lexer.LT) || p.is(lexer.LPAREN)
+	switch {
+	case nested && equalASCIIFold(name, "array"):
+		dt.Name = typeArray
+		if err := p.parseElemType(dt); err != nil {
+			return nil, err
+		}
+	case nested && (equalASCIIFold(name, "struct") || equalASCIIFold(name, "tuple")):
+		if err := p.parseStructFields(dt); err != nil {
+			return nil, err
+		}
+	case p.is(lexer.LPAREN) && p.peekToken().Type == lexer.IDENT && (equalASCIIFold(name, "geometry") || equalASCIIFold(name, "geography")):
+		if err := p.parseSpatialTypmod(dt); err != nil {
+			return nil, err
+		}
+	case p.is(lexer.LPAREN):
 		p.advance()
 		if p.is(lexer.INT) {
 			t := p.advance()
@@ -1663,6 +2881,9 @@ func (p *Parser) parseDataType() (*ast.DataType, error) {
 			return nil, err
 		}
 	}
+	if err := p.parseTimeZone(dt); err != nil {
+		return nil, err
+	}
 	// UNSIGNED / ZEROFILL
 	if p.is(lexer.IDENT) {
 		if equalASCIIFold(p.tok.Raw, "unsigned") {
@@ -1674,9 +2895,174 @@ func (p *Parser) parseDataType() (*ast.DataType, error) {
 			dt.Zerofill = true
 		}
 	}
+	// PostgreSQL arrays: INT[], INT[3][3]
+	for p.is(lexer.LBRACKET) {
+		p.advance()
+		p.tryEat(lexer.INT)
+		if _, err := p.eat(lexer.RBRACKET); err != nil {
+			return nil, err
+		}
+		dt = arenaNode(&p.arena, ast.DataType{Name: typeArray, Elem: dt, TokPos: pos})
+	}
 	return dt, nil
 }
 
+// parseSpatialTypmod parses the (Subtype[, srid]) of a PostGIS GEOMETRY
+// or GEOGRAPHY, as in GEOMETRY(Point, 4326).
+func (p *Parser) parseSpatialTypmod(dt *ast.DataType) error {
+	p.advance() // (
+	dt.Subtype = p.advance().Raw
+	if p.tryEat(lexer.COMMA) {
+		t, err := p.eat(lexer.INT)
+		if err != nil {
+			return err
+		}
+		dt.SRID, _ = strconv.Atoi(string(t.Raw))
+	}
+	_, err := p.eat(lexer.RPAREN)
+	return err
+}
+
+// parseTypeWords reads the rest of a standard type name of more than one
+// word and returns the one-word name PostgreSQL and MySQL also know it
+// by, in the case of the first word: DOUBLE PRECISION becomes DOUBLE,
+// CHARACTER VARYING VARCHAR, NATIONAL CHARACTER [VARYING] NCHAR or
+// NVARCHAR and BIT VARYING VARBIT. The fields of an INTERVAL, as in
+// INTERVAL DAY TO SECOND, stay part of its name.
+func (p *Parser) parseTypeWords(name []byte) []byte {
+	switch {
+	case equalASCIIFold(name, "double") && p.isWord("precision"):
+		p.advance()
+	case (equalASCIIFold(name, "character") || equalASCIIFold(name, "char")) && p.isWord("varying"):
+		p.advance()
+		return p.typeNameLike(name, "varchar")
+	case equalASCIIFold(name, "national") && (p.is(lexer.CHARACTER) || p.isWord("char")):
+		p.advance()
+		if p.isWord("varying") {
+			p.advance()
+			return p.typeNameLike(name, "nvarchar")
+		}
+		return p.typeNameLike(name, "nchar")
+	case equalASCIIFold(name, "nchar") && p.isWord("varying"):
+		p.advance()
+		return p.typeNameLike(name, "nvarchar")
+	case equalASCIIFold(name, "bit") && p.isWord("varying"):
+		p.advance()
+		return p.typeNameLike(name, "varbit")
+	case equalASCIIFold(name, "interval") && p.isIntervalField():
+		out := append([]byte(nil), name...)
+		for p.isIntervalField() || p.is(lexer.TO) {
+			out = append(append(out, ' '), p.advance().Raw...)
+		}
+		return p.arena.copyBytes(out)
+	}
+	return name
+}
+
+// isIntervalField reports whether the token is one of the fields that
+// restrict an INTERVAL type.
+func (p *Parser) isIntervalField() bool {
+	return p.is(lexer.YEAR) || p.isWord("month") || p.isWord("day") || p.isWord("hour") || p.isWord("minute") || p.isWord("second")
+}
+
+// parseTimeZone reads the WITH TIME ZONE or WITHOUT TIME ZONE of a
+// TIMESTAMP or TIME type. WITH TIME ZONE makes it TIMESTAMPTZ or TIMETZ;
+// WITHOUT TIME ZONE is the default and leaves it as it is.
+func (p *Parser) parseTimeZone(dt *ast.DataType) error {
+	if !(p.is(lexer.WITH) || p.is(lexer.WITHOUT)) || p.peekToken().Type != lexer.TIME ||
+		!equalASCIIFold(dt.Name, "timestamp") && !equalASCIIFold(dt.Name, "time") {
+		return nil
+	}
+	with := p.advance().Type == lexer.WITH
+	p.advance() // TIME
+	if !p.isWord("zone") {
+		return p.expectf([]lexer.TokenType{lexer.IDENT}, "expected ZONE, got %q", p.tok.Raw)
+	}
+	p.advance()
+	if with {
+		dt.Name = p.arena.copyBytes(append(append([]byte(nil), dt.Name...), p.typeNameLike(dt.Name, "tz")...))
+	}
+	return nil
+}
+
+// typeNameLike returns word, copied into the arena, in upper case when
+// name starts with an upper-case letter.
+func (p *Parser) typeNameLike(name []byte, word string) []byte {
+	if name[0] >= 'A' && name[0] <= 'Z' {
+		word = strings.ToUpper(word)
+	}
+	out := p.arena.alloc(len(word))
+	copy(out, word)
+	return out[:len(word):len(word)]
+}
+
+// parseElemType parses the element type of ARRAY<T> or Array(T).
+func (p *Parser) parseElemType(dt *ast.DataType) error {
+	if err := p.descend(); err != nil {
+		return err
+	}
+	defer func() { p.depth-- }()
+	open := p.advance()
+	elem, err := p.parseDataType()
+	if err != nil {
+		return err
+	}
+	dt.Elem = elem
+	return p.closeNestedType(open)
+}
+
+// parseStructFields parses the members of STRUCT<a T, ...>, STRUCT(a T,
+// ...) or Tuple(a T, ...). A member is unnamed when its first word is
+// directly followed by what ends or continues a type, as in Tuple(String,
+// Array(Int32)).
+func (p *Parser) parseStructFields(dt *ast.DataType) error {
+	if err := p.descend(); err != nil {
+		return err
+	}
+	defer func() { p.depth-- }()
+	open := p.advance()
+	for {
+		var f ast.StructField
+		switch p.peekToken().Type {
+		case lexer.COMMA, lexer.RPAREN, lexer.GT, lexer.RSHIFT, lexer.LPAREN, lexer.LT, lexer.LBRACKET, lexer.DOT:
+		default:
+			name, err := p.parseIdent()
+			if err != nil {
+				return err
+			}
+			f.Name = name
+		}
+		typ, err := p.parseDataType()
+		if err != nil {
+			return err
+		}
+		f.Type = typ
+		dt.Fields = arenaAppend(&p.arena, dt.Fields, f)
+		if !p.tryEat(lexer.COMMA) {
+			break
+		}
+	}
+	return p.closeNestedType(open)
+}
+
+// closeNestedType consumes the ) or > matching open. The >> that ends two
+// nested ARRAY<...> is split, leaving the second > as the current token.
+func (p *Parser) closeNestedType(open lexer.Token) error {
+	if open.Type == lexer.LPAREN {
+		_, err := p.eat(lexer.RPAREN)
+		return err
+	}
+	switch {
+	case p.is(lexer.GT):
+		p.advance()
+	case p.is(lexer.RSHIFT):
+		p.tok.Type, p.tok.Raw, p.tok.Pos = lexer.GT, p.tok.Raw[1:], p.tok.Pos+1
+	default:
+		return p.expectf([]lexer.TokenType{lexer.GT}, "expected > to close the type, got %q", p.tok.Raw)
+	}
+	return nil
+}
+
 func (p *Parser) parseTableConstraint() (*ast.TableConstraint, error) {
 	pos := p.tok.Pos
 	c := arenaNode(&p.arena, ast.TableConstraint{TokPos: pos})
@@ -1749,8 +3135,11 @@ func (p *Parser) parseTableConstraint() (*ast.TableConstraint, error) {
 		}
 		c.RefTable = ref.Table
 		c.RefCols = ref.Columns
+		c.Match = ref.Match
 		c.OnDelete = ref.OnDelete
 		c.OnUpdate = ref.OnUpdate
+		c.Deferrable = ref.Deferrable
+		c.InitiallyDeferred = ref.InitiallyDeferred
 	case lexer.CHECK:
 		p.advance()
 		c.Type = ast.CheckConstraint
@@ -1765,40 +3154,70 @@ func (p *Parser) parseTableConstraint() (*ast.TableConstraint, error) {
 		if _, err := p.eat(lexer.RPAREN); err != nil {
 			return nil, err
 		}
-	default:
-		return nil, p.errorf("expected constraint type, got %q", p.tok.Raw)
-	}
-	return c, nil
-}
-
-func (p *Parser) parseIndexColDefs() ([]*ast.IndexColDef, error) {
-	if _, err := p.eat(lexer.LPAREN); err != nil {
-		return nil, err
+	case lexer.IDENT:
+		// MySQL's FULLTEXT and SPATIAL [INDEX | KEY] [name] (cols)
+		if !p.isWord("fulltext") && !p.isWord("spatial") {
+			return nil, p.expectf(constraintStarts, "expected constraint type, got %q", p.tok.Raw)
+		}
+		c.Type = ast.FulltextConstraint
+		if equalASCIIFold(p.advance().Raw, "spatial") {
+			c.Type = ast.SpatialConstraint
+		}
+		if !p.tryEatKeyword(lexer.INDEX) {
+			p.tryEatKeyword(lexer.KEY)
+		}
+		if p.is(lexer.IDENT) || p.is(lexer.BACKTICK) {
+			c.Name, _ = p.parseIdent()
+		}
+		cols, err := p.parseIndexColDefs()
+		if err != nil {
+			return nil, err
+		}
+		c.Columns = cols
+	default:
+		return nil, p.expectf(constraintStarts, "expected constraint type, got %q", p.tok.Raw)
+	}
+	if c.Type != ast.ForeignKeyConstraint {
+		deferrable, initially, err := p.parseDeferrability()
+		if err != nil {
+			return nil, err
+		}
+		c.Deferrable, c.InitiallyDeferred = deferrable, initially
+	}
+	return c, nil
+}
+
+func (p *Parser) parseIndexColDefs() ([]*ast.IndexColDef, error) {
+	if _, err := p.eat(lexer.LPAREN); err != nil {
+		return nil, err
 	}
 	var cols []*ast.IndexColDef
 	for {
-		name, err := p.parseIdent()
+		icd, err := p.parseIndexKeyPart()
 		if err != nil {
 			return nil, err
 		}
-		icd := arenaNode(&p.arena, ast.IndexColDef{Name: name})
-		if p.is(lexer.LPAREN) {
+		// A PostgreSQL operator class, unless it is NULLS FIRST/LAST.
+		if p.is(lexer.IDENT) && !equalASCIIFold(p.tok.Raw, "nulls") {
+			icd.OpClass = p.tok.Raw
 			p.advance()
-			t, err := p.eat(lexer.INT)
-			if err != nil {
-				return nil, err
-			}
-			n, _ := strconv.Atoi(string(t.Raw))
-			icd.Length = arenaNode(&p.arena, n)
-			if _, err := p.eat(lexer.RPAREN); err != nil {
-				return nil, err
-			}
 		}
 		if p.tryEatKeyword(lexer.DESC) {
 			icd.Desc = true
 		} else {
 			p.tryEatKeyword(lexer.ASC)
 		}
+		if p.isWord("nulls") {
+			p.advance()
+			switch {
+			case p.tryEatKeyword(lexer.FIRST):
+				icd.NullsFirst = &nullsFirst
+			case p.tryEatKeyword(lexer.LAST):
+				icd.NullsFirst = &nullsLast
+			default:
+				return nil, p.expectf([]lexer.TokenType{lexer.FIRST, lexer.LAST}, "expected FIRST or LAST, got %q", p.tok.Raw)
+			}
+		}
 		cols = arenaAppend(&p.arena, cols, icd)
 		if !p.tryEat(lexer.COMMA) {
 			break
@@ -1810,6 +3229,45 @@ func (p *Parser) parseIndexColDefs() ([]*ast.IndexColDef, error) {
 	return cols, nil
 }
 
+// parseIndexKeyPart parses a column with an optional prefix length, as in
+// name(10), or an expression: one in parentheses, or a function call.
+func (p *Parser) parseIndexKeyPart() (*ast.IndexColDef, error) {
+	if p.is(lexer.LPAREN) {
+		expr, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return arenaNode(&p.arena, ast.IndexColDef{Expr: expr}), nil
+	}
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	icd := arenaNode(&p.arena, ast.IndexColDef{Name: name})
+	if !p.is(lexer.LPAREN) {
+		return icd, nil
+	}
+	if p.peekToken().Type != lexer.INT {
+		parts := arenaMakeSlice[*ast.Ident](&p.arena, 1, 1)
+		parts[0] = name
+		call, err := p.parseFuncCall(arenaNode(&p.arena, ast.QualifiedIdent{Parts: parts}))
+		if err != nil {
+			return nil, err
+		}
+		icd.Name, icd.Expr = nil, call
+		return icd, nil
+	}
+	p.advance()
+	t := p.tok
+	p.advance()
+	n, _ := strconv.Atoi(string(t.Raw))
+	icd.Length = arenaNode(&p.arena, n)
+	if _, err := p.eat(lexer.RPAREN); err != nil {
+		return nil, err
+	}
+	return icd, nil
+}
+
 func (p *Parser) parseFKRef() (*ast.ForeignKeyRef, error) {
 	if err := p.eatKeyword(lexer.REFERENCES); err != nil {
 		return nil, err
@@ -1830,6 +3288,19 @@ func (p *Parser) parseFKRef() (*ast.ForeignKeyRef, error) {
 			return nil, err
 		}
 	}
+	if p.tryEatKeyword(lexer.MATCH) {
+		switch {
+		case p.is(lexer.FULL):
+			ref.Match = ast.MatchFull
+		case p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "partial"):
+			ref.Match = ast.MatchPartial
+		case p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "simple"):
+			ref.Match = ast.MatchSimple
+		default:
+			return nil, p.expectf([]lexer.TokenType{lexer.FULL, lexer.IDENT}, "expected FULL, PARTIAL or SIMPLE after MATCH, got %q", p.tok.Raw)
+		}
+		p.advance()
+	}
 	for {
 		if p.is(lexer.ON) {
 			p.advance()
@@ -1845,9 +3316,47 @@ func (p *Parser) parseFKRef() (*ast.ForeignKeyRef, error) {
 			break
 		}
 	}
+	deferrable, initially, err := p.parseDeferrability()
+	if err != nil {
+		return nil, err
+	}
+	ref.Deferrable, ref.InitiallyDeferred = deferrable, initially
 	return ref, nil
 }
 
+// parseDeferrability parses [NOT] DEFERRABLE and INITIALLY DEFERRED |
+// IMMEDIATE, in either order. INITIALLY DEFERRED makes a constraint
+// deferrable on its own.
+func (p *Parser) parseDeferrability() (deferrable, initiallyDeferred bool, err error) {
+	notDeferrable := false
+	for {
+		switch {
+		case p.is(lexer.DEFERRABLE):
+			p.advance()
+			deferrable = true
+		case p.is(lexer.NOT) && p.peekToken().Type == lexer.DEFERRABLE:
+			p.advance()
+			p.advance()
+			notDeferrable = true
+		case p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "initially"):
+			p.advance()
+			switch {
+			case p.is(lexer.DEFERRED):
+				initiallyDeferred = true
+			case p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "immediate"):
+			default:
+				return false, false, p.expectf([]lexer.TokenType{lexer.DEFERRED, lexer.IDENT}, "expected DEFERRED or IMMEDIATE after INITIALLY, got %q", p.tok.Raw)
+			}
+			p.advance()
+		default:
+			if notDeferrable && initiallyDeferred {
+				return false, false, p.errorf("a constraint declared INITIALLY DEFERRED must be DEFERRABLE")
+			}
+			return deferrable || initiallyDeferred, initiallyDeferred, nil
+		}
+	}
+}
+
 func (p *Parser) parseRefAction() ast.RefAction {
 	switch p.tok.Type {
 	case lexer.RESTRICT:
@@ -1889,16 +3398,38 @@ func (p *Parser) parseRefAction() ast.RefAction {
 func (p *Parser) parseCreateIndex() (*ast.CreateIndexStmt, error) {
 	pos := p.tok.Pos
 	typ := ast.IndexConstraint
-	if p.tryEatKeyword(lexer.UNIQUE) {
+	switch {
+	case p.tryEatKeyword(lexer.UNIQUE):
 		typ = ast.UniqueConstraint
+	case p.isWord("fulltext"):
+		p.advance()
+		typ = ast.FulltextConstraint
+	case p.isWord("spatial"):
+		p.advance()
+		typ = ast.SpatialConstraint
 	}
 	p.tryEatKeyword(lexer.INDEX)
 	stmt := arenaNode(&p.arena, ast.CreateIndexStmt{Type: typ, TokPos: pos})
-	name, err := p.parseIdent()
-	if err != nil {
+	p.track(stmt)
+	// CONCURRENTLY is a keyword in PostgreSQL; an index of that name is
+	// written quoted.
+	if p.isWord("concurrently") {
+		p.advance()
+		stmt.Concurrently = true
+	}
+	// PostgreSQL names the index itself when the name is left out.
+	if !p.is(lexer.ON) && !p.is(lexer.USING) {
+		name, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Name = name
+	}
+	// MySQL accepts USING before ON and after the key parts, PostgreSQL
+	// between the table and the key parts.
+	if err := p.parseIndexMethod(stmt); err != nil {
 		return nil, err
 	}
-	stmt.Name = name
 	if err := p.eatKeyword(lexer.ON); err != nil {
 		return nil, err
 	}
@@ -1907,20 +3438,93 @@ func (p *Parser) parseCreateIndex() (*ast.CreateIndexStmt, error) {
 		return nil, err
 	}
 	stmt.Table = table
+	if err := p.parseIndexMethod(stmt); err != nil {
+		return nil, err
+	}
 	cols, err := p.parseIndexColDefs()
 	if err != nil {
 		return nil, err
 	}
 	stmt.Columns = cols
+	if err := p.parseIndexClauses(stmt); err != nil {
+		return nil, err
+	}
 	return stmt, nil
 }
 
+// parseIndexClauses parses what follows the key parts of CREATE INDEX, in
+// any order: USING, INCLUDE (...), WITH (...) and WHERE from PostgreSQL and
+// SQL Server, and MySQL's index options and VISIBLE / INVISIBLE.
+func (p *Parser) parseIndexClauses(stmt *ast.CreateIndexStmt) error {
+	for {
+		switch {
+		case p.is(lexer.USING):
+			if err := p.parseIndexMethod(stmt); err != nil {
+				return err
+			}
+		case p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "include") && p.peekToken().Type == lexer.LPAREN:
+			p.advance()
+			p.advance()
+			cols, err := p.parseIdentList()
+			if err != nil {
+				return err
+			}
+			if _, err := p.eat(lexer.RPAREN); err != nil {
+				return err
+			}
+			stmt.Include = cols
+		case p.is(lexer.WITH) && p.peekToken().Type == lexer.LPAREN:
+			params, err := p.parseStorageParams()
+			if err != nil {
+				return err
+			}
+			stmt.StorageParams = params
+		case p.is(lexer.WHERE) && stmt.Where == nil:
+			p.advance()
+			where, err := p.parseExpr(0)
+			if err != nil {
+				return err
+			}
+			stmt.Where = where
+		case p.is(lexer.IDENT) && (equalASCIIFold(p.tok.Raw, "visible") || equalASCIIFold(p.tok.Raw, "invisible")):
+			stmt.Invisible = equalASCIIFold(p.advance().Raw, "invisible")
+		default:
+			word := p.tok.Raw
+			if p.is(lexer.COMMENT_KW) || p.is(lexer.IDENT) {
+				if spec := lookupOption(indexOptionSpecs, word); spec != nil {
+					p.advance()
+					opt, err := p.parseTableOptionValue(spec)
+					if err != nil {
+						return err
+					}
+					stmt.Options = arenaAppend(&p.arena, stmt.Options, opt)
+					continue
+				}
+			}
+			return nil
+		}
+	}
+}
+
+func (p *Parser) parseIndexMethod(stmt *ast.CreateIndexStmt) error {
+	if !p.tryEatKeyword(lexer.USING) {
+		return nil
+	}
+	if !p.is(lexer.IDENT) {
+		return p.expectf([]lexer.TokenType{lexer.IDENT}, "expected index method, got %q", p.tok.Raw)
+	}
+	stmt.Method = p.tok.Raw
+	p.advance()
+	return nil
+}
+
 // ---- CREATE VIEW ----
 
-func (p *Parser) parseCreateView(orReplace bool) (*ast.CreateViewStmt, error) {
+func (p *Parser) parseCreateView(orReplace, temporary bool) (*ast.CreateViewStmt, error) {
 	pos := p.tok.Pos
 	p.advance() // VIEW
-	stmt := arenaNode(&p.arena, ast.CreateViewStmt{TokPos: pos, OrReplace: orReplace})
+	stmt := arenaNode(&p.arena, ast.CreateViewStmt{TokPos: pos, OrReplace: orReplace, Temporary: temporary})
+	p.track(stmt)
 	name, err := p.parseQualifiedIdent()
 	if err != nil {
 		return nil, err
@@ -1956,14 +3560,18 @@ func (p *Parser) parseAlter() (ast.Statement, error) {
 	if p.is(lexer.DATABASE) || (p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "schema")) {
 		return p.parseAlterDatabase(pos)
 	}
+	if p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "sequence") {
+		return p.parseAlterSequence(pos)
+	}
 	if !p.tryEatKeyword(lexer.TABLE) {
-		return p.parseGenericDDL([]byte("alter"), p.tok.Raw)
+		return p.parseGenericDDL(verbAlter, p.tok.Raw)
 	}
 	name, err := p.parseQualifiedIdent()
 	if err != nil {
 		return nil, err
 	}
 	stmt := arenaNode(&p.arena, ast.AlterTableStmt{Table: name, TokPos: pos})
+	p.track(stmt)
 
 	for {
 		cmd, err := p.parseAlterCmd()
@@ -2049,23 +3657,23 @@ func (p *Parser) parseAlterCmd() (ast.AlterCmd, error) {
 		if equalASCIIFold(p.tok.Raw, "modify") {
 			p.advance()
 			p.tryEatKeyword(lexer.COLUMN)
-			col, err := p.parseColumnDef()
-			if err != nil {
-				return nil, err
-			}
-			cmd := arenaNode(&p.arena, ast.ModifyColumnCmd{Col: col, TokPos: pos})
-			if p.tryEatKeyword(lexer.FIRST) {
-				cmd.First = true
-			} else if p.tryEatKeyword(lexer.AFTER) {
-				after, err := p.parseIdent()
-				if err != nil {
-					return nil, err
-				}
-				cmd.After = after
-			}
-			return cmd, nil
+			return p.parseModifyColumn(pos, nil)
 		}
 
+	case lexer.CHANGE:
+		p.advance()
+		p.tryEatKeyword(lexer.COLUMN)
+		old, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		return p.parseModifyColumn(pos, old)
+
+	case lexer.ALTER:
+		p.advance()
+		p.tryEatKeyword(lexer.COLUMN)
+		return p.parseAlterColumn(pos)
+
 	case lexer.RENAME:
 		p.advance()
 		p.tryEatKeyword(lexer.TO)
@@ -2078,6 +3686,100 @@ func (p *Parser) parseAlterCmd() (ast.AlterCmd, error) {
 	return nil, p.errorf("unexpected ALTER TABLE command: %q", p.tok.Raw)
 }
 
+// parseModifyColumn parses the column definition and position of MySQL's
+// MODIFY COLUMN, or of CHANGE COLUMN old.
+func (p *Parser) parseModifyColumn(pos int32, old *ast.Ident) (ast.AlterCmd, error) {
+	col, err := p.parseColumnDef()
+	if err != nil {
+		return nil, err
+	}
+	cmd := arenaNode(&p.arena, ast.ModifyColumnCmd{Col: col, OldName: old, TokPos: pos})
+	if p.tryEatKeyword(lexer.FIRST) {
+		cmd.First = true
+	} else if p.tryEatKeyword(lexer.AFTER) {
+		after, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		cmd.After = after
+	}
+	return cmd, nil
+}
+
+// parseAlterColumn parses what follows ALTER [COLUMN]: the column name and
+// [SET DATA] TYPE type [USING expr], SET or DROP NOT NULL, or SET or DROP
+// DEFAULT.
+func (p *Parser) parseAlterColumn(pos int32) (ast.AlterCmd, error) {
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	cmd := arenaNode(&p.arena, ast.AlterColumnCmd{Name: name, TokPos: pos})
+	switch {
+	case p.tryEatKeyword(lexer.SET):
+		switch {
+		case p.isWord("data"):
+			p.advance()
+			if !p.isWord("type") {
+				return nil, p.expectf([]lexer.TokenType{lexer.IDENT}, "expected TYPE, got %q", p.tok.Raw)
+			}
+			if err := p.parseAlterColumnType(cmd); err != nil {
+				return nil, err
+			}
+		case p.tryEatKeyword(lexer.NOT):
+			if _, err := p.eat(lexer.NULL_KW); err != nil {
+				return nil, err
+			}
+			cmd.Action = ast.AlterColumnSetNotNull
+		case p.tryEatKeyword(lexer.DEFAULT):
+			def, err := p.parseExpr(0)
+			if err != nil {
+				return nil, err
+			}
+			cmd.Action, cmd.Default = ast.AlterColumnSetDefault, def
+		default:
+			return nil, p.errorf("expected DATA TYPE, NOT NULL or DEFAULT after SET, got %q", p.tok.Raw)
+		}
+	case p.tryEatKeyword(lexer.DROP):
+		switch {
+		case p.tryEatKeyword(lexer.NOT):
+			if _, err := p.eat(lexer.NULL_KW); err != nil {
+				return nil, err
+			}
+			cmd.Action = ast.AlterColumnDropNotNull
+		case p.tryEatKeyword(lexer.DEFAULT):
+			cmd.Action = ast.AlterColumnDropDefault
+		default:
+			return nil, p.errorf("expected NOT NULL or DEFAULT after DROP, got %q", p.tok.Raw)
+		}
+	case p.isWord("type"):
+		if err := p.parseAlterColumnType(cmd); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, p.errorf("expected TYPE, SET or DROP after ALTER COLUMN %s, got %q", name.Unquoted, p.tok.Raw)
+	}
+	return cmd, nil
+}
+
+// parseAlterColumnType parses TYPE type [USING expr].
+func (p *Parser) parseAlterColumnType(cmd *ast.AlterColumnCmd) error {
+	p.advance() // TYPE
+	dt, err := p.parseDataType()
+	if err != nil {
+		return err
+	}
+	cmd.Action, cmd.Type = ast.AlterColumnType, dt
+	if p.tryEatKeyword(lexer.USING) {
+		using, err := p.parseExpr(0)
+		if err != nil {
+			return err
+		}
+		cmd.Using = using
+	}
+	return nil
+}
+
 // ---- DROP ----
 
 func (p *Parser) parseDrop() (ast.Statement, error) {
@@ -2090,10 +3792,11 @@ func (p *Parser) parseDrop() (ast.Statement, error) {
 	case lexer.INDEX:
 		return p.parseDropIndex()
 	case lexer.FUNCTION, lexer.PROCEDURE, lexer.TRIGGER:
-		return p.parseGenericDDL([]byte("drop"), p.tok.Raw)
+		return p.parseGenericDDL(verbDrop, p.tok.Raw)
 	case lexer.VIEW:
 		p.advance()
 		stmt := arenaNode(&p.arena, ast.DropTableStmt{TokPos: p.tok.Pos})
+		p.track(stmt)
 		n, err := p.parseQualifiedIdent()
 		if err != nil {
 			return nil, err
@@ -2104,12 +3807,50 @@ func (p *Parser) parseDrop() (ast.Statement, error) {
 		if equalASCIIFold(p.tok.Raw, "schema") {
 			return p.parseDropDatabase()
 		}
-		return p.parseGenericDDL([]byte("drop"), p.tok.Raw)
+		if equalASCIIFold(p.tok.Raw, "sequence") {
+			return p.parseDropSequence()
+		}
+		if equalASCIIFold(p.tok.Raw, "type") || equalASCIIFold(p.tok.Raw, "domain") {
+			return p.parseDropType(equalASCIIFold(p.tok.Raw, "domain"))
+		}
+		return p.parseGenericDDL(verbDrop, p.tok.Raw)
 	default:
-		return p.parseGenericDDL([]byte("drop"), p.tok.Raw)
+		return p.parseGenericDDL(verbDrop, p.tok.Raw)
+	}
+}
+
+// skipDefiner skips MySQL's DEFINER = user clause, which mysqldump writes
+// before the routines and triggers it dumps. The user is a name with an
+// optional @host, or CURRENT_USER, written without spaces.
+func (p *Parser) skipDefiner() {
+	if !p.is(lexer.IDENT) || !equalASCIIFold(p.tok.Raw, "definer") || p.peekToken().Type != lexer.EQ {
+		return
+	}
+	p.advance() // DEFINER
+	p.advance() // =
+	for end := int32(-1); !p.is(lexer.SEMICOLON) && !p.is(lexer.EOF); {
+		if end >= 0 && p.tok.Pos != end {
+			return
+		}
+		end = p.tok.Pos + int32(len(p.tok.Raw))
+		p.advance()
 	}
 }
 
+// atStatementEnd reports whether the current token ends the statement.
+// After a DELIMITER line, only the new delimiter does: semicolons belong
+// to the body of the routine being defined.
+func (p *Parser) atStatementEnd() bool {
+	switch p.tok.Type {
+	case lexer.EOF:
+		return true
+	case lexer.SEMICOLON:
+		d := p.lex.Delimiter()
+		return d == nil || string(p.tok.Raw) == string(d)
+	}
+	return false
+}
+
 func (p *Parser) parseGenericDDL(verb, obj []byte) (*ast.GenericDDLStmt, error) {
 	pos := p.tok.Pos
 	stmt := arenaNode(&p.arena, ast.GenericDDLStmt{Verb: verb, Object: obj, TokPos: pos})
@@ -2120,9 +3861,14 @@ func (p *Parser) parseGenericDDL(verb, obj []byte) (*ast.GenericDDLStmt, error)
 			stmt.Name = name
 		}
 	}
-	for p.tok.Type != lexer.SEMICOLON && p.tok.Type != lexer.EOF {
+	start, end := p.tok.Pos, p.tok.Pos
+	for !p.atStatementEnd() {
+		end = p.tok.Pos + int32(len(p.tok.Raw))
 		p.advance()
 	}
+	if end > start {
+		stmt.Body = p.lex.Source()[start:end]
+	}
 	return stmt, nil
 }
 
@@ -2133,7 +3879,7 @@ func (p *Parser) parseDropDatabase() (*ast.DropDatabaseStmt, error) {
 	if p.is(lexer.IF) {
 		p.advance()
 		if !p.tryEatKeyword(lexer.EXISTS) {
-			return nil, p.errorf("expected EXISTS in IF EXISTS")
+			return nil, p.expectf([]lexer.TokenType{lexer.EXISTS}, "expected EXISTS in IF EXISTS")
 		}
 		stmt.IfExists = true
 	}
@@ -2149,6 +3895,7 @@ func (p *Parser) parseDropTable() (*ast.DropTableStmt, error) {
 	pos := p.tok.Pos
 	p.advance() // TABLE
 	stmt := arenaNode(&p.arena, ast.DropTableStmt{TokPos: pos})
+	p.track(stmt)
 	if p.is(lexer.IF) {
 		p.advance()
 		p.advance() // EXISTS
@@ -2200,7 +3947,7 @@ func (p *Parser) parseBegin() (*ast.TransactionStmt, error) {
 	if p.is(lexer.TRANSACTION) || (p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "transaction")) {
 		p.advance()
 	}
-	return arenaNode(&p.arena, ast.TransactionStmt{Action: []byte("begin"), TokPos: pos}), nil
+	return arenaNode(&p.arena, ast.TransactionStmt{Action: actionBegin, TokPos: pos}), nil
 }
 
 func (p *Parser) parseCommit() (*ast.TransactionStmt, error) {
@@ -2209,13 +3956,13 @@ func (p *Parser) parseCommit() (*ast.TransactionStmt, error) {
 	if p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "work") {
 		p.advance()
 	}
-	return arenaNode(&p.arena, ast.TransactionStmt{Action: []byte("commit"), TokPos: pos}), nil
+	return arenaNode(&p.arena, ast.TransactionStmt{Action: actionCommit, TokPos: pos}), nil
 }
 
 func (p *Parser) parseRollback() (*ast.TransactionStmt, error) {
 	pos := p.tok.Pos
 	p.advance() // ROLLBACK
-	stmt := arenaNode(&p.arena, ast.TransactionStmt{Action: []byte("rollback"), TokPos: pos})
+	stmt := arenaNode(&p.arena, ast.TransactionStmt{Action: actionRollback, TokPos: pos})
 	if p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "work") {
 		p.advance()
 	}
@@ -2236,10 +3983,10 @@ func (p *Parser) parseStartTransaction() (*ast.TransactionStmt, error) {
 	pos := p.tok.Pos
 	p.advance() // START
 	if !(p.is(lexer.TRANSACTION) || (p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "transaction"))) {
-		return nil, p.errorf("expected TRANSACTION after START")
+		return nil, p.expectf([]lexer.TokenType{lexer.TRANSACTION}, "expected TRANSACTION after START")
 	}
 	p.advance()
-	stmt := arenaNode(&p.arena, ast.TransactionStmt{Action: []byte("start_transaction"), TokPos: pos})
+	stmt := arenaNode(&p.arena, ast.TransactionStmt{Action: actionStartTransaction, TokPos: pos})
 	for !p.is(lexer.SEMICOLON) && !p.is(lexer.EOF) {
 		stmt.Options = arenaAppend(&p.arena, stmt.Options, p.advance().Raw)
 	}
@@ -2254,7 +4001,7 @@ func (p *Parser) parseSavepoint() (*ast.TransactionStmt, error) {
 		return nil, err
 	}
 	return arenaNode(&p.arena, ast.TransactionStmt{
-		Action:    []byte("savepoint"),
+		Action:    actionSavepoint,
 		Savepoint: sp,
 		TokPos:    pos,
 	}), nil
@@ -2271,7 +4018,7 @@ func (p *Parser) parseReleaseSavepoint() (*ast.TransactionStmt, error) {
 		return nil, err
 	}
 	return arenaNode(&p.arena, ast.TransactionStmt{
-		Action:    []byte("release_savepoint"),
+		Action:    actionReleaseSavepoint,
 		Savepoint: sp,
 		TokPos:    pos,
 	}), nil
@@ -2284,7 +4031,7 @@ func (p *Parser) parseSetStmt() (ast.Statement, error) {
 		return nil, p.errorf("unsupported SET statement %q", p.tok.Raw)
 	}
 	p.advance() // TRANSACTION
-	stmt := arenaNode(&p.arena, ast.TransactionStmt{Action: []byte("set_transaction"), TokPos: pos})
+	stmt := arenaNode(&p.arena, ast.TransactionStmt{Action: actionSetTransaction, TokPos: pos})
 	for !p.is(lexer.SEMICOLON) && !p.is(lexer.EOF) {
 		stmt.Options = arenaAppend(&p.arena, stmt.Options, p.advance().Raw)
 	}
@@ -2317,12 +4064,33 @@ func (p *Parser) parseCall() (*ast.CallStmt, error) {
 func (p *Parser) parseTruncate() (*ast.TruncateStmt, error) {
 	pos := p.tok.Pos
 	p.advance()
+	stmt := arenaNode(&p.arena, ast.TruncateStmt{TokPos: pos})
+	p.track(stmt)
 	p.tryEatKeyword(lexer.TABLE)
-	name, err := p.parseQualifiedIdent()
-	if err != nil {
-		return nil, err
+	for {
+		name, err := p.parseQualifiedIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Tables = arenaAppend(&p.arena, stmt.Tables, name)
+		if !p.tryEat(lexer.COMMA) {
+			break
+		}
+	}
+	if p.is(lexer.IDENT) && (equalASCIIFold(p.tok.Raw, "restart") || equalASCIIFold(p.tok.Raw, "continue")) {
+		restart := equalASCIIFold(p.advance().Raw, "restart")
+		if !(p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "identity")) {
+			return nil, p.errorf("expected IDENTITY, got %q", p.tok.Raw)
+		}
+		p.advance()
+		stmt.RestartIdentity = restart
 	}
-	return arenaNode(&p.arena, ast.TruncateStmt{Table: name, TokPos: pos}), nil
+	if p.tryEatKeyword(lexer.CASCADE) {
+		stmt.Cascade = true
+	} else {
+		p.tryEatKeyword(lexer.RESTRICT)
+	}
+	return stmt, nil
 }
 
 func (p *Parser) parseUse() (*ast.UseStmt, error) {
@@ -2335,12 +4103,135 @@ func (p *Parser) parseUse() (*ast.UseStmt, error) {
 	return arenaNode(&p.arena, ast.UseStmt{Database: db, TokPos: pos}), nil
 }
 
+// parsePragma parses SQLite PRAGMA [schema.]name [= value | (value)].
+func (p *Parser) parsePragma() (*ast.PragmaStmt, error) {
+	pos := p.tok.Pos
+	p.advance() // PRAGMA
+	name, err := p.parseQualifiedIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt := arenaNode(&p.arena, ast.PragmaStmt{Name: name, TokPos: pos})
+	switch {
+	case p.tryEat(lexer.EQ):
+	case p.tryEat(lexer.LPAREN):
+		stmt.Call = true
+	default:
+		return stmt, nil
+	}
+	// Values such as ON, FULL or WAL are bare words, not column names.
+	if isWordToken(p.tok) && p.peekToken().Type != lexer.DOT && p.peekToken().Type != lexer.LPAREN {
+		t := p.advance()
+		stmt.Value = arenaNode(&p.arena, ast.Ident{Raw: t.Raw, Unquoted: lowerASCIIStringArena(&p.arena, t.Raw), TokPos: t.Pos})
+	} else {
+		v, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		stmt.Value = v
+	}
+	if stmt.Call {
+		if _, err := p.eat(lexer.RPAREN); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+// parseAttach parses SQLite ATTACH [DATABASE] file AS schema and
+// DETACH [DATABASE] schema.
+func (p *Parser) parseAttach() (*ast.AttachStmt, error) {
+	pos := p.tok.Pos
+	stmt := arenaNode(&p.arena, ast.AttachStmt{Detach: equalASCIIFold(p.advance().Raw, "detach"), TokPos: pos})
+	p.tryEatKeyword(lexer.DATABASE)
+	if !stmt.Detach {
+		file, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		stmt.File = file
+		if err := p.eatKeyword(lexer.AS); err != nil {
+			return nil, err
+		}
+	}
+	schema, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Schema = schema
+	return stmt, nil
+}
+
+// parseShow parses SHOW [GLOBAL | SESSION] [FULL] what, where what is
+// followed by an object type and name for SHOW CREATE, by FROM | IN
+// table for SHOW COLUMNS and SHOW INDEX, by its second word for two-word
+// variants such as SHOW TABLE STATUS and SHOW ENGINE name STATUS, and by
+// FOR user for SHOW GRANTS, and any of them by FROM | IN database and LIKE
+// or WHERE.
 func (p *Parser) parseShow() (*ast.ShowStmt, error) {
 	pos := p.tok.Pos
-	p.advance()
-	what := p.tok.Raw
-	p.advance()
-	stmt := arenaNode(&p.arena, ast.ShowStmt{What: what, TokPos: pos})
+	p.advance() // SHOW
+	stmt := arenaNode(&p.arena, ast.ShowStmt{TokPos: pos})
+	if p.is(lexer.IDENT) && (equalASCIIFold(p.tok.Raw, "global") || equalASCIIFold(p.tok.Raw, "session")) && isWordToken(p.peekToken()) {
+		stmt.Scope = p.advance().Raw
+	}
+	stmt.Full = p.tryEatKeyword(lexer.FULL)
+	if !isWordToken(p.tok) {
+		return nil, p.errorf("expected what to show, got %q", p.tok.Raw)
+	}
+	stmt.What = p.advance().Raw
+	switch {
+	case equalASCIIFold(stmt.What, "create"):
+		if !isWordToken(p.tok) {
+			return nil, p.errorf("expected object type after SHOW CREATE, got %q", p.tok.Raw)
+		}
+		stmt.Object = p.advance().Raw
+		name, err := p.parseQualifiedIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Name = name
+		return stmt, nil
+	case isShowOfTable(stmt.What):
+		if !p.tryEatKeyword(lexer.FROM) && !p.tryEatKeyword(lexer.IN) {
+			return nil, p.expectf([]lexer.TokenType{lexer.FROM, lexer.IN}, "expected FROM or IN after SHOW %s", stmt.What)
+		}
+		name, err := p.parseQualifiedIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Name = name
+	case isShowOfTwoWords(stmt.What):
+		if equalASCIIFold(stmt.What, "engine") {
+			if !isWordToken(p.tok) {
+				return nil, p.errorf("expected engine name after SHOW ENGINE, got %q", p.tok.Raw)
+			}
+			stmt.Engine = p.advance().Raw
+		}
+		if !isWordToken(p.tok) {
+			return nil, p.errorf("expected word after SHOW %s, got %q", stmt.What, p.tok.Raw)
+		}
+		stmt.Detail = p.advance().Raw
+	case equalASCIIFold(stmt.What, "grants") && p.tryEatKeyword(lexer.FOR):
+		// The user is written without spaces: name@host, 'name'@'host'
+		// or CURRENT_USER[()].
+		if p.atStatementEnd() {
+			return nil, p.errorf("expected user after SHOW GRANTS FOR")
+		}
+		start, end := p.tok.Pos, p.tok.Pos
+		for !p.atStatementEnd() && (end == start || p.tok.Pos == end) {
+			end = p.tok.Pos + int32(len(p.tok.Raw))
+			p.advance()
+		}
+		stmt.User = p.lex.Source()[start:end]
+	}
+	if p.tryEatKeyword(lexer.FROM) || p.tryEatKeyword(lexer.IN) {
+		db, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Database = db
+	}
 	if p.tryEatKeyword(lexer.LIKE) {
 		t, err := p.eat(lexer.STRING)
 		if err != nil {
@@ -2357,14 +4248,246 @@ func (p *Parser) parseShow() (*ast.ShowStmt, error) {
 	return stmt, nil
 }
 
-func (p *Parser) parseExplain() (*ast.ExplainStmt, error) {
+// isShowOfTable reports whether SHOW what lists something of a table, as
+// SHOW COLUMNS FROM t does.
+func isShowOfTable(what []byte) bool {
+	for _, w := range []string{"columns", "fields", "index", "indexes", "keys"} {
+		if equalASCIIFold(what, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// isShowOfTwoWords reports whether SHOW what takes a second word, as
+// SHOW TABLE STATUS does.
+func isShowOfTwoWords(what []byte) bool {
+	for _, w := range []string{"table", "engine", "master", "slave", "replica", "binary", "open",
+		"procedure", "function", "character", "storage", "binlog", "relaylog"} {
+		if equalASCIIFold(what, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// ---- Maintenance statements ----
+
+func (p *Parser) parseCreateExtension() (*ast.MaintenanceStmt, error) {
 	pos := p.tok.Pos
-	p.advance()
-	inner, err := p.parseStatement()
+	p.advance() // EXTENSION
+	stmt := arenaNode(&p.arena, ast.MaintenanceStmt{Kind: ast.CreateExtension, TokPos: pos})
+	if p.is(lexer.IF) {
+		p.advance()
+		if !p.tryEatKeyword(lexer.NOT) || !p.tryEatKeyword(lexer.EXISTS) {
+			return nil, p.errorf("expected IF NOT EXISTS")
+		}
+		stmt.IfNotExists = true
+	}
+	name, err := p.parseQualifiedIdent()
 	if err != nil {
 		return nil, err
 	}
-	return arenaNode(&p.arena, ast.ExplainStmt{Stmt: inner, TokPos: pos}), nil
+	stmt.Targets = arenaAppend(&p.arena, stmt.Targets, ast.MaintenanceTarget{Name: name})
+	p.tryEatKeyword(lexer.WITH)
+	for isWordToken(p.tok) {
+		key := p.advance().Raw
+		if equalASCIIFold(key, "cascade") {
+			stmt.Options = arenaAppend(&p.arena, stmt.Options, ast.TableOption{Key: key})
+			continue
+		}
+		if p.is(lexer.SEMICOLON) || p.is(lexer.EOF) {
+			return nil, p.errorf("expected value for %s", key)
+		}
+		stmt.Options = arenaAppend(&p.arena, stmt.Options, ast.TableOption{Key: key, Value: p.advance().Raw})
+	}
+	return stmt, nil
+}
+
+func (p *Parser) parseVacuum() (*ast.MaintenanceStmt, error) {
+	pos := p.tok.Pos
+	p.advance() // VACUUM
+	stmt := arenaNode(&p.arena, ast.MaintenanceStmt{Kind: ast.Vacuum, TokPos: pos})
+	if err := p.parseMaintenanceFlags(stmt, "full", "freeze", "verbose", "analyze"); err != nil {
+		return nil, err
+	}
+	if err := p.parseMaintenanceTargets(stmt, true); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseAnalyze handles both PostgreSQL/SQLite ANALYZE [VERBOSE] [table] and
+// MySQL ANALYZE [NO_WRITE_TO_BINLOG | LOCAL] TABLE t, ...
+func (p *Parser) parseAnalyze() (*ast.MaintenanceStmt, error) {
+	pos := p.tok.Pos
+	p.advance() // ANALYZE
+	if p.is(lexer.TABLE) || (p.is(lexer.IDENT) && p.peekToken().Type == lexer.TABLE) {
+		return p.parseMySQLTableMaintenanceAt(ast.AnalyzeTable, pos)
+	}
+	stmt := arenaNode(&p.arena, ast.MaintenanceStmt{Kind: ast.Analyze, TokPos: pos})
+	if err := p.parseMaintenanceFlags(stmt, "verbose"); err != nil {
+		return nil, err
+	}
+	if err := p.parseMaintenanceTargets(stmt, true); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+func (p *Parser) parseReindex() (*ast.MaintenanceStmt, error) {
+	pos := p.tok.Pos
+	p.advance() // REINDEX
+	stmt := arenaNode(&p.arena, ast.MaintenanceStmt{Kind: ast.Reindex, TokPos: pos})
+	if err := p.parseMaintenanceFlags(stmt); err != nil {
+		return nil, err
+	}
+	switch {
+	case p.is(lexer.INDEX), p.is(lexer.TABLE), p.is(lexer.DATABASE),
+		p.is(lexer.IDENT) && (equalASCIIFold(p.tok.Raw, "schema") || equalASCIIFold(p.tok.Raw, "system")):
+		stmt.Object = p.advance().Raw
+	}
+	if p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "concurrently") {
+		stmt.Options = arenaAppend(&p.arena, stmt.Options, ast.TableOption{Key: p.advance().Raw})
+	}
+	if err := p.parseMaintenanceTargets(stmt, false); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+func (p *Parser) parseCluster() (*ast.MaintenanceStmt, error) {
+	pos := p.tok.Pos
+	p.advance() // CLUSTER
+	stmt := arenaNode(&p.arena, ast.MaintenanceStmt{Kind: ast.Cluster, TokPos: pos})
+	if err := p.parseMaintenanceFlags(stmt, "verbose"); err != nil {
+		return nil, err
+	}
+	if err := p.parseMaintenanceTargets(stmt, false); err != nil {
+		return nil, err
+	}
+	if p.is(lexer.USING) {
+		key := p.advance().Raw
+		idx, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Options = arenaAppend(&p.arena, stmt.Options, ast.TableOption{Key: key, Value: idx.Raw})
+	}
+	return stmt, nil
+}
+
+func (p *Parser) parseMySQLTableMaintenance(kind ast.MaintenanceKind) (*ast.MaintenanceStmt, error) {
+	pos := p.tok.Pos
+	p.advance() // OPTIMIZE | CHECK
+	return p.parseMySQLTableMaintenanceAt(kind, pos)
+}
+
+// parseMySQLTableMaintenanceAt parses the remainder of
+// OPTIMIZE/ANALYZE/CHECK [NO_WRITE_TO_BINLOG | LOCAL] TABLE t, ... [option ...].
+func (p *Parser) parseMySQLTableMaintenanceAt(kind ast.MaintenanceKind, pos int32) (*ast.MaintenanceStmt, error) {
+	stmt := arenaNode(&p.arena, ast.MaintenanceStmt{Kind: kind, TokPos: pos})
+	if p.is(lexer.IDENT) {
+		stmt.Options = arenaAppend(&p.arena, stmt.Options, ast.TableOption{Key: p.advance().Raw})
+	}
+	if err := p.eatKeyword(lexer.TABLE); err != nil {
+		return nil, err
+	}
+	if err := p.parseMaintenanceTargets(stmt, false); err != nil {
+		return nil, err
+	}
+	if len(stmt.Targets) == 0 {
+		return nil, p.errorf("expected table name, got %q", p.tok.Raw)
+	}
+	// CHECK TABLE options: FOR UPGRADE | QUICK | FAST | MEDIUM | EXTENDED | CHANGED
+	for kind == ast.CheckTable && isWordToken(p.tok) {
+		key := p.advance().Raw
+		if bytes.EqualFold(key, []byte("for")) {
+			if !isWordToken(p.tok) {
+				return nil, p.errorf("expected UPGRADE after FOR, got %q", p.tok.Raw)
+			}
+			stmt.Options = arenaAppend(&p.arena, stmt.Options, ast.TableOption{Key: key, Value: p.advance().Raw})
+			continue
+		}
+		stmt.Options = arenaAppend(&p.arena, stmt.Options, ast.TableOption{Key: key})
+	}
+	return stmt, nil
+}
+
+// parseMaintenanceFlags reads a parenthesised option list, e.g.
+// (VERBOSE, PARALLEL 4), followed by any of the given bare flag words.
+func (p *Parser) parseMaintenanceFlags(stmt *ast.MaintenanceStmt, flags ...string) error {
+	if p.tryEat(lexer.LPAREN) {
+		for {
+			if !isWordToken(p.tok) {
+				return p.errorf("expected option name, got %q", p.tok.Raw)
+			}
+			opt := ast.TableOption{Key: p.advance().Raw}
+			if !p.is(lexer.COMMA) && !p.is(lexer.RPAREN) {
+				opt.Value = p.advance().Raw
+			}
+			stmt.Options = arenaAppend(&p.arena, stmt.Options, opt)
+			if !p.tryEat(lexer.COMMA) {
+				break
+			}
+		}
+		if _, err := p.eat(lexer.RPAREN); err != nil {
+			return err
+		}
+	}
+	for isWordToken(p.tok) {
+		matched := false
+		for _, f := range flags {
+			if equalASCIIFold(p.tok.Raw, f) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			break
+		}
+		stmt.Options = arenaAppend(&p.arena, stmt.Options, ast.TableOption{Key: p.advance().Raw})
+	}
+	return nil
+}
+
+// parseMaintenanceTargets reads an optional comma-separated list of object
+// names, each optionally followed by a column list when columns is set.
+func (p *Parser) parseMaintenanceTargets(stmt *ast.MaintenanceStmt, columns bool) error {
+	if !p.is(lexer.IDENT) && !p.is(lexer.BACKTICK) && !p.is(lexer.DQUOTE) {
+		return nil
+	}
+	for {
+		name, err := p.parseQualifiedIdent()
+		if err != nil {
+			return err
+		}
+		target := ast.MaintenanceTarget{Name: name}
+		if columns && p.tryEat(lexer.LPAREN) {
+			cols, err := p.parseIdentList()
+			if err != nil {
+				return err
+			}
+			target.Columns = cols
+			if _, err := p.eat(lexer.RPAREN); err != nil {
+				return err
+			}
+		}
+		stmt.Targets = arenaAppend(&p.arena, stmt.Targets, target)
+		if !p.tryEat(lexer.COMMA) {
+			return nil
+		}
+	}
+}
+
+// isWordToken reports whether t is an identifier or keyword, as opposed to a
+// literal or punctuation.
+func isWordToken(t lexer.Token) bool {
+	if len(t.Raw) == 0 || t.Type == lexer.HEXLIT || t.Type == lexer.BITLIT {
+		return false
+	}
+	c := t.Raw[0]
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
 }
 
 // parseUnknownStmt skips tokens until a semicolon or EOF.
@@ -2382,7 +4505,11 @@ func (p *Parser) parseIdent() (*ast.Ident, error) {
 	switch t.Type {
 	case lexer.IDENT, lexer.BACKTICK, lexer.DQUOTE:
 		p.advance()
-		unquoted := unquoteIdentArena(&p.arena, t.Raw)
+		esc := byte('\\')
+		if t.Raw[0] == 'u' || t.Raw[0] == 'U' {
+			esc = p.parseUEscape()
+		}
+		unquoted := unquoteIdentArena(&p.arena, t.Raw, esc)
 		return arenaNode(&p.arena, ast.Ident{Raw: t.Raw, Unquoted: unquoted, TokPos: t.Pos}), nil
 	default:
 		// Allow keywords as identifiers in column/table positions
@@ -2390,11 +4517,10 @@ func (p *Parser) parseIdent() (*ast.Ident, error) {
 			p.advance()
 			return arenaNode(&p.arena, ast.Ident{Raw: t.Raw, Unquoted: lowerASCIIStringArena(&p.arena, t.Raw), TokPos: t.Pos}), nil
 		}
-		return nil, p.errorf("expected identifier, got %q", t.Raw)
+		return nil, p.expectf(identTokens, "expected identifier, got %q", t.Raw)
 	}
 }
 
-
 func (p *Parser) parseQualifiedIdent() (*ast.QualifiedIdent, error) {
 	id, err := p.parseIdent()
 	if err != nil {
@@ -2453,7 +4579,7 @@ func (p *Parser) parseAssignments() ([]ast.Assignment, error) {
 		if _, err := p.eat(lexer.EQ); err != nil {
 			return nil, err
 		}
-		val, err := p.parseExpr(0)
+		val, err := p.parseValue(true)
 		if err != nil {
 			return nil, err
 		}
@@ -2465,15 +4591,84 @@ func (p *Parser) parseAssignments() ([]ast.Assignment, error) {
 	return asgn, nil
 }
 
-// unquoteIdent strips backtick or double-quote delimiters.
-func unquoteIdentArena(a *arena, raw []byte) string {
+// parseValue parses an expression, or with defaults the keyword DEFAULT
+// standing for the column's default.
+func (p *Parser) parseValue(defaults bool) (ast.Expr, error) {
+	if defaults && p.is(lexer.DEFAULT) {
+		return arenaNode(&p.arena, ast.DefaultExpr{TokPos: p.advance().Pos}), nil
+	}
+	return p.parseExpr(0)
+}
+
+// parseUEscape parses the UESCAPE 'c' clause that may follow a U&"..."
+// identifier and returns its escape character, backslash by default.
+func (p *Parser) parseUEscape() byte {
+	if !p.is(lexer.IDENT) || !equalASCIIFold(p.tok.Raw, "uescape") {
+		return '\\'
+	}
+	if s := p.peekToken(); s.Type != lexer.STRING || len(s.Raw) != 3 {
+		return '\\'
+	}
+	p.advance() // UESCAPE
+	return p.advance().Raw[1]
+}
+
+// unquoteIdentArena returns the name an identifier stands for. Quoted
+// names lose their delimiters and have doubled ones folded, so "say ""hi"""
+// is say "hi"; PostgreSQL's U&"..." names also have their Unicode
+// escapes, esc followed by four hex digits or by + and six, decoded. Bare
+// names are lowered.
+func unquoteIdentArena(a *arena, raw []byte, esc byte) string {
+	unicode := len(raw) >= 4 && (raw[0] == 'u' || raw[0] == 'U') && raw[1] == '&' && raw[2] == '"'
+	if unicode {
+		raw = raw[2:]
+	}
 	if len(raw) < 2 {
 		return lowerASCIIStringArena(a, raw)
 	}
-	if (raw[0] == '`' || raw[0] == '"') && raw[len(raw)-1] == raw[0] {
-		return bytesToString(raw[1 : len(raw)-1])
+	q := raw[0]
+	if (q != '`' && q != '"') || raw[len(raw)-1] != q {
+		return lowerASCIIStringArena(a, raw)
+	}
+	body := raw[1 : len(raw)-1]
+	if !unicode && bytes.IndexByte(body, q) < 0 {
+		return bytesToString(body)
+	}
+	// Folding and decoding only ever shorten the name.
+	dst := a.alloc(len(body))[:0:len(body)]
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if c == q && i+1 < len(body) && body[i+1] == q {
+			i++
+		} else if unicode && c == esc {
+			if r, n := unicodeEscape(body[i+1:], esc); n > 0 {
+				dst = utf8.AppendRune(dst, r)
+				i += n
+				continue
+			}
+		}
+		dst = append(dst, c)
+	}
+	return bytesToString(dst)
+}
+
+// unicodeEscape decodes the escape that follows esc in s, returning the
+// character and the number of bytes read, or 0 when s does not start with
+// one.
+func unicodeEscape(s []byte, esc byte) (rune, int) {
+	switch {
+	case len(s) > 0 && s[0] == esc:
+		return rune(esc), 1
+	case len(s) >= 7 && s[0] == '+':
+		if v, err := strconv.ParseUint(string(s[1:7]), 16, 32); err == nil {
+			return rune(v), 7
+		}
+	case len(s) >= 4:
+		if v, err := strconv.ParseUint(string(s[:4]), 16, 32); err == nil {
+			return rune(v), 4
+		}
 	}
-	return lowerASCIIStringArena(a, raw)
+	return 0, 0
 }
 
 func lowerASCIIStringArena(a *arena, raw []byte) string {
@@ -1,10 +1,16 @@
 package parser_test
 
 import (
+	"errors"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 
 	sqlparser "github.com/oarkflow/sqlparser"
 	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
 )
 
 // ---- helpers ----
@@ -73,6 +79,79 @@ func TestSelectSubquery(t *testing.T) {
 		) sub WHERE sub.name LIKE 'A%'`)
 }
 
+func TestQuantifiedComparison(t *testing.T) {
+	sel := mustParse(t, "SELECT id FROM orders WHERE total > ALL (SELECT total FROM refunds) AND user_id = ANY ($1) OR 1 <> some (SELECT 2)").(*ast.SelectStmt)
+	or := sel.Where.(*ast.BinaryExpr)
+	and := or.Left.(*ast.BinaryExpr)
+	all := and.Left.(*ast.QuantifiedComparisonExpr)
+	if all.Op != lexer.GT || all.Quantifier != ast.QuantifyAll || all.Subq == nil || all.Array != nil {
+		t.Errorf("> ALL (subquery): %+v", all)
+	}
+	if arr := and.Right.(*ast.QuantifiedComparisonExpr); arr.Quantifier != ast.QuantifyAny || arr.Subq != nil {
+		t.Errorf("= ANY (array): %+v", arr)
+	} else if _, ok := arr.Array.(*ast.Param); !ok {
+		t.Errorf("= ANY array operand: %T", arr.Array)
+	}
+	if some := or.Right.(*ast.QuantifiedComparisonExpr); some.Op != lexer.NEQ || some.Quantifier != ast.QuantifySome {
+		t.Errorf("<> SOME: %+v", some)
+	}
+	// Without parentheses ANY is an ordinary name.
+	mustParse(t, "SELECT id FROM t WHERE any = 1")
+}
+
+func TestValuesTable(t *testing.T) {
+	vals := mustParse(t, "VALUES (1, 'a'), (2, 'b') ORDER BY 1 LIMIT 1").(*ast.SelectStmt)
+	if len(vals.Values) != 2 || len(vals.Values[1]) != 2 || vals.Columns != nil || len(vals.OrderBy) != 1 || vals.Limit == nil {
+		t.Errorf("VALUES statement: %+v", vals)
+	}
+	if row := mustParse(t, "VALUES ROW(1, 2), ROW(3, 4)").(*ast.SelectStmt); len(row.Values) != 2 {
+		t.Errorf("MySQL ROW(...) rows: %+v", row)
+	}
+
+	sel := mustParse(t, "SELECT v.id FROM (VALUES (1, 'a'), (2, 'b')) AS v (id, name) JOIN users u ON u.id = v.id").(*ast.SelectStmt)
+	sub := sel.From[0].(*ast.JoinTable).Left.(*ast.SubqueryTable)
+	if len(sub.Subq.Values) != 2 || sub.Alias.Unquoted != "v" || len(sub.Columns) != 2 || sub.Columns[1].Unquoted != "name" {
+		t.Errorf("VALUES in FROM: %+v", sub)
+	}
+	union := mustParse(t, "SELECT 1 UNION ALL VALUES (2)").(*ast.SelectStmt)
+	if union.SetOp == nil || len(union.SetOp.Right.Values) != 1 {
+		t.Errorf("VALUES in UNION: %+v", union)
+	}
+	with := mustParse(t, "WITH seed (n) AS (VALUES (1), (2)) SELECT n FROM seed").(*ast.SelectStmt)
+	if len(with.With.CTEs[0].Subq.Values) != 2 {
+		t.Errorf("VALUES in CTE: %+v", with.With.CTEs[0].Subq)
+	}
+	if _, err := sqlparser.ParseStatement("VALUES (1, 2"); err == nil {
+		t.Error("expected an error for an unclosed row")
+	}
+}
+
+func TestInsertDefaultsAndSet(t *testing.T) {
+	def := mustParse(t, "INSERT INTO users DEFAULT VALUES").(*ast.InsertStmt)
+	if !def.DefaultValues || def.Columns != nil || def.Values != nil {
+		t.Errorf("DEFAULT VALUES: %+v", def)
+	}
+	set := mustParse(t, "INSERT INTO users SET name = 'x', created_at = DEFAULT ON DUPLICATE KEY UPDATE name = 'y'").(*ast.InsertStmt)
+	if !set.SetForm || len(set.Columns) != 2 || set.Columns[1].Unquoted != "created_at" || len(set.Values) != 1 || len(set.OnDupKey) != 1 {
+		t.Fatalf("INSERT ... SET: %+v", set)
+	}
+	if _, ok := set.Values[0][1].(*ast.DefaultExpr); !ok {
+		t.Errorf("SET value DEFAULT: %T", set.Values[0][1])
+	}
+	if rep := mustParse(t, "REPLACE INTO users SET id = 1").(*ast.InsertStmt); !rep.Replace || !rep.SetForm {
+		t.Errorf("REPLACE ... SET: %+v", rep)
+	}
+	vals := mustParse(t, "INSERT INTO users (id, name) VALUES (DEFAULT, 'a'), (2, DEFAULT)").(*ast.InsertStmt)
+	if _, ok := vals.Values[1][1].(*ast.DefaultExpr); !ok {
+		t.Errorf("VALUES DEFAULT: %T", vals.Values[1][1])
+	}
+	for _, sql := range []string{"INSERT INTO users (id) DEFAULT VALUES", "VALUES (DEFAULT)", "SELECT DEFAULT"} {
+		if _, err := sqlparser.ParseStatement(sql); err == nil {
+			t.Errorf("expected an error for %q", sql)
+		}
+	}
+}
+
 func TestSelectCTE(t *testing.T) {
 	mustParse(t, `
 		WITH active_users AS (
@@ -86,6 +165,45 @@ func TestSelectCTE(t *testing.T) {
 		JOIN recent_orders r ON u.id = r.user_id`)
 }
 
+func TestCTEMaterializedAndDML(t *testing.T) {
+	sel := mustParse(t, `
+		WITH a AS MATERIALIZED (SELECT 1), b AS NOT MATERIALIZED (SELECT 2), c AS (SELECT 3)
+		SELECT * FROM a, b, c`).(*ast.SelectStmt)
+	want := []ast.Materialization{ast.MaterializeAlways, ast.MaterializeNever, ast.MaterializeDefault}
+	for i, cte := range sel.With.CTEs {
+		if cte.Materialized != want[i] || cte.Subq == nil {
+			t.Errorf("CTE %d: %+v", i, cte)
+		}
+	}
+
+	sel = mustParse(t, `
+		WITH moved AS (DELETE FROM queue WHERE done RETURNING *),
+		     logged AS (INSERT INTO log (id) SELECT id FROM moved RETURNING id AS logged_id)
+		SELECT logged_id FROM logged`).(*ast.SelectStmt)
+	if _, ok := sel.With.CTEs[0].DML.(*ast.DeleteStmt); !ok || sel.With.CTEs[0].Subq != nil {
+		t.Fatalf("DELETE CTE: %+v", sel.With.CTEs[0])
+	}
+	ins, ok := sel.With.CTEs[1].DML.(*ast.InsertStmt)
+	if !ok || len(ins.Returning) != 1 || ins.Returning[0].Alias.Unquoted != "logged_id" {
+		t.Fatalf("INSERT CTE: %+v", sel.With.CTEs[1])
+	}
+
+	del := mustParse(t, "DELETE FROM t RETURNING *").(*ast.DeleteStmt)
+	if len(del.Returning) != 1 || !del.Returning[0].Star {
+		t.Errorf("DELETE RETURNING: %+v", del.Returning)
+	}
+	if from := del.From[0].(*ast.SimpleTable); from.Alias != nil {
+		t.Errorf("RETURNING read as a table alias: %+v", from.Alias)
+	}
+	upd := mustParse(t, "UPDATE t SET n = n + 1 WHERE id = 1 RETURNING n, id").(*ast.UpdateStmt)
+	if len(upd.Returning) != 2 {
+		t.Errorf("UPDATE RETURNING: %+v", upd.Returning)
+	}
+	if _, err := sqlparser.ParseStatement("WITH x AS NOT (SELECT 1) SELECT * FROM x"); err == nil {
+		t.Error("expected an error for NOT without MATERIALIZED")
+	}
+}
+
 func TestSelectCase(t *testing.T) {
 	mustParse(t, `
 		SELECT id,
@@ -133,6 +251,203 @@ func TestSelectSetOpChain(t *testing.T) {
 	}
 }
 
+func TestSelectSetOpTree(t *testing.T) {
+	sel := mustParse(t, "SELECT a FROM t UNION SELECT b FROM u EXCEPT SELECT c FROM v ORDER BY 1 LIMIT 5").(*ast.SelectStmt)
+	if sel.SetOp == nil || sel.SetOp.Op != ast.Except || sel.SetOp.Left.SetOp == nil || sel.SetOp.Left.SetOp.Op != ast.Union {
+		t.Fatalf("UNION and EXCEPT should group from the left: %+v", sel.SetOp)
+	}
+	if len(sel.OrderBy) != 1 || sel.Limit == nil || sel.SetOp.Right.OrderBy != nil || sel.SetOp.Right.Limit != nil {
+		t.Errorf("ORDER BY and LIMIT should apply to the whole set operation")
+	}
+
+	sel = mustParse(t, "SELECT 1 UNION SELECT 2 INTERSECT SELECT 3").(*ast.SelectStmt)
+	if sel.SetOp.Op != ast.Union || sel.SetOp.Right.SetOp == nil || sel.SetOp.Right.SetOp.Op != ast.Intersect {
+		t.Errorf("INTERSECT should bind tighter than UNION: %+v", sel.SetOp)
+	}
+
+	sel = mustParse(t, "(SELECT a FROM t ORDER BY a LIMIT 3) UNION ALL (SELECT 1 UNION SELECT 2) ORDER BY 1").(*ast.SelectStmt)
+	if !sel.SetOp.All || sel.SetOp.Left.Limit == nil || len(sel.SetOp.Left.OrderBy) != 1 || sel.SetOp.Right.SetOp == nil || len(sel.OrderBy) != 1 {
+		t.Errorf("parenthesized operands: %+v", sel.SetOp)
+	}
+
+	if sel := mustParse(t, "(SELECT a FROM t) ORDER BY a").(*ast.SelectStmt); sel.SetOp != nil || len(sel.OrderBy) != 1 {
+		t.Errorf("outer ORDER BY of a parenthesized SELECT: %+v", sel)
+	}
+	for _, sql := range []string{
+		"(SELECT a FROM t LIMIT 1) LIMIT 2",
+		"SELECT a FROM t ORDER BY a UNION SELECT b FROM u",
+		"(SELECT 1 UNION SELECT 2",
+	} {
+		if _, err := sqlparser.ParseStatements(sql); err == nil {
+			t.Errorf("expected an error for %q", sql)
+		}
+	}
+}
+
+func TestTableIndexHintsAndSample(t *testing.T) {
+	sel := mustParse(t, "SELECT * FROM orders o FORCE INDEX (idx_created) IGNORE KEY FOR ORDER BY (PRIMARY, idx_b) JOIN users USE INDEX () ON o.uid = users.id").(*ast.SelectStmt)
+	orders := sel.From[0].(*ast.JoinTable).Left.(*ast.SimpleTable)
+	if orders.Alias == nil || len(orders.IndexHints) != 2 {
+		t.Fatalf("orders: %+v", orders)
+	}
+	if h := orders.IndexHints[0]; h.Kind != ast.ForceIndex || h.For != ast.HintAll || len(h.Indexes) != 1 {
+		t.Errorf("FORCE INDEX: %+v", h)
+	}
+	if h := orders.IndexHints[1]; h.Kind != ast.IgnoreIndex || h.For != ast.HintOrderBy || len(h.Indexes) != 2 {
+		t.Errorf("IGNORE KEY FOR ORDER BY: %+v", h)
+	}
+	users := sel.From[0].(*ast.JoinTable).Right.(*ast.SimpleTable)
+	if len(users.IndexHints) != 1 || users.IndexHints[0].Kind != ast.UseIndex || len(users.IndexHints[0].Indexes) != 0 {
+		t.Errorf("USE INDEX (): %+v", users.IndexHints)
+	}
+
+	sel = mustParse(t, "SELECT * FROM events TABLESAMPLE BERNOULLI (2.5) REPEATABLE (42) WHERE kind = 'click'").(*ast.SelectStmt)
+	events := sel.From[0].(*ast.SimpleTable)
+	if events.Alias != nil || events.Sample == nil || events.Sample.Method.Unquoted != "bernoulli" || len(events.Sample.Args) != 1 || events.Sample.Repeatable == nil || sel.Where == nil {
+		t.Errorf("TABLESAMPLE: %+v", events)
+	}
+	if sel := mustParse(t, "SELECT * FROM t AS force").(*ast.SelectStmt); sel.From[0].(*ast.SimpleTable).Alias == nil {
+		t.Error("alias force")
+	}
+	for _, sql := range []string{"SELECT * FROM t FORCE INDEX ()", "SELECT * FROM t USE INDEX FOR UPDATE (a)"} {
+		if _, err := sqlparser.ParseStatement(sql); err == nil {
+			t.Errorf("expected an error for %q", sql)
+		}
+	}
+}
+
+func TestSelectTableStarExcept(t *testing.T) {
+	sel := mustParse(t, "SELECT o.*, app.users.*, COUNT(o.*) FROM app.users JOIN orders o ON o.uid = users.id").(*ast.SelectStmt)
+	if ts, ok := sel.Columns[0].Expr.(*ast.TableStar); !ok || sel.Columns[0].Star || len(ts.Table.Parts) != 1 || ts.Table.Parts[0].Unquoted != "o" {
+		t.Errorf("o.*: %+v", sel.Columns[0])
+	}
+	if ts, ok := sel.Columns[1].Expr.(*ast.TableStar); !ok || len(ts.Table.Parts) != 2 || ts.Pos() != 12 {
+		t.Errorf("app.users.*: %+v", sel.Columns[1].Expr)
+	}
+	if f := sel.Columns[2].Expr.(*ast.FuncCall); len(f.Args) != 1 {
+		t.Errorf("COUNT(o.*): %+v", f)
+	} else if _, ok := f.Args[0].(*ast.TableStar); !ok {
+		t.Errorf("COUNT(o.*) argument: %T", f.Args[0])
+	}
+
+	if _, err := sqlparser.ParseStatements("SELECT * EXCEPT (secret) FROM users"); err == nil {
+		t.Error("* EXCEPT parsed without StarExcept")
+	}
+	res, err := sqlparser.ParseWithOptions("SELECT * EXCEPT (secret, token) FROM users; SELECT u.* exclude (secret), 1 FROM users u; SELECT * FROM a EXCEPT SELECT * FROM b", sqlparser.ParseOptions{StarExcept: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := res.Statements[0].(*ast.SelectStmt).Columns[0]; !c.Star || len(c.Except) != 2 || c.Except[1].Unquoted != "token" || c.Expr.Pos() != 7 {
+		t.Errorf("* EXCEPT: %+v", c)
+	}
+	if cols := res.Statements[1].(*ast.SelectStmt).Columns; len(cols) != 2 || len(cols[0].Except) != 1 {
+		t.Errorf("u.* EXCLUDE: %+v", cols)
+	}
+	if s := res.Statements[2].(*ast.SelectStmt); s.SetOp == nil || s.SetOp.Op != ast.Except {
+		t.Errorf("set operation: %+v", s)
+	}
+}
+
+func TestAnalyticsClauses(t *testing.T) {
+	const sql = "SELECT user_id, ts, row_number() OVER (PARTITION BY user_id ORDER BY ts DESC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS rn FROM events e QUALIFY rn <= 3 ORDER BY user_id LIMIT 2 OFFSET 1 BY user_id LIMIT 100"
+	if _, err := sqlparser.ParseStatements(sql); err == nil {
+		t.Error("QUALIFY parsed without AnalyticsClauses")
+	}
+	res, err := sqlparser.ParseWithOptions(sql, sqlparser.ParseOptions{AnalyticsClauses: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sel := res.Statements[0].(*ast.SelectStmt)
+	if alias := sel.From[0].(*ast.SimpleTable).Alias; alias == nil || alias.Unquoted != "e" {
+		t.Errorf("alias: %+v", alias)
+	}
+	over := sel.Columns[2].Expr.(*ast.FuncCall).Over
+	if over == nil || len(over.PartitionBy) != 1 || len(over.OrderBy) != 1 || !over.OrderBy[0].Desc || string(over.Frame) != "ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW" {
+		t.Errorf("OVER: %+v", over)
+	}
+	if sel.Qualify == nil || len(sel.OrderBy) != 1 {
+		t.Errorf("QUALIFY: %+v", sel)
+	}
+	if lb := sel.LimitBy; lb == nil || lb.Count == nil || lb.Offset == nil || len(lb.By) != 1 {
+		t.Errorf("LIMIT BY: %+v", lb)
+	}
+	if sel.Limit == nil || sel.Limit.Count == nil || sel.Limit.Offset != nil {
+		t.Errorf("LIMIT: %+v", sel.Limit)
+	}
+
+	sel = mustParse(t, "SELECT sum(total) OVER w, count(*) OVER () FROM orders").(*ast.SelectStmt)
+	if over := sel.Columns[0].Expr.(*ast.FuncCall).Over; over == nil || over.Name == nil || over.Name.Unquoted != "w" {
+		t.Errorf("OVER w: %+v", over)
+	}
+	if f := sel.Columns[1].Expr.(*ast.FuncCall); !f.Star || f.Over == nil || f.Over.PartitionBy != nil {
+		t.Errorf("count(*) OVER (): %+v", f)
+	}
+}
+
+func TestNestedDataTypes(t *testing.T) {
+	ct := mustParse(t, "CREATE TABLE t (tags TEXT[], grid INT[3][3], ids ARRAY<ARRAY<INT64>>, addr STRUCT<city STRING, zips ARRAY<INT>>, pair Tuple(String, Array(Int32)), s STRUCT(a INT))").(*ast.CreateTableStmt)
+	types := make(map[string]*ast.DataType)
+	for _, c := range ct.Columns {
+		types[c.Name.Unquoted] = c.Type
+	}
+	if dt := types["tags"]; string(dt.Name) != "ARRAY" || dt.Elem == nil || string(dt.Elem.Name) != "TEXT" {
+		t.Errorf("TEXT[]: %+v", dt)
+	}
+	if dt := types["grid"]; dt.Elem == nil || dt.Elem.Elem == nil || string(dt.Elem.Elem.Name) != "INT" {
+		t.Errorf("INT[3][3]: %+v", dt)
+	}
+	if dt := types["ids"]; dt.Elem == nil || dt.Elem.Elem == nil || string(dt.Elem.Elem.Name) != "INT64" {
+		t.Errorf("ARRAY<ARRAY<INT64>>: %+v", dt)
+	}
+	if dt := types["addr"]; len(dt.Fields) != 2 || dt.Fields[0].Name.Unquoted != "city" || dt.Fields[1].Type.Elem == nil {
+		t.Errorf("STRUCT<...>: %+v", dt)
+	}
+	if dt := types["pair"]; len(dt.Fields) != 2 || dt.Fields[0].Name != nil || dt.Fields[1].Type.Elem == nil {
+		t.Errorf("Tuple(...): %+v", dt)
+	}
+	if dt := types["s"]; len(dt.Fields) != 1 || dt.Fields[0].Name.Unquoted != "a" {
+		t.Errorf("STRUCT(...): %+v", dt)
+	}
+	for _, sql := range []string{"CREATE TABLE t (a ARRAY<INT)", "CREATE TABLE t (a INT[)"} {
+		if _, err := sqlparser.ParseStatement(sql); err == nil {
+			t.Errorf("expected an error for %q", sql)
+		}
+	}
+}
+
+func TestSelectPivot(t *testing.T) {
+	sel := mustParse(t, "SELECT * FROM sales s PIVOT (SUM(amount) FOR quarter IN ('Q1' AS q1, 'Q2')) AS p JOIN regions r ON r.id = p.region").(*ast.SelectStmt)
+	join, ok := sel.From[0].(*ast.JoinTable)
+	if !ok {
+		t.Fatalf("FROM: %T", sel.From[0])
+	}
+	pt, ok := join.Left.(*ast.PivotTable)
+	if !ok || pt.Unpivot || pt.Alias.Unquoted != "p" || pt.For.Unquoted != "quarter" || len(pt.In) != 2 || pt.TokPos != 22 {
+		t.Fatalf("PIVOT: %+v", join.Left)
+	}
+	if st := pt.Source.(*ast.SimpleTable); st.Alias == nil || st.Alias.Unquoted != "s" {
+		t.Errorf("source: %+v", st)
+	}
+	if pt.Agg.Name.Parts[0].Unquoted != "sum" || pt.In[0].Alias.Unquoted != "q1" || pt.In[1].Alias != nil {
+		t.Errorf("PIVOT parts: %+v %+v", pt.Agg, pt.In)
+	}
+
+	sel = mustParse(t, "SELECT * FROM q unpivot include nulls (amount FOR quarter IN (q1, q2 AS second)) u").(*ast.SelectStmt)
+	pt, ok = sel.From[0].(*ast.PivotTable)
+	if !ok || !pt.Unpivot || !pt.IncludeNulls || pt.Value.Unquoted != "amount" || pt.In[1].Alias.Unquoted != "second" || pt.Alias.Unquoted != "u" {
+		t.Fatalf("UNPIVOT: %+v", sel.From[0])
+	}
+
+	// Without a parenthesis PIVOT is an alias.
+	sel = mustParse(t, "SELECT pivot.id FROM t pivot").(*ast.SelectStmt)
+	if st := sel.From[0].(*ast.SimpleTable); st.Alias == nil || st.Alias.Unquoted != "pivot" {
+		t.Errorf("pivot alias: %+v", st)
+	}
+	if _, err := sqlparser.ParseStatements("SELECT * FROM t PIVOT (amount FOR q IN (1))"); err == nil {
+		t.Error("PIVOT without an aggregate parsed")
+	}
+}
+
 func TestSelectIn(t *testing.T) {
 	mustParse(t, "SELECT * FROM t WHERE id IN (1, 2, 3)")
 	mustParse(t, "SELECT * FROM t WHERE id NOT IN (SELECT id FROM blacklist)")
@@ -154,6 +469,84 @@ func TestSelectCast(t *testing.T) {
 	mustParse(t, "SELECT CAST(price AS DECIMAL(10,2)) FROM products")
 }
 
+func TestPostgresCastsAndTypeNames(t *testing.T) {
+	sel := mustParse(t, "SELECT -id::text, tags::int[], (a)::numeric(10,2)::text FROM t").(*ast.SelectStmt)
+	neg, ok := sel.Columns[0].Expr.(*ast.UnaryExpr)
+	if !ok {
+		t.Fatalf("-id::text: %T", sel.Columns[0].Expr)
+	}
+	if c, ok := neg.Expr.(*ast.CastExpr); !ok || string(c.Type.Name) != "text" {
+		t.Errorf("-id::text: %+v", neg.Expr)
+	}
+	if c, ok := sel.Columns[1].Expr.(*ast.CastExpr); !ok || c.Type.Elem == nil {
+		t.Errorf("tags::int[]: %+v", sel.Columns[1].Expr)
+	}
+	if c, ok := sel.Columns[2].Expr.(*ast.CastExpr); !ok || string(c.Type.Name) != "text" {
+		t.Errorf("(a)::numeric(10,2)::text: %+v", sel.Columns[2].Expr)
+	} else if inner, ok := c.Expr.(*ast.CastExpr); !ok || inner.Type.Precision != 10 || inner.Type.Scale != 2 {
+		t.Errorf("(a)::numeric(10,2): %+v", c.Expr)
+	}
+
+	ct := mustParse(t, "CREATE TABLE t (a DOUBLE PRECISION, b CHARACTER VARYING(20), c char varying, d NATIONAL CHARACTER(3), "+
+		"e BIT VARYING(8), f TIMESTAMP(3) WITH TIME ZONE, g timestamp without time zone, h TIME WITH TIME ZONE, "+
+		"i INTERVAL DAY TO SECOND(3), j INTERVAL, id UUID, ip INET, mac MACADDR, price MONEY)").(*ast.CreateTableStmt)
+	want := []struct {
+		name      string
+		precision int
+	}{
+		{"DOUBLE", 0}, {"VARCHAR", 20}, {"varchar", 0}, {"NCHAR", 3},
+		{"VARBIT", 8}, {"TIMESTAMPTZ", 3}, {"timestamp", 0}, {"TIMETZ", 0},
+		{"INTERVAL DAY TO SECOND", 3}, {"INTERVAL", 0}, {"UUID", 0}, {"INET", 0}, {"MACADDR", 0}, {"MONEY", 0},
+	}
+	if len(ct.Columns) != len(want) {
+		t.Fatalf("got %d columns", len(ct.Columns))
+	}
+	for i, c := range ct.Columns {
+		if string(c.Type.Name) != want[i].name || c.Type.Precision != want[i].precision {
+			t.Errorf("column %s: got %s(%d), want %s(%d)", c.Name.Unquoted, c.Type.Name, c.Type.Precision, want[i].name, want[i].precision)
+		}
+	}
+	if _, err := sqlparser.ParseStatement("CREATE TABLE t (a TIMESTAMP WITH TIME)"); err == nil {
+		t.Error("expected an error for WITH TIME without ZONE")
+	}
+}
+
+func TestSpatialTypesAndIndexes(t *testing.T) {
+	ct := mustParse(t, "CREATE TABLE places (g GEOMETRY(Point, 4326), geo geography(MultiPolygon), p POINT NOT NULL SRID 4326, "+
+		"shape GEOMETRY, SPATIAL INDEX idx_p (p), FULLTEXT KEY (name))").(*ast.CreateTableStmt)
+	want := []struct {
+		name, subtype string
+		srid          int
+	}{
+		{"GEOMETRY", "Point", 4326}, {"geography", "MultiPolygon", 0}, {"POINT", "", 4326}, {"GEOMETRY", "", 0},
+	}
+	if len(ct.Columns) != len(want) {
+		t.Fatalf("got %d columns", len(ct.Columns))
+	}
+	for i, c := range ct.Columns {
+		if dt := c.Type; string(dt.Name) != want[i].name || string(dt.Subtype) != want[i].subtype || dt.SRID != want[i].srid {
+			t.Errorf("column %s: got %s(%s, %d), want %s(%s, %d)", c.Name.Unquoted, dt.Name, dt.Subtype, dt.SRID, want[i].name, want[i].subtype, want[i].srid)
+		}
+	}
+	if !ct.Columns[2].NotNull {
+		t.Error("p: NOT NULL before SRID was lost")
+	}
+	if len(ct.Constraints) != 2 || ct.Constraints[0].Type != ast.SpatialConstraint || ct.Constraints[0].Name.Unquoted != "idx_p" ||
+		ct.Constraints[1].Type != ast.FulltextConstraint || ct.Constraints[1].Name != nil {
+		t.Errorf("unexpected constraints: %+v", ct.Constraints)
+	}
+
+	for sql, typ := range map[string]ast.ConstraintType{
+		"CREATE SPATIAL INDEX idx_g ON places (g)":     ast.SpatialConstraint,
+		"CREATE FULLTEXT INDEX idx_n ON places (name)": ast.FulltextConstraint,
+	} {
+		ci, ok := mustParse(t, sql).(*ast.CreateIndexStmt)
+		if !ok || ci.Type != typ {
+			t.Errorf("%s: got %+v", sql, ci)
+		}
+	}
+}
+
 func TestSelectMultipleJoins(t *testing.T) {
 	mustParse(t, `
 		SELECT a.id, b.name, c.total
@@ -168,10 +561,50 @@ func TestSelectOffset(t *testing.T) {
 	mustParse(t, "SELECT * FROM t LIMIT 40, 20")
 }
 
+func TestSelectFetchFirst(t *testing.T) {
+	tests := []struct {
+		sql           string
+		count, offset bool
+		fetch, ties   bool
+	}{
+		{"SELECT * FROM t ORDER BY id OFFSET 40 ROWS FETCH NEXT 20 ROWS ONLY", true, true, true, false},
+		{"SELECT * FROM t fetch FIRST ROW ONLY", true, false, true, false},
+		{"SELECT id FROM t ORDER BY score FETCH FIRST 3 ROWS WITH TIES", true, false, true, true},
+		{"SELECT * FROM t LIMIT ALL OFFSET 5", false, true, false, false},
+		{"SELECT * FROM t OFFSET 5", false, true, false, false},
+		{"SELECT * FROM t OFFSET 5 LIMIT 10", true, true, false, false},
+	}
+	for _, tt := range tests {
+		sel := mustParse(t, tt.sql).(*ast.SelectStmt)
+		l := sel.Limit
+		if l == nil || (l.Count != nil) != tt.count || (l.Offset != nil) != tt.offset || l.Fetch != tt.fetch || l.WithTies != tt.ties {
+			t.Errorf("%s: %+v", tt.sql, l)
+		}
+		if _, ok := sel.From[0].(*ast.SimpleTable); !ok || sel.From[0].(*ast.SimpleTable).Alias != nil {
+			t.Errorf("%s: FETCH read as a table alias", tt.sql)
+		}
+	}
+	if one := mustParse(t, "SELECT 1 FETCH FIRST 1 ROW ONLY").(*ast.SelectStmt); one.Columns[0].Alias != nil || one.Limit == nil {
+		t.Errorf("FETCH after a select column: %+v", one)
+	}
+	if alias := mustParse(t, "SELECT * FROM t AS fetch").(*ast.SelectStmt); alias.From[0].(*ast.SimpleTable).Alias == nil {
+		t.Error("explicit alias fetch")
+	}
+	for _, sql := range []string{"SELECT * FROM t FETCH FIRST 5 ONLY", "SELECT * FROM t FETCH NEXT 5 ROWS"} {
+		if _, err := sqlparser.ParseStatement(sql); err == nil {
+			t.Errorf("expected an error for %q", sql)
+		}
+	}
+}
+
 func TestSelectFunctionCalls(t *testing.T) {
 	mustParse(t, `SELECT NOW(), COALESCE(a, b, 0), IFNULL(x, 'default') FROM t`)
 }
 
+func TestSelectTypeNamedFunctions(t *testing.T) {
+	mustParse(t, `SELECT DATE(created_at), YEAR(d), TIME(ts), TIMESTAMP(d, '12:00') FROM t WHERE DATE(created_at) = '2024-01-01'`)
+}
+
 func TestSelectJSONBOperators(t *testing.T) {
 	mustParse(t, `SELECT payload->'user' FROM events`)
 	mustParse(t, `SELECT payload->>'user' FROM events`)
@@ -204,6 +637,41 @@ func TestInsertOnDuplicate(t *testing.T) {
 		ON DUPLICATE KEY UPDATE val = val + 1`)
 }
 
+func TestInsertedRefs(t *testing.T) {
+	dup := mustParse(t, "INSERT INTO t (a, b) VALUES (1, 2) ON DUPLICATE KEY UPDATE a = VALUES(a) + 1, b = new.b").(*ast.InsertStmt)
+	add := dup.OnDupKey[0].Value.(*ast.BinaryExpr)
+	if ref, ok := add.Left.(*ast.InsertedRef); !ok || ref.Form != ast.InsertedValues || ref.Column.Unquoted != "a" {
+		t.Errorf("VALUES(a): %#v", add.Left)
+	}
+	if _, ok := dup.OnDupKey[1].Value.(*ast.QualifiedIdent); !ok {
+		t.Errorf("new.b without a row alias: %T", dup.OnDupKey[1].Value)
+	}
+
+	alias := mustParse(t, "INSERT INTO t (a, b) VALUES (1, 2) AS new (m, n) ON DUPLICATE KEY UPDATE a = new.m, b = n").(*ast.InsertStmt)
+	if alias.RowAlias == nil || alias.RowAlias.Unquoted != "new" || len(alias.RowAliasColumns) != 2 {
+		t.Fatalf("row alias: %+v", alias)
+	}
+	for i, want := range []string{"a", "b"} {
+		ref, ok := alias.OnDupKey[i].Value.(*ast.InsertedRef)
+		if !ok || ref.Form != ast.InsertedAlias || ref.Column.Unquoted != want || ref.Qualifier.Unquoted != "new" {
+			t.Errorf("alias reference %d: %#v", i, alias.OnDupKey[i].Value)
+		}
+	}
+
+	conflict := mustParse(t, "INSERT INTO t (a, b) VALUES (1, 2) ON CONFLICT (a) DO UPDATE SET b = EXCLUDED.b WHERE t.b < excluded.b").(*ast.InsertStmt)
+	if ref, ok := conflict.OnConflictUpdate[0].Value.(*ast.InsertedRef); !ok || ref.Form != ast.InsertedExcluded || ref.Column.Unquoted != "b" {
+		t.Errorf("EXCLUDED.b: %#v", conflict.OnConflictUpdate[0].Value)
+	}
+	if _, ok := conflict.OnConflictUpdateWhere.(*ast.BinaryExpr).Right.(*ast.InsertedRef); !ok {
+		t.Errorf("excluded.b in DO UPDATE WHERE: %#v", conflict.OnConflictUpdateWhere)
+	}
+	if sel := mustParse(t, "SELECT excluded.b FROM excluded").(*ast.SelectStmt); sel.Columns[0].Expr == nil {
+		t.Error("excluded outside an upsert")
+	} else if _, ok := sel.Columns[0].Expr.(*ast.QualifiedIdent); !ok {
+		t.Errorf("excluded.b outside an upsert: %T", sel.Columns[0].Expr)
+	}
+}
+
 func TestInsertOnConflict(t *testing.T) {
 	mustParse(t, `
 		INSERT INTO counters (id, val) VALUES (1, 1)
@@ -211,6 +679,21 @@ func TestInsertOnConflict(t *testing.T) {
 	mustParse(t, `
 		INSERT INTO counters (id, val) VALUES (1, 1)
 		ON CONFLICT DO NOTHING`)
+
+	named := mustParse(t, `INSERT INTO counters (id, val) VALUES (1, 1)
+		ON CONFLICT ON CONSTRAINT counters_pkey DO UPDATE SET val = excluded.val WHERE counters.val < excluded.val`).(*ast.InsertStmt)
+	if named.OnConflictConstraint == nil || named.OnConflictConstraint.Unquoted != "counters_pkey" || named.OnConflictTarget != nil ||
+		len(named.OnConflictUpdate) != 1 || named.OnConflictUpdateWhere == nil {
+		t.Errorf("ON CONSTRAINT: %+v", named)
+	}
+	partial := mustParse(t, `INSERT INTO counters (id, val) VALUES (1, 1)
+		ON CONFLICT (id) WHERE deleted_at IS NULL DO NOTHING`).(*ast.InsertStmt)
+	if len(partial.OnConflictTarget) != 1 || partial.OnConflictWhere == nil || !partial.OnConflictDoNothing {
+		t.Errorf("conflict target WHERE: %+v", partial)
+	}
+	if _, err := sqlparser.ParseStatement("INSERT INTO counters (id) VALUES (1) ON CONFLICT ON CONSTRAINT DO NOTHING"); err == nil {
+		t.Error("expected an error for a missing constraint name")
+	}
 }
 
 func TestInsertWithCTE(t *testing.T) {
@@ -331,6 +814,38 @@ func TestCreateTableForeignKey(t *testing.T) {
 		) ENGINE=InnoDB`)
 }
 
+func TestForeignKeyMatchAndDeferrable(t *testing.T) {
+	tbl := mustParse(t, `CREATE TABLE orders (
+		id INT,
+		user_id INT REFERENCES users (id) MATCH SIMPLE NOT DEFERRABLE NOT NULL,
+		code TEXT,
+		CONSTRAINT fk_user FOREIGN KEY (id, user_id) REFERENCES users (a, b) MATCH FULL ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+		UNIQUE (code) INITIALLY IMMEDIATE DEFERRABLE
+	)`).(*ast.CreateTableStmt)
+	if ref := tbl.Columns[1].References; ref.Match != ast.MatchSimple || ref.Deferrable || !tbl.Columns[1].NotNull {
+		t.Errorf("column reference: %+v", ref)
+	}
+	if fk := tbl.Constraints[0]; fk.Match != ast.MatchFull || fk.OnDelete != ast.Cascade || !fk.Deferrable || !fk.InitiallyDeferred {
+		t.Errorf("foreign key: %+v", fk)
+	}
+	if uq := tbl.Constraints[1]; !uq.Deferrable || uq.InitiallyDeferred {
+		t.Errorf("unique: %+v", uq)
+	}
+	alter := mustParse(t, "ALTER TABLE t ADD CONSTRAINT fk FOREIGN KEY (a) REFERENCES u (id) INITIALLY DEFERRED").(*ast.AlterTableStmt)
+	if c := alter.Cmds[0].(*ast.AddConstraintCmd).Constraint; !c.Deferrable || !c.InitiallyDeferred {
+		t.Errorf("INITIALLY DEFERRED should imply DEFERRABLE: %+v", c)
+	}
+	for _, sql := range []string{
+		"CREATE TABLE t (a INT REFERENCES u (id) MATCH ANY)",
+		"CREATE TABLE t (a INT, FOREIGN KEY (a) REFERENCES u (id) NOT DEFERRABLE INITIALLY DEFERRED)",
+		"CREATE TABLE t (a INT, UNIQUE (a) INITIALLY LATER)",
+	} {
+		if _, err := sqlparser.ParseStatement(sql); err == nil {
+			t.Errorf("expected an error for %s", sql)
+		}
+	}
+}
+
 func TestCreateTableIfNotExists(t *testing.T) {
 	mustParse(t, `CREATE TABLE IF NOT EXISTS config (k VARCHAR(64) PRIMARY KEY, v TEXT)`)
 }
@@ -342,6 +857,59 @@ func TestCreateTableAsSelect(t *testing.T) {
 func TestCreateIndex(t *testing.T) {
 	mustParse(t, "CREATE UNIQUE INDEX idx_email ON users (email)")
 	mustParse(t, "CREATE INDEX idx_multi ON t (a ASC, b DESC, c(10))")
+	if stmt := mustParse(t, "CREATE INDEX CONCURRENTLY idx_a ON t (a)").(*ast.CreateIndexStmt); !stmt.Concurrently || stmt.Name.Unquoted != "idx_a" {
+		t.Errorf("CONCURRENTLY: %+v", stmt)
+	}
+	for _, sql := range []string{"CREATE INDEX concurrently ON t (a)", "CREATE INDEX CONCURRENTLY ON t (a)"} {
+		if stmt := mustParse(t, sql).(*ast.CreateIndexStmt); !stmt.Concurrently || stmt.Name != nil {
+			t.Errorf("%s: %+v", sql, stmt)
+		}
+	}
+	if stmt := mustParse(t, `CREATE INDEX "concurrently" ON t (a)`).(*ast.CreateIndexStmt); stmt.Concurrently || stmt.Name.Unquoted != "concurrently" {
+		t.Errorf("index named concurrently: %+v", stmt)
+	}
+	if stmt := mustParse(t, "CREATE INDEX ON t USING btree (a)").(*ast.CreateIndexStmt); stmt.Name != nil || string(stmt.Method) != "btree" {
+		t.Errorf("unnamed index: %+v", stmt)
+	}
+
+	gin := mustParse(t, "CREATE INDEX CONCURRENTLY docs_body ON docs USING gin (body jsonb_path_ops) WHERE deleted_at IS NULL").(*ast.CreateIndexStmt)
+	if string(gin.Method) != "gin" || string(gin.Columns[0].OpClass) != "jsonb_path_ops" || gin.Where == nil {
+		t.Errorf("partial gin index: %+v", gin)
+	}
+	fn := mustParse(t, "CREATE UNIQUE INDEX users_email ON users ((lower(email)), tenant_id DESC) USING BTREE").(*ast.CreateIndexStmt)
+	if call, ok := fn.Columns[0].Expr.(*ast.FuncCall); !ok || fn.Columns[0].Name != nil || string(call.Name.Parts[0].Raw) != "lower" || !fn.Columns[1].Desc || string(fn.Method) != "BTREE" {
+		t.Errorf("functional index: %+v", fn)
+	}
+	bare := mustParse(t, "CREATE INDEX users_name ON users (lower(name), code(4))").(*ast.CreateIndexStmt)
+	if _, ok := bare.Columns[0].Expr.(*ast.FuncCall); !ok || bare.Columns[1].Name.Unquoted != "code" || *bare.Columns[1].Length != 4 {
+		t.Errorf("call and prefix length: %+v", bare)
+	}
+	if stmt := mustParse(t, "CREATE INDEX h USING HASH ON t (a)").(*ast.CreateIndexStmt); string(stmt.Method) != "HASH" {
+		t.Errorf("USING before ON: %+v", stmt)
+	}
+	nulls := mustParse(t, "CREATE INDEX users_seen ON users (lower(email) DESC NULLS LAST, a NULLS FIRST, b)").(*ast.CreateIndexStmt)
+	if c := nulls.Columns; !c[0].Desc || c[0].NullsFirst == nil || *c[0].NullsFirst || c[1].NullsFirst == nil || !*c[1].NullsFirst || c[2].NullsFirst != nil {
+		t.Errorf("NULLS FIRST/LAST: %+v", nulls)
+	}
+	if _, err := sqlparser.ParseStatement("CREATE INDEX i ON t (a NULLS)"); err == nil {
+		t.Error("NULLS without FIRST or LAST: expected an error")
+	}
+
+	covering := mustParse(t, "CREATE INDEX orders_user ON orders (user_id) INCLUDE (total, status) WITH (fillfactor = 70) WHERE status = 'open'").(*ast.CreateIndexStmt)
+	if len(covering.Include) != 2 || covering.Include[1].Unquoted != "status" || string(covering.StorageParams[0].Key) != "fillfactor" || covering.Where == nil {
+		t.Errorf("covering index: %+v", covering)
+	}
+	// SQL Server puts WITH after WHERE.
+	if stmt := mustParse(t, "CREATE INDEX o ON orders (a) INCLUDE (b) WHERE b > 0 WITH (FILLFACTOR = 80)").(*ast.CreateIndexStmt); stmt.Where == nil || string(stmt.StorageParams[0].Value) != "80" {
+		t.Errorf("WITH after WHERE: %+v", stmt)
+	}
+	my := mustParse(t, "CREATE INDEX t_a ON t (a) USING BTREE KEY_BLOCK_SIZE = 8 COMMENT 'lookup' INVISIBLE").(*ast.CreateIndexStmt)
+	if string(my.Method) != "BTREE" || len(my.Options) != 2 || string(my.Options[0].Key) != "KEY_BLOCK_SIZE" || string(my.Options[1].Value) != "'lookup'" || !my.Invisible {
+		t.Errorf("mysql index options: %+v", my)
+	}
+	if _, err := sqlparser.ParseStatement("CREATE INDEX t_a ON t (a) KEY_BLOCK_SIZE = big"); err == nil {
+		t.Error("expected an error for a non-integer KEY_BLOCK_SIZE")
+	}
 }
 
 func TestCreateView(t *testing.T) {
@@ -350,6 +918,68 @@ func TestCreateView(t *testing.T) {
 		SELECT id, name, email FROM users WHERE active = 1`)
 }
 
+func TestCreateTemporary(t *testing.T) {
+	tbl := mustParse(t, "CREATE TEMPORARY TABLE IF NOT EXISTS scratch (id INT)").(*ast.CreateTableStmt)
+	if !tbl.Temporary || !tbl.IfNotExists {
+		t.Fatalf("expected temporary table, got %+v", tbl)
+	}
+	view := mustParse(t, "CREATE OR REPLACE TEMP VIEW recent AS SELECT id FROM scratch").(*ast.CreateViewStmt)
+	if !view.Temporary || !view.OrReplace {
+		t.Fatalf("expected temporary view, got %+v", view)
+	}
+	if tbl := mustParse(t, "CREATE TABLE temp (id INT)").(*ast.CreateTableStmt); tbl.Temporary || tbl.Table.Parts[0].Unquoted != "temp" {
+		t.Fatalf("temp as a table name was taken for the flag: %+v", tbl)
+	}
+}
+
+func TestSequences(t *testing.T) {
+	create := mustParse(t, "CREATE TEMP SEQUENCE IF NOT EXISTS app.order_seq AS bigint INCREMENT BY -2 MINVALUE -100 NO MAXVALUE START WITH -1 CACHE 10 NO CYCLE OWNED BY app.orders.id").(*ast.CreateSequenceStmt)
+	o := create.Options
+	if !create.Temporary || !create.IfNotExists || string(o.Increment) != "-2" || string(o.MinValue) != "-100" || !o.NoMaxValue ||
+		string(o.Start) != "-1" || string(o.Cache) != "10" || !o.NoCycle || len(o.OwnedBy.Parts) != 3 || string(o.Type.Name) != "bigint" {
+		t.Fatalf("unexpected CREATE SEQUENCE: %+v", create)
+	}
+	alter := mustParse(t, "ALTER SEQUENCE IF EXISTS s RESTART WITH 100 CYCLE OWNED BY NONE").(*ast.AlterSequenceStmt)
+	if !alter.IfExists || !alter.Options.Restart || string(alter.Options.RestartWith) != "100" || !alter.Options.Cycle || !alter.Options.OwnedByNone {
+		t.Fatalf("unexpected ALTER SEQUENCE: %+v", alter)
+	}
+	mustParse(t, "CREATE SEQUENCE s START 5 INCREMENT 1 NOCACHE NOCYCLE")
+	drop := mustParse(t, "DROP SEQUENCE IF EXISTS a, b CASCADE").(*ast.DropSequenceStmt)
+	if !drop.IfExists || !drop.Cascade || len(drop.Names) != 2 {
+		t.Fatalf("unexpected DROP SEQUENCE: %+v", drop)
+	}
+	for _, sql := range []string{"CREATE SEQUENCE s RESTART WITH 1", "CREATE SEQUENCE s INCREMENT BY x", "ALTER SEQUENCE s NO TOUCH"} {
+		if _, err := sqlparser.ParseStatements(sql); err == nil {
+			t.Errorf("%s: expected an error", sql)
+		}
+	}
+}
+
+func TestUserTypes(t *testing.T) {
+	enum := mustParse(t, "CREATE TYPE public.mood AS ENUM ('sad', 'ok', 'happy')").(*ast.CreateTypeStmt)
+	if len(enum.Name.Parts) != 2 || len(enum.EnumVals) != 3 || string(enum.EnumVals[2]) != "'happy'" {
+		t.Fatalf("unexpected CREATE TYPE: %+v", enum)
+	}
+	if _, ok := mustParse(t, "CREATE TYPE pair AS (a int, b text)").(*ast.GenericDDLStmt); !ok {
+		t.Fatal("composite CREATE TYPE should parse as generic DDL")
+	}
+	domain := mustParse(t, "CREATE DOMAIN app.email AS varchar(255) COLLATE \"C\" NOT NULL DEFAULT '' CONSTRAINT has_at CHECK (VALUE LIKE '%@%') CHECK (length(VALUE) > 3)").(*ast.CreateDomainStmt)
+	if string(domain.Type.Name) != "varchar" || domain.Type.Precision != 255 || !domain.NotNull || domain.Default == nil || len(domain.Checks) != 2 {
+		t.Fatalf("unexpected CREATE DOMAIN: %+v", domain)
+	}
+	drop := mustParse(t, "DROP DOMAIN IF EXISTS a, b CASCADE").(*ast.DropTypeStmt)
+	if !drop.Domain || !drop.IfExists || !drop.Cascade || len(drop.Names) != 2 {
+		t.Fatalf("unexpected DROP DOMAIN: %+v", drop)
+	}
+	col := mustParse(t, "CREATE TABLE t (m public.mood NOT NULL)").(*ast.CreateTableStmt).Columns[0]
+	if string(col.Type.Name) != "public.mood" || !col.NotNull {
+		t.Fatalf("unexpected qualified column type: %+v", col.Type)
+	}
+	if _, err := sqlparser.ParseStatements("CREATE TYPE mood AS ENUM ('a' 'b')"); err == nil {
+		t.Error("expected an error for a malformed label list")
+	}
+}
+
 func TestCreateDatabase(t *testing.T) {
 	mustParse(t, "CREATE DATABASE IF NOT EXISTS appdb")
 	mustParse(t, "CREATE SCHEMA analytics")
@@ -360,6 +990,31 @@ func TestAlterTable(t *testing.T) {
 	mustParse(t, "ALTER TABLE users DROP COLUMN phone")
 	mustParse(t, "ALTER TABLE users RENAME TO members")
 	mustParse(t, "ALTER TABLE users ADD INDEX idx_phone (phone)")
+
+	alter := mustParse(t, "ALTER TABLE users ALTER COLUMN id TYPE bigint USING id::bigint, ALTER name SET DATA TYPE text, "+
+		"ALTER COLUMN name SET NOT NULL, ALTER plan DROP NOT NULL, ALTER plan SET DEFAULT 'free', ALTER plan DROP DEFAULT").(*ast.AlterTableStmt)
+	actions := []ast.AlterColumnAction{ast.AlterColumnType, ast.AlterColumnType, ast.AlterColumnSetNotNull,
+		ast.AlterColumnDropNotNull, ast.AlterColumnSetDefault, ast.AlterColumnDropDefault}
+	if len(alter.Cmds) != len(actions) {
+		t.Fatalf("got %d commands, want %d", len(alter.Cmds), len(actions))
+	}
+	for i, cmd := range alter.Cmds {
+		c, ok := cmd.(*ast.AlterColumnCmd)
+		if !ok || c.Action != actions[i] {
+			t.Errorf("command %d: got %#v, want action %d", i, cmd, actions[i])
+		}
+	}
+	if c := alter.Cmds[0].(*ast.AlterColumnCmd); string(c.Type.Name) != "bigint" || c.Using == nil {
+		t.Errorf("ALTER COLUMN TYPE: %+v", c)
+	}
+
+	change := mustParse(t, "ALTER TABLE users CHANGE COLUMN phone mobile VARCHAR(32) NOT NULL AFTER email").(*ast.AlterTableStmt)
+	if c, ok := change.Cmds[0].(*ast.ModifyColumnCmd); !ok || c.OldName.Unquoted != "phone" || c.Col.Name.Unquoted != "mobile" || c.After == nil {
+		t.Errorf("CHANGE COLUMN: %#v", change.Cmds[0])
+	}
+	if _, err := sqlparser.ParseStatement("ALTER TABLE users ALTER COLUMN name RESET"); err == nil {
+		t.Error("expected an error for an unknown ALTER COLUMN action")
+	}
 }
 
 func TestAlterDatabase(t *testing.T) {
@@ -381,6 +1036,17 @@ func TestDropIndex(t *testing.T) {
 
 func TestTruncate(t *testing.T) {
 	mustParse(t, "TRUNCATE TABLE logs")
+
+	multi := mustParse(t, "TRUNCATE a, b RESTART IDENTITY CASCADE").(*ast.TruncateStmt)
+	if len(multi.Tables) != 2 || !multi.RestartIdentity || !multi.Cascade {
+		t.Errorf("TRUNCATE a, b RESTART IDENTITY CASCADE: %+v", multi)
+	}
+	if def := mustParse(t, "TRUNCATE TABLE a CONTINUE IDENTITY RESTRICT").(*ast.TruncateStmt); def.RestartIdentity || def.Cascade {
+		t.Errorf("CONTINUE IDENTITY RESTRICT: %+v", def)
+	}
+	if _, err := sqlparser.ParseStatement("TRUNCATE a RESTART"); err == nil {
+		t.Error("expected an error for RESTART without IDENTITY")
+	}
 }
 
 func TestUse(t *testing.T) {
@@ -390,10 +1056,73 @@ func TestUse(t *testing.T) {
 func TestShow(t *testing.T) {
 	mustParse(t, "SHOW TABLES")
 	mustParse(t, "SHOW TABLES LIKE 'user%'")
+
+	s := mustParse(t, "SHOW FULL TABLES FROM shop LIKE 'o%'").(*ast.ShowStmt)
+	if !s.Full || string(s.What) != "TABLES" || s.Database.Unquoted != "shop" || s.Like == nil {
+		t.Fatalf("unexpected SHOW: %+v", s)
+	}
+	s = mustParse(t, "SHOW CREATE TABLE shop.orders").(*ast.ShowStmt)
+	if string(s.Object) != "TABLE" || len(s.Name.Parts) != 2 || s.Name.Parts[1].Unquoted != "orders" {
+		t.Fatalf("unexpected SHOW CREATE: %+v", s)
+	}
+	s = mustParse(t, "SHOW INDEX FROM orders IN shop WHERE Key_name = 'PRIMARY'").(*ast.ShowStmt)
+	if s.Name.Parts[0].Unquoted != "orders" || s.Database.Unquoted != "shop" || s.Where == nil {
+		t.Fatalf("unexpected SHOW INDEX: %+v", s)
+	}
+	s = mustParse(t, "SHOW GLOBAL VARIABLES LIKE 'max%'").(*ast.ShowStmt)
+	if string(s.Scope) != "GLOBAL" || string(s.What) != "VARIABLES" {
+		t.Fatalf("unexpected SHOW VARIABLES: %+v", s)
+	}
+	mustParse(t, "SHOW search_path")
+	s = mustParse(t, "SHOW TABLE STATUS FROM shop WHERE Rows > 0").(*ast.ShowStmt)
+	if string(s.What) != "TABLE" || string(s.Detail) != "STATUS" || s.Database.Unquoted != "shop" || s.Where == nil {
+		t.Fatalf("unexpected SHOW TABLE STATUS: %+v", s)
+	}
+	s = mustParse(t, "SHOW ENGINE INNODB STATUS").(*ast.ShowStmt)
+	if string(s.What) != "ENGINE" || string(s.Engine) != "INNODB" || string(s.Detail) != "STATUS" {
+		t.Fatalf("unexpected SHOW ENGINE: %+v", s)
+	}
+	for sql, user := range map[string]string{
+		"SHOW GRANTS FOR 'app'@'%'": "'app'@'%'", "SHOW GRANTS FOR app@localhost": "app@localhost", "SHOW GRANTS FOR CURRENT_USER()": "CURRENT_USER()",
+	} {
+		if s := mustParse(t, sql).(*ast.ShowStmt); string(s.User) != user {
+			t.Errorf("%s: user %q, want %q", sql, s.User, user)
+		}
+	}
+	mustParse(t, "SHOW BINARY LOGS")
+	mustParse(t, "SHOW CHARACTER SET LIKE 'utf%'")
+	mustParse(t, "SHOW PROCEDURE STATUS WHERE Db = 'shop'")
+	if _, err := sqlparser.ParseStatements("SHOW COLUMNS orders"); err == nil {
+		t.Fatal("expected an error for SHOW COLUMNS without FROM")
+	}
 }
 
 func TestExplain(t *testing.T) {
 	mustParse(t, "EXPLAIN SELECT * FROM users WHERE id = 1")
+
+	s := mustParse(t, "EXPLAIN (ANALYZE, FORMAT JSON, BUFFERS, COSTS off) SELECT 1").(*ast.ExplainStmt)
+	if !s.Analyze || string(s.Format) != "JSON" || len(s.Options) != 2 || string(s.Options[1].Value) != "off" {
+		t.Fatalf("unexpected options: %+v", s)
+	}
+	s = mustParse(t, "EXPLAIN ANALYZE FORMAT=TREE SELECT 1").(*ast.ExplainStmt)
+	if !s.Analyze || string(s.Format) != "TREE" {
+		t.Fatalf("unexpected options: %+v", s)
+	}
+	s = mustParse(t, "EXPLAIN QUERY PLAN DELETE FROM users").(*ast.ExplainStmt)
+	if _, ok := s.Stmt.(*ast.DeleteStmt); !ok || !s.QueryPlan {
+		t.Fatalf("unexpected statement: %+v", s)
+	}
+	s = mustParse(t, "EXPLAIN (ANALYZE false) SELECT 1").(*ast.ExplainStmt)
+	if s.Analyze {
+		t.Fatal("ANALYZE false set Analyze")
+	}
+	s = mustParse(t, "DESCRIBE app.users email").(*ast.ExplainStmt)
+	if s.Stmt != nil || len(s.Table.Parts) != 2 || s.Table.Parts[1].Unquoted != "users" || s.Column.Unquoted != "email" {
+		t.Fatalf("unexpected DESCRIBE: %+v", s)
+	}
+	for _, sql := range []string{"DESC users", "EXPLAIN `users`", "DESCRIBE SELECT 1"} {
+		mustParse(t, sql)
+	}
 }
 
 func TestCallStatement(t *testing.T) {
@@ -424,6 +1153,153 @@ func TestGenericRoutineDDL(t *testing.T) {
 	if _, ok := stmt.(*ast.GenericDDLStmt); !ok {
 		t.Fatalf("expected *GenericDDLStmt for DROP TRIGGER, got %T", stmt)
 	}
+	s := mustParse(t, "CREATE TRIGGER trg BEFORE INSERT ON t FOR EACH ROW SET NEW.a = 1").(*ast.GenericDDLStmt)
+	if s.Name.Unquoted != "trg" || string(s.Body) != "BEFORE INSERT ON t FOR EACH ROW SET NEW.a = 1" {
+		t.Fatalf("unexpected CREATE TRIGGER: name %v, body %q", s.Name, s.Body)
+	}
+	s = mustParse(t, "DROP FUNCTION IF EXISTS f").(*ast.GenericDDLStmt)
+	if s.Name != nil || string(s.Body) != "IF EXISTS f" {
+		t.Fatalf("unexpected DROP FUNCTION: name %v, body %q", s.Name, s.Body)
+	}
+}
+
+func TestMaintenanceStatements(t *testing.T) {
+	tests := []struct {
+		sql     string
+		kind    ast.MaintenanceKind
+		targets int
+		options int
+	}{
+		{"CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public CASCADE", ast.CreateExtension, 1, 2},
+		{"VACUUM (VERBOSE, PARALLEL 4) users, orders (id, total)", ast.Vacuum, 2, 2},
+		{"VACUUM FULL ANALYZE", ast.Vacuum, 0, 2},
+		{"ANALYZE VERBOSE users (email)", ast.Analyze, 1, 1},
+		{"ANALYZE LOCAL TABLE a, b", ast.AnalyzeTable, 2, 1},
+		{"REINDEX TABLE CONCURRENTLY users", ast.Reindex, 1, 1},
+		{"CLUSTER users USING users_pkey", ast.Cluster, 1, 1},
+		{"OPTIMIZE TABLE t1, t2", ast.OptimizeTable, 2, 0},
+		{"CHECK TABLE t1 FOR UPGRADE QUICK", ast.CheckTable, 1, 2},
+	}
+	for _, tt := range tests {
+		stmt := mustParse(t, tt.sql)
+		m, ok := stmt.(*ast.MaintenanceStmt)
+		if !ok {
+			t.Fatalf("%s: expected *MaintenanceStmt, got %T", tt.sql, stmt)
+		}
+		if m.Kind != tt.kind || len(m.Targets) != tt.targets || len(m.Options) != tt.options {
+			t.Fatalf("%s: got kind=%d targets=%d options=%d", tt.sql, m.Kind, len(m.Targets), len(m.Options))
+		}
+	}
+}
+
+func TestCreateTableStorageClauses(t *testing.T) {
+	ct := mustParse(t, `CREATE TABLE measurement_y2024 (extra TEXT)
+		INHERITS (measurement, public.audit) USING heap
+		WITH (fillfactor = 70, toast.autovacuum_enabled = false, oids) TABLESPACE fast_ssd`).(*ast.CreateTableStmt)
+	if len(ct.Inherits) != 2 || ct.Inherits[1].Parts[0].Unquoted != "public" {
+		t.Fatalf("unexpected INHERITS %+v", ct.Inherits)
+	}
+	if ct.AccessMethod == nil || ct.AccessMethod.Unquoted != "heap" {
+		t.Fatalf("unexpected access method %+v", ct.AccessMethod)
+	}
+	if len(ct.StorageParams) != 3 || string(ct.StorageParams[1].Key) != "toast.autovacuum_enabled" ||
+		string(ct.StorageParams[0].Value) != "70" || ct.StorageParams[2].Value != nil {
+		t.Fatalf("unexpected storage parameters %q", ct.StorageParams)
+	}
+	if ct.Tablespace == nil || ct.Tablespace.Unquoted != "fast_ssd" || len(ct.Options) != 0 {
+		t.Fatalf("TABLESPACE not structured: %+v %q", ct.Tablespace, ct.Options)
+	}
+
+	ct = mustParse(t, "CREATE TABLE t (id INT) ENGINE=InnoDB TABLESPACE=ts1").(*ast.CreateTableStmt)
+	if ct.Tablespace == nil || ct.Tablespace.Unquoted != "ts1" || len(ct.Options) != 1 {
+		t.Fatalf("MySQL TABLESPACE not structured: %+v %q", ct.Tablespace, ct.Options)
+	}
+}
+
+func TestCreateTableOptions(t *testing.T) {
+	ct := mustParse(t, `CREATE TABLE t (id INT) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='x' row_format=dynamic,
+		DEFAULT CHARACTER SET = latin1 COLLATE utf8mb4_bin AUTO_INCREMENT 100 INDEX DIRECTORY '/idx' UNION=(a, b) foo=bar`).(*ast.CreateTableStmt)
+	want := []struct {
+		key, value string
+		typ        ast.TableOptionType
+	}{
+		{"ENGINE", "InnoDB", ast.OptionName},
+		{"CHARSET", "utf8mb4", ast.OptionName},
+		{"COMMENT", "'x'", ast.OptionString},
+		{"ROW_FORMAT", "DYNAMIC", ast.OptionEnum},
+		{"CHARSET", "latin1", ast.OptionName},
+		{"COLLATE", "utf8mb4_bin", ast.OptionName},
+		{"AUTO_INCREMENT", "100", ast.OptionInt},
+		{"INDEX DIRECTORY", "'/idx'", ast.OptionString},
+		{"UNION", "(a, b)", ast.OptionList},
+		{"foo", "bar", ast.OptionRaw},
+	}
+	if len(ct.Options) != len(want) {
+		t.Fatalf("got %d options, want %d: %q", len(ct.Options), len(want), ct.Options)
+	}
+	for i, w := range want {
+		if o := ct.Options[i]; string(o.Key) != w.key || string(o.Value) != w.value || o.Type != w.typ {
+			t.Errorf("option %d: got %s=%s (%d), want %s=%s (%d)", i, o.Key, o.Value, o.Type, w.key, w.value, w.typ)
+		}
+	}
+
+	ct = mustParse(t, "CREATE TABLE t (id INTEGER PRIMARY KEY) STRICT, WITHOUT ROWID").(*ast.CreateTableStmt)
+	if !ct.WithoutRowID || len(ct.Options) != 1 || ct.Options[0].Type != ast.OptionFlag {
+		t.Fatalf("unexpected SQLite options %q without rowid=%v", ct.Options, ct.WithoutRowID)
+	}
+
+	for _, sql := range []string{
+		"CREATE TABLE t (id INT) ROW_FORMAT=SLOW",
+		"CREATE TABLE t (id INT) AUTO_INCREMENT='x'",
+		"CREATE TABLE t (id INT) COMMENT=x",
+		"CREATE TABLE t (id INT) DEFAULT ENGINE=InnoDB",
+		"CREATE TABLE t (id INT) ENGINE=InnoDB,",
+	} {
+		if _, err := sqlparser.ParseStatement(sql); err == nil {
+			t.Errorf("%s: expected an error", sql)
+		}
+	}
+}
+
+func TestSQLiteStatements(t *testing.T) {
+	ct := mustParse(t, "CREATE TABLE kv (id INTEGER PRIMARY KEY AUTOINCREMENT, v TEXT) WITHOUT ROWID").(*ast.CreateTableStmt)
+	if !ct.WithoutRowID || !ct.Columns[0].AutoIncrement || !ct.Columns[0].PrimaryKey {
+		t.Fatalf("unexpected CREATE TABLE %+v / %+v", ct, ct.Columns[0])
+	}
+
+	pragmas := []struct {
+		sql   string
+		name  string
+		value string
+		call  bool
+	}{
+		{"PRAGMA foreign_keys = ON", "foreign_keys", "ON", false},
+		{"PRAGMA main.journal_mode = WAL", "journal_mode", "WAL", false},
+		{"PRAGMA table_info(users)", "table_info", "users", true},
+		{"PRAGMA cache_size = -2000", "cache_size", "", false},
+		{"PRAGMA user_version", "user_version", "", false},
+	}
+	for _, tt := range pragmas {
+		pr, ok := mustParse(t, tt.sql).(*ast.PragmaStmt)
+		if !ok {
+			t.Fatalf("%s: expected *PragmaStmt", tt.sql)
+		}
+		if got := pr.Name.Parts[len(pr.Name.Parts)-1].Unquoted; got != tt.name || pr.Call != tt.call {
+			t.Fatalf("%s: got name %q call %v", tt.sql, got, pr.Call)
+		}
+		if id, ok := pr.Value.(*ast.Ident); tt.value != "" && (!ok || string(id.Raw) != tt.value) {
+			t.Fatalf("%s: unexpected value %#v", tt.sql, pr.Value)
+		}
+	}
+
+	at := mustParse(t, "ATTACH DATABASE 'other.db' AS other").(*ast.AttachStmt)
+	if at.Detach || at.File == nil || at.Schema.Unquoted != "other" {
+		t.Fatalf("unexpected ATTACH %+v", at)
+	}
+	dt := mustParse(t, "DETACH other").(*ast.AttachStmt)
+	if !dt.Detach || dt.File != nil || dt.Schema.Unquoted != "other" {
+		t.Fatalf("unexpected DETACH %+v", dt)
+	}
 }
 
 // ---- Multiple statements ----
@@ -440,6 +1316,384 @@ func TestMultipleStatements(t *testing.T) {
 	}
 }
 
+// ---- Truncated input ----
+
+func TestAllowIncompleteInsert(t *testing.T) {
+	res, err := sqlparser.ParseWithOptions("INSERT INTO logs (msg, level) VALUES ('disk full', 'warn'), ('secr",
+		sqlparser.ParseOptions{AllowIncomplete: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Incomplete || len(res.Statements) != 1 {
+		t.Fatalf("expected 1 incomplete statement, got %d (incomplete=%v)", len(res.Statements), res.Incomplete)
+	}
+	ins, ok := res.Statements[0].(*ast.InsertStmt)
+	if !ok {
+		t.Fatalf("expected *InsertStmt, got %T", res.Statements[0])
+	}
+	if ins.Table == nil || ins.Table.Parts[0].Unquoted != "logs" {
+		t.Fatalf("expected table logs, got %+v", ins.Table)
+	}
+	// The second row is kept with the values read before the cut.
+	if len(ins.Values) != 2 || len(ins.Values[1]) != 1 {
+		t.Fatalf("expected 2 rows with the last one partial, got %d", len(ins.Values))
+	}
+}
+
+func TestAllowIncompleteAfterCompleteStatements(t *testing.T) {
+	res, err := sqlparser.ParseWithOptions("DELETE FROM a WHERE id = 1; UPDATE users SET name = 'x' WHERE id IN (1, 2",
+		sqlparser.ParseOptions{AllowIncomplete: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Incomplete || len(res.Statements) != 2 {
+		t.Fatalf("expected 2 statements with the last incomplete, got %d (incomplete=%v)", len(res.Statements), res.Incomplete)
+	}
+	upd, ok := res.Statements[1].(*ast.UpdateStmt)
+	if !ok || len(upd.Tables) != 1 || upd.Where != nil {
+		t.Fatalf("unexpected partial UPDATE: %#v", res.Statements[1])
+	}
+}
+
+func TestAllowIncompleteUnterminatedString(t *testing.T) {
+	res, err := sqlparser.ParseWithOptions("SELECT * FROM t WHERE name = 'ab",
+		sqlparser.ParseOptions{AllowIncomplete: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Incomplete {
+		t.Fatal("expected unterminated literal to be reported as incomplete")
+	}
+	res, err = sqlparser.ParseWithOptions("SELECT * FROM t WHERE name = 'it''s'", sqlparser.ParseOptions{AllowIncomplete: true})
+	if err != nil || res.Incomplete {
+		t.Fatalf("complete statement reported incomplete: %v", err)
+	}
+}
+
+func TestTruncatedInputStillFailsByDefault(t *testing.T) {
+	if _, err := sqlparser.ParseWithOptions("SELECT a FROM t WHERE", sqlparser.ParseOptions{}); err == nil {
+		t.Fatal("expected error without AllowIncomplete")
+	}
+	// Errors before the end of input are real syntax errors.
+	if _, err := sqlparser.ParseWithOptions("SELECT FROM WHERE t", sqlparser.ParseOptions{AllowIncomplete: true}); err == nil {
+		t.Fatal("expected syntax error")
+	}
+}
+
+// ---- AST lifetime ----
+
+func TestParseStatementResultsAreIndependent(t *testing.T) {
+	first := mustParse(t, "SELECT a FROM users")
+	mustParse(t, "DELETE FROM orders WHERE id = 1")
+	sel := first.(*ast.SelectStmt)
+	if got := sel.From[0].(*ast.SimpleTable).Name.Parts[0].Unquoted; got != "users" {
+		t.Fatalf("earlier AST was overwritten by a later parse: table %q", got)
+	}
+}
+
+func TestMultiSlabASTSurvivesGC(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("SELECT * FROM t WHERE id IN (")
+	for i := 0; i < 5000; i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(strconv.Itoa(i))
+	}
+	sb.WriteString(") AND Name = 'x'")
+	stmt := mustParse(t, sb.String())
+	sb.Reset()
+	for i := 0; i < 3; i++ {
+		runtime.GC()
+		_ = make([]byte, 1<<20)
+	}
+	sel := stmt.(*ast.SelectStmt)
+	and := sel.Where.(*ast.BinaryExpr)
+	in := and.Left.(*ast.InExpr)
+	if len(in.List) != 5000 {
+		t.Fatalf("expected 5000 IN values, got %d", len(in.List))
+	}
+	if lit := in.List[4999].(*ast.Literal); string(lit.Raw) != "4999" {
+		t.Fatalf("IN value corrupted after GC: %q", lit.Raw)
+	}
+	if col := and.Right.(*ast.BinaryExpr).Left.(*ast.Ident); col.Unquoted != "name" {
+		t.Fatalf("identifier corrupted after GC: %q", col.Unquoted)
+	}
+}
+
+func TestSetFormInsertSurvivesGC(t *testing.T) {
+	stmt := mustParse(t, "INSERT INTO t SET a = 1, b = 'x'")
+	churnHeap()
+	ins := stmt.(*ast.InsertStmt)
+	if len(ins.Values) != 1 || len(ins.Values[0]) != 2 {
+		t.Fatalf("expected one row of two values, got %v", ins.Values)
+	}
+	if lit := ins.Values[0][1].(*ast.Literal); string(lit.Raw) != "'x'" {
+		t.Fatalf("SET value corrupted after GC: %q", lit.Raw)
+	}
+}
+
+func TestTypeNamesSurviveGC(t *testing.T) {
+	stmt := mustParse(t, "SELECT CAST(a AS TIMESTAMP WITH TIME ZONE), CAST(b AS interval day to second), CAST(c AS character varying(3)) FROM t")
+	churnHeap()
+	want := []string{"TIMESTAMPTZ", "interval day to second", "varchar"}
+	for i, col := range stmt.(*ast.SelectStmt).Columns {
+		if got := string(col.Expr.(*ast.CastExpr).Type.Name); got != want[i] {
+			t.Errorf("type %d corrupted after GC: got %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+// churnHeap collects garbage and refills the freed small-object spans, so a
+// heap object still referenced only from arena memory gets overwritten.
+func churnHeap() {
+	runtime.GC()
+	runtime.GC()
+	junk := &ast.Literal{Raw: []byte("junk")}
+	keep := make([]any, 0, 8192)
+	for i := 0; i < cap(keep)/2; i++ {
+		b := make([]byte, 8+i%64)
+		for j := range b {
+			b[j] = 0xA5
+		}
+		e := make([]ast.Expr, 1+i%8)
+		for j := range e {
+			e[j] = junk
+		}
+		keep = append(keep, b, e)
+	}
+	runtime.KeepAlive(keep)
+}
+
+func TestArenaLimit(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("SELECT * FROM t WHERE id IN (")
+	for i := 0; i < 20000; i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(strconv.Itoa(i))
+	}
+	sb.WriteString(")")
+	big := sb.String()
+
+	p := sqlparser.NewWithOptions([]byte(big), sqlparser.ParseOptions{MaxArenaBytes: 256 << 10})
+	_, err := p.Next()
+	if !errors.Is(err, sqlparser.ErrArenaLimit) {
+		t.Fatalf("expected ErrArenaLimit, got %v", err)
+	}
+	if st := p.ArenaStats(); st.Bytes > 256<<10 {
+		t.Fatalf("arena grew past the limit: %+v", st)
+	}
+
+	p.Reset([]byte("SELECT a FROM t"))
+	if _, err := p.Next(); err != nil {
+		t.Fatalf("small statement failed after limit error: %v", err)
+	}
+	st := p.ArenaStats()
+	if st.Slabs != 1 || st.Used == 0 || st.HighWater < 128<<10 {
+		t.Fatalf("unexpected stats after reset: %+v", st)
+	}
+}
+
+func TestExpressionDepthLimit(t *testing.T) {
+	deep := "SELECT " + strings.Repeat("(", 100000) + "1" + strings.Repeat(")", 100000)
+	_, err := sqlparser.ParseStatement(deep)
+	if !errors.Is(err, sqlparser.ErrDepthLimit) {
+		t.Fatalf("expected ErrDepthLimit for nested parentheses, got %v", err)
+	}
+	_, err = sqlparser.ParseStatement("SELECT " + strings.Repeat("- ", 100000) + "1")
+	if !errors.Is(err, sqlparser.ErrDepthLimit) {
+		t.Fatalf("expected ErrDepthLimit for unary chain, got %v", err)
+	}
+	sub := strings.Repeat("SELECT * FROM (", 50) + "SELECT 1" + strings.Repeat(") AS t", 50)
+	p := sqlparser.NewWithOptions([]byte(sub), sqlparser.ParseOptions{MaxExpressionDepth: 20})
+	if _, err := p.Next(); !errors.Is(err, sqlparser.ErrDepthLimit) {
+		t.Fatalf("expected ErrDepthLimit for nested subqueries, got %v", err)
+	}
+	_, err = sqlparser.ParseStatement("SELECT * FROM " + strings.Repeat("(", 100000) + "t" + strings.Repeat(")", 100000))
+	if !errors.Is(err, sqlparser.ErrDepthLimit) {
+		t.Fatalf("expected ErrDepthLimit for nested table references, got %v", err)
+	}
+	for _, typ := range []string{"Array(", "Tuple(", "ARRAY<"} {
+		closer := ")"
+		if typ == "ARRAY<" {
+			closer = ">"
+		}
+		_, err = sqlparser.ParseStatement("CREATE TABLE t (a " + strings.Repeat(typ, 100000) + "Int32" + strings.Repeat(closer, 100000) + ")")
+		if !errors.Is(err, sqlparser.ErrDepthLimit) {
+			t.Fatalf("expected ErrDepthLimit for nested %s types, got %v", typ, err)
+		}
+	}
+
+	// Moderate nesting within the limit parses, and the depth is reset per statement.
+	mid := "SELECT " + strings.Repeat("(", 200) + "1" + strings.Repeat(")", 200)
+	if _, err := sqlparser.ParseStatements(mid + "; " + mid); err != nil {
+		t.Fatalf("moderate nesting failed: %v", err)
+	}
+	beyond := "SELECT " + strings.Repeat("(", 2000) + "1" + strings.Repeat(")", 2000)
+	p = sqlparser.NewWithOptions([]byte(beyond), sqlparser.ParseOptions{MaxExpressionDepth: -1})
+	if _, err := p.Next(); err != nil {
+		t.Fatalf("negative depth should disable the check: %v", err)
+	}
+}
+
+func TestStatementLengthLimit(t *testing.T) {
+	opts := sqlparser.ParseOptions{MaxStatementLength: 32}
+	p := sqlparser.NewWithOptions([]byte("SELECT a FROM t; SELECT a, b, c, d, e, f, g FROM t WHERE x = 1"), opts)
+	if _, err := p.Next(); err != nil {
+		t.Fatalf("short statement failed: %v", err)
+	}
+	_, err := p.Next()
+	var pe *sqlparser.ParseError
+	if !errors.As(err, &pe) || !errors.Is(err, sqlparser.ErrStatementTooLong) {
+		t.Fatalf("expected ParseError wrapping ErrStatementTooLong, got %v", err)
+	}
+	if pe.Pos > 17+32 {
+		t.Fatalf("parser read past the limit before failing: pos %d", pe.Pos)
+	}
+
+	// A statement of exactly the limit followed by another one is fine.
+	p = sqlparser.NewWithOptions([]byte("SELECT 1; SELECT 2"), sqlparser.ParseOptions{MaxStatementLength: 8})
+	if stmts, err := p.All(); err != nil || len(stmts) != 2 {
+		t.Fatalf("expected 2 statements, got %d, %v", len(stmts), err)
+	}
+}
+
+func TestParseErrorDetail(t *testing.T) {
+	_, err := sqlparser.ParseStatement("SELECT id\nFROM t\n\tWHERE (a = 1 ORDER BY id")
+	var pe *sqlparser.ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected ParseError, got %v", err)
+	}
+	if pe.Token.Type != lexer.ORDER || string(pe.Token.Raw) != "ORDER" {
+		t.Fatalf("unexpected offending token %v %q", pe.Token.Type, pe.Token.Raw)
+	}
+	if len(pe.Expected) != 1 || pe.Expected[0] != lexer.RPAREN {
+		t.Fatalf("expected RPAREN in expected set, got %v", pe.Expected)
+	}
+	want := "3 | \tWHERE (a = 1 ORDER BY id\n  | \t             ^^^^^"
+	if pe.Snippet != want {
+		t.Fatalf("unexpected snippet:\n%s\nwant:\n%s", pe.Snippet, want)
+	}
+	if !strings.Contains(pe.Detail(), "expected one of: )") {
+		t.Fatalf("detail misses expected tokens:\n%s", pe.Detail())
+	}
+
+	_, err = sqlparser.ParseStatement("SELEC 1")
+	if !errors.As(err, &pe) || !slices.Contains(pe.Expected, lexer.SELECT) {
+		t.Fatalf("expected statement keywords in expected set, got %v", err)
+	}
+	if !strings.Contains(pe.Detail(), "SELECT, WITH, INSERT") {
+		t.Fatalf("keyword tokens should print by name:\n%s", pe.Detail())
+	}
+
+	// The error keeps its own copy of the token after the source is reused.
+	src := []byte("SELECT FROM")
+	p := sqlparser.New(src)
+	_, err = p.Next()
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected ParseError, got %v", err)
+	}
+	copy(src, "XXXXXXXXXXX")
+	if string(pe.Token.Raw) != "FROM" {
+		t.Fatalf("token raw aliases the source: %q", pe.Token.Raw)
+	}
+}
+
+func TestMultipleParseErrors(t *testing.T) {
+	const sql = "SELECT a, FROM t WHERE (x = ORDER BY b LIMIT , 2; SELECT 1"
+	p := sqlparser.NewWithOptions([]byte(sql), sqlparser.ParseOptions{MaxErrors: 5})
+	_, err := p.Next()
+	var list sqlparser.ParseErrors
+	if !errors.As(err, &list) {
+		t.Fatalf("expected ParseErrors, got %T %v", err, err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("expected 3 errors, got %d:\n%s", len(list), list.Detail())
+	}
+	for i, tok := range []lexer.TokenType{lexer.FROM, lexer.ORDER, lexer.COMMA} {
+		if list[i].Token.Type != tok {
+			t.Errorf("error %d: offending token %v, want %v", i, list[i].Token.Type, tok)
+		}
+	}
+	var pe *sqlparser.ParseError
+	if !errors.As(err, &pe) || pe != list[0] {
+		t.Fatalf("errors.As should find the first error")
+	}
+	// Parsing resumes with the next statement.
+	if stmt, err := p.Next(); err != nil || stmt == nil {
+		t.Fatalf("next statement failed: %v", err)
+	}
+
+	// MaxErrors caps the list.
+	_, err = sqlparser.NewWithOptions([]byte(sql), sqlparser.ParseOptions{MaxErrors: 2}).Next()
+	if !errors.As(err, &list) || len(list) != 2 {
+		t.Fatalf("expected 2 errors, got %v", err)
+	}
+
+	// By default the first error is returned on its own.
+	_, err = sqlparser.ParseStatement(sql)
+	if errors.As(err, &list) || !errors.As(err, &pe) || pe.Token.Type != lexer.FROM {
+		t.Fatalf("expected a single ParseError, got %T %v", err, err)
+	}
+}
+
+func TestOptimizerHints(t *testing.T) {
+	stmt, err := sqlparser.ParseStatement("SELECT /*+ INDEX(t idx_a) NO_ICP() BKA */ a FROM t")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	hints := stmt.(*ast.SelectStmt).Hints
+	if len(hints) != 3 {
+		t.Fatalf("expected 3 hints, got %d", len(hints))
+	}
+	if string(hints[0].Name) != "INDEX" || string(hints[0].Args) != "t idx_a" {
+		t.Fatalf("unexpected first hint %s(%s)", hints[0].Name, hints[0].Args)
+	}
+	if hints[1].Args == nil || len(hints[1].Args) != 0 || hints[2].Args != nil {
+		t.Fatalf("empty parentheses and no parentheses should differ: %q %q", hints[1].Args, hints[2].Args)
+	}
+	if hints[0].TokPos != 11 {
+		t.Fatalf("unexpected hint position %d", hints[0].TokPos)
+	}
+
+	// Plain comments are not hints.
+	stmt, _ = sqlparser.ParseStatement("SELECT /* INDEX(t idx) */ a FROM t")
+	if hints := stmt.(*ast.SelectStmt).Hints; hints != nil {
+		t.Fatalf("plain comment read as hints: %v", hints)
+	}
+}
+
+func TestExecuteVersionComments(t *testing.T) {
+	const sql = "/*!40101 SELECT a FROM t */; SELECT b /*!50100 , c */ FROM u"
+	p := sqlparser.NewWithOptions([]byte(sql), sqlparser.ParseOptions{ExecuteVersionComments: true})
+	stmts, err := p.All()
+	if err != nil || len(stmts) != 2 {
+		t.Fatalf("expected 2 statements, got %d, %v", len(stmts), err)
+	}
+	if cols := stmts[1].(*ast.SelectStmt).Columns; len(cols) != 2 {
+		t.Fatalf("version comment body not parsed: %d columns", len(cols))
+	}
+
+	stmts, err = sqlparser.ParseStatements(sql)
+	if err != nil || len(stmts) != 1 || len(stmts[0].(*ast.SelectStmt).Columns) != 1 {
+		t.Fatalf("version comments should be skipped by default, got %d statements, %v", len(stmts), err)
+	}
+}
+
+func TestParseStandardStrings(t *testing.T) {
+	const sql = `SELECT 'C:\dir\' FROM t`
+	p := sqlparser.NewWithOptions([]byte(sql), sqlparser.ParseOptions{StandardStrings: true})
+	stmt, err := p.Next()
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if lit := stmt.(*ast.SelectStmt).Columns[0].Expr.(*ast.Literal); string(lit.Raw) != `'C:\dir\'` {
+		t.Fatalf("got literal %s", lit.Raw)
+	}
+}
+
 // ---- Tokenizer tests ----
 
 func TestTokenize(t *testing.T) {
@@ -569,3 +1823,28 @@ func BenchmarkParseInsert(b *testing.B) {
 		}
 	}
 }
+
+func TestQuotedIdentUnquoting(t *testing.T) {
+	tests := []struct{ src, want string }{
+		{"SELECT Name FROM t", "name"},
+		{`SELECT "Name" FROM t`, "Name"},
+		{`SELECT "say ""hi""" FROM t`, `say "hi"`},
+		{"SELECT `a``b` FROM t", "a`b"},
+		{`SELECT U&"d\0061t\+000061" FROM t`, "data"},
+		{`SELECT u&"caf!00e9" UESCAPE '!' FROM t`, "café"},
+		{`SELECT U&"a\\b" FROM t`, `a\b`},
+	}
+	for _, tt := range tests {
+		sel := mustParse(t, tt.src).(*ast.SelectStmt)
+		id, ok := sel.Columns[0].Expr.(*ast.Ident)
+		if !ok {
+			t.Fatalf("%s: expected *Ident, got %T", tt.src, sel.Columns[0].Expr)
+		}
+		if id.Unquoted != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.src, id.Unquoted, tt.want)
+		}
+		if sel.Columns[0].Alias != nil {
+			t.Errorf("%s: UESCAPE read as an alias", tt.src)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// ParseErrors is returned instead of a single *ParseError when
+// Options.MaxErrors lets the parser report more than one syntax error per
+// statement. errors.As finds the first error.
+type ParseErrors []*ParseError
+
+func (l ParseErrors) Error() string {
+	switch len(l) {
+	case 0:
+		return "no parse errors"
+	case 1:
+		return l[0].Error()
+	}
+	return l[0].Error() + " (and " + strconv.Itoa(len(l)-1) + " more errors)"
+}
+
+// Detail renders every error with its source excerpt, one after another.
+func (l ParseErrors) Detail() string {
+	var b strings.Builder
+	for i, e := range l {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(e.Detail())
+	}
+	return b.String()
+}
+
+// Unwrap exposes the individual errors to errors.Is and errors.As.
+func (l ParseErrors) Unwrap() []error {
+	out := make([]error, len(l))
+	for i, e := range l {
+		out[i] = e
+	}
+	return out
+}
+
+// resync records a syntax error inside a clause and skips to the start of
+// the next clause, so the rest of the statement is still checked. It
+// reports false, leaving err to be returned as usual, when recovery is off,
+// err is a resource-limit failure, or Options.MaxErrors has been reached.
+func (p *Parser) resync(err error) bool {
+	var pe *ParseError
+	if p.opts.MaxErrors <= 1 || !errors.As(err, &pe) || pe.err != nil || len(p.errs)+1 >= p.opts.MaxErrors {
+		return false
+	}
+	p.errs = append(p.errs, pe)
+	depth := 0
+	for {
+		switch p.tok.Type {
+		case lexer.EOF, lexer.SEMICOLON:
+			return true
+		case lexer.LPAREN:
+			depth++
+		case lexer.RPAREN:
+			if depth == 0 {
+				return true
+			}
+			depth--
+		case lexer.FROM, lexer.WHERE, lexer.GROUP, lexer.HAVING, lexer.ORDER, lexer.LIMIT,
+			lexer.SET, lexer.UNION, lexer.INTERSECT, lexer.EXCEPT:
+			if depth == 0 {
+				return true
+			}
+		}
+		p.advance()
+	}
+}
+
+// collectErrors merges the errors recovered while parsing a statement with
+// the final outcome. With no recovered errors err is returned unchanged.
+// Otherwise the statement is discarded, the rest of it is skipped, and all
+// errors are returned together.
+func (p *Parser) collectErrors(err error) error {
+	if len(p.errs) == 0 {
+		return err
+	}
+	list := append(ParseErrors(nil), p.errs...)
+	p.errs = p.errs[:0]
+	var pe *ParseError
+	switch {
+	case errors.As(err, &pe):
+		list = append(list, pe)
+	case err == nil && !p.is(lexer.SEMICOLON) && !p.is(lexer.EOF) && len(list) < p.opts.MaxErrors:
+		list = append(list, p.expectf([]lexer.TokenType{lexer.SEMICOLON}, "unexpected token %q after statement", p.tok.Raw))
+	}
+	for !p.is(lexer.SEMICOLON) && !p.is(lexer.EOF) {
+		p.advance()
+	}
+	return list
+}
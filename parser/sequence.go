@@ -0,0 +1,177 @@
+package parser
+
+import (
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// parseCreateSequence parses CREATE [TEMPORARY] SEQUENCE [IF NOT EXISTS]
+// name [options].
+func (p *Parser) parseCreateSequence(temporary bool) (*ast.CreateSequenceStmt, error) {
+	pos := p.tok.Pos
+	p.advance() // SEQUENCE
+	stmt := arenaNode(&p.arena, ast.CreateSequenceStmt{Temporary: temporary, TokPos: pos})
+	p.track(stmt)
+	if p.is(lexer.IF) {
+		p.advance()
+		if !p.tryEatKeyword(lexer.NOT) || !p.tryEatKeyword(lexer.EXISTS) {
+			return nil, p.errorf("expected IF NOT EXISTS")
+		}
+		stmt.IfNotExists = true
+	}
+	name, err := p.parseQualifiedIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = name
+	return stmt, p.parseSequenceOptions(&stmt.Options, false)
+}
+
+// parseAlterSequence parses ALTER SEQUENCE [IF EXISTS] name options.
+func (p *Parser) parseAlterSequence(pos int32) (*ast.AlterSequenceStmt, error) {
+	p.advance() // SEQUENCE
+	stmt := arenaNode(&p.arena, ast.AlterSequenceStmt{TokPos: pos})
+	p.track(stmt)
+	if p.is(lexer.IF) {
+		p.advance()
+		if !p.tryEatKeyword(lexer.EXISTS) {
+			return nil, p.expectf([]lexer.TokenType{lexer.EXISTS}, "expected EXISTS in IF EXISTS")
+		}
+		stmt.IfExists = true
+	}
+	name, err := p.parseQualifiedIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = name
+	return stmt, p.parseSequenceOptions(&stmt.Options, true)
+}
+
+// parseDropSequence parses DROP SEQUENCE [IF EXISTS] name [, ...]
+// [CASCADE | RESTRICT].
+func (p *Parser) parseDropSequence() (*ast.DropSequenceStmt, error) {
+	pos := p.tok.Pos
+	p.advance() // SEQUENCE
+	stmt := arenaNode(&p.arena, ast.DropSequenceStmt{TokPos: pos})
+	p.track(stmt)
+	if p.is(lexer.IF) {
+		p.advance()
+		if !p.tryEatKeyword(lexer.EXISTS) {
+			return nil, p.expectf([]lexer.TokenType{lexer.EXISTS}, "expected EXISTS in IF EXISTS")
+		}
+		stmt.IfExists = true
+	}
+	for {
+		name, err := p.parseQualifiedIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Names = arenaAppend(&p.arena, stmt.Names, name)
+		if !p.tryEat(lexer.COMMA) {
+			break
+		}
+	}
+	stmt.Cascade = p.tryEatKeyword(lexer.CASCADE)
+	if !stmt.Cascade {
+		p.tryEatKeyword(lexer.RESTRICT)
+	}
+	return stmt, nil
+}
+
+// parseSequenceOptions parses the PostgreSQL sequence clauses, in any
+// order, plus MariaDB's NOCACHE, NOCYCLE, NOMINVALUE and NOMAXVALUE
+// spellings. RESTART is only accepted by ALTER SEQUENCE.
+func (p *Parser) parseSequenceOptions(o *ast.SequenceOptions, alter bool) error {
+	for {
+		var err error
+		switch {
+		case p.is(lexer.AS):
+			p.advance()
+			o.Type, err = p.parseDataType()
+		case p.is(lexer.NO):
+			p.advance()
+			switch {
+			case p.isWord("minvalue"):
+				o.NoMinValue = true
+			case p.isWord("maxvalue"):
+				o.NoMaxValue = true
+			case p.isWord("cycle"):
+				o.NoCycle = true
+			case p.isWord("cache"):
+				// NO CACHE is MariaDB's CACHE 1.
+			default:
+				return p.errorf("expected MINVALUE, MAXVALUE, CYCLE or CACHE after NO, got %q", p.tok.Raw)
+			}
+			p.advance()
+		case p.isWord("increment"):
+			p.advance()
+			p.tryEatKeyword(lexer.BY)
+			o.Increment, err = p.parseSequenceNumber("INCREMENT")
+		case p.isWord("minvalue"):
+			p.advance()
+			o.MinValue, err = p.parseSequenceNumber("MINVALUE")
+		case p.isWord("maxvalue"):
+			p.advance()
+			o.MaxValue, err = p.parseSequenceNumber("MAXVALUE")
+		case p.isWord("start"):
+			p.advance()
+			p.tryEatKeyword(lexer.WITH)
+			o.Start, err = p.parseSequenceNumber("START")
+		case p.isWord("cache"):
+			p.advance()
+			o.Cache, err = p.parseSequenceNumber("CACHE")
+		case p.isWord("cycle"):
+			p.advance()
+			o.Cycle = true
+		case p.isWord("nocycle"):
+			p.advance()
+			o.NoCycle = true
+		case p.isWord("nominvalue"):
+			p.advance()
+			o.NoMinValue = true
+		case p.isWord("nomaxvalue"):
+			p.advance()
+			o.NoMaxValue = true
+		case p.isWord("nocache"):
+			p.advance()
+		case alter && p.isWord("restart"):
+			p.advance()
+			o.Restart = true
+			if p.tryEatKeyword(lexer.WITH) || p.is(lexer.INT) || p.is(lexer.MINUS) {
+				o.RestartWith, err = p.parseSequenceNumber("RESTART")
+			}
+		case p.isWord("owned"):
+			p.advance()
+			if !p.tryEatKeyword(lexer.BY) {
+				return p.expectf([]lexer.TokenType{lexer.BY}, "expected BY after OWNED")
+			}
+			if p.isWord("none") {
+				p.advance()
+				o.OwnedByNone = true
+				break
+			}
+			o.OwnedBy, err = p.parseQualifiedIdent()
+		default:
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (p *Parser) isWord(word string) bool {
+	return p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, word)
+}
+
+// parseSequenceNumber parses an optionally negative integer and returns
+// its source text.
+func (p *Parser) parseSequenceNumber(clause string) ([]byte, error) {
+	start := p.tok.Pos
+	p.tryEat(lexer.MINUS)
+	if !p.is(lexer.INT) {
+		return nil, p.expectf([]lexer.TokenType{lexer.INT}, "%s expects an integer, got %q", clause, p.tok.Raw)
+	}
+	end := p.advance()
+	return p.lex.Source()[start : end.Pos+int32(len(end.Raw))], nil
+}
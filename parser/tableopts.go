@@ -0,0 +1,266 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// tableOptionSpec is one option of the CREATE TABLE option grammar.
+type tableOptionSpec struct {
+	// name is the canonical spelling.
+	name string
+	key  []byte
+	typ  ast.TableOptionType
+	// enum lists the accepted values of an OptionEnum, in canonical case.
+	enum []string
+	// orDefault also accepts the word DEFAULT as the value.
+	orDefault bool
+}
+
+func optionSpec(name string, typ ast.TableOptionType, enum ...string) tableOptionSpec {
+	return tableOptionSpec{name: name, key: []byte(name), typ: typ, enum: enum}
+}
+
+// The options spelled with more than one word are matched separately.
+var (
+	charsetSpec  = tableOptionSpec{name: "CHARSET", key: []byte("CHARSET"), typ: ast.OptionName, orDefault: true}
+	collateSpec  = tableOptionSpec{name: "COLLATE", key: []byte("COLLATE"), typ: ast.OptionName, orDefault: true}
+	dataDirSpec  = optionSpec("DATA DIRECTORY", ast.OptionString)
+	indexDirSpec = optionSpec("INDEX DIRECTORY", ast.OptionString)
+)
+
+// tableOptionSpecs are the single-word MySQL table options, plus SQLite's
+// STRICT.
+var tableOptionSpecs = []tableOptionSpec{
+	optionSpec("AUTO_INCREMENT", ast.OptionInt),
+	optionSpec("AVG_ROW_LENGTH", ast.OptionInt),
+	optionSpec("CHECKSUM", ast.OptionEnum, "0", "1"),
+	optionSpec("COMMENT", ast.OptionString),
+	optionSpec("COMPRESSION", ast.OptionString),
+	optionSpec("CONNECTION", ast.OptionString),
+	optionSpec("DELAY_KEY_WRITE", ast.OptionEnum, "0", "1"),
+	optionSpec("ENCRYPTION", ast.OptionString),
+	optionSpec("ENGINE", ast.OptionName),
+	optionSpec("ENGINE_ATTRIBUTE", ast.OptionString),
+	optionSpec("INSERT_METHOD", ast.OptionEnum, "NO", "FIRST", "LAST"),
+	optionSpec("KEY_BLOCK_SIZE", ast.OptionInt),
+	optionSpec("MAX_ROWS", ast.OptionInt),
+	optionSpec("MIN_ROWS", ast.OptionInt),
+	optionSpec("PACK_KEYS", ast.OptionEnum, "0", "1", "DEFAULT"),
+	optionSpec("PASSWORD", ast.OptionString),
+	optionSpec("ROW_FORMAT", ast.OptionEnum, "DEFAULT", "DYNAMIC", "FIXED", "COMPRESSED", "REDUNDANT", "COMPACT"),
+	optionSpec("SECONDARY_ENGINE", ast.OptionName),
+	optionSpec("SECONDARY_ENGINE_ATTRIBUTE", ast.OptionString),
+	optionSpec("STATS_AUTO_RECALC", ast.OptionEnum, "0", "1", "DEFAULT"),
+	optionSpec("STATS_PERSISTENT", ast.OptionEnum, "0", "1", "DEFAULT"),
+	{name: "STATS_SAMPLE_PAGES", key: []byte("STATS_SAMPLE_PAGES"), typ: ast.OptionInt, orDefault: true},
+	optionSpec("STRICT", ast.OptionFlag),
+	optionSpec("UNION", ast.OptionList),
+}
+
+// indexOptionSpecs are MySQL's CREATE INDEX options other than USING and
+// VISIBLE / INVISIBLE.
+var indexOptionSpecs = []tableOptionSpec{
+	optionSpec("COMMENT", ast.OptionString),
+	optionSpec("ENGINE_ATTRIBUTE", ast.OptionString),
+	optionSpec("KEY_BLOCK_SIZE", ast.OptionInt),
+	optionSpec("SECONDARY_ENGINE_ATTRIBUTE", ast.OptionString),
+}
+
+// enumValues holds the canonical bytes of every enum value, so OptionEnum
+// values can be upper-cased without allocating.
+var enumValues = func() map[string][]byte {
+	m := map[string][]byte{}
+	for _, s := range tableOptionSpecs {
+		for _, v := range s.enum {
+			m[v] = []byte(v)
+		}
+	}
+	return m
+}()
+
+// parseTableOptions parses the options after a CREATE TABLE column list:
+// MySQL's ENGINE=..., DEFAULT CHARSET=..., COMMENT '...' and friends,
+// optionally separated by commas and with optional '=', and SQLite's
+// STRICT and WITHOUT ROWID. Known options are validated against their
+// value type. An unknown word followed by '=' is kept as an OptionRaw
+// pair; any other token ends the list.
+func (p *Parser) parseTableOptions(stmt *ast.CreateTableStmt) error {
+	for n := 0; ; n++ {
+		comma := n > 0 && p.tryEat(lexer.COMMA)
+		if !isTableOptionStart(p.tok, p.peekToken()) {
+			if comma {
+				return p.errorf("expected a table option after ',', got %q", p.tok.Raw)
+			}
+			return nil
+		}
+		switch {
+		case p.is(lexer.WITHOUT):
+			p.advance()
+			p.advance() // ROWID
+			stmt.WithoutRowID = true
+			continue
+		case p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "tablespace"):
+			p.advance()
+			p.tryEat(lexer.EQ)
+			ts, err := p.parseIdent()
+			if err != nil {
+				return err
+			}
+			stmt.Tablespace = ts
+			continue
+		}
+		spec, err := p.parseTableOptionName()
+		if err != nil {
+			return err
+		}
+		if spec == nil {
+			key := p.advance().Raw
+			p.advance() // =
+			val := p.advance().Raw
+			stmt.Options = arenaAppend(&p.arena, stmt.Options, ast.TableOption{Key: key, Value: val})
+			continue
+		}
+		opt, err := p.parseTableOptionValue(spec)
+		if err != nil {
+			return err
+		}
+		stmt.Options = arenaAppend(&p.arena, stmt.Options, opt)
+	}
+}
+
+// isTableOptionStart reports whether tok, followed by next, can begin a
+// table option.
+func isTableOptionStart(tok, next lexer.Token) bool {
+	switch tok.Type {
+	case lexer.ENGINE, lexer.COMMENT_KW, lexer.AUTO_INCREMENT, lexer.DEFAULT, lexer.CHARACTER, lexer.COLLATE, lexer.UNION:
+		// UNION only as an option when it has a value; otherwise it starts
+		// nothing a CREATE TABLE accepts here.
+		return tok.Type != lexer.UNION || next.Type == lexer.EQ || next.Type == lexer.LPAREN
+	case lexer.INDEX:
+		return equalASCIIFold(next.Raw, "directory")
+	case lexer.WITHOUT:
+		return equalASCIIFold(next.Raw, "rowid")
+	case lexer.IDENT:
+		if next.Type == lexer.EQ || equalASCIIFold(tok.Raw, "tablespace") || equalASCIIFold(tok.Raw, "charset") {
+			return true
+		}
+		if equalASCIIFold(tok.Raw, "data") {
+			return equalASCIIFold(next.Raw, "directory")
+		}
+		return lookupTableOption(tok.Raw) != nil
+	}
+	return false
+}
+
+func lookupTableOption(word []byte) *tableOptionSpec {
+	return lookupOption(tableOptionSpecs, word)
+}
+
+func lookupOption(specs []tableOptionSpec, word []byte) *tableOptionSpec {
+	for i := range specs {
+		if strings.EqualFold(bytesToString(word), specs[i].name) {
+			return &specs[i]
+		}
+	}
+	return nil
+}
+
+// parseTableOptionName consumes the name of a known option and returns its
+// spec, or returns nil without consuming anything for an unknown word.
+func (p *Parser) parseTableOptionName() (*tableOptionSpec, error) {
+	isDefault := p.tryEatKeyword(lexer.DEFAULT)
+	switch {
+	case p.is(lexer.CHARACTER):
+		p.advance()
+		if !p.tryEatKeyword(lexer.SET) {
+			return nil, p.expectf([]lexer.TokenType{lexer.SET}, "expected SET after CHARACTER")
+		}
+		return &charsetSpec, nil
+	case p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "charset"):
+		p.advance()
+		return &charsetSpec, nil
+	case p.is(lexer.COLLATE):
+		p.advance()
+		return &collateSpec, nil
+	case isDefault:
+		return nil, p.errorf("expected CHARACTER SET, CHARSET or COLLATE after DEFAULT, got %q", p.tok.Raw)
+	case p.is(lexer.INDEX), p.is(lexer.IDENT) && equalASCIIFold(p.tok.Raw, "data"):
+		first := p.advance()
+		p.advance() // DIRECTORY
+		if first.Type == lexer.INDEX {
+			return &indexDirSpec, nil
+		}
+		return &dataDirSpec, nil
+	}
+	spec := lookupTableOption(p.tok.Raw)
+	if spec != nil {
+		p.advance()
+	}
+	return spec, nil
+}
+
+// parseTableOptionValue parses the optional '=' and the value of a known
+// option, checking it against the option's type.
+func (p *Parser) parseTableOptionValue(spec *tableOptionSpec) (ast.TableOption, error) {
+	opt := ast.TableOption{Key: spec.key, Type: spec.typ}
+	if spec.typ == ast.OptionFlag {
+		return opt, nil
+	}
+	p.tryEat(lexer.EQ)
+	if spec.orDefault && p.is(lexer.DEFAULT) {
+		opt.Value = p.advance().Raw
+		return opt, nil
+	}
+	switch spec.typ {
+	case ast.OptionName:
+		if p.is(lexer.STRING) || p.is(lexer.BACKTICK) || p.is(lexer.DQUOTE) || isWordToken(p.tok) {
+			opt.Value = p.advance().Raw
+			return opt, nil
+		}
+		return opt, p.errorf("%s expects a name, got %q", spec.name, p.tok.Raw)
+	case ast.OptionString:
+		if p.is(lexer.STRING) {
+			opt.Value = p.advance().Raw
+			return opt, nil
+		}
+		return opt, p.expectf([]lexer.TokenType{lexer.STRING}, "%s expects a string literal, got %q", spec.name, p.tok.Raw)
+	case ast.OptionInt:
+		if p.is(lexer.INT) {
+			opt.Value = p.advance().Raw
+			return opt, nil
+		}
+		return opt, p.expectf([]lexer.TokenType{lexer.INT}, "%s expects an integer, got %q", spec.name, p.tok.Raw)
+	case ast.OptionEnum:
+		for _, v := range spec.enum {
+			if strings.EqualFold(bytesToString(p.tok.Raw), v) {
+				p.advance()
+				opt.Value = enumValues[v]
+				return opt, nil
+			}
+		}
+		return opt, p.errorf("invalid %s value %q; expected one of %s", spec.name, p.tok.Raw, strings.Join(spec.enum, ", "))
+	case ast.OptionList:
+		start := p.tok.Pos
+		if _, err := p.eat(lexer.LPAREN); err != nil {
+			return opt, err
+		}
+		for {
+			if _, err := p.parseQualifiedIdent(); err != nil {
+				return opt, err
+			}
+			if !p.tryEat(lexer.COMMA) {
+				break
+			}
+		}
+		end, err := p.eat(lexer.RPAREN)
+		if err != nil {
+			return opt, err
+		}
+		opt.Value = p.lex.Source()[start : end.Pos+1]
+		return opt, nil
+	}
+	return opt, nil
+}
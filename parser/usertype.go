@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// parseCreateType parses CREATE TYPE name AS ENUM ('label', ...). Composite,
+// range and base types are kept as a GenericDDLStmt.
+func (p *Parser) parseCreateType() (ast.Statement, error) {
+	pos := p.tok.Pos
+	obj := p.advance().Raw // TYPE
+	name, err := p.parseQualifiedIdent()
+	if err != nil {
+		return nil, err
+	}
+	if !p.is(lexer.AS) || p.peekToken().Type != lexer.ENUM {
+		for !p.is(lexer.SEMICOLON) && !p.is(lexer.EOF) {
+			p.advance()
+		}
+		return arenaNode(&p.arena, ast.GenericDDLStmt{Verb: verbCreate, Object: obj, Name: name.Parts[len(name.Parts)-1], TokPos: pos}), nil
+	}
+	p.advance() // AS
+	p.advance() // ENUM
+	stmt := arenaNode(&p.arena, ast.CreateTypeStmt{Name: name, TokPos: pos})
+	p.track(stmt)
+	if _, err := p.eat(lexer.LPAREN); err != nil {
+		return nil, err
+	}
+	for p.is(lexer.STRING) {
+		stmt.EnumVals = arenaAppend(&p.arena, stmt.EnumVals, p.advance().Raw)
+		if !p.tryEat(lexer.COMMA) {
+			break
+		}
+	}
+	if _, err := p.eat(lexer.RPAREN); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseCreateDomain parses CREATE DOMAIN name [AS] type followed by any of
+// DEFAULT expr, [NOT] NULL, COLLATE collation and [CONSTRAINT name]
+// CHECK (expr).
+func (p *Parser) parseCreateDomain() (*ast.CreateDomainStmt, error) {
+	pos := p.tok.Pos
+	p.advance() // DOMAIN
+	stmt := arenaNode(&p.arena, ast.CreateDomainStmt{TokPos: pos})
+	p.track(stmt)
+	name, err := p.parseQualifiedIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = name
+	p.tryEatKeyword(lexer.AS)
+	if stmt.Type, err = p.parseDataType(); err != nil {
+		return nil, err
+	}
+	for {
+		switch p.tok.Type {
+		case lexer.DEFAULT:
+			p.advance()
+			if stmt.Default, err = p.parseExpr(0); err != nil {
+				return nil, err
+			}
+		case lexer.NOT:
+			p.advance()
+			if _, err := p.eat(lexer.NULL_KW); err != nil {
+				return nil, err
+			}
+			stmt.NotNull = true
+		case lexer.NULL_KW:
+			p.advance()
+		case lexer.COLLATE:
+			p.advance()
+			p.advance() // collation name
+		case lexer.CONSTRAINT:
+			p.advance()
+			if _, err := p.parseIdent(); err != nil {
+				return nil, err
+			}
+		case lexer.CHECK:
+			p.advance()
+			if _, err := p.eat(lexer.LPAREN); err != nil {
+				return nil, err
+			}
+			check, err := p.parseExpr(0)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.eat(lexer.RPAREN); err != nil {
+				return nil, err
+			}
+			stmt.Checks = arenaAppend(&p.arena, stmt.Checks, check)
+		default:
+			return stmt, nil
+		}
+	}
+}
+
+// parseDropType parses DROP TYPE|DOMAIN [IF EXISTS] name [, ...]
+// [CASCADE | RESTRICT].
+func (p *Parser) parseDropType(domain bool) (*ast.DropTypeStmt, error) {
+	pos := p.tok.Pos
+	p.advance() // TYPE|DOMAIN
+	stmt := arenaNode(&p.arena, ast.DropTypeStmt{Domain: domain, TokPos: pos})
+	p.track(stmt)
+	if p.is(lexer.IF) {
+		p.advance()
+		if !p.tryEatKeyword(lexer.EXISTS) {
+			return nil, p.expectf([]lexer.TokenType{lexer.EXISTS}, "expected EXISTS in IF EXISTS")
+		}
+		stmt.IfExists = true
+	}
+	for {
+		name, err := p.parseQualifiedIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Names = arenaAppend(&p.arena, stmt.Names, name)
+		if !p.tryEat(lexer.COMMA) {
+			break
+		}
+	}
+	stmt.Cascade = p.tryEatKeyword(lexer.CASCADE)
+	if !stmt.Cascade {
+		p.tryEatKeyword(lexer.RESTRICT)
+	}
+	return stmt, nil
+}
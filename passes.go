@@ -0,0 +1,177 @@
+package sqlparser
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// PassFactory builds a pass from the options of a pipeline definition,
+// such as those read from a configuration file. It should reject options
+// it does not know.
+type PassFactory func(opts map[string]string) (Pass, error)
+
+var (
+	passesMu sync.RWMutex
+	passes   = map[string]PassFactory{}
+)
+
+func init() {
+	RegisterPass("analyze", func(opts map[string]string) (Pass, error) {
+		if err := knownPassOptions(opts); err != nil {
+			return nil, err
+		}
+		return AnalyzePass(), nil
+	})
+	RegisterPass("coalesce-inserts", func(opts map[string]string) (Pass, error) {
+		if err := knownPassOptions(opts, "max_rows"); err != nil {
+			return nil, err
+		}
+		maxRows := 0
+		if v, ok := opts["max_rows"]; ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("max_rows: %w", err)
+			}
+			maxRows = n
+		}
+		return CoalesceInsertsPass(maxRows), nil
+	})
+	RegisterPass("eliminate-dead-code", func(opts map[string]string) (Pass, error) {
+		if err := knownPassOptions(opts); err != nil {
+			return nil, err
+		}
+		return EliminateDeadCodePass(), nil
+	})
+	RegisterPass("explicit-joins", func(opts map[string]string) (Pass, error) {
+		if err := knownPassOptions(opts); err != nil {
+			return nil, err
+		}
+		return ExplicitJoinsPass(), nil
+	})
+	RegisterPass("fold-constants", func(opts map[string]string) (Pass, error) {
+		if err := knownPassOptions(opts); err != nil {
+			return nil, err
+		}
+		return FoldConstantsPass(), nil
+	})
+	RegisterPass("inline-ctes", func(opts map[string]string) (Pass, error) {
+		if err := knownPassOptions(opts); err != nil {
+			return nil, err
+		}
+		return InlineCTEsPass(), nil
+	})
+	RegisterPass("flatten-subqueries", func(opts map[string]string) (Pass, error) {
+		if err := knownPassOptions(opts); err != nil {
+			return nil, err
+		}
+		return FlattenSubqueriesPass(), nil
+	})
+	RegisterPass("join-in-lists", func(opts map[string]string) (Pass, error) {
+		if err := knownPassOptions(opts, "min_size"); err != nil {
+			return nil, err
+		}
+		minSize := largeInList
+		if v, ok := opts["min_size"]; ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("min_size: %w", err)
+			}
+			minSize = n
+		}
+		return JoinInListsPass(minSize), nil
+	})
+	RegisterPass("normalize", func(opts map[string]string) (Pass, error) {
+		if err := knownPassOptions(opts, "expand_between"); err != nil {
+			return nil, err
+		}
+		var n NormalizeOptions
+		if v, ok := opts["expand_between"]; ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("expand_between: %w", err)
+			}
+			n.ExpandBetween = b
+		}
+		return NormalizePass(n), nil
+	})
+	RegisterPass("or-to-union", func(opts map[string]string) (Pass, error) {
+		if err := knownPassOptions(opts); err != nil {
+			return nil, err
+		}
+		return OrToUnionPass(), nil
+	})
+	RegisterPass("push-down-predicates", func(opts map[string]string) (Pass, error) {
+		if err := knownPassOptions(opts); err != nil {
+			return nil, err
+		}
+		return PushDownPredicatesPass(), nil
+	})
+}
+
+// RegisterPass makes a pass available by name to LookupPass. Modules that
+// ship analyzers or rewriters call it from an init function, so importing
+// the module, or opening it as a Go plugin, is enough to make its passes
+// available to pipelines built from configuration:
+//
+//	func init() {
+//		sqlparser.RegisterPass("acme/no-drop", newNoDropPass)
+//	}
+//
+// Names should be prefixed by the module's own name to avoid collisions.
+// RegisterPass panics if name is empty, factory is nil or name is already
+// registered.
+func RegisterPass(name string, factory PassFactory) {
+	if name == "" || factory == nil {
+		panic("sqlparser: RegisterPass needs a name and a factory")
+	}
+	passesMu.Lock()
+	defer passesMu.Unlock()
+	if _, dup := passes[name]; dup {
+		panic("sqlparser: RegisterPass called twice for pass " + name)
+	}
+	passes[name] = factory
+}
+
+// LookupPass builds the registered pass name with opts. The built-in
+// passes are "analyze", "fold-constants", "eliminate-dead-code",
+// "inline-ctes", "flatten-subqueries", "push-down-predicates",
+// "or-to-union" and "explicit-joins", which take no options, "coalesce-inserts", which
+// takes max_rows, "join-in-lists", which takes min_size (1000 by default),
+// and "normalize", which takes expand_between (false by default).
+func LookupPass(name string, opts map[string]string) (Pass, error) {
+	passesMu.RLock()
+	factory, ok := passes[name]
+	passesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("sqlparser: unknown pass %q", name)
+	}
+	p, err := factory(opts)
+	if err != nil {
+		return nil, fmt.Errorf("sqlparser: pass %s: %w", name, err)
+	}
+	return p, nil
+}
+
+// RegisteredPasses returns the names of the registered passes, sorted.
+func RegisteredPasses() []string {
+	passesMu.RLock()
+	defer passesMu.RUnlock()
+	names := make([]string, 0, len(passes))
+	for name := range passes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// knownPassOptions rejects options outside known.
+func knownPassOptions(opts map[string]string, known ...string) error {
+	for k := range opts {
+		if !slices.Contains(known, k) {
+			return fmt.Errorf("unknown option %q", k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package sqlparser_test
+
+import (
+	"slices"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// Register the way a third-party module would, once per process.
+func init() {
+	sqlparser.RegisterPass("test/drop-use", func(opts map[string]string) (sqlparser.Pass, error) {
+		return sqlparser.NewPass("test/drop-use", func(_ *sqlparser.PipelineContext, stmts []sqlparser.Statement) ([]sqlparser.Statement, error) {
+			out := stmts[:0:0]
+			for _, s := range stmts {
+				if _, ok := s.(*ast.UseStmt); !ok {
+					out = append(out, s)
+				}
+			}
+			return out, nil
+		}), nil
+	})
+}
+
+func TestRegisterPass(t *testing.T) {
+	names := sqlparser.RegisteredPasses()
+	for _, want := range []string{"analyze", "coalesce-inserts", "test/drop-use"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("%s not in %v", want, names)
+		}
+	}
+
+	var list []sqlparser.Pass
+	for _, def := range []struct {
+		name string
+		opts map[string]string
+	}{{"test/drop-use", nil}, {"coalesce-inserts", map[string]string{"max_rows": "2"}}} {
+		p, err := sqlparser.LookupPass(def.name, def.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list = append(list, p)
+	}
+	p := &sqlparser.Pipeline{Convert: sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL}, Passes: list}
+	res, err := p.RunSQL("USE shop; INSERT INTO t VALUES (1); INSERT INTO t VALUES (2); INSERT INTO t VALUES (3)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "INSERT INTO `t` VALUES (1), (2); INSERT INTO `t` VALUES (3)"; res.SQL != want {
+		t.Errorf("got %s\nwant %s", res.SQL, want)
+	}
+
+	for _, tt := range []struct {
+		name string
+		opts map[string]string
+	}{{"missing", nil}, {"analyze", map[string]string{"x": "1"}}, {"coalesce-inserts", map[string]string{"max_rows": "many"}}} {
+		if _, err := sqlparser.LookupPass(tt.name, tt.opts); err == nil {
+			t.Errorf("%s %v: expected an error", tt.name, tt.opts)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("duplicate registration did not panic")
+		}
+	}()
+	sqlparser.RegisterPass("analyze", func(map[string]string) (sqlparser.Pass, error) { return nil, nil })
+}
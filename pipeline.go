@@ -0,0 +1,180 @@
+package sqlparser
+
+import (
+	"fmt"
+	"io"
+	"iter"
+)
+
+// Pipeline runs statements through an ordered list of passes that share a
+// PipelineContext, and renders the result for Convert.Target. It replaces
+// chains of calls such as ParseStatements, CoalesceInserts, AnalyzeSQL and
+// ConvertStatements with one declaration:
+//
+//	p := &sqlparser.Pipeline{
+//		Convert: sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres},
+//		Passes:  []sqlparser.Pass{sqlparser.AnalyzePass(), sqlparser.CoalesceInsertsPass(500)},
+//	}
+//	res, err := p.RunSQL(script)
+type Pipeline struct {
+	// Schema is handed to the passes in PipelineContext.Schema.
+	Schema *Schema
+	// Convert holds the target dialect and options of the render step
+	// that follows the passes; with no Target nothing is rendered.
+	// KeepTags and KeepVersionComments are ignored, as in
+	// ConvertStatements.
+	Convert ConvertOptions
+	Passes  []Pass
+}
+
+// Pass is one step of a Pipeline. Run receives the statements of a run in
+// order and returns those the following passes see: it may filter,
+// replace, reorder or add statements, or return stmts unchanged after
+// inspecting them.
+type Pass interface {
+	Name() string
+	Run(ctx *PipelineContext, stmts []Statement) ([]Statement, error)
+}
+
+// PipelineContext is the state the passes of a run share.
+type PipelineContext struct {
+	Schema *Schema
+	// Dialect is the pipeline's Convert.Target.
+	Dialect Dialect
+	// Findings and Warnings collect what passes report; they end up in
+	// the PipelineResult.
+	Findings []AnalysisFinding
+	Warnings []ConversionWarning
+	// Values carries data between custom passes. In Stream it lives for
+	// the whole stream.
+	Values map[string]any
+}
+
+// PipelineResult is the outcome of a run.
+type PipelineResult struct {
+	// Statements are the statements the last pass returned.
+	Statements []Statement
+	// SQL is Statements rendered for Convert.Target, with the conversion
+	// warnings appended to Warnings; it is empty without a target.
+	SQL      string
+	Findings []AnalysisFinding
+	Warnings []ConversionWarning
+}
+
+// NewPass returns a Pass that runs fn.
+func NewPass(name string, fn func(ctx *PipelineContext, stmts []Statement) ([]Statement, error)) Pass {
+	return funcPass{name, fn}
+}
+
+type funcPass struct {
+	name string
+	fn   func(*PipelineContext, []Statement) ([]Statement, error)
+}
+
+func (p funcPass) Name() string { return p.name }
+
+func (p funcPass) Run(ctx *PipelineContext, stmts []Statement) ([]Statement, error) {
+	return p.fn(ctx, stmts)
+}
+
+// AnalyzePass adds the findings of AnalyzeSQL for the pipeline's dialect
+// to the context. Finding positions are offsets in the source; RunSQL also
+// fills in their lines and columns.
+func AnalyzePass() Pass {
+	return NewPass("analyze", func(ctx *PipelineContext, stmts []Statement) ([]Statement, error) {
+		var report AnalysisReport
+		for i, stmt := range stmts {
+			analyzeStatement(stmt, i, &report, AnalysisOptions{Dialect: ctx.Dialect, Schema: ctx.Schema})
+		}
+		ctx.Findings = append(ctx.Findings, report.Findings...)
+		return stmts, nil
+	})
+}
+
+// CoalesceInsertsPass merges consecutive INSERTs with CoalesceInserts,
+// comparing names as the dialect of the pipeline's schema does.
+func CoalesceInsertsPass(maxRows int) Pass {
+	return NewPass("coalesce-inserts", func(ctx *PipelineContext, stmts []Statement) ([]Statement, error) {
+		return coalesceInserts(stmts, maxRows, ctx.Schema.identDialect()), nil
+	})
+}
+
+// Run runs the passes over stmts and renders the result.
+func (p *Pipeline) Run(stmts []Statement) (*PipelineResult, error) {
+	return p.run(p.newContext(), stmts)
+}
+
+// RunSQL parses sql and runs the pipeline over its statements.
+func (p *Pipeline) RunSQL(sql string) (*PipelineResult, error) {
+	stmts, err := ParseStatements(sql)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.Run(stmts)
+	if res != nil {
+		setFindingLines(res.Findings, sql)
+	}
+	return res, err
+}
+
+// Stream runs the pipeline over each statement read from r in turn, in
+// constant memory, sharing one context across the stream. Like
+// StreamParser.Iter it yields parse errors and carries on; a pass error
+// ends the stream. Passes see one statement at a time, so
+// CoalesceInsertsPass has nothing to merge, and neither they nor the
+// caller may keep a statement past its step. Findings are placed in the
+// stream as RunSQL places them in its script: their positions, lines and
+// columns are those of the whole stream, and StatementIndex counts its
+// statements, including those that failed to parse.
+func (p *Pipeline) Stream(r io.Reader) iter.Seq2[*PipelineResult, error] {
+	return func(yield func(*PipelineResult, error) bool) {
+		ctx := p.newContext()
+		sp := NewStreamParser(r)
+		idx := -1
+		for stmt, err := range sp.Iter() {
+			idx++
+			if err != nil {
+				if !yield(nil, err) {
+					return
+				}
+				continue
+			}
+			res, err := p.run(ctx, []Statement{stmt})
+			if res != nil {
+				sp.placeFindings(res.Findings, idx)
+				for i := range res.Warnings {
+					if res.Warnings[i].Pos >= 0 {
+						res.Warnings[i].Pos += sp.stmtOff
+					}
+				}
+			}
+			if !yield(res, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (p *Pipeline) newContext() *PipelineContext {
+	return &PipelineContext{Schema: p.Schema, Dialect: p.Convert.Target, Values: map[string]any{}}
+}
+
+func (p *Pipeline) run(ctx *PipelineContext, stmts []Statement) (*PipelineResult, error) {
+	ctx.Findings, ctx.Warnings = nil, nil
+	for _, pass := range p.Passes {
+		var err error
+		if stmts, err = pass.Run(ctx, stmts); err != nil {
+			return nil, fmt.Errorf("sqlparser: pipeline pass %s: %w", pass.Name(), err)
+		}
+	}
+	res := &PipelineResult{Statements: stmts, Findings: ctx.Findings, Warnings: ctx.Warnings}
+	if p.Convert.Target != "" {
+		out, warnings, err := ConvertStatements(stmts, p.Convert)
+		res.Warnings = append(res.Warnings, warnings...)
+		if err != nil {
+			return res, err
+		}
+		res.SQL = out
+	}
+	return res, nil
+}
@@ -0,0 +1,96 @@
+package sqlparser_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+func TestPipeline(t *testing.T) {
+	var seen []string
+	dropUse := sqlparser.NewPass("drop-use", func(ctx *sqlparser.PipelineContext, stmts []sqlparser.Statement) ([]sqlparser.Statement, error) {
+		seen = append(seen, string(ctx.Dialect))
+		out := stmts[:0:0]
+		for _, s := range stmts {
+			if _, ok := s.(*ast.UseStmt); !ok {
+				out = append(out, s)
+			}
+		}
+		ctx.Values["kept"] = len(out)
+		return out, nil
+	})
+	count := sqlparser.NewPass("count", func(ctx *sqlparser.PipelineContext, stmts []sqlparser.Statement) ([]sqlparser.Statement, error) {
+		if ctx.Values["kept"] != 3 || len(stmts) != 2 {
+			t.Errorf("kept = %v, got %d statements after coalescing", ctx.Values["kept"], len(stmts))
+		}
+		return stmts, nil
+	})
+	p := &sqlparser.Pipeline{
+		Convert: sqlparser.ConvertOptions{Target: sqlparser.DialectPostgres},
+		Passes:  []sqlparser.Pass{dropUse, sqlparser.CoalesceInsertsPass(0), sqlparser.AnalyzePass(), count},
+	}
+	res, err := p.RunSQL("USE shop;\nINSERT INTO t (a) VALUES (1);\nINSERT INTO t (a) VALUES (2);\nDELETE FROM t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `INSERT INTO "t" ("a") VALUES (1), (2); DELETE FROM "t"`; res.SQL != want {
+		t.Errorf("got %s\nwant %s", res.SQL, want)
+	}
+	if len(res.Findings) != 1 || res.Findings[0].Code != "DELETE_WITHOUT_WHERE" || res.Findings[0].Line != 4 {
+		t.Errorf("unexpected findings %#v", res.Findings)
+	}
+	if len(seen) != 1 || seen[0] != "postgres" {
+		t.Errorf("context dialect %v", seen)
+	}
+
+	boom := errors.New("boom")
+	p.Passes = []sqlparser.Pass{sqlparser.AnalyzePass(), sqlparser.NewPass("fail", func(*sqlparser.PipelineContext, []sqlparser.Statement) ([]sqlparser.Statement, error) {
+		return nil, boom
+	})}
+	if _, err := p.RunSQL("SELECT 1"); !errors.Is(err, boom) || !strings.Contains(err.Error(), "fail") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestPipelineStream(t *testing.T) {
+	p := &sqlparser.Pipeline{
+		Convert: sqlparser.ConvertOptions{Target: sqlparser.DialectMySQL},
+		Passes:  []sqlparser.Pass{sqlparser.AnalyzePass()},
+	}
+	var out []string
+	var findings, errs int
+	for res, err := range p.Stream(strings.NewReader(`SELECT "a" FROM t; SELECT FROM; UPDATE t SET a = 1;`)) {
+		if err != nil {
+			errs++
+			continue
+		}
+		out = append(out, res.SQL)
+		findings += len(res.Findings)
+	}
+	if strings.Join(out, "; ") != "SELECT `a` FROM `t`; UPDATE `t` SET `a` = 1" || errs != 1 || findings != 1 {
+		t.Fatalf("got %q, %d errors, %d findings", out, errs, findings)
+	}
+
+	var got []sqlparser.AnalysisFinding
+	for res, err := range p.Stream(strings.NewReader("SELECT 1;\nSELECT 2;\nDELETE FROM t; DELETE FROM u")) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, res.Findings...)
+	}
+	if len(got) != 2 {
+		t.Fatalf("unexpected findings %#v", got)
+	}
+	for i, want := range []struct {
+		idx       int
+		pos       int32
+		line, col uint32
+	}{{2, 20, 3, 1}, {3, 35, 3, 16}} {
+		if f := got[i]; f.StatementIndex != want.idx || f.Pos != want.pos || f.Line != want.line || f.Col != want.col {
+			t.Errorf("finding %d at statement %d pos %d %d:%d, want %+v", i, f.StatementIndex, f.Pos, f.Line, f.Col, want)
+		}
+	}
+}
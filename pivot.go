@@ -0,0 +1,225 @@
+package sqlparser
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// renderPivot renders PIVOT or UNPIVOT, which only the generic target
+// keeps. For the others it becomes a derived table: a PIVOT one aggregate
+// of CASE per value grouped by the remaining columns, an UNPIVOT one
+// SELECT per column joined by UNION ALL. Both need the source's columns,
+// from ConvertOptions.Schema or a derived table's select list; without
+// them the clause is kept with a warning.
+func (r *dialectRenderer) renderPivot(t *ast.PivotTable) string {
+	if r.target != "" {
+		if cols, ok := r.sourceColumns(t.Source); ok {
+			sub := &ast.SubqueryTable{Subq: r.pivotSelect(t, cols), Alias: pivotAlias(t), TokPos: t.TokPos}
+			r.warn(WarnPivotRewritten, t.TokPos, "%s is not supported by %s and was rewritten as a derived table", pivotKeyword(t), r.target)
+			return r.renderDerivedTable(sub)
+		}
+		r.warn(WarnPivotUnsupported, t.TokPos, "%s is not supported by %s and the columns of its source are unknown; list them in ConvertOptions.Schema to rewrite it", pivotKeyword(t), r.target)
+	}
+	var b strings.Builder
+	b.WriteString(r.renderTableRef(t.Source) + " " + pivotKeyword(t))
+	if t.IncludeNulls {
+		b.WriteString(" INCLUDE NULLS")
+	}
+	b.WriteString(" (")
+	if t.Unpivot {
+		b.WriteString(r.renderIdent(t.Value))
+	} else {
+		b.WriteString(r.renderExpr(t.Agg))
+	}
+	b.WriteString(" FOR " + r.renderIdent(t.For) + " IN (")
+	for i, v := range t.In {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(r.renderExpr(v.Expr))
+		if v.Alias != nil {
+			b.WriteString(" AS " + r.renderIdent(v.Alias))
+		}
+	}
+	b.WriteString("))")
+	if t.Alias != nil {
+		b.WriteString(" " + r.renderIdent(t.Alias))
+	}
+	return b.String()
+}
+
+func pivotKeyword(t *ast.PivotTable) string {
+	if t.Unpivot {
+		return "UNPIVOT"
+	}
+	return "PIVOT"
+}
+
+// pivotAlias names the rewritten derived table: the clause's alias, or
+// the source's so that columns qualified with it still resolve.
+func pivotAlias(t *ast.PivotTable) *ast.Ident {
+	if t.Alias != nil {
+		return t.Alias
+	}
+	switch s := t.Source.(type) {
+	case *ast.SimpleTable:
+		if s.Alias != nil {
+			return s.Alias
+		}
+		return s.Name.Parts[len(s.Name.Parts)-1]
+	case *ast.SubqueryTable:
+		if s.Alias != nil {
+			return s.Alias
+		}
+	}
+	return &ast.Ident{Unquoted: strings.ToLower(pivotKeyword(t))}
+}
+
+// sourceColumns lists the columns of a FROM item: a table of the schema,
+// or a derived table whose columns are renamed or all have a name.
+func (r *dialectRenderer) sourceColumns(ref ast.TableRef) ([]string, bool) {
+	switch t := ref.(type) {
+	case *ast.SimpleTable:
+		if r.schema == nil {
+			return nil, false
+		}
+		table := r.schema.lookup(t.Name)
+		if table == nil {
+			return nil, false
+		}
+		cols := make([]string, len(table.Columns))
+		for i, c := range table.Columns {
+			cols[i] = c.Name
+		}
+		return cols, true
+	case *ast.SubqueryTable:
+		if len(t.Columns) > 0 {
+			return identNames(t.Columns), true
+		}
+		s := t.Subq
+		for s.SetOp != nil {
+			s = s.SetOp.Left
+		}
+		if len(s.Columns) == 0 {
+			return nil, false
+		}
+		cols := make([]string, len(s.Columns))
+		for i, c := range s.Columns {
+			switch e := c.Expr.(type) {
+			case nil:
+				return nil, false
+			case *ast.Ident:
+				cols[i] = e.Unquoted
+			case *ast.QualifiedIdent:
+				cols[i] = e.Parts[len(e.Parts)-1].Unquoted
+			}
+			if c.Alias != nil {
+				cols[i] = c.Alias.Unquoted
+			}
+			if cols[i] == "" || c.Star || len(c.Except) > 0 {
+				return nil, false
+			}
+		}
+		return cols, true
+	}
+	return nil, false
+}
+
+// pivotSelect builds the query a PIVOT or UNPIVOT over a source with
+// columns cols stands for.
+func (r *dialectRenderer) pivotSelect(t *ast.PivotTable, cols []string) *ast.SelectStmt {
+	used := []string{t.For.Unquoted}
+	if t.Unpivot {
+		used = used[:0]
+		for _, v := range t.In {
+			used = append(used, v.Expr.(*ast.Ident).Unquoted)
+		}
+	} else {
+		a := &auditor{
+			visit: func(string, string, accessKind) {},
+			node: func(n ast.Node, _ int) {
+				switch x := n.(type) {
+				case *ast.Ident:
+					used = append(used, x.Unquoted)
+				case *ast.QualifiedIdent:
+					used = append(used, x.Parts[len(x.Parts)-1].Unquoted)
+				}
+			},
+		}
+		for _, arg := range t.Agg.Args {
+			a.expr(arg, &auditScope{})
+		}
+	}
+	var kept []ast.SelectColumn
+	var group []ast.Expr
+	for _, c := range cols {
+		if slices.ContainsFunc(used, func(u string) bool { return strings.EqualFold(u, c) }) {
+			continue
+		}
+		id := &ast.Ident{Unquoted: c}
+		kept = append(kept, ast.SelectColumn{Expr: id})
+		group = append(group, id)
+	}
+	if !t.Unpivot {
+		s := &ast.SelectStmt{Columns: kept, From: []ast.TableRef{t.Source}, GroupBy: group, TokPos: t.TokPos}
+		for _, v := range t.In {
+			value := v.Expr
+			if id, ok := value.(*ast.Ident); ok {
+				// SQL Server names the values as identifiers.
+				value = stringLiteral(id.Unquoted)
+			}
+			agg := *t.Agg
+			var result ast.Expr = &ast.Literal{Raw: []byte("1"), Kind: lexer.INT}
+			if !agg.Star && len(agg.Args) > 0 {
+				result = agg.Args[0]
+			}
+			when := &ast.CaseExpr{Whens: []ast.WhenClause{{Cond: &ast.BinaryExpr{Left: t.For, Op: lexer.EQ, Right: value}, Result: result}}}
+			agg.Star = false
+			agg.Args = append([]ast.Expr{when}, agg.Args[min(1, len(agg.Args)):]...)
+			s.Columns = append(s.Columns, ast.SelectColumn{Expr: &agg, Alias: &ast.Ident{Unquoted: r.pivotValueName(v)}})
+		}
+		return s
+	}
+	var out *ast.SelectStmt
+	for _, v := range t.In {
+		col := v.Expr.(*ast.Ident)
+		s := &ast.SelectStmt{From: []ast.TableRef{t.Source}, TokPos: t.TokPos}
+		s.Columns = append(append(s.Columns, kept...),
+			ast.SelectColumn{Expr: stringLiteral(r.pivotValueName(v)), Alias: t.For},
+			ast.SelectColumn{Expr: col, Alias: t.Value})
+		if !t.IncludeNulls {
+			s.Where = &ast.IsNullExpr{Expr: col, Not: true}
+		}
+		if out == nil {
+			out = s
+			continue
+		}
+		out = &ast.SelectStmt{SetOp: &ast.SetOperation{Op: ast.Union, All: true, Left: out, Right: s}, TokPos: t.TokPos}
+	}
+	return out
+}
+
+// pivotValueName is the column a PIVOT value becomes, or the name an
+// UNPIVOT writes for a column: its alias, the identifier or the string.
+func (r *dialectRenderer) pivotValueName(v ast.PivotValue) string {
+	if v.Alias != nil {
+		return v.Alias.Unquoted
+	}
+	switch e := v.Expr.(type) {
+	case *ast.Ident:
+		return e.Unquoted
+	case *ast.Literal:
+		if e.Kind == lexer.STRING {
+			return unquoteString(e.Raw, r.source)
+		}
+		return string(e.Raw)
+	}
+	return strings.Trim(r.renderExpr(v.Expr), "()")
+}
+
+func stringLiteral(s string) *ast.Literal {
+	return &ast.Literal{Raw: []byte(quoteSQLString(s)), Kind: lexer.STRING}
+}
@@ -0,0 +1,333 @@
+package sqlparser
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// PrivilegeScope is the kind of object a privilege is granted on.
+type PrivilegeScope uint8
+
+const (
+	// ScopeTable is a table or view.
+	ScopeTable PrivilegeScope = iota
+	// ScopeSchema is the schema a new table or view goes into: a MySQL
+	// database or a PostgreSQL schema. An empty Object is the current one.
+	ScopeSchema
+	// ScopeDatabase is a database created, altered or dropped as a whole.
+	ScopeDatabase
+	// ScopeRoutine is a stored procedure or function.
+	ScopeRoutine
+)
+
+// Privilege is one grant a statement needs: Action on Object, limited to
+// Columns when they are set. Actions are SELECT, INSERT, UPDATE, DELETE,
+// REFERENCES, TRUNCATE, CREATE, CREATE TEMPORARY TABLES, CREATE VIEW,
+// ALTER, DROP, INDEX and EXECUTE.
+type Privilege struct {
+	Action  string
+	Scope   PrivilegeScope
+	Object  string
+	Columns []string
+}
+
+// String formats p for display, e.g. "UPDATE (email, name) ON users" or
+// "CREATE ON app.*".
+func (p Privilege) String() string {
+	var b strings.Builder
+	b.WriteString(p.Action)
+	if len(p.Columns) > 0 {
+		b.WriteString(" (" + strings.Join(p.Columns, ", ") + ")")
+	}
+	switch {
+	case p.Scope == ScopeRoutine:
+		b.WriteString(" ON PROCEDURE " + p.Object)
+	case p.Scope == ScopeTable:
+		b.WriteString(" ON " + p.Object)
+	case p.Object == "":
+		b.WriteString(" ON *")
+	default:
+		b.WriteString(" ON " + p.Object + ".*")
+	}
+	return b.String()
+}
+
+// RequiredPrivileges computes the smallest set of privileges needed to run
+// stmt. Column references are resolved to their tables as in
+// AuditColumnAccess, so reads become SELECT (cols), an INSERT column list
+// becomes INSERT (cols) and SET clauses become UPDATE (cols); statements
+// that use every column, such as SELECT * or DELETE, need the table-level
+// privilege. An unqualified column that could come from several joined
+// tables is granted on each of them. DDL needs CREATE on the schema,
+// ALTER, DROP or INDEX on the table, and REFERENCES on the columns its
+// foreign keys point at.
+//
+// The result is sorted by scope, object and action, with one entry per
+// action and object.
+func RequiredPrivileges(stmt Statement) []Privilege {
+	g := privilegeSet{}
+	a := &auditor{spread: true, visit: g.access}
+	g.statement(a, stmt)
+	return g.list()
+}
+
+// privilegeSet accumulates privileges keyed by scope, object and action. A
+// nil column set means the whole object.
+type privilegeSet map[privilegeKey]map[string]bool
+
+type privilegeKey struct {
+	scope  PrivilegeScope
+	object string
+	action string
+}
+
+// add grants action on object, for column, or for the whole object when
+// column is empty or "*".
+func (g privilegeSet) add(scope PrivilegeScope, object, action, column string) {
+	key := privilegeKey{scope, object, action}
+	cols, seen := g[key]
+	switch {
+	case column == "" || column == "*":
+		g[key] = nil
+	case !seen:
+		g[key] = map[string]bool{column: true}
+	case cols != nil:
+		cols[column] = true
+	}
+}
+
+var accessActions = [...]string{
+	accessRead:   "SELECT",
+	accessInsert: "INSERT",
+	accessUpdate: "UPDATE",
+	accessDelete: "DELETE",
+}
+
+func (g privilegeSet) access(table, column string, kind accessKind) {
+	if table == "" {
+		return
+	}
+	if kind == accessDelete {
+		column = ""
+	}
+	g.add(ScopeTable, table, accessActions[kind], column)
+}
+
+func (g privilegeSet) statement(a *auditor, stmt Statement) {
+	switch s := stmt.(type) {
+	case *ast.ExplainStmt:
+		if s.Table != nil {
+			g.add(ScopeTable, qualifiedName(s.Table), "SELECT", "")
+			return
+		}
+		g.statement(a, s.Stmt)
+	case *ast.CreateTableStmt:
+		if s.Temporary {
+			g.add(ScopeSchema, schemaOf(s.Table), "CREATE TEMPORARY TABLES", "")
+		} else {
+			g.add(ScopeSchema, schemaOf(s.Table), "CREATE", "")
+		}
+		if s.Like != nil {
+			g.add(ScopeTable, qualifiedName(s.Like), "SELECT", "")
+		}
+		for _, col := range s.Columns {
+			if col.References != nil {
+				g.references(col.References.Table, col.References.Columns)
+			}
+		}
+		for _, c := range s.Constraints {
+			if c.Type == ast.ForeignKeyConstraint {
+				g.references(c.RefTable, c.RefCols)
+			}
+		}
+		a.statement(s)
+	case *ast.CreateViewStmt:
+		g.add(ScopeSchema, schemaOf(s.Name), "CREATE VIEW", "")
+		a.statement(s)
+	case *ast.CreateIndexStmt:
+		g.add(ScopeTable, qualifiedName(s.Table), "INDEX", "")
+	case *ast.DropIndexStmt:
+		if s.Table != nil {
+			g.add(ScopeTable, qualifiedName(s.Table), "INDEX", "")
+		}
+	case *ast.AlterTableStmt:
+		table := qualifiedName(s.Table)
+		g.add(ScopeTable, table, "ALTER", "")
+		for _, cmd := range s.Cmds {
+			switch c := cmd.(type) {
+			case *ast.AddColumnCmd:
+				if c.Col.References != nil {
+					g.references(c.Col.References.Table, c.Col.References.Columns)
+				}
+			case *ast.AddConstraintCmd:
+				if c.Constraint.Type == ast.ForeignKeyConstraint {
+					g.references(c.Constraint.RefTable, c.Constraint.RefCols)
+				}
+			case *ast.DropIndexCmd:
+				g.add(ScopeTable, table, "INDEX", "")
+			case *ast.RenameTableCmd:
+				// Renaming moves the rows into a new table: MySQL checks
+				// DROP on the old name and CREATE and INSERT on the new.
+				g.add(ScopeTable, table, "DROP", "")
+				g.add(ScopeSchema, schemaOf(c.NewName), "CREATE", "")
+				g.add(ScopeTable, qualifiedName(c.NewName), "INSERT", "")
+			}
+		}
+	case *ast.DropTableStmt:
+		for _, t := range s.Tables {
+			g.add(ScopeTable, qualifiedName(t), "DROP", "")
+		}
+	case *ast.TruncateStmt:
+		for _, t := range s.Tables {
+			g.add(ScopeTable, qualifiedName(t), "TRUNCATE", "")
+		}
+	case *ast.CreateDatabaseStmt:
+		g.add(ScopeDatabase, s.Name.Unquoted, "CREATE", "")
+	case *ast.AlterDatabaseStmt:
+		g.add(ScopeDatabase, s.Name.Unquoted, "ALTER", "")
+	case *ast.DropDatabaseStmt:
+		g.add(ScopeDatabase, s.Name.Unquoted, "DROP", "")
+	case *ast.CallStmt:
+		g.add(ScopeRoutine, qualifiedName(s.Name), "EXECUTE", "")
+		for _, arg := range s.Args {
+			a.expr(arg, &auditScope{})
+		}
+	default:
+		a.statement(s)
+	}
+}
+
+func (g privilegeSet) references(table *ast.QualifiedIdent, cols []*ast.Ident) {
+	if table == nil {
+		return
+	}
+	if len(cols) == 0 {
+		g.add(ScopeTable, qualifiedName(table), "REFERENCES", "")
+	}
+	for _, c := range cols {
+		g.add(ScopeTable, qualifiedName(table), "REFERENCES", c.Unquoted)
+	}
+}
+
+func (g privilegeSet) list() []Privilege {
+	out := make([]Privilege, 0, len(g))
+	for key, cols := range g {
+		p := Privilege{Action: key.action, Scope: key.scope, Object: key.object}
+		for c := range cols {
+			p.Columns = append(p.Columns, c)
+		}
+		slices.SortFunc(p.Columns, compareFold)
+		out = append(out, p)
+	}
+	slices.SortFunc(out, func(x, y Privilege) int {
+		if c := cmp.Compare(x.Scope, y.Scope); c != 0 {
+			return c
+		}
+		if c := compareFold(x.Object, y.Object); c != 0 {
+			return c
+		}
+		return cmp.Compare(x.Action, y.Action)
+	})
+	return out
+}
+
+// schemaOf returns the schema qualifier of a table name, or "" for the
+// current schema.
+func schemaOf(q *ast.QualifiedIdent) string {
+	if q == nil || len(q.Parts) < 2 {
+		return ""
+	}
+	return qualifiedName(&ast.QualifiedIdent{Parts: q.Parts[:len(q.Parts)-1]})
+}
+
+// GrantStatements renders privs, as returned by RequiredPrivileges, as one
+// GRANT statement per object for grantee, which is written as given. For
+// PostgreSQL, privileges that only an object's owner or a role attribute
+// confers (ALTER, DROP, INDEX and database-level grants) have no GRANT
+// form and are left out, as is TEMPORARY, which is granted on the
+// database; CREATE VIEW becomes CREATE on the schema, and the current
+// schema is assumed to be public. For MySQL, TRUNCATE is granted as DROP.
+func GrantStatements(privs []Privilege, grantee string, target Dialect) []string {
+	r := newDialectRenderer(ConvertOptions{Target: target})
+	var out []string
+	var cur []string
+	var on string
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, "GRANT "+strings.Join(cur, ", ")+" ON "+on+" TO "+grantee)
+		}
+		cur = cur[:0]
+	}
+	for _, p := range privs {
+		action, object, ok := r.grantTarget(p)
+		if !ok {
+			continue
+		}
+		if object != on {
+			flush()
+			on = object
+		}
+		if len(p.Columns) > 0 {
+			cols := make([]string, len(p.Columns))
+			for i, c := range p.Columns {
+				cols[i] = r.renderIdent(&ast.Ident{Unquoted: c})
+			}
+			action += " (" + strings.Join(cols, ", ") + ")"
+		}
+		if !slices.Contains(cur, action) {
+			cur = append(cur, action)
+		}
+	}
+	flush()
+	return out
+}
+
+// grantTarget maps p to the action and ON clause of a GRANT for the
+// target, or reports false when the target cannot grant it.
+func (r *dialectRenderer) grantTarget(p Privilege) (action, on string, ok bool) {
+	name := func(dotted string) string {
+		parts := strings.Split(dotted, ".")
+		for i, part := range parts {
+			parts[i] = r.renderIdent(&ast.Ident{Unquoted: part})
+		}
+		return strings.Join(parts, ".")
+	}
+	if r.target == DialectMySQL {
+		action = p.Action
+		if action == "TRUNCATE" {
+			action = "DROP"
+		}
+		switch {
+		case p.Scope == ScopeTable:
+			return action, name(p.Object), true
+		case p.Scope == ScopeRoutine:
+			return action, "PROCEDURE " + name(p.Object), true
+		case p.Object == "":
+			return action, "*", true
+		}
+		return action, name(p.Object) + ".*", true
+	}
+	switch p.Action {
+	case "ALTER", "DROP", "INDEX":
+		return "", "", false
+	case "CREATE TEMPORARY TABLES":
+		// TEMPORARY is granted on the database, by name.
+		return "", "", false
+	}
+	switch p.Scope {
+	case ScopeTable:
+		return p.Action, name(p.Object), true
+	case ScopeRoutine:
+		return p.Action, "PROCEDURE " + name(p.Object), true
+	case ScopeSchema:
+		schema := p.Object
+		if schema == "" {
+			schema = "public"
+		}
+		return "CREATE", "SCHEMA " + name(schema), true
+	}
+	return "", "", false
+}
@@ -0,0 +1,67 @@
+package sqlparser_test
+
+import (
+	"reflect"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestRequiredPrivileges(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []string
+	}{
+		{"SELECT u.email, o.total FROM users u JOIN orders o ON o.user_id = u.id", []string{
+			"SELECT (total, user_id) ON orders", "SELECT (email, id) ON users"}},
+		{"SELECT * FROM users WHERE id = 1", []string{"SELECT ON users"}},
+		{"SELECT id FROM a, b", []string{"SELECT (id) ON a", "SELECT (id) ON b"}},
+		{"UPDATE users SET email = lower(name) WHERE id = 1", []string{"SELECT (id, name) ON users", "UPDATE (email) ON users"}},
+		{"INSERT INTO log (msg) SELECT name FROM users ON DUPLICATE KEY UPDATE msg = 'x'", []string{
+			"INSERT (msg) ON log", "UPDATE (msg) ON log", "SELECT (name) ON users"}},
+		{"DELETE FROM sessions WHERE expires < NOW()", []string{"DELETE ON sessions", "SELECT (expires) ON sessions"}},
+		{"CREATE TABLE app.orders (id INT, user_id INT REFERENCES users (id))", []string{"REFERENCES (id) ON users", "CREATE ON app.*"}},
+		{"CREATE TEMPORARY TABLE app.scratch (id INT)", []string{"CREATE TEMPORARY TABLES ON app.*"}},
+		{"CREATE VIEW v AS SELECT name FROM users", []string{"SELECT (name) ON users", "CREATE VIEW ON *"}},
+		{"ALTER TABLE t RENAME TO u", []string{"ALTER ON t", "DROP ON t", "INSERT ON u", "CREATE ON *"}},
+		{"TRUNCATE TABLE t", []string{"TRUNCATE ON t"}},
+		{"CALL archive((SELECT max(id) FROM t))", []string{"SELECT (id) ON t", "EXECUTE ON PROCEDURE archive"}},
+		{"BEGIN", nil},
+	}
+	for _, tt := range tests {
+		stmt, err := sqlparser.ParseStatement(tt.sql)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.sql, err)
+		}
+		var got []string
+		for _, p := range sqlparser.RequiredPrivileges(stmt) {
+			got = append(got, p.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got %q\nwant %q", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestGrantStatements(t *testing.T) {
+	stmt, err := sqlparser.ParseStatement("CREATE TABLE orders AS SELECT id, email FROM users")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	privs := sqlparser.RequiredPrivileges(stmt)
+	got := sqlparser.GrantStatements(privs, "'app'@'%'", sqlparser.DialectMySQL)
+	want := []string{"GRANT SELECT (`email`, `id`) ON `users` TO 'app'@'%'", "GRANT CREATE ON * TO 'app'@'%'"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("MySQL grants:\n got %q\nwant %q", got, want)
+	}
+	got = sqlparser.GrantStatements(privs, "app", sqlparser.DialectPostgres)
+	want = []string{`GRANT SELECT ("email", "id") ON "users" TO app`, `GRANT CREATE ON SCHEMA "public" TO app`}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("PostgreSQL grants:\n got %q\nwant %q", got, want)
+	}
+
+	stmt, _ = sqlparser.ParseStatement("DROP TABLE t")
+	if got := sqlparser.GrantStatements(sqlparser.RequiredPrivileges(stmt), "app", sqlparser.DialectPostgres); len(got) != 0 {
+		t.Fatalf("owner-only privilege should have no GRANT, got %q", got)
+	}
+}
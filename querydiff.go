@@ -0,0 +1,114 @@
+package sqlparser
+
+import "strings"
+
+// Query is one query of a query set, such as those an ORM issues,
+// extracted from one revision of an application.
+type Query struct {
+	// ID identifies the query across revisions, e.g. the file and function
+	// that issue it. It is optional; without it an edited query is
+	// reported as added rather than changed.
+	ID  string `json:"id,omitempty"`
+	SQL string `json:"sql"`
+}
+
+// QueryChangeKind says how a query differs from the previous revision.
+type QueryChangeKind string
+
+const (
+	QueryAdded   QueryChangeKind = "added"
+	QueryChanged QueryChangeKind = "changed"
+)
+
+// QueryChange is a query of the new revision whose fingerprint the old
+// revision lacks, with its analysis.
+type QueryChange struct {
+	Query
+	Kind QueryChangeKind `json:"kind"`
+	// Fingerprint is the query in canonical form, as in
+	// CacheInfo.Fingerprint; unparsable queries only have their
+	// whitespace collapsed.
+	Fingerprint string         `json:"fingerprint"`
+	Report      AnalysisReport `json:"report"`
+}
+
+// QueryDiffReport is the outcome of AnalyzeQueryChanges.
+type QueryDiffReport struct {
+	Changes []QueryChange `json:"changes"`
+	// Unchanged counts the queries of the new revision that the old one
+	// already had, which were not analyzed.
+	Unchanged int `json:"unchanged"`
+	// Removed lists the IDs of the old revision that the new one no longer
+	// has.
+	Removed []string `json:"removed,omitempty"`
+}
+
+// AnalyzeQueryChanges analyzes only the queries of after that are new or
+// changed since before, so a CI check can gate pull requests on SQL
+// quality without re-flagging legacy queries. Queries are compared by
+// fingerprint, which ignores formatting, keyword case and identifier
+// quoting but not literals; a query whose fingerprint is new is changed
+// when before has a query with the same ID, and added otherwise.
+func AnalyzeQueryChanges(before, after []Query, opts AnalysisOptions) *QueryDiffReport {
+	known := map[string]bool{}
+	ids := map[string]bool{}
+	for _, q := range before {
+		known[queryFingerprint(q.SQL)] = true
+		if q.ID != "" {
+			ids[q.ID] = true
+		}
+	}
+	report := &QueryDiffReport{Changes: []QueryChange{}}
+	kept := map[string]bool{}
+	for _, q := range after {
+		kept[q.ID] = true
+		fp := queryFingerprint(q.SQL)
+		if known[fp] {
+			report.Unchanged++
+			continue
+		}
+		kind := QueryAdded
+		if ids[q.ID] {
+			kind = QueryChanged
+		}
+		report.Changes = append(report.Changes, QueryChange{Query: q, Kind: kind, Fingerprint: fp, Report: AnalyzeSQLWithOptions(q.SQL, opts)})
+	}
+	for _, q := range before {
+		if ids[q.ID] && !kept[q.ID] {
+			report.Removed = append(report.Removed, q.ID)
+			kept[q.ID] = true
+		}
+	}
+	return report
+}
+
+// Failed reports whether any changed query has a finding of severity min
+// or worse, the usual condition for failing a CI check.
+func (r *QueryDiffReport) Failed(min FindingSeverity) bool {
+	for _, c := range r.Changes {
+		for _, f := range c.Report.Findings {
+			if f.Severity.Rank() >= min.Rank() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// queryFingerprint returns the canonical form of the statements of sql.
+func queryFingerprint(sql string) string {
+	stmts, err := ParseStatements(sql)
+	if err != nil {
+		return strings.Join(strings.Fields(sql), " ")
+	}
+	r := newDialectRenderer(ConvertOptions{Target: DialectPostgres})
+	parts := make([]string, len(stmts))
+	for i, stmt := range stmts {
+		out, err := r.renderStatement(stmt)
+		if err != nil {
+			return strings.Join(strings.Fields(sql), " ")
+		}
+		parts[i] = out
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,49 @@
+package sqlparser_test
+
+import (
+	"reflect"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestAnalyzeQueryChanges(t *testing.T) {
+	before := []sqlparser.Query{
+		{ID: "users.list", SQL: "SELECT * FROM users"},
+		{ID: "users.purge", SQL: "DELETE FROM users"},
+		{ID: "orders.get", SQL: "SELECT id FROM orders WHERE id = ?"},
+		{ID: "orders.old", SQL: "SELECT 1"},
+	}
+	after := []sqlparser.Query{
+		{ID: "users.list", SQL: "select *\n  from `users`"},
+		{ID: "users.purge", SQL: "DELETE FROM users"},
+		{ID: "orders.get", SQL: "SELECT id, total FROM orders WHERE id = $1"},
+		{ID: "orders.wipe", SQL: "UPDATE orders SET total = 0"},
+		{SQL: "SELECT FROM"},
+	}
+	report := sqlparser.AnalyzeQueryChanges(before, after, sqlparser.AnalysisOptions{})
+	if report.Unchanged != 2 || !reflect.DeepEqual(report.Removed, []string{"orders.old"}) {
+		t.Errorf("unchanged %d, removed %v", report.Unchanged, report.Removed)
+	}
+	var got []string
+	for _, c := range report.Changes {
+		got = append(got, c.ID+":"+string(c.Kind))
+	}
+	if want := []string{"orders.get:changed", "orders.wipe:added", ":added"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if c := report.Changes[1]; len(c.Report.Findings) == 0 || c.Report.Findings[0].Code != "UPDATE_WITHOUT_WHERE" {
+		t.Errorf("orders.wipe findings %+v", c.Report.Findings)
+	}
+	if c := report.Changes[2]; c.Report.Valid || c.Fingerprint != "SELECT FROM" {
+		t.Errorf("unparsable query %+v", c)
+	}
+	if !report.Failed(sqlparser.SeverityCritical) {
+		t.Error("expected the critical findings to fail the check")
+	}
+
+	report = sqlparser.AnalyzeQueryChanges(before, before[:2], sqlparser.AnalysisOptions{})
+	if len(report.Changes) != 0 || report.Failed(sqlparser.SeverityInfo) {
+		t.Errorf("legacy queries were re-flagged: %+v", report.Changes)
+	}
+}
@@ -0,0 +1,144 @@
+package sqlparser
+
+import (
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// QuotePolicy selects which identifiers ConvertDialectWithOptions quotes.
+type QuotePolicy uint8
+
+const (
+	// QuoteAlways quotes every identifier, which is always safe.
+	QuoteAlways QuotePolicy = iota
+	// QuoteWhenNeeded quotes only the identifiers the target would not
+	// read back as the same name unquoted: reserved words of the target
+	// or keywords of this parser, names with characters other than
+	// letters, digits and underscores or starting with a digit, and, for
+	// PostgreSQL, which folds unquoted names to lower case, names with
+	// upper-case letters.
+	QuoteWhenNeeded
+	// QuotePreserve quotes the identifiers that were quoted in the source,
+	// and those QuoteWhenNeeded would quote, so the output still parses.
+	QuotePreserve
+)
+
+// needsQuotes reports whether name must be quoted to be read back as
+// itself by the target.
+func (r *dialectRenderer) needsQuotes(name string) bool {
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return true
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '_':
+		case c >= 'A' && c <= 'Z':
+			if r.target == DialectPostgres || r.target == "" {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	words := reservedWords[r.target]
+	if r.target == "" {
+		// The generic output follows the standard, as PostgreSQL does.
+		words = reservedWords[DialectPostgres]
+	}
+	return lexer.IsKeyword(name) || words[strings.ToLower(name)]
+}
+
+// checkReserved warns when id, unquoted in the source, is a reserved word
+// of the target, which reads it as a name only quoted. renderIdent quotes
+// it under every QuotePolicy; the warning tells that hand-written SQL for
+// the target must quote it too. Each identifier is reported once, however
+// often it is rendered.
+func (r *dialectRenderer) checkReserved(id *ast.Ident) {
+	if r.target == "" || len(id.Raw) == 0 || quoted(id) || !reservedWords[r.target][strings.ToLower(id.Unquoted)] {
+		return
+	}
+	for _, seen := range r.reserved {
+		if seen.TokPos == id.TokPos {
+			return
+		}
+	}
+	r.reserved = append(r.reserved, id)
+	r.warn(WarnReservedWordQuoted, id.TokPos, "%s is a reserved word in %s and was quoted", id.Unquoted, r.target)
+}
+
+// quoted reports whether id was quoted in the source.
+func quoted(id *ast.Ident) bool {
+	return len(id.Raw) > 0 && (id.Raw[0] == '`' || id.Raw[0] == '"' || id.Raw[0] == '[') ||
+		len(id.Raw) > 2 && id.Raw[1] == '&'
+}
+
+// reservedWords are the words each target reserves, which name a column
+// or table only when quoted. ClickHouse reserves none, but reads the
+// standard's clause keywords as such where an alias may follow.
+var reservedWords = map[Dialect]map[string]bool{
+	DialectMySQL: wordSet(`accessible add all alter analyze and as asc asensitive before between
+		bigint binary blob both by call cascade case change char character check collate column
+		condition constraint continue convert create cross cube cume_dist current_date
+		current_time current_timestamp current_user cursor database databases day_hour
+		day_microsecond day_minute day_second dec decimal declare default delayed delete
+		dense_rank desc describe deterministic distinct distinctrow div double drop dual each
+		else elseif empty enclosed escaped except exists exit explain false fetch first_value
+		float float4 float8 for force foreign from fulltext function generated get grant group
+		grouping groups having high_priority hour_microsecond hour_minute hour_second if ignore
+		in index infile inner inout insensitive insert int int1 int2 int3 int4 int8 integer
+		intersect interval into io_after_gtids io_before_gtids is iterate join json_table key
+		keys kill lag last_value lateral lead leading leave left like limit linear lines load
+		localtime localtimestamp lock long longblob longtext loop low_priority master_bind
+		master_ssl_verify_server_cert match maxvalue mediumblob mediumint mediumtext middleint
+		minute_microsecond minute_second mod modifies natural not no_write_to_binlog nth_value
+		ntile null numeric of on optimize optimizer_costs option optionally or order out outer
+		outfile over partition percent_rank precision primary procedure purge range rank read
+		reads read_write real recursive references regexp release rename repeat replace require
+		resignal restrict return revoke right rlike row row_number rows schema schemas
+		second_microsecond select sensitive separator set show signal smallint spatial specific
+		sql sqlexception sqlstate sqlwarning sql_big_result sql_calc_found_rows
+		sql_small_result ssl starting stored straight_join system table terminated then
+		tinyblob tinyint tinytext to trailing trigger true undo union unique unlock unsigned
+		update usage use using utc_date utc_time utc_timestamp values varbinary varchar
+		varcharacter varying virtual when where while window with write xor year_month
+		zerofill`),
+	DialectPostgres: wordSet(`all analyse analyze and any array as asc asymmetric authorization
+		binary both case cast check collate collation column concurrently constraint create
+		cross current_catalog current_date current_role current_schema current_time
+		current_timestamp current_user default deferrable desc distinct do else end except
+		false fetch for foreign freeze from full grant group having ilike in initially inner
+		intersect into is isnull join lateral leading left like limit localtime localtimestamp
+		natural not notnull null offset on only or order outer overlaps placing primary
+		references returning right select session_user similar some symmetric system_user
+		table tablesample then to trailing true union unique user using variadic verbose when
+		where window with`),
+	DialectSQLite: wordSet(`abort action add after all alter always analyze and as asc attach
+		autoincrement before begin between by cascade case cast check collate column commit
+		conflict constraint create cross current current_date current_time current_timestamp
+		database default deferrable deferred delete desc detach distinct do drop each else end
+		escape except exclude exclusive exists explain fail filter first following for foreign
+		from full generated glob group groups having if ignore immediate in index indexed
+		initially inner insert instead intersect into is isnull join key last left like limit
+		match materialized natural no not nothing notnull null nulls of offset on or order
+		others outer over partition plan pragma preceding primary query raise range recursive
+		references regexp reindex release rename replace restrict returning right rollback row
+		rows savepoint select set table temp temporary then ties to transaction trigger
+		unbounded union unique update using vacuum values view virtual when where window with
+		without`),
+	DialectClickHouse: wordSet(`all and any array as asc by case cross desc distinct else end
+		except final format from full global group having in inner intersect into is join
+		left like limit not null on or order outer prewhere right sample select settings then
+		union using when where with`),
+}
+
+// wordSet returns the set of the space-separated words of s.
+func wordSet(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range strings.Fields(s) {
+		set[w] = true
+	}
+	return set
+}
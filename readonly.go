@@ -0,0 +1,112 @@
+package sqlparser
+
+import (
+	"fmt"
+
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// sideEffectFuncs are the built-in functions that change state when a
+// query calls them: sequences, locks, notifications, configuration, large
+// objects and other sessions. Functions that only read session state, such
+// as LAST_INSERT_ID, or wait, such as SLEEP, are not listed.
+var sideEffectFuncs = map[string]bool{
+	"nextval": true, "setval": true, "txid_current": true, "pg_current_xact_id": true,
+	"pg_advisory_lock": true, "pg_advisory_lock_shared": true, "pg_advisory_xact_lock": true,
+	"pg_advisory_xact_lock_shared": true, "pg_try_advisory_lock": true, "pg_try_advisory_lock_shared": true,
+	"pg_try_advisory_xact_lock": true, "pg_try_advisory_xact_lock_shared": true, "pg_advisory_unlock": true,
+	"pg_advisory_unlock_shared": true, "pg_advisory_unlock_all": true, "pg_notify": true, "set_config": true,
+	"pg_switch_wal": true, "pg_create_restore_point": true, "pg_cancel_backend": true,
+	"pg_terminate_backend": true, "pg_reload_conf": true, "lo_create": true, "lo_creat": true,
+	"lo_import": true, "lo_export": true, "lo_unlink": true, "dblink_exec": true,
+	"get_lock": true, "release_lock": true, "release_all_locks": true, "load_extension": true,
+}
+
+// ReadOnlyError is returned by ValidateReadOnly for a statement with side
+// effects.
+type ReadOnlyError struct {
+	// Statement is the index of the statement in the script.
+	Statement int
+	// Pos is the offset of the statement or of the call that has the side
+	// effect.
+	Pos    int32
+	Reason string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("sqlparser: statement %d is not read-only: %s", e.Statement+1, e.Reason)
+}
+
+// ValidateReadOnly returns an error unless every statement of sql is free
+// of side effects, for endpoints that run queries written by customers:
+// SELECT, SHOW, DESCRIBE, and EXPLAIN (with ANALYZE only of a read-only
+// statement). INSERT, UPDATE, DELETE, DDL, CALL, SET, USE and anything
+// else is rejected, as is a query that calls a built-in function with side
+// effects, such as nextval or pg_advisory_lock, anywhere in it, or that
+// runs an INSERT, UPDATE or DELETE inside WITH. The error is a
+// *ReadOnlyError, or the *ParseError of a script that does not parse.
+//
+// User-defined functions are assumed to be read-only; deny EXECUTE on
+// them, or use a read-only transaction, where that matters.
+func ValidateReadOnly(sql string) error {
+	stmts, err := ParseStatements(sql)
+	if err != nil {
+		return err
+	}
+	for i, stmt := range stmts {
+		if pos, reason := sideEffect(stmt); reason != "" {
+			return &ReadOnlyError{Statement: i, Pos: pos, Reason: reason}
+		}
+	}
+	return nil
+}
+
+// sideEffect returns the position of the side effect of stmt and a
+// description of it, or an empty reason for a read-only statement.
+func sideEffect(stmt Statement) (int32, string) {
+	switch s := stmt.(type) {
+	case *ast.ShowStmt:
+		return 0, ""
+	case *ast.ExplainStmt:
+		if s.Table != nil || !s.Analyze {
+			return 0, ""
+		}
+		if pos, reason := sideEffect(s.Stmt); reason != "" {
+			return pos, "EXPLAIN ANALYZE runs the statement, which " + reason
+		}
+		return 0, ""
+	case *ast.SelectStmt:
+		var (
+			pos    int32
+			reason string
+		)
+		if s.With != nil {
+			for _, cte := range s.With.CTEs {
+				if cte.DML != nil {
+					return cte.DML.Pos(), "writes rows in WITH"
+				}
+			}
+		}
+		a := &auditor{
+			visit: func(_, _ string, kind accessKind) {
+				if kind != accessRead && reason == "" {
+					pos, reason = s.Pos(), "writes rows in WITH"
+				}
+			},
+			call: func(f *ast.FuncCall) {
+				if name := sequenceFunc(f); sideEffectFuncs[name] && reason == "" {
+					pos, reason = f.Name.Pos(), "calls "+name
+				}
+			},
+		}
+		a.selectStmt(s, nil)
+		return pos, reason
+	}
+	switch stmt.(type) {
+	case *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt, *ast.TruncateStmt:
+		return stmt.Pos(), "writes rows"
+	case *ast.CallStmt:
+		return stmt.Pos(), "calls a procedure"
+	}
+	return stmt.Pos(), "is not a query"
+}
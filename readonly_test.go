@@ -0,0 +1,52 @@
+package sqlparser_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestValidateReadOnly(t *testing.T) {
+	for _, sql := range []string{
+		"SELECT * FROM orders WHERE placed > NOW(); SELECT COUNT(*) FROM users",
+		"WITH t AS (SELECT id FROM users) SELECT * FROM t",
+		"EXPLAIN DELETE FROM users",
+		"EXPLAIN ANALYZE SELECT id FROM users",
+		"SHOW TABLES",
+		"DESCRIBE users",
+		"SELECT LAST_INSERT_ID()",
+	} {
+		if err := sqlparser.ValidateReadOnly(sql); err != nil {
+			t.Errorf("%s: %v", sql, err)
+		}
+	}
+
+	tests := []struct {
+		sql    string
+		stmt   int
+		reason string
+		at     string // the source at the reported position
+	}{
+		{"SELECT 1; DELETE FROM users", 1, "writes rows", "DELETE"},
+		{"UPDATE users SET name = 'x'", 0, "writes rows", "UPDATE"},
+		{"CREATE TABLE t (id INT)", 0, "is not a query", "TABLE"},
+		{"CALL purge()", 0, "calls a procedure", "CALL"},
+		{"SET TRANSACTION READ WRITE", 0, "is not a query", "SET"},
+		{"SELECT id FROM users WHERE id IN (SELECT nextval('s'))", 0, "calls nextval", "nextval"},
+		{"EXPLAIN ANALYZE UPDATE users SET n = 1", 0, "EXPLAIN ANALYZE runs the statement, which writes rows", "UPDATE"},
+		{"WITH gone AS (DELETE FROM users RETURNING id) SELECT * FROM gone", 0, "writes rows in WITH", "DELETE"},
+	}
+	for _, tt := range tests {
+		err := sqlparser.ValidateReadOnly(tt.sql)
+		var ro *sqlparser.ReadOnlyError
+		if !errors.As(err, &ro) {
+			t.Errorf("%s: got %v, want a ReadOnlyError", tt.sql, err)
+			continue
+		}
+		if ro.Statement != tt.stmt || ro.Reason != tt.reason || !strings.HasPrefix(tt.sql[ro.Pos:], tt.at) {
+			t.Errorf("%s: got %+v (at %q)", tt.sql, ro, tt.sql[ro.Pos:])
+		}
+	}
+}
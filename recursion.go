@@ -0,0 +1,155 @@
+package sqlparser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// analyzeRecursiveCTEs checks every CTE of a WITH RECURSIVE that refers to
+// itself: it must be an anchor SELECT that does not, joined by UNION
+// [ALL] to the SELECTs that do (RECURSIVE_CTE_NO_UNION,
+// RECURSIVE_CTE_NO_ANCHOR), and each recursive SELECT needs a WHERE, a
+// join condition or a LIMIT that can stop producing rows
+// (RECURSIVE_CTE_UNBOUNDED). On MySQL the recursive SELECTs must also
+// reference the CTE once, in FROM, without aggregates, GROUP BY or
+// DISTINCT (RECURSIVE_CTE_MYSQL).
+func analyzeRecursiveCTEs(stmt Statement, idx int, report *AnalysisReport, opts AnalysisOptions) {
+	var withs []*ast.WithClause
+	a := &auditor{
+		visit: func(string, string, accessKind) {},
+		with:  func(w *ast.WithClause) { withs = append(withs, w) },
+	}
+	a.statement(stmt)
+	for _, w := range withs {
+		if !w.Recursive {
+			continue
+		}
+		for _, cte := range w.CTEs {
+			if cte.Subq != nil {
+				analyzeRecursiveCTE(cte, idx, report, opts)
+			}
+		}
+	}
+}
+
+func analyzeRecursiveCTE(cte ast.CTE, idx int, report *AnalysisReport, opts AnalysisOptions) {
+	name := cte.Name.Unquoted
+	var blocks []*ast.SelectStmt
+	var op *ast.SetOperation
+	var flatten func(*ast.SelectStmt)
+	flatten = func(s *ast.SelectStmt) {
+		if s.SetOp == nil {
+			blocks = append(blocks, s)
+			return
+		}
+		if s.SetOp.Op != ast.Union && op == nil {
+			op = s.SetOp
+		}
+		flatten(s.SetOp.Left)
+		flatten(s.SetOp.Right)
+	}
+	flatten(cte.Subq)
+	refs := make([]cteRefs, len(blocks))
+	recursive := false
+	for i, s := range blocks {
+		refs[i] = findCTERefs(s, name)
+		recursive = recursive || refs[i].count > 0
+	}
+	if recursive && op != nil {
+		addFinding(report, SeverityCritical, "RECURSIVE_CTE_NO_UNION", fmt.Sprintf("Recursive CTE %s combines its SELECTs with INTERSECT or EXCEPT; a recursive CTE must be an anchor SELECT and a recursive SELECT joined by UNION [ALL].", name), "Join the anchor and the recursive SELECT with UNION ALL (or UNION to drop duplicate rows).", idx, op.Right.TokPos)
+		return
+	}
+	if !recursive {
+		return
+	}
+	if len(blocks) == 1 {
+		addFinding(report, SeverityCritical, "RECURSIVE_CTE_NO_UNION", fmt.Sprintf("Recursive CTE %s refers to itself without a UNION [ALL]; there is no anchor to start the recursion from.", name), "Write the CTE as an anchor SELECT that does not read it, UNION ALL, and the recursive SELECT.", idx, cte.Name.Pos())
+		return
+	}
+	if refs[0].count > 0 {
+		addFinding(report, SeverityCritical, "RECURSIVE_CTE_NO_ANCHOR", fmt.Sprintf("The first SELECT of recursive CTE %s reads %s itself, so it has no non-recursive anchor.", name, name), "Start the CTE with a SELECT that does not read it, such as the root rows or the first value, followed by UNION ALL and the recursive SELECT.", idx, blocks[0].TokPos)
+	}
+	for i, s := range blocks {
+		if refs[i].count == 0 {
+			continue
+		}
+		if !bounded(s) {
+			addFinding(report, SeverityWarning, "RECURSIVE_CTE_UNBOUNDED", fmt.Sprintf("The recursive SELECT of CTE %s has no WHERE, join condition or LIMIT, so nothing stops it from producing rows and the recursion is likely infinite.", name), "Add a predicate that eventually fails, such as WHERE n < 100 or a depth counter, or join to the table being walked.", idx, s.TokPos)
+		}
+		if opts.Dialect == DialectMySQL {
+			analyzeMySQLRecursion(name, s, refs[i], idx, report)
+		}
+	}
+}
+
+func analyzeMySQLRecursion(name string, s *ast.SelectStmt, r cteRefs, idx int, report *AnalysisReport) {
+	var problems []string
+	if r.count > 1 {
+		problems = append(problems, "references the CTE more than once")
+	}
+	if r.nested {
+		problems = append(problems, "references the CTE inside a subquery")
+	}
+	if s.Distinct || len(s.GroupBy) > 0 || r.aggregate {
+		problems = append(problems, "uses DISTINCT, GROUP BY or an aggregate function")
+	}
+	if len(problems) == 0 {
+		return
+	}
+	addFinding(report, SeverityCritical, "RECURSIVE_CTE_MYSQL", fmt.Sprintf("The recursive SELECT of CTE %s %s, which MySQL rejects.", name, strings.Join(problems, " and ")), "In MySQL the recursive SELECT must read the CTE exactly once, directly in FROM, and aggregate in the outer query instead.", idx, s.TokPos)
+}
+
+// cteRefs describes how one SELECT block of a CTE body reads the CTE.
+type cteRefs struct {
+	count int
+	// nested is set when a reference is inside a subquery.
+	nested bool
+	// aggregate is set when the block itself calls an aggregate function.
+	aggregate bool
+}
+
+func findCTERefs(s *ast.SelectStmt, name string) cteRefs {
+	var r cteRefs
+	a := &auditor{
+		visit: func(string, string, accessKind) {},
+		node: func(n ast.Node, depth int) {
+			switch x := n.(type) {
+			case *ast.SimpleTable:
+				if len(x.Name.Parts) == 1 && strings.EqualFold(x.Name.Parts[0].Unquoted, name) {
+					r.count++
+					r.nested = r.nested || depth > 0
+				}
+			case *ast.FuncCall:
+				if depth == 0 && x.Name != nil && x.Over == nil && aggregateFuncs[strings.ToUpper(x.Name.Parts[len(x.Name.Parts)-1].Unquoted)] {
+					r.aggregate = true
+				}
+			}
+		},
+	}
+	a.selectCore(s, &auditScope{})
+	return r
+}
+
+// bounded reports whether the recursive SELECT s has something that can
+// stop it: a WHERE, a LIMIT, or a join condition.
+func bounded(s *ast.SelectStmt) bool {
+	if s.Where != nil || s.Limit != nil {
+		return true
+	}
+	for _, ref := range s.From {
+		if joinCondition(ref) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinCondition(ref ast.TableRef) bool {
+	j, ok := ref.(*ast.JoinTable)
+	if !ok {
+		return false
+	}
+	return j.On != nil || len(j.Using) > 0 || j.Kind == ast.NaturalJoin || joinCondition(j.Left) || joinCondition(j.Right)
+}
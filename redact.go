@@ -0,0 +1,60 @@
+package sqlparser
+
+import (
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// redactedLiteral replaces every redacted literal. A string literal keeps
+// the output valid wherever the original literal was.
+const redactedLiteral = "'?'"
+
+// RedactOptions tunes RedactLiteralsWithOptions.
+type RedactOptions struct {
+	// Target is the dialect the statements are rendered for.
+	Target Dialect
+	// RedactLimit redacts the counts of LIMIT and OFFSET too. They are kept
+	// by default: they describe the query rather than the data, and a '?'
+	// in their place is no valid count in any dialect.
+	RedactLimit bool
+}
+
+// RedactLiterals replaces the string and number literals of sql with '?'
+// for compliant query logging. Unlike Obfuscate it parses the statements
+// and returns valid SQL with the structure intact, so the redacted text can
+// still be explained or replayed; text that does not parse is an error. A
+// negated number is redacted as a whole, and integers used as positions in
+// GROUP BY and ORDER BY are kept, as are the counts of LIMIT and OFFSET.
+func RedactLiterals(sql string) (string, error) {
+	return RedactLiteralsWithOptions(sql, RedactOptions{})
+}
+
+// RedactLiteralsWithOptions is RedactLiterals with explicit options.
+func RedactLiteralsWithOptions(sql string, opts RedactOptions) (string, error) {
+	stmts, err := ParseStatements(sql)
+	if err != nil {
+		return "", err
+	}
+	r := newDialectRenderer(ConvertOptions{Target: opts.Target})
+	r.literals = literalsRedacted
+	r.keepLimit = !opts.RedactLimit
+	return r.renderStatements(stmts)
+}
+
+// redactable reports whether e is a string, number, hex or bit literal, or
+// a negated number.
+func redactable(e ast.Expr) bool {
+	if u, ok := e.(*ast.UnaryExpr); ok && u.Op == lexer.MINUS {
+		lit, ok := u.Expr.(*ast.Literal)
+		return ok && (lit.Kind == lexer.INT || lit.Kind == lexer.FLOAT)
+	}
+	lit, ok := e.(*ast.Literal)
+	if !ok {
+		return false
+	}
+	switch lit.Kind {
+	case lexer.STRING, lexer.INT, lexer.FLOAT, lexer.HEXLIT, lexer.BITLIT:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package sqlparser_test
+
+import (
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestRedactLiterals(t *testing.T) {
+	tests := []struct {
+		sql  string
+		opts sqlparser.RedactOptions
+		want string
+	}{
+		{
+			"SELECT id, 'x' FROM users WHERE email = 'a@b.c' AND age > -30 AND active = TRUE ORDER BY 1 LIMIT 10 OFFSET 20",
+			sqlparser.RedactOptions{},
+			`SELECT "id", '?' FROM "users" WHERE ((("email" = '?') AND ("age" > '?')) AND ("active" = TRUE)) ORDER BY 1 ASC LIMIT 10 OFFSET 20`,
+		},
+		{
+			"SELECT id FROM users WHERE ssn = '123-45-6789' LIMIT 10 OFFSET 20",
+			sqlparser.RedactOptions{Target: sqlparser.DialectMySQL},
+			"SELECT `id` FROM `users` WHERE (`ssn` = '?') LIMIT 10 OFFSET 20",
+		},
+		{
+			"SELECT id FROM users LIMIT 10 OFFSET 20",
+			sqlparser.RedactOptions{RedactLimit: true},
+			`SELECT "id" FROM "users" LIMIT '?' OFFSET '?'`,
+		},
+		{
+			"INSERT INTO cards (number, cvv, note) VALUES ('4111111111111111', 123, NULL); UPDATE cards SET cvv = 0x1F WHERE id = 7",
+			sqlparser.RedactOptions{},
+			`INSERT INTO "cards" ("number", "cvv", "note") VALUES ('?', '?', NULL); UPDATE "cards" SET "cvv" = '?' WHERE ("id" = '?')`,
+		},
+	}
+	for _, tt := range tests {
+		got, err := sqlparser.RedactLiteralsWithOptions(tt.sql, tt.opts)
+		if err != nil {
+			t.Errorf("%s: %v", tt.sql, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s:\ngot  %s\nwant %s", tt.sql, got, tt.want)
+		}
+		if _, err := sqlparser.ParseStatements(got); err != nil {
+			t.Errorf("%s: redacted SQL does not parse: %v", got, err)
+		}
+	}
+
+	if _, err := sqlparser.RedactLiterals("SELECT FROM WHERE"); err == nil {
+		t.Error("expected an error for SQL that does not parse")
+	}
+}
@@ -0,0 +1,75 @@
+package sqlparser
+
+import (
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// replicaUnsafeFuncs are, per dialect, the built-in functions a read
+// replica must not run: those that write, such as nextval, take locks,
+// read state of the session on the primary, such as LAST_INSERT_ID, or
+// wait on the server, such as SLEEP. Functions that merely vary between
+// runs, such as NOW() or RAND(), are safe.
+var replicaUnsafeFuncs = map[Dialect][]string{
+	DialectMySQL: {
+		"last_insert_id", "found_rows", "row_count", "get_lock", "release_lock",
+		"release_all_locks", "sleep", "benchmark", "master_pos_wait", "source_pos_wait",
+		"wait_for_executed_gtid_set", "wait_until_sql_thread_after_gtids",
+	},
+	DialectPostgres: {
+		"nextval", "setval", "currval", "lastval", "pg_sleep", "pg_sleep_for", "pg_sleep_until",
+		"pg_advisory_lock", "pg_advisory_lock_shared", "pg_advisory_xact_lock",
+		"pg_advisory_xact_lock_shared", "pg_try_advisory_lock", "pg_try_advisory_lock_shared",
+		"pg_try_advisory_xact_lock", "pg_try_advisory_xact_lock_shared", "pg_advisory_unlock",
+		"pg_advisory_unlock_shared", "pg_advisory_unlock_all", "pg_notify", "set_config",
+		"txid_current", "pg_current_xact_id", "pg_current_wal_lsn", "pg_switch_wal",
+		"pg_create_restore_point", "pg_cancel_backend", "pg_terminate_backend", "pg_reload_conf",
+		"lo_create", "lo_creat", "lo_import", "lo_export", "lo_unlink", "dblink_exec",
+	},
+	DialectSQLite: {
+		"last_insert_rowid", "changes", "total_changes", "load_extension",
+	},
+}
+
+// replicaUnsafe is every dialect's replicaUnsafeFuncs: a statement does
+// not say which dialect it was written in.
+var replicaUnsafe = func() map[string]bool {
+	m := map[string]bool{}
+	for _, names := range replicaUnsafeFuncs {
+		for _, name := range names {
+			m[name] = true
+		}
+	}
+	return m
+}()
+
+// IsReplicaSafe reports whether stmt may be sent to a read replica: a
+// SELECT, or an EXPLAIN without ANALYZE of one, that calls none of the
+// built-in functions that write, lock, wait or read the primary's session
+// state and has no INSERT, UPDATE or DELETE in its WITH. Functions that only vary between runs, such as NOW(), are safe.
+// DESCRIBE of a table is safe; any other statement, including SHOW and
+// SET, is not.
+//
+// User-defined functions are assumed to be read-only.
+func IsReplicaSafe(stmt Statement) bool {
+	switch s := stmt.(type) {
+	case *ast.ExplainStmt:
+		return s.Table != nil || !s.Analyze && IsReplicaSafe(s.Stmt)
+	case *ast.SelectStmt:
+		safe := true
+		a := &auditor{
+			visit: func(_, _ string, kind accessKind) {
+				if kind != accessRead {
+					safe = false
+				}
+			},
+			call: func(f *ast.FuncCall) {
+				if replicaUnsafe[sequenceFunc(f)] {
+					safe = false
+				}
+			},
+		}
+		a.selectStmt(s, nil)
+		return safe
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package sqlparser_test
+
+import (
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestIsReplicaSafe(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want bool
+	}{
+		{"SELECT id, NOW(), RAND() FROM users WHERE created_at > CURRENT_DATE", true},
+		{"SELECT COUNT(*) FROM (SELECT DISTINCT org FROM users) o", true},
+		{"SELECT LAST_INSERT_ID()", false},
+		{"SELECT nextval('orders_id_seq')", false},
+		{"SELECT pg_catalog.pg_advisory_lock(42)", false},
+		{"SELECT * FROM users WHERE id IN (SELECT usr FROM logins WHERE SLEEP(1) = 0)", false},
+		{"WITH x AS (SELECT last_insert_rowid() AS id) SELECT * FROM x", false},
+		{"WITH gone AS (DELETE FROM jobs RETURNING id) SELECT * FROM gone", false},
+		{"SELECT app.nextval('x')", true},
+		{"EXPLAIN SELECT * FROM users", true},
+		{"EXPLAIN ANALYZE SELECT * FROM users", false},
+		{"DESCRIBE users", true},
+		{"INSERT INTO users (id) VALUES (1)", false},
+		{"SHOW TABLES", false},
+	}
+	for _, tt := range tests {
+		stmt, err := sqlparser.ParseStatement(tt.sql)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.sql, err)
+		}
+		if got := sqlparser.IsReplicaSafe(stmt); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package sqlparser
+
+import "fmt"
+
+// analyzeReservedWords flags the identifiers written unquoted that are
+// reserved words of opts.Dialect, such as a column named user or order in
+// PostgreSQL, which the target reads as a name only quoted
+// (RESERVED_WORD_IDENTIFIER). It renders the statement for the dialect,
+// whose renderer already finds and quotes them.
+func analyzeReservedWords(stmt Statement, idx int, report *AnalysisReport, opts AnalysisOptions) {
+	if opts.Dialect == "" {
+		return
+	}
+	r := newDialectRenderer(ConvertOptions{Target: opts.Dialect})
+	r.renderStatements([]Statement{stmt})
+	for _, id := range r.reserved {
+		quoted := `"` + id.Unquoted + `"`
+		if opts.Dialect == DialectMySQL || opts.Dialect == DialectClickHouse {
+			quoted = "`" + id.Unquoted + "`"
+		}
+		addFinding(report, SeverityWarning, "RESERVED_WORD_IDENTIFIER",
+			fmt.Sprintf("%s is a reserved word in %s, which reads it as a name only when quoted.", id.Unquoted, opts.Dialect),
+			fmt.Sprintf("Quote it as %s or rename it; dialect conversion to %s quotes it automatically.", quoted, opts.Dialect),
+			idx, id.TokPos)
+	}
+}
@@ -0,0 +1,87 @@
+package sqlparser
+
+import "github.com/oarkflow/sqlparser/ast"
+
+// Result holds the statements a reused Parser parsed and checks that they
+// are still valid. Their nodes live in the Parser's arena and their
+// identifiers and literals alias its input, so Reset and ReleaseParser
+// reuse the memory under them: a statement kept past either reads another
+// query's text, or zeroes. Statements panics instead once that has
+// happened. Retain and Detach make the statements outlive the Parser.
+//
+//	p.Reset(src)
+//	res, err := p.Parse()
+//	if err != nil { ... }
+//	cache[key] = res.Detach() // independent of p and src
+type Result struct {
+	stmts []Statement
+	// p and gen are the Parser and reset the statements belong to; p is
+	// nil once they no longer depend on it.
+	p   *Parser
+	gen uint64
+}
+
+// Parse parses all remaining statements into a Result.
+func (p *Parser) Parse() (*Result, error) {
+	stmts, err := p.parser().ParseAll()
+	if err != nil {
+		return nil, err
+	}
+	return &Result{stmts: stmts, p: p, gen: p.gen}, nil
+}
+
+// Valid reports whether the statements can still be used: the Parser has
+// not been reset or released since it parsed them, or they were retained
+// or detached.
+func (r *Result) Valid() bool {
+	return r.p == nil || r.p.p != nil && r.p.gen == r.gen
+}
+
+// Statements returns the parsed statements. It panics if they are no
+// longer valid.
+func (r *Result) Statements() []Statement {
+	r.check("Statements")
+	return r.stmts
+}
+
+func (r *Result) check(method string) {
+	if !r.Valid() {
+		panic("sqlparser: Result." + method + " called after its Parser was reset or released; call Retain or Detach first")
+	}
+}
+
+// Retain keeps the statements valid past the Parser's next Reset and
+// ReleaseParser without copying them: the Parser leaves its arena to them
+// and parses on in a new one, so its next parse allocates. The statements
+// still alias the input, which must not be modified.
+func (r *Result) Retain() *Result {
+	r.check("Retain")
+	if r.p != nil {
+		r.p.parser().Retain()
+		r.p = nil
+	}
+	return r
+}
+
+// Detach copies the statements onto the garbage-collected heap, with
+// every identifier and literal copied out of the input, so they depend on
+// neither the Parser nor the input buffer; see ast.Clone. It costs an
+// allocation per node; Retain is cheaper when the input is left alone.
+func (r *Result) Detach() *Result {
+	r.check("Detach")
+	detach(r.stmts)
+	r.p = nil
+	return r
+}
+
+// detach replaces each of stmts with its copy on the heap, and returns
+// stmts. The rewriters detach the statements before changing them: a
+// parsed statement lives in arena memory, which the garbage collector
+// does not scan, so a node allocated by a rewrite and linked into it
+// would be freed while the statement still points to it.
+func detach(stmts []Statement) []Statement {
+	for i, stmt := range stmts {
+		stmts[i] = ast.Clone(stmt)
+	}
+	return stmts
+}
@@ -0,0 +1,423 @@
+package sqlparser
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/eval"
+	"github.com/oarkflow/sqlparser/ident"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// KeyConstraint is what a statement says about the values of one column
+// of the rows it reads or changes: the values and ranges that rows may
+// have, or All when the statement does not narrow them down.
+//
+// The constraint may be wider than the statement's predicate, never
+// narrower: a sharding proxy that sends the statement to every shard
+// holding one of the values or part of one of the ranges reaches every
+// row it touches. An empty constraint, such as that of WHERE k = 1 AND
+// k = 2, matches no row.
+type KeyConstraint struct {
+	All    bool
+	Values []ChangeValue
+	Ranges []KeyRange
+}
+
+// KeyRange is a range of key values. A nil Lo or Hi leaves that end
+// unbounded.
+type KeyRange struct {
+	Lo, Hi                   *ChangeValue
+	LoInclusive, HiInclusive bool
+}
+
+// String renders the constraint for logs, e.g. {1, 2, [10, 20)} or all.
+func (c KeyConstraint) String() string {
+	if c.All {
+		return "all"
+	}
+	parts := make([]string, 0, len(c.Values)+len(c.Ranges))
+	for _, v := range c.Values {
+		parts = append(parts, v.String())
+	}
+	for _, r := range c.Ranges {
+		parts = append(parts, r.String())
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+func (r KeyRange) String() string {
+	lo, hi := "(-inf", "+inf)"
+	if r.Lo != nil {
+		lo = "(" + r.Lo.String()
+		if r.LoInclusive {
+			lo = "[" + r.Lo.String()
+		}
+	}
+	if r.Hi != nil {
+		hi = r.Hi.String() + ")"
+		if r.HiInclusive {
+			hi = r.Hi.String() + "]"
+		}
+	}
+	return lo + ", " + hi
+}
+
+// String renders v as SQL: the placeholder, or the literal.
+func (v ChangeValue) String() string {
+	switch x := v.Value.(type) {
+	case nil:
+		if v.Param != "" {
+			return v.Param
+		}
+		return "NULL"
+	case string:
+		return quoteSQLString(x)
+	}
+	return fmt.Sprint(v.Value)
+}
+
+// RoutingKey returns the values of column that the rows stmt reads or
+// changes may have, so a sharding proxy can route it without running it.
+// column is a column name, optionally qualified by a table name or alias
+// as in o.tenant_id to pick one side of a join.
+//
+// The WHERE clause of a SELECT, UPDATE or DELETE constrains the column
+// with =, IN, BETWEEN, <, <=, >, >= and IS NULL, combined with AND and
+// OR; anything else, including NOT, leaves it unconstrained. Constant
+// expressions are folded first, so tenant_id = 40 + 2 pins 42. The sides
+// of a UNION each contribute their values, and the VALUES rows of an
+// INSERT pin the column when it is in the column list. Values bound at
+// execution are reported as their placeholders.
+func RoutingKey(stmt Statement, column string) KeyConstraint {
+	k := keyFinder{column: column, args: changeArgs(stmt)}
+	if i := strings.LastIndexByte(column, '.'); i >= 0 {
+		k.qualifier, k.column = column[:i], column[i+1:]
+	}
+	switch s := stmt.(type) {
+	case *ast.SelectStmt:
+		return k.selectStmt(s)
+	case *ast.UpdateStmt:
+		return k.where(s.Where)
+	case *ast.DeleteStmt:
+		return k.where(s.Where)
+	case *ast.InsertStmt:
+		return k.insert(s)
+	}
+	return KeyConstraint{All: true}
+}
+
+type keyFinder struct {
+	qualifier, column string
+	args              []int32
+}
+
+func (k *keyFinder) selectStmt(s *ast.SelectStmt) KeyConstraint {
+	if s.SetOp != nil {
+		l, r := k.selectStmt(s.SetOp.Left), k.selectStmt(s.SetOp.Right)
+		switch s.SetOp.Op {
+		case ast.Union:
+			return unionKeys(l, r)
+		case ast.Intersect:
+			return intersectKeys(l, r)
+		}
+		// EXCEPT returns rows of its left side only.
+		return l
+	}
+	if s.Values != nil {
+		return KeyConstraint{All: true}
+	}
+	return k.where(s.Where)
+}
+
+func (k *keyFinder) insert(s *ast.InsertStmt) KeyConstraint {
+	if s.Select != nil || len(s.Values) == 0 {
+		return KeyConstraint{All: true}
+	}
+	idx := -1
+	for i, c := range s.Columns {
+		if k.same(c.Unquoted, k.column) {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return KeyConstraint{All: true}
+	}
+	var c KeyConstraint
+	for _, row := range s.Values {
+		if idx >= len(row) {
+			return KeyConstraint{All: true}
+		}
+		v, ok := k.value(row[idx])
+		if !ok {
+			return KeyConstraint{All: true}
+		}
+		c = unionKeys(c, KeyConstraint{Values: []ChangeValue{v}})
+	}
+	return c
+}
+
+func (k *keyFinder) where(e Expr) KeyConstraint {
+	all := KeyConstraint{All: true}
+	switch x := e.(type) {
+	case *ast.BinaryExpr:
+		switch x.Op {
+		case lexer.AND, lexer.DAMP:
+			return intersectKeys(k.where(x.Left), k.where(x.Right))
+		case lexer.OR:
+			return unionKeys(k.where(x.Left), k.where(x.Right))
+		}
+		op, other := x.Op, x.Right
+		switch {
+		case k.isColumn(x.Left):
+		case k.isColumn(x.Right):
+			op, other = flipComparison(op), x.Left
+		default:
+			return all
+		}
+		v, ok := k.value(other)
+		if !ok {
+			return all
+		}
+		switch op {
+		case lexer.EQ:
+			return KeyConstraint{Values: []ChangeValue{v}}
+		case lexer.LT, lexer.LTE:
+			return KeyConstraint{Ranges: []KeyRange{{Hi: &v, HiInclusive: op == lexer.LTE}}}
+		case lexer.GT, lexer.GTE:
+			return KeyConstraint{Ranges: []KeyRange{{Lo: &v, LoInclusive: op == lexer.GTE}}}
+		}
+	case *ast.InExpr:
+		if x.Not || x.Subq != nil || !k.isColumn(x.Expr) {
+			return all
+		}
+		var c KeyConstraint
+		for _, item := range x.List {
+			v, ok := k.value(item)
+			if !ok {
+				return all
+			}
+			c = unionKeys(c, KeyConstraint{Values: []ChangeValue{v}})
+		}
+		return c
+	case *ast.BetweenExpr:
+		if x.Not || !k.isColumn(x.Expr) {
+			return all
+		}
+		lo, ok1 := k.value(x.Lo)
+		hi, ok2 := k.value(x.Hi)
+		if !ok1 || !ok2 {
+			return all
+		}
+		return KeyConstraint{Ranges: []KeyRange{{Lo: &lo, Hi: &hi, LoInclusive: true, HiInclusive: true}}}
+	case *ast.IsNullExpr:
+		if !x.Not && k.isColumn(x.Expr) {
+			return KeyConstraint{Values: []ChangeValue{{Arg: -1}}}
+		}
+	}
+	return all
+}
+
+// isColumn reports whether e names the column, unqualified or, when the
+// column was given qualified, with the same qualifier.
+func (k *keyFinder) isColumn(e Expr) bool {
+	switch x := e.(type) {
+	case *ast.Ident:
+		return k.same(x.Unquoted, k.column)
+	case *ast.QualifiedIdent:
+		n := len(x.Parts)
+		return n >= 2 && k.same(x.Parts[n-1].Unquoted, k.column) &&
+			(k.qualifier == "" || k.same(x.Parts[n-2].Unquoted, k.qualifier))
+	}
+	return false
+}
+
+// same reports whether two names are the same, as ident.Equal has it for
+// a statement of unknown dialect.
+func (k *keyFinder) same(a, b string) bool {
+	return ident.Equal(a, b, "")
+}
+
+func (k *keyFinder) value(e Expr) (ChangeValue, bool) {
+	return changeValue(eval.Fold(e), k.args)
+}
+
+// flipComparison returns the operator that compares the same way with
+// its operands swapped.
+func flipComparison(op lexer.TokenType) lexer.TokenType {
+	switch op {
+	case lexer.LT:
+		return lexer.GT
+	case lexer.LTE:
+		return lexer.GTE
+	case lexer.GT:
+		return lexer.LT
+	case lexer.GTE:
+		return lexer.LTE
+	}
+	return op
+}
+
+// unionKeys returns the constraint of a OR b.
+func unionKeys(a, b KeyConstraint) KeyConstraint {
+	if a.All || b.All {
+		return KeyConstraint{All: true}
+	}
+	out := KeyConstraint{Values: a.Values, Ranges: append(a.Ranges[:len(a.Ranges):len(a.Ranges)], b.Ranges...)}
+	for _, v := range b.Values {
+		if !containsValue(out.Values, v) {
+			out.Values = append(out.Values[:len(out.Values):len(out.Values)], v)
+		}
+	}
+	return out
+}
+
+// intersectKeys returns a constraint for a AND b. The values of a side
+// without ranges are checked against the other side where their order is
+// known; two sides with ranges keep the first unless each is one range.
+func intersectKeys(a, b KeyConstraint) KeyConstraint {
+	switch {
+	case a.All:
+		return b
+	case b.All:
+		return a
+	case len(a.Ranges) == 0 && len(b.Ranges) == 0:
+		x, y := filterValues(a.Values, b), filterValues(b.Values, a)
+		if len(y) < len(x) {
+			x = y
+		}
+		return KeyConstraint{Values: x}
+	case len(a.Ranges) == 0:
+		return KeyConstraint{Values: filterValues(a.Values, b)}
+	case len(b.Ranges) == 0:
+		return KeyConstraint{Values: filterValues(b.Values, a)}
+	case len(a.Values) == 0 && len(b.Values) == 0 && len(a.Ranges) == 1 && len(b.Ranges) == 1:
+		r, ok := intersectRanges(a.Ranges[0], b.Ranges[0])
+		if !ok {
+			return KeyConstraint{}
+		}
+		return KeyConstraint{Ranges: []KeyRange{r}}
+	}
+	return a
+}
+
+// filterValues drops the values that c certainly excludes.
+func filterValues(values []ChangeValue, c KeyConstraint) []ChangeValue {
+	var out []ChangeValue
+	for _, v := range values {
+		if !excludes(c, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func excludes(c KeyConstraint, v ChangeValue) bool {
+	for _, u := range c.Values {
+		if n, ok := compareKeys(v, u); !ok || n == 0 {
+			return false
+		}
+	}
+	for _, r := range c.Ranges {
+		if !outside(r, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// outside reports whether v is certainly not in r.
+func outside(r KeyRange, v ChangeValue) bool {
+	if r.Lo != nil {
+		if n, ok := compareKeys(v, *r.Lo); ok && (n < 0 || n == 0 && !r.LoInclusive) {
+			return true
+		}
+	}
+	if r.Hi != nil {
+		if n, ok := compareKeys(v, *r.Hi); ok && (n > 0 || n == 0 && !r.HiInclusive) {
+			return true
+		}
+	}
+	return false
+}
+
+// intersectRanges returns the overlap of a and b, and false when they
+// certainly do not overlap. Bounds that cannot be compared keep a's.
+func intersectRanges(a, b KeyRange) (KeyRange, bool) {
+	r := a
+	if b.Lo != nil {
+		n, ok := 0, r.Lo != nil
+		if ok {
+			n, ok = compareKeys(*b.Lo, *r.Lo)
+		}
+		if r.Lo == nil || ok && (n > 0 || n == 0 && !b.LoInclusive) {
+			r.Lo, r.LoInclusive = b.Lo, b.LoInclusive
+		}
+	}
+	if b.Hi != nil {
+		n, ok := 0, r.Hi != nil
+		if ok {
+			n, ok = compareKeys(*b.Hi, *r.Hi)
+		}
+		if r.Hi == nil || ok && (n < 0 || n == 0 && !b.HiInclusive) {
+			r.Hi, r.HiInclusive = b.Hi, b.HiInclusive
+		}
+	}
+	if r.Lo != nil && r.Hi != nil {
+		if n, ok := compareKeys(*r.Lo, *r.Hi); ok && (n > 0 || n == 0 && !(r.LoInclusive && r.HiInclusive)) {
+			return KeyRange{}, false
+		}
+	}
+	return r, true
+}
+
+func containsValue(values []ChangeValue, v ChangeValue) bool {
+	for _, u := range values {
+		if u == v {
+			return true
+		}
+	}
+	return false
+}
+
+// compareKeys orders two literal values of the same kind: numbers with
+// numbers and booleans with booleans. Strings are only known to be equal
+// when they are the same: how others compare depends on the collation,
+// and MySQL's default ones take 'A' and 'a ' for equal to 'a'. ok is
+// false for placeholders, NULL, values of different kinds and different
+// strings.
+func compareKeys(a, b ChangeValue) (int, bool) {
+	if a.Param != "" || b.Param != "" {
+		return 0, false
+	}
+	switch x := a.Value.(type) {
+	case string:
+		y, ok := b.Value.(string)
+		return 0, ok && x == y
+	case bool:
+		y, ok := b.Value.(bool)
+		return cmp.Compare(boolRank(x), boolRank(y)), ok
+	}
+	x, ok1 := keyNumber(a.Value)
+	y, ok2 := keyNumber(b.Value)
+	return cmp.Compare(x, y), ok1 && ok2
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func keyNumber(v any) (float64, bool) {
+	switch x := v.(type) {
+	case int64:
+		return float64(x), true
+	case float64:
+		return x, true
+	}
+	return 0, false
+}
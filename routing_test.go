@@ -0,0 +1,60 @@
+package sqlparser_test
+
+import (
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestRoutingKey(t *testing.T) {
+	tests := []struct {
+		sql, column, want string
+	}{
+		{"SELECT * FROM orders WHERE tenant_id = 42", "tenant_id", "{42}"},
+		{"SELECT * FROM orders WHERE tenant_id = 40 + 2 AND status = 'open'", "tenant_id", "{42}"},
+		{"SELECT * FROM orders WHERE 'eu' = region", "region", "{'eu'}"},
+		{"SELECT * FROM orders WHERE tenant_id IN (1, 2, 3) AND tenant_id IN (2, 3, 4)", "tenant_id", "{2, 3}"},
+		{"SELECT * FROM orders WHERE tenant_id = 1 OR tenant_id IN (1, ?)", "tenant_id", "{1, ?}"},
+		{"SELECT * FROM orders WHERE tenant_id = $2 AND id = $1", "tenant_id", "{$2}"},
+		{"SELECT * FROM orders WHERE tenant_id = 1 AND tenant_id = 2", "tenant_id", "{}"},
+		{"SELECT * FROM orders WHERE region = 'eu' AND region = 'eu'", "region", "{'eu'}"},
+		{"SELECT * FROM orders WHERE region = 'EU' AND region = 'eu'", "region", "{'EU'}"},
+		{"SELECT * FROM orders WHERE region IN ('EU', 'US') AND region = 'eu'", "region", "{'eu'}"},
+		{"SELECT * FROM orders WHERE region >= 'a' AND region < 'B'", "region", "{['a', 'B')}"},
+		{"SELECT * FROM orders WHERE tenant_id BETWEEN 10 AND 20", "tenant_id", "{[10, 20]}"},
+		{"SELECT * FROM orders WHERE tenant_id >= 10 AND tenant_id < 20", "tenant_id", "{[10, 20)}"},
+		{"SELECT * FROM orders WHERE 5 < tenant_id", "tenant_id", "{(5, +inf)}"},
+		{"SELECT * FROM orders WHERE tenant_id > 30 AND tenant_id < 20", "tenant_id", "{}"},
+		{"SELECT * FROM orders WHERE tenant_id IN (5, 15, 25) AND tenant_id BETWEEN 10 AND 20", "tenant_id", "{15}"},
+		{"SELECT * FROM orders WHERE tenant_id < 10 OR tenant_id > 90", "tenant_id", "{(-inf, 10), (90, +inf)}"},
+		{"SELECT * FROM orders WHERE tenant_id IS NULL", "tenant_id", "{NULL}"},
+		{"SELECT * FROM orders WHERE tenant_id = 1 OR status = 'open'", "tenant_id", "all"},
+		{"SELECT * FROM orders WHERE NOT tenant_id = 1", "tenant_id", "all"},
+		{"SELECT * FROM orders WHERE tenant_id <> 1", "tenant_id", "all"},
+		{"SELECT * FROM orders WHERE tenant_id IN (SELECT id FROM tenants)", "tenant_id", "all"},
+		{"SELECT * FROM orders", "tenant_id", "all"},
+		{"SELECT * FROM orders o JOIN users u ON u.id = o.user_id WHERE u.tenant_id = 1 AND o.tenant_id = 2", "o.tenant_id", "{2}"},
+		{"SELECT id FROM a WHERE tenant_id = 1 UNION SELECT id FROM b WHERE tenant_id = 2", "tenant_id", "{1, 2}"},
+		{"SELECT id FROM a WHERE tenant_id = 1 UNION SELECT id FROM b", "tenant_id", "all"},
+		{"UPDATE orders SET status = 'done' WHERE tenant_id = ? AND id = ?", "tenant_id", "{?}"},
+		{"DELETE FROM orders WHERE tenant_id IN (7, 8)", "tenant_id", "{7, 8}"},
+		{"INSERT INTO orders (id, tenant_id) VALUES (1, 7), (2, 8), (3, 7)", "tenant_id", "{7, 8}"},
+		{"INSERT INTO orders VALUES (1, 7)", "tenant_id", "all"},
+		{"CREATE TABLE t (tenant_id int)", "tenant_id", "all"},
+	}
+	for _, tt := range tests {
+		stmt, err := sqlparser.ParseStatement(tt.sql)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.sql, err)
+		}
+		if got := sqlparser.RoutingKey(stmt, tt.column).String(); got != tt.want {
+			t.Errorf("RoutingKey(%q, %s) = %s, want %s", tt.sql, tt.column, got, tt.want)
+		}
+	}
+
+	stmt, _ := sqlparser.ParseStatement("SELECT * FROM orders WHERE id = ? AND tenant_id = ?")
+	c := sqlparser.RoutingKey(stmt, "tenant_id")
+	if len(c.Values) != 1 || c.Values[0].Arg != 1 {
+		t.Errorf("placeholder argument: %+v", c.Values)
+	}
+}
@@ -0,0 +1,167 @@
+package sqlparser
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// MaxRows returns an upper bound on the rows stmt can return or, for
+// INSERT, UPDATE and DELETE, change, so a gateway can budget memory for a
+// query before running it. It reports false when no bound is known.
+//
+// Bounds come from a literal LIMIT, the row count of INSERT ... VALUES or
+// DEFAULT VALUES, a
+// SELECT without FROM or with aggregates and no GROUP BY, and a WHERE
+// clause that pins every column of a primary key or UNIQUE index of a
+// single table with = or IN, which needs schema. schema may be nil.
+func MaxRows(stmt Statement, schema *Schema) (int64, bool) {
+	switch s := stmt.(type) {
+	case *ast.SelectStmt:
+		return selectRows(s, schema).max()
+	case *ast.InsertStmt:
+		if s.Select != nil {
+			return selectRows(s.Select, schema).max()
+		}
+		if s.DefaultValues {
+			return 1, true
+		}
+		return int64(len(s.Values)), true
+	case *ast.UpdateStmt:
+		b := unbounded
+		if t, ok := singleTable(s.Tables); ok {
+			b = keyRows(t, s.Where, schema)
+		}
+		return b.limit(s.Limit).max()
+	case *ast.DeleteStmt:
+		b := unbounded
+		if t, ok := singleTable(s.From); ok && len(s.Tables) <= 1 {
+			b = keyRows(t, s.Where, schema)
+		}
+		return b.limit(s.Limit).max()
+	}
+	return 0, false
+}
+
+// rowBound is an upper bound on a row count; negative means unbounded.
+type rowBound int64
+
+const unbounded rowBound = -1
+
+func (b rowBound) max() (int64, bool) { return int64(b), b >= 0 }
+
+// limit bounds b by a literal LIMIT count. FETCH ... WITH TIES can return
+// more rows than its count.
+func (b rowBound) limit(l *ast.LimitClause) rowBound {
+	if l == nil || l.WithTies {
+		return b
+	}
+	lit, ok := l.Count.(*ast.Literal)
+	if !ok || lit.Kind != lexer.INT {
+		return b
+	}
+	n, err := strconv.ParseInt(string(lit.Raw), 10, 64)
+	if err != nil {
+		return b
+	}
+	return b.min(rowBound(n))
+}
+
+func (b rowBound) min(o rowBound) rowBound {
+	if b < 0 || o >= 0 && o < b {
+		return o
+	}
+	return b
+}
+
+// add returns the bound on the rows of two results together.
+func (b rowBound) add(o rowBound) rowBound {
+	if b < 0 || o < 0 {
+		return unbounded
+	}
+	if b > math.MaxInt64-o {
+		return math.MaxInt64
+	}
+	return b + o
+}
+
+// selectRows bounds a SELECT or set operation.
+func selectRows(s *ast.SelectStmt, schema *Schema) rowBound {
+	if s.SetOp == nil {
+		return coreRows(s, schema).limit(s.Limit)
+	}
+	b := selectRows(s.SetOp.Left, schema)
+	r := selectRows(s.SetOp.Right, schema)
+	switch s.SetOp.Op {
+	case ast.Union:
+		b = b.add(r)
+	case ast.Intersect:
+		b = b.min(r)
+	}
+	return b.limit(s.Limit)
+}
+
+// coreRows bounds one SELECT without its LIMIT and set operations.
+func coreRows(s *ast.SelectStmt, schema *Schema) rowBound {
+	if len(s.From) == 0 {
+		return 1
+	}
+	if len(s.GroupBy) == 0 {
+		for _, c := range s.Columns {
+			if hasAggregate(c.Expr) {
+				return 1
+			}
+		}
+	}
+	if t, ok := singleTable(s.From); ok {
+		return keyRows(t, s.Where, schema)
+	}
+	return unbounded
+}
+
+// keyRows bounds the rows of table t that where selects by the unique keys
+// it pins: one row per combination of pinned values, for the key with the
+// fewest.
+func keyRows(t *ast.SimpleTable, where Expr, schema *Schema) rowBound {
+	if schema == nil || where == nil {
+		return unbounded
+	}
+	table := schema.lookup(t.Name)
+	if table == nil {
+		return unbounded
+	}
+	qualifier := identName(t.Alias)
+	if qualifier == "" {
+		qualifier = t.Name.Parts[len(t.Name.Parts)-1].Unquoted
+	}
+	keys := [][]string{table.PrimaryKey}
+	for _, idx := range table.Indexes {
+		if idx.Unique() {
+			keys = append(keys, idx.Columns)
+		}
+	}
+	terms := andTerms(where, nil)
+	b := unbounded
+	for _, key := range keys {
+		if len(key) == 0 {
+			continue
+		}
+		n := rowBound(1)
+		for _, col := range key {
+			values := pinnedValues(terms, qualifier, col, nil, table.dialect)
+			if values == nil {
+				n = unbounded
+				break
+			}
+			if n > math.MaxInt64/rowBound(len(values)) {
+				n = math.MaxInt64
+				continue
+			}
+			n *= rowBound(len(values))
+		}
+		b = b.min(n)
+	}
+	return b
+}
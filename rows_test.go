@@ -0,0 +1,58 @@
+package sqlparser_test
+
+import (
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestMaxRows(t *testing.T) {
+	schema, err := sqlparser.BuildSchema(`
+		CREATE TABLE users (id BIGINT PRIMARY KEY, email TEXT UNIQUE, org INT, name TEXT);
+		CREATE TABLE memberships (org INT, usr INT, role TEXT, PRIMARY KEY (org, usr))`)
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	tests := []struct {
+		sql     string
+		want    int64
+		bounded bool
+	}{
+		{"SELECT * FROM users", 0, false},
+		{"SELECT * FROM users LIMIT 50", 50, true},
+		{"SELECT * FROM users LIMIT ?", 0, false},
+		{"SELECT * FROM users u WHERE u.id = ? AND name LIKE 'a%'", 1, true},
+		{"SELECT * FROM users WHERE email IN ('a', 'b', 'c') LIMIT 10", 3, true},
+		{"SELECT * FROM users WHERE org = 1", 0, false},
+		{"SELECT * FROM memberships WHERE org IN (1, 2) AND usr IN (3, 4, 5)", 6, true},
+		{"SELECT * FROM memberships WHERE org = 1", 0, false},
+		{"SELECT COUNT(*), MAX(id) FROM users", 1, true},
+		{"SELECT org, COUNT(*) FROM users GROUP BY org", 0, false},
+		{"SELECT 1", 1, true},
+		{"SELECT id FROM users WHERE id = 1 UNION ALL SELECT id FROM users WHERE id IN (2, 3)", 3, true},
+		{"SELECT id FROM users UNION SELECT usr FROM memberships LIMIT 20", 20, true},
+		{"SELECT * FROM users u JOIN memberships m ON m.usr = u.id WHERE u.id = 1", 0, false},
+		{"INSERT INTO users (id) VALUES (1), (2), (3)", 3, true},
+		{"INSERT INTO users (id) SELECT usr FROM memberships LIMIT 5", 5, true},
+		{"UPDATE users SET name = 'x' WHERE id IN (1, 2)", 2, true},
+		{"UPDATE users SET name = 'x' WHERE org = 7 LIMIT 100", 100, true},
+		{"DELETE FROM memberships WHERE org = 1 AND usr = 2", 1, true},
+		{"DELETE FROM users", 0, false},
+		{"CREATE TABLE t (id INT)", 0, false},
+	}
+	for _, tt := range tests {
+		stmt, err := sqlparser.ParseStatement(tt.sql)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.sql, err)
+		}
+		got, ok := sqlparser.MaxRows(stmt, schema)
+		if got != tt.want && tt.bounded || ok != tt.bounded {
+			t.Errorf("%s: got %d %v, want %d %v", tt.sql, got, ok, tt.want, tt.bounded)
+		}
+	}
+
+	stmt, _ := sqlparser.ParseStatement("SELECT * FROM users WHERE id = 1")
+	if _, ok := sqlparser.MaxRows(stmt, nil); ok {
+		t.Error("bounded a key lookup without a schema")
+	}
+}
@@ -0,0 +1,111 @@
+package sqlparser
+
+import "encoding/json"
+
+// SARIF encodes the report as a SARIF 2.1.0 log, the format GitHub code
+// scanning and other CI dashboards import. uri names the analyzed file;
+// each finding code becomes a rule, and severities map to the levels
+// error, warning and note.
+func (r AnalysisReport) SARIF(uri string) ([]byte, error) {
+	run := sarifRun{Results: []sarifResult{}}
+	run.Tool.Driver = sarifDriver{
+		Name:           "sqlparser",
+		InformationURI: "https://github.com/oarkflow/sqlparser",
+		Rules:          []sarifRule{},
+	}
+	rules := map[string]int{}
+	for _, f := range r.Findings {
+		idx, ok := rules[f.Code]
+		if !ok {
+			idx = len(run.Tool.Driver.Rules)
+			rules[f.Code] = idx
+			rule := sarifRule{ID: f.Code, ShortDescription: sarifText{f.Problem}}
+			if f.Recommendation != "" {
+				rule.Help = &sarifText{f.Recommendation}
+			}
+			rule.DefaultConfiguration.Level = sarifLevel(f.Severity)
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+		}
+		loc := sarifLocation{}
+		loc.PhysicalLocation.ArtifactLocation.URI = uri
+		if f.Line > 0 {
+			loc.PhysicalLocation.Region = &sarifRegion{StartLine: f.Line, StartColumn: f.Col}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    f.Code,
+			RuleIndex: idx,
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifText{f.Message},
+			Locations: []sarifLocation{loc},
+		})
+	}
+	return json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+}
+
+func sarifLevel(s FindingSeverity) string {
+	switch s {
+	case SeverityCritical:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return "note"
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver sarifDriver `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string     `json:"id"`
+	ShortDescription     sarifText  `json:"shortDescription"`
+	Help                 *sarifText `json:"help,omitempty"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifText       `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region *sarifRegion `json:"region,omitempty"`
+	} `json:"physicalLocation"`
+}
+
+type sarifRegion struct {
+	StartLine   uint32 `json:"startLine"`
+	StartColumn uint32 `json:"startColumn"`
+}
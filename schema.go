@@ -0,0 +1,510 @@
+package sqlparser
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/ident"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// Schema is a snapshot of the tables a DDL script defines. It is built by
+// replaying CREATE, ALTER and DROP statements in order, so a migration
+// history yields the schema as of its last statement.
+type Schema struct {
+	Tables []*Table
+	// Dialect decides which names are the same, as ident.Equal does:
+	// PostgreSQL and ClickHouse tell apart names that differ in case, the
+	// others, and the empty dialect, do not. Set it before applying
+	// statements; tables keep the dialect they were created under.
+	Dialect Dialect
+}
+
+// Table is one table of a Schema. Names are the resolved identifiers, so
+// unquoted names are lower-case.
+type Table struct {
+	Name string
+	// Namespace is the schema or database qualifier, empty when the table
+	// was declared unqualified.
+	Namespace   string
+	Columns     []*Column
+	PrimaryKey  []string
+	Indexes     []*Index
+	ForeignKeys []*ForeignKey
+	// Checks are the CHECK expressions of the table and its columns.
+	Checks  []ast.Expr
+	Options []ast.TableOption
+
+	dialect Dialect
+}
+
+// Column is one column of a Table.
+type Column struct {
+	Name string
+	// Type is the declared type name in upper case, e.g. VARCHAR.
+	Type      string
+	Precision int
+	Scale     int
+	Unsigned  bool
+	// EnumValues lists the unquoted members of an ENUM or SET type.
+	EnumValues    []string
+	Nullable      bool
+	Default       ast.Expr
+	AutoIncrement bool
+	Generated     bool
+	Comment       string
+}
+
+// Index is a secondary index or UNIQUE constraint of a Table.
+type Index struct {
+	Name    string
+	Columns []string
+	Kind    ast.ConstraintType
+	// Method is the access method of USING, as written, or "" for the
+	// default.
+	Method string
+	// Include lists the non-key columns of a covering index.
+	Include []string
+	// Options are the storage parameters and MySQL index options, such as
+	// fillfactor or KEY_BLOCK_SIZE.
+	Options []ast.TableOption
+	// Invisible reports a MySQL index the optimizer ignores.
+	Invisible bool
+	// Where is the predicate of a partial index.
+	Where ast.Expr
+}
+
+// Unique reports whether the index enforces uniqueness.
+func (i *Index) Unique() bool { return i.Kind == ast.UniqueConstraint }
+
+// ForeignKey is a FOREIGN KEY constraint or a column REFERENCES clause.
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnDelete   ast.RefAction
+	OnUpdate   ast.RefAction
+	Match      ast.FKMatch
+	// Deferrable and InitiallyDeferred say when the reference is checked:
+	// at the end of the statement, or, when deferred, at commit.
+	Deferrable        bool
+	InitiallyDeferred bool
+}
+
+// BuildSchema parses sql and replays its DDL into a Schema. Statements
+// other than DDL are ignored.
+func BuildSchema(sql string) (*Schema, error) {
+	return BuildSchemaForDialect(sql, "")
+}
+
+// BuildSchemaForDialect is BuildSchema for a schema whose names compare,
+// and whose strings read, as they do in dialect.
+func BuildSchemaForDialect(sql string, dialect Dialect) (*Schema, error) {
+	res, err := ParseWithOptions(sql, ParseOptions{StandardStrings: dialect == DialectPostgres || dialect == DialectSQLite})
+	if err != nil {
+		return nil, err
+	}
+	s := &Schema{Dialect: dialect}
+	for _, stmt := range res.Statements {
+		s.Apply(stmt)
+	}
+	return s, nil
+}
+
+// identDialect returns the dialect by which the names of s compare, the
+// empty one when s is nil.
+func (s *Schema) identDialect() Dialect {
+	if s == nil {
+		return ""
+	}
+	return s.Dialect
+}
+
+// Table returns the table with the given name, or nil. The name may be
+// qualified as namespace.table; it is matched as Dialect compares names.
+func (s *Schema) Table(name string) *Table {
+	ns, tbl := "", name
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		ns, tbl = name[:i], name[i+1:]
+	}
+	for _, t := range s.Tables {
+		if ident.Equal(t.Name, tbl, s.Dialect) && (ns == "" || ident.Equal(t.Namespace, ns, s.Dialect)) {
+			return t
+		}
+	}
+	return nil
+}
+
+// Apply updates the schema with one statement. CREATE TABLE, ALTER TABLE,
+// DROP TABLE, CREATE INDEX and DROP INDEX are understood; anything else,
+// and references to tables the schema does not know, leave it unchanged.
+func (s *Schema) Apply(stmt Statement) {
+	switch st := stmt.(type) {
+	case *ast.CreateTableStmt:
+		s.applyCreateTable(st)
+	case *ast.AlterTableStmt:
+		if t := s.lookup(st.Table); t != nil {
+			for _, cmd := range st.Cmds {
+				t.applyAlter(cmd)
+			}
+		}
+	case *ast.DropTableStmt:
+		for _, q := range st.Tables {
+			if t := s.lookup(q); t != nil {
+				s.remove(t)
+			}
+		}
+	case *ast.CreateIndexStmt:
+		if t := s.lookup(st.Table); t != nil {
+			t.Indexes = append(t.Indexes, &Index{
+				Name:      defaultIndexName(st),
+				Columns:   indexColNames(st.Columns),
+				Kind:      st.Type,
+				Method:    string(st.Method),
+				Include:   identNames(st.Include),
+				Options:   append(slices.Clip(st.StorageParams), st.Options...),
+				Invisible: st.Invisible,
+				Where:     st.Where,
+			})
+		}
+	case *ast.DropIndexStmt:
+		for _, t := range s.Tables {
+			if st.Table != nil && s.lookup(st.Table) != t {
+				continue
+			}
+			t.dropIndex(identName(st.Name))
+		}
+	}
+}
+
+func (s *Schema) lookup(q *ast.QualifiedIdent) *Table {
+	if q == nil || len(q.Parts) == 0 {
+		return nil
+	}
+	ns, name := splitQualified(q)
+	for _, t := range s.Tables {
+		if ident.Equal(t.Name, name, s.Dialect) && (ns == "" || t.Namespace == "" || ident.Equal(t.Namespace, ns, s.Dialect)) {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *Schema) remove(t *Table) {
+	for i, cur := range s.Tables {
+		if cur == t {
+			s.Tables = append(s.Tables[:i], s.Tables[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *Schema) applyCreateTable(st *ast.CreateTableStmt) {
+	if existing := s.lookup(st.Table); existing != nil {
+		if st.IfNotExists {
+			return
+		}
+		s.remove(existing)
+	}
+	t := &Table{dialect: s.Dialect}
+	t.Namespace, t.Name = splitQualified(st.Table)
+	if st.Like != nil {
+		if src := s.lookup(st.Like); src != nil {
+			t.Columns = make([]*Column, len(src.Columns))
+			for i, c := range src.Columns {
+				cp := *c
+				t.Columns[i] = &cp
+			}
+			t.PrimaryKey = append(t.PrimaryKey, src.PrimaryKey...)
+			for _, idx := range src.Indexes {
+				cp := *idx
+				t.Indexes = append(t.Indexes, &cp)
+			}
+			t.Checks = append(t.Checks, src.Checks...)
+		}
+		s.Tables = append(s.Tables, t)
+		return
+	}
+	// PostgreSQL INHERITS: parent columns come first; a column the child
+	// declares again is merged into the inherited one.
+	for _, q := range st.Inherits {
+		if parent := s.lookup(q); parent != nil {
+			for _, c := range parent.Columns {
+				if t.Column(c.Name) == nil {
+					cp := *c
+					t.Columns = append(t.Columns, &cp)
+				}
+			}
+		}
+	}
+	for _, cd := range st.Columns {
+		at := len(t.Columns)
+		if i := t.columnIndex(identName(cd.Name)); i >= 0 {
+			t.Columns = append(t.Columns[:i], t.Columns[i+1:]...)
+			at = i
+		}
+		t.addColumn(cd, at)
+	}
+	for _, c := range st.Constraints {
+		t.addConstraint(c)
+	}
+	t.Options = append(t.Options, st.Options...)
+	s.Tables = append(s.Tables, t)
+}
+
+func (t *Table) applyAlter(cmd ast.AlterCmd) {
+	switch c := cmd.(type) {
+	case *ast.AddColumnCmd:
+		t.addColumn(c.Col, t.position(c.First, c.After, len(t.Columns)))
+	case *ast.ModifyColumnCmd:
+		name := identName(c.Col.Name)
+		if c.OldName != nil {
+			old := identName(c.OldName)
+			t.renameColumn(old, name)
+			name = old
+		}
+		at := t.columnIndex(name)
+		if at < 0 {
+			return
+		}
+		t.Columns = append(t.Columns[:at], t.Columns[at+1:]...)
+		t.addColumn(c.Col, t.position(c.First, c.After, at))
+	case *ast.AlterColumnCmd:
+		col := t.Column(identName(c.Name))
+		if col == nil {
+			return
+		}
+		switch c.Action {
+		case ast.AlterColumnType:
+			col.Type = strings.ToUpper(string(c.Type.Name))
+			col.Precision, col.Scale, col.Unsigned = c.Type.Precision, c.Type.Scale, c.Type.Unsigned
+			col.EnumValues = nil
+			for _, v := range c.Type.EnumVals {
+				col.EnumValues = append(col.EnumValues, unquoteString(v, t.dialect))
+			}
+		case ast.AlterColumnSetNotNull:
+			col.Nullable = false
+		case ast.AlterColumnDropNotNull:
+			col.Nullable = true
+		case ast.AlterColumnSetDefault:
+			col.Default = c.Default
+		case ast.AlterColumnDropDefault:
+			col.Default = nil
+		}
+	case *ast.DropColumnCmd:
+		name := identName(c.Name)
+		if at := t.columnIndex(name); at >= 0 {
+			t.Columns = append(t.Columns[:at], t.Columns[at+1:]...)
+		}
+		t.PrimaryKey = removeName(t.PrimaryKey, name, t.dialect)
+	case *ast.AddConstraintCmd:
+		t.addConstraint(c.Constraint)
+	case *ast.DropIndexCmd:
+		t.dropIndex(identName(c.Name))
+	case *ast.RenameTableCmd:
+		t.Namespace, t.Name = splitQualified(c.NewName)
+	}
+}
+
+// Column returns the column with the given name, or nil. Names are
+// matched as the dialect of the schema that created t compares them.
+func (t *Table) Column(name string) *Column {
+	if i := t.columnIndex(name); i >= 0 {
+		return t.Columns[i]
+	}
+	return nil
+}
+
+func (t *Table) columnIndex(name string) int {
+	for i, c := range t.Columns {
+		if ident.Equal(c.Name, name, t.dialect) {
+			return i
+		}
+	}
+	return -1
+}
+
+// position resolves FIRST / AFTER col into an insertion index.
+func (t *Table) position(first bool, after *ast.Ident, def int) int {
+	if first {
+		return 0
+	}
+	if after != nil {
+		if i := t.columnIndex(identName(after)); i >= 0 {
+			return i + 1
+		}
+	}
+	return def
+}
+
+func (t *Table) addColumn(cd *ast.ColumnDef, at int) {
+	col := &Column{
+		Name:          identName(cd.Name),
+		Nullable:      !cd.NotNull && !cd.PrimaryKey,
+		Default:       cd.Default,
+		AutoIncrement: cd.AutoIncrement,
+		Generated:     cd.Generated != nil,
+	}
+	if dt := cd.Type; dt != nil {
+		col.Type = strings.ToUpper(string(dt.Name))
+		col.Precision = dt.Precision
+		col.Scale = dt.Scale
+		col.Unsigned = dt.Unsigned
+		for _, v := range dt.EnumVals {
+			col.EnumValues = append(col.EnumValues, unquoteString(v, t.dialect))
+		}
+	}
+	if cd.Comment != nil {
+		col.Comment = unquoteString(cd.Comment.Raw, t.dialect)
+	}
+	at = min(max(at, 0), len(t.Columns))
+	t.Columns = append(t.Columns, nil)
+	copy(t.Columns[at+1:], t.Columns[at:])
+	t.Columns[at] = col
+
+	if cd.PrimaryKey {
+		t.PrimaryKey = []string{col.Name}
+	}
+	if cd.Unique {
+		t.Indexes = append(t.Indexes, &Index{Columns: []string{col.Name}, Kind: ast.UniqueConstraint})
+	}
+	if cd.Check != nil {
+		t.Checks = append(t.Checks, cd.Check)
+	}
+	if ref := cd.References; ref != nil {
+		_, refTable := splitQualified(ref.Table)
+		t.ForeignKeys = append(t.ForeignKeys, &ForeignKey{
+			Columns:           []string{col.Name},
+			RefTable:          refTable,
+			RefColumns:        identNames(ref.Columns),
+			OnDelete:          ref.OnDelete,
+			OnUpdate:          ref.OnUpdate,
+			Match:             ref.Match,
+			Deferrable:        ref.Deferrable,
+			InitiallyDeferred: ref.InitiallyDeferred,
+		})
+	}
+}
+
+func (t *Table) addConstraint(c *ast.TableConstraint) {
+	switch c.Type {
+	case ast.PrimaryKeyConstraint:
+		t.PrimaryKey = indexColNames(c.Columns)
+		for _, name := range t.PrimaryKey {
+			if col := t.Column(name); col != nil {
+				col.Nullable = false
+			}
+		}
+	case ast.ForeignKeyConstraint:
+		_, refTable := splitQualified(c.RefTable)
+		t.ForeignKeys = append(t.ForeignKeys, &ForeignKey{
+			Name:              identName(c.Name),
+			Columns:           indexColNames(c.Columns),
+			RefTable:          refTable,
+			RefColumns:        identNames(c.RefCols),
+			OnDelete:          c.OnDelete,
+			OnUpdate:          c.OnUpdate,
+			Match:             c.Match,
+			Deferrable:        c.Deferrable,
+			InitiallyDeferred: c.InitiallyDeferred,
+		})
+	case ast.CheckConstraint:
+		if c.Check != nil {
+			t.Checks = append(t.Checks, c.Check)
+		}
+	default:
+		t.Indexes = append(t.Indexes, &Index{Name: identName(c.Name), Columns: indexColNames(c.Columns), Kind: c.Type})
+	}
+}
+
+func (t *Table) dropIndex(name string) {
+	for i, idx := range t.Indexes {
+		if idx.Name != "" && ident.Equal(idx.Name, name, t.dialect) {
+			t.Indexes = append(t.Indexes[:i], t.Indexes[i+1:]...)
+			return
+		}
+	}
+	for i, fk := range t.ForeignKeys {
+		if fk.Name != "" && ident.Equal(fk.Name, name, t.dialect) {
+			t.ForeignKeys = append(t.ForeignKeys[:i], t.ForeignKeys[i+1:]...)
+			return
+		}
+	}
+}
+
+func splitQualified(q *ast.QualifiedIdent) (ns, name string) {
+	if q == nil || len(q.Parts) == 0 {
+		return "", ""
+	}
+	name = identName(q.Parts[len(q.Parts)-1])
+	if len(q.Parts) > 1 {
+		ns = identName(q.Parts[len(q.Parts)-2])
+	}
+	return ns, name
+}
+
+func identNames(ids []*ast.Ident) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = identName(id)
+	}
+	return out
+}
+
+// indexColNames returns the column names of an index, and the expression
+// of a functional key part in parentheses.
+func indexColNames(cols []*ast.IndexColDef) []string {
+	out := make([]string, len(cols))
+	for i, c := range cols {
+		if c.Expr != nil {
+			out[i] = "(" + newDialectRenderer(ConvertOptions{}).renderExpr(c.Expr) + ")"
+			continue
+		}
+		out[i] = identName(c.Name)
+	}
+	return out
+}
+
+func removeName(names []string, name string, dialect Dialect) []string {
+	out := names[:0]
+	for _, n := range names {
+		if !ident.Equal(n, name, dialect) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// renameColumn renames the column old in the keys and indexes that
+// name it.
+func (t *Table) renameColumn(old, name string) {
+	rename := func(names []string) {
+		for i, n := range names {
+			if ident.Equal(n, old, t.dialect) {
+				names[i] = name
+			}
+		}
+	}
+	rename(t.PrimaryKey)
+	for _, idx := range t.Indexes {
+		rename(idx.Columns)
+		rename(idx.Include)
+	}
+	for _, fk := range t.ForeignKeys {
+		rename(fk.Columns)
+	}
+}
+
+// unquoteString returns the value of a quoted string literal written in
+// dialect, decoded as renderString decodes it: backslashes are escapes
+// except in the standard strings of PostgreSQL and SQLite. A literal that
+// cannot be decoded is returned as written.
+func unquoteString(raw []byte, dialect Dialect) string {
+	v, ok := lexer.DecodeString(raw, dialect != DialectPostgres && dialect != DialectSQLite)
+	if !ok {
+		return string(raw)
+	}
+	return v
+}
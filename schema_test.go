@@ -0,0 +1,171 @@
+package sqlparser_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestBuildSchemaInherits(t *testing.T) {
+	s, err := sqlparser.BuildSchema(`
+CREATE TABLE base (id INT NOT NULL, created_at TIMESTAMP);
+CREATE TABLE child (note TEXT, created_at TIMESTAMP NOT NULL) INHERITS (base)`)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	child := s.Table("child")
+	var cols []string
+	for _, c := range child.Columns {
+		cols = append(cols, c.Name)
+	}
+	if !reflect.DeepEqual(cols, []string{"id", "created_at", "note"}) {
+		t.Fatalf("unexpected child columns %v", cols)
+	}
+	if child.Column("created_at").Nullable {
+		t.Fatalf("child's own definition should win over the inherited one")
+	}
+}
+
+func TestBuildSchemaIndexDetails(t *testing.T) {
+	s, err := sqlparser.BuildSchema(`
+CREATE TABLE orders (id INT PRIMARY KEY, user_id INT, total INT, status TEXT);
+CREATE INDEX orders_open ON orders USING btree (user_id) INCLUDE (total) WITH (fillfactor = 70) WHERE status = 'open';
+CREATE INDEX orders_total ON orders (total) KEY_BLOCK_SIZE = 8 INVISIBLE`)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	idx := s.Table("orders").Indexes
+	if len(idx) != 2 {
+		t.Fatalf("expected two indexes, got %+v", idx)
+	}
+	open := idx[0]
+	if open.Method != "btree" || !reflect.DeepEqual(open.Include, []string{"total"}) || len(open.Options) != 1 || open.Where == nil || open.Invisible {
+		t.Fatalf("unexpected partial covering index %+v", open)
+	}
+	if total := idx[1]; !total.Invisible || string(total.Options[0].Key) != "KEY_BLOCK_SIZE" || total.Where != nil {
+		t.Fatalf("unexpected invisible index %+v", total)
+	}
+}
+
+func TestBuildSchemaReplaysDDL(t *testing.T) {
+	s, err := sqlparser.BuildSchema(`
+CREATE TABLE users (id BIGINT AUTO_INCREMENT, email VARCHAR(255) NOT NULL UNIQUE, PRIMARY KEY (id));
+CREATE TABLE orders (id INT PRIMARY KEY, user_id BIGINT REFERENCES users (id), total DECIMAL(10,2));
+ALTER TABLE users ADD COLUMN name TEXT AFTER id, DROP COLUMN email;
+CREATE INDEX idx_orders_user ON orders (user_id);
+DROP TABLE IF EXISTS missing;
+CREATE TABLE tmp (x INT);
+DROP TABLE tmp;
+SELECT 1`)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if len(s.Tables) != 2 {
+		t.Fatalf("expected users and orders, got %d tables", len(s.Tables))
+	}
+	users := s.Table("USERS")
+	if users == nil {
+		t.Fatalf("users table not found")
+	}
+	var cols []string
+	for _, c := range users.Columns {
+		cols = append(cols, c.Name)
+	}
+	if !reflect.DeepEqual(cols, []string{"id", "name"}) {
+		t.Fatalf("unexpected users columns %v", cols)
+	}
+	if id := users.Column("id"); id.Nullable || !id.AutoIncrement || id.Type != "BIGINT" {
+		t.Fatalf("unexpected id column %+v", id)
+	}
+	if !reflect.DeepEqual(users.PrimaryKey, []string{"id"}) {
+		t.Fatalf("unexpected primary key %v", users.PrimaryKey)
+	}
+
+	orders := s.Table("orders")
+	if len(orders.ForeignKeys) != 1 || orders.ForeignKeys[0].RefTable != "users" {
+		t.Fatalf("expected foreign key to users, got %+v", orders.ForeignKeys)
+	}
+	if len(orders.Indexes) != 1 || orders.Indexes[0].Name != "idx_orders_user" {
+		t.Fatalf("expected idx_orders_user, got %+v", orders.Indexes)
+	}
+	if total := orders.Column("total"); !total.Nullable || total.Precision != 10 || total.Scale != 2 {
+		t.Fatalf("unexpected total column %+v", total)
+	}
+}
+
+func TestBuildSchemaAlterColumn(t *testing.T) {
+	s, err := sqlparser.BuildSchema(`
+CREATE TABLE users (id INT PRIMARY KEY, email VARCHAR(100), plan TEXT NOT NULL DEFAULT 'free');
+CREATE INDEX users_email ON users (email);
+ALTER TABLE users ALTER COLUMN id TYPE BIGINT, ALTER COLUMN email SET NOT NULL, ALTER plan DROP NOT NULL, ALTER plan DROP DEFAULT;
+ALTER TABLE users CHANGE COLUMN email mail VARCHAR(255) NOT NULL`)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	users := s.Table("users")
+	if id := users.Column("id"); id.Type != "BIGINT" {
+		t.Errorf("id not retyped: %+v", id)
+	}
+	if plan := users.Column("plan"); !plan.Nullable || plan.Default != nil {
+		t.Errorf("plan still NOT NULL or defaulted: %+v", plan)
+	}
+	if users.Column("email") != nil {
+		t.Errorf("email not renamed")
+	}
+	if mail := users.Column("mail"); mail == nil || mail.Nullable || mail.Precision != 255 {
+		t.Errorf("unexpected mail column %+v", mail)
+	}
+	if !reflect.DeepEqual(users.Indexes[0].Columns, []string{"mail"}) {
+		t.Errorf("index not renamed: %v", users.Indexes[0].Columns)
+	}
+}
+
+func TestBuildSchemaNameCase(t *testing.T) {
+	const ddl = `
+CREATE TABLE "Users" (id INT, "Email" TEXT);
+CREATE TABLE users (id INT);
+ALTER TABLE users ADD COLUMN email TEXT`
+	pg, err := sqlparser.BuildSchemaForDialect(ddl, sqlparser.DialectPostgres)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if len(pg.Tables) != 2 || pg.Table("Users") == pg.Table("users") {
+		t.Fatalf("postgres: quoted \"Users\" and users should be two tables, got %d", len(pg.Tables))
+	}
+	if pg.Table("Users").Column("email") != nil || pg.Table("users").Column("email") == nil {
+		t.Fatalf("postgres: the ALTER should reach users only")
+	}
+
+	my, err := sqlparser.BuildSchemaForDialect(ddl, sqlparser.DialectMySQL)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if len(my.Tables) != 1 || my.Table("USERS").Column("EMAIL") == nil {
+		t.Fatalf("mysql: names should match regardless of case, got %d tables", len(my.Tables))
+	}
+}
+
+func TestBuildSchemaStringsByDialect(t *testing.T) {
+	my, err := sqlparser.BuildSchemaForDialect(`CREATE TABLE t (kind ENUM('a\'b', 'c\\d') COMMENT 'it\'s\ta')`, sqlparser.DialectMySQL)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	col := my.Table("t").Column("kind")
+	if want := []string{"a'b", `c\d`}; !reflect.DeepEqual(col.EnumValues, want) || col.Comment != "it's\ta" {
+		t.Fatalf("mysql: got enum %q and comment %q", col.EnumValues, col.Comment)
+	}
+
+	pg, err := sqlparser.BuildSchemaForDialect(`CREATE TABLE t (kind TEXT DEFAULT 'C:\dir\')`, sqlparser.DialectPostgres)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	out, err := pg.JSONSchema(sqlparser.JSONSchemaDraft2020)
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if !strings.Contains(string(out), `"default": "C:\\dir\\"`) {
+		t.Fatalf("postgres: default not read as a standard string: %s", out)
+	}
+}
@@ -0,0 +1,331 @@
+package sqlparser
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// seqColumn is the column a sequence numbers.
+type seqColumn struct {
+	table  *ast.QualifiedIdent
+	column *ast.Ident
+}
+
+// collectSequences maps every sequence the script ties to a column, by a
+// DEFAULT nextval('seq') or by a SERIAL type's implicit table_column_seq,
+// to that column. Targets without sequences turn those columns into
+// AUTO_INCREMENT columns and use the map to rewrite statements that
+// address the sequence.
+func collectSequences(stmts []Statement) map[string]seqColumn {
+	m := map[string]seqColumn{}
+	add := func(table *ast.QualifiedIdent, c *ast.ColumnDef) {
+		if seq, ok := nextvalSequence(c.Default); ok {
+			m[seq] = seqColumn{table, c.Name}
+		}
+		if c.Type != nil && isSerialType(c.Type) && len(table.Parts) > 0 {
+			implicit := table.Parts[len(table.Parts)-1].Unquoted + "_" + c.Name.Unquoted + "_seq"
+			m[sequenceKey(implicit)] = seqColumn{table, c.Name}
+		}
+	}
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *ast.CreateTableStmt:
+			for _, c := range s.Columns {
+				add(s.Table, c)
+			}
+		case *ast.AlterTableStmt:
+			for _, cmd := range s.Cmds {
+				switch c := cmd.(type) {
+				case *ast.AddColumnCmd:
+					add(s.Table, c.Col)
+				case *ast.ModifyColumnCmd:
+					add(s.Table, c.Col)
+				}
+			}
+		}
+	}
+	return m
+}
+
+// sequenceKey normalizes a sequence name for lookups: the last dotted part,
+// unquoted and lower-cased, since nextval() names it in a string that may
+// carry a schema.
+func sequenceKey(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.ToLower(strings.Trim(name, `"`))
+}
+
+// sequenceFunc returns the lower-case name of a call to a sequence or
+// last-insert-id function, accepting PostgreSQL's pg_catalog qualifier.
+func sequenceFunc(e *ast.FuncCall) string {
+	if e.Name == nil || len(e.Name.Parts) == 0 || len(e.Name.Parts) > 2 {
+		return ""
+	}
+	if len(e.Name.Parts) == 2 && !strings.EqualFold(e.Name.Parts[0].Unquoted, "pg_catalog") {
+		return ""
+	}
+	return strings.ToLower(e.Name.Parts[len(e.Name.Parts)-1].Unquoted)
+}
+
+// nextvalSequence reports the sequence a nextval('seq') call draws from.
+func nextvalSequence(e ast.Expr) (string, bool) {
+	call, ok := e.(*ast.FuncCall)
+	if !ok || sequenceFunc(call) != "nextval" || len(call.Args) != 1 {
+		return "", false
+	}
+	return sequenceArg(call.Args[0])
+}
+
+// sequenceArg returns the sequence a string names, as in nextval('seq'),
+// or the 'seq'::regclass pg_dump writes.
+func sequenceArg(e ast.Expr) (string, bool) {
+	if c, ok := e.(*ast.CastExpr); ok && c.Type != nil && strings.EqualFold(string(c.Type.Name), "regclass") {
+		e = c.Expr
+	}
+	lit, ok := e.(*ast.Literal)
+	if !ok || lit.Kind != lexer.STRING {
+		return "", false
+	}
+	return sequenceKey(unquoteString(lit.Raw, "")), true
+}
+
+func isSerialType(dt *ast.DataType) bool {
+	switch strings.ToLower(string(dt.Name)) {
+	case "serial", "bigserial", "smallserial", "serial4", "serial8", "serial2":
+		return true
+	}
+	return false
+}
+
+// serialBaseType is the integer type a SERIAL type stands for.
+func serialBaseType(name string) string {
+	switch strings.ToLower(name) {
+	case "bigserial", "serial8":
+		return "BIGINT"
+	case "smallserial", "serial2":
+		return "SMALLINT"
+	}
+	return "INT"
+}
+
+// autoIncrementStart returns the value of MySQL's AUTO_INCREMENT table
+// option when the table has an AUTO_INCREMENT column, for the identity
+// column PostgreSQL gets instead.
+func autoIncrementStart(s *ast.CreateTableStmt) []byte {
+	if !slices.ContainsFunc(s.Columns, func(c *ast.ColumnDef) bool { return c.AutoIncrement }) {
+		return nil
+	}
+	for _, opt := range s.Options {
+		if strings.EqualFold(string(opt.Key), "AUTO_INCREMENT") {
+			return opt.Value
+		}
+	}
+	return nil
+}
+
+// renderSequenceFunc maps nextval, currval, lastval and the last insert id
+// functions between dialects. It reports false to leave the call as it is.
+func (r *dialectRenderer) renderSequenceFunc(e *ast.FuncCall) (string, bool) {
+	name := sequenceFunc(e)
+	if r.target == DialectPostgres {
+		if (name == "last_insert_id" || name == "last_insert_rowid") && len(e.Args) == 0 {
+			return "lastval()", true
+		}
+		return "", false
+	}
+	lastID := "LAST_INSERT_ID()"
+	if r.target == DialectSQLite {
+		lastID = "last_insert_rowid()"
+	}
+	switch name {
+	case "lastval", "last_insert_id", "last_insert_rowid":
+		if len(e.Args) == 0 {
+			return lastID, true
+		}
+	case "currval":
+		r.warn(WarnSequenceAsAutoIncrement, e.TokPos, "currval() became %s, which returns the last id generated for any table in the session", lastID)
+		return lastID, true
+	case "nextval":
+		seq, _ := nextvalSequence(e)
+		if col, ok := r.sequences[seq]; ok {
+			r.warn(WarnSequenceAsAutoIncrement, e.TokPos, "nextval('%s') became NULL so the AUTO_INCREMENT of %s.%s assigns the value", seq, qualifiedName(col.table), col.column.Unquoted)
+			return "NULL", true
+		}
+		r.warn(WarnSequenceUnsupported, e.TokPos, "%s has no sequences; nextval() was emitted unchanged", r.target)
+	case "setval":
+		r.warn(WarnSequenceUnsupported, e.TokPos, "%s has no sequences; setval() was emitted unchanged", r.target)
+	}
+	return "", false
+}
+
+// renderSetval rewrites a standalone SELECT setval('seq', n [, is_called])
+// on a sequence that became an AUTO_INCREMENT into the target's way of
+// moving the counter. It reports false for any other statement.
+func (r *dialectRenderer) renderSetval(s *ast.SelectStmt) (string, bool) {
+	if r.target == DialectPostgres || len(s.From) > 0 || s.Where != nil || s.SetOp != nil || len(s.Columns) != 1 {
+		return "", false
+	}
+	call, ok := s.Columns[0].Expr.(*ast.FuncCall)
+	if !ok || sequenceFunc(call) != "setval" || len(call.Args) < 2 || len(call.Args) > 3 {
+		return "", false
+	}
+	seq, ok := sequenceArg(call.Args[0])
+	col, mapped := r.sequences[seq]
+	lit, isInt := call.Args[1].(*ast.Literal)
+	if !ok || !mapped || !isInt || lit.Kind != lexer.INT {
+		return "", false
+	}
+	last, err := strconv.ParseInt(string(lit.Raw), 10, 64)
+	if err != nil {
+		return "", false
+	}
+	if len(call.Args) == 3 {
+		called, ok := call.Args[2].(*ast.Literal)
+		switch {
+		case !ok:
+			return "", false
+		case called.Kind == lexer.FALSE_KW:
+			last-- // the next nextval() returns n itself
+		case called.Kind != lexer.TRUE_KW:
+			return "", false
+		}
+	}
+	return r.setAutoIncrement(col, last), true
+}
+
+// setAutoIncrement moves the AUTO_INCREMENT counter of col so that the
+// next generated value is last+1.
+func (r *dialectRenderer) setAutoIncrement(col seqColumn, last int64) string {
+	if r.target == DialectSQLite {
+		name := quoteSQLString(col.table.Parts[len(col.table.Parts)-1].Unquoted)
+		return "DELETE FROM sqlite_sequence WHERE name = " + name +
+			"; INSERT INTO sqlite_sequence (name, seq) VALUES (" + name + ", " + strconv.FormatInt(last, 10) + ")"
+	}
+	return "ALTER TABLE " + r.renderQualifiedIdent(col.table) + " AUTO_INCREMENT = " + strconv.FormatInt(last+1, 10)
+}
+
+func (r *dialectRenderer) renderCreateSequence(s *ast.CreateSequenceStmt) string {
+	if r.target != DialectPostgres {
+		if col, ok := r.sequences[sequenceKey(s.Name.Parts[len(s.Name.Parts)-1].Unquoted)]; ok {
+			r.warn(WarnSequenceAsAutoIncrement, s.TokPos, "sequence %s became the AUTO_INCREMENT of %s.%s; its options were dropped", qualifiedName(s.Name), qualifiedName(col.table), col.column.Unquoted)
+		} else {
+			r.warn(WarnSequenceUnsupported, s.TokPos, "%s has no sequences; CREATE SEQUENCE %s was omitted", r.target, qualifiedName(s.Name))
+		}
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("CREATE ")
+	if s.Temporary {
+		b.WriteString("TEMPORARY ")
+	}
+	b.WriteString("SEQUENCE ")
+	if s.IfNotExists {
+		b.WriteString("IF NOT EXISTS ")
+	}
+	b.WriteString(r.renderQualifiedIdent(s.Name))
+	r.writeSequenceOptions(&b, &s.Options)
+	return b.String()
+}
+
+func (r *dialectRenderer) renderAlterSequence(s *ast.AlterSequenceStmt) string {
+	if r.target != DialectPostgres {
+		col, ok := r.sequences[sequenceKey(s.Name.Parts[len(s.Name.Parts)-1].Unquoted)]
+		o := &s.Options
+		other := o.Type != nil || o.Increment != nil || o.MinValue != nil || o.MaxValue != nil || o.NoMinValue || o.NoMaxValue ||
+			o.Start != nil || o.Cache != nil || o.Cycle || o.NoCycle || o.OwnedByNone
+		switch {
+		case ok && !other && o.RestartWith != nil:
+			if n, err := strconv.ParseInt(string(o.RestartWith), 10, 64); err == nil {
+				return r.setAutoIncrement(col, n-1)
+			}
+		case ok && !other && !o.Restart:
+			// OWNED BY only: the AUTO_INCREMENT column already owns it.
+			return ""
+		}
+		r.warn(WarnSequenceUnsupported, s.TokPos, "%s has no sequences; ALTER SEQUENCE %s was omitted", r.target, qualifiedName(s.Name))
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("ALTER SEQUENCE ")
+	if s.IfExists {
+		b.WriteString("IF EXISTS ")
+	}
+	b.WriteString(r.renderQualifiedIdent(s.Name))
+	r.writeSequenceOptions(&b, &s.Options)
+	return b.String()
+}
+
+func (r *dialectRenderer) renderDropSequence(s *ast.DropSequenceStmt) string {
+	if r.target != DialectPostgres {
+		r.warn(WarnSequenceUnsupported, s.TokPos, "%s has no sequences; DROP SEQUENCE was omitted", r.target)
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("DROP SEQUENCE ")
+	if s.IfExists {
+		b.WriteString("IF EXISTS ")
+	}
+	for i, name := range s.Names {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(r.renderQualifiedIdent(name))
+	}
+	if s.Cascade {
+		b.WriteString(" CASCADE")
+	}
+	return b.String()
+}
+
+// writeSequenceOptions writes the sequence clauses in PostgreSQL's
+// canonical order and spelling.
+func (r *dialectRenderer) writeSequenceOptions(b *strings.Builder, o *ast.SequenceOptions) {
+	if o.Type != nil {
+		b.WriteString(" AS " + r.renderDataType(o.Type))
+	}
+	if o.Increment != nil {
+		b.WriteString(" INCREMENT BY " + string(o.Increment))
+	}
+	switch {
+	case o.MinValue != nil:
+		b.WriteString(" MINVALUE " + string(o.MinValue))
+	case o.NoMinValue:
+		b.WriteString(" NO MINVALUE")
+	}
+	switch {
+	case o.MaxValue != nil:
+		b.WriteString(" MAXVALUE " + string(o.MaxValue))
+	case o.NoMaxValue:
+		b.WriteString(" NO MAXVALUE")
+	}
+	if o.Start != nil {
+		b.WriteString(" START WITH " + string(o.Start))
+	}
+	if o.Restart {
+		b.WriteString(" RESTART")
+		if o.RestartWith != nil {
+			b.WriteString(" WITH " + string(o.RestartWith))
+		}
+	}
+	if o.Cache != nil {
+		b.WriteString(" CACHE " + string(o.Cache))
+	}
+	switch {
+	case o.Cycle:
+		b.WriteString(" CYCLE")
+	case o.NoCycle:
+		b.WriteString(" NO CYCLE")
+	}
+	switch {
+	case o.OwnedBy != nil:
+		b.WriteString(" OWNED BY " + r.renderQualifiedIdent(o.OwnedBy))
+	case o.OwnedByNone:
+		b.WriteString(" OWNED BY NONE")
+	}
+}
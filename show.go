@@ -0,0 +1,128 @@
+package sqlparser
+
+import (
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// mysqlShows are the SHOW statements of MySQL; in PostgreSQL, SHOW only
+// reads a setting.
+var mysqlShows = map[string]bool{
+	"tables": true, "databases": true, "schemas": true, "columns": true, "fields": true,
+	"index": true, "indexes": true, "keys": true, "create": true, "variables": true,
+	"status": true, "processlist": true, "grants": true, "warnings": true, "errors": true,
+	"engines": true, "triggers": true, "events": true, "privileges": true, "table": true,
+	"charset": true, "collation": true, "plugins": true, "master": true, "slave": true,
+	"replica": true, "binary": true, "open": true, "procedure": true, "function": true,
+	"engine": true, "character": true, "storage": true, "binlog": true, "relaylog": true,
+}
+
+// pgColumns are the columns of information_schema.columns that stand in
+// for MySQL's DESCRIBE and SHOW COLUMNS.
+const pgColumns = "column_name, data_type, is_nullable, column_default"
+
+func (r *dialectRenderer) renderShow(s *ast.ShowStmt) (string, error) {
+	if r.target == DialectPostgres && mysqlShows[strings.ToLower(string(s.What))] {
+		if out, ok := r.pgShowQuery(s); ok {
+			r.warn(WarnShowRewritten, s.TokPos, "SHOW %s became a catalog query; its result columns differ from MySQL's", strings.ToUpper(string(s.What)))
+			return out, nil
+		}
+		r.warn(WarnShowUnsupported, s.TokPos, "SHOW %s has no %s equivalent and was emitted unchanged", strings.ToUpper(string(s.What)), r.target)
+	}
+	var b strings.Builder
+	b.WriteString("SHOW ")
+	if s.Scope != nil {
+		b.WriteString(strings.ToUpper(string(s.Scope)) + " ")
+	}
+	if s.Full {
+		b.WriteString("FULL ")
+	}
+	b.WriteString(string(s.What))
+	if s.Engine != nil {
+		b.WriteString(" " + string(s.Engine))
+	}
+	if s.Detail != nil {
+		b.WriteString(" " + string(s.Detail))
+	}
+	if s.User != nil {
+		b.WriteString(" FOR " + string(s.User))
+	}
+	if s.Object != nil {
+		b.WriteString(" " + strings.ToUpper(string(s.Object)) + " " + r.renderQualifiedIdent(s.Name))
+	} else if s.Name != nil {
+		b.WriteString(" FROM " + r.renderQualifiedIdent(s.Name))
+	}
+	if s.Database != nil {
+		b.WriteString(" FROM " + r.renderIdent(s.Database))
+	}
+	if s.Like != nil {
+		b.WriteString(" LIKE " + r.renderExpr(s.Like))
+	}
+	if s.Where != nil {
+		b.WriteString(" WHERE " + r.renderExpr(s.Where))
+	}
+	return b.String(), nil
+}
+
+// pgShowQuery rewrites SHOW TABLES, DATABASES, COLUMNS and INDEX as
+// PostgreSQL catalog queries, reading MySQL databases as schemas. A WHERE
+// clause is kept, but names MySQL's result columns.
+func (r *dialectRenderer) pgShowQuery(s *ast.ShowStmt) (string, bool) {
+	var conds []string
+	filter := func(col string) {
+		if s.Like != nil {
+			conds = append(conds, col+" LIKE "+r.renderExpr(s.Like))
+		}
+		if s.Where != nil {
+			conds = append(conds, "("+r.renderExpr(s.Where)+")")
+		}
+	}
+	switch strings.ToLower(string(s.What)) {
+	case "tables":
+		cols := "table_name"
+		if s.Full {
+			cols += ", table_type"
+		}
+		conds = append(conds, "table_schema = "+catalogSchema(nil, s.Database))
+		filter("table_name")
+		return catalogQuery(cols, "information_schema.tables", conds, "table_name"), true
+	case "databases", "schemas":
+		filter("schema_name")
+		return catalogQuery("schema_name", "information_schema.schemata", conds, "schema_name"), true
+	case "columns", "fields":
+		conds = append(conds, "table_schema = "+catalogSchema(s.Name, s.Database), "table_name = "+catalogTable(s.Name))
+		filter("column_name")
+		return catalogQuery(pgColumns, "information_schema.columns", conds, "ordinal_position"), true
+	case "index", "indexes", "keys":
+		conds = append(conds, "schemaname = "+catalogSchema(s.Name, s.Database), "tablename = "+catalogTable(s.Name))
+		filter("indexname")
+		return catalogQuery("indexname, indexdef", "pg_indexes", conds, "indexname"), true
+	}
+	return "", false
+}
+
+// catalogSchema returns the schema of table, or db, as a string literal
+// for a catalog query, defaulting to the current schema.
+func catalogSchema(table *ast.QualifiedIdent, db *ast.Ident) string {
+	if table != nil && len(table.Parts) > 1 {
+		return quoteSQLString(table.Parts[len(table.Parts)-2].Unquoted)
+	}
+	if db != nil {
+		return quoteSQLString(db.Unquoted)
+	}
+	return "current_schema()"
+}
+
+// catalogTable returns the unqualified name of table as a string literal.
+func catalogTable(table *ast.QualifiedIdent) string {
+	return quoteSQLString(table.Parts[len(table.Parts)-1].Unquoted)
+}
+
+func catalogQuery(cols, from string, conds []string, order string) string {
+	out := "SELECT " + cols + " FROM " + from
+	if len(conds) > 0 {
+		out += " WHERE " + strings.Join(conds, " AND ")
+	}
+	return out + " ORDER BY " + order
+}
@@ -0,0 +1,54 @@
+package sqlparser_test
+
+import (
+	"slices"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestConvertShow(t *testing.T) {
+	tests := []struct {
+		in     string
+		target sqlparser.Dialect
+		want   string
+		codes  []string
+	}{
+		{"SHOW FULL TABLES FROM shop LIKE 'o%'", sqlparser.DialectMySQL, "SHOW FULL TABLES FROM `shop` LIKE 'o%'", nil},
+		{"SHOW CREATE TABLE shop.orders", sqlparser.DialectMySQL, "SHOW CREATE TABLE `shop`.`orders`", nil},
+		{"SHOW TABLES", sqlparser.DialectPostgres,
+			"SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() ORDER BY table_name",
+			[]string{sqlparser.WarnShowRewritten}},
+		{"SHOW FULL TABLES IN shop LIKE 'o%'", sqlparser.DialectPostgres,
+			"SELECT table_name, table_type FROM information_schema.tables WHERE table_schema = 'shop' AND table_name LIKE 'o%' ORDER BY table_name",
+			[]string{sqlparser.WarnShowRewritten}},
+		{"SHOW DATABASES", sqlparser.DialectPostgres, "SELECT schema_name FROM information_schema.schemata ORDER BY schema_name",
+			[]string{sqlparser.WarnShowRewritten}},
+		{"SHOW COLUMNS FROM shop.orders LIKE 'c%'", sqlparser.DialectPostgres,
+			"SELECT column_name, data_type, is_nullable, column_default FROM information_schema.columns " +
+				"WHERE table_schema = 'shop' AND table_name = 'orders' AND column_name LIKE 'c%' ORDER BY ordinal_position",
+			[]string{sqlparser.WarnShowRewritten}},
+		{"SHOW INDEX FROM orders", sqlparser.DialectPostgres,
+			"SELECT indexname, indexdef FROM pg_indexes WHERE schemaname = current_schema() AND tablename = 'orders' ORDER BY indexname",
+			[]string{sqlparser.WarnShowRewritten}},
+		{"SHOW CREATE TABLE orders", sqlparser.DialectPostgres, `SHOW CREATE TABLE "orders"`, []string{sqlparser.WarnShowUnsupported}},
+		{"SHOW search_path", sqlparser.DialectPostgres, "SHOW search_path", nil},
+		{"SHOW TABLE STATUS FROM shop LIKE 'o%'", sqlparser.DialectMySQL, "SHOW TABLE STATUS FROM `shop` LIKE 'o%'", nil},
+		{"SHOW ENGINE INNODB STATUS", sqlparser.DialectMySQL, "SHOW ENGINE INNODB STATUS", nil},
+		{"SHOW GRANTS FOR 'app'@'%'", sqlparser.DialectMySQL, "SHOW GRANTS FOR 'app'@'%'", nil},
+		{"SHOW ENGINE INNODB STATUS", sqlparser.DialectPostgres, "SHOW ENGINE INNODB STATUS", []string{sqlparser.WarnShowUnsupported}},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(tt.in, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: convert failed: %v", tt.in, err)
+		}
+		var codes []string
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+		}
+		if out != tt.want || !slices.Equal(codes, tt.codes) {
+			t.Errorf("%s -> %s:\n got %s %v\nwant %s %v", tt.in, tt.target, out, codes, tt.want, tt.codes)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package sqlparser
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// spatialShapes map the lower-cased names of MySQL's spatial types, which
+// are also the subtypes of a PostGIS GEOMETRY or GEOGRAPHY, to the
+// spelling PostGIS gives them.
+var spatialShapes = map[string]string{
+	"geometry": "Geometry", "point": "Point", "linestring": "LineString", "polygon": "Polygon",
+	"multipoint": "MultiPoint", "multilinestring": "MultiLineString", "multipolygon": "MultiPolygon",
+	"geometrycollection": "GeometryCollection", "geomcollection": "GeometryCollection",
+}
+
+// clickHouseShapes are the spatial shapes ClickHouse has a type for.
+var clickHouseShapes = map[string]bool{
+	"Point": true, "LineString": true, "Polygon": true, "MultiLineString": true, "MultiPolygon": true,
+}
+
+// spatialShape returns the shape of a spatial type, as spelled in
+// spatialShapes: the type itself for MySQL's, and the subtype of a GEOMETRY
+// or GEOGRAPHY, or Geometry for any shape. Subtypes with Z or M
+// coordinates, such as PointZ, are Geometry too. ok is false for types
+// that are not spatial.
+func spatialShape(dt *ast.DataType) (shape string, ok bool) {
+	name := strings.ToLower(string(dt.Name))
+	if name == "geography" || name == "geometry" && dt.Subtype != nil {
+		if shape, ok := spatialShapes[strings.ToLower(string(dt.Subtype))]; ok {
+			return shape, true
+		}
+		return "Geometry", true
+	}
+	shape, ok = spatialShapes[name]
+	return shape, ok
+}
+
+// spatialSRID returns the SRID of a spatial type: the one it was given,
+// or 4326, WGS 84, for a GEOGRAPHY without one, as PostGIS assumes.
+func spatialSRID(dt *ast.DataType) int {
+	if dt.SRID == 0 && strings.EqualFold(string(dt.Name), "geography") {
+		return 4326
+	}
+	return dt.SRID
+}
+
+// renderSpatialType renders a spatial type for the target, if dt is one.
+// PostgreSQL writes GEOMETRY(Point, 4326) as PostGIS does, keeping its own
+// POINT and POLYGON when they have no SRID. MySQL, which has no
+// GEOGRAPHY, writes the shape and leaves the SRID to renderColumnDef.
+// SQLite writes the shape for SpatiaLite to read, and ClickHouse its own
+// Point, Polygon and the like, storing other shapes as String.
+func (r *dialectRenderer) renderSpatialType(dt *ast.DataType) (string, bool) {
+	shape, ok := spatialShape(dt)
+	if !ok {
+		return "", false
+	}
+	name := string(dt.Name)
+	lower := strings.ToLower(name)
+	switch r.target {
+	case DialectPostgres:
+		switch {
+		case lower == "geometry" || lower == "geography":
+			if dt.Subtype == nil && dt.SRID == 0 {
+				return name, true
+			}
+			return name + postgisTypmod(dt, shape), true
+		case (lower == "point" || lower == "polygon") && dt.SRID == 0:
+			return name, true
+		}
+		return "GEOMETRY" + postgisTypmod(dt, shape), true
+	case DialectMySQL:
+		return strings.ToUpper(shape), true
+	case DialectSQLite:
+		typ := strings.ToUpper(shape)
+		r.warn(WarnSpatialExtension, dt.TokPos, "sqlite has no spatial types; %s needs the SpatiaLite extension, whose AddGeometryColumn() also records the SRID", typ)
+		return typ, true
+	case DialectClickHouse:
+		if clickHouseShapes[shape] {
+			return shape, true
+		}
+		r.warn(WarnTypeApproximated, dt.TokPos, "%s became String, as clickhouse has no such type; shapes are stored as WKT or WKB and must be parsed before the geo functions can use them", strings.ToUpper(name))
+		return "String", true
+	}
+	if dt.Subtype == nil {
+		return name, true
+	}
+	return name + postgisTypmod(dt, shape), true
+}
+
+// postgisTypmod writes the (Subtype[, srid]) of a PostGIS type, keeping a
+// subtype as written.
+func postgisTypmod(dt *ast.DataType, shape string) string {
+	if dt.Subtype != nil {
+		shape = string(dt.Subtype)
+	}
+	if dt.SRID == 0 {
+		return "(" + shape + ")"
+	}
+	return "(" + shape + ", " + strconv.Itoa(dt.SRID) + ")"
+}
+
+// isClickHouseShape reports whether dt becomes one of ClickHouse's spatial
+// types, which cannot be Nullable.
+func isClickHouseShape(dt *ast.DataType) bool {
+	shape, ok := spatialShape(dt)
+	return ok && clickHouseShapes[shape]
+}
+
+// writeSRID writes MySQL's SRID column attribute, which the generic output
+// keeps when the type had no PostGIS subtype to carry it.
+func (r *dialectRenderer) writeSRID(b *strings.Builder, dt *ast.DataType) {
+	if dt == nil {
+		return
+	}
+	if _, ok := spatialShape(dt); !ok {
+		return
+	}
+	srid := dt.SRID
+	switch {
+	case r.target == DialectMySQL:
+		srid = spatialSRID(dt)
+	case r.target != "" || dt.Subtype != nil:
+		return
+	}
+	if srid != 0 {
+		b.WriteString(" SRID " + strconv.Itoa(srid))
+	}
+}
+
+// indexKind returns the keyword a CREATE INDEX or inline index of type
+// typ starts with: UNIQUE, or MySQL's FULLTEXT and SPATIAL, which other
+// targets write as plain indexes. PostgreSQL makes a SPATIAL index a GiST
+// one; see indexMethod.
+func (r *dialectRenderer) indexKind(typ ast.ConstraintType, name *ast.Ident, pos int32) string {
+	switch typ {
+	case ast.UniqueConstraint:
+		return "UNIQUE "
+	case ast.FulltextConstraint:
+		if r.target == DialectMySQL || r.target == "" {
+			return "FULLTEXT "
+		}
+		r.warn(WarnIndexMethodDropped, pos, "%s has no FULLTEXT indexes; index %s is a plain one", r.target, identName(name))
+	case ast.SpatialConstraint:
+		switch r.target {
+		case DialectMySQL, "":
+			return "SPATIAL "
+		case DialectSQLite:
+			r.warn(WarnSpatialExtension, pos, "sqlite has no spatial indexes; index %s is a plain one, and SpatiaLite's CreateSpatialIndex() builds an R*Tree instead", identName(name))
+		case DialectClickHouse:
+			r.warn(WarnIndexMethodDropped, pos, "clickhouse has no SPATIAL indexes; index %s is a plain one", identName(name))
+		}
+	}
+	return ""
+}
+
+// checkSpatialFunc warns of a call to a spatial function, ST_Distance and
+// the like, in SQLite, which only has them with SpatiaLite loaded.
+func (r *dialectRenderer) checkSpatialFunc(fc *ast.FuncCall) {
+	if r.target != DialectSQLite || fc.Name == nil || len(fc.Name.Parts) != 1 {
+		return
+	}
+	if fn := strings.ToUpper(fc.Name.Parts[0].Unquoted); strings.HasPrefix(fn, "ST_") {
+		r.warn(WarnSpatialExtension, fc.TokPos, "%s needs the SpatiaLite extension in sqlite", fn)
+	}
+}
+
+// dropsInlineIndex reports whether a FULLTEXT or SPATIAL index of CREATE
+// TABLE is left out, as it is for targets that only create indexes with
+// CREATE INDEX. For PostgreSQL the index becomes a CREATE INDEX after the
+// table.
+func (r *dialectRenderer) dropsInlineIndex(c *ast.TableConstraint) bool {
+	if c.Type != ast.FulltextConstraint && c.Type != ast.SpatialConstraint || r.target == DialectMySQL || r.target == "" {
+		return false
+	}
+	if r.target == DialectPostgres && r.table != nil {
+		idx, _ := r.renderCreateIndex(&ast.CreateIndexStmt{Name: c.Name, Table: r.table, Columns: c.Columns, Type: c.Type, TokPos: c.TokPos})
+		r.pendingIndexes = append(r.pendingIndexes, idx)
+		return true
+	}
+	kind := "FULLTEXT"
+	if c.Type == ast.SpatialConstraint {
+		kind = "SPATIAL"
+	}
+	r.warn(WarnIndexDropped, c.TokPos, "%s has no %s indexes in CREATE TABLE; index %s was dropped and needs a CREATE INDEX of its own", r.target, kind, identName(c.Name))
+	return true
+}
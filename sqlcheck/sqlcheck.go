@@ -0,0 +1,161 @@
+// Package sqlcheck verifies SQL against a target dialect and turns parse
+// errors and analyzer findings into errors that read well in test output.
+// Its Hook has the func(query string) error shape that ORM callbacks and
+// query hooks accept, so the SQL that GORM or sqlx generates can be checked
+// in tests:
+//
+//	check := sqlcheck.Hook(sqlcheck.Config{Dialect: sqlparser.DialectPostgres})
+//	db.Callback().Query().After("gorm:query").Register("sqlcheck", func(tx *gorm.DB) {
+//		if err := check(tx.Statement.SQL.String()); err != nil {
+//			tx.AddError(err)
+//		}
+//	})
+package sqlcheck
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// Config says what a query is verified against.
+type Config struct {
+	// Dialect is the target the queries must run on. Besides the
+	// dialect's analyzer rules, whatever does not convert to it cleanly
+	// is reported under the conversion warning's code, such as
+	// "REPLACE_UNSUPPORTED".
+	Dialect sqlparser.Dialect
+	// Schema enables the analyzer rules that need column types.
+	Schema *sqlparser.Schema
+	// Rules restricts the checks to these finding codes. Nil keeps every
+	// finding; PARSE_ERROR is always kept.
+	Rules []string
+	// Fail is the lowest severity that fails a query. Empty means
+	// SeverityWarning.
+	Fail sqlparser.FindingSeverity
+}
+
+// Hook returns a function that returns a *Error for a query that fails
+// Check, and nil otherwise.
+func Hook(cfg Config) func(query string) error {
+	return cfg.Check
+}
+
+// Check parses and analyzes query and returns a *Error with its findings
+// of the failing severity or worse, or nil when it has none.
+func (c Config) Check(query string) error {
+	report := sqlparser.AnalyzeSQLWithOptions(query, sqlparser.AnalysisOptions{Dialect: c.Dialect, Schema: c.Schema})
+	if !report.Valid {
+		return FromFindings(query, report.Findings)
+	}
+	findings := report.Findings
+	if c.Dialect != "" {
+		_, warnings, err := sqlparser.ConvertDialectWithOptions(query, sqlparser.ConvertOptions{Target: c.Dialect})
+		for _, w := range warnings {
+			findings = append(findings, finding(query, sqlparser.SeverityWarning, w))
+		}
+		if err != nil {
+			w := sqlparser.ConversionWarning{Code: "CONVERSION_ERROR", Message: err.Error()}
+			var ce *sqlparser.ConversionError
+			if errors.As(err, &ce) {
+				w = ce.Warning
+			}
+			findings = append(findings, finding(query, sqlparser.SeverityCritical, w))
+		}
+	}
+	fail := c.Fail
+	if fail == "" {
+		fail = sqlparser.SeverityWarning
+	}
+	findings = slices.DeleteFunc(findings, func(f sqlparser.AnalysisFinding) bool {
+		return f.Severity.Rank() < fail.Rank() || c.Rules != nil && !slices.Contains(c.Rules, f.Code)
+	})
+	return FromFindings(query, findings)
+}
+
+func finding(query string, sev sqlparser.FindingSeverity, w sqlparser.ConversionWarning) sqlparser.AnalysisFinding {
+	f := sqlparser.AnalysisFinding{Severity: sev, Code: w.Code, Message: w.Message, Problem: w.Message, Pos: w.Pos}
+	f.Line, f.Col = lexer.ComputeLineCol([]byte(query), int(w.Pos))
+	return f
+}
+
+// Error is a query that failed verification, with the findings that
+// failed it.
+type Error struct {
+	Query    string
+	Findings []sqlparser.AnalysisFinding
+}
+
+// Error lists one finding per line with its position, code and problem,
+// followed by the query.
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString("sqlcheck: query failed verification:")
+	for _, f := range e.Findings {
+		fmt.Fprintf(&b, "\n  %d:%d %s (%s): %s", f.Line, f.Col, f.Code, f.Severity, f.Problem)
+		if f.Recommendation != "" {
+			b.WriteString(" " + f.Recommendation)
+		}
+	}
+	b.WriteString("\n  query: " + e.Query)
+	return b.String()
+}
+
+// Codes returns the codes of the findings, for assertions in tests.
+func (e *Error) Codes() []string {
+	codes := make([]string, len(e.Findings))
+	for i, f := range e.Findings {
+		codes[i] = f.Code
+	}
+	return codes
+}
+
+// FromFindings returns a *Error for findings of query, such as those of an
+// AnalysisReport, or nil when there are none.
+func FromFindings(query string, findings []sqlparser.AnalysisFinding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	return &Error{Query: query, Findings: findings}
+}
+
+// FromError converts the error of parsing or converting query, a
+// *ParseError, ParseErrors or *ConversionError, into a *Error with one
+// finding per problem. Other errors are returned unchanged.
+func FromError(query string, err error) error {
+	var (
+		findings []sqlparser.AnalysisFinding
+		pes      sqlparser.ParseErrors
+		pe       *sqlparser.ParseError
+		ce       *sqlparser.ConversionError
+	)
+	switch {
+	case errors.As(err, &pes):
+		for _, pe := range pes {
+			findings = append(findings, parseFinding(pe))
+		}
+	case errors.As(err, &pe):
+		findings = append(findings, parseFinding(pe))
+	case errors.As(err, &ce):
+		findings = append(findings, finding(query, sqlparser.SeverityCritical, ce.Warning))
+	default:
+		return err
+	}
+	return &Error{Query: query, Findings: findings}
+}
+
+func parseFinding(pe *sqlparser.ParseError) sqlparser.AnalysisFinding {
+	return sqlparser.AnalysisFinding{
+		Severity: sqlparser.SeverityCritical,
+		Code:     "PARSE_ERROR",
+		Message:  pe.Error(),
+		Problem:  pe.Error(),
+		Pos:      pe.Pos,
+		Line:     pe.Line,
+		Col:      pe.Col,
+	}
+}
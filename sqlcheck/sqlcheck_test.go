@@ -0,0 +1,67 @@
+package sqlcheck_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+	"github.com/oarkflow/sqlparser/sqlcheck"
+)
+
+func TestHook(t *testing.T) {
+	check := sqlcheck.Hook(sqlcheck.Config{Dialect: sqlparser.DialectPostgres})
+	if err := check(`SELECT "id", "name" FROM "users" WHERE "id" = $1`); err != nil {
+		t.Errorf("valid query: %v", err)
+	}
+
+	tests := []struct {
+		query string
+		codes []string
+	}{
+		{"SELECT * FROM users", []string{"SELECT_STAR"}},
+		{"REPLACE INTO users (id) VALUES (1)", []string{"REPLACE_NOT_PORTABLE", "REPLACE_UNSUPPORTED"}},
+		{"SELECT id FROM\nusers WHERE", []string{"PARSE_ERROR"}},
+	}
+	for _, tt := range tests {
+		err := check(tt.query)
+		var ce *sqlcheck.Error
+		if !errors.As(err, &ce) {
+			t.Errorf("%s: got %v, want *sqlcheck.Error", tt.query, err)
+			continue
+		}
+		if !slices.Equal(ce.Codes(), tt.codes) {
+			t.Errorf("%s: codes %v, want %v", tt.query, ce.Codes(), tt.codes)
+		}
+	}
+
+	err := check("SELECT id FROM\nusers WHERE")
+	if msg := err.Error(); !strings.Contains(msg, "2:12 PARSE_ERROR (critical)") || !strings.HasSuffix(msg, "query: SELECT id FROM\nusers WHERE") {
+		t.Errorf("unexpected message:\n%s", msg)
+	}
+
+	onlyWrites := sqlcheck.Hook(sqlcheck.Config{Rules: []string{"DELETE_WITHOUT_WHERE"}})
+	if err := onlyWrites("SELECT * FROM users"); err != nil {
+		t.Errorf("filtered rule: %v", err)
+	}
+	if err := onlyWrites("DELETE FROM users"); err == nil {
+		t.Error("DELETE without WHERE passed")
+	}
+}
+
+func TestFromError(t *testing.T) {
+	query := "SELECT FROM"
+	_, err := sqlparser.ParseStatements(query)
+	var ce *sqlcheck.Error
+	if !errors.As(sqlcheck.FromError(query, err), &ce) || ce.Findings[0].Code != "PARSE_ERROR" || ce.Findings[0].Line != 1 {
+		t.Errorf("got %#v", ce)
+	}
+	other := errors.New("boom")
+	if got := sqlcheck.FromError(query, other); got != other {
+		t.Errorf("got %v, want the error unchanged", got)
+	}
+	if sqlcheck.FromFindings(query, nil) != nil {
+		t.Error("no findings should be no error")
+	}
+}
@@ -0,0 +1,267 @@
+// Package sqldriver wraps a database/sql driver so that every statement is
+// parsed and analyzed before it reaches the database. Queries with findings
+// are reported to a callback and, above a configured severity, blocked, which
+// enforces SQL rules without changing any call site:
+//
+//	sql.Register("postgres-checked", sqldriver.Wrap(&pq.Driver{}, sqldriver.Config{
+//		Analysis: sqlparser.AnalysisOptions{Dialect: sqlparser.DialectPostgres},
+//		Block:    sqlparser.SeverityCritical,
+//	}))
+//	db, err := sql.Open("postgres-checked", dsn)
+package sqldriver
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"slices"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+// Config says which findings matter and what happens to the queries that
+// have them.
+type Config struct {
+	Analysis sqlparser.AnalysisOptions
+	// Rules restricts the checks to these finding codes, such as
+	// "SELECT_STAR" or "UPDATE_WITHOUT_WHERE". Nil keeps every finding;
+	// PARSE_ERROR is always kept.
+	Rules []string
+	// Block is the lowest severity that blocks a query with a *BlockedError
+	// before it is sent. Empty never blocks, so findings are only reported.
+	Block sqlparser.FindingSeverity
+	// BlockUnparsed also blocks queries the parser rejects, such as
+	// vendor syntax it does not cover. Without it they are reported with
+	// a PARSE_ERROR finding and sent anyway.
+	BlockUnparsed bool
+	// OnFindings, when set, is called with every query that has findings,
+	// blocked or not, e.g. to log or count them.
+	OnFindings func(ctx context.Context, query string, findings []sqlparser.AnalysisFinding)
+}
+
+// BlockedError is returned instead of running a query that has a finding
+// of the blocking severity.
+type BlockedError struct {
+	Query string
+	// Finding is the first finding that blocked the query.
+	Finding sqlparser.AnalysisFinding
+	// Findings are all of the query's findings, not only the blocking ones.
+	Findings []sqlparser.AnalysisFinding
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("sqldriver: query blocked by %s: %s", e.Finding.Code, e.Finding.Problem)
+}
+
+// check analyzes query and returns a *BlockedError if it must not run.
+func (c *Config) check(ctx context.Context, query string) error {
+	report := sqlparser.AnalyzeSQLWithOptions(query, c.Analysis)
+	findings := report.Findings
+	if c.Rules != nil {
+		findings = slices.DeleteFunc(findings, func(f sqlparser.AnalysisFinding) bool {
+			return f.Code != "PARSE_ERROR" && !slices.Contains(c.Rules, f.Code)
+		})
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+	if c.OnFindings != nil {
+		c.OnFindings(ctx, query, findings)
+	}
+	if !report.Valid {
+		if c.BlockUnparsed {
+			return &BlockedError{Query: query, Finding: findings[0], Findings: findings}
+		}
+		return nil
+	}
+	if c.Block == "" {
+		return nil
+	}
+	for _, f := range findings {
+		if f.Severity.Rank() >= c.Block.Rank() {
+			return &BlockedError{Query: query, Finding: f, Findings: findings}
+		}
+	}
+	return nil
+}
+
+// Wrap returns a driver that checks every statement before d runs it. It
+// is meant for sql.Register; use WrapConnector with sql.OpenDB instead when
+// the driver hands out a connector.
+func Wrap(d driver.Driver, cfg Config) driver.Driver {
+	return &wrappedDriver{parent: d, cfg: &cfg}
+}
+
+// WrapConnector returns a connector whose connections check every
+// statement before c's connections run it.
+func WrapConnector(c driver.Connector, cfg Config) driver.Connector {
+	return &connector{parent: c, drv: &wrappedDriver{parent: c.Driver(), cfg: &cfg}}
+}
+
+type wrappedDriver struct {
+	parent driver.Driver
+	cfg    *Config
+}
+
+func (d *wrappedDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.parent.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{parent: c, cfg: d.cfg}, nil
+}
+
+func (d *wrappedDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.parent.(driver.DriverContext); ok {
+		c, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+		return &connector{parent: c, drv: d}, nil
+	}
+	return &dsnConnector{dsn: name, drv: d}, nil
+}
+
+type connector struct {
+	parent driver.Connector
+	drv    *wrappedDriver
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	pc, err := c.parent.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{parent: pc, cfg: c.drv.cfg}, nil
+}
+
+func (c *connector) Driver() driver.Driver { return c.drv }
+
+// dsnConnector is the connector of a driver without DriverContext, as
+// database/sql builds it for such drivers itself.
+type dsnConnector struct {
+	dsn string
+	drv *wrappedDriver
+}
+
+func (c *dsnConnector) Connect(context.Context) (driver.Conn, error) { return c.drv.Open(c.dsn) }
+
+func (c *dsnConnector) Driver() driver.Driver { return c.drv }
+
+// conn checks statements on their way to the parent connection. Queries
+// and execs the parent cannot run directly return driver.ErrSkip unchecked,
+// so database/sql prepares them and they are checked once, in Prepare.
+type conn struct {
+	parent driver.Conn
+	cfg    *Config
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := c.cfg.check(ctx, query); err != nil {
+		return nil, err
+	}
+	if pc, ok := c.parent.(driver.ConnPrepareContext); ok {
+		return pc.PrepareContext(ctx, query)
+	}
+	return c.parent.Prepare(query)
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, hasCtx := c.parent.(driver.QueryerContext)
+	q, hasPlain := c.parent.(driver.Queryer)
+	if !hasCtx && !hasPlain {
+		return nil, driver.ErrSkip
+	}
+	if err := c.cfg.check(ctx, query); err != nil {
+		return nil, err
+	}
+	if hasCtx {
+		return qc.QueryContext(ctx, query, args)
+	}
+	values, err := plainValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return q.Query(query, values)
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, hasCtx := c.parent.(driver.ExecerContext)
+	e, hasPlain := c.parent.(driver.Execer)
+	if !hasCtx && !hasPlain {
+		return nil, driver.ErrSkip
+	}
+	if err := c.cfg.check(ctx, query); err != nil {
+		return nil, err
+	}
+	if hasCtx {
+		return ec.ExecContext(ctx, query, args)
+	}
+	values, err := plainValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return e.Exec(query, values)
+}
+
+// plainValues converts args for drivers without the context interfaces,
+// which take neither names nor cancellation.
+func plainValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		if a.Name != "" {
+			return nil, errors.New("sqldriver: driver does not support the use of Named Parameters")
+		}
+		values[i] = a.Value
+	}
+	return values, nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if bc, ok := c.parent.(driver.ConnBeginTx); ok {
+		return bc.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != 0 || opts.ReadOnly {
+		return nil, errors.New("sqldriver: driver does not support transaction options")
+	}
+	return c.parent.Begin()
+}
+
+func (c *conn) Close() error { return c.parent.Close() }
+
+func (c *conn) Ping(ctx context.Context) error {
+	if p, ok := c.parent.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if r, ok := c.parent.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *conn) IsValid() bool {
+	if v, ok := c.parent.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nc, ok := c.parent.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
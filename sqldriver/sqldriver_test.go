@@ -0,0 +1,97 @@
+package sqldriver_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+	"github.com/oarkflow/sqlparser/sqldriver"
+)
+
+// fakeDriver records the statements that reach it.
+type fakeDriver struct{ ran []string }
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{c.d, query}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("no transactions") }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.d.ran = append(c.d.ran, query)
+	return driver.RowsAffected(1), nil
+}
+
+// fakeStmt serves queries, which fakeConn cannot run directly.
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not used")
+}
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.ran = append(s.d.ran, s.query)
+	return fakeRows{}, nil
+}
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string         { return nil }
+func (fakeRows) Close() error              { return nil }
+func (fakeRows) Next([]driver.Value) error { return io.EOF }
+
+func TestWrap(t *testing.T) {
+	fake := &fakeDriver{}
+	var reported []string
+	sql.Register("sqldriver-test", sqldriver.Wrap(fake, sqldriver.Config{
+		Rules: []string{"SELECT_STAR", "UPDATE_WITHOUT_WHERE"},
+		Block: sqlparser.SeverityCritical,
+		OnFindings: func(_ context.Context, query string, findings []sqlparser.AnalysisFinding) {
+			for _, f := range findings {
+				reported = append(reported, f.Code)
+			}
+		},
+	}))
+	db, err := sql.Open("sqldriver-test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("UPDATE users SET active = FALSE WHERE id = ?", 1); err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec("UPDATE users SET active = FALSE")
+	var blocked *sqldriver.BlockedError
+	if !errors.As(err, &blocked) || blocked.Finding.Code != "UPDATE_WITHOUT_WHERE" {
+		t.Fatalf("got %v, want UPDATE_WITHOUT_WHERE block", err)
+	}
+	// Queries go through Prepare, since fakeConn has no QueryContext.
+	rows, err := db.Query("SELECT * FROM users WHERE id IN (1, 2)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+	// Unparsable queries are reported and sent.
+	if _, err := db.Exec("FROBNICATE users"); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"UPDATE users SET active = FALSE WHERE id = ?", "SELECT * FROM users WHERE id IN (1, 2)", "FROBNICATE users"}; !slices.Equal(fake.ran, want) {
+		t.Errorf("ran %q, want %q", fake.ran, want)
+	}
+	if want := []string{"UPDATE_WITHOUT_WHERE", "SELECT_STAR", "PARSE_ERROR"}; !slices.Equal(reported, want) {
+		t.Errorf("reported %v, want %v", reported, want)
+	}
+}
@@ -0,0 +1,93 @@
+package sqlparser
+
+import (
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// renderPragma renders a SQLite PRAGMA. The foreign_keys pragma maps to
+// MySQL's FOREIGN_KEY_CHECKS; other pragmas have no counterpart outside
+// SQLite and are emitted unchanged with a warning.
+func (r *dialectRenderer) renderPragma(s *ast.PragmaStmt) string {
+	name := pragmaName(s.Name)
+	if r.target == DialectMySQL && strings.EqualFold(name, "foreign_keys") {
+		if s.Value == nil {
+			return "SELECT @@FOREIGN_KEY_CHECKS"
+		}
+		if on, ok := pragmaBool(s.Value); ok {
+			if on {
+				return "SET FOREIGN_KEY_CHECKS = 1"
+			}
+			return "SET FOREIGN_KEY_CHECKS = 0"
+		}
+	}
+	if r.target != DialectSQLite {
+		r.warn(WarnPragmaUnsupported, s.TokPos, "PRAGMA %s has no %s equivalent and was emitted unchanged", name, r.target)
+	}
+	out := "PRAGMA " + name
+	switch {
+	case s.Value == nil:
+	case s.Call:
+		out += "(" + r.pragmaValue(s.Value) + ")"
+	default:
+		out += " = " + r.pragmaValue(s.Value)
+	}
+	return out
+}
+
+// renderAttach renders SQLite ATTACH / DETACH DATABASE, which no other
+// target supports.
+func (r *dialectRenderer) renderAttach(s *ast.AttachStmt) string {
+	verb := "ATTACH"
+	if s.Detach {
+		verb = "DETACH"
+	}
+	if r.target != DialectSQLite {
+		r.warn(WarnAttachUnsupported, s.TokPos, "%s DATABASE is SQLite-specific and was emitted unchanged", verb)
+	}
+	if s.Detach {
+		return "DETACH DATABASE " + s.Schema.Unquoted
+	}
+	return "ATTACH DATABASE " + r.renderExpr(s.File) + " AS " + s.Schema.Unquoted
+}
+
+// pragmaName joins the parts of a pragma name as written; pragma names are
+// never quoted.
+func pragmaName(q *ast.QualifiedIdent) string {
+	parts := make([]string, len(q.Parts))
+	for i, p := range q.Parts {
+		parts[i] = p.Unquoted
+	}
+	return strings.Join(parts, ".")
+}
+
+// pragmaValue renders a pragma value, keeping bare words such as WAL
+// unquoted.
+func (r *dialectRenderer) pragmaValue(e ast.Expr) string {
+	if id, ok := e.(*ast.Ident); ok {
+		return string(id.Raw)
+	}
+	return r.renderExpr(e)
+}
+
+// pragmaBool reads a SQLite boolean pragma value: ON/OFF, YES/NO,
+// TRUE/FALSE or 1/0.
+func pragmaBool(e ast.Expr) (on, ok bool) {
+	var word string
+	switch v := e.(type) {
+	case *ast.Ident:
+		word = v.Unquoted
+	case *ast.Literal:
+		word = strings.ToLower(string(v.Raw))
+	default:
+		return false, false
+	}
+	switch word {
+	case "on", "yes", "true", "1":
+		return true, true
+	case "off", "no", "false", "0":
+		return false, true
+	}
+	return false, false
+}
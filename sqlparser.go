@@ -12,11 +12,13 @@
 //
 //	stmt, err := sqlparser.ParseStatement("SELECT id, name FROM users WHERE id = 1")
 //	stmts, err := sqlparser.ParseStatements(sql)
-//	p := sqlparser.NewParser(src)
-//	for stmt := range p.Iter() { ... }
+//	p := sqlparser.New(src)
+//	for stmt, err := range p.Iter() { ... }
 package sqlparser
 
 import (
+	"iter"
+
 	"github.com/oarkflow/sqlparser/ast"
 	"github.com/oarkflow/sqlparser/lexer"
 	"github.com/oarkflow/sqlparser/parser"
@@ -39,9 +41,22 @@ type (
 	CallStmt           = ast.CallStmt
 	TransactionStmt    = ast.TransactionStmt
 	GenericDDLStmt     = ast.GenericDDLStmt
+	MaintenanceStmt    = ast.MaintenanceStmt
+	PragmaStmt         = ast.PragmaStmt
+	AttachStmt         = ast.AttachStmt
+	CreateSequenceStmt = ast.CreateSequenceStmt
+	AlterSequenceStmt  = ast.AlterSequenceStmt
+	DropSequenceStmt   = ast.DropSequenceStmt
+	CreateTypeStmt     = ast.CreateTypeStmt
+	CreateDomainStmt   = ast.CreateDomainStmt
+	DropTypeStmt       = ast.DropTypeStmt
 	ParseError         = parser.ParseError
+	ParseErrors        = parser.ParseErrors
+	ParseOptions       = parser.Options
+	ArenaStats         = parser.ArenaStats
 	Token              = lexer.Token
 	TokenType          = lexer.TokenType
+	LexerOptions       = lexer.Options
 )
 
 // ParseStatement parses a single SQL statement from a string.
@@ -55,10 +70,67 @@ func ParseStatements(sql string) ([]Statement, error) {
 	return parser.ParseStatements(sql)
 }
 
+// ErrArenaLimit is wrapped by the ParseError returned when a statement needs
+// more memory than ParseOptions.MaxArenaBytes allows.
+var ErrArenaLimit = parser.ErrArenaLimit
+
+// ErrDepthLimit and ErrStatementTooLong are wrapped by the ParseError
+// returned when input exceeds ParseOptions.MaxExpressionDepth or
+// ParseOptions.MaxStatementLength.
+var (
+	ErrDepthLimit       = parser.ErrDepthLimit
+	ErrStatementTooLong = parser.ErrStatementTooLong
+)
+
+// DefaultMaxExpressionDepth is the nesting limit applied when
+// ParseOptions.MaxExpressionDepth is zero.
+const DefaultMaxExpressionDepth = parser.DefaultMaxExpressionDepth
+
+// ParseResult is the outcome of ParseWithOptions.
+type ParseResult struct {
+	Statements []Statement
+	// Incomplete is set when the input ended part-way through the last
+	// statement and ParseOptions.AllowIncomplete let it through. That
+	// statement holds only what was parsed before the cut.
+	Incomplete bool
+	// Tags holds the comment tags of each statement, indexed like
+	// Statements; see StatementTags.
+	Tags []Tags
+}
+
+// ParseWithOptions parses all statements in sql using opts.
+func ParseWithOptions(sql string, opts ParseOptions) (*ParseResult, error) {
+	p := parser.NewStringWithOptions(sql, opts)
+	var stmts []Statement
+	var ends []int
+	for {
+		stmt, err := p.ParseOne()
+		if err != nil {
+			return nil, err
+		}
+		if stmt == nil {
+			break
+		}
+		stmts = append(stmts, stmt)
+		ends = append(ends, p.StatementEnd())
+	}
+	tags := tagsByEnds(sql, opts, ends)
+	if len(tags) > len(stmts) {
+		tags = tags[:len(stmts)]
+	}
+	for len(tags) < len(stmts) {
+		tags = append(tags, nil)
+	}
+	return &ParseResult{Statements: stmts, Incomplete: p.Incomplete(), Tags: tags}, nil
+}
+
 // Parser is a reusable, stateful SQL parser.
 // Reuse a Parser across calls to amortise arena allocations.
 type Parser struct {
 	p *parser.Parser
+	// gen counts the resets, so a Result can tell that its statements'
+	// memory was reused.
+	gen uint64
 }
 
 // New creates a Parser backed by the given SQL bytes.
@@ -71,19 +143,72 @@ func NewString(src string) *Parser {
 	return &Parser{p: parser.NewString(src)}
 }
 
+// NewWithOptions creates a Parser backed by the given SQL bytes using opts.
+func NewWithOptions(src []byte, opts ParseOptions) *Parser {
+	return &Parser{p: parser.NewWithOptions(src, opts)}
+}
+
+// AcquireParser returns a Parser from a process-wide pool that reuses arenas
+// and lexers. Give it input with Reset and return it with ReleaseParser once
+// no statement it produced is in use:
+//
+//	p := sqlparser.AcquireParser()
+//	defer sqlparser.ReleaseParser(p)
+//	p.Reset(src)
+//	stmt, err := p.Next()
+func AcquireParser() *Parser {
+	return &Parser{p: parser.Acquire()}
+}
+
+// ReleaseParser returns p to the pool. Any use of p afterwards, including a
+// second ReleaseParser, panics; statements it produced must not be used
+// either.
+func ReleaseParser(p *Parser) {
+	inner := p.parser()
+	p.p = nil
+	parser.Release(inner)
+}
+
+func (p *Parser) parser() *parser.Parser {
+	if p.p == nil {
+		panic("sqlparser: Parser used after ReleaseParser")
+	}
+	return p.p
+}
+
 // Reset reuses the Parser with new input, reusing internal allocations.
+// Statements it returned before must no longer be used, unless a Result
+// retained or detached them.
 func (p *Parser) Reset(src []byte) {
-	p.p.Reset(src)
+	p.parser().Reset(src)
+	p.gen++
 }
 
 // Next returns the next statement or (nil, nil) at EOF.
 func (p *Parser) Next() (Statement, error) {
-	return p.p.ParseOne()
+	return p.parser().ParseOne()
 }
 
 // All parses all remaining statements.
 func (p *Parser) All() ([]Statement, error) {
-	return p.p.ParseAll()
+	return p.parser().ParseAll()
+}
+
+// Iter returns an iterator over the remaining statements. Iteration stops
+// after the first error, which is yielded with a nil statement.
+func (p *Parser) Iter() iter.Seq2[Statement, error] {
+	return p.parser().Iter()
+}
+
+// ArenaStats reports the Parser's current arena memory use.
+func (p *Parser) ArenaStats() ArenaStats {
+	return p.parser().ArenaStats()
+}
+
+// Incomplete reports whether the last statement returned was cut off by the
+// end of input. See ParseOptions.AllowIncomplete.
+func (p *Parser) Incomplete() bool {
+	return p.parser().Incomplete()
 }
 
 // Tokenize breaks a SQL string into tokens.
@@ -95,3 +220,10 @@ func (p *Parser) All() ([]Statement, error) {
 func Tokenize(src []byte, buf []Token) []Token {
 	return lexer.Tokenize(src, buf)
 }
+
+// TokenizeWithOptions is Tokenize with explicit lexer options. Set
+// EmitComments and EmitWhitespace to get a lossless token stream for syntax
+// highlighters and formatters.
+func TokenizeWithOptions(src []byte, buf []Token, opts LexerOptions) []Token {
+	return lexer.TokenizeWithOptions(src, buf, opts)
+}
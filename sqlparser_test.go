@@ -0,0 +1,151 @@
+package sqlparser_test
+
+import (
+	"runtime"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+func TestAcquireReleaseParser(t *testing.T) {
+	p := sqlparser.AcquireParser()
+	p.Reset([]byte("SELECT id FROM users"))
+	stmt, err := p.Next()
+	if err != nil || stmt == nil {
+		t.Fatalf("unexpected result: %v, %v", stmt, err)
+	}
+	sqlparser.ReleaseParser(p)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic when using a released parser")
+		}
+	}()
+	p.Reset([]byte("SELECT 1"))
+}
+
+func TestReleaseParserTwicePanics(t *testing.T) {
+	p := sqlparser.AcquireParser()
+	sqlparser.ReleaseParser(p)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic on double release")
+		}
+	}()
+	sqlparser.ReleaseParser(p)
+}
+
+func TestResultLifetime(t *testing.T) {
+	const sql = "SELECT u.id, COUNT(*) AS n FROM users u JOIN orders o ON o.user_id = u.id WHERE u.name LIKE 'a%' GROUP BY u.id; " +
+		"CREATE TABLE t (id INT PRIMARY KEY, tags TEXT[], g GEOMETRY(Point, 4326), KEY idx (id)); " +
+		"INSERT INTO t (id) VALUES (?), (2) ON CONFLICT (id) DO UPDATE SET id = excluded.id"
+	want, err := sqlparser.ParseStatements(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	same := func(name string, got []sqlparser.Statement) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s: got %d statements, want %d", name, len(got), len(want))
+		}
+		for i := range got {
+			if !ast.Equal(got[i], want[i], ast.EqualOptions{}) {
+				t.Errorf("%s: statement %d differs: %v", name, i, ast.Diff(got[i], want[i], ast.EqualOptions{}))
+			}
+		}
+	}
+
+	p := sqlparser.AcquireParser()
+	p.Reset([]byte(sql))
+	stale, err := p.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	same("fresh", stale.Statements())
+
+	p.Reset([]byte(sql))
+	retained, err := p.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	retained.Retain()
+	if stale.Valid() {
+		t.Error("a Result from before Reset is still valid")
+	}
+
+	src := []byte(sql)
+	p.Reset(src)
+	detached, err := p.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	detached.Detach()
+	for i := range src {
+		src[i] = 'x'
+	}
+	p.Reset([]byte("SELECT other FROM elsewhere WHERE x = 'zzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz'"))
+	if _, err := p.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	sqlparser.ReleaseParser(p)
+	same("retained", retained.Statements())
+	same("detached", detached.Statements())
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic using a Result after Reset")
+		}
+	}()
+	stale.Statements()
+}
+
+// checkRewriteSurvivesGC parses src, rewrites it, collects garbage and
+// refills the freed memory before rendering, and fails unless it renders
+// as the rewrite of a heap copy does: a heap node linked into a
+// statement's arena memory, which the collector does not scan, is freed
+// under it.
+func checkRewriteSurvivesGC(t *testing.T, src string, rewrite func([]sqlparser.Statement) []sqlparser.Statement) {
+	t.Helper()
+	render := func(stmts []sqlparser.Statement) string {
+		got, _, err := sqlparser.ConvertStatements(stmts, sqlparser.ConvertOptions{})
+		if err != nil {
+			t.Fatalf("convert: %v", err)
+		}
+		return got
+	}
+	parse := func() []sqlparser.Statement {
+		stmts, err := sqlparser.ParseStatements(src)
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		return stmts
+	}
+	heap := parse()
+	for i, stmt := range heap {
+		heap[i] = ast.Clone(stmt)
+	}
+	want := render(rewrite(heap))
+	for i := 0; i < 20; i++ {
+		stmts := rewrite(parse())
+		runtime.GC()
+		runtime.GC()
+		junk := &ast.Literal{Raw: []byte("junk")}
+		keep := make([]any, 0, 8192)
+		for i := 0; i < cap(keep)/2; i++ {
+			b := make([]byte, 8+i%64)
+			for j := range b {
+				b[j] = 0xA5
+			}
+			e := make([]ast.Expr, 1+i%8)
+			for j := range e {
+				e[j] = junk
+			}
+			keep = append(keep, b, e)
+		}
+		runtime.KeepAlive(keep)
+		if got := render(stmts); got != want {
+			t.Fatalf("rewrite changed after GC:\ngot  %s\nwant %s", got, want)
+		}
+	}
+}
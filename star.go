@@ -0,0 +1,103 @@
+package sqlparser
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// renderStarExcept renders * EXCEPT (...) or table.* EXCEPT (...), which
+// only BigQuery and DuckDB have. The generic target keeps it; for the
+// others the star is expanded to the remaining columns when
+// ConvertOptions.Schema lists every table it covers.
+func (r *dialectRenderer) renderStarExcept(c ast.SelectColumn, from []ast.TableRef) string {
+	star := "*"
+	if ts, ok := c.Expr.(*ast.TableStar); ok {
+		star = r.renderQualifiedIdent(ts.Table) + ".*"
+	}
+	if r.target == "" {
+		names := make([]string, len(c.Except))
+		for i, id := range c.Except {
+			names[i] = r.renderIdent(id)
+		}
+		return star + " EXCEPT (" + strings.Join(names, ", ") + ")"
+	}
+	if cols, ok := r.expandStar(c, from); ok {
+		return strings.Join(cols, ", ")
+	}
+	r.warn(WarnStarExceptDropped, c.Expr.Pos(), "%s EXCEPT is not supported by %s and the schema does not list the columns it covers; every column is selected", star, r.target)
+	return star
+}
+
+// starSource is a FROM item a star expands over: the name its columns are
+// qualified with and its table in the schema.
+type starSource struct {
+	qualifier *ast.QualifiedIdent
+	table     *Table
+}
+
+// expandStar lists the columns a star column with exclusions selects, or
+// reports false when a FROM item it covers is not a table of the schema or
+// a USING or NATURAL join merges columns.
+func (r *dialectRenderer) expandStar(c ast.SelectColumn, from []ast.TableRef) ([]string, bool) {
+	if r.schema == nil {
+		return nil, false
+	}
+	var sources []starSource
+	ok := true
+	var flatten func(ast.TableRef)
+	flatten = func(ref ast.TableRef) {
+		switch t := ref.(type) {
+		case *ast.SimpleTable:
+			src := starSource{qualifier: t.Name, table: r.schema.lookup(t.Name)}
+			if t.Alias != nil {
+				src.qualifier = &ast.QualifiedIdent{Parts: []*ast.Ident{t.Alias}}
+			}
+			sources = append(sources, src)
+		case *ast.JoinTable:
+			if len(t.Using) > 0 || t.Kind == ast.NaturalJoin {
+				ok = false
+			}
+			flatten(t.Left)
+			flatten(t.Right)
+		default:
+			sources = append(sources, starSource{})
+		}
+	}
+	for _, ref := range from {
+		flatten(ref)
+	}
+	qualify := len(sources) > 1
+	if ts, isTable := c.Expr.(*ast.TableStar); isTable {
+		name := qualifiedName(ts.Table)
+		i := slices.IndexFunc(sources, func(s starSource) bool {
+			return s.qualifier != nil && (strings.EqualFold(qualifiedName(s.qualifier), name) ||
+				strings.EqualFold(s.qualifier.Parts[len(s.qualifier.Parts)-1].Unquoted, name))
+		})
+		if i < 0 {
+			return nil, false
+		}
+		sources, ok, qualify = []starSource{{qualifier: ts.Table, table: sources[i].table}}, true, true
+	}
+	if !ok || len(sources) == 0 {
+		return nil, false
+	}
+	var cols []string
+	for _, src := range sources {
+		if src.table == nil {
+			return nil, false
+		}
+		for _, col := range src.table.Columns {
+			if slices.ContainsFunc(c.Except, func(id *ast.Ident) bool { return strings.EqualFold(id.Unquoted, col.Name) }) {
+				continue
+			}
+			out := r.renderIdent(&ast.Ident{Unquoted: col.Name})
+			if qualify {
+				out = r.renderQualifiedIdent(src.qualifier) + "." + out
+			}
+			cols = append(cols, out)
+		}
+	}
+	return cols, len(cols) > 0
+}
@@ -0,0 +1,308 @@
+package sqlparser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"iter"
+
+	"github.com/oarkflow/sqlparser/lexer"
+	"github.com/oarkflow/sqlparser/parser"
+)
+
+// StreamParser parses semicolon-separated statements from an io.Reader one
+// at a time, so arbitrarily large dumps and migration files can be processed
+// in constant memory.
+//
+// The input buffer and AST arena are reused between statements: a returned
+// Statement is only valid until the next call to Next or the next iteration
+// step. Copy out anything that must outlive it.
+type StreamParser struct {
+	r   *bufio.Reader
+	buf []byte
+	p   *parser.Parser
+
+	// Position of the next unread byte, used to report errors relative to
+	// the whole stream rather than the current statement.
+	off  int32
+	line uint32
+	col  uint32
+
+	// Position of the first byte of the statement last read.
+	stmtOff  int32
+	stmtLine uint32
+	stmtCol  uint32
+
+	err error // sticky read error
+
+	opts ParseOptions
+
+	// delim is the statement delimiter set by a DELIMITER line, or nil
+	// while it is the semicolon.
+	delim []byte
+}
+
+// NewStreamParser creates a StreamParser reading from r.
+func NewStreamParser(r io.Reader) *StreamParser {
+	return NewStreamParserWithOptions(r, ParseOptions{})
+}
+
+// NewStreamParserWithOptions creates a StreamParser reading from r that
+// parses each statement with opts. Under MaxStatementLength a statement
+// stops being buffered at the first token past the limit: the rest of it
+// is skipped and Next reports a ParseError wrapping ErrStatementTooLong.
+// As in the parser, comments and whitespace around the statement are not
+// counted, so they are still buffered.
+func NewStreamParserWithOptions(r io.Reader, opts ParseOptions) *StreamParser {
+	return &StreamParser{
+		r:    bufio.NewReader(r),
+		p:    parser.NewWithOptions(nil, opts),
+		line: 1,
+		col:  1,
+		opts: opts,
+	}
+}
+
+// Next returns the next statement or (nil, nil) at end of input. A parse
+// error only affects the statement it occurs in; the following call resumes
+// with the next statement.
+func (s *StreamParser) Next() (Statement, error) {
+	for {
+		if s.err != nil {
+			if s.err == io.EOF {
+				return nil, nil
+			}
+			return nil, s.err
+		}
+		off, line, col := s.off, s.line, s.col
+		s.stmtOff, s.stmtLine, s.stmtCol = off, line, col
+		s.readStatement()
+		if s.err != nil && s.err != io.EOF {
+			return nil, s.err
+		}
+		s.p.Reset(s.buf)
+		s.p.SetDelimiter(s.delim)
+		stmt, err := s.p.ParseOne()
+		if err != nil {
+			var pe *parser.ParseError
+			if errors.As(err, &pe) {
+				pe.Pos += off
+				if pe.Line == 1 {
+					pe.Col += col - 1
+				}
+				pe.Line += line - 1
+			}
+			return nil, err
+		}
+		if stmt != nil {
+			return stmt, nil
+		}
+	}
+}
+
+// Iter returns an iterator over the statements in the stream. Unlike
+// Parser.Iter, parse errors are yielded and iteration continues with the
+// next statement; it only stops early on a read error.
+func (s *StreamParser) Iter() iter.Seq2[Statement, error] {
+	return func(yield func(Statement, error) bool) {
+		for {
+			stmt, err := s.Next()
+			if stmt == nil && err == nil {
+				return
+			}
+			if !yield(stmt, err) {
+				return
+			}
+			if err != nil && s.err != nil && s.err != io.EOF {
+				return
+			}
+		}
+	}
+}
+
+// placeFindings moves findings about the statement last read, the idx-th
+// of the stream, from the statement to the stream, as Next does with
+// parse errors.
+func (s *StreamParser) placeFindings(findings []AnalysisFinding, idx int) {
+	setFindingLines(findings, string(s.buf))
+	for i := range findings {
+		f := &findings[i]
+		f.StatementIndex += idx
+		f.Pos += s.stmtOff
+		if f.Line == 1 {
+			f.Col += s.stmtCol - 1
+		}
+		f.Line += s.stmtLine - 1
+	}
+}
+
+// readStatement fills s.buf with the bytes up to the next semicolon that is
+// not inside a quoted string, quoted identifier or comment. The semicolon
+// itself is consumed but not stored. After a mysql client DELIMITER line,
+// such as DELIMITER $$, statements end at the new delimiter instead, and
+// the line itself is returned as a statement of its own.
+//
+// Under MaxStatementLength, bytes past the first token that ends beyond
+// the limit are read but not stored, so the parser fails on that token
+// without the whole statement being held in memory.
+func (s *StreamParser) readStatement() {
+	s.buf = s.buf[:0]
+	var quote byte   // active quote delimiter, or 0
+	escapes := false // a backslash in the active quote escapes the next byte
+	lineComment, blockComment := false, false
+	var prev byte
+	limit := s.opts.MaxStatementLength
+	start := -1   // offset in s.buf of the statement's first token
+	over := false // a token ran past limit; the rest is skipped
+	for {
+		c, ok := s.readByte()
+		if !ok {
+			return
+		}
+		cur := c
+		inComment := lineComment || blockComment
+		switch {
+		case lineComment:
+			lineComment = c != '\n'
+		case blockComment:
+			if prev == '*' && c == '/' {
+				blockComment = false
+				cur = 0 // "*/*" must not reopen a comment
+			}
+		case quote != 0:
+			if c == '\\' && escapes {
+				// Keep the escaped byte verbatim without its special meaning.
+				if !over {
+					s.buf = append(s.buf, c)
+				}
+				if c, ok = s.readByte(); !ok {
+					return
+				}
+			} else if c == quote {
+				quote = 0 // a doubled quote simply reopens on the next byte
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			escapes = c != '`' && (!s.opts.StandardStrings || c == '\'' && (prev == 'e' || prev == 'E'))
+		case c == '-' && prev == '-':
+			lineComment = true
+		case c == '#':
+			if next, err := s.r.Peek(1); err != nil || next[0] != '>' {
+				lineComment = true
+			}
+		case c == '*' && prev == '/':
+			blockComment = true
+			cur = 0 // "/*/" must not close the comment
+		case (c == 'd' || c == 'D') && s.atDelimiterLine():
+			s.buf = append(s.buf, c)
+			s.readDelimiterLine()
+			return
+		case s.delim == nil && c == ';':
+			return
+		case over && s.delim != nil && c == s.delim[0] && s.atDelimiter():
+			return
+		case !over && s.delim != nil && c == s.delim[len(s.delim)-1] && bytes.HasSuffix(append(s.buf, c), s.delim):
+			s.buf = s.buf[:len(s.buf)-len(s.delim)+1]
+			return
+		}
+		prev = cur
+		if over {
+			continue
+		}
+		if limit > 0 && (quote != 0 || !inComment && !lineComment && !blockComment && !s.insignificant(c)) {
+			if start < 0 {
+				start = len(s.buf)
+			} else if len(s.buf) >= start+limit {
+				over = true
+			}
+		}
+		s.buf = append(s.buf, c)
+	}
+}
+
+// insignificant reports whether c, read outside quotes and comments, is
+// not part of a token: it is whitespace, opens a comment or starts the
+// statement delimiter.
+func (s *StreamParser) insignificant(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '\f', '\v':
+		return true
+	case '-':
+		next, err := s.r.Peek(1)
+		return err == nil && next[0] == '-'
+	case '/':
+		// The body of a MySQL version comment is SQL when it is executed.
+		next, err := s.r.Peek(2)
+		return len(next) > 0 && next[0] == '*' &&
+			!(s.opts.ExecuteVersionComments && err == nil && next[1] == '!')
+	}
+	return s.delim != nil && c == s.delim[0] && s.peekDelimiter()
+}
+
+// peekDelimiter reports whether the delimiter starting with the byte just
+// read is followed by the rest of it.
+func (s *StreamParser) peekDelimiter() bool {
+	next, err := s.r.Peek(len(s.delim) - 1)
+	return err == nil && bytes.Equal(next, s.delim[1:])
+}
+
+// atDelimiter consumes the rest of the delimiter starting with the byte
+// just read, if it follows.
+func (s *StreamParser) atDelimiter() bool {
+	if !s.peekDelimiter() {
+		return false
+	}
+	for range len(s.delim) - 1 {
+		s.readByte()
+	}
+	return true
+}
+
+// atDelimiterLine reports whether the d just read starts a DELIMITER line:
+// it is followed by "elimiter" and a space, and only whitespace and
+// comments precede it in the statement.
+func (s *StreamParser) atDelimiterLine() bool {
+	next, err := s.r.Peek(9)
+	if err != nil || !bytes.EqualFold(next[:8], []byte("elimiter")) || next[8] != ' ' && next[8] != '\t' {
+		return false
+	}
+	return lexer.New(s.buf).Next().Type == lexer.EOF
+}
+
+// readDelimiterLine reads the rest of a DELIMITER line into s.buf and
+// makes its argument the statement delimiter.
+func (s *StreamParser) readDelimiterLine() {
+	start := len(s.buf) - 1
+	for {
+		c, ok := s.readByte()
+		if !ok || c == '\n' {
+			break
+		}
+		s.buf = append(s.buf, c)
+	}
+	fields := bytes.Fields(s.buf[start:])
+	if len(fields) < 2 || string(fields[1]) == ";" {
+		s.delim = nil
+		return
+	}
+	s.delim = append(s.delim[:0], fields[1]...)
+}
+
+// readByte reads one byte and advances the stream position. At end of input
+// or on a read error it records s.err and returns false.
+func (s *StreamParser) readByte() (byte, bool) {
+	c, err := s.r.ReadByte()
+	if err != nil {
+		s.err = err
+		return 0, false
+	}
+	s.off++
+	if c == '\n' {
+		s.line++
+		s.col = 1
+	} else {
+		s.col++
+	}
+	return c, true
+}
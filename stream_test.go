@@ -0,0 +1,133 @@
+package sqlparser_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestParserIter(t *testing.T) {
+	p := sqlparser.NewString("SELECT 1; SELECT 2;; DELETE FROM t WHERE id = 3")
+	n := 0
+	for stmt, err := range p.Iter() {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if stmt == nil {
+			t.Fatal("nil statement yielded")
+		}
+		n++
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 statements, got %d", n)
+	}
+}
+
+func TestStreamParserSplitsOnTopLevelSemicolons(t *testing.T) {
+	src := `INSERT INTO t VALUES ('a;b', "c;d", 'it''s; here', 'esc\'; x');
+-- comment; with semicolon
+SELECT /* ; */ 1 # trailing; comment
+;
+UPDATE t SET a = 1`
+	var kinds []string
+	for stmt, err := range sqlparser.NewStreamParser(strings.NewReader(src)).Iter() {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		switch stmt.(type) {
+		case *sqlparser.InsertStmt:
+			kinds = append(kinds, "insert")
+		case *sqlparser.SelectStmt:
+			kinds = append(kinds, "select")
+		case *sqlparser.UpdateStmt:
+			kinds = append(kinds, "update")
+		}
+	}
+	if strings.Join(kinds, ",") != "insert,select,update" {
+		t.Fatalf("unexpected statements: %v", kinds)
+	}
+}
+
+func TestStreamParserContinuesAfterError(t *testing.T) {
+	src := "SELECT 1;\nSELECT FROM;\nSELECT 3"
+	var stmts int
+	var errs []error
+	for stmt, err := range sqlparser.NewStreamParser(strings.NewReader(src)).Iter() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if stmt != nil {
+			stmts++
+		}
+	}
+	if stmts != 2 || len(errs) != 1 {
+		t.Fatalf("expected 2 statements and 1 error, got %d and %v", stmts, errs)
+	}
+	var pe *sqlparser.ParseError
+	if !errors.As(errs[0], &pe) {
+		t.Fatalf("expected ParseError, got %T", errs[0])
+	}
+	if pe.Line != 2 || pe.Col != 8 || pe.Pos != 17 {
+		t.Fatalf("expected error at stream line 2 col 8 (pos 17), got line %d col %d pos %d", pe.Line, pe.Col, pe.Pos)
+	}
+}
+
+func TestStreamParserDelimiter(t *testing.T) {
+	src := "DROP PROCEDURE IF EXISTS p;\n-- routines\nDELIMITER $$\n" +
+		"CREATE DEFINER=`root`@`localhost` PROCEDURE p(IN x INT)\nBEGIN\n  SELECT 1;\n  SELECT ';$$';\nEND$$\n" +
+		"DELIMITER ;;\nCREATE TRIGGER tr BEFORE INSERT ON t FOR EACH ROW BEGIN SET NEW.a = 1; END;;\n" +
+		"DELIMITER ;\nSELECT 2"
+	var names []string
+	for stmt, err := range sqlparser.NewStreamParser(strings.NewReader(src)).Iter() {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		switch s := stmt.(type) {
+		case *sqlparser.GenericDDLStmt:
+			name := string(s.Object)
+			if s.Name != nil {
+				name += " " + string(s.Name.Unquoted)
+			}
+			names = append(names, name)
+		case *sqlparser.SelectStmt:
+			names = append(names, "select")
+		}
+	}
+	if got := strings.Join(names, ","); got != "PROCEDURE,PROCEDURE p,TRIGGER tr,select" {
+		t.Fatalf("unexpected statements: %s", got)
+	}
+}
+
+func TestStreamParserWithOptions(t *testing.T) {
+	long := strings.Repeat("x", 1<<16)
+	src := "SELECT 1;\nSELECT '" + long + "';\n" +
+		"SELECT 2 -- " + long + "\n;\n" +
+		"DELIMITER $$\nSELECT 'a;b', " + long + "$$\nSELECT 'C:\\dir\\'$$"
+	p := sqlparser.NewStreamParserWithOptions(strings.NewReader(src), sqlparser.ParseOptions{
+		MaxStatementLength: 32,
+		StandardStrings:    true,
+	})
+	var got []string
+	for stmt, err := range p.Iter() {
+		if err != nil {
+			var pe *sqlparser.ParseError
+			if !errors.Is(err, sqlparser.ErrStatementTooLong) || !errors.As(err, &pe) {
+				t.Fatalf("expected ErrStatementTooLong, got %v", err)
+			}
+			got = append(got, fmt.Sprintf("too long at line %d", pe.Line))
+			continue
+		}
+		if _, ok := stmt.(*sqlparser.SelectStmt); ok {
+			got = append(got, "select")
+		}
+	}
+	// The last SELECT only parses when its backslashes are plain characters.
+	want := []string{"select", "too long at line 2", "select", "too long at line 6", "select"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+package sqlparser
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// renderString renders a string literal so the target reads the value the
+// source meant. A literal with a backslash means different things in
+// different dialects: MySQL and ClickHouse read backslash escapes, while
+// PostgreSQL's standard strings and SQLite's keep backslashes as they are.
+// Such a literal is decoded by the rules of ConvertOptions.Source, or kept
+// as written when the source is unknown, and written again in the
+// target's own terms: MySQL and ClickHouse escape backslashes, PostgreSQL
+// keeps them in a standard string and uses E'...' only for control
+// characters, and SQLite and the generic output, which have no escapes,
+// write every character as is. A PostgreSQL E'...' literal is always
+// decoded. Literals without a backslash read the same everywhere and keep
+// their source spelling.
+func (r *dialectRenderer) renderString(lit *ast.Literal) (string, bool) {
+	if lit.Kind != lexer.STRING || len(lit.Raw) < 2 || lit.Raw[0] == '\'' && bytes.IndexByte(lit.Raw, '\\') < 0 {
+		return "", false
+	}
+	backslashes := true
+	if lit.Raw[0] == '\'' {
+		switch r.source {
+		case DialectMySQL, DialectClickHouse:
+		case DialectPostgres, DialectSQLite:
+			backslashes = false
+		default:
+			return "", false
+		}
+	}
+	v, ok := lexer.DecodeString(lit.Raw, backslashes)
+	if !ok {
+		return "", false
+	}
+	return r.quoteString(v)
+}
+
+// quoteString writes v as a string literal of the target. ok is false when
+// the target cannot hold v, such as a NUL byte in PostgreSQL.
+func (r *dialectRenderer) quoteString(v string) (string, bool) {
+	var b strings.Builder
+	b.Grow(len(v) + 2)
+	switch r.target {
+	case DialectMySQL, DialectClickHouse:
+		b.WriteByte('\'')
+		for i := 0; i < len(v); i++ {
+			switch c := v[i]; c {
+			case '\'':
+				b.WriteString("''")
+			case '\\':
+				b.WriteString(`\\`)
+			case 0:
+				b.WriteString(`\0`)
+			case '\n':
+				b.WriteString(`\n`)
+			case '\r':
+				b.WriteString(`\r`)
+			case '\t':
+				b.WriteString(`\t`)
+			case 0x1a:
+				b.WriteString(`\Z`)
+			default:
+				b.WriteByte(c)
+			}
+		}
+	case DialectPostgres:
+		if strings.IndexByte(v, 0) >= 0 {
+			return "", false
+		}
+		if !strings.ContainsAny(v, "\b\f\n\r\t") {
+			return "'" + strings.ReplaceAll(v, "'", "''") + "'", true
+		}
+		b.WriteString("E'")
+		for i := 0; i < len(v); i++ {
+			switch c := v[i]; c {
+			case '\'':
+				b.WriteString("''")
+			case '\\':
+				b.WriteString(`\\`)
+			case '\b':
+				b.WriteString(`\b`)
+			case '\f':
+				b.WriteString(`\f`)
+			case '\n':
+				b.WriteString(`\n`)
+			case '\r':
+				b.WriteString(`\r`)
+			case '\t':
+				b.WriteString(`\t`)
+			default:
+				b.WriteByte(c)
+			}
+		}
+	default:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'", true
+	}
+	b.WriteByte('\'')
+	return b.String(), true
+}
@@ -0,0 +1,195 @@
+package sqlparser
+
+import (
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// OutputStyle sets the house style of ConvertDialectWithOptions output.
+// The zero value renders keywords in upper case and everything else as
+// written, without a final semicolon.
+type OutputStyle struct {
+	// Keywords sets the case of keywords, function names, type names and
+	// the TRUE, FALSE and NULL literals.
+	Keywords KeywordCase
+	// Identifiers sets the case of identifiers. Unlike the other options,
+	// it can change what the statement means: quoted names are
+	// case-sensitive in PostgreSQL and SQLite, and table names in MySQL on
+	// most platforms.
+	Identifiers IdentCase
+	// Booleans sets how TRUE and FALSE are written.
+	Booleans BoolStyle
+	// Semicolon ends the output with a semicolon.
+	Semicolon bool
+	// Separator is written between statements: "; " when empty, or ";\n"
+	// to put each statement on a line of its own.
+	Separator string
+}
+
+// KeywordCase is the case of keywords in rendered SQL.
+type KeywordCase uint8
+
+const (
+	// KeywordDefault writes keywords and function names in upper case and
+	// type names and literals as written.
+	KeywordDefault KeywordCase = iota
+	KeywordUpper
+	KeywordLower
+)
+
+// IdentCase is the case of identifiers in rendered SQL.
+type IdentCase uint8
+
+const (
+	IdentAsWritten IdentCase = iota
+	IdentLower
+	IdentUpper
+)
+
+// BoolStyle is how boolean literals are written.
+type BoolStyle uint8
+
+const (
+	BoolAsWritten BoolStyle = iota
+	// BoolKeywords writes TRUE and FALSE.
+	BoolKeywords
+	// BoolNumbers writes 1 and 0, as MySQL and SQLite store booleans.
+	// PostgreSQL output keeps TRUE and FALSE, since 1 is not a boolean
+	// there.
+	BoolNumbers
+)
+
+// renderBool renders a TRUE or FALSE literal in the style's spelling, or
+// as 1 and 0 for MySQL and SQLite under ConvertOptions.ConvertBooleans.
+// The 1 and 0 markBoolLiterals found become TRUE and FALSE.
+func (r *dialectRenderer) renderBool(lit *ast.Literal) (string, bool) {
+	if v, ok := r.boolLits[lit]; ok {
+		if v {
+			return "TRUE", true
+		}
+		return "FALSE", true
+	}
+	if lit.Kind != lexer.TRUE_KW && lit.Kind != lexer.FALSE_KW {
+		return "", false
+	}
+	numbers := r.convertBooleans && (r.target == DialectMySQL || r.target == DialectSQLite)
+	switch {
+	case (r.style.Booleans == BoolNumbers || numbers) && r.target != DialectPostgres:
+		if lit.Kind == lexer.TRUE_KW {
+			return "1", true
+		}
+		return "0", true
+	case r.style.Booleans != BoolAsWritten:
+		if lit.Kind == lexer.TRUE_KW {
+			return "TRUE", true
+		}
+		return "FALSE", true
+	}
+	return "", false
+}
+
+// separator returns the text written between statements.
+func (r *dialectRenderer) separator() string {
+	if r.style.Separator == "" {
+		return "; "
+	}
+	return r.style.Separator
+}
+
+// identCase applies the identifier case of the style to name.
+func (r *dialectRenderer) identCase(name string) string {
+	switch r.style.Identifiers {
+	case IdentLower:
+		return strings.ToLower(name)
+	case IdentUpper:
+		return strings.ToUpper(name)
+	}
+	return name
+}
+
+// applyStyle sets the case of the keywords of rendered output and adds
+// the final semicolon. Keywords are found by lexing the output: the
+// keyword tokens and the names of called functions, except the
+// identifiers renderIdent left unquoted, and words in mixed case, such as
+// ClickHouse's case-sensitive type names. Comments, quoted names, strings
+// and text copied as written are left alone; strings are read by the
+// target's rules, so a PostgreSQL 'a\' does not run on into the SQL
+// after it.
+func (r *dialectRenderer) applyStyle(out string) string {
+	if out == "" {
+		return out
+	}
+	if r.style.Keywords != KeywordDefault {
+		// The spans of out copied as written, which appear in the order
+		// they were rendered.
+		var spans [][2]int
+		from := 0
+		for _, v := range r.verbatim {
+			if i := strings.Index(out[from:], v); i >= 0 {
+				spans = append(spans, [2]int{from + i, from + i + len(v)})
+				from += i + len(v)
+			}
+		}
+		toks := lexer.TokenizeWithOptions([]byte(out), nil, lexer.Options{
+			StandardStrings: r.target == DialectPostgres || r.target == DialectSQLite,
+		})
+		var b strings.Builder
+		last := 0
+		for i, tok := range toks {
+			for len(spans) > 0 && int(tok.Pos) >= spans[0][1] {
+				spans = spans[1:]
+			}
+			if len(spans) > 0 && int(tok.Pos) >= spans[0][0] {
+				continue
+			}
+			word := string(tok.Raw)
+			call := tok.Type == lexer.IDENT && toks[i+1].Type == lexer.LPAREN
+			if tok.Type == lexer.IDENT && !call || tok.Type == lexer.STRING || tok.Type == lexer.NAMEDPARAM ||
+				!isBareWord(word) || r.bareIdents[word] || r.target == DialectClickHouse && clickHouseTypeNames[word] {
+				continue
+			}
+			cased := strings.ToUpper(word)
+			if r.style.Keywords == KeywordLower {
+				cased = strings.ToLower(word)
+			}
+			if word != strings.ToUpper(word) && word != strings.ToLower(word) || cased == word {
+				continue
+			}
+			b.WriteString(out[last:tok.Pos])
+			b.WriteString(cased)
+			last = int(tok.Pos) + len(word)
+		}
+		b.WriteString(out[last:])
+		out = b.String()
+	}
+	if r.style.Semicolon {
+		out += ";"
+	}
+	return out
+}
+
+// isBareWord reports whether s is an unquoted word.
+func isBareWord(s string) bool {
+	if s == "" || s[0] >= '0' && s[0] <= '9' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// clickHouseTypeNames are the ClickHouse type names the renderer writes,
+// which are case-sensitive.
+var clickHouseTypeNames = func() map[string]bool {
+	names := map[string]bool{}
+	for _, name := range clickHouseTypes {
+		names[name] = true
+	}
+	return names
+}()
@@ -0,0 +1,266 @@
+package sqlparser
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/lexer"
+	"github.com/oarkflow/sqlparser/parser"
+)
+
+// Tags is routing metadata read from magic comments such as
+// /* app:checkout team:payments */ or sqlcommenter's
+// /*controller='index',traceparent='00-...'*/. Keys may be hierarchical,
+// with dots separating levels, e.g. owner.team:payments.
+type Tags map[string]string
+
+// Sub returns the tags nested under prefix, with "prefix." removed from
+// their keys. It returns nil when there are none.
+func (t Tags) Sub(prefix string) Tags {
+	var out Tags
+	for k, v := range t {
+		if len(k) > len(prefix) && k[len(prefix)] == '.' && strings.HasPrefix(k, prefix) {
+			if out == nil {
+				out = Tags{}
+			}
+			out[k[len(prefix)+1:]] = v
+		}
+	}
+	return out
+}
+
+// TagFormat selects how tags are written back into SQL comments.
+type TagFormat string
+
+const (
+	// TagFormatSQLCommenter writes /*key='value',...*/ as specified by
+	// sqlcommenter, with URL-encoded keys and values.
+	TagFormatSQLCommenter TagFormat = "sqlcommenter"
+	// TagFormatKeyValue writes /* key:value ... */.
+	TagFormatKeyValue TagFormat = "keyvalue"
+)
+
+// StatementTags returns the tags attached to each statement in sql, in
+// statement order. Comments count towards the statement they appear in or
+// directly precede; a comment after the last semicolon belongs to the last
+// statement. Entries are nil for statements without tags. Optimizer hints
+// (/*+ ... */) and MySQL version comments (/*! ... */) are never read as tags.
+//
+// Statements end where the parser ends them, so the semicolons of a
+// routine body between DELIMITER lines stay inside it. Input that does not
+// parse is split at every semicolon instead.
+func StatementTags(sql string) []Tags {
+	return statementTags(sql, ParseOptions{})
+}
+
+// statementTags is StatementTags for sql parsed with opts.
+func statementTags(sql string, opts ParseOptions) []Tags {
+	p := parser.NewStringWithOptions(sql, opts)
+	var ends []int
+	for {
+		stmt, err := p.ParseOne()
+		if err != nil {
+			return tagsByEnds(sql, opts, nil)
+		}
+		if stmt == nil {
+			return tagsByEnds(sql, opts, ends)
+		}
+		ends = append(ends, p.StatementEnd())
+	}
+}
+
+// tagsByEnds returns the tags of the statements of sql that end at the
+// offsets ends, as reported by parser.StatementEnd. A nil ends splits sql
+// at every semicolon and delimiter.
+func tagsByEnds(sql string, opts ParseOptions, ends []int) []Tags {
+	l := lexer.NewString(sql)
+	l.SetOptions(lexer.Options{EmitComments: true, StandardStrings: opts.StandardStrings})
+	split := ends == nil
+	var out []Tags
+	var cur Tags
+	inStmt := false
+	for {
+		tok := l.Next()
+		switch tok.Type {
+		case lexer.COMMENT:
+			cur = mergeTags(cur, parseCommentTags(tok.Raw))
+			continue
+		case lexer.SEMICOLON, lexer.DELIMITER, lexer.EOF:
+			if !split && tok.Type != lexer.EOF && (len(out) >= len(ends) || int(tok.Pos) < ends[len(out)]) {
+				// A semicolon inside the statement, or after the last.
+				if len(out) < len(ends) {
+					inStmt = true
+				}
+				continue
+			}
+			if inStmt {
+				out = append(out, cur)
+				cur, inStmt = nil, false
+			} else if cur != nil && len(out) > 0 {
+				out[len(out)-1] = mergeTags(out[len(out)-1], cur)
+				cur = nil
+			}
+			if tok.Type == lexer.EOF {
+				return out
+			}
+		default:
+			if !split && inStmt && len(out) < len(ends) && int(tok.Pos) >= ends[len(out)] {
+				// The next statement began without a semicolon.
+				out = append(out, cur)
+				cur = nil
+			}
+			inStmt = true
+		}
+	}
+}
+
+func mergeTags(dst, src Tags) Tags {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(Tags, len(src))
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// parseCommentTags extracts tags from a single comment token.
+func parseCommentTags(raw []byte) Tags {
+	body := string(raw)
+	switch {
+	case strings.HasPrefix(body, "/*"):
+		body = strings.TrimSuffix(body[2:], "*/")
+		if strings.HasPrefix(body, "+") || strings.HasPrefix(body, "!") {
+			return nil
+		}
+	case strings.HasPrefix(body, "--"):
+		body = body[2:]
+	case strings.HasPrefix(body, "#"):
+		body = body[1:]
+	}
+	body = strings.TrimSpace(body)
+	if strings.Contains(body, "='") {
+		return parseSQLCommenter(body)
+	}
+	var tags Tags
+	for _, field := range strings.FieldsFunc(body, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == ','
+	}) {
+		k, v, ok := strings.Cut(field, ":")
+		if !ok || k == "" || v == "" || !isTagKey(k) {
+			continue
+		}
+		if tags == nil {
+			tags = Tags{}
+		}
+		tags[k] = unescapeTag(v)
+	}
+	return tags
+}
+
+// parseSQLCommenter parses key='value' pairs separated by commas. Parsing
+// stops at the first malformed pair, keeping the pairs read so far.
+func parseSQLCommenter(body string) Tags {
+	var tags Tags
+	for body != "" {
+		eq := strings.IndexByte(body, '=')
+		if eq <= 0 || eq+1 >= len(body) || body[eq+1] != '\'' {
+			break
+		}
+		key := strings.TrimSpace(body[:eq])
+		rest := body[eq+2:]
+		var val strings.Builder
+		end := -1
+		for i := 0; i < len(rest); i++ {
+			if rest[i] == '\\' && i+1 < len(rest) {
+				i++
+				val.WriteByte(rest[i])
+				continue
+			}
+			if rest[i] == '\'' {
+				end = i
+				break
+			}
+			val.WriteByte(rest[i])
+		}
+		if end < 0 {
+			break
+		}
+		if tags == nil {
+			tags = Tags{}
+		}
+		tags[unescapeTag(key)] = unescapeTag(val.String())
+		body = strings.TrimLeft(rest[end+1:], " ,")
+	}
+	return tags
+}
+
+func isTagKey(k string) bool {
+	for i := 0; i < len(k); i++ {
+		c := k[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+func unescapeTag(s string) string {
+	if u, err := url.PathUnescape(s); err == nil {
+		return u
+	}
+	return s
+}
+
+// escapeTag percent-encodes everything outside the URL unreserved set, which
+// also guarantees the result cannot terminate the surrounding comment.
+func escapeTag(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&15])
+	}
+	return b.String()
+}
+
+// formatTagComment renders tags as a comment in the given format, with keys
+// sorted so output is deterministic.
+func formatTagComment(tags Tags, format TagFormat) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	if format == TagFormatKeyValue {
+		b.WriteString("/*")
+		for _, k := range keys {
+			b.WriteString(" " + escapeTag(k) + ":" + escapeTag(tags[k]))
+		}
+		b.WriteString(" */")
+		return b.String()
+	}
+	b.WriteString("/*")
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(escapeTag(k) + "='" + escapeTag(tags[k]) + "'")
+	}
+	b.WriteString("*/")
+	return b.String()
+}
@@ -0,0 +1,114 @@
+package sqlparser_test
+
+import (
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestStatementTags(t *testing.T) {
+	sql := `/* app:checkout team.owner:payments team.oncall:alice */ SELECT 1;
+SELECT 2 /*controller='index',route='%2Fpolls%201',traceparent='00-abc-01'*/;
+/*+ MAX_EXECUTION_TIME(100) */ SELECT 3; -- fetch users
+UPDATE t SET a = 1; -- region:eu`
+	tags := sqlparser.StatementTags(sql)
+	if len(tags) != 4 {
+		t.Fatalf("expected tags for 4 statements, got %d", len(tags))
+	}
+	if tags[0]["app"] != "checkout" || tags[0].Sub("team")["oncall"] != "alice" {
+		t.Fatalf("unexpected key:value tags: %v", tags[0])
+	}
+	if tags[1]["route"] != "/polls 1" || tags[1]["traceparent"] != "00-abc-01" {
+		t.Fatalf("unexpected sqlcommenter tags: %v", tags[1])
+	}
+	if tags[2] != nil {
+		t.Fatalf("hints and prose comments must not produce tags, got %v", tags[2])
+	}
+	if tags[3]["region"] != "eu" {
+		t.Fatalf("expected trailing comment to tag the last statement, got %v", tags[3])
+	}
+
+	res, err := sqlparser.ParseWithOptions(sql, sqlparser.ParseOptions{})
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if len(res.Tags) != len(res.Statements) || res.Tags[0]["app"] != "checkout" {
+		t.Fatalf("ParseResult.Tags not aligned with statements: %v", res.Tags)
+	}
+}
+
+func TestStatementTagsDelimiter(t *testing.T) {
+	sql := `/* app:one */ SELECT 1;
+DELIMITER $$
+/* app:two */ CREATE PROCEDURE p() BEGIN SELECT 2; SELECT 3; END$$
+DELIMITER ;
+SELECT 4; -- app:four`
+	want := []string{"one", "two", "four"}
+	check := func(name string, tags []sqlparser.Tags) {
+		t.Helper()
+		if len(tags) != len(want) {
+			t.Fatalf("%s: expected tags for %d statements, got %v", name, len(want), tags)
+		}
+		for i, app := range want {
+			if tags[i]["app"] != app {
+				t.Errorf("%s: statement %d: got %v, want app:%s", name, i, tags[i], app)
+			}
+		}
+	}
+	check("StatementTags", sqlparser.StatementTags(sql))
+	res, err := sqlparser.ParseWithOptions(sql, sqlparser.ParseOptions{})
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if len(res.Statements) != len(want) {
+		t.Fatalf("expected %d statements, got %d", len(want), len(res.Statements))
+	}
+	check("ParseResult.Tags", res.Tags)
+}
+
+func TestConvertInjectsTags(t *testing.T) {
+	out, _, err := sqlparser.ConvertDialectWithOptions(`SELECT id FROM users /* app:web */`, sqlparser.ConvertOptions{
+		Target:   sqlparser.DialectPostgres,
+		Tags:     sqlparser.Tags{"traceparent": "00-abc-01", "route": "/users"},
+		KeepTags: true,
+	})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	want := `SELECT "id" FROM "users" /*app='web',route='%2Fusers',traceparent='00-abc-01'*/`
+	if out != want {
+		t.Fatalf("got:  %s\nwant: %s", out, want)
+	}
+	tags := sqlparser.StatementTags(out)
+	if len(tags) != 1 || tags[0]["route"] != "/users" {
+		t.Fatalf("injected comment did not round-trip: %v", tags)
+	}
+
+	out, _, err = sqlparser.ConvertDialectWithOptions(`SELECT 1`, sqlparser.ConvertOptions{
+		Target:    sqlparser.DialectMySQL,
+		Tags:      sqlparser.Tags{"team": "payments", "note": "a */ b"},
+		TagFormat: sqlparser.TagFormatKeyValue,
+	})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if out != `SELECT 1 /* note:a%20%2A%2F%20b team:payments */` {
+		t.Fatalf("unexpected key:value output: %s", out)
+	}
+}
+
+func TestConvertKeepsTagsAcrossDelimiter(t *testing.T) {
+	sql := "/* app:one */ SELECT 1;\nDELIMITER $$\n/* app:two */ CREATE PROCEDURE p() BEGIN SELECT 2; SELECT 3; END$$\nDELIMITER ;\nSELECT 4; -- app:four"
+	out, _, err := sqlparser.ConvertDialectWithOptions(sql, sqlparser.ConvertOptions{
+		Source:   sqlparser.DialectMySQL,
+		Target:   sqlparser.DialectMySQL,
+		KeepTags: true,
+	})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	want := "SELECT 1 /*app='one'*/; CREATE PROCEDURE `p` () BEGIN SELECT 2; SELECT 3; END /*app='two'*/; SELECT 4 /*app='four'*/"
+	if out != want {
+		t.Fatalf("got:  %s\nwant: %s", out, want)
+	}
+}
@@ -0,0 +1,225 @@
+package sqlparser
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// Template is a statement some of whose table and column names are
+// {{name}} placeholders, as in SELECT * FROM {{table}}, filled in by
+// Execute. A placeholder stands for one whole name: a table, a column, an
+// alias, or one part of a dotted name such as {{schema}}.users. The names
+// substituted are identifiers of the AST, which the renderer quotes as
+// the target requires, so no value can change what the statement does.
+// Placeholders anywhere else, such as inside a string, in place of a
+// keyword, type or function name, or as part of a longer name, are
+// rejected when the template is parsed. A placeholder where a value would
+// go is read as a column name; values are bound as parameters instead.
+//
+// A Template can be executed any number of times, concurrently.
+type Template struct {
+	stmt Statement
+	// slots map the positions of the placeholders to their names.
+	slots map[int32]string
+	names []string
+}
+
+// ParseTemplate parses a single statement with {{name}} placeholders, where name
+// is a letter or underscore followed by letters, digits and underscores,
+// and spaces may surround it. Parse errors report positions in sql.
+func ParseTemplate(sql string) (*Template, error) {
+	src := []byte(sql)
+	t := &Template{slots: map[int32]string{}}
+	var toks []lexer.Token
+	var l lexer.Lexer
+	l.Init([]byte(sql))
+	for tok := l.Next(); tok.Type != lexer.EOF; tok = l.Next() {
+		toks = append(toks, tok)
+	}
+	for i := 0; i < len(toks); i++ {
+		tok := toks[i]
+		if tok.Type != lexer.LBRACE || i+1 == len(toks) || toks[i+1].Type != lexer.LBRACE || toks[i+1].Pos != tok.Pos+1 {
+			if tok.Type != lexer.LBRACE && strings.Contains(string(tok.Raw), "{{") {
+				return nil, templateErrorf(src, tok.Pos, "placeholder inside %s; only table and column names can be substituted", tok.Raw)
+			}
+			continue
+		}
+		start := tok.Pos
+		if i+4 >= len(toks) || !templateName(toks[i+2].Raw) || toks[i+3].Type != lexer.RBRACE || toks[i+4].Type != lexer.RBRACE || toks[i+4].Pos != toks[i+3].Pos+1 {
+			return nil, templateErrorf(src, start, "malformed placeholder; placeholders are written {{name}}")
+		}
+		name := string(toks[i+2].Raw)
+		end := toks[i+4].Pos + 1
+		i += 4
+		if start > 0 && templateWordByte(src[start-1]) || int(end) < len(src) && templateWordByte(src[end]) {
+			return nil, templateErrorf(src, start, "placeholder {{%s}} is part of a longer name; it must stand for a whole name", name)
+		}
+		// A quoted identifier of the same length keeps the positions of
+		// the parse, and of its errors, those of sql.
+		sentinel := `"{` + name + `}"`
+		copy(src[start:end], sentinel+strings.Repeat(" ", int(end-start)-len(sentinel)))
+		t.slots[start] = name
+		if !slices.Contains(t.names, name) {
+			t.names = append(t.names, name)
+		}
+	}
+	stmts, err := ParseStatements(string(src))
+	switch {
+	case err != nil:
+		return nil, err
+	case len(stmts) != 1:
+		return nil, fmt.Errorf("sqlparser: a template holds one statement, not %d", len(stmts))
+	}
+	stmt := stmts[0]
+	found := map[int32]bool{}
+	err = walkTemplateNames(reflect.ValueOf(&stmt).Elem(), false, func(id *ast.Ident, allowed bool) error {
+		name, ok := t.slots[id.TokPos]
+		if !ok || string(id.Raw) != `"{`+name+`}"` {
+			return nil
+		}
+		if !allowed {
+			return templateErrorf(src, id.TokPos, "placeholder {{%s}} is not a table or column name", name)
+		}
+		found[id.TokPos] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for pos, name := range t.slots {
+		if !found[pos] {
+			return nil, templateErrorf(src, pos, "placeholder {{%s}} is not a table or column name", name)
+		}
+	}
+	slices.Sort(t.names)
+	t.stmt = stmt
+	return t, nil
+}
+
+// Names returns the names of the template's placeholders, sorted.
+func (t *Template) Names() []string {
+	return slices.Clone(t.names)
+}
+
+// Execute returns a copy of the statement with each placeholder replaced
+// by the name values gives it. Every placeholder needs a non-empty value;
+// values for names the template lacks are ignored.
+func (t *Template) Execute(values map[string]string) (Statement, error) {
+	for _, name := range t.names {
+		v, ok := values[name]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("sqlparser: no value for placeholder {{%s}}", name)
+		case v == "":
+			return nil, fmt.Errorf("sqlparser: empty value for placeholder {{%s}}", name)
+		case strings.IndexByte(v, 0) >= 0:
+			return nil, fmt.Errorf("sqlparser: value for placeholder {{%s}} contains a NUL byte", name)
+		}
+	}
+	stmt := ast.Clone(t.stmt)
+	walkTemplateNames(reflect.ValueOf(&stmt).Elem(), false, func(id *ast.Ident, allowed bool) error {
+		if name, ok := t.slots[id.TokPos]; ok && allowed && string(id.Raw) == `"{`+name+`}"` {
+			v := values[name]
+			// Quoted, so that renderers that keep the source's quoting
+			// quote the value too.
+			id.Raw = []byte(`"` + strings.ReplaceAll(v, `"`, `""`) + `"`)
+			id.Unquoted = v
+		}
+		return nil
+	})
+	return stmt, nil
+}
+
+// templateFields are the fields, besides expressions, whose identifiers
+// name tables, columns or aliases, and so may be placeholders. Other
+// identifiers, such as function names, are written as they are.
+var templateFields = map[reflect.Type][]string{
+	reflect.TypeFor[ast.SimpleTable]():   {"Name", "Alias"},
+	reflect.TypeFor[ast.SubqueryTable](): {"Alias", "Columns"},
+	reflect.TypeFor[ast.JoinTable]():     {"Using"},
+	reflect.TypeFor[ast.TableStar]():     {"Table"},
+	reflect.TypeFor[ast.SelectColumn]():  {"Alias"},
+	reflect.TypeFor[ast.CTE]():           {"Name", "Columns"},
+	reflect.TypeFor[ast.InsertStmt]():    {"Table", "Columns"},
+	reflect.TypeFor[ast.Assignment]():    {"Column"},
+	reflect.TypeFor[ast.DeleteStmt]():    {"Tables"},
+}
+
+var (
+	exprType           = reflect.TypeFor[ast.Expr]()
+	identType          = reflect.TypeFor[*ast.Ident]()
+	qualifiedIdentType = reflect.TypeFor[*ast.QualifiedIdent]()
+)
+
+// walkTemplateNames calls visit for every identifier under v, saying
+// whether it is a table or column name: one held by an expression, such as
+// a column reference, or by a field of templateFields. allowed says
+// whether v itself is one.
+func walkTemplateNames(v reflect.Value, allowed bool, visit func(id *ast.Ident, allowed bool) error) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		switch v.Type() {
+		case identType:
+			return visit((*ast.Ident)(v.UnsafePointer()), allowed)
+		case qualifiedIdentType:
+			for _, part := range (*ast.QualifiedIdent)(v.UnsafePointer()).Parts {
+				if err := visit(part, allowed); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return walkTemplateNames(v.Elem(), false, visit)
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return walkTemplateNames(v.Elem(), v.Type() == exprType, visit)
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			if err := walkTemplateNames(v.Index(i), allowed, visit); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		fields := templateFields[v.Type()]
+		for i := range v.NumField() {
+			if err := walkTemplateNames(v.Field(i), slices.Contains(fields, v.Type().Field(i).Name), visit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// templateName reports whether raw is a valid placeholder name.
+func templateName(raw []byte) bool {
+	if len(raw) == 0 || raw[0] >= '0' && raw[0] <= '9' {
+		return false
+	}
+	for _, c := range raw {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// templateWordByte reports whether c would join a placeholder to the name
+// or quoted identifier next to it.
+func templateWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '$' || c == '"' || c == '`' || c >= 0x80
+}
+
+func templateErrorf(src []byte, pos int32, format string, args ...any) error {
+	line, col := lexer.ComputeLineCol(src, int(pos))
+	return fmt.Errorf("sqlparser: line %d, column %d: %s", line, col, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,113 @@
+package sqlparser_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestTemplate(t *testing.T) {
+	tmpl, err := sqlparser.ParseTemplate("SELECT {{col}}, COUNT(*) AS n FROM {{schema}}.{{table}} t WHERE t.{{col}} > ? GROUP BY {{col}} ORDER BY {{ col }} DESC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tmpl.Names(), []string{"col", "schema", "table"}; !slices.Equal(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+	tests := []struct {
+		values map[string]string
+		target sqlparser.Dialect
+		want   string
+	}{
+		{
+			map[string]string{"col": "region", "schema": "sales", "table": "orders"},
+			sqlparser.DialectPostgres,
+			`SELECT "region", COUNT(*) AS "n" FROM "sales"."orders" "t" WHERE ("t"."region" > $1) GROUP BY "region" ORDER BY "region" DESC`,
+		},
+		{
+			map[string]string{"col": "Region", "schema": "sales", "table": `orders"; DROP TABLE users; --`, "unused": "x"},
+			sqlparser.DialectPostgres,
+			`SELECT "Region", COUNT(*) AS "n" FROM "sales"."orders""; DROP TABLE users; --" "t" WHERE ("t"."Region" > $1) GROUP BY "Region" ORDER BY "Region" DESC`,
+		},
+		{
+			map[string]string{"col": "region", "schema": "sales", "table": "orders` WHERE 1"},
+			sqlparser.DialectMySQL,
+			"SELECT `region`, COUNT(*) AS `n` FROM `sales`.`orders`` WHERE 1` `t` WHERE (`t`.`region` > ?) GROUP BY `region` ORDER BY `region` DESC",
+		},
+	}
+	for _, tt := range tests {
+		stmt, err := tmpl.Execute(tt.values)
+		if err != nil {
+			t.Fatalf("Execute(%v): %v", tt.values, err)
+		}
+		got, _, err := sqlparser.ConvertStatements([]sqlparser.Statement{stmt}, sqlparser.ConvertOptions{Target: tt.target})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Execute(%v) for %s:\ngot  %s\nwant %s", tt.values, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestTemplatePositions(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []string
+	}{
+		{"INSERT INTO {{table}} ({{a}}, b) VALUES (1, 2)", []string{"a", "table"}},
+		{"UPDATE {{table}} SET {{col}} = {{col}} + 1 WHERE id = ?", []string{"col", "table"}},
+		{"DELETE FROM {{table}} WHERE id = ?", []string{"table"}},
+		{"WITH {{cte}} AS (SELECT 1) SELECT {{t}}.* FROM {{cte}} {{t}} JOIN u USING ({{key}})", []string{"cte", "key", "t"}},
+		{"SELECT a AS {{alias}} FROM (SELECT 1) AS {{sub}}", []string{"alias", "sub"}},
+	}
+	for _, tt := range tests {
+		tmpl, err := sqlparser.ParseTemplate(tt.sql)
+		if err != nil {
+			t.Errorf("ParseTemplate(%q): %v", tt.sql, err)
+			continue
+		}
+		if got := tmpl.Names(); !slices.Equal(got, tt.want) {
+			t.Errorf("ParseTemplate(%q).Names() = %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{"SELECT * FROM t WHERE name = '{{name}}'", "line 1, column 30: placeholder inside '{{name}}'"},
+		{`SELECT "{{col}}" FROM t`, "placeholder inside"},
+		{"SELECT {{fn}}(a) FROM t", "line 1, column 8: placeholder {{fn}} is not a table or column name"},
+		{"SELECT CAST(a AS {{typ}}) FROM t", "placeholder {{typ}} is not a table or column name"},
+		{"INSERT INTO t (a) VALUES (1) ON CONFLICT (a) DO UPDATE SET a = excluded.{{col}}", "placeholder {{col}} is not a table or column name"},
+		{"SELECT SUM(a) OVER {{w}} FROM t", "placeholder {{w}} is not a table or column name"},
+		{"SELECT * FROM report_{{year}}", "is part of a longer name"},
+		{"SELECT * FROM {{table}}_2024", "is part of a longer name"},
+		{"SELECT * FROM {{table}", "malformed placeholder"},
+		{"SELECT * FROM {{1table}}", "malformed placeholder"},
+		{"SELECT * FROM {{a b}}", "malformed placeholder"},
+		{"SELECT * FROM\n  {{table}} WHERE", "line 2"},
+		{"SELECT * FROM {{table}}; DROP TABLE users", "one statement, not 2"},
+	}
+	for _, tt := range tests {
+		_, err := sqlparser.ParseTemplate(tt.sql)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseTemplate(%q) = %v, want an error containing %q", tt.sql, err, tt.want)
+		}
+	}
+
+	tmpl, err := sqlparser.ParseTemplate("SELECT * FROM {{table}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, values := range []map[string]string{nil, {"table": ""}, {"table": "a\x00b"}} {
+		if _, err := tmpl.Execute(values); err == nil {
+			t.Errorf("Execute(%q) succeeded", values)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Package testutil compares SQL in tests by what it means rather than how
+// it is written. Statements are parsed and compared with ast.Equal, so
+// formatting, keyword case, identifier quoting and placeholder style never
+// fail a test, and a failure shows both sides in canonical form with the
+// places where they differ:
+//
+//	func TestUserQueries(t *testing.T) {
+//		testutil.Snapshot(t, "testdata/users.sql", repo.ListUsersSQL(), ast.EqualOptions{})
+//	}
+//
+// Snapshot files are written, in canonical form, when they are missing
+// and the UPDATE_SQL_SNAPSHOTS environment variable is set, or rewritten
+// when it is set and they differ.
+package testutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// UpdateEnv is the environment variable that makes Snapshot write its
+// files instead of comparing against them.
+const UpdateEnv = "UPDATE_SQL_SNAPSHOTS"
+
+// Normalize parses sql and renders it in canonical form: one statement
+// per line, each ending with a semicolon, with keywords upper-cased and
+// identifiers quoted.
+func Normalize(sql string) (string, error) {
+	stmts, err := sqlparser.ParseStatements(sql)
+	if err != nil {
+		return "", err
+	}
+	lines, err := render(stmts)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func render(stmts []sqlparser.Statement) ([]string, error) {
+	lines := make([]string, len(stmts))
+	for i, stmt := range stmts {
+		out, _, err := sqlparser.ConvertStatements([]sqlparser.Statement{stmt}, sqlparser.ConvertOptions{})
+		if err != nil {
+			return nil, err
+		}
+		lines[i] = out + ";"
+	}
+	return lines, nil
+}
+
+// Diff compares the statements of want and got and returns a report of
+// the differences, or "" when they are the same. Statements are matched
+// in order: those only want has are marked -, those only got has +, and
+// for a statement that changed both sides are followed by the fields
+// that differ.
+func Diff(want, got string, opts ast.EqualOptions) (string, error) {
+	a, err := sqlparser.ParseStatements(want)
+	if err != nil {
+		return "", fmt.Errorf("want: %w", err)
+	}
+	b, err := sqlparser.ParseStatements(got)
+	if err != nil {
+		return "", fmt.Errorf("got: %w", err)
+	}
+	return diffStatements(a, b, opts)
+}
+
+func diffStatements(a, b []sqlparser.Statement, opts ast.EqualOptions) (string, error) {
+	aLines, err := render(a)
+	if err != nil {
+		return "", err
+	}
+	bLines, err := render(b)
+	if err != nil {
+		return "", err
+	}
+	// common[i][j] is the length of the longest common subsequence of
+	// a[i:] and b[j:].
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if ast.Equal(a[i], b[j], opts) {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+	var out strings.Builder
+	changed := false
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && ast.Equal(a[i], b[j], opts):
+			fmt.Fprintf(&out, "  %s\n", aLines[i])
+			i++
+			j++
+			continue
+		case i < len(a) && j < len(b) && common[i+1][j+1] == common[i][j]:
+			// Neither statement is kept: report them as one change.
+			fmt.Fprintf(&out, "- %s\n+ %s\n", aLines[i], bLines[j])
+			for _, d := range ast.Diff(a[i], b[j], opts) {
+				fmt.Fprintf(&out, "    %s\n", d)
+			}
+			i++
+			j++
+		case j == len(b) || i < len(a) && common[i+1][j] >= common[i][j+1]:
+			fmt.Fprintf(&out, "- %s\n", aLines[i])
+			i++
+		default:
+			fmt.Fprintf(&out, "+ %s\n", bLines[j])
+			j++
+		}
+		changed = true
+	}
+	if !changed {
+		return "", nil
+	}
+	return out.String(), nil
+}
+
+// Equal fails t when want and got are not the same statements, reporting
+// them as Diff does.
+func Equal(t testing.TB, want, got string, opts ast.EqualOptions) {
+	t.Helper()
+	report, err := Diff(want, got, opts)
+	if err != nil {
+		t.Errorf("testutil.Equal: %v", err)
+		return
+	}
+	if report != "" {
+		t.Errorf("SQL differs (- want, + got):\n%s", report)
+	}
+}
+
+// Snapshot fails t when got is not the same statements as the snapshot
+// file at path, reporting them as Diff does. With UPDATE_SQL_SNAPSHOTS set
+// it writes got to the file in canonical form instead, creating missing
+// directories.
+func Snapshot(t testing.TB, path, got string, opts ast.EqualOptions) {
+	t.Helper()
+	norm, err := Normalize(got)
+	if err != nil {
+		t.Errorf("testutil.Snapshot %s: got: %v", path, err)
+		return
+	}
+	update := os.Getenv(UpdateEnv) != ""
+	want, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && !update:
+		t.Errorf("testutil.Snapshot: %s does not exist; run the test with %s=1 to create it", path, UpdateEnv)
+		return
+	case err != nil && !update:
+		t.Errorf("testutil.Snapshot: %v", err)
+		return
+	case err == nil:
+		report, err := Diff(string(want), got, opts)
+		if err != nil {
+			t.Errorf("testutil.Snapshot %s: %v", path, err)
+			return
+		}
+		if report == "" {
+			return
+		}
+		if !update {
+			t.Errorf("SQL differs from snapshot %s (- snapshot, + got); run the test with %s=1 to update it:\n%s", path, UpdateEnv, report)
+			return
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Errorf("testutil.Snapshot: %v", err)
+		return
+	}
+	if err := os.WriteFile(path, []byte(norm+"\n"), 0o644); err != nil {
+		t.Errorf("testutil.Snapshot: %v", err)
+		return
+	}
+	t.Logf("testutil.Snapshot: wrote %s", path)
+}
@@ -0,0 +1,102 @@
+package testutil_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/testutil"
+)
+
+// recorder collects the failures a helper reports.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper()             {}
+func (r *recorder) Logf(string, ...any) {}
+func (r *recorder) Errorf(format string, a ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, a...))
+}
+
+func TestNormalize(t *testing.T) {
+	got, err := testutil.Normalize("select id\n  from users where id=?; delete from users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `SELECT "id" FROM "users" WHERE ("id" = ?);` + "\n" + `DELETE FROM "users";`
+	if got != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+	if _, err := testutil.Normalize("SELECT FROM"); err == nil {
+		t.Error("no error for invalid SQL")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	report, err := testutil.Diff("SELECT id FROM users; SELECT 1", "select ID from `users`; SELECT 1", ast.EqualOptions{})
+	if err != nil || report != "" {
+		t.Fatalf("equivalent SQL: %q %v", report, err)
+	}
+
+	report, err = testutil.Diff(
+		"SELECT id, name FROM users; DELETE FROM sessions; UPDATE users SET seen = 1",
+		"SELECT id, email FROM users; UPDATE users SET seen = 1; INSERT INTO log VALUES (1)",
+		ast.EqualOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `- SELECT "id", "name" FROM "users";
++ SELECT "id", "email" FROM "users";
+    Columns[1].Expr: name != email
+- DELETE FROM "sessions";
+  UPDATE "users" SET "seen" = 1;
++ INSERT INTO "log" VALUES (1);
+`
+	if report != want {
+		t.Errorf("got\n%s\nwant\n%s", report, want)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	r := &recorder{TB: t}
+	testutil.Equal(r, "SELECT id FROM users WHERE id = 1", "SELECT id FROM users WHERE id = $1", ast.EqualOptions{IgnoreLiterals: true})
+	if len(r.errors) != 0 {
+		t.Fatalf("unexpected failure: %v", r.errors)
+	}
+	testutil.Equal(r, "SELECT id FROM users WHERE id = 1", "SELECT id FROM users WHERE id = 2", ast.EqualOptions{})
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "Where.Right: 1 != 2") {
+		t.Errorf("failure: %v", r.errors)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots", "users.sql")
+	r := &recorder{TB: t}
+	testutil.Snapshot(r, path, "SELECT id FROM users", ast.EqualOptions{})
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "does not exist") {
+		t.Fatalf("missing snapshot: %v", r.errors)
+	}
+
+	t.Setenv(testutil.UpdateEnv, "1")
+	r.errors = nil
+	testutil.Snapshot(r, path, "select id from users", ast.EqualOptions{})
+	data, err := os.ReadFile(path)
+	if err != nil || len(r.errors) != 0 {
+		t.Fatalf("update: %v %v", err, r.errors)
+	}
+	if string(data) != "SELECT \"id\" FROM \"users\";\n" {
+		t.Errorf("snapshot file: %q", data)
+	}
+
+	t.Setenv(testutil.UpdateEnv, "")
+	testutil.Snapshot(r, path, `SELECT "id" FROM users`, ast.EqualOptions{})
+	testutil.Snapshot(r, path, "SELECT id, name FROM users", ast.EqualOptions{})
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "Columns: 1 item != 2 items") {
+		t.Errorf("changed snapshot: %v", r.errors)
+	}
+}
@@ -0,0 +1,102 @@
+package sqlparser
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// TimeoutPolicy decides the execution time limit injected for each DML
+// statement by ConvertDialectWithOptions. A zero duration leaves the
+// statement untouched. DDL, maintenance and transaction control statements
+// never get a limit.
+type TimeoutPolicy struct {
+	// Default applies to SELECT, INSERT, UPDATE and DELETE.
+	Default time.Duration
+	// Reads overrides Default for SELECT statements.
+	Reads time.Duration
+	// Writes overrides Default for INSERT, UPDATE and DELETE statements.
+	Writes time.Duration
+}
+
+// For returns the time limit the policy assigns to stmt.
+func (p TimeoutPolicy) For(stmt Statement) time.Duration {
+	switch stmt.(type) {
+	case *ast.SelectStmt:
+		if p.Reads > 0 {
+			return p.Reads
+		}
+	case *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+		if p.Writes > 0 {
+			return p.Writes
+		}
+	default:
+		return 0
+	}
+	return p.Default
+}
+
+// timeoutMillis rounds d up to whole milliseconds, the unit both MySQL and
+// PostgreSQL use for statement limits.
+func timeoutMillis(d time.Duration) string {
+	ms := (d + time.Millisecond - 1) / time.Millisecond
+	return strconv.FormatInt(int64(ms), 10)
+}
+
+// applyTimeout prepares the per-dialect timeout construct for stmt and
+// returns the rendered statement wrapped accordingly. render produces the
+// statement text; it is called after any hint has been armed.
+//
+// MySQL gets an optimizer hint, which the server only honours on SELECT.
+// PostgreSQL gets SET LOCAL statement_timeout, wrapped in its own
+// transaction unless the script already opened one.
+func (r *dialectRenderer) applyTimeout(stmt Statement, render func() (string, error)) (string, error) {
+	d := r.timeouts.For(stmt)
+	if d <= 0 {
+		return render()
+	}
+	ms := timeoutMillis(d)
+	switch r.target {
+	case DialectMySQL:
+		if _, ok := stmt.(*ast.SelectStmt); ok {
+			r.selectHint = "MAX_EXECUTION_TIME(" + ms + ")"
+			return render()
+		}
+		r.warn(WarnTimeoutUnsupported, stmt.Pos(), "MAX_EXECUTION_TIME only applies to SELECT in MySQL; no timeout was added")
+		return render()
+	case DialectPostgres:
+		out, err := render()
+		if err != nil {
+			return "", err
+		}
+		sep := r.separator()
+		set := "SET LOCAL statement_timeout = '" + ms + "ms'" + sep
+		if r.inTx {
+			return set + out, nil
+		}
+		return "BEGIN" + sep + set + out + sep + "COMMIT", nil
+	default:
+		r.warn(WarnTimeoutUnsupported, stmt.Pos(), "%s has no per-statement timeout; no timeout was added", r.target)
+		return render()
+	}
+}
+
+// trackTx follows explicit transaction boundaries in the script so timeout
+// wrappers are not nested inside a transaction the script opened itself.
+func (r *dialectRenderer) trackTx(stmt Statement) {
+	tx, ok := stmt.(*ast.TransactionStmt)
+	if !ok {
+		return
+	}
+	switch string(tx.Action) {
+	case "begin", "start_transaction":
+		r.inTx = true
+	case "commit":
+		r.inTx = false
+	case "rollback":
+		if tx.Savepoint == nil {
+			r.inTx = false
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package sqlparser_test
+
+import (
+	"testing"
+	"time"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+func TestTimeoutPolicyMySQL(t *testing.T) {
+	out, warnings, err := sqlparser.ConvertDialectWithOptions(
+		`WITH r AS (SELECT id FROM t) SELECT id FROM r; DELETE FROM t WHERE id = 1`,
+		sqlparser.ConvertOptions{
+			Target:   sqlparser.DialectMySQL,
+			Timeouts: sqlparser.TimeoutPolicy{Default: 1500 * time.Microsecond},
+		})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	want := "WITH `r` AS (SELECT `id` FROM `t`) SELECT /*+ MAX_EXECUTION_TIME(2) */ `id` FROM `r`; DELETE FROM `t` WHERE (`id` = 1)"
+	if out != want {
+		t.Fatalf("got:  %s\nwant: %s", out, want)
+	}
+	if len(warnings) != 1 || warnings[0].Code != sqlparser.WarnTimeoutUnsupported {
+		t.Fatalf("expected %s warning for DELETE, got %#v", sqlparser.WarnTimeoutUnsupported, warnings)
+	}
+}
+
+func TestTimeoutPolicyMySQLSetOperations(t *testing.T) {
+	tests := []struct {
+		src, want string
+		warned    bool
+	}{
+		{"SELECT a FROM t UNION SELECT a FROM u",
+			"SELECT /*+ MAX_EXECUTION_TIME(5) */ `a` FROM `t` UNION SELECT `a` FROM `u`", false},
+		{"SELECT /*+ NO_ICP(t) */ a FROM t INTERSECT SELECT a FROM u EXCEPT SELECT a FROM v",
+			"SELECT /*+ NO_ICP(t) MAX_EXECUTION_TIME(5) */ `a` FROM `t` INTERSECT SELECT `a` FROM `u` EXCEPT SELECT `a` FROM `v`", false},
+		{"VALUES ROW(1), ROW(2)", "VALUES ROW(1), ROW(2)", true},
+	}
+	for _, tt := range tests {
+		out, warnings, err := sqlparser.ConvertDialectWithOptions(tt.src, sqlparser.ConvertOptions{
+			Target:   sqlparser.DialectMySQL,
+			Timeouts: sqlparser.TimeoutPolicy{Default: 5 * time.Millisecond},
+		})
+		if err != nil {
+			t.Fatalf("%s: convert failed: %v", tt.src, err)
+		}
+		if out != tt.want {
+			t.Errorf("got:  %s\nwant: %s", out, tt.want)
+		}
+		if warned := len(warnings) == 1 && warnings[0].Code == sqlparser.WarnTimeoutUnsupported; warned != tt.warned || !warned && len(warnings) > 0 {
+			t.Errorf("%s: unexpected warnings %#v", tt.src, warnings)
+		}
+	}
+}
+
+func TestTimeoutPolicyPostgres(t *testing.T) {
+	out, _, err := sqlparser.ConvertDialectWithOptions(
+		`SELECT 1; BEGIN; UPDATE t SET a = 1; COMMIT; CREATE INDEX i ON t (a)`,
+		sqlparser.ConvertOptions{
+			Target:   sqlparser.DialectPostgres,
+			Timeouts: sqlparser.TimeoutPolicy{Reads: time.Second, Writes: 5 * time.Second},
+		})
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	want := `BEGIN; SET LOCAL statement_timeout = '1000ms'; SELECT 1; COMMIT; ` +
+		`BEGIN; SET LOCAL statement_timeout = '5000ms'; UPDATE "t" SET "a" = 1; COMMIT; ` +
+		`CREATE INDEX "i" ON "t" ("a")`
+	if out != want {
+		t.Fatalf("got:  %s\nwant: %s", out, want)
+	}
+}
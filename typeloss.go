@@ -0,0 +1,20 @@
+package sqlparser
+
+// analyzeTypeLoss flags the PostgreSQL types opts.Dialect lacks, such as
+// UUID or INET in MySQL, whose values lose some of their meaning in the
+// type dialect conversion gives them (TYPE_SEMANTIC_LOSS). It renders the
+// statement for the dialect, whose renderer already reports them.
+func analyzeTypeLoss(stmt Statement, idx int, report *AnalysisReport, opts AnalysisOptions) {
+	if opts.Dialect == "" {
+		return
+	}
+	r := newDialectRenderer(ConvertOptions{Target: opts.Dialect})
+	r.renderStatements([]Statement{stmt})
+	for _, w := range r.warnings {
+		if w.Code == WarnTypeApproximated {
+			addFinding(report, SeverityInfo, "TYPE_SEMANTIC_LOSS", w.Message+".",
+				"Check that the application does not rely on what is lost, or choose the target type with ConvertOptions.TypeMap (for example BINARY(16) for UUID keys in MySQL).",
+				idx, w.Pos)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package sqlparser
+
+import (
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+)
+
+// TypeMap overrides the data types ConvertDialectWithOptions writes, by
+// source type name matched without regard to case: {"datetime":
+// "TIMESTAMPTZ"} writes every DATETIME as TIMESTAMPTZ. A bare name keeps
+// the source's length or precision, so DATETIME(3) becomes TIMESTAMPTZ(3);
+// a type with arguments of its own, such as "CHAR(36)", replaces them.
+// Overrides win over the built-in mapping and over ENUM handling.
+type TypeMap map[string]string
+
+// lookup returns the override for the source type name.
+func (m TypeMap) lookup(name string) (string, bool) {
+	if typ, ok := m[name]; ok {
+		return typ, true
+	}
+	for k, typ := range m {
+		if strings.EqualFold(k, name) {
+			return typ, true
+		}
+	}
+	return "", false
+}
+
+// typeRule is how a target writes a source type: under name, or the
+// source's own spelling when name is empty, keeping the source's length
+// or precision when sized.
+type typeRule struct {
+	name  string
+	sized bool
+}
+
+// typeRules map the lower-cased names of the types each target lacks or
+// spells differently to the nearest one it has. MySQL's integer display
+// widths and text and blob lengths mean nothing to PostgreSQL and are
+// dropped. SQLite takes any type name, so only those its affinity rules
+// would misread are renamed. ClickHouse has its own table,
+// clickHouseTypes, and the generic output keeps every type as written.
+var typeRules = map[Dialect]map[string]typeRule{
+	DialectPostgres: {
+		"tinyint": {"SMALLINT", false}, "int1": {"SMALLINT", false},
+		"mediumint": {"INTEGER", false}, "int3": {"INTEGER", false}, "middleint": {"INTEGER", false},
+		"smallint": {}, "int": {}, "integer": {}, "bigint": {},
+		"double":   {"DOUBLE PRECISION", false},
+		"datetime": {"TIMESTAMP", true},
+		"year":     {"SMALLINT", false},
+		"text":     {}, "tinytext": {"TEXT", false}, "mediumtext": {"TEXT", false}, "longtext": {"TEXT", false},
+		"clob":     {"TEXT", false},
+		"nvarchar": {"VARCHAR", true},
+		"binary":   {"BYTEA", false}, "varbinary": {"BYTEA", false},
+		"blob": {"BYTEA", false}, "tinyblob": {"BYTEA", false}, "mediumblob": {"BYTEA", false}, "longblob": {"BYTEA", false},
+		// MySQL's CAST(x AS SIGNED) and CAST(x AS UNSIGNED)
+		"signed": {"BIGINT", false}, "unsigned": {"NUMERIC(20)", false},
+	},
+	DialectMySQL: {
+		"jsonb":       {"JSON", false},
+		"bytea":       {"LONGBLOB", false},
+		"uuid":        {"CHAR(36)", false},
+		"timestamptz": {"DATETIME", true}, "timetz": {"TIME", true},
+		"citext": {"TEXT", false}, "clob": {"LONGTEXT", false},
+		"money": {"DECIMAL(19,2)", false},
+		// PostgreSQL's own types
+		"inet": {"VARCHAR(43)", false}, "cidr": {"VARCHAR(43)", false},
+		"macaddr": {"CHAR(17)", false}, "macaddr8": {"CHAR(23)", false},
+		"interval": {"VARCHAR(64)", false},
+		"xml":      {"LONGTEXT", false}, "tsvector": {"LONGTEXT", false}, "tsquery": {"LONGTEXT", false},
+		"hstore": {"JSON", false},
+		"varbit": {"BIT", true}, "bpchar": {"CHAR", true},
+		"oid": {"INT UNSIGNED", false},
+	},
+	DialectSQLite: {
+		"json": {"TEXT", false}, "jsonb": {"TEXT", false},
+		"bytea":  {"BLOB", false},
+		"uuid":   {"TEXT", false},
+		"signed": {"INTEGER", false}, "unsigned": {"INTEGER", false},
+		// PostgreSQL's own types, some of which SQLite would read as
+		// integers (INTERVAL) or numbers (VARBIT)
+		"inet": {"TEXT", false}, "cidr": {"TEXT", false}, "macaddr": {"TEXT", false}, "macaddr8": {"TEXT", false},
+		"interval": {"TEXT", false}, "varbit": {"TEXT", false},
+		"xml": {"TEXT", false}, "tsvector": {"TEXT", false}, "tsquery": {"TEXT", false}, "hstore": {"TEXT", false},
+	},
+}
+
+// typeLosses say what the values of a PostgreSQL type lose when a target
+// without it stores them as another.
+var typeLosses = map[string]string{
+	"uuid":        "values are no longer checked to be UUIDs and take 36 bytes instead of 16, which makes primary keys and the indexes that carry them larger",
+	"inet":        "addresses are no longer validated and the network operators and functions are unavailable",
+	"cidr":        "addresses are no longer validated and the network operators and functions are unavailable",
+	"macaddr":     "addresses are no longer validated or normalized",
+	"macaddr8":    "addresses are no longer validated or normalized",
+	"money":       "values are no longer formatted for the currency locale",
+	"interval":    "values are text, so date arithmetic and comparisons no longer work on them",
+	"xml":         "values are no longer checked to be well-formed XML",
+	"tsvector":    "full-text search operators and indexes are unavailable",
+	"tsquery":     "full-text search operators and indexes are unavailable",
+	"timestamptz": "values are no longer converted to and from the session time zone",
+}
+
+// warnTypeLoss reports a PostgreSQL type stored as another type that
+// loses some of what it meant; see typeLosses. ClickHouse types are only
+// reported when they became strings.
+func (r *dialectRenderer) warnTypeLoss(dt *ast.DataType, typ string) {
+	name := strings.ToLower(string(dt.Name))
+	loss, ok := typeLosses[typeBase(name)]
+	if !ok || strings.EqualFold(typ, name) || r.target == DialectClickHouse && typ != "String" {
+		return
+	}
+	r.warn(WarnTypeApproximated, dt.TokPos, "%s became %s, as %s has no such type; %s", strings.ToUpper(name), typ, r.target, loss)
+}
+
+// typeBase returns the first word of a lower-cased type name, INTERVAL
+// for INTERVAL DAY TO SECOND.
+func typeBase(name string) string {
+	if i := strings.IndexByte(name, ' '); i > 0 {
+		return name[:i]
+	}
+	return name
+}
+
+// unsignedTypes are the PostgreSQL types wide enough for each MySQL
+// unsigned integer type.
+var unsignedTypes = map[string]string{
+	"tinyint": "SMALLINT", "int1": "SMALLINT",
+	"smallint": "INTEGER", "int2": "INTEGER", "mediumint": "INTEGER", "int3": "INTEGER", "middleint": "INTEGER",
+	"int": "BIGINT", "integer": "BIGINT", "int4": "BIGINT",
+	"bigint": "NUMERIC(20)", "int8": "NUMERIC(20)",
+}
+
+// mysqlCastTypes map the types the MySQL target writes to the nearest of
+// the few that its CAST takes. Lengths and precisions go with CHAR,
+// BINARY, DECIMAL, DATETIME and TIME.
+var mysqlCastTypes = map[string]string{
+	"tinyint": "SIGNED", "smallint": "SIGNED", "mediumint": "SIGNED", "int": "SIGNED", "integer": "SIGNED",
+	"bigint": "SIGNED", "bool": "SIGNED", "boolean": "SIGNED", "year": "SIGNED", "signed": "SIGNED",
+	"int1": "SIGNED", "int2": "SIGNED", "int3": "SIGNED", "int4": "SIGNED", "int8": "SIGNED", "middleint": "SIGNED",
+	"bit": "UNSIGNED", "unsigned": "UNSIGNED",
+	"char": "CHAR", "varchar": "CHAR", "nchar": "CHAR", "nvarchar": "CHAR", "text": "CHAR", "tinytext": "CHAR",
+	"mediumtext": "CHAR", "longtext": "CHAR", "enum": "CHAR", "set": "CHAR",
+	"decimal": "DECIMAL", "numeric": "DECIMAL", "dec": "DECIMAL", "fixed": "DECIMAL",
+	"float": "DOUBLE", "double": "DOUBLE", "real": "DOUBLE", "float4": "DOUBLE", "float8": "DOUBLE",
+	"date": "DATE", "datetime": "DATETIME", "timestamp": "DATETIME", "time": "TIME",
+	"json":   "JSON",
+	"binary": "BINARY", "varbinary": "BINARY", "blob": "BINARY", "tinyblob": "BINARY", "mediumblob": "BINARY",
+	"longblob": "BINARY",
+}
+
+// renderCastType renders the type of a CAST. MySQL's CAST only takes
+// SIGNED, UNSIGNED, CHAR, DECIMAL, DOUBLE, DATE, DATETIME, TIME, JSON and
+// BINARY, and a few others in recent versions, so the type MySQL would
+// store becomes the nearest of those: BIGINT becomes SIGNED and VARCHAR(40)
+// CHAR(40). Types without one are written as they are.
+func (r *dialectRenderer) renderCastType(dt *ast.DataType) string {
+	typ := r.renderDataType(dt)
+	if r.target != DialectMySQL || dt.Elem != nil || dt.Fields != nil {
+		return typ
+	}
+	lower := strings.ToLower(typ)
+	base, args := lower, ""
+	if i := strings.IndexAny(lower, "( "); i > 0 {
+		base = lower[:i]
+		if lower[i] == '(' {
+			args = typ[i:]
+			if j := strings.IndexByte(args, ')'); j > 0 {
+				args = args[:j+1]
+			}
+		}
+	}
+	cast, ok := mysqlCastTypes[base]
+	switch {
+	case !ok:
+		return typ
+	case cast == "SIGNED" && strings.HasSuffix(lower, " unsigned"):
+		return "UNSIGNED"
+	case cast == "CHAR" || cast == "BINARY" || cast == "DECIMAL" || cast == "DATETIME" || cast == "TIME":
+		return cast + args
+	}
+	return cast
+}
+
+// overrideType renders dt as ConvertOptions.TypeMap says, if it names it.
+func (r *dialectRenderer) overrideType(dt *ast.DataType) (string, bool) {
+	typ, ok := r.typeMap.lookup(string(dt.Name))
+	if !ok {
+		return "", false
+	}
+	t := *dt
+	t.EnumVals = nil
+	if strings.IndexByte(typ, '(') >= 0 {
+		t.Precision, t.Scale = 0, 0
+	}
+	return r.renderDataTypeAs(&t, typ), true
+}
+
+// mapType returns the name a PostgreSQL, MySQL or SQLite target writes
+// dt under, and whether the source's length or precision goes with it.
+// MySQL's TINYINT(1), its BOOLEAN, becomes PostgreSQL's BOOLEAN, and its
+// unsigned integers the next wider type, and a VARCHAR without a length,
+// which MySQL rejects, becomes TEXT. Under ConvertOptions.ConvertBooleans
+// a BOOLEAN becomes MySQL's TINYINT(1) and SQLite's INTEGER.
+func (r *dialectRenderer) mapType(dt *ast.DataType, name string) (string, bool) {
+	lower := strings.ToLower(name)
+	switch {
+	case r.target == DialectPostgres && dt.Unsigned && unsignedTypes[lower] != "":
+		return unsignedTypes[lower], false
+	case r.target == DialectPostgres && lower == "tinyint" && dt.Precision == 1:
+		r.warn(WarnTinyintAsBoolean, dt.TokPos, "TINYINT(1) became BOOLEAN; integer values written to it must become TRUE and FALSE")
+		return "BOOLEAN", false
+	case r.target == DialectMySQL && (lower == "varchar" || lower == "nvarchar") && dt.Precision == 0:
+		return "TEXT", false
+	case r.convertBooleans && (lower == "boolean" || lower == "bool") && r.target == DialectMySQL:
+		return "TINYINT(1)", false
+	case r.convertBooleans && (lower == "boolean" || lower == "bool") && r.target == DialectSQLite:
+		return "INTEGER", false
+	}
+	rule, ok := typeRules[r.target][typeBase(lower)]
+	switch {
+	case !ok:
+		return name, true
+	case rule.name == "":
+		return name, rule.sized
+	}
+	r.warnTypeLoss(dt, rule.name)
+	return rule.name, rule.sized
+}
+
+// checkedUnsigned returns the type a MySQL unsigned integer column takes
+// in PostgreSQL and SQLite, which have no unsigned types: the next wider
+// signed one in PostgreSQL, so that every value fits, and the same one in
+// SQLite, whose integers have 64 bits. writeTypeChecks adds the CHECK
+// that rejects negative values. It returns nil for other types and
+// targets, and for types TypeMap overrides.
+func (r *dialectRenderer) checkedUnsigned(dt *ast.DataType) *ast.DataType {
+	if dt == nil || !dt.Unsigned || r.target != DialectPostgres && r.target != DialectSQLite {
+		return nil
+	}
+	name := string(dt.Name)
+	wide := unsignedTypes[strings.ToLower(name)]
+	if wide == "" {
+		return nil
+	}
+	if _, ok := r.typeMap.lookup(name); ok {
+		return nil
+	}
+	t := *dt
+	t.Unsigned = false
+	if r.target == DialectPostgres {
+		t.Name = []byte(wide)
+		t.Precision = 0
+	}
+	return &t
+}
@@ -0,0 +1,204 @@
+package sqlparser
+
+import (
+	"strings"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/lexer"
+)
+
+// collectUserTypes indexes the enum types and domains a script creates, so
+// the columns that use them can be rendered for targets without named
+// types.
+func collectUserTypes(stmts []Statement) (map[string]*ast.CreateTypeStmt, map[string]*ast.CreateDomainStmt) {
+	enums := map[string]*ast.CreateTypeStmt{}
+	domains := map[string]*ast.CreateDomainStmt{}
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *ast.CreateTypeStmt:
+			enums[typeKey(qualifiedName(s.Name))] = s
+		case *ast.CreateDomainStmt:
+			domains[typeKey(qualifiedName(s.Name))] = s
+		}
+	}
+	return enums, domains
+}
+
+// typeKey normalizes a type name for lookups the way sequenceKey does: a
+// column may name the type with or without its schema.
+func typeKey(name string) string {
+	return sequenceKey(name)
+}
+
+// inlinesUserTypes reports whether the target lacks named types, so enum
+// types and domains are written into the columns that use them.
+func (r *dialectRenderer) inlinesUserTypes() bool {
+	return r.target == DialectMySQL || r.target == DialectSQLite || r.target == DialectClickHouse
+}
+
+// resolveDomain returns the base type and the domain of a column declared
+// with one of the script's domains, when the target inlines them.
+func (r *dialectRenderer) resolveDomain(dt *ast.DataType) (*ast.DataType, *ast.CreateDomainStmt) {
+	if dt == nil || !r.inlinesUserTypes() {
+		return dt, nil
+	}
+	if d, ok := r.domains[typeKey(string(dt.Name))]; ok {
+		return d.Type, d
+	}
+	return dt, nil
+}
+
+// enumValues returns the labels of an inline ENUM or SET type, or of one of
+// the script's enum types when the target inlines them.
+func (r *dialectRenderer) enumValues(dt *ast.DataType) [][]byte {
+	if len(dt.EnumVals) > 0 {
+		return dt.EnumVals
+	}
+	if t, ok := r.enumTypes[typeKey(string(dt.Name))]; ok && r.inlinesUserTypes() {
+		return t.EnumVals
+	}
+	return nil
+}
+
+// renderColumnType renders the type of column col. Enum types become
+// MySQL's inline ENUM, TEXT restricted by a CHECK in SQLite (see
+// writeTypeChecks), and a named enum type in PostgreSQL, created ahead of
+// the statement as table_column. Unsigned integers become signed ones
+// checked the same way; see checkedUnsigned.
+func (r *dialectRenderer) renderColumnType(col *ast.Ident, dt *ast.DataType) string {
+	if t := r.checkedUnsigned(dt); t != nil {
+		return r.renderDataType(t)
+	}
+	if _, ok := r.typeMap.lookup(string(dt.Name)); ok {
+		return r.renderDataType(dt)
+	}
+	vals := r.enumValues(dt)
+	switch {
+	case vals == nil || r.target == "" || r.target == DialectMySQL && len(dt.EnumVals) > 0:
+		return r.renderDataType(dt)
+	case strings.EqualFold(string(dt.Name), "set"):
+		text := "TEXT"
+		if r.target == DialectClickHouse {
+			text = "String"
+		}
+		r.warn(WarnSetTypeUnsupported, dt.TokPos, "%s has no SET type; column %s became %s and its members are not enforced", r.target, col.Unquoted, text)
+		return text
+	case r.target == DialectMySQL:
+		return "ENUM(" + r.enumList(vals) + ")"
+	case r.target == DialectClickHouse:
+		return clickHouseEnum(vals)
+	case r.target == DialectSQLite:
+		return "TEXT"
+	case r.target == DialectPostgres && r.table != nil:
+		n := len(r.table.Parts)
+		name := r.table.Parts[n-1].Unquoted + "_" + col.Unquoted
+		q := &ast.QualifiedIdent{Parts: append(r.table.Parts[:n-1:n-1], &ast.Ident{Unquoted: name})}
+		r.pendingTypes = append(r.pendingTypes, "CREATE TYPE "+r.renderQualifiedIdent(q)+" AS ENUM ("+r.enumList(vals)+")")
+		r.warn(WarnEnumTypeCreated, dt.TokPos, "the inline ENUM of column %s became the enum type %s", col.Unquoted, qualifiedName(q))
+		return r.renderQualifiedIdent(q)
+	}
+	return r.renderDataType(dt)
+}
+
+// writeTypeChecks writes the CHECK constraints that stand in for an enum
+// type in SQLite, for an unsigned integer in PostgreSQL and SQLite and
+// for the checks of an inlined domain, with VALUE replaced by the column.
+func (r *dialectRenderer) writeTypeChecks(b *strings.Builder, col *ast.Ident, dt *ast.DataType, domain *ast.CreateDomainStmt) {
+	if r.checkedUnsigned(dt) != nil {
+		b.WriteString(" CHECK (" + r.renderIdent(col) + " >= 0)")
+	}
+	if r.target == DialectSQLite && dt != nil && !strings.EqualFold(string(dt.Name), "set") {
+		if vals := r.enumValues(dt); vals != nil {
+			b.WriteString(" CHECK (" + r.renderIdent(col) + " IN (" + r.enumList(vals) + "))")
+		}
+	}
+	if domain == nil {
+		return
+	}
+	r.domainValue = r.renderIdent(col)
+	for _, c := range domain.Checks {
+		b.WriteString(" CHECK (" + r.renderExpr(c) + ")")
+	}
+	r.domainValue = ""
+}
+
+// withPendingTypes puts the CREATE TYPE statements the rendered statement
+// needs in front of it, and the CREATE INDEX statements of its inline
+// indexes after it.
+func (r *dialectRenderer) withPendingTypes(out string, err error) (string, error) {
+	pending, indexes := r.pendingTypes, r.pendingIndexes
+	r.pendingTypes, r.pendingIndexes = nil, nil
+	if err != nil {
+		return out, err
+	}
+	return strings.Join(append(append(pending, out), indexes...), r.separator()), nil
+}
+
+// enumList renders enum labels as a comma-separated list of string
+// literals, requoted so MySQL backslash escapes do not leak into other
+// dialects.
+func (r *dialectRenderer) enumList(vals [][]byte) string {
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		out[i] = string(v)
+		if q, ok := r.renderString(&ast.Literal{Raw: v, Kind: lexer.STRING}); ok {
+			out[i] = q
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+func (r *dialectRenderer) renderCreateType(s *ast.CreateTypeStmt) string {
+	if r.inlinesUserTypes() {
+		r.warn(WarnUserTypeInlined, s.TokPos, "%s has no named types; enum type %s was inlined into the columns that use it", r.target, qualifiedName(s.Name))
+		return ""
+	}
+	return "CREATE TYPE " + r.renderQualifiedIdent(s.Name) + " AS ENUM (" + r.enumList(s.EnumVals) + ")"
+}
+
+func (r *dialectRenderer) renderCreateDomain(s *ast.CreateDomainStmt) string {
+	if r.inlinesUserTypes() {
+		r.warn(WarnUserTypeInlined, s.TokPos, "%s has no domains; domain %s was inlined into the columns that use it", r.target, qualifiedName(s.Name))
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("CREATE DOMAIN " + r.renderQualifiedIdent(s.Name) + " AS " + r.renderDataType(s.Type))
+	if s.Default != nil {
+		b.WriteString(" DEFAULT " + r.renderExpr(s.Default))
+	}
+	if s.NotNull {
+		b.WriteString(" NOT NULL")
+	}
+	r.domainValue = "VALUE"
+	for _, c := range s.Checks {
+		b.WriteString(" CHECK (" + r.renderExpr(c) + ")")
+	}
+	r.domainValue = ""
+	return b.String()
+}
+
+func (r *dialectRenderer) renderDropType(s *ast.DropTypeStmt) string {
+	kind := "TYPE"
+	if s.Domain {
+		kind = "DOMAIN"
+	}
+	if r.inlinesUserTypes() {
+		r.warn(WarnUserTypeInlined, s.TokPos, "%s has no named types; DROP %s was omitted", r.target, kind)
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("DROP " + kind + " ")
+	if s.IfExists {
+		b.WriteString("IF EXISTS ")
+	}
+	for i, name := range s.Names {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(r.renderQualifiedIdent(name))
+	}
+	if s.Cascade {
+		b.WriteString(" CASCADE")
+	}
+	return b.String()
+}
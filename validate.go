@@ -0,0 +1,361 @@
+package sqlparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/oarkflow/sqlparser/ast"
+	"github.com/oarkflow/sqlparser/eval"
+)
+
+// DataViolation is a value in an INSERT or UPDATE that the database would
+// reject. Code is one of NOT_NULL, TYPE_MISMATCH, OUT_OF_RANGE, TOO_LONG,
+// NOT_IN_ENUM, GENERATED_COLUMN, CHECK_VIOLATION, UNKNOWN_COLUMN or
+// COLUMN_COUNT.
+type DataViolation struct {
+	// Statement is the index of the statement in the script.
+	Statement int
+	// Row is the index of the VALUES row; UPDATE and ON DUPLICATE KEY /
+	// ON CONFLICT assignments have row 0, and problems with the statement
+	// as a whole row -1.
+	Row   int
+	Table string
+	// Column is empty for a CHECK constraint and for column-count errors.
+	Column  string
+	Code    string
+	Message string
+}
+
+// ValidateData checks the literal values of the INSERT and UPDATE
+// statements in stmts against the schema: column types (integer and
+// decimal ranges, dates, times, UUIDs, JSON, character lengths), NOT NULL,
+// ENUM and SET membership, and the table's CHECK constraints, evaluated on
+// the row the statement would write. Only what is certain is reported:
+// values that are not constant, such as function calls or parameters, are
+// skipped, a CHECK that depends on a value the statement does not give is
+// not evaluated, and for conversions the dialects disagree on the more
+// lenient one is assumed. Statements on tables the schema does not know
+// are ignored.
+func (s *Schema) ValidateData(stmts []Statement) []DataViolation {
+	v := &dataValidator{schema: s, r: newDialectRenderer(ConvertOptions{})}
+	for i, stmt := range stmts {
+		v.stmt = i
+		switch st := stmt.(type) {
+		case *ast.InsertStmt:
+			v.insert(st)
+		case *ast.UpdateStmt:
+			v.update(st)
+		}
+	}
+	return v.out
+}
+
+type dataValidator struct {
+	schema *Schema
+	r      *dialectRenderer
+	stmt   int
+	out    []DataViolation
+}
+
+func (v *dataValidator) report(t *Table, row int, column, code, format string, args ...any) {
+	v.out = append(v.out, DataViolation{
+		Statement: v.stmt,
+		Row:       row,
+		Table:     t.schemaKey(),
+		Column:    column,
+		Code:      code,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *dataValidator) insert(s *ast.InsertStmt) {
+	t := v.schema.lookup(s.Table)
+	if t == nil {
+		return
+	}
+	var cols []*Column
+	if len(s.Columns) == 0 {
+		for _, c := range t.Columns {
+			if !c.Generated {
+				cols = append(cols, c)
+			}
+		}
+	}
+	for _, id := range s.Columns {
+		c := t.Column(identName(id))
+		if c == nil {
+			v.report(t, -1, identName(id), "UNKNOWN_COLUMN", "table %s has no column %s", t.schemaKey(), identName(id))
+			return
+		}
+		cols = append(cols, c)
+	}
+	if s.DefaultValues {
+		v.checks(t, v.omitted(t, nil, 0), 0)
+	}
+	for i, values := range s.Values {
+		if len(values) != len(cols) {
+			v.report(t, i, "", "COLUMN_COUNT", "row has %d values for %d columns", len(values), len(cols))
+			continue
+		}
+		// A DEFAULT value leaves the column to its default, as if omitted.
+		var given []*Column
+		for j, c := range cols {
+			if _, ok := values[j].(*ast.DefaultExpr); !ok {
+				given = append(given, c)
+			}
+		}
+		row := v.omitted(t, given, i)
+		for j, c := range cols {
+			if _, ok := values[j].(*ast.DefaultExpr); !ok {
+				v.assign(t, c, values[j], i, row)
+			}
+		}
+		v.checks(t, row, i)
+	}
+	for _, set := range [][]ast.Assignment{s.OnDupKey, s.OnConflictUpdate} {
+		if len(set) > 0 {
+			v.assignments(t, set)
+		}
+	}
+}
+
+// omitted binds the columns an INSERT leaves out to their defaults, and
+// reports NOT NULL columns that have none. Columns whose value the
+// database computes stay unbound.
+func (v *dataValidator) omitted(t *Table, given []*Column, rowIdx int) map[string]eval.Value {
+	row := map[string]eval.Value{}
+	for _, c := range t.Columns {
+		switch {
+		case c.Generated || slices.Contains(given, c):
+		case c.Default != nil:
+			if val, ok := eval.Eval(c.Default, nil); ok {
+				row[strings.ToLower(c.Name)] = val
+			}
+		case isSerial(c):
+		case !c.Nullable:
+			v.report(t, rowIdx, c.Name, "NOT_NULL", "column %s is NOT NULL and has no default, but the row does not set it", c.Name)
+		default:
+			row[strings.ToLower(c.Name)] = eval.Value{}
+		}
+	}
+	return row
+}
+
+func (v *dataValidator) update(s *ast.UpdateStmt) {
+	if len(s.Tables) != 1 {
+		return
+	}
+	st, ok := s.Tables[0].(*ast.SimpleTable)
+	if !ok {
+		return
+	}
+	if t := v.schema.lookup(st.Name); t != nil {
+		v.assignments(t, s.Set)
+	}
+}
+
+// assignments validates SET col = value pairs. The CHECK constraints see
+// only the assigned columns, since the rest of the row is unknown.
+func (v *dataValidator) assignments(t *Table, set []ast.Assignment) {
+	row := map[string]eval.Value{}
+	for _, a := range set {
+		c := t.Column(identName(a.Column))
+		if c == nil {
+			v.report(t, 0, identName(a.Column), "UNKNOWN_COLUMN", "table %s has no column %s", t.schemaKey(), identName(a.Column))
+			continue
+		}
+		v.assign(t, c, a.Value, 0, row)
+	}
+	v.checks(t, row, 0)
+}
+
+// assign validates e as the value of c and, when it is constant, binds it
+// in row.
+func (v *dataValidator) assign(t *Table, c *Column, e ast.Expr, rowIdx int, row map[string]eval.Value) {
+	if c.Generated {
+		v.report(t, rowIdx, c.Name, "GENERATED_COLUMN", "column %s is generated and cannot be assigned", c.Name)
+		return
+	}
+	val, ok := eval.Eval(e, nil)
+	if !ok {
+		return
+	}
+	row[strings.ToLower(c.Name)] = val
+	if val.Kind == eval.Null {
+		if !c.Nullable && !c.AutoIncrement {
+			v.report(t, rowIdx, c.Name, "NOT_NULL", "NULL in NOT NULL column %s", c.Name)
+		}
+		return
+	}
+	if code, msg := columnAccepts(c, val); code != "" {
+		v.report(t, rowIdx, c.Name, code, "%s for column %s %s", msg, c.Name, c.typeText())
+	}
+}
+
+func (v *dataValidator) checks(t *Table, row map[string]eval.Value, rowIdx int) {
+	for _, e := range t.Checks {
+		if res, ok := eval.Eval(e, row); ok && res.Truth() == eval.False {
+			v.report(t, rowIdx, "", "CHECK_VIOLATION", "row violates CHECK (%s)", v.r.renderExpr(e))
+		}
+	}
+}
+
+// columnAccepts checks a non-NULL value against the type of c and returns
+// the violation code and a description of the value, or "" when the value
+// is accepted.
+func columnAccepts(c *Column, val eval.Value) (code, msg string) {
+	lit := val.String()
+	if val.Kind == eval.Text {
+		lit = quoteSQLString(val.Text)
+	}
+	kind, lo, hi := fixtureKindOf(c)
+	switch kind {
+	case fixtureInt, fixtureDecimal, fixtureFloat:
+		n, ok := val.Number()
+		if !ok {
+			return "TYPE_MISMATCH", lit + " is not a number"
+		}
+		if kind == fixtureInt {
+			if n = math.Round(n); n < lo || n > hi {
+				return "OUT_OF_RANGE", lit + " is out of range"
+			}
+		}
+		if kind == fixtureDecimal && c.Precision > 0 && math.Abs(n) >= math.Pow10(c.Precision-c.Scale) {
+			return "OUT_OF_RANGE", lit + " has too many integer digits"
+		}
+	case fixtureBool:
+		if val.Kind == eval.Text && !isBoolText(val.Text) {
+			return "TYPE_MISMATCH", lit + " is not a boolean"
+		}
+	case fixtureDate:
+		if val.Kind == eval.Text && !isDateTimeText(val.Text, true) {
+			return "TYPE_MISMATCH", lit + " is not a valid date"
+		}
+	case fixtureDateTime:
+		if val.Kind == eval.Text && !isDateTimeText(val.Text, false) {
+			return "TYPE_MISMATCH", lit + " is not a valid timestamp"
+		}
+	case fixtureTime:
+		if val.Kind == eval.Text && !isTimeText(val.Text) {
+			return "TYPE_MISMATCH", lit + " is not a valid time"
+		}
+	case fixtureUUID:
+		if val.Kind != eval.Text || !isUUIDText(val.Text) {
+			return "TYPE_MISMATCH", lit + " is not a UUID"
+		}
+	case fixtureJSON:
+		if val.Kind == eval.Text && !json.Valid([]byte(val.Text)) {
+			return "TYPE_MISMATCH", lit + " is not valid JSON"
+		}
+	case fixtureEnum:
+		members := []string{val.String()}
+		if strings.EqualFold(c.Type, "set") {
+			if val.String() == "" {
+				break // the empty set
+			}
+			members = strings.Split(val.String(), ",")
+		}
+		for _, m := range members {
+			if !slices.ContainsFunc(c.EnumValues, func(e string) bool { return strings.EqualFold(e, m) }) {
+				return "NOT_IN_ENUM", quoteSQLString(m) + " is not a member"
+			}
+		}
+	case fixtureText:
+		if isCharType(c.Type) && c.Precision > 0 && utf8.RuneCountInString(val.String()) > c.Precision {
+			return "TOO_LONG", lit + " is longer than " + strconv.Itoa(c.Precision) + " characters"
+		}
+	}
+	return "", ""
+}
+
+func isCharType(typ string) bool {
+	switch strings.ToLower(typ) {
+	case "char", "character", "varchar", "nchar", "nvarchar", "varchar2", "character varying":
+		return true
+	}
+	return false
+}
+
+func isBoolText(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "t", "true", "y", "yes", "on", "1", "f", "false", "n", "no", "off", "0":
+		return true
+	}
+	return false
+}
+
+// dateTimeLayouts are the timestamp spellings both MySQL and PostgreSQL
+// accept. time.Parse also accepts a fractional second after the seconds
+// of any of them.
+var dateTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02 15:04:05-07",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+}
+
+func isDateTimeText(s string, dateOnly bool) bool {
+	s = strings.TrimSpace(s)
+	if _, err := time.Parse("2006-01-02", s); err == nil {
+		return true
+	}
+	if dateOnly {
+		return false
+	}
+	for _, layout := range dateTimeLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isTimeText accepts [-]H[H...]:MM[:SS[.fraction]]. Hours are not capped
+// at 24, since MySQL's TIME is an interval of up to 838 hours.
+func isTimeText(s string) bool {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(s), "-"), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return false
+	}
+	if _, err := strconv.ParseUint(parts[0], 10, 16); err != nil {
+		return false
+	}
+	for i, p := range parts[1:] {
+		if i == 1 {
+			p, _, _ = strings.Cut(p, ".")
+		}
+		if n, err := strconv.ParseUint(p, 10, 8); err != nil || len(p) != 2 || n > 59 {
+			return false
+		}
+	}
+	return true
+}
+
+// isUUIDText accepts 32 hex digits, optionally hyphenated and in braces,
+// as PostgreSQL does.
+func isUUIDText(s string) bool {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+		s = s[1 : len(s)-1]
+	}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '-':
+		case '0' <= c && c <= '9', 'a' <= c && c <= 'f', 'A' <= c && c <= 'F':
+			n++
+		default:
+			return false
+		}
+	}
+	return n == 32 && !strings.HasPrefix(s, "-") && !strings.HasSuffix(s, "-") && !strings.Contains(s, "--")
+}
@@ -0,0 +1,85 @@
+package sqlparser_test
+
+import (
+	"reflect"
+	"testing"
+
+	sqlparser "github.com/oarkflow/sqlparser"
+)
+
+const validateDDL = `
+CREATE TABLE orders (
+  id INT AUTO_INCREMENT PRIMARY KEY,
+  ref UUID,
+  status ENUM('new', 'paid') NOT NULL DEFAULT 'new',
+  tags SET('gift', 'rush'),
+  qty SMALLINT NOT NULL CHECK (qty BETWEEN 1 AND 5),
+  price DECIMAL(6,2) NOT NULL,
+  discount DECIMAL(6,2) DEFAULT 0,
+  note VARCHAR(5),
+  placed DATE,
+  meta JSON,
+  CHECK (discount < price OR status = 'paid')
+)`
+
+func TestValidateData(t *testing.T) {
+	schema, err := sqlparser.BuildSchema(validateDDL)
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	stmts, err := sqlparser.ParseStatements(`
+		INSERT INTO orders (qty, price, tags, placed, meta, ref) VALUES
+		  (2, 9.99, 'gift,rush', '2024-02-29', '{"a": 1}', 'a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11'),
+		  (0, 10, NULL, '2023-02-29', '{', 'nope'),
+		  (1, 10000, 'gift,box', NULL, NULL, NULL),
+		  (3, NULL, NULL, NULL, NULL, NULL),
+		  (1, 5, NULL, NULL, NULL, NULL, 7);
+		INSERT INTO orders (qty, price, discount, note) VALUES (40000, 5, 6, 'toolong'), (1, 5, 6, 'ok');
+		INSERT INTO orders (qty, status, price, discount) VALUES (1, 'paid', 5, 6), (1, 'void', NOW(), 6);
+		INSERT INTO orders (price) VALUES (1);
+		UPDATE orders SET qty = qty + 10, status = 'shipped' WHERE id = 1;
+		UPDATE orders SET discount = 9, price = 4; -- status may be 'paid'
+		UPDATE orders SET qty = 9 WHERE id = 2;
+		INSERT INTO orders (nope) VALUES (1);
+		INSERT INTO unknown (a) VALUES ('x');
+		INSERT INTO orders DEFAULT VALUES;
+		INSERT INTO orders SET qty = DEFAULT, price = 5`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	type got struct {
+		stmt, row int
+		column    string
+		code      string
+	}
+	var out []got
+	for _, v := range schema.ValidateData(stmts) {
+		out = append(out, got{v.Statement, v.Row, v.Column, v.Code})
+	}
+	want := []got{
+		{0, 1, "placed", "TYPE_MISMATCH"},
+		{0, 1, "meta", "TYPE_MISMATCH"},
+		{0, 1, "ref", "TYPE_MISMATCH"},
+		{0, 1, "", "CHECK_VIOLATION"},
+		{0, 2, "price", "OUT_OF_RANGE"},
+		{0, 2, "tags", "NOT_IN_ENUM"},
+		{0, 3, "price", "NOT_NULL"},
+		{0, 4, "", "COLUMN_COUNT"},
+		{1, 0, "qty", "OUT_OF_RANGE"},
+		{1, 0, "note", "TOO_LONG"},
+		{1, 0, "", "CHECK_VIOLATION"},
+		{1, 0, "", "CHECK_VIOLATION"},
+		{1, 1, "", "CHECK_VIOLATION"},
+		{2, 1, "status", "NOT_IN_ENUM"},
+		{3, 0, "qty", "NOT_NULL"},
+		{4, 0, "status", "NOT_IN_ENUM"},
+		{6, 0, "", "CHECK_VIOLATION"},
+		{7, -1, "nope", "UNKNOWN_COLUMN"},
+		{9, 0, "qty", "NOT_NULL"},
+		{9, 0, "price", "NOT_NULL"},
+		{10, 0, "qty", "NOT_NULL"},
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("violations:\n got %v\nwant %v", out, want)
+	}
+}